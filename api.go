@@ -23,8 +23,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pilosa/pilosa/pql"
@@ -93,10 +96,12 @@ func appendMap(a, b map[apiMethod]struct{}) map[apiMethod]struct{} {
 
 func (api *API) validate(f apiMethod) error {
 	state := api.cluster.State()
-	if _, ok := validAPIMethods[state][f]; ok {
-		return nil
+	if _, ok := validAPIMethods[state][f]; !ok {
+		return newApiMethodNotAllowedError(errors.Errorf("api method %s not allowed in state %s", f, state))
+	} else if _, ok := methodsWrite[f]; ok && api.holder.ReadOnly {
+		return newForbiddenError(ErrReadOnly)
 	}
-	return newApiMethodNotAllowedError(errors.Errorf("api method %s not allowed in state %s", f, state))
+	return nil
 }
 
 // Query parses a PQL query out of the request and executes it.
@@ -108,24 +113,82 @@ func (api *API) Query(ctx context.Context, req *QueryRequest) (QueryResponse, er
 		return QueryResponse{}, errors.Wrap(err, "validating api method")
 	}
 
+	start := time.Now()
 	q, err := pql.NewParser(strings.NewReader(req.Query)).Parse()
 	if err != nil {
 		return QueryResponse{}, errors.Wrap(err, "parsing")
 	}
+	parse := time.Since(start)
 	execOpts := &execOptions{
 		Remote:          req.Remote,
 		ExcludeRowAttrs: req.ExcludeRowAttrs, // NOTE: Kept for Pilosa 1.x compat.
 		ExcludeColumns:  req.ExcludeColumns,  // NOTE: Kept for Pilosa 1.x compat.
 		ColumnAttrs:     req.ColumnAttrs,     // NOTE: Kept for Pilosa 1.x compat.
+		Profile:         req.Profile,
 	}
 	resp, err := api.server.executor.Execute(ctx, req.Index, q, req.Shards, execOpts)
 	if err != nil {
 		return QueryResponse{}, errors.Wrap(err, "executing")
 	}
+	if resp.Profile != nil {
+		resp.Profile.Parse = parse
+	}
+
+	if req.Labels && !req.Remote {
+		api.labelResponse(req.Index, q, &resp)
+	}
 
 	return resp, nil
 }
 
+// QueryStream parses and executes a query of a single call returning a row,
+// and calls fn with the columns of the row in ascending order as the row of
+// each shard arrives, so the whole row is never held at once. The slice
+// passed to fn is reused once it returns. Row attributes aren't returned. It
+// returns the number of columns.
+func (api *API) QueryStream(ctx context.Context, req *QueryRequest, fn func(columns []uint64) error) (uint64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.QueryStream")
+	defer span.Finish()
+
+	if err := api.validate(apiQuery); err != nil {
+		return 0, errors.Wrap(err, "validating api method")
+	} else if req.Remote {
+		return 0, NewBadRequestError(errors.New("remote queries can't be streamed"))
+	}
+
+	q, err := pql.NewParser(strings.NewReader(req.Query)).Parse()
+	if err != nil {
+		return 0, errors.Wrap(err, "parsing")
+	}
+	execOpts := &execOptions{
+		ExcludeRowAttrs: true,
+		ColumnAttrs:     req.ColumnAttrs,
+	}
+	n, err := api.server.executor.ExecuteStream(ctx, req.Index, q, req.Shards, execOpts, fn)
+	if err != nil {
+		return n, errors.Wrap(err, "executing")
+	}
+	return n, nil
+}
+
+// labelResponse sets the labels used in place of IDs when resp is encoded
+// as JSON.
+func (api *API) labelResponse(indexName string, q *pql.Query, resp *QueryResponse) {
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return
+	}
+	resp.ColumnLabel = index.ColumnLabel()
+	resp.RowLabels = make([]string, len(resp.Results))
+	for i, call := range q.Calls {
+		if i >= len(resp.RowLabels) {
+			break
+		} else if f := index.Field(callArgString(call, "_field")); f != nil {
+			resp.RowLabels[i] = f.RowLabel()
+		}
+	}
+}
+
 // CreateIndex makes a new Pilosa index.
 func (api *API) CreateIndex(ctx context.Context, indexName string, options IndexOptions) (*Index, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.CreateIndex")
@@ -133,6 +196,8 @@ func (api *API) CreateIndex(ctx context.Context, indexName string, options Index
 
 	if err := api.validate(apiCreateIndex); err != nil {
 		return nil, errors.Wrap(err, "validating api method")
+	} else if !options.TimeQuantum.Valid() {
+		return nil, NewBadRequestError(ErrInvalidTimeQuantum)
 	}
 
 	// Create index.
@@ -197,6 +262,156 @@ func (api *API) DeleteIndex(ctx context.Context, indexName string) error {
 	return nil
 }
 
+// RenameIndex renames an index on every node. Every node checks that the
+// index can be renamed first. If any node then fails to rename it, the
+// nodes which have renamed it are asked to rename it back.
+func (api *API) RenameIndex(ctx context.Context, indexName, newName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RenameIndex")
+	defer span.Finish()
+
+	if err := api.validate(apiRenameIndex); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	if err := api.holder.CheckRenameIndex(indexName, newName); err != nil {
+		return errors.Wrap(err, "checking rename")
+	}
+	err := api.server.SendSync(
+		&RenameIndexMessage{
+			Index:   indexName,
+			NewName: newName,
+			Check:   true,
+		})
+	if err != nil {
+		return NewBadRequestError(errors.Wrap(err, "checking rename on all nodes"))
+	}
+
+	if err := api.holder.RenameIndex(indexName, newName); err != nil {
+		return errors.Wrap(err, "renaming index")
+	}
+	err = api.server.SendSync(
+		&RenameIndexMessage{
+			Index:   indexName,
+			NewName: newName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending RenameIndex message, rolling back: %s", err)
+		api.rollbackRenameIndex(indexName, newName)
+		return errors.Wrap(err, "sending RenameIndex message")
+	}
+	api.holder.Stats.Count("renameIndex", 1, 1.0)
+	return nil
+}
+
+// DeleteColumnRange deletes the columns in [min, max] of an index, and their
+// attributes, on every node.
+func (api *API) DeleteColumnRange(ctx context.Context, indexName string, min, max uint64) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteColumnRange")
+	defer span.Finish()
+
+	if err := api.validate(apiDeleteColumnRange); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	idx := api.holder.Index(indexName)
+	if idx == nil {
+		return newNotFoundError(ErrIndexNotFound)
+	}
+	if err := idx.DeleteColumnRange(min, max); err != nil {
+		return errors.Wrap(err, "deleting column range")
+	}
+
+	// Send the deletion to all nodes.
+	err := api.server.SendSync(
+		&DeleteColumnRangeMessage{
+			Index: indexName,
+			Min:   min,
+			Max:   max,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending DeleteColumnRange message: %s", err)
+	}
+	return errors.Wrap(err, "sending DeleteColumnRange message")
+}
+
+// rollbackRenameIndex renames an index back on every node after a rename
+// failed on some of them. Nodes which hadn't renamed it fail to find it,
+// which is ignored.
+func (api *API) rollbackRenameIndex(indexName, newName string) {
+	if err := api.holder.RenameIndex(newName, indexName); err != nil {
+		api.server.logger.Printf("problem rolling back index rename: %s", err)
+	}
+	err := api.server.SendSync(
+		&RenameIndexMessage{
+			Index:   newName,
+			NewName: indexName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending RenameIndex rollback message: %s", err)
+	}
+}
+
+// RenameField renames a field of an index on every node. Every node checks
+// that the field can be renamed first. If any node then fails to rename it,
+// the nodes which have renamed it are asked to rename it back.
+func (api *API) RenameField(ctx context.Context, indexName, fieldName, newName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RenameField")
+	defer span.Finish()
+
+	if err := api.validate(apiRenameField); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	if err := api.holder.CheckRenameField(indexName, fieldName, newName); err != nil {
+		return errors.Wrap(err, "checking rename")
+	}
+	err := api.server.SendSync(
+		&RenameFieldMessage{
+			Index:   indexName,
+			Field:   fieldName,
+			NewName: newName,
+			Check:   true,
+		})
+	if err != nil {
+		return NewBadRequestError(errors.Wrap(err, "checking rename on all nodes"))
+	}
+
+	if err := api.holder.RenameField(indexName, fieldName, newName); err != nil {
+		return errors.Wrap(err, "renaming field")
+	}
+	err = api.server.SendSync(
+		&RenameFieldMessage{
+			Index:   indexName,
+			Field:   fieldName,
+			NewName: newName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending RenameField message, rolling back: %s", err)
+		api.rollbackRenameField(indexName, fieldName, newName)
+		return errors.Wrap(err, "sending RenameField message")
+	}
+	api.holder.Stats.Count("renameField", 1, 1.0)
+	return nil
+}
+
+// rollbackRenameField renames a field back on every node after a rename
+// failed on some of them. Nodes which hadn't renamed it fail to find it,
+// which is ignored.
+func (api *API) rollbackRenameField(indexName, fieldName, newName string) {
+	if err := api.holder.RenameField(indexName, newName, fieldName); err != nil {
+		api.server.logger.Printf("problem rolling back field rename: %s", err)
+	}
+	err := api.server.SendSync(
+		&RenameFieldMessage{
+			Index:   indexName,
+			Field:   newName,
+			NewName: fieldName,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending RenameField rollback message: %s", err)
+	}
+}
+
 // CreateField makes the named field in the named index with the given options.
 // This method currently only takes a single functional option, but that may be
 // changed in the future to support multiple options.
@@ -221,6 +436,8 @@ func (api *API) CreateField(ctx context.Context, indexName string, fieldName str
 	index := api.holder.Index(indexName)
 	if index == nil {
 		return nil, newNotFoundError(ErrIndexNotFound)
+	} else if fo.Type == FieldTypeTime && fo.TimeQuantum == "" && index.TimeQuantum() == "" {
+		return nil, NewBadRequestError(errors.New("timeQuantum is required for field type time"))
 	}
 
 	// Create field.
@@ -229,7 +446,10 @@ func (api *API) CreateField(ctx context.Context, indexName string, fieldName str
 		return nil, errors.Wrap(err, "creating field")
 	}
 
-	// Send the create field message to all nodes.
+	// Send the create field message to all nodes. The field's options are
+	// sent, rather than those requested, so that every node uses the time
+	// quantum this node inherited.
+	fo = field.Options()
 	err = api.server.SendSync(
 		&CreateFieldMessage{
 			Index: indexName,
@@ -287,13 +507,15 @@ func setUpImportOptions(opts ...ImportOption) (*ImportOptions, error) {
 // set in Pilosa row "i/ShardWidth", and in column
 // (shard*ShardWidth)+(i%ShardWidth). That is to say that "data" represents all
 // of the rows in this shard of this field concatenated together in one long
-// bitmap.
+// bitmap. ShardWidth here is the shard width of the index.
 func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string, shard uint64, remote bool, req *ImportRoaringRequest) (err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "API.ImportRoaring")
 	defer span.Finish()
 
 	if err = api.validate(apiField); err != nil {
 		return errors.Wrap(err, "validating api method")
+	} else if api.holder.ReadOnly {
+		return newForbiddenError(ErrReadOnly)
 	}
 
 	nodes := api.cluster.shardNodes(indexName, shard)
@@ -304,6 +526,10 @@ func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string,
 		return newNotFoundError(ErrFieldNotFound)
 	}
 
+	if api.holder.fieldCopying(indexName, fieldName) {
+		return newConflictError(ErrFieldCopying)
+	}
+
 	// only set and time fields are supported
 	if field.Type() != FieldTypeSet && field.Type() != FieldTypeTime {
 		return NewBadRequestError(errors.New("roaring import is only supported for set and time fields"))
@@ -389,6 +615,115 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 	return nil
 }
 
+// SetIndexTimeQuantum changes the default time quantum of an index. It is
+// used by time fields created afterwards without a quantum of their own;
+// existing fields are unaffected.
+func (api *API) SetIndexTimeQuantum(ctx context.Context, indexName string, q TimeQuantum) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetIndexTimeQuantum")
+	defer span.Finish()
+
+	if err := api.validate(apiSetIndexTimeQuantum); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve index.
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound)
+	} else if !q.Valid() {
+		return NewBadRequestError(ErrInvalidTimeQuantum)
+	}
+
+	if err := index.setTimeQuantum(q); err != nil {
+		return errors.Wrap(err, "setting time quantum")
+	}
+
+	// Send the time quantum to all nodes.
+	err := api.server.SendSync(
+		&SetIndexTimeQuantumMessage{
+			Index:       indexName,
+			TimeQuantum: q,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending SetIndexTimeQuantum message: %s", err)
+	}
+	return errors.Wrap(err, "sending SetIndexTimeQuantum message")
+}
+
+// SetFieldTimeQuantum changes the time quantum of an existing time field.
+// Only subsequent writes are affected; views already written are kept.
+func (api *API) SetFieldTimeQuantum(ctx context.Context, indexName, fieldName string, q TimeQuantum) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetFieldTimeQuantum")
+	defer span.Finish()
+
+	if err := api.validate(apiSetFieldTimeQuantum); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve field.
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound)
+	} else if f.Type() != FieldTypeTime {
+		return NewBadRequestError(errors.Errorf("time quantum can only be set on a time field: %s", fieldName))
+	} else if q == "" || !q.Valid() {
+		return NewBadRequestError(ErrInvalidTimeQuantum)
+	}
+
+	if err := f.setTimeQuantum(q); err != nil {
+		return errors.Wrap(err, "setting time quantum")
+	}
+
+	// Send the time quantum to all nodes.
+	err := api.server.SendSync(
+		&SetFieldTimeQuantumMessage{
+			Index:       indexName,
+			Field:       fieldName,
+			TimeQuantum: q,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending SetFieldTimeQuantum message: %s", err)
+	}
+	return errors.Wrap(err, "sending SetFieldTimeQuantum message")
+}
+
+// SetFieldCacheSize changes the cache size of an existing ranked or LRU
+// field on every node, evicting the rows beyond the new size.
+func (api *API) SetFieldCacheSize(ctx context.Context, indexName, fieldName string, size uint32) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetFieldCacheSize")
+	defer span.Finish()
+
+	if err := api.validate(apiSetFieldCacheSize); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve field.
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound)
+	} else if ct := f.Options().CacheType; ct != CacheTypeRanked && ct != CacheTypeLRU {
+		return NewBadRequestError(errors.Errorf("cache size can only be set on a field with a ranked or lru cache: %s", fieldName))
+	} else if size == 0 {
+		return NewBadRequestError(errors.New("cache size must be positive"))
+	}
+
+	if err := f.SetCacheSize(size); err != nil {
+		return errors.Wrap(err, "setting cache size")
+	}
+
+	// Send the cache size to all nodes.
+	err := api.server.SendSync(
+		&SetFieldCacheSizeMessage{
+			Index:     indexName,
+			Field:     fieldName,
+			CacheSize: size,
+		})
+	if err != nil {
+		api.server.logger.Printf("problem sending SetFieldCacheSize message: %s", err)
+	}
+	return errors.Wrap(err, "sending SetFieldCacheSize message")
+}
+
 // DeleteAvailableShard a shard ID from the available shard set cache.
 func (api *API) DeleteAvailableShard(_ context.Context, indexName, fieldName string, shardID uint64) error {
 	if err := api.validate(apiDeleteAvailableShard); err != nil {
@@ -421,9 +756,87 @@ func (api *API) DeleteAvailableShard(_ context.Context, indexName, fieldName str
 	return nil
 }
 
+// ExportOptions holds the options for the API export methods.
+type ExportOptions struct {
+	// Only rows between MinRowID and MaxRowID, inclusive, are exported.
+	MinRowID uint64
+	MaxRowID uint64
+
+	// If not nil, only the listed rows within the range are exported.
+	RowIDs []uint64
+
+	// If set, only the named view is exported. Otherwise CSV exports hold
+	// the standard view and protobuf exports every view.
+	View string
+}
+
+// ExportOption is a functional option type for the API export methods.
+type ExportOption func(*ExportOptions) error
+
+// OptExportOptionsRowRange restricts an export to the rows from min to max,
+// inclusive.
+func OptExportOptionsRowRange(min, max uint64) ExportOption {
+	return func(o *ExportOptions) error {
+		if min > max {
+			return NewBadRequestError(errors.Errorf("min row %d is greater than max row %d", min, max))
+		}
+		o.MinRowID, o.MaxRowID = min, max
+		return nil
+	}
+}
+
+// OptExportOptionsRowIDs restricts an export to the given rows. An empty,
+// non-nil list exports nothing.
+func OptExportOptionsRowIDs(rowIDs []uint64) ExportOption {
+	return func(o *ExportOptions) error {
+		o.RowIDs = rowIDs
+		return nil
+	}
+}
+
+// OptExportOptionsView restricts an export to the named view.
+func OptExportOptionsView(name string) ExportOption {
+	return func(o *ExportOptions) error {
+		o.View = name
+		return nil
+	}
+}
+
+// NewExportOptions applies opts to the default options, which export every
+// row.
+func NewExportOptions(opts ...ExportOption) (*ExportOptions, error) {
+	options := &ExportOptions{MaxRowID: math.MaxUint64}
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, errors.Wrap(err, "applying option")
+		}
+	}
+	return options, nil
+}
+
+// rowIDs returns the sorted, distinct listed rows within the range.
+func (o *ExportOptions) rowIDs() []uint64 {
+	rowIDs := make([]uint64, 0, len(o.RowIDs))
+	for _, rowID := range o.RowIDs {
+		if rowID >= o.MinRowID && rowID <= o.MaxRowID {
+			rowIDs = append(rowIDs, rowID)
+		}
+	}
+	sort.Slice(rowIDs, func(i, j int) bool { return rowIDs[i] < rowIDs[j] })
+
+	n := 0
+	for i, rowID := range rowIDs {
+		if i == 0 || rowID != rowIDs[n-1] {
+			rowIDs[n] = rowID
+			n++
+		}
+	}
+	return rowIDs[:n]
+}
+
 // ExportCSV encodes the fragment designated by the index,field,shard as
 // CSV of the form <row>,<col>
-func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName string, shard uint64, w io.Writer) error {
+func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName string, shard uint64, w io.Writer, opts ...ExportOption) error {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.ExportCSV")
 	defer span.Finish()
 
@@ -431,6 +844,11 @@ func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName strin
 		return errors.Wrap(err, "validating api method")
 	}
 
+	options, err := NewExportOptions(opts...)
+	if err != nil {
+		return errors.Wrap(err, "setting up export options")
+	}
+
 	// Validate that this handler owns the shard.
 	if !api.cluster.ownsShard(api.Node().ID, indexName, shard) {
 		api.server.logger.Printf("node %s does not own shard %d of index %s", api.Node().ID, shard, indexName)
@@ -450,10 +868,18 @@ func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName strin
 	}
 
 	// Find the fragment.
-	f := api.holder.fragment(indexName, fieldName, viewStandard, shard)
-	if f == nil {
+	viewName := viewStandard
+	if options.View != "" {
+		viewName = options.View
+	}
+	// Hold the fragment open for the whole export.
+	f, err := api.holder.acquireFragment(indexName, fieldName, viewName, shard)
+	if err != nil {
+		return errors.Wrap(err, "acquiring fragment")
+	} else if f == nil {
 		return ErrFragmentNotFound
 	}
+	defer f.release()
 
 	// Wrap writer with a CSV writer.
 	cw := csv.NewWriter(w)
@@ -487,7 +913,7 @@ func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName strin
 	}
 
 	// Iterate over each column.
-	if err := f.forEachBit(fn); err != nil {
+	if err := f.forEachExportBit(options, fn); err != nil {
 		return errors.Wrap(err, "writing CSV")
 	}
 
@@ -499,6 +925,123 @@ func (api *API) ExportCSV(ctx context.Context, indexName string, fieldName strin
 	return nil
 }
 
+// ExportProto writes the bits of a shard of a field to w as a stream of
+// ImportRequest messages, each prefixed with its length as a uvarint. There is
+// a message for each view and run of at most chunk bits, and every message
+// names its view, so posting the stream back to the import endpoint rebuilds
+// the same fragments. The bits of a time view are given the start of the
+// view's period as their timestamp. Row and column IDs are never translated.
+func (api *API) ExportProto(ctx context.Context, indexName string, fieldName string, shard uint64, chunk int, w io.Writer, opts ...ExportOption) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ExportProto")
+	defer span.Finish()
+
+	if err := api.validate(apiExportProto); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	options, err := NewExportOptions(opts...)
+	if err != nil {
+		return errors.Wrap(err, "setting up export options")
+	}
+
+	// Validate that this handler owns the shard.
+	if !api.cluster.ownsShard(api.Node().ID, indexName, shard) {
+		api.server.logger.Printf("node %s does not own shard %d of index %s", api.Node().ID, shard, indexName)
+		return ErrClusterDoesNotOwnShard
+	}
+
+	field := api.holder.Field(indexName, fieldName)
+	if api.holder.Index(indexName) == nil {
+		return newNotFoundError(ErrIndexNotFound)
+	} else if field == nil {
+		return newNotFoundError(ErrFieldNotFound)
+	} else if field.Type() == FieldTypeInt {
+		return NewBadRequestError(errors.New("protobuf export is not supported for int fields"))
+	} else if chunk <= 0 {
+		return NewBadRequestError(errors.New("chunk size must be positive"))
+	}
+
+	views := field.views()
+	sort.Slice(views, func(i, j int) bool { return views[i].name < views[j].name })
+
+	var n int
+	for _, v := range views {
+		if !field.importableView(v.name) {
+			continue
+		} else if options.View != "" && v.name != options.View {
+			continue
+		}
+		viewN, err := api.exportProtoView(v, shard, chunk, options, w)
+		n += viewN
+		if err != nil {
+			return err
+		}
+	}
+
+	span.LogKV("n", n)
+
+	return nil
+}
+
+// exportProtoView writes the bits of a shard of a view for ExportProto, and
+// returns the number written. The fragment is held open while it's read.
+func (api *API) exportProtoView(v *view, shard uint64, chunk int, options *ExportOptions, w io.Writer) (n int, err error) {
+	f, err := v.acquireFragment(shard)
+	if err != nil {
+		return 0, errors.Wrapf(err, "acquiring fragment of view %s", v.name)
+	} else if f == nil {
+		return 0, nil
+	}
+	defer f.release()
+
+	var timestamp int64
+	if v.name != viewStandard {
+		_, start, _ := viewTimeUnit(v.name)
+		timestamp = start.UnixNano()
+	}
+
+	req := &ImportRequest{Index: v.index, Field: v.field, Shard: shard, View: v.name}
+	flush := func() error {
+		if len(req.ColumnIDs) == 0 {
+			return nil
+		} else if timestamp != 0 {
+			req.Timestamps = req.Timestamps[:0]
+			for range req.ColumnIDs {
+				req.Timestamps = append(req.Timestamps, timestamp)
+			}
+		}
+
+		buf, err := api.Serializer.Marshal(req)
+		if err != nil {
+			return errors.Wrap(err, "marshaling")
+		}
+		var prefix [binary.MaxVarintLen64]byte
+		if _, err := w.Write(prefix[:binary.PutUvarint(prefix[:], uint64(len(buf)))]); err != nil {
+			return err
+		} else if _, err := w.Write(buf); err != nil {
+			return err
+		}
+
+		n += len(req.ColumnIDs)
+		req.RowIDs, req.ColumnIDs = req.RowIDs[:0], req.ColumnIDs[:0]
+		return nil
+	}
+
+	if err := f.forEachExportBit(options, func(rowID, columnID uint64) error {
+		req.RowIDs = append(req.RowIDs, rowID)
+		req.ColumnIDs = append(req.ColumnIDs, columnID)
+		if len(req.ColumnIDs) < chunk {
+			return nil
+		}
+		return flush()
+	}); err != nil {
+		return n, errors.Wrapf(err, "writing view %s", v.name)
+	} else if err := flush(); err != nil {
+		return n, errors.Wrapf(err, "writing view %s", v.name)
+	}
+	return n, nil
+}
+
 // ShardNodes returns the node and all replicas which should contain a shard's data.
 func (api *API) ShardNodes(ctx context.Context, indexName string, shard uint64) ([]*Node, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.ShardNodes")
@@ -513,7 +1056,8 @@ func (api *API) ShardNodes(ctx context.Context, indexName string, shard uint64)
 
 // FragmentBlockData is an endpoint for internal usage. It is not guaranteed to
 // return anything useful. Currently it returns protobuf encoded row and column
-// ids from a "block" which is a subdivision of a fragment.
+// ids from a "block" which is a subdivision of a fragment. The request's
+// Offset and Limit select a page of the ids.
 func (api *API) FragmentBlockData(ctx context.Context, body io.Reader) ([]byte, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentBlockData")
 	defer span.Finish()
@@ -531,14 +1075,18 @@ func (api *API) FragmentBlockData(ctx context.Context, body io.Reader) ([]byte,
 		return nil, NewBadRequestError(errors.Wrap(err, "unmarshal body error"))
 	}
 
-	// Retrieve fragment from holder.
-	f := api.holder.fragment(req.Index, req.Field, req.View, req.Shard)
-	if f == nil {
+	// Retrieve fragment from holder, acquired so that it isn't closed while
+	// idle part way through the read.
+	f, err := api.holder.acquireFragment(req.Index, req.Field, req.View, req.Shard)
+	if err != nil {
+		return nil, errors.Wrap(err, "acquiring fragment")
+	} else if f == nil {
 		return nil, ErrFragmentNotFound
 	}
+	defer f.release()
 
 	var resp = BlockDataResponse{}
-	resp.RowIDs, resp.ColumnIDs = f.blockData(int(req.Block))
+	resp.RowIDs, resp.ColumnIDs, resp.More = f.blockData(int(req.Block), int(req.Offset), int(req.Limit))
 
 	// Encode response.
 	buf, err := api.Serializer.Marshal(&resp)
@@ -559,16 +1107,40 @@ func (api *API) FragmentBlocks(ctx context.Context, indexName, fieldName, viewNa
 	}
 
 	// Retrieve fragment from holder.
-	f := api.holder.fragment(indexName, fieldName, viewName, shard)
-	if f == nil {
+	f, err := api.holder.acquireFragment(indexName, fieldName, viewName, shard)
+	if err != nil {
+		return nil, errors.Wrap(err, "acquiring fragment")
+	} else if f == nil {
 		return nil, ErrFragmentNotFound
 	}
+	defer f.release()
 
 	// Retrieve blocks.
 	blocks := f.Blocks()
 	return blocks, nil
 }
 
+// FragmentChecksums returns the checksum of each fragment of a view held by
+// this node. A view this node holds no shards of has no checksums.
+func (api *API) FragmentChecksums(ctx context.Context, indexName, fieldName, viewName string) ([]FragmentChecksum, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentChecksums")
+	defer span.Finish()
+
+	if err := api.validate(apiFragmentChecksums); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+	v := f.view(viewName)
+	if v == nil {
+		return []FragmentChecksum{}, nil
+	}
+	return v.fragmentChecksums(), nil
+}
+
 // FragmentData returns all data in the specified fragment.
 func (api *API) FragmentData(ctx context.Context, indexName, fieldName, viewName string, shard uint64) (io.WriterTo, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.FragmentData")
@@ -579,13 +1151,111 @@ func (api *API) FragmentData(ctx context.Context, indexName, fieldName, viewName
 	}
 
 	// Retrieve fragment from holder.
-	f := api.holder.fragment(indexName, fieldName, viewName, shard)
-	if f == nil {
+	f, err := api.holder.acquireFragment(indexName, fieldName, viewName, shard)
+	if err != nil {
+		return nil, errors.Wrap(err, "acquiring fragment")
+	} else if f == nil {
 		return nil, ErrFragmentNotFound
 	}
+	defer f.release()
 	return f, nil
 }
 
+// DeleteFragment closes and deletes the fragment of a shard of a view on this
+// node, with its data and cache files, so that anti-entropy or an import can
+// repopulate it. The next write to the shard creates an empty fragment. The
+// shard remains available while other views or nodes hold it.
+func (api *API) DeleteFragment(ctx context.Context, indexName, fieldName, viewName string, shard uint64) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteFragment")
+	defer span.Finish()
+
+	if err := api.validate(apiDeleteFragment); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound)
+	}
+	v := f.view(viewName)
+	if v == nil {
+		return newNotFoundError(ErrFragmentNotFound)
+	}
+	if err := v.deleteFragment(shard); err == ErrFragmentNotFound {
+		return newNotFoundError(err)
+	} else if err != nil {
+		return errors.Wrap(err, "deleting fragment")
+	}
+	return nil
+}
+
+// CompactFragment rewrites the data file of a fragment on this node, folding
+// in its op log and dropping empty containers, and returns the number of
+// bytes reclaimed.
+func (api *API) CompactFragment(ctx context.Context, indexName, fieldName, viewName string, shard uint64) (int64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CompactFragment")
+	defer span.Finish()
+
+	if err := api.validate(apiCompactFragment); err != nil {
+		return 0, errors.Wrap(err, "validating api method")
+	}
+
+	v, err := api.compactView(indexName, fieldName, viewName)
+	if err != nil {
+		return 0, err
+	}
+	frag, err := v.acquireFragment(shard)
+	if err == ErrFragmentClosing {
+		return 0, newNotFoundError(ErrFragmentNotFound)
+	} else if err != nil {
+		return 0, errors.Wrap(err, "acquiring fragment")
+	} else if frag == nil {
+		return 0, newNotFoundError(ErrFragmentNotFound)
+	}
+	defer frag.release()
+	n, err := frag.Compact()
+	if err == ErrFragmentClosing {
+		return 0, newNotFoundError(ErrFragmentNotFound)
+	} else if err != nil {
+		return 0, errors.Wrap(err, "compacting fragment")
+	}
+	return n, nil
+}
+
+// CompactView compacts each fragment of a view on this node in turn, and
+// returns the number of bytes reclaimed.
+func (api *API) CompactView(ctx context.Context, indexName, fieldName, viewName string) (int64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CompactView")
+	defer span.Finish()
+
+	if err := api.validate(apiCompactFragment); err != nil {
+		return 0, errors.Wrap(err, "validating api method")
+	}
+
+	v, err := api.compactView(indexName, fieldName, viewName)
+	if err != nil {
+		return 0, err
+	}
+	n, err := v.compact()
+	if err != nil {
+		return n, errors.Wrap(err, "compacting view")
+	}
+	return n, nil
+}
+
+// compactView returns the view holding the fragments to compact.
+func (api *API) compactView(indexName, fieldName, viewName string) (*view, error) {
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	}
+	v := f.view(viewName)
+	if v == nil {
+		return nil, newNotFoundError(ErrFragmentNotFound)
+	}
+	return v, nil
+}
+
 // Hosts returns a list of the hosts in the cluster including their ID,
 // URL, and which is the coordinator.
 func (api *API) Hosts(ctx context.Context) []*Node {
@@ -617,6 +1287,150 @@ func (api *API) RecalculateCaches(ctx context.Context) error {
 	return nil
 }
 
+// RebuildCaches rebuilds the caches of every fragment on every node from
+// storage in the background, as when fragments are opened without valid cache
+// files. TopN reads counts from storage until each cache is rebuilt.
+func (api *API) RebuildCaches(ctx context.Context) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RebuildCaches")
+	defer span.Finish()
+
+	if err := api.validate(apiRebuildCaches); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	if err := api.server.SendSync(&RecalculateCaches{Rebuild: true}); err != nil {
+		return errors.Wrap(err, "broadcasting message")
+	}
+	return api.holder.rebuildCaches()
+}
+
+// FragmentCache describes the contents of a fragment's row count cache.
+type FragmentCache struct {
+	Shard uint64 `json:"shard"`
+	Size  int    `json:"size"`
+	Pairs []Pair `json:"pairs,omitempty"`
+}
+
+// FieldCache returns the cached row counts of the standard view of a field
+// for the given shards. If no shards are given then a summary of every
+// local fragment is returned without the individual counts.
+func (api *API) FieldCache(ctx context.Context, indexName, fieldName string, shards []uint64) ([]FragmentCache, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FieldCache")
+	defer span.Finish()
+
+	if err := api.validate(apiFieldCache); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	frags, err := api.cacheFragments(indexName, fieldName, shards)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseFragments(frags)
+
+	caches := make([]FragmentCache, len(frags))
+	for i, frag := range frags {
+		caches[i] = FragmentCache{Shard: frag.shard}
+		if len(shards) == 0 {
+			caches[i].Size = frag.cacheLen()
+		} else {
+			caches[i].Pairs = frag.cachePairs()
+			caches[i].Size = len(caches[i].Pairs)
+		}
+	}
+	return caches, nil
+}
+
+// InvalidateFieldCache drops the cached row counts of the standard view of
+// a field for the given shards, or every local fragment if no shards are
+// given, and rebuilds them from storage.
+func (api *API) InvalidateFieldCache(ctx context.Context, indexName, fieldName string, shards []uint64) ([]FragmentCache, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.InvalidateFieldCache")
+	defer span.Finish()
+
+	if err := api.validate(apiInvalidateFieldCache); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	frags, err := api.cacheFragments(indexName, fieldName, shards)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseFragments(frags)
+
+	// The caches are rebuilt in the background, without holding up reads and
+	// writes of the fragments, and their sizes reported once they're done.
+	for _, frag := range frags {
+		if err := frag.resetCache(); err != nil {
+			return nil, errors.Wrapf(err, "resetting cache: shard=%d", frag.shard)
+		}
+	}
+	caches := make([]FragmentCache, len(frags))
+	for i, frag := range frags {
+		frag.cacheRebuild.Wait()
+		caches[i] = FragmentCache{Shard: frag.shard, Size: frag.cacheLen()}
+	}
+	return caches, nil
+}
+
+// cacheFragments returns the local fragments of a field's standard view for
+// the given shards, or all of them if no shards are given. The fragments are
+// acquired and must be released with releaseFragments.
+func (api *API) cacheFragments(indexName, fieldName string, shards []uint64) ([]*fragment, error) {
+	field := api.holder.Field(indexName, fieldName)
+	if field == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	}
+	view := field.view(viewStandard)
+
+	if len(shards) == 0 {
+		if view == nil {
+			return nil, nil
+		}
+		all := view.allFragments()
+		sort.Slice(all, func(i, j int) bool { return all[i].shard < all[j].shard })
+		frags := make([]*fragment, 0, len(all))
+		for _, f := range all {
+			frag, err := view.acquireFragment(f.shard)
+			if err == ErrFragmentClosing {
+				continue // deleted while acquiring the others
+			} else if err != nil {
+				releaseFragments(frags)
+				return nil, errors.Wrap(err, "acquiring fragment")
+			} else if frag == nil {
+				continue
+			}
+			frags = append(frags, frag)
+		}
+		return frags, nil
+	}
+
+	frags := make([]*fragment, 0, len(shards))
+	for _, shard := range shards {
+		var frag *fragment
+		if view != nil {
+			var err error
+			if frag, err = view.acquireFragment(shard); err != nil {
+				releaseFragments(frags)
+				return nil, errors.Wrap(err, "acquiring fragment")
+			}
+		}
+		if frag == nil {
+			releaseFragments(frags)
+			return nil, newNotFoundError(ErrFragmentNotFound)
+		}
+		frags = append(frags, frag)
+	}
+	return frags, nil
+}
+
+// releaseFragments releases each of a set of acquired fragments.
+func releaseFragments(frags []*fragment) {
+	for _, frag := range frags {
+		frag.release()
+	}
+}
+
 // PostClusterMessage is for internal use. It decodes a protobuf message out of
 // the body and forwards it to the BroadcastHandler.
 func (api *API) ClusterMessage(ctx context.Context, reqBody io.Reader) error {
@@ -652,7 +1466,62 @@ func (api *API) ClusterMessage(ctx context.Context, reqBody io.Reader) error {
 func (api *API) Schema(ctx context.Context) []*IndexInfo {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Schema")
 	defer span.Finish()
-	return api.holder.limitedSchema()
+	return api.holder.limitedSchema(false, false)
+}
+
+// SchemaETag returns an entity tag which changes whenever an index or field
+// is created, deleted or renamed on this node.
+func (api *API) SchemaETag() string {
+	return api.holder.SchemaETag()
+}
+
+// CanonicalSchema returns the schema of this node, including internal fields
+// and views, in a form which can be compared with other nodes.
+func (api *API) CanonicalSchema(ctx context.Context) *CanonicalSchema {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CanonicalSchema")
+	defer span.Finish()
+	return api.holder.CanonicalSchema()
+}
+
+// SchemaVerbose returns the same information as Schema along with
+// statistics about each field's cache on this node.
+func (api *API) SchemaVerbose(ctx context.Context) []*IndexInfo {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SchemaVerbose")
+	defer span.Finish()
+	return api.holder.limitedSchema(true, true)
+}
+
+// SchemaViews returns the same information as Schema along with the views
+// of each field on this node.
+func (api *API) SchemaViews(ctx context.Context) []*IndexInfo {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SchemaViews")
+	defer span.Finish()
+	return api.holder.limitedSchema(true, false)
+}
+
+// ApplySchema creates the indexes, fields and views of schema which don't
+// exist on this node. Existing ones are left as they are; those whose
+// options differ from schema are returned in a SchemaConflictError, after
+// everything else has been created.
+func (api *API) ApplySchema(ctx context.Context, schema []*IndexInfo) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ApplySchema")
+	defer span.Finish()
+
+	if err := api.validate(apiApplySchema); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	for _, index := range schema {
+		if index == nil || index.Name == "" {
+			return NewBadRequestError(errors.New("index name required"))
+		}
+		for _, f := range index.Fields {
+			if f == nil || f.Name == "" {
+				return NewBadRequestError(errors.Errorf("field name required in index %s", index.Name))
+			}
+		}
+	}
+	return api.holder.applySchema(&Schema{Indexes: schema}, true)
 }
 
 // Views returns the views in the given field.
@@ -675,6 +1544,36 @@ func (api *API) Views(ctx context.Context, indexName string, fieldName string) (
 	return views, nil
 }
 
+// ViewInfos returns information about the views of a field on this node,
+// sorted by name. If from or to is set, only the time views whose periods
+// overlap [from, to) are returned.
+func (api *API) ViewInfos(ctx context.Context, indexName, fieldName string, from, to time.Time) ([]*ViewInfo, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ViewInfos")
+	defer span.Finish()
+
+	if err := api.validate(apiViews); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	} else if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, NewBadRequestError(errors.New("from must be before to"))
+	}
+
+	infos := make([]*ViewInfo, 0)
+	for _, v := range f.views() {
+		info := v.info(f.Type() == FieldTypeTime)
+		if (!from.IsZero() || !to.IsZero()) && !info.overlaps(from, to) {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	sort.Sort(viewInfoSlice(infos))
+	return infos, nil
+}
+
 // DeleteView removes the given view.
 func (api *API) DeleteView(ctx context.Context, indexName string, fieldName string, viewName string) error {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.DeleteView")
@@ -687,11 +1586,11 @@ func (api *API) DeleteView(ctx context.Context, indexName string, fieldName stri
 	// Retrieve field.
 	f := api.holder.Field(indexName, fieldName)
 	if f == nil {
-		return ErrFieldNotFound
+		return newNotFoundError(ErrFieldNotFound)
 	}
 
 	// Delete the view.
-	if err := f.deleteView(viewName); err != nil {
+	if err := f.DeleteView(viewName); err != nil {
 		// Ignore this error because views do not exist on all nodes due to shard distribution.
 		if err != ErrInvalidView {
 			return errors.Wrap(err, "deleting view")
@@ -717,39 +1616,182 @@ func (api *API) IndexAttrDiff(ctx context.Context, indexName string, blocks []At
 	span, _ := tracing.StartSpanFromContext(ctx, "API.IndexAttrDiff")
 	defer span.Finish()
 
-	if err := api.validate(apiIndexAttrDiff); err != nil {
+	if err := api.validate(apiIndexAttrDiff); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	// Retrieve index from holder.
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+
+	// Retrieve local blocks.
+	localBlocks, err := index.ColumnAttrStore().Blocks()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting blocks")
+	}
+
+	// Read all attributes from all mismatched blocks.
+	attrs := make(map[uint64]map[string]interface{})
+	for _, blockID := range attrBlocks(localBlocks).Diff(blocks) {
+		// Retrieve block data.
+		m, err := index.ColumnAttrStore().BlockData(blockID)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting block")
+		}
+
+		// Copy to index-wide struct.
+		for k, v := range m {
+			attrs[k] = v
+		}
+	}
+	return attrs, nil
+}
+
+// IndexAttrBlocks returns the checksums and block ids of the column
+// attribute store of an index.
+func (api *API) IndexAttrBlocks(ctx context.Context, indexName string) ([]AttrBlock, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.IndexAttrBlocks")
+	defer span.Finish()
+
+	if err := api.validate(apiIndexAttrBlocks); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+	return index.ColumnAttrStore().Blocks()
+}
+
+// IndexAttrBlockData returns a page of the column attributes in a block of
+// the column attribute store of an index, starting at column start. A limit
+// of zero returns up to DefaultAttrBlockPageLimit entries.
+func (api *API) IndexAttrBlockData(ctx context.Context, indexName string, block, start uint64, limit int) (*AttrBlockPage, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.IndexAttrBlockData")
+	defer span.Finish()
+
+	if err := api.validate(apiIndexAttrBlockData); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	if limit == 0 {
+		limit = DefaultAttrBlockPageLimit
+	} else if limit < 0 || limit > MaxAttrBlockPageLimit {
+		return nil, NewBadRequestError(errors.Errorf("limit must be between 1 and %d", MaxAttrBlockPageLimit))
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+	return attrBlockPage(index.ColumnAttrStore(), block, start, limit)
+}
+
+// SearchColumnAttrs returns the columns of an index on this node whose value
+// of an attribute matches the search, with all of their attributes.
+func (api *API) SearchColumnAttrs(ctx context.Context, indexName string, q *ColumnAttrSearch) (*ColumnAttrSearchResult, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SearchColumnAttrs")
+	defer span.Finish()
+
+	if err := api.validate(apiSearchColumnAttrs); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	match, err := q.match()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultColumnAttrSearchLimit
+	} else if limit < 0 || limit > MaxColumnAttrSearchLimit {
+		return nil, NewBadRequestError(errors.Errorf("limit must be between 1 and %d", MaxColumnAttrSearchLimit))
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+
+	// Find one more column than the limit to tell whether there are more.
+	m, err := index.ColumnAttrStore().Find(q.Attr, match, limit+1)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding column attrs")
+	}
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	result := &ColumnAttrSearchResult{ColumnAttrs: []*ColumnAttrSet{}}
+	if len(ids) > limit {
+		ids, result.Truncated = ids[:limit], true
+	}
+	for _, id := range ids {
+		set := &ColumnAttrSet{ID: id, Attrs: m[id]}
+		if index.Keys() {
+			if set.Key, err = api.holder.translateFile.TranslateColumnToString(index.Name(), id); err != nil {
+				return nil, errors.Wrap(err, "translating column")
+			}
+		}
+		result.ColumnAttrs = append(result.ColumnAttrs, set)
+	}
+	return result, nil
+}
+
+// RowAttrs returns the attributes of the rows of a field with the given IDs.
+// Every ID is returned, those of rows without attributes with an empty map.
+func (api *API) RowAttrs(ctx context.Context, indexName, fieldName string, ids []uint64) (map[uint64]map[string]interface{}, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RowAttrs")
+	defer span.Finish()
+
+	if err := api.validate(apiRowAttrs); err != nil {
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
-	// Retrieve index from holder.
-	index := api.holder.Index(indexName)
-	if index == nil {
-		return nil, newNotFoundError(ErrIndexNotFound)
+	if len(ids) > MaxBulkRowAttrIDs {
+		return nil, NewBadRequestError(errors.Errorf("at most %d ids allowed", MaxBulkRowAttrIDs))
 	}
 
-	// Retrieve local blocks.
-	localBlocks, err := index.ColumnAttrStore().Blocks()
-	if err != nil {
-		return nil, errors.Wrap(err, "getting blocks")
+	field := api.holder.Field(indexName, fieldName)
+	if field == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
 	}
 
-	// Read all attributes from all mismatched blocks.
-	attrs := make(map[uint64]map[string]interface{})
-	for _, blockID := range attrBlocks(localBlocks).Diff(blocks) {
-		// Retrieve block data.
-		m, err := index.ColumnAttrStore().BlockData(blockID)
-		if err != nil {
-			return nil, errors.Wrap(err, "getting block")
-		}
-
-		// Copy to index-wide struct.
-		for k, v := range m {
-			attrs[k] = v
+	m, err := field.RowAttrStore().BlockAttrs(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting row attrs")
+	}
+	attrs := make(map[uint64]map[string]interface{}, len(ids))
+	for _, id := range ids {
+		if attrs[id] = m[id]; attrs[id] == nil {
+			attrs[id] = map[string]interface{}{}
 		}
 	}
 	return attrs, nil
 }
 
+// SetIndexAttrBlockData applies column attributes read from a block of
+// another store to the column attribute store of an index.
+func (api *API) SetIndexAttrBlockData(ctx context.Context, indexName string, attrs map[uint64]map[string]interface{}) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.SetIndexAttrBlockData")
+	defer span.Finish()
+
+	if err := api.validate(apiSetIndexAttrBlockData); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound)
+	}
+	return errors.Wrap(index.ColumnAttrStore().SetBulkAttrs(attrs), "setting attrs")
+}
+
 func (api *API) FieldAttrDiff(ctx context.Context, indexName string, fieldName string, blocks []AttrBlock) (map[uint64]map[string]interface{}, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.FieldAttrDiff")
 	defer span.Finish()
@@ -810,47 +1852,62 @@ func OptImportOptionsIgnoreKeyCheck(b bool) ImportOption {
 	}
 }
 
-// Import bulk imports data into a particular index,field,shard.
-func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOption) error {
+// Import bulk imports data into a particular index,field,shard. It returns
+// the number of distinct bits imported into each shard, and how many of them
+// changed.
+func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOption) ([]ImportCount, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Import")
 	defer span.Finish()
 
 	if err := api.validate(apiImport); err != nil {
-		return errors.Wrap(err, "validating api method")
+		return nil, errors.Wrap(err, "validating api method")
 	}
 
 	// Set up import options.
 	options, err := setUpImportOptions(opts...)
 	if err != nil {
-		return errors.Wrap(err, "setting up import options")
+		return nil, errors.Wrap(err, "setting up import options")
 	}
 
 	index, field, err := api.indexField(req.Index, req.Field, req.Shard)
 	if err != nil {
-		return errors.Wrap(err, "getting index and field")
+		return nil, errors.Wrap(err, "getting index and field")
+	}
+
+	if err := validateImportTimestamps(req.Timestamps); err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	// Bits imported into a view, e.g. from a protobuf export, are untranslated.
+	if req.View != "" {
+		if len(req.RowKeys) != 0 || len(req.ColumnKeys) != 0 {
+			return nil, NewBadRequestError(errors.New("keys cannot be imported into a view"))
+		} else if !field.importableView(req.View) {
+			return nil, NewBadRequestError(errors.Errorf("cannot import into view %q of field %s", req.View, field.Name()))
+		}
 	}
 
 	// Unless explicitly ignoring key validation (meaning keys have been
 	// translated to ids in a previous step at the coordinator node), then
 	// check to see if keys need translation.
-	if !options.IgnoreKeyCheck {
+	if !options.IgnoreKeyCheck && req.View == "" {
 		// Translate row keys.
 		if field.keys() {
 			if len(req.RowIDs) != 0 {
-				return errors.New("row ids cannot be used because field uses string keys")
+				return nil, errors.New("row ids cannot be used because field uses string keys")
 			}
 			if req.RowIDs, err = api.holder.translateFile.TranslateRowsToUint64(index.Name(), field.Name(), req.RowKeys); err != nil {
-				return errors.Wrap(err, "translating rows")
+				return nil, errors.Wrap(err, "translating rows")
 			}
 		}
 
 		// Translate column keys.
 		if index.Keys() {
 			if len(req.ColumnIDs) != 0 {
-				return errors.New("column ids cannot be used because index uses string keys")
+				return nil, errors.New("column ids cannot be used because index uses string keys")
 			}
 			if req.ColumnIDs, err = api.holder.translateFile.TranslateColumnsToUint64(index.Name(), req.ColumnKeys); err != nil {
-				return errors.Wrap(err, "translating columns")
+				return nil, errors.Wrap(err, "translating columns")
 			}
 		}
 
@@ -860,14 +1917,14 @@ func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOp
 			m := make(map[uint64][]Bit)
 
 			for i, colID := range req.ColumnIDs {
-				shard := colID / ShardWidth
+				shard := colID / index.ShardWidth()
 				if _, ok := m[shard]; !ok {
 					m[shard] = make([]Bit, 0)
 				}
 				m[shard] = append(m[shard], Bit{
 					RowID:     req.RowIDs[i],
 					ColumnID:  colID,
-					Timestamp: req.Timestamps[i],
+					Timestamp: timestampAt(req.Timestamps, i),
 				})
 			}
 
@@ -875,47 +1932,255 @@ func (api *API) Import(ctx context.Context, req *ImportRequest, opts ...ImportOp
 			opts = append(opts, OptImportOptionsIgnoreKeyCheck(true))
 
 			var eg errgroup.Group
+			var mu sync.Mutex
+			counts := make([]ImportCount, 0, len(m))
 			for shard, bits := range m {
 				// TODO: if local node owns this shard we don't need to go through the client
 				shard := shard
 				bits := bits
 				eg.Go(func() error {
-					return api.server.defaultClient.Import(ctx, req.Index, req.Field, shard, bits, opts...)
+					count, err := api.server.defaultClient.Import(ctx, req.Index, req.Field, shard, bits, opts...)
+					mu.Lock()
+					counts = append(counts, count)
+					mu.Unlock()
+					return err
 				})
 			}
-			return eg.Wait()
+			if err := eg.Wait(); err != nil {
+				return nil, err
+			}
+			sort.Slice(counts, func(i, j int) bool { return counts[i].Shard < counts[j].Shard })
+			return counts, nil
 		}
 	}
 
 	// Validate shard ownership.
 	if err := api.validateShardOwnership(req.Index, req.Shard); err != nil {
-		return errors.Wrap(err, "validating shard ownership")
+		return nil, errors.Wrap(err, "validating shard ownership")
 	}
 
 	// Convert timestamps to time.Time.
-	timestamps := make([]*time.Time, len(req.Timestamps))
-	for i, ts := range req.Timestamps {
-		if ts == 0 {
-			continue
-		}
-		t := time.Unix(0, ts).UTC()
-		timestamps[i] = &t
-	}
+	timestamps := importTimestamps(req.Timestamps)
 
 	// Import columnIDs into existence field.
 	if !options.Clear {
 		if err := importExistenceColumns(index, req.ColumnIDs); err != nil {
 			api.server.logger.Printf("import existence error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
-			return errors.Wrap(err, "importing existence columns")
+			return nil, errors.Wrap(err, "importing existence columns")
 		}
 	}
 
 	// Import into fragment.
-	err = field.Import(req.RowIDs, req.ColumnIDs, timestamps, opts...)
+	var counts []ImportCount
+	if req.View != "" {
+		counts, err = field.importView(req.View, req.RowIDs, req.ColumnIDs, opts...)
+	} else {
+		counts, err = field.Import(req.RowIDs, req.ColumnIDs, timestamps, opts...)
+	}
 	if err != nil {
 		api.server.logger.Printf("import error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
+		return nil, errors.Wrap(err, "importing")
+	}
+	return counts, nil
+}
+
+// ImportRoaringRows unions, or clears, whole rows of a shard of a set or time
+// field. Each row is a serialized roaring bitmap, in the standard or pilosa
+// format, of the column offsets within the shard, so a bit "i" in the row
+// bitmap sets column (shard*ShardWidth)+i. The rows are only written to the
+// standard view. It returns the number of bits in the imported rows.
+func (api *API) ImportRoaringRows(ctx context.Context, req *ImportRoaringRowsRequest, opts ...ImportOption) (uint64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ImportRoaringRows")
+	defer span.Finish()
+
+	if err := api.validate(apiImport); err != nil {
+		return 0, errors.Wrap(err, "validating api method")
+	}
+
+	options, err := setUpImportOptions(opts...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setting up import options")
+	}
+
+	index, field, err := api.indexField(req.Index, req.Field, req.Shard)
+	if err == ErrFieldNotFound {
+		return 0, newNotFoundError(err)
+	} else if err != nil {
+		return 0, errors.Wrap(err, "getting index and field")
+	}
+
+	if field.Type() != FieldTypeSet && field.Type() != FieldTypeTime {
+		return 0, NewBadRequestError(errors.New("roaring row import is only supported for set and time fields"))
+	} else if field.options.NoStandardView {
+		return 0, NewBadRequestError(errors.New("roaring row import requires a standard view"))
+	} else if field.keys() {
+		return 0, NewBadRequestError(errors.New("roaring row import doesn't support row keys"))
+	}
+
+	if err := api.validateShardOwnership(req.Index, req.Shard); err != nil {
+		return 0, errors.Wrap(err, "validating shard ownership")
+	}
+
+	// Decode the rows, merging any which are repeated.
+	rows := make(map[uint64]*roaring.Bitmap, len(req.Rows))
+	columns := roaring.NewBitmap()
+	for _, row := range req.Rows {
+		// Decoding the standard roaring format modifies the data.
+		data := make([]byte, len(row.Data))
+		copy(data, row.Data)
+		bm := roaring.NewBitmap()
+		if err := bm.UnmarshalBinary(data); err != nil {
+			return 0, NewBadRequestError(errors.Wrapf(err, "decoding row %d", row.RowID))
+		} else if max := bm.Max(); max >= index.ShardWidth() {
+			return 0, NewBadRequestError(errors.Errorf("row %d: column offset %d is not within the shard width %d", row.RowID, max, index.ShardWidth()))
+		}
+
+		if rows[row.RowID] != nil {
+			bm = bm.Union(rows[row.RowID])
+		}
+		rows[row.RowID] = bm
+		columns.UnionInPlace(bm)
+	}
+	var n uint64
+	for _, bm := range rows {
+		n += bm.Count()
+	}
+
+	// Import the columns into the existence field.
+	if ef := index.existenceField(); ef != nil && !options.Clear {
+		if err := ef.importRoaringRows(req.Shard, map[uint64]*roaring.Bitmap{0: columns}, false); err != nil {
+			return 0, errors.Wrap(err, "importing existence columns")
+		}
+	}
+
+	if err := field.importRoaringRows(req.Shard, rows, options.Clear); err != nil {
+		api.server.logger.Printf("import error: index=%s, field=%s, shard=%d, rows=%d, err=%s", req.Index, req.Field, req.Shard, len(rows), err)
+		return 0, errors.Wrap(err, "importing")
+	}
+	return n, nil
+}
+
+// ImportBits imports bits of any shards of a set, time, mutex or bool field,
+// sending the bits of each shard to the nodes which own it. Bits with keys are
+// sent to the coordinator to be translated.
+func (api *API) ImportBits(ctx context.Context, indexName, fieldName string, bits []Bit, opts ...ImportOption) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.ImportBits")
+	defer span.Finish()
+
+	if err := api.validate(apiImport); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	index, field, err := api.indexField(indexName, fieldName, 0)
+	if err == ErrFieldNotFound {
+		return newNotFoundError(err)
+	} else if err != nil {
+		return errors.Wrap(err, "getting index and field")
+	} else if field.Type() == FieldTypeInt {
+		return NewBadRequestError(errors.New("bits cannot be imported into an int field"))
+	} else if len(bits) == 0 {
+		return nil
+	}
+
+	for _, bit := range bits {
+		if bit.RowKey != "" || bit.ColumnKey != "" {
+			_, err := api.server.defaultClient.ImportK(ctx, indexName, fieldName, bits, opts...)
+			return err
+		}
+	}
+
+	m := make(map[uint64][]Bit)
+	for _, bit := range bits {
+		shard := bit.ColumnID / index.ShardWidth()
+		m[shard] = append(m[shard], bit)
+	}
+
+	var eg errgroup.Group
+	for shard, bits := range m {
+		shard, bits := shard, bits
+		eg.Go(func() error {
+			_, err := api.server.defaultClient.Import(ctx, indexName, fieldName, shard, bits, opts...)
+			return err
+		})
+	}
+	return eg.Wait()
+}
+
+// ImportViewCounts returns the number of bits an import request writes to each
+// view of its field. Bits with a timestamp are written to the time views of
+// the field's quantum as well as the standard view, unless the field has no
+// standard view. A request naming a view only writes to that view.
+func (api *API) ImportViewCounts(ctx context.Context, req *ImportRequest) (map[string]uint64, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ImportViewCounts")
+	defer span.Finish()
+
+	if err := api.validate(apiImport); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	_, field, err := api.indexField(req.Index, req.Field, req.Shard)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting index and field")
+	}
+
+	n := len(req.ColumnIDs)
+	if len(req.ColumnKeys) > n {
+		n = len(req.ColumnKeys)
+	}
+	if req.View != "" {
+		return map[string]uint64{req.View: uint64(n)}, nil
+	}
+	return field.importViewCounts(n, importTimestamps(req.Timestamps)), nil
+}
+
+// importTimestamps converts import timestamps in nanoseconds to times. A zero
+// timestamp means the bit has no time.
+func importTimestamps(a []int64) []*time.Time {
+	timestamps := make([]*time.Time, len(a))
+	for i, ts := range a {
+		if ts == 0 {
+			continue
+		}
+		t := time.Unix(0, ts).UTC()
+		timestamps[i] = &t
+	}
+	return timestamps
+}
+
+// maxImportTimestampErrors is the number of offsets of invalid timestamps
+// listed in an import error.
+const maxImportTimestampErrors = 10
+
+// validateImportTimestamps returns an error listing the offsets of the
+// timestamps before the epoch. Zero means the bit has no timestamp.
+func validateImportTimestamps(a []int64) error {
+	var offsets []int
+	var n int
+	for i, ts := range a {
+		if ts >= 0 {
+			continue
+		}
+		if n < maxImportTimestampErrors {
+			offsets = append(offsets, i)
+		}
+		n++
+	}
+	switch {
+	case n == 0:
+		return nil
+	case n > len(offsets):
+		return errors.Errorf("timestamps before the epoch at offsets %v and %d more", offsets, n-len(offsets))
+	default:
+		return errors.Errorf("timestamps before the epoch at offsets %v", offsets)
 	}
-	return errors.Wrap(err, "importing")
+}
+
+// timestampAt returns the i-th timestamp, or zero if timestamps were omitted.
+func timestampAt(a []int64, i int) int64 {
+	if i < len(a) {
+		return a[i]
+	}
+	return 0
 }
 
 // ImportValue bulk imports values into a particular field.
@@ -956,7 +2221,7 @@ func (api *API) ImportValue(ctx context.Context, req *ImportValueRequest, opts .
 			m := make(map[uint64][]FieldValue)
 
 			for i, colID := range req.ColumnIDs {
-				shard := colID / ShardWidth
+				shard := colID / index.ShardWidth()
 				if _, ok := m[shard]; !ok {
 					m[shard] = make([]FieldValue, 0)
 				}
@@ -1010,7 +2275,8 @@ func importExistenceColumns(index *Index, columnIDs []uint64) error {
 	}
 
 	existenceRowIDs := make([]uint64, len(columnIDs))
-	return ef.Import(existenceRowIDs, columnIDs, nil)
+	_, err := ef.Import(existenceRowIDs, columnIDs, nil)
+	return err
 }
 
 // MaxShards returns the maximum shard number for each index in a map.
@@ -1027,6 +2293,41 @@ func (api *API) MaxShards(ctx context.Context) map[string]uint64 {
 	return m
 }
 
+// MaxIDs returns the highest column ID ever set in an index on this node and
+// the highest row ID ever set in each of its fields, other than int fields.
+func (api *API) MaxIDs(ctx context.Context, indexName string) (*MaxIDs, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.MaxIDs")
+	defer span.Finish()
+
+	if err := api.validate(apiMaxIDs); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+
+	ids := &MaxIDs{
+		MaxColumnID: index.MaxColumnID(),
+		MaxRowIDs:   make(map[string]uint64),
+	}
+	for _, f := range index.Fields() {
+		if strings.HasPrefix(f.Name(), "_") || f.Type() == FieldTypeInt {
+			continue
+		}
+		ids.MaxRowIDs[f.Name()] = f.MaxRowID()
+	}
+	return ids, nil
+}
+
+// MaxIDs holds the highest IDs ever set in an index and its fields. IDs
+// cleared since then are still included, so they only ever increase.
+type MaxIDs struct {
+	MaxColumnID uint64            `json:"maxColumnID"`
+	MaxRowIDs   map[string]uint64 `json:"maxRowIDs"`
+}
+
 // AvailableShardsByIndex returns bitmaps of shards with available by index name.
 func (api *API) AvailableShardsByIndex(ctx context.Context) map[string]*roaring.Bitmap {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.AvailableShardsByIndex")
@@ -1043,6 +2344,173 @@ func (api *API) StatsWithTags(tags []string) stats.StatsClient {
 	return api.holder.Stats.WithTags(tags...)
 }
 
+// ExpiredViews returns the local time views which are older than the
+// retention of their field and would be deleted by the next retention pass.
+func (api *API) ExpiredViews(ctx context.Context) ([]ExpiredView, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.ExpiredViews")
+	defer span.Finish()
+
+	if err := api.validate(apiExpiredViews); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	views := api.holder.expiredViews(time.Now())
+	if views == nil {
+		views = []ExpiredView{}
+	}
+	return views, nil
+}
+
+// HolderStats calls fn with the statistics of each view and its fragments on
+// this node, limited to an index or a field of it if given, and returns their
+// totals.
+func (api *API) HolderStats(ctx context.Context, indexName, fieldName string, fn func(*ViewStats) error) (*HolderStatsTotals, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.HolderStats")
+	defer span.Finish()
+
+	if err := api.validate(apiHolderStats); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	return api.holder.walkStats(ctx, indexName, fieldName, fn)
+}
+
+// StartTimeMigration starts building the views of the units in to of a time
+// field from its views of unit from on this node. An empty to builds every
+// unit of the field's quantum which is larger than from.
+func (api *API) StartTimeMigration(ctx context.Context, indexName, fieldName string, from, to TimeQuantum) (*TimeMigration, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.StartTimeMigration")
+	defer span.Finish()
+
+	if err := api.validate(apiStartTimeMigration); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	f := api.holder.Field(indexName, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	} else if f.Type() != FieldTypeTime {
+		return nil, NewBadRequestError(errors.Errorf("time views can only be migrated on a time field: %s", fieldName))
+	}
+
+	m, err := api.holder.StartTimeMigration(indexName, fieldName, from, to)
+	if err == ErrFieldNotFound {
+		return nil, newNotFoundError(err)
+	} else if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+	return m, nil
+}
+
+// TimeMigration returns the progress of the latest time migration of a field
+// on this node.
+func (api *API) TimeMigration(ctx context.Context, indexName, fieldName string) (*TimeMigration, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.TimeMigration")
+	defer span.Finish()
+
+	if err := api.validate(apiTimeMigration); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	if api.holder.Field(indexName, fieldName) == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	}
+	m := api.holder.TimeMigration(indexName, fieldName)
+	if m == nil {
+		return nil, newNotFoundError(ErrTimeMigrationNotFound)
+	}
+	return m, nil
+}
+
+// CopyField creates the destination field of req with the options of its
+// source, as overridden by req.Options, and starts copying the data of the
+// source on this node into it. The destination can't be used until
+// FinishFieldCopy is called.
+func (api *API) CopyField(ctx context.Context, req FieldCopyRequest) (*FieldCopy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.CopyField")
+	defer span.Finish()
+
+	if err := api.validate(apiCopyField); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	c, err := api.holder.StartFieldCopy(req)
+	switch err.(type) {
+	case nil:
+		return c, nil
+	case BadRequestError, ConflictError:
+		return nil, err
+	}
+	if err == ErrIndexNotFound || err == ErrFieldNotFound {
+		return nil, newNotFoundError(err)
+	}
+	return nil, NewBadRequestError(err)
+}
+
+// FieldCopy returns the progress of the copy into a field on this node.
+func (api *API) FieldCopy(ctx context.Context, indexName, fieldName string) (*FieldCopy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FieldCopy")
+	defer span.Finish()
+
+	if err := api.validate(apiFieldCopy); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	if api.holder.Field(indexName, fieldName) == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	}
+	c := api.holder.FieldCopy(indexName, fieldName)
+	if c == nil {
+		return nil, newNotFoundError(ErrFieldCopyNotFound)
+	}
+	return c, nil
+}
+
+// FieldCopyByID returns the progress of the copy with the given ID on this
+// node.
+func (api *API) FieldCopyByID(ctx context.Context, id string) (*FieldCopy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FieldCopyByID")
+	defer span.Finish()
+
+	if err := api.validate(apiFieldCopy); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	c := api.holder.FieldCopyByID(id)
+	if c == nil {
+		return nil, newNotFoundError(ErrFieldCopyNotFound)
+	}
+	return c, nil
+}
+
+// FinishFieldCopy marks the destination of a copy which has finished on this
+// node ready to be used. It should only be called once the copy has finished
+// on every node.
+func (api *API) FinishFieldCopy(ctx context.Context, indexName, fieldName string) (*FieldCopy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.FinishFieldCopy")
+	defer span.Finish()
+
+	if err := api.validate(apiFinishFieldCopy); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	c, err := api.holder.FinishFieldCopy(indexName, fieldName)
+	switch err {
+	case nil:
+		return c, nil
+	case ErrFieldNotFound, ErrFieldCopyNotFound:
+		return nil, newNotFoundError(err)
+	default:
+		return nil, NewBadRequestError(err)
+	}
+}
+
+// PendingCacheRebuilds returns the number of fragments on this node whose
+// caches are waiting to be rebuilt after an import.
+func (api *API) PendingCacheRebuilds() uint64 {
+	return uint64(api.holder.PendingCacheRebuilds())
+}
+
 // LongQueryTime returns the configured threshold for logging/statting
 // long running queries.
 func (api *API) LongQueryTime() time.Duration {
@@ -1076,6 +2544,8 @@ func (api *API) indexField(indexName string, fieldName string, shard uint64) (*I
 	if field == nil {
 		api.server.logger.Printf("field error: index=%s, field=%s, shard=%d, err=%s", indexName, fieldName, shard, ErrFieldNotFound.Error())
 		return nil, nil, ErrFieldNotFound
+	} else if api.holder.fieldCopying(indexName, fieldName) {
+		return nil, nil, newConflictError(ErrFieldCopying)
 	}
 	return index, field, nil
 }
@@ -1137,6 +2607,26 @@ func (api *API) RemoveNode(id string) (*Node, error) {
 	return removeNode, nil
 }
 
+// RemoveNodeWithHandoff starts removing the given node from the cluster
+// while it keeps serving queries, or resumes removing it. The nodes which own
+// its shards once it's removed pull them from it, after which it's dropped
+// from the cluster.
+func (api *API) RemoveNodeWithHandoff(id string) (*NodeRemoval, error) {
+	if err := api.validate(apiRemoveNodeWithHandoff); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+	return api.cluster.removeNodeWithHandoff(id)
+}
+
+// NodeRemoval returns the progress of the current or last removal of a node
+// with a handoff.
+func (api *API) NodeRemoval() (*NodeRemoval, error) {
+	if err := api.validate(apiNodeRemoval); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+	return api.cluster.nodeRemoval()
+}
+
 // ResizeAbort stops the current resize job.
 func (api *API) ResizeAbort() error {
 	if err := api.validate(apiResizeAbort); err != nil {
@@ -1170,12 +2660,25 @@ func (api *API) State() string {
 	return api.cluster.State()
 }
 
+// ReadOnly returns true if the server was started read-only.
+func (api *API) ReadOnly() bool {
+	return api.holder.ReadOnly
+}
+
+// SchemaLimits returns the limits on the number of indexes, fields, and views
+// on this node along with the current counts.
+func (api *API) SchemaLimits() SchemaLimits {
+	return api.holder.SchemaLimits()
+}
+
 // Version returns the Pilosa version.
 func (api *API) Version() string {
 	return strings.TrimPrefix(Version, "v")
 }
 
-// Info returns information about this server instance
+// Info returns information about this server instance. Its shard width is
+// the width of indexes created without one; the width of each index is in
+// the schema.
 func (api *API) Info() serverInfo {
 	return serverInfo{
 		ShardWidth: ShardWidth,
@@ -1213,6 +2716,7 @@ func (api *API) TranslateKeys(body io.Reader) ([]byte, error) {
 }
 
 type serverInfo struct {
+	// Default shard width of indexes.
 	ShardWidth uint64 `json:"shardWidth"`
 }
 
@@ -1220,36 +2724,65 @@ type apiMethod int
 
 // API validation constants.
 const (
-	apiClusterMessage apiMethod = iota
+	apiApplySchema apiMethod = iota
+	apiClusterMessage
+	apiCompactFragment
+	apiCopyField
 	apiCreateField
 	apiCreateIndex
+	apiDeleteColumnRange
 	apiDeleteField
 	apiDeleteAvailableShard
+	apiDeleteFragment
 	apiDeleteIndex
 	apiDeleteView
+	apiExpiredViews
 	apiExportCSV
+	apiExportProto
 	apiFragmentBlockData
 	apiFragmentBlocks
+	apiFragmentChecksums
 	apiFragmentData
 	apiField
 	apiFieldAttrDiff
+	apiFieldCache
+	apiFieldCopy
+	apiFinishFieldCopy
+	apiHolderStats
 	//apiHosts // not implemented
 	apiImport
 	apiImportValue
 	apiIndex
+	apiIndexAttrBlockData
+	apiIndexAttrBlocks
 	apiIndexAttrDiff
+	apiInvalidateFieldCache
 	//apiLocalID // not implemented
 	//apiLongQueryTime // not implemented
 	//apiMaxShards // not implemented
+	apiMaxIDs
+	apiNodeRemoval
 	apiQuery
+	apiRebuildCaches
 	apiRecalculateCaches
 	apiRemoveNode
+	apiRemoveNodeWithHandoff
+	apiRenameField
+	apiRenameIndex
 	apiResizeAbort
+	apiRowAttrs
 	//apiSchema // not implemented
+	apiSearchColumnAttrs
 	apiSetCoordinator
+	apiSetFieldCacheSize
+	apiSetFieldTimeQuantum
+	apiSetIndexAttrBlockData
+	apiSetIndexTimeQuantum
 	apiShardNodes
+	apiStartTimeMigration
 	//apiState // not implemented
 	//apiStatsWithTags // not implemented
+	apiTimeMigration
 	//apiVersion // not implemented
 	apiViews
 )
@@ -1264,25 +2797,81 @@ var methodsResizing = map[apiMethod]struct{}{
 	apiResizeAbort:  {},
 }
 
+// methodsWrite are the methods which modify the data directory, and so
+// aren't allowed when the holder is read-only.
+var methodsWrite = map[apiMethod]struct{}{
+	apiApplySchema:           {},
+	apiCompactFragment:       {},
+	apiCopyField:             {},
+	apiCreateField:           {},
+	apiCreateIndex:           {},
+	apiDeleteColumnRange:     {},
+	apiDeleteAvailableShard:  {},
+	apiDeleteField:           {},
+	apiDeleteFragment:        {},
+	apiDeleteIndex:           {},
+	apiDeleteView:            {},
+	apiFinishFieldCopy:       {},
+	apiImport:                {},
+	apiImportValue:           {},
+	apiRenameField:           {},
+	apiRenameIndex:           {},
+	apiSetFieldCacheSize:     {},
+	apiSetFieldTimeQuantum:   {},
+	apiSetIndexAttrBlockData: {},
+	apiSetIndexTimeQuantum:   {},
+	apiStartTimeMigration:    {},
+}
+
 var methodsNormal = map[apiMethod]struct{}{
-	apiCreateField:          {},
-	apiCreateIndex:          {},
-	apiDeleteField:          {},
-	apiDeleteAvailableShard: {},
-	apiDeleteIndex:          {},
-	apiDeleteView:           {},
-	apiExportCSV:            {},
-	apiFragmentBlockData:    {},
-	apiFragmentBlocks:       {},
-	apiField:                {},
-	apiFieldAttrDiff:        {},
-	apiImport:               {},
-	apiImportValue:          {},
-	apiIndex:                {},
-	apiIndexAttrDiff:        {},
-	apiQuery:                {},
-	apiRecalculateCaches:    {},
-	apiRemoveNode:           {},
-	apiShardNodes:           {},
-	apiViews:                {},
+	apiApplySchema:           {},
+	apiCompactFragment:       {},
+	apiCopyField:             {},
+	apiCreateField:           {},
+	apiCreateIndex:           {},
+	apiDeleteColumnRange:     {},
+	apiDeleteField:           {},
+	apiDeleteAvailableShard:  {},
+	apiDeleteFragment:        {},
+	apiDeleteIndex:           {},
+	apiDeleteView:            {},
+	apiExpiredViews:          {},
+	apiExportCSV:             {},
+	apiExportProto:           {},
+	apiFragmentBlockData:     {},
+	apiFragmentBlocks:        {},
+	apiFragmentChecksums:     {},
+	apiFragmentData:          {},
+	apiField:                 {},
+	apiFieldAttrDiff:         {},
+	apiFieldCache:            {},
+	apiFieldCopy:             {},
+	apiFinishFieldCopy:       {},
+	apiHolderStats:           {},
+	apiImport:                {},
+	apiImportValue:           {},
+	apiIndex:                 {},
+	apiIndexAttrBlockData:    {},
+	apiIndexAttrBlocks:       {},
+	apiIndexAttrDiff:         {},
+	apiInvalidateFieldCache:  {},
+	apiMaxIDs:                {},
+	apiNodeRemoval:           {},
+	apiQuery:                 {},
+	apiRebuildCaches:         {},
+	apiRecalculateCaches:     {},
+	apiRemoveNode:            {},
+	apiRemoveNodeWithHandoff: {},
+	apiRenameField:           {},
+	apiRenameIndex:           {},
+	apiRowAttrs:              {},
+	apiSearchColumnAttrs:     {},
+	apiSetFieldCacheSize:     {},
+	apiSetFieldTimeQuantum:   {},
+	apiSetIndexAttrBlockData: {},
+	apiSetIndexTimeQuantum:   {},
+	apiShardNodes:            {},
+	apiStartTimeMigration:    {},
+	apiTimeMigration:         {},
+	apiViews:                 {},
 }
@@ -0,0 +1,164 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Ensure block data read while idle fragments are closed is either the
+// fragment's full data or an error, and never the empty data of a closed
+// fragment.
+func TestAPI_FragmentBlockData_CloseIdleFragments(t *testing.T) {
+	h := newHolder()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	const bitN = 1000
+	f := h.MustCreateFieldIfNotExists("i", "f")
+	rowIDs, columnIDs := make([]uint64, bitN), make([]uint64, bitN)
+	for i := range columnIDs {
+		rowIDs[i], columnIDs[i] = 1, uint64(i)
+	}
+	if _, err := f.Import(rowIDs, columnIDs, nil); err != nil {
+		t.Fatal(err)
+	}
+	v := f.view(viewStandard)
+
+	// The closer must run alongside the reads to close a fragment part way
+	// through one.
+	prev := runtime.GOMAXPROCS(4)
+	defer runtime.GOMAXPROCS(prev)
+
+	s := &blockDataSerializer{req: BlockDataRequest{Index: "i", Field: "f", View: viewStandard}}
+	api := &API{holder: h.Holder, cluster: NewTestCluster(1), Serializer: s}
+
+	done := make(chan struct{})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			v.closeIdleFragments(time.Nanosecond, 0)
+		}
+	}()
+	defer func() {
+		close(done)
+		<-closed
+	}()
+
+	for i := 0; i < 2000; i++ {
+		if _, err := api.FragmentBlockData(context.Background(), &bytes.Buffer{}); err != nil {
+			continue
+		} else if n := len(s.resp.RowIDs); n != bitN || len(s.resp.ColumnIDs) != bitN || s.resp.More {
+			t.Fatalf("unexpected block data: %d rows, %d columns, more=%v", n, len(s.resp.ColumnIDs), s.resp.More)
+		}
+
+		if blocks, err := api.FragmentBlocks(context.Background(), "i", "f", viewStandard, 0); err != nil {
+			continue
+		} else if len(blocks) != 1 {
+			t.Fatalf("unexpected blocks: %+v", blocks)
+		}
+	}
+}
+
+// Ensure exports run while idle fragments are closed hold the fragment open,
+// and write every bit of it rather than fail or write the empty data of a
+// closed fragment.
+func TestAPI_Export_CloseIdleFragments(t *testing.T) {
+	h := newHolder()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	const rowN, colN = 50, 20
+	f := h.MustCreateFieldIfNotExists("i", "f")
+	var rowIDs, columnIDs []uint64
+	for row := uint64(0); row < rowN; row++ {
+		for col := uint64(0); col < colN; col++ {
+			rowIDs, columnIDs = append(rowIDs, row), append(columnIDs, col)
+		}
+	}
+	if _, err := f.Import(rowIDs, columnIDs, nil); err != nil {
+		t.Fatal(err)
+	}
+	v := f.view(viewStandard)
+	exportRows := make([]uint64, rowN)
+	for i := range exportRows {
+		exportRows[i] = uint64(i)
+	}
+
+	prev := runtime.GOMAXPROCS(4)
+	defer runtime.GOMAXPROCS(prev)
+
+	c := NewTestCluster(1)
+	api := &API{holder: h.Holder, cluster: c, server: &Server{cluster: c}}
+
+	done := make(chan struct{})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			v.closeIdleFragments(time.Nanosecond, 0)
+		}
+	}()
+	defer func() {
+		close(done)
+		<-closed
+	}()
+
+	for i := 0; i < 500; i++ {
+		var buf bytes.Buffer
+		if err := api.ExportCSV(context.Background(), "i", "f", 0, &buf, OptExportOptionsRowIDs(exportRows)); err != nil {
+			t.Fatal(err)
+		} else if n := strings.Count(buf.String(), "\n"); n != rowN*colN {
+			t.Fatalf("unexpected exported bits: %d", n)
+		}
+	}
+}
+
+// blockDataSerializer is a Serializer which decodes every message as a
+// fixed block data request, and keeps the last block data response encoded.
+type blockDataSerializer struct {
+	req  BlockDataRequest
+	resp BlockDataResponse
+}
+
+func (s *blockDataSerializer) Marshal(m Message) ([]byte, error) {
+	s.resp = *m.(*BlockDataResponse)
+	return nil, nil
+}
+
+func (s *blockDataSerializer) Unmarshal(_ []byte, m Message) error {
+	*m.(*BlockDataRequest) = s.req
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pilosa/pilosa"
 	"github.com/pilosa/pilosa/server"
 	"github.com/pilosa/pilosa/test"
+	"github.com/pkg/errors"
 )
 
 func TestAPI_Import(t *testing.T) {
@@ -81,7 +82,7 @@ func TestAPI_Import(t *testing.T) {
 			ColumnKeys: colKeys,
 			Timestamps: timestamps,
 		}
-		if err := m0.API.Import(ctx, req); err != nil {
+		if _, err := m0.API.Import(ctx, req); err != nil {
 			t.Fatal(err)
 		}
 
@@ -149,7 +150,7 @@ func TestAPI_Import(t *testing.T) {
 			ColumnIDs:  colIDs,
 			Timestamps: timestamps,
 		}
-		if err := m0.API.Import(ctx, req); err != nil {
+		if _, err := m0.API.Import(ctx, req); err != nil {
 			t.Fatal(err)
 		}
 
@@ -169,6 +170,113 @@ func TestAPI_Import(t *testing.T) {
 			t.Fatalf("unexpected column ids: %+v", columns)
 		}
 	})
+
+	t.Run("RowKeyNoTimestamps", func(t *testing.T) {
+		ctx := context.Background()
+		index := "rknt"
+		field := "f"
+
+		_, err := m0.API.CreateIndex(ctx, index, pilosa.IndexOptions{})
+		if err != nil {
+			t.Fatalf("creating index: %v", err)
+		}
+		_, err = m0.API.CreateField(ctx, index, field, pilosa.OptFieldTypeSet(pilosa.DefaultCacheType, 100), pilosa.OptFieldKeys())
+		if err != nil {
+			t.Fatalf("creating field: %v", err)
+		}
+
+		// Timestamps may be omitted entirely from a keyed import.
+		colIDs := []uint64{1, 2}
+		req := &pilosa.ImportRequest{
+			Index:     index,
+			Field:     field,
+			RowKeys:   []string{"a", "a"},
+			ColumnIDs: colIDs,
+		}
+		if _, err := m0.API.Import(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+
+		if res, err := m0.API.Query(ctx, &pilosa.QueryRequest{Index: index, Query: fmt.Sprintf("Row(%s=a)", field)}); err != nil {
+			t.Fatal(err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, colIDs) {
+			t.Fatalf("unexpected column ids: %+v", columns)
+		}
+	})
+
+	t.Run("Timestamps", func(t *testing.T) {
+		ctx := context.Background()
+		index := "ts"
+		field := "f"
+
+		_, err := m0.API.CreateIndex(ctx, index, pilosa.IndexOptions{})
+		if err != nil {
+			t.Fatalf("creating index: %v", err)
+		}
+		_, err = m0.API.CreateField(ctx, index, field, pilosa.OptFieldTypeTime(pilosa.TimeQuantum("YMD")))
+		if err != nil {
+			t.Fatalf("creating field: %v", err)
+		}
+
+		// Import a mixed batch to the owner of shard 0 (node1). The bit
+		// without a timestamp is only written to the standard view.
+		req := &pilosa.ImportRequest{
+			Index:      index,
+			Field:      field,
+			Shard:      0,
+			RowIDs:     []uint64{1, 1, 1},
+			ColumnIDs:  []uint64{1, 2, 3},
+			Timestamps: []int64{1514764800000000000, 0, 1514851200000000000}, // 2018-01-01T00:00, none, 2018-01-02T00:00
+		}
+		if _, err := m1.API.Import(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+
+		if counts, err := m1.API.ImportViewCounts(ctx, req); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(counts, map[string]uint64{
+			"standard":          3,
+			"standard_2018":     2,
+			"standard_201801":   2,
+			"standard_20180101": 1,
+			"standard_20180102": 1,
+		}) {
+			t.Fatalf("unexpected view counts: %v", counts)
+		}
+
+		for pql, exp := range map[string][]uint64{
+			"Row(f=1)": {1, 2, 3},
+			"Row(f=1, from=2018-01-01T00:00, to=2019-01-01T00:00)": {1, 3},
+			"Row(f=1, from=2018-01-02T00:00, to=2018-01-03T00:00)": {3},
+		} {
+			if res, err := m0.API.Query(ctx, &pilosa.QueryRequest{Index: index, Query: pql}); err != nil {
+				t.Fatal(err)
+			} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, exp) {
+				t.Fatalf("%s: unexpected column ids: %+v", pql, columns)
+			}
+		}
+
+		// Nothing is imported if a timestamp precedes the epoch.
+		req = &pilosa.ImportRequest{
+			Index:      index,
+			Field:      field,
+			Shard:      0,
+			RowIDs:     []uint64{2, 2, 2},
+			ColumnIDs:  []uint64{1, 2, 3},
+			Timestamps: []int64{-1, 1514764800000000000, -86400000000000}, // 1969-12-31T23:59, 2018-01-01T00:00, 1969-12-31T00:00
+		}
+		_, err = m1.API.Import(ctx, req)
+		if _, ok := errors.Cause(err).(pilosa.BadRequestError); !ok {
+			t.Fatalf("expected bad request, got %v", err)
+		} else if !strings.Contains(err.Error(), "offsets [0 2]") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res, err := m0.API.Query(ctx, &pilosa.QueryRequest{Index: index, Query: "Row(f=2)"}); err != nil {
+			t.Fatal(err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); len(columns) != 0 {
+			t.Fatalf("unexpected column ids: %+v", columns)
+		}
+	})
 }
 
 func TestAPI_ImportValue(t *testing.T) {
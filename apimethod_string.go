@@ -4,9 +4,9 @@ package pilosa
 
 import "strconv"
 
-const _apiMethod_name = "apiClusterMessageapiCreateFieldapiCreateIndexapiDeleteFieldapiDeleteAvailableShardapiDeleteIndexapiDeleteViewapiExportCSVapiFragmentBlockDataapiFragmentBlocksapiFieldapiFieldAttrDiffapiImportapiImportValueapiIndexapiIndexAttrDiffapiQueryapiRecalculateCachesapiRemoveNodeapiResizeAbortapiSetCoordinatorapiShardNodesapiViews"
+const _apiMethod_name = "apiApplySchemaapiClusterMessageapiCompactFragmentapiCopyFieldapiCreateFieldapiCreateIndexapiDeleteColumnRangeapiDeleteFieldapiDeleteAvailableShardapiDeleteFragmentapiDeleteIndexapiDeleteViewapiExpiredViewsapiExportCSVapiExportProtoapiFragmentBlockDataapiFragmentBlocksapiFragmentChecksumsapiFragmentDataapiFieldapiFieldAttrDiffapiFieldCacheapiFieldCopyapiFinishFieldCopyapiHolderStatsapiImportapiImportValueapiIndexapiIndexAttrBlockDataapiIndexAttrBlocksapiIndexAttrDiffapiInvalidateFieldCacheapiMaxIDsapiNodeRemovalapiQueryapiRebuildCachesapiRecalculateCachesapiRemoveNodeapiRemoveNodeWithHandoffapiRenameFieldapiRenameIndexapiResizeAbortapiRowAttrsapiSearchColumnAttrsapiSetCoordinatorapiSetFieldCacheSizeapiSetFieldTimeQuantumapiSetIndexAttrBlockDataapiSetIndexTimeQuantumapiShardNodesapiStartTimeMigrationapiTimeMigrationapiViews"
 
-var _apiMethod_index = [...]uint16{0, 17, 31, 45, 59, 82, 96, 109, 121, 141, 158, 166, 182, 191, 205, 213, 229, 237, 257, 270, 284, 301, 314, 322}
+var _apiMethod_index = [...]uint16{0, 14, 31, 49, 61, 75, 89, 109, 123, 146, 163, 177, 190, 205, 217, 231, 251, 268, 288, 303, 311, 327, 340, 352, 370, 384, 393, 407, 415, 436, 454, 470, 493, 502, 516, 524, 540, 560, 573, 597, 611, 625, 639, 650, 670, 687, 707, 729, 753, 775, 788, 809, 825, 833}
 
 func (i apiMethod) String() string {
 	if i < 0 || i >= apiMethod(len(_apiMethod_index)-1) {
@@ -16,10 +16,14 @@ package pilosa
 
 import (
 	"bytes"
+	"encoding/json"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pilosa/pilosa/internal"
+	"github.com/pkg/errors"
 )
 
 // Attribute data type enum.
@@ -36,10 +40,119 @@ type AttrStore interface {
 	Open() error
 	Close() error
 	Attrs(id uint64) (m map[string]interface{}, err error)
+
+	// BlockAttrs returns the attributes of those ids which have any. The
+	// ids are sorted and grouped by block, and each block is read once.
+	BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error)
+
 	SetAttrs(id uint64, m map[string]interface{}) error
 	SetBulkAttrs(m map[uint64]map[string]interface{}) error
+
+	// DeleteRange deletes the attributes of every id in [min, max].
+	DeleteRange(min, max uint64) error
+
 	Blocks() ([]AttrBlock, error)
 	BlockData(i uint64) (map[uint64]map[string]interface{}, error)
+
+	// Find returns the attributes of the IDs whose value of attr matches,
+	// in ID order, up to limit IDs. Zero is unlimited. An error is returned
+	// if match panics.
+	Find(attr string, match func(value interface{}) bool, limit int) (map[uint64]map[string]interface{}, error)
+}
+
+// Attribute commit modes.
+const (
+	// AttrCommitAlways commits each write in its own transaction before it
+	// returns.
+	AttrCommitAlways = "always"
+
+	// AttrCommitBatched buffers writes and commits them together in the
+	// background. Buffered writes are read back from the store's cache.
+	AttrCommitBatched = "batched"
+)
+
+// Defaults of batched attribute commits.
+const (
+	DefaultAttrCommitInterval = 10 * time.Millisecond
+	DefaultAttrCommitMaxOps   = 1000
+)
+
+// AttrCommit determines when the writes to an attribute store are committed
+// to disk. The zero value commits every write.
+type AttrCommit struct {
+	Mode string
+
+	// Interval is the longest a batched write waits to be committed, and
+	// MaxOps the number of buffered writes which are committed without
+	// waiting for it. Zero is their default.
+	Interval time.Duration
+	MaxOps   int
+}
+
+// attrCommitJSON is the JSON representation of AttrCommit.
+type attrCommitJSON struct {
+	Mode     string `json:"mode"`
+	Interval string `json:"interval,omitempty"`
+	MaxOps   int    `json:"maxOps,omitempty"`
+}
+
+// IsZero returns true if every write is committed.
+func (c AttrCommit) IsZero() bool { return c == AttrCommit{} }
+
+// Batched returns true if writes are committed in batches.
+func (c AttrCommit) Batched() bool { return c.Mode == AttrCommitBatched }
+
+// validate returns an error if the mode is unknown, or if the batch limits
+// are negative or set without batching.
+func (c AttrCommit) validate() error {
+	switch c.Mode {
+	case "", AttrCommitAlways:
+		if c.Interval != 0 || c.MaxOps != 0 {
+			return errors.New("attrCommit interval and maxOps only apply to batched commits")
+		}
+	case AttrCommitBatched:
+		if c.Interval < 0 {
+			return errors.Errorf("invalid attrCommit interval: %s", c.Interval)
+		} else if c.MaxOps < 0 {
+			return errors.Errorf("invalid attrCommit maxOps: %d", c.MaxOps)
+		}
+	default:
+		return errors.Errorf("invalid attrCommit mode %q, must be always or batched", c.Mode)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the interval as a string, e.g. "10ms".
+func (c AttrCommit) MarshalJSON() ([]byte, error) {
+	o := attrCommitJSON{Mode: c.Mode, MaxOps: c.MaxOps}
+	if c.Interval != 0 {
+		o.Interval = c.Interval.String()
+	}
+	return json.Marshal(o)
+}
+
+// UnmarshalJSON decodes an interval such as "10ms".
+func (c *AttrCommit) UnmarshalJSON(data []byte) error {
+	var o attrCommitJSON
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	other := AttrCommit{Mode: o.Mode, MaxOps: o.MaxOps}
+	if o.Interval != "" {
+		v, err := time.ParseDuration(o.Interval)
+		if err != nil {
+			return errors.Wrap(err, "invalid attrCommit interval")
+		}
+		other.Interval = v
+	}
+	*c = other
+	return nil
+}
+
+// attrCommitter is implemented by attribute stores which can batch commits.
+// It may be called before or after the store is opened.
+type attrCommitter interface {
+	SetAttrCommit(c AttrCommit) error
 }
 
 // nopStore represents an AttrStore that doesn't do anything.
@@ -64,18 +177,42 @@ func (s nopAttrStore) Close() error { return nil }
 // Attrs is a no-op implementation of AttrStore Attrs method.
 func (s nopAttrStore) Attrs(id uint64) (m map[string]interface{}, err error) { return nil, nil }
 
+// BlockAttrs is a no-op implementation of AttrStore BlockAttrs method.
+func (s nopAttrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	return nil, nil
+}
+
 // SetAttrs is a no-op implementation of AttrStore SetAttrs method.
 func (s nopAttrStore) SetAttrs(id uint64, m map[string]interface{}) error { return nil }
 
 // SetBulkAttrs is a no-op implementation of AttrStore SetBulkAttrs method.
 func (s nopAttrStore) SetBulkAttrs(m map[uint64]map[string]interface{}) error { return nil }
 
+// DeleteRange is a no-op implementation of AttrStore DeleteRange method.
+func (s nopAttrStore) DeleteRange(min, max uint64) error { return nil }
+
 // Blocks is a no-op implementation of AttrStore Blocks method.
 func (s nopAttrStore) Blocks() ([]AttrBlock, error) { return nil, nil }
 
 // BlockData is a no-op implementation of AttrStore BlockData method.
 func (s nopAttrStore) BlockData(i uint64) (map[uint64]map[string]interface{}, error) { return nil, nil }
 
+// Find is a no-op implementation of AttrStore Find method.
+func (s nopAttrStore) Find(attr string, match func(value interface{}) bool, limit int) (map[uint64]map[string]interface{}, error) {
+	return nil, nil
+}
+
+// MatchAttrValue returns the result of match for value, or an error if match
+// panics.
+func MatchAttrValue(match func(value interface{}) bool, value interface{}) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("matching attr value %v: panic: %v", value, r)
+		}
+	}()
+	return match(value), nil
+}
+
 // AttrBlock represents a checksummed block of the attribute store.
 type AttrBlock struct {
 	ID       uint64 `json:"id"`
@@ -119,6 +256,111 @@ func (a attrBlocks) Diff(other []AttrBlock) []uint64 {
 	}
 }
 
+// MaxBulkRowAttrIDs is the most row IDs whose attributes can be read at once.
+const MaxBulkRowAttrIDs = 10000
+
+// Limits on the number of columns returned by a column attribute search.
+const (
+	DefaultColumnAttrSearchLimit = 100
+	MaxColumnAttrSearchLimit     = 1000
+)
+
+// ColumnAttrSearch is a search of the column attributes of an index by the
+// string value of one attribute. Either Like, which matches the values
+// starting with it regardless of case, or Equals, which matches the values
+// equal to it, is set.
+type ColumnAttrSearch struct {
+	Attr   string `json:"attr"`
+	Like   string `json:"like,omitempty"`
+	Equals string `json:"equals,omitempty"`
+
+	// Limit is the most columns returned. Zero returns up to
+	// DefaultColumnAttrSearchLimit columns.
+	Limit int `json:"limit,omitempty"`
+}
+
+// match returns the function matching the attribute values of the search.
+func (q *ColumnAttrSearch) match() (func(value interface{}) bool, error) {
+	switch {
+	case q.Attr == "":
+		return nil, errors.New("attr required")
+	case q.Like != "" && q.Equals != "":
+		return nil, errors.New("like and equals can't both be set")
+	case q.Like != "":
+		prefix := strings.ToLower(q.Like)
+		return func(value interface{}) bool {
+			s, ok := value.(string)
+			return ok && strings.HasPrefix(strings.ToLower(s), prefix)
+		}, nil
+	case q.Equals != "":
+		return func(value interface{}) bool {
+			s, ok := value.(string)
+			return ok && s == q.Equals
+		}, nil
+	default:
+		return nil, errors.New("like or equals required")
+	}
+}
+
+// ColumnAttrSearchResult is the columns found by a column attribute search,
+// in column order. Truncated is set if more columns matched than the limit.
+type ColumnAttrSearchResult struct {
+	ColumnAttrs []*ColumnAttrSet `json:"columnAttrs"`
+	Truncated   bool             `json:"truncated"`
+}
+
+// Limits on the number of entries in a page of an attribute block.
+const (
+	DefaultAttrBlockPageLimit = 100
+	MaxAttrBlockPageLimit     = 1000
+)
+
+// attrBlockPageBytes is the encoded size after which a page of an attribute
+// block ends early, so blocks with enormous attributes are split across
+// several pages. A page always holds at least one entry.
+const attrBlockPageBytes = 1 << 20
+
+// AttrBlockPage is a page of the entries of an attribute block.
+type AttrBlockPage struct {
+	Attrs map[uint64]map[string]interface{} `json:"attrs"`
+
+	// Next is the ID the next page starts at. It is unset on the last page.
+	Next *uint64 `json:"next,omitempty"`
+}
+
+// attrBlockPage returns the entries of block i of store with IDs of at least
+// start, up to limit entries.
+func attrBlockPage(store AttrStore, i, start uint64, limit int) (*AttrBlockPage, error) {
+	m, err := store.BlockData(i)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting block")
+	}
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		if id >= start {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	page := &AttrBlockPage{Attrs: make(map[uint64]map[string]interface{})}
+	var n int
+	for _, id := range ids {
+		buf, err := json.Marshal(m[id])
+		if err != nil {
+			return nil, errors.Wrap(err, "marshaling attrs")
+		}
+		if len(page.Attrs) == limit || (len(page.Attrs) > 0 && n+len(buf) > attrBlockPageBytes) {
+			next := id
+			page.Next = &next
+			break
+		}
+		page.Attrs[id] = m[id]
+		n += len(buf)
+	}
+	return page, nil
+}
+
 func encodeAttrs(m map[string]interface{}) []*internal.Attr {
 	keys := make([]string, 0, len(m))
 	for k := range m {
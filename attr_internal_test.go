@@ -0,0 +1,74 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// blockAttrStore is an attribute store with a single block.
+type blockAttrStore struct {
+	nopAttrStore
+	m map[uint64]map[string]interface{}
+}
+
+func (s blockAttrStore) BlockData(i uint64) (map[uint64]map[string]interface{}, error) {
+	return s.m, nil
+}
+
+// Ensure pages of a block end at the entry limit or the size limit, and
+// always hold at least one entry.
+func TestAttrBlockPage(t *testing.T) {
+	big := strings.Repeat("x", attrBlockPageBytes/2)
+	store := blockAttrStore{m: map[uint64]map[string]interface{}{
+		1: {"a": int64(1)},
+		2: {"a": int64(2)},
+		3: {"big": big},
+		4: {"big": big},
+		5: {"big": big + big},
+		6: {"a": int64(6)},
+	}}
+
+	var pages [][]uint64
+	var start uint64
+	for {
+		page, err := attrBlockPage(store, 0, start, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []uint64
+		for id := uint64(1); id <= 6; id++ {
+			if _, ok := page.Attrs[id]; ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) != len(page.Attrs) {
+			t.Fatalf("unexpected page: %v", page.Attrs)
+		}
+		pages = append(pages, ids)
+		if page.Next == nil {
+			break
+		}
+		start = *page.Next
+	}
+
+	// 1, 2 and 3 fill the entry limit, 4 and 5 would exceed the size limit
+	// together, and 5 exceeds it on its own.
+	if got, exp := fmt.Sprint(pages), "[[1 2 3] [4] [5] [6]]"; got != exp {
+		t.Fatalf("unexpected pages: %s, expected %s", got, exp)
+	}
+}
@@ -19,8 +19,11 @@ import (
 	"os"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pilosa/pilosa"
 	"github.com/pilosa/pilosa/boltdb"
@@ -87,6 +90,218 @@ func TestAttrStore_Attrs_Unset(t *testing.T) {
 	}
 }
 
+// Ensure each type of attribute value is encoded and decoded unchanged.
+func TestAttrs_EncodeDecode(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"String", "foo"},
+		{"EmptyString", ""},
+		{"Null", nil},
+		{"Int", int64(-1 << 40)},
+		{"True", true},
+		{"False", false},
+		{"Float", 0.30000000000000004},
+		{"FloatTiny", 5e-324},
+		{"FloatWhole", float64(3)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := pilosa.EncodeAttrs(map[string]interface{}{"k": tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := pilosa.DecodeAttrs(buf)
+			if err != nil {
+				t.Fatal(err)
+			} else if v, ok := m["k"]; !ok {
+				t.Fatalf("key not decoded: %#v", m)
+			} else if !reflect.DeepEqual(v, tt.value) {
+				t.Fatalf("unexpected value: %#v (%[1]T), expected %#v (%[2]T)", v, tt.value)
+			}
+		})
+	}
+}
+
+// Ensure an attribute set to an empty string is kept, while one set to nil
+// is removed, in bulk as well.
+func TestAttrStore_SetBulkAttrs_Types(t *testing.T) {
+	s := MustOpenAttrStore()
+	defer s.Close()
+
+	if err := s.SetBulkAttrs(map[uint64]map[string]interface{}{
+		1: {"s": "x", "e": "x", "b": true, "f": 1.25},
+		2: {"f": 1e100},
+	}); err != nil {
+		t.Fatal(err)
+	} else if err := s.SetBulkAttrs(map[uint64]map[string]interface{}{
+		1: {"s": nil, "e": "", "b": false},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if m, err := s.Attrs(1); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[string]interface{}{"e": "", "b": false, "f": 1.25}) {
+		t.Fatalf("unexpected attrs(1): %#v", m)
+	}
+	if m, err := s.Attrs(2); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[string]interface{}{"f": 1e100}) {
+		t.Fatalf("unexpected attrs(2): %#v", m)
+	}
+}
+
+// Ensure the attributes of many IDs can be read at once, in any order and
+// whether cached or not, with IDs without attributes left out.
+func TestAttrStore_BlockAttrs(t *testing.T) {
+	s := MustOpenAttrStore()
+	defer s.Close()
+
+	if err := s.SetAttrs(1, map[string]interface{}{"A": "X"}); err != nil {
+		t.Fatal(err)
+	} else if err := s.SetBulkAttrs(map[uint64]map[string]interface{}{2: {"A": int64(10)}, 300: {"B": true}}); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[uint64]map[string]interface{}{
+		1:   {"A": "X"},
+		2:   {"A": int64(10)},
+		300: {"B": true},
+	}
+
+	// Read twice, the second time from the cache.
+	for i := 0; i < 2; i++ {
+		if m, err := s.BlockAttrs([]uint64{300, 3, 1, 2, 300, 1000, 99}); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(m, exp) {
+			t.Fatalf("unexpected attrs (read %d): %#v", i, m)
+		}
+	}
+}
+
+// Ensure the attributes of a range of IDs can be deleted, including those
+// already cached.
+func TestAttrStore_DeleteRange(t *testing.T) {
+	s := MustOpenAttrStore()
+	defer s.Close()
+
+	if err := s.SetBulkAttrs(map[uint64]map[string]interface{}{
+		1:   {"A": "X"},
+		2:   {"A": int64(10)},
+		150: {"B": true},
+		300: {"B": false},
+	}); err != nil {
+		t.Fatal(err)
+	} else if _, err := s.Attrs(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteRange(2, 150); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := s.BlockAttrs([]uint64{1, 2, 150, 300}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[uint64]map[string]interface{}{1: {"A": "X"}, 300: {"B": false}}) {
+		t.Fatalf("unexpected attrs: %#v", m)
+	}
+	if m, err := s.Attrs(2); err != nil {
+		t.Fatal(err)
+	} else if len(m) != 0 {
+		t.Fatalf("unexpected attrs: %#v", m)
+	}
+}
+
+// Ensure batched writes are read back before they're committed, and are
+// committed before blocks are read and when the store is closed.
+func TestAttrStore_Batched(t *testing.T) {
+	s := MustOpenAttrStoreCommit(pilosa.AttrCommit{Mode: pilosa.AttrCommitBatched, Interval: time.Hour})
+	defer s.Close()
+
+	if err := s.SetAttrs(1, map[string]interface{}{"A": 100, "B": "x"}); err != nil {
+		t.Fatal(err)
+	} else if err := s.SetBulkAttrs(map[uint64]map[string]interface{}{1: {"B": nil, "C": true}, 2: {"A": int64(200)}}); err != nil {
+		t.Fatal(err)
+	} else if err := s.SetAttrs(150, map[string]interface{}{"A": 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := s.Attrs(1); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[string]interface{}{"A": int64(100), "C": true}) {
+		t.Fatalf("unexpected attrs(1): %#v", m)
+	}
+
+	// Buffered writes to a deleted range are dropped.
+	if err := s.DeleteRange(100, 200); err != nil {
+		t.Fatal(err)
+	} else if m, err := s.BlockData(0); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[uint64]map[string]interface{}{1: {"A": int64(100), "C": true}, 2: {"A": int64(200)}}) {
+		t.Fatalf("unexpected block: %#v", m)
+	}
+
+	if err := s.SetAttrs(2, map[string]interface{}{"A": int64(300)}); err != nil {
+		t.Fatal(err)
+	} else if err := s.AttrStore.Close(); err != nil {
+		t.Fatal(err)
+	}
+	other := boltdb.NewAttrStore(s.Path())
+	if err := other.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if m, err := other.BlockAttrs([]uint64{1, 2, 150}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[uint64]map[string]interface{}{1: {"A": int64(100), "C": true}, 2: {"A": int64(300)}}) {
+		t.Fatalf("unexpected attrs after reopening: %#v", m)
+	}
+}
+
+// Ensure the IDs whose attribute matches can be found, in ID order and up to
+// a limit, and that a panicking match is returned as an error.
+func TestAttrStore_Find(t *testing.T) {
+	s := MustOpenAttrStore()
+	defer s.Close()
+
+	if err := s.SetBulkAttrs(map[uint64]map[string]interface{}{
+		1:   {"name": "alpha", "n": int64(1)},
+		2:   {"name": "beta"},
+		150: {"name": "alps"},
+		300: {"other": "alpha"},
+		301: {"name": int64(7)},
+		450: {"name": "alpine"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	prefix := func(value interface{}) bool {
+		s, ok := value.(string)
+		return ok && strings.HasPrefix(s, "al")
+	}
+
+	if m, err := s.Find("name", prefix, 0); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[uint64]map[string]interface{}{
+		1:   {"name": "alpha", "n": int64(1)},
+		150: {"name": "alps"},
+		450: {"name": "alpine"},
+	}) {
+		t.Fatalf("unexpected attrs: %#v", m)
+	}
+
+	// The limit keeps the lowest IDs.
+	if m, err := s.Find("name", prefix, 2); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[uint64]map[string]interface{}{
+		1:   {"name": "alpha", "n": int64(1)},
+		150: {"name": "alps"},
+	}) {
+		t.Fatalf("unexpected attrs: %#v", m)
+	}
+
+	if _, err := s.Find("name", func(value interface{}) bool { return value.(string) != "" }, 0); err == nil || !strings.Contains(err.Error(), "panic") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 // Ensure attribute block checksums can be returned.
 func TestAttrStore_Blocks(t *testing.T) {
 	s := MustOpenAttrStore()
@@ -185,6 +400,38 @@ func BenchmarkAttrStore_Duplicate(b *testing.B) {
 	}
 }
 
+// Benchmark concurrent writes of attributes which commit every write against
+// those which are committed in batches.
+func BenchmarkAttrStore_SetAttrs(b *testing.B) {
+	for _, c := range []pilosa.AttrCommit{
+		{Mode: pilosa.AttrCommitAlways},
+		{Mode: pilosa.AttrCommitBatched},
+	} {
+		b.Run(c.Mode, func(b *testing.B) {
+			s := MustOpenAttrStoreCommit(c)
+			defer s.Close()
+
+			var id uint64
+			b.ReportAllocs()
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := s.SetAttrs(atomic.AddUint64(&id, 1), map[string]interface{}{"A": int64(100), "B": "foo"}); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+
+			// Include the last batch.
+			if err := s.AttrStore.Close(); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
+
 // MustOpenAttrStore returns a new, opened attribute store at a temporary path. Panic on error.
 func MustOpenAttrStore() pilosa.AttrStore {
 	s := NewAttrStore("")
@@ -194,6 +441,20 @@ func MustOpenAttrStore() pilosa.AttrStore {
 	return s
 }
 
+// MustOpenAttrStoreCommit returns a new, opened attribute store at a
+// temporary path which commits its writes as c sets. Panic on error.
+func MustOpenAttrStoreCommit(c pilosa.AttrCommit) *AttrStore {
+	s := NewAttrStore("").(*AttrStore)
+	if err := s.AttrStore.(interface {
+		SetAttrCommit(pilosa.AttrCommit) error
+	}).SetAttrCommit(c); err != nil {
+		panic(err)
+	} else if err := s.Open(); err != nil {
+		panic(err)
+	}
+	return s
+}
+
 // Close closes the database and removes the underlying data.
 func (s *AttrStore) Close() error {
 	defer os.RemoveAll(s.Path())
@@ -19,8 +19,13 @@ import (
 
 	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"os"
 	"sort"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/cespare/xxhash"
@@ -33,6 +38,11 @@ import (
 // attrBlockSize is the size of attribute blocks for anti-entropy.
 const attrBlockSize = 100
 
+// attrFindTxTime is how long Find scans in a single transaction before
+// releasing the store and resuming from the next ID, so that a scan of a
+// large store doesn't hold off writes for its whole duration.
+const attrFindTxTime = 10 * time.Millisecond
+
 // attrCache represents a cache for attributes.
 type attrCache struct {
 	mu    sync.RWMutex
@@ -69,6 +79,32 @@ type attrStore struct {
 	path      string
 	db        *bolt.DB
 	attrCache *attrCache
+
+	// Skips syncing each transaction to disk.
+	noSync bool
+
+	// Opens the data file read-only, so every write fails.
+	readOnly bool
+
+	// Temporary copy of the data file opened instead of it while another
+	// process holds it for writing. Removed on close.
+	copyPath string
+
+	// When writes are committed. Batched writes are buffered in pending by
+	// ID, and the error of the last failed batch is returned to the next
+	// writer.
+	commit    pilosa.AttrCommit
+	pending   map[uint64]map[string]interface{}
+	commitErr error
+
+	// Serializes commits of the buffer, so batches are committed in order.
+	commitMu sync.Mutex
+
+	// Stops the goroutine committing batches, and signals it that the
+	// buffer is full.
+	closing chan struct{}
+	notify  chan struct{}
+	wg      sync.WaitGroup
 }
 
 // newAttrCache returns a new instance of AttrCache.
@@ -83,20 +119,71 @@ func NewAttrStore(path string) pilosa.AttrStore {
 	return &attrStore{
 		path:      path,
 		attrCache: newAttrCache(),
+		notify:    make(chan struct{}, 1),
 	}
 }
 
+// SetSync sets whether each write is synced to disk. It must be called
+// before the store is opened.
+func (s *attrStore) SetSync(sync bool) { s.noSync = !sync }
+
+// SetReadOnly sets whether the store's data file is opened read-only. It must
+// be called before the store is opened.
+func (s *attrStore) SetReadOnly(readOnly bool) { s.readOnly = readOnly }
+
+// SetAttrCommit sets when writes are committed. Writes buffered before
+// batching is turned off are committed.
+func (s *attrStore) SetAttrCommit(c pilosa.AttrCommit) error {
+	if c.Batched() && c.Interval == 0 {
+		c.Interval = pilosa.DefaultAttrCommitInterval
+	}
+	if c.Batched() && c.MaxOps == 0 {
+		c.MaxOps = pilosa.DefaultAttrCommitMaxOps
+	}
+	s.mu.Lock()
+	s.commit = c
+	s.mu.Unlock()
+
+	s.stopCommits()
+	err := s.flush()
+	if s.db != nil && s.batched() {
+		s.startCommits()
+	}
+	return err
+}
+
+// batched returns true if writes are buffered rather than committed.
+func (s *attrStore) batched() bool { return s.commit.Batched() && !s.readOnly }
+
 // Path returns path to the store's data file.
 func (s *attrStore) Path() string { return s.path }
 
 // Open opens and initializes the store.
 func (s *attrStore) Open() error {
+	// A read-only store can't share the data file with a process writing
+	// to it, so it reads a copy of the file as it is now.
+	path := s.path
+	if s.readOnly {
+		if locked, err := fileLocked(s.path); err != nil {
+			return errors.Wrap(err, "checking lock")
+		} else if locked {
+			if s.copyPath, err = copyTempFile(s.path); err != nil {
+				return errors.Wrap(err, "copying locked data file")
+			}
+			path = s.copyPath
+		}
+	}
+
 	// Open storage.
-	db, err := bolt.Open(s.path, 0666, &bolt.Options{Timeout: 1 * time.Second})
+	db, err := bolt.Open(path, 0666, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: s.readOnly})
 	if err != nil {
 		return errors.Wrap(err, "opening storage")
 	}
+	db.NoSync = s.noSync
 	s.db = db
+	if s.readOnly {
+		return nil
+	}
 
 	// Initialize database.
 	if err := s.db.Update(func(tx *bolt.Tx) error {
@@ -106,17 +193,269 @@ func (s *attrStore) Open() error {
 		return errors.Wrap(err, "initializing")
 	}
 
+	if s.batched() {
+		s.startCommits()
+	}
 	return nil
 }
 
-// Close closes the store.
+// Close commits any buffered writes and closes the store.
 func (s *attrStore) Close() error {
+	s.stopCommits()
+	var err error
 	if s.db != nil {
+		if err = s.flush(); err == nil {
+			err = s.takeCommitErr()
+		}
 		s.db.Close()
 	}
+	if s.copyPath != "" {
+		os.Remove(s.copyPath)
+		s.copyPath = ""
+	}
+	return err
+}
+
+// startCommits starts the goroutine committing batches of writes.
+func (s *attrStore) startCommits() {
+	s.closing = make(chan struct{})
+	s.wg.Add(1)
+	go func(closing <-chan struct{}, interval time.Duration) {
+		defer s.wg.Done()
+		s.monitorCommits(closing, interval)
+	}(s.closing, s.commit.Interval)
+}
+
+// stopCommits stops the goroutine committing batches, if it's running.
+func (s *attrStore) stopCommits() {
+	if s.closing == nil {
+		return
+	}
+	close(s.closing)
+	s.wg.Wait()
+	s.closing = nil
+}
+
+// monitorCommits commits the buffered writes every interval, or as soon as
+// the buffer is full, until closing is closed.
+func (s *attrStore) monitorCommits(closing <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case <-ticker.C:
+		case <-s.notify:
+		}
+
+		if err := s.flush(); err != nil {
+			s.mu.Lock()
+			s.commitErr = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+// flush commits the buffered writes in a single transaction. Writes which
+// fail to commit stay buffered, beneath any made since.
+func (s *attrStore) flush() error {
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		_, err := txUpdateBulkAttrs(tx, batch)
+		return err
+	}); err != nil {
+		s.mu.Lock()
+		for id, m := range s.pending {
+			batch[id] = overlayAttrs(batch[id], m)
+		}
+		s.pending = batch
+		s.mu.Unlock()
+		return errors.Wrap(err, "committing attrs")
+	}
+	return nil
+}
+
+// takeCommitErr returns and clears the error of the last failed batch.
+func (s *attrStore) takeCommitErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.commitErr
+	s.commitErr = nil
+	return err
+}
+
+// bufferAttrs merges m into the cached attributes of each ID, and buffers it
+// to be committed. s.mu must be held.
+func (s *attrStore) bufferAttrs(m map[uint64]map[string]interface{}) error {
+	attrs := make(map[uint64]map[string]interface{}, len(m))
+	var missing []uint64
+	for id := range m {
+		if attr := s.attrCache.Get(id); attr != nil {
+			attrs[id] = attr
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		if err := s.db.View(func(tx *bolt.Tx) error {
+			for _, id := range missing {
+				attr, err := txAttrs(tx, id)
+				if err != nil {
+					return err
+				}
+				attrs[id] = attr
+			}
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "finding attributes")
+		}
+	}
+	for id, attr := range attrs {
+		merged, err := mergeAttrs(attr, m[id])
+		if err != nil {
+			return err
+		}
+		attrs[id] = merged
+	}
+
+	if s.pending == nil {
+		s.pending = make(map[uint64]map[string]interface{})
+	}
+	for id, attr := range attrs {
+		s.pending[id] = overlayAttrs(s.pending[id], m[id])
+		s.attrCache.Set(id, attr)
+	}
+	if len(s.pending) >= s.commit.MaxOps {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
 	return nil
 }
 
+// fileLocked returns true if another process holds the exclusive lock which
+// bolt takes on the file at path for writing.
+func fileLocked(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_SH|syscall.LOCK_NB); err == syscall.EWOULDBLOCK {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+// copyTempFileAttempts is how many times copyTempFile copies a data file
+// which is committed to during the copy before giving up.
+const copyTempFileAttempts = 10
+
+// errCopyChanged is returned when a data file is committed to during every
+// attempt to copy it.
+var errCopyChanged = errors.New("data file changed during copy")
+
+// copyTempFile copies the file at path to a new temporary file outside of
+// the data directory and returns its path.
+//
+// The writer's exclusive lock keeps the file from being opened with bolt to
+// copy it in a transaction, so it's copied as is, again until no transaction
+// was committed during the copy. Pages referenced by the meta page of the
+// last commit aren't rewritten until a later one, which makes the copy
+// consistent.
+func copyTempFile(path string) (_ string, err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := ioutil.TempFile("", "pilosa-attrs-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(dst.Name())
+		}
+	}()
+	defer dst.Close()
+
+	for i := 0; i < copyTempFileAttempts; i++ {
+		before, err := lastTxID(src)
+		if err != nil {
+			return "", errors.Wrap(err, "reading meta")
+		}
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		} else if _, err := dst.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		} else if err := dst.Truncate(0); err != nil {
+			return "", err
+		} else if _, err := io.Copy(dst, src); err != nil {
+			return "", err
+		}
+		if after, err := lastTxID(src); err != nil {
+			return "", errors.Wrap(err, "reading meta")
+		} else if after == before {
+			return dst.Name(), dst.Close()
+		}
+	}
+	return "", errCopyChanged
+}
+
+// lastTxID returns the ID of the last transaction committed to a bolt data
+// file, from the newer of its two meta pages with a valid checksum.
+func lastTxID(file *os.File) (uint64, error) {
+	// Offsets of the meta fields from the start of a page, after the page
+	// header.
+	const (
+		pageSizeOffset = 16 + 8
+		txIDOffset     = 16 + 48
+		checksumOffset = 16 + 56
+	)
+
+	buf := make([]byte, checksumOffset+8)
+	if _, err := file.ReadAt(buf, 0); err != nil {
+		return 0, err
+	}
+	pageSize := int64(binary.LittleEndian.Uint32(buf[pageSizeOffset:]))
+
+	var txID uint64
+	var valid bool
+	for i := int64(0); i < 2; i++ {
+		if _, err := file.ReadAt(buf, i*pageSize); err != nil {
+			return 0, err
+		}
+		h := fnv.New64a()
+		h.Write(buf[16:checksumOffset]) // nolint: errcheck
+		if h.Sum64() != binary.LittleEndian.Uint64(buf[checksumOffset:]) {
+			continue
+		}
+		if id := binary.LittleEndian.Uint64(buf[txIDOffset:]); !valid || id > txID {
+			txID, valid = id, true
+		}
+	}
+	if !valid {
+		return 0, errors.New("no valid meta page")
+	}
+	return txID, nil
+}
+
 // Attrs returns a set of attributes by ID.
 func (s *attrStore) Attrs(id uint64) (m map[string]interface{}, err error) {
 	s.mu.RLock()
@@ -141,11 +480,67 @@ func (s *attrStore) Attrs(id uint64) (m map[string]interface{}, err error) {
 	return m, nil
 }
 
+// BlockAttrs returns the attributes of those ids which have any. The ids
+// which aren't cached are sorted and grouped by block, and each block is read
+// once, in a single transaction.
+func (s *attrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m := make(map[uint64]map[string]interface{}, len(ids))
+	var missing []uint64
+	for _, id := range ids {
+		if attrs := s.attrCache.Get(id); attrs == nil {
+			missing = append(missing, id)
+		} else if len(attrs) > 0 {
+			m[id] = attrs
+		}
+	}
+	if len(missing) == 0 {
+		return m, nil
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket([]byte("attrs")).Cursor()
+		for i := 0; i < len(missing); {
+			// Read the block from its first missing id, moving the cursor
+			// forward through the other ids of the block.
+			block := missing[i] / attrBlockSize
+			k, v := cur.Seek(u64tob(missing[i]))
+			for ; i < len(missing) && missing[i]/attrBlockSize == block; i++ {
+				id := missing[i]
+				for k != nil && btou64(k) < id {
+					k, v = cur.Next()
+				}
+
+				attrs := emptyMap
+				if k != nil && btou64(k) == id {
+					var err error
+					if attrs, err = pilosa.DecodeAttrs(v); err != nil {
+						return errors.Wrap(err, "decoding attrs")
+					}
+				}
+				s.attrCache.Set(id, attrs)
+				if len(attrs) > 0 {
+					m[id] = attrs
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "finding attributes")
+	}
+	return m, nil
+}
+
 // SetAttrs sets attribute values for a given ID.
 func (s *attrStore) SetAttrs(id uint64, m map[string]interface{}) error {
 	// Ignore empty maps.
 	if len(m) == 0 {
 		return nil
+	} else if err := s.takeCommitErr(); err != nil {
+		return err
 	}
 
 	// Check if the attributes already exist under a read-only lock.
@@ -159,6 +554,10 @@ func (s *attrStore) SetAttrs(id uint64, m map[string]interface{}) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.batched() {
+		return s.bufferAttrs(map[uint64]map[string]interface{}{id: m})
+	}
+
 	var attr map[string]interface{}
 	if err := s.db.Update(func(tx *bolt.Tx) error {
 		tmp, err := txUpdateAttrs(tx, id, m)
@@ -180,35 +579,69 @@ func (s *attrStore) SetAttrs(id uint64, m map[string]interface{}) error {
 
 // SetBulkAttrs sets attribute values for a set of ids.
 func (s *attrStore) SetBulkAttrs(m map[uint64]map[string]interface{}) error {
+	if err := s.takeCommitErr(); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	attrs := make(map[uint64]map[string]interface{})
-	if err := s.db.Update(func(tx *bolt.Tx) error {
-		// Collect and sort keys.
-		ids := make([]uint64, 0, len(m))
-		for id := range m {
+	if s.batched() {
+		return s.bufferAttrs(m)
+	}
+
+	var attrs map[uint64]map[string]interface{}
+	if err := s.db.Update(func(tx *bolt.Tx) (err error) {
+		attrs, err = txUpdateBulkAttrs(tx, m)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Swap attributes map in cache.
+	for id, attr := range attrs {
+		s.attrCache.Set(id, attr)
+	}
+
+	return nil
+}
+
+// DeleteRange deletes the attributes of every ID in [min, max]. Buffered
+// writes to the range are dropped, once any commit in progress finishes.
+func (s *attrStore) DeleteRange(min, max uint64) error {
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []uint64
+	for id := range s.pending {
+		if id >= min && id <= max {
+			delete(s.pending, id)
 			ids = append(ids, id)
 		}
-		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte("attrs"))
 
-		// Update attributes for each id.
+		// Collect the keys first, as deleting under a cursor skips the next key.
+		cur := bkt.Cursor()
+		for k, _ := cur.Seek(u64tob(min)); k != nil && btou64(k) <= max; k, _ = cur.Next() {
+			ids = append(ids, btou64(k))
+		}
 		for _, id := range ids {
-			attr, err := txUpdateAttrs(tx, id, m[id])
-			if err != nil {
-				return err
+			if err := bkt.Delete(u64tob(id)); err != nil {
+				return errors.Wrap(err, "deleting attrs")
 			}
-			attrs[id] = attr
 		}
-
 		return nil
 	}); err != nil {
-		return err
+		return errors.Wrap(err, "updating store")
 	}
 
-	// Swap attributes map in cache.
-	for id, attr := range attrs {
-		s.attrCache.Set(id, attr)
+	// Drop the deleted attributes from the cache.
+	for _, id := range ids {
+		s.attrCache.Set(id, nil)
 	}
 
 	return nil
@@ -216,6 +649,10 @@ func (s *attrStore) SetBulkAttrs(m map[uint64]map[string]interface{}) error {
 
 // Blocks returns a list of all blocks in the store.
 func (s *attrStore) Blocks() ([]pilosa.AttrBlock, error) {
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(false)
 	if err != nil {
 		return nil, errors.Wrap(err, "starting transaction")
@@ -247,6 +684,9 @@ func (s *attrStore) Blocks() ([]pilosa.AttrBlock, error) {
 
 // BlockData returns all data for a single block.
 func (s *attrStore) BlockData(i uint64) (map[uint64]map[string]interface{}, error) {
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
 	m := make(map[uint64]map[string]interface{})
 
 	// Start read-only transaction.
@@ -278,6 +718,67 @@ func (s *attrStore) BlockData(i uint64) (map[uint64]map[string]interface{}, erro
 	return m, nil
 }
 
+// Find returns the attributes of the IDs whose value of attr matches, in ID
+// order, up to limit IDs. Zero is unlimited. The store is scanned in
+// transactions of at most attrFindTxTime each.
+func (s *attrStore) Find(attr string, match func(value interface{}) bool, limit int) (map[uint64]map[string]interface{}, error) {
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+	m := make(map[uint64]map[string]interface{})
+	var start []byte
+	for {
+		next, err := s.findFrom(start, attr, match, limit, m)
+		if err != nil {
+			return nil, err
+		} else if next == nil {
+			return m, nil
+		}
+		start = next
+	}
+}
+
+// findFrom adds the matches from the key start onwards to m until the limit
+// is reached, the store is exhausted, or attrFindTxTime has passed, in which
+// case it returns the key to resume from.
+func (s *attrStore) findFrom(start []byte, attr string, match func(value interface{}) bool, limit int, m map[uint64]map[string]interface{}) (next []byte, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deadline := time.Now().Add(attrFindTxTime)
+	err = s.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket([]byte("attrs")).Cursor()
+		k, v := cur.First()
+		if start != nil {
+			k, v = cur.Seek(start)
+		}
+		for ; k != nil; k, v = cur.Next() {
+			if limit > 0 && len(m) >= limit {
+				return nil
+			} else if time.Now().After(deadline) {
+				next = append([]byte(nil), k...)
+				return nil
+			}
+
+			attrs, err := pilosa.DecodeAttrs(v)
+			if err != nil {
+				return errors.Wrap(err, "decoding attrs")
+			}
+			value, ok := attrs[attr]
+			if !ok {
+				continue
+			}
+			if ok, err := pilosa.MatchAttrValue(match, value); err != nil {
+				return errors.Wrapf(err, "id %d", btou64(k))
+			} else if ok {
+				m[btou64(k)] = attrs
+			}
+		}
+		return nil
+	})
+	return next, err
+}
+
 // txAttrs returns a map of attributes for an id.
 func txAttrs(tx *bolt.Tx, id uint64) (map[string]interface{}, error) {
 	v := tx.Bucket([]byte("attrs")).Get(u64tob(id))
@@ -293,8 +794,46 @@ func txUpdateAttrs(tx *bolt.Tx, id uint64, m map[string]interface{}) (map[string
 	attr, err := txAttrs(tx, id)
 	if err != nil {
 		return nil, err
+	} else if attr, err = mergeAttrs(attr, m); err != nil {
+		return nil, err
+	}
+
+	// Marshal and save new values.
+	buf, err := pilosa.EncodeAttrs(attr)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding attrs")
+	}
+	if err := tx.Bucket([]byte("attrs")).Put(u64tob(id), buf); err != nil {
+		return nil, errors.Wrap(err, "saving attrs")
 	}
+	return attr, nil
+}
 
+// txUpdateBulkAttrs updates the attributes of each id, in id order, and
+// returns their new combined sets of attributes.
+func txUpdateBulkAttrs(tx *bolt.Tx, m map[uint64]map[string]interface{}) (map[uint64]map[string]interface{}, error) {
+	// Collect and sort keys.
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// Update attributes for each id.
+	attrs := make(map[uint64]map[string]interface{}, len(m))
+	for _, id := range ids {
+		attr, err := txUpdateAttrs(tx, id, m[id])
+		if err != nil {
+			return nil, err
+		}
+		attrs[id] = attr
+	}
+	return attrs, nil
+}
+
+// mergeAttrs merges m into attr, and returns the result. Nil values delete
+// their keys. attr is modified unless it's empty.
+func mergeAttrs(attr, m map[string]interface{}) (map[string]interface{}, error) {
 	// Create a new map if it is empty so we don't update emptyMap.
 	if len(attr) == 0 {
 		attr = make(map[string]interface{}, len(m))
@@ -321,16 +860,20 @@ func txUpdateAttrs(tx *bolt.Tx, id uint64, m map[string]interface{}) (map[string
 			return nil, fmt.Errorf("invalid attr type: %T", v)
 		}
 	}
+	return attr, nil
+}
 
-	// Marshal and save new values.
-	buf, err := pilosa.EncodeAttrs(attr)
-	if err != nil {
-		return nil, errors.Wrap(err, "encoding attrs")
+// overlayAttrs returns the writes of m made after those of attr, keeping the
+// nil values which delete keys.
+func overlayAttrs(attr, m map[string]interface{}) map[string]interface{} {
+	other := make(map[string]interface{}, len(attr)+len(m))
+	for k, v := range attr {
+		other[k] = v
 	}
-	if err := tx.Bucket([]byte("attrs")).Put(u64tob(id), buf); err != nil {
-		return nil, errors.Wrap(err, "saving attrs")
+	for k, v := range m {
+		other[k] = v
 	}
-	return attr, nil
+	return other
 }
 
 // u64tob encodes v to big endian encoding.
@@ -0,0 +1,201 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/pilosa/pilosa"
+	"github.com/pkg/errors"
+)
+
+// mustOpenBatchedAttrStore returns an opened store in a temporary directory
+// which commits its writes in batches of maxOps. The caller removes the
+// directory.
+func mustOpenBatchedAttrStore(t *testing.T, maxOps int) *attrStore {
+	dir, err := ioutil.TempDir("", "pilosa-attr-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewAttrStore(filepath.Join(dir, "data")).(*attrStore)
+	if err := s.SetAttrCommit(pilosa.AttrCommit{Mode: pilosa.AttrCommitBatched, Interval: time.Hour, MaxOps: maxOps}); err != nil {
+		t.Fatal(err)
+	} else if err := s.Open(); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return s
+}
+
+// pendingN returns the number of IDs with buffered writes.
+func (s *attrStore) pendingN() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Ensure a full buffer is committed without waiting for the interval.
+func TestAttrStore_Batched_MaxOps(t *testing.T) {
+	s := mustOpenBatchedAttrStore(t, 3)
+	defer os.RemoveAll(filepath.Dir(s.Path()))
+	defer s.Close()
+
+	for id := uint64(0); id < 2; id++ {
+		if err := s.SetAttrs(id, map[string]interface{}{"A": int64(id)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := s.pendingN(); n != 2 {
+		t.Fatalf("unexpected pending: %d", n)
+	}
+
+	if err := s.SetAttrs(2, map[string]interface{}{"A": int64(2)}); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); s.pendingN() > 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("buffer not committed: %d pending", s.pendingN())
+		}
+	}
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		if m, err := txAttrs(tx, 2); err != nil {
+			return err
+		} else if !reflect.DeepEqual(m, map[string]interface{}{"A": int64(2)}) {
+			t.Fatalf("unexpected attrs: %#v", m)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure the error of a failed batch is returned to the next writer, and
+// that the writes of the batch stay buffered beneath later ones.
+func TestAttrStore_Batched_CommitError(t *testing.T) {
+	s := mustOpenBatchedAttrStore(t, 2)
+	defer os.RemoveAll(filepath.Dir(s.Path()))
+	defer s.Close()
+
+	if err := s.SetAttrs(1, map[string]interface{}{"A": int64(1), "B": "x"}); err != nil {
+		t.Fatal(err)
+	} else if _, err := s.Attrs(2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Committing to a closed database fails. Both IDs are cached, so their
+	// writes don't read it.
+	if err := s.db.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := s.SetAttrs(2, map[string]interface{}{"A": int64(2)}); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		s.mu.Lock()
+		err := s.commitErr
+		s.mu.Unlock()
+		if err != nil {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("commit didn't fail")
+		}
+	}
+	s.stopCommits()
+
+	if err := s.SetAttrs(1, map[string]interface{}{"A": int64(3)}); err == nil || errors.Cause(err) != bolt.ErrDatabaseNotOpen {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.SetAttrs(1, map[string]interface{}{"B": "y"}); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	pending := s.pending[1]
+	s.mu.Unlock()
+	if !reflect.DeepEqual(pending, map[string]interface{}{"A": int64(1), "B": "y"}) {
+		t.Fatalf("unexpected pending writes: %#v", pending)
+	}
+}
+
+// Ensure a data file copied while it's committed to opens with the writes
+// committed before the copy.
+func TestCopyTempFile_Committing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pilosa-attr-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := NewAttrStore(filepath.Join(dir, "data")).(*attrStore)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.SetAttrs(1, map[string]interface{}{"A": int64(1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		for id := uint64(2); ; id++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := s.SetAttrs(id, map[string]interface{}{"A": int64(id)}); err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		path, err := copyTempFile(s.Path())
+		if err != nil {
+			if err != errCopyChanged {
+				t.Fatal(err)
+			}
+			continue
+		}
+		db, err := bolt.Open(path, 0666, &bolt.Options{ReadOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		if err := db.View(func(tx *bolt.Tx) error {
+			for err := range tx.Check() {
+				return err
+			}
+			m, err = txAttrs(tx, 1)
+			return err
+		}); err != nil {
+			t.Fatal(err)
+		}
+		db.Close()
+		os.Remove(path)
+		if !reflect.DeepEqual(m, map[string]interface{}{"A": int64(1)}) {
+			t.Fatalf("unexpected attrs: %#v", m)
+		}
+	}
+	close(done)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -69,6 +69,14 @@ const (
 	messageTypeRecalculateCaches
 	messageTypeNodeEvent
 	messageTypeNodeStatus
+	messageTypeSetFieldTimeQuantum
+	messageTypeSetIndexTimeQuantum
+	messageTypeRenameIndex
+	messageTypeSetFieldCacheSize
+	messageTypeDeleteColumnRange
+	messageTypeRenameField
+	messageTypeNodeHandoff
+	messageTypeNodeHandoffProgress
 )
 
 // MarshalInternalMessage serializes the pilosa message and adds pilosa internal
@@ -116,6 +124,22 @@ func getMessage(typ byte) Message {
 		return &NodeEvent{}
 	case messageTypeNodeStatus:
 		return &NodeStatus{}
+	case messageTypeSetFieldTimeQuantum:
+		return &SetFieldTimeQuantumMessage{}
+	case messageTypeSetIndexTimeQuantum:
+		return &SetIndexTimeQuantumMessage{}
+	case messageTypeRenameIndex:
+		return &RenameIndexMessage{}
+	case messageTypeSetFieldCacheSize:
+		return &SetFieldCacheSizeMessage{}
+	case messageTypeDeleteColumnRange:
+		return &DeleteColumnRangeMessage{}
+	case messageTypeRenameField:
+		return &RenameFieldMessage{}
+	case messageTypeNodeHandoff:
+		return &NodeHandoffMessage{}
+	case messageTypeNodeHandoffProgress:
+		return &NodeHandoffProgress{}
 	default:
 		panic(fmt.Sprintf("unknown message type %d", typ))
 	}
@@ -155,6 +179,22 @@ func getMessageType(m Message) byte {
 		return messageTypeNodeEvent
 	case *NodeStatus:
 		return messageTypeNodeStatus
+	case *SetFieldTimeQuantumMessage:
+		return messageTypeSetFieldTimeQuantum
+	case *SetIndexTimeQuantumMessage:
+		return messageTypeSetIndexTimeQuantum
+	case *RenameIndexMessage:
+		return messageTypeRenameIndex
+	case *SetFieldCacheSizeMessage:
+		return messageTypeSetFieldCacheSize
+	case *DeleteColumnRangeMessage:
+		return messageTypeDeleteColumnRange
+	case *RenameFieldMessage:
+		return messageTypeRenameField
+	case *NodeHandoffMessage:
+		return messageTypeNodeHandoff
+	case *NodeHandoffProgress:
+		return messageTypeNodeHandoffProgress
 	default:
 		panic(fmt.Sprintf("don't have type for message %#v", m))
 	}
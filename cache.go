@@ -50,6 +50,13 @@ type cache interface {
 	// Returns an ordered list of the top ranked bitmaps.
 	Top() []bitmapPair
 
+	// Removes entries until no more than n remain, keeping the most valuable.
+	Shrink(n int)
+
+	// Changes the number of entries the cache keeps, removing those beyond
+	// it. Lists already returned by Top are unaffected.
+	SetMaxEntries(n uint32)
+
 	// SetStats defines the stats client used in the cache.
 	SetStats(s stats.StatsClient)
 }
@@ -129,6 +136,26 @@ func (c *lruCache) SetStats(s stats.StatsClient) {
 
 func (c *lruCache) onEvicted(key lru.Key, _ interface{}) { delete(c.counts, key.(uint64)) }
 
+// Shrink evicts the least recently used entries until no more than n remain.
+func (c *lruCache) Shrink(n int) {
+	for c.cache.Len() > n {
+		c.cache.RemoveOldest()
+	}
+}
+
+// SetMaxEntries changes the size of the cache, evicting the least recently
+// used entries beyond it.
+func (c *lruCache) SetMaxEntries(n uint32) {
+	c.cache.SetMaxEntries(int(n))
+
+	// Maps don't shrink as keys are deleted, so copy the remaining counts.
+	counts := make(map[uint64]uint64, len(c.counts))
+	for id, cnt := range c.counts {
+		counts[id] = cnt
+	}
+	c.counts = counts
+}
+
 // Ensure LRUCache implements Cache.
 var _ cache = &lruCache{}
 
@@ -287,6 +314,57 @@ func (c *rankCache) SetStats(s stats.StatsClient) {
 // Top returns an ordered list of pairs.
 func (c *rankCache) Top() []bitmapPair { return c.rankings }
 
+// Shrink removes the lowest ranked entries until no more than n remain. The
+// threshold is raised until the next recalculation so that evicted rows
+// aren't immediately readmitted.
+func (c *rankCache) Shrink(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) <= n {
+		return
+	}
+	c.stats.Count("cache.shrink", 1, 1.0)
+
+	rankings := make([]bitmapPair, 0, len(c.entries))
+	for id, cnt := range c.entries {
+		rankings = append(rankings, bitmapPair{ID: id, Count: cnt})
+	}
+	sort.Sort(bitmapPairs(rankings))
+
+	c.thresholdValue = rankings[n].Count
+	for _, pair := range rankings[n:] {
+		delete(c.entries, pair.ID)
+	}
+	rankings = rankings[:n]
+	if len(rankings) > int(c.maxEntries) {
+		rankings = rankings[:c.maxEntries]
+	}
+	c.rankings = rankings
+	c.updateTime, c.updateN = time.Now(), 0
+}
+
+// SetMaxEntries changes the size of the cache and reranks it, removing the
+// lowest ranked entries beyond the new size rather than waiting for the cache
+// to outgrow its threshold buffer. The rankings are replaced rather than
+// modified, so rows already returned by Top stay valid.
+func (c *rankCache) SetMaxEntries(n uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = n
+	c.thresholdBuffer = int(thresholdFactor * float64(n))
+	c.recalculate()
+
+	// Copy the remaining entries, since neither the map nor the array
+	// behind the rankings shrinks as entries are removed.
+	rankings := make([]bitmapPair, len(c.rankings))
+	copy(rankings, c.rankings)
+	entries := make(map[uint64]uint64, len(rankings))
+	for _, pair := range rankings {
+		entries[pair.ID] = pair.Count
+	}
+	c.rankings, c.entries = rankings, entries
+}
+
 // WriteTo writes the cache to w.
 func (c *rankCache) WriteTo(w io.Writer) (n int64, err error) {
 	panic("FIXME: TODO")
@@ -309,9 +387,14 @@ type bitmapPair struct {
 // bitmapPairs is a sortable list of BitmapPair objects.
 type bitmapPairs []bitmapPair
 
-func (p bitmapPairs) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p bitmapPairs) Len() int           { return len(p) }
-func (p bitmapPairs) Less(i, j int) bool { return p[i].Count > p[j].Count }
+func (p bitmapPairs) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p bitmapPairs) Len() int      { return len(p) }
+
+// Less ranks rows with the same count by ID, as Pairs does, so that every
+// shard offers the same rows with tied counts to TopN.
+func (p bitmapPairs) Less(i, j int) bool {
+	return p[i].Count > p[j].Count || (p[i].Count == p[j].Count && p[i].ID < p[j].ID)
+}
 
 // Pair holds an id/count pair.
 type Pair struct {
@@ -323,9 +406,14 @@ type Pair struct {
 // Pairs is a sortable slice of Pair objects.
 type Pairs []Pair
 
-func (p Pairs) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p Pairs) Len() int           { return len(p) }
-func (p Pairs) Less(i, j int) bool { return p[i].Count > p[j].Count }
+func (p Pairs) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p Pairs) Len() int      { return len(p) }
+
+// Less sorts pairs by count in descending order, and pairs with the same
+// count by ID, so that TopN results page consistently.
+func (p Pairs) Less(i, j int) bool {
+	return p[i].Count > p[j].Count || (p[i].Count == p[j].Count && p[i].ID < p[j].ID)
+}
 
 // pairHeap is a heap implementation over a group of Pairs.
 type pairHeap struct {
@@ -480,3 +568,320 @@ func (c nopCache) SetStats(stats.StatsClient) {}
 func (c nopCache) Top() []bitmapPair {
 	return []bitmapPair{}
 }
+
+func (c nopCache) Shrink(int) {}
+
+func (c nopCache) SetMaxEntries(uint32) {}
+
+const (
+	// rankCacheEntrySize is the approximate memory, in bytes, used by an
+	// entry in a ranked cache including map and ranking overhead.
+	rankCacheEntrySize = 64
+
+	// lruCacheEntrySize is the approximate memory, in bytes, used by an
+	// entry in an LRU cache including list and map overhead.
+	lruCacheEntrySize = 128
+)
+
+// cacheEntrySize returns the approximate size in bytes of a single entry in
+// a cache of the given type.
+func cacheEntrySize(cacheType string) int64 {
+	switch cacheType {
+	case CacheTypeRanked:
+		return rankCacheEntrySize
+	case CacheTypeLRU:
+		return lruCacheEntrySize
+	default:
+		return 0
+	}
+}
+
+// cacheAccountant tracks the approximate memory used by the caches of all
+// open fragments in a holder. When the usage exceeds the budget, the caches
+// of the fragments least recently used by TopN are shrunk first.
+type cacheAccountant struct {
+	mu        sync.Mutex
+	fragments map[*fragment]struct{}
+	usage     int64
+
+	stats stats.StatsClient
+}
+
+// newCacheAccountant returns a new instance of cacheAccountant.
+func newCacheAccountant() *cacheAccountant {
+	return &cacheAccountant{
+		fragments: make(map[*fragment]struct{}),
+		stats:     stats.NopStatsClient,
+	}
+}
+
+// register adds a fragment's cache to the accountant.
+func (a *cacheAccountant) register(f *fragment) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	a.fragments[f] = struct{}{}
+	a.mu.Unlock()
+}
+
+// unregister removes a fragment's cache from the accountant.
+func (a *cacheAccountant) unregister(f *fragment) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	delete(a.fragments, f)
+	a.mu.Unlock()
+}
+
+// Usage returns the approximate memory used by all registered caches as of
+// the last call to enforce.
+func (a *cacheAccountant) Usage() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.usage
+}
+
+// enforce recomputes the memory used by all registered caches and, if it
+// exceeds maxMemory, shrinks caches in order of least recent TopN usage until
+// the usage fits within the budget. A maxMemory of zero disables eviction.
+func (a *cacheAccountant) enforce(maxMemory int64) {
+	// Copy the registered fragments so that fragment locks are never
+	// acquired while holding the accountant lock.
+	a.mu.Lock()
+	frags := make([]*fragment, 0, len(a.fragments))
+	for f := range a.fragments {
+		frags = append(frags, f)
+	}
+	a.mu.Unlock()
+
+	type cacheUsage struct {
+		frag      *fragment
+		entries   int
+		entrySize int64
+		lastUsed  int64
+	}
+	usages := make([]cacheUsage, 0, len(frags))
+	var total int64
+	for _, f := range frags {
+		u := cacheUsage{
+			frag:      f,
+			entries:   f.cacheLen(),
+			entrySize: cacheEntrySize(f.CacheType),
+			lastUsed:  f.cacheLastUsed(),
+		}
+		total += int64(u.entries) * u.entrySize
+		usages = append(usages, u)
+	}
+
+	if maxMemory > 0 && total > maxMemory {
+		// Evict from the least valuable caches first. Caches which have
+		// never been used by TopN sort first; ties are broken by size.
+		sort.Slice(usages, func(i, j int) bool {
+			if usages[i].lastUsed != usages[j].lastUsed {
+				return usages[i].lastUsed < usages[j].lastUsed
+			}
+			return usages[i].entries > usages[j].entries
+		})
+
+		for _, u := range usages {
+			if total <= maxMemory {
+				break
+			} else if u.entries == 0 || u.entrySize == 0 {
+				continue
+			}
+
+			// Determine how many entries must be removed from this cache.
+			excess := (total - maxMemory + u.entrySize - 1) / u.entrySize
+			keep := u.entries - int(excess)
+			if keep < 0 {
+				keep = 0
+			}
+			u.frag.shrinkCache(keep)
+			total -= int64(u.entries-keep) * u.entrySize
+			a.stats.Count("cache.evict", int64(u.entries-keep), 1.0)
+		}
+	}
+
+	a.mu.Lock()
+	a.usage = total
+	a.mu.Unlock()
+	a.stats.Gauge("cache.memory", float64(total), 1.0)
+}
+
+// cacheRebuilder recalculates fragment caches in the background after bulk
+// imports. Each fragment is queued at most once no matter how many imports
+// touch it before its rebuild starts, and the number of concurrent rebuilds
+// is bounded by the number of workers running.
+type cacheRebuilder struct {
+	mu      sync.Mutex
+	pending map[*fragment]struct{}
+	queue   []*fragment
+	running int
+
+	// Number of fragments whose caches are being warmed up after opening,
+	// and which have finished warming up.
+	warming int
+	warmed  int
+
+	// Signals idle workers that the queue is non-empty.
+	notify chan struct{}
+
+	stats stats.StatsClient
+}
+
+// newCacheRebuilder returns a new instance of cacheRebuilder.
+func newCacheRebuilder() *cacheRebuilder {
+	return &cacheRebuilder{
+		pending: make(map[*fragment]struct{}),
+		notify:  make(chan struct{}, 1),
+		stats:   stats.NopStatsClient,
+	}
+}
+
+// enqueue schedules a rebuild of the fragment's cache unless one is
+// already pending.
+func (r *cacheRebuilder) enqueue(f *fragment) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pending[f]; ok {
+		return
+	}
+	r.pending[f] = struct{}{}
+	r.queue = append(r.queue, f)
+	r.signal()
+}
+
+// done removes the fragment from the pending set. It is called once the
+// fragment's cache has been brought up to date, whether by a worker or by a
+// reader which could not wait.
+func (r *cacheRebuilder) done(f *fragment) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delete(r.pending, f)
+	r.mu.Unlock()
+}
+
+// queued returns true if a rebuild of the fragment's cache is pending.
+func (r *cacheRebuilder) queued(f *fragment) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.pending[f]
+	return ok
+}
+
+// Pending returns the number of fragments whose caches are waiting to be
+// rebuilt or are being rebuilt.
+func (r *cacheRebuilder) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending) + r.running
+}
+
+// warmupStarted records that a fragment started warming up its cache.
+func (r *cacheRebuilder) warmupStarted() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warming++
+	r.gaugeWarmup()
+}
+
+// warmupFinished records that a fragment stopped warming up its cache, and
+// whether its cache is now warm.
+func (r *cacheRebuilder) warmupFinished(warm bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warming--
+	if warm {
+		r.warmed++
+	}
+	r.gaugeWarmup()
+}
+
+// Warmup returns the number of fragments whose caches are being warmed up
+// and which have finished warming up.
+func (r *cacheRebuilder) Warmup() (warming, warmed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.warming, r.warmed
+}
+
+// gaugeWarmup reports the warm up counts. r.mu must be held.
+func (r *cacheRebuilder) gaugeWarmup() {
+	r.stats.Gauge("cache.warming", float64(r.warming), 1.0)
+	r.stats.Gauge("cache.warmed", float64(r.warmed), 1.0)
+}
+
+// signal wakes a worker without blocking. r.mu must be held.
+func (r *cacheRebuilder) signal() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the next fragment to rebuild off the queue. Fragments which were
+// brought up to date since being queued are skipped.
+func (r *cacheRebuilder) next() *fragment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(r.queue) > 0 {
+		f := r.queue[0]
+		r.queue[0] = nil
+		r.queue = r.queue[1:]
+		if _, ok := r.pending[f]; !ok {
+			continue
+		}
+		delete(r.pending, f)
+		r.running++
+
+		// Let another worker pick up the rest of the queue.
+		if len(r.queue) > 0 {
+			r.signal()
+		}
+		return f
+	}
+	return nil
+}
+
+// run rebuilds queued caches until closing is closed.
+func (r *cacheRebuilder) run(closing <-chan struct{}) {
+	for {
+		select {
+		case <-closing:
+			return
+		default:
+		}
+
+		if f := r.next(); f != nil {
+			f.rebuildDirtyCache()
+			r.stats.Count("cache.rebuildImport", 1, 1.0)
+
+			r.mu.Lock()
+			r.running--
+			r.mu.Unlock()
+			continue
+		}
+
+		select {
+		case <-closing:
+			return
+		case <-r.notify:
+		}
+	}
+}
@@ -33,3 +33,48 @@ func TestCache_Rank(t *testing.T) {
 	}
 
 }
+
+// Ensure a ranked cache keeps the highest counts when resized, and that
+// rankings already returned are unaffected.
+func TestCache_Rank_SetMaxEntries(t *testing.T) {
+	cache := pilosa.NewRankCache(10)
+	for i := uint64(1); i <= 5; i++ {
+		cache.BulkAdd(i, i)
+	}
+	cache.Recalculate()
+	top := cache.Top()
+
+	cache.SetMaxEntries(2)
+	if ids := cache.IDs(); len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Fatalf("unexpected ids: %v", ids)
+	} else if n := len(cache.Top()); n != 2 {
+		t.Fatalf("unexpected rankings: %d", n)
+	} else if len(top) != 5 || top[4].ID != 1 {
+		t.Fatalf("unexpected previous rankings: %v", top)
+	}
+
+	// Growing the cache again admits lower counts.
+	cache.SetMaxEntries(10)
+	cache.Add(6, 1)
+	if n := cache.Len(); n != 3 {
+		t.Fatalf("unexpected cache size: %d", n)
+	}
+}
+
+// Ensure a ranked cache keeps the highest counts when shrunk.
+func TestCache_Rank_Shrink(t *testing.T) {
+	cache := pilosa.NewRankCache(10)
+	for i := uint64(1); i <= 5; i++ {
+		cache.BulkAdd(i, i)
+	}
+	cache.Shrink(2)
+	if ids := cache.IDs(); len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+
+	// Lower counts are no longer admitted.
+	cache.Add(6, 1)
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("unexpected cache size: %d", n)
+	}
+}
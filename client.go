@@ -32,28 +32,33 @@ type FieldValue struct {
 type InternalClient interface {
 	MaxShardByIndex(ctx context.Context) (map[string]uint64, error)
 	Schema(ctx context.Context) ([]*IndexInfo, error)
+	CanonicalSchema(ctx context.Context, uri *URI) (*CanonicalSchema, error)
+	FieldViews(ctx context.Context, uri *URI, index, field string) ([]*ViewInfo, error)
 	CreateIndex(ctx context.Context, index string, opt IndexOptions) error
 	FragmentNodes(ctx context.Context, index string, shard uint64) ([]*Node, error)
 	Nodes(ctx context.Context) ([]*Node, error)
 	Query(ctx context.Context, index string, queryRequest *QueryRequest) (*QueryResponse, error)
 	QueryNode(ctx context.Context, uri *URI, index string, queryRequest *QueryRequest) (*QueryResponse, error)
-	Import(ctx context.Context, index, field string, shard uint64, bits []Bit, opts ...ImportOption) error
-	ImportK(ctx context.Context, index, field string, bits []Bit, opts ...ImportOption) error
+	Import(ctx context.Context, index, field string, shard uint64, bits []Bit, opts ...ImportOption) (ImportCount, error)
+	ImportK(ctx context.Context, index, field string, bits []Bit, opts ...ImportOption) ([]ImportCount, error)
 	EnsureIndex(ctx context.Context, name string, options IndexOptions) error
 	EnsureField(ctx context.Context, indexName string, fieldName string) error
 	EnsureFieldWithOptions(ctx context.Context, index, field string, opt FieldOptions) error
 	ImportValue(ctx context.Context, index, field string, shard uint64, vals []FieldValue, opts ...ImportOption) error
 	ImportValueK(ctx context.Context, index, field string, vals []FieldValue, opts ...ImportOption) error
-	ExportCSV(ctx context.Context, index, field string, shard uint64, w io.Writer) error
+	ExportCSV(ctx context.Context, index, field string, shard uint64, w io.Writer, opts ...ExportOption) error
 	CreateField(ctx context.Context, index, field string) error
 	CreateFieldWithOptions(ctx context.Context, index, field string, opt FieldOptions) error
 	FragmentBlocks(ctx context.Context, uri *URI, index, field, view string, shard uint64) ([]FragmentBlock, error)
-	BlockData(ctx context.Context, uri *URI, index, field, view string, shard uint64, block int) ([]uint64, []uint64, error)
+	FragmentChecksums(ctx context.Context, uri *URI, index, field, view string) ([]FragmentChecksum, error)
+	BlockData(ctx context.Context, uri *URI, index, field, view string, shard uint64, block, offset, limit int) (rowIDs, columnIDs []uint64, more bool, err error)
 	ColumnAttrDiff(ctx context.Context, uri *URI, index string, blks []AttrBlock) (map[uint64]map[string]interface{}, error)
 	RowAttrDiff(ctx context.Context, uri *URI, index, field string, blks []AttrBlock) (map[uint64]map[string]interface{}, error)
 	SendMessage(ctx context.Context, uri *URI, msg []byte) error
 	RetrieveShardFromURI(ctx context.Context, index, field, view string, shard uint64, uri URI) (io.ReadCloser, error)
 	ImportRoaring(ctx context.Context, uri *URI, index, field string, shard uint64, remote bool, req *ImportRoaringRequest) error
+	ImportRoaringRows(ctx context.Context, index, field string, shard uint64, rows []ImportRoaringRow, opts ...ImportOption) error
+	ImportView(ctx context.Context, req *ImportRequest, opts ...ImportOption) error
 }
 
 //===============
@@ -88,6 +93,12 @@ func (n nopInternalClient) MaxShardByIndex(context.Context) (map[string]uint64,
 	return nil, nil
 }
 func (n nopInternalClient) Schema(ctx context.Context) ([]*IndexInfo, error) { return nil, nil }
+func (n nopInternalClient) CanonicalSchema(ctx context.Context, uri *URI) (*CanonicalSchema, error) {
+	return nil, nil
+}
+func (n nopInternalClient) FieldViews(ctx context.Context, uri *URI, index, field string) ([]*ViewInfo, error) {
+	return nil, nil
+}
 func (n nopInternalClient) CreateIndex(ctx context.Context, index string, opt IndexOptions) error {
 	return nil
 }
@@ -103,13 +114,19 @@ func (n nopInternalClient) Query(ctx context.Context, index string, queryRequest
 func (n nopInternalClient) QueryNode(ctx context.Context, uri *URI, index string, queryRequest *QueryRequest) (*QueryResponse, error) {
 	return nil, nil
 }
-func (n nopInternalClient) Import(ctx context.Context, index, field string, shard uint64, bits []Bit, opts ...ImportOption) error {
+func (n nopInternalClient) Import(ctx context.Context, index, field string, shard uint64, bits []Bit, opts ...ImportOption) (ImportCount, error) {
+	return ImportCount{}, nil
+}
+func (n nopInternalClient) ImportK(ctx context.Context, index, field string, bits []Bit, opts ...ImportOption) ([]ImportCount, error) {
+	return nil, nil
+}
+func (n nopInternalClient) ImportRoaring(ctx context.Context, uri *URI, index, field string, shard uint64, remote bool, req *ImportRoaringRequest) error {
 	return nil
 }
-func (n nopInternalClient) ImportK(ctx context.Context, index, field string, bits []Bit, opts ...ImportOption) error {
+func (n nopInternalClient) ImportRoaringRows(ctx context.Context, index, field string, shard uint64, rows []ImportRoaringRow, opts ...ImportOption) error {
 	return nil
 }
-func (n nopInternalClient) ImportRoaring(ctx context.Context, uri *URI, index, field string, shard uint64, remote bool, req *ImportRoaringRequest) error {
+func (n nopInternalClient) ImportView(ctx context.Context, req *ImportRequest, opts ...ImportOption) error {
 	return nil
 }
 func (n nopInternalClient) EnsureIndex(ctx context.Context, name string, options IndexOptions) error {
@@ -127,7 +144,7 @@ func (n nopInternalClient) ImportValue(ctx context.Context, index, field string,
 func (n nopInternalClient) ImportValueK(ctx context.Context, index, field string, vals []FieldValue, opts ...ImportOption) error {
 	return nil
 }
-func (n nopInternalClient) ExportCSV(ctx context.Context, index, field string, shard uint64, w io.Writer) error {
+func (n nopInternalClient) ExportCSV(ctx context.Context, index, field string, shard uint64, w io.Writer, opts ...ExportOption) error {
 	return nil
 }
 func (n nopInternalClient) CreateField(ctx context.Context, index, field string) error { return nil }
@@ -137,8 +154,11 @@ func (n nopInternalClient) CreateFieldWithOptions(ctx context.Context, index, fi
 func (n nopInternalClient) FragmentBlocks(ctx context.Context, uri *URI, index, field, view string, shard uint64) ([]FragmentBlock, error) {
 	return nil, nil
 }
-func (n nopInternalClient) BlockData(ctx context.Context, uri *URI, index, field, view string, shard uint64, block int) ([]uint64, []uint64, error) {
-	return nil, nil, nil
+func (n nopInternalClient) FragmentChecksums(ctx context.Context, uri *URI, index, field, view string) ([]FragmentChecksum, error) {
+	return nil, nil
+}
+func (n nopInternalClient) BlockData(ctx context.Context, uri *URI, index, field, view string, shard uint64, block, offset, limit int) (rowIDs, columnIDs []uint64, more bool, err error) {
+	return nil, nil, false, nil
 }
 func (n nopInternalClient) ColumnAttrDiff(ctx context.Context, uri *URI, index string, blks []AttrBlock) (map[uint64]map[string]interface{}, error) {
 	return nil, nil
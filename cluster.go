@@ -213,6 +213,13 @@ type cluster struct { // nolint: maligned
 	jobs       map[int64]*resizeJob
 	currentJob *resizeJob
 
+	// The ID of the node being removed with a handoff, the state of its
+	// removal on the coordinator, and the ID of the node whose shards this
+	// node is pulling, if any.
+	leaving string
+	removal *nodeRemoval
+	handoff string
+
 	// Close management
 	wg      sync.WaitGroup
 	closing chan struct{}
@@ -444,16 +451,21 @@ func (c *cluster) unprotectedSetState(state string) {
 	// been removed.
 	// It's safe to do a cleanup after state changes back to normal.
 	if doCleanup {
-		var cleaner holderCleaner
-		cleaner.Node = c.Node
-		cleaner.Holder = c.holder
-		cleaner.Cluster = c
-		cleaner.Closing = c.closing
+		c.unprotectedCleanHolder()
+	}
+}
 
-		// Clean holder.
-		if err := cleaner.CleanHolder(); err != nil {
-			c.logger.Printf("holder clean error: err=%s", err)
-		}
+// unprotectedCleanHolder removes the fragments this node doesn't own.
+func (c *cluster) unprotectedCleanHolder() {
+	var cleaner holderCleaner
+	cleaner.Node = c.Node
+	cleaner.Holder = c.holder
+	cleaner.Cluster = c
+	cleaner.Closing = c.closing
+
+	// Clean holder.
+	if err := cleaner.CleanHolder(); err != nil {
+		c.logger.Printf("holder clean error: err=%s", err)
 	}
 }
 
@@ -544,6 +556,7 @@ func (c *cluster) unprotectedStatus() *ClusterStatus {
 		ClusterID: c.id,
 		State:     c.state,
 		Nodes:     c.nodes,
+		LeavingID: c.leaving,
 	}
 }
 
@@ -764,6 +777,7 @@ func (c *cluster) fragSources(to *cluster, idx *Index) (map[string][]*ResizeSour
 		srcCluster.nodes = Nodes(c.nodes).Clone()
 		srcCluster.Hasher = c.Hasher
 		srcCluster.partitionN = c.partitionN
+		srcCluster.holder = c.holder
 		srcCluster.ReplicaN = 1
 	}
 
@@ -831,11 +845,22 @@ func (c *cluster) partition(index string, shard uint64) int {
 
 	// Hash the bytes and mod by partition count.
 	h := fnv.New64a()
-	h.Write([]byte(index))
+	h.Write([]byte(c.partitionKey(index)))
 	h.Write(buf[:])
 	return int(h.Sum64() % uint64(c.partitionN))
 }
 
+// partitionKey returns the name hashed to place the shards of index, which
+// stays the same when the index is renamed.
+func (c *cluster) partitionKey(index string) string {
+	if c.holder == nil {
+		return index
+	} else if idx := c.holder.Index(index); idx != nil {
+		return idx.PartitionKey()
+	}
+	return index
+}
+
 // ShardNodes returns a list of nodes that own a fragment. Safe for concurrent use.
 func (c *cluster) ShardNodes(index string, shard uint64) []*Node {
 	c.mu.RLock()
@@ -853,24 +878,40 @@ func (c *cluster) ownsShard(nodeID string, index string, shard uint64) bool {
 	return Nodes(c.shardNodes(index, shard)).ContainsID(nodeID)
 }
 
-// partitionNodes returns a list of nodes that own a partition. unprotected.
+// partitionNodes returns a list of nodes that own a partition. While a node
+// is being removed with a handoff, they are its owners with the node,
+// followed by any others it has without the node. unprotected.
 func (c *cluster) partitionNodes(partitionID int) []*Node {
+	nodes := c.ringNodes(c.nodes, partitionID)
+	if c.leaving == "" || !Nodes(c.nodes).ContainsID(c.leaving) {
+		return nodes
+	}
+	for _, node := range c.ringNodes(Nodes(c.nodes).FilterID(c.leaving), partitionID) {
+		if !Nodes(nodes).ContainsID(node.ID) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// ringNodes returns the nodes that own a partition in a ring of nodes.
+func (c *cluster) ringNodes(ring []*Node, partitionID int) []*Node {
 	// Default replica count to between one and the number of nodes.
 	// The replica count can be zero if there are no nodes.
 	replicaN := c.ReplicaN
-	if replicaN > len(c.nodes) {
-		replicaN = len(c.nodes)
+	if replicaN > len(ring) {
+		replicaN = len(ring)
 	} else if replicaN == 0 {
 		replicaN = 1
 	}
 
 	// Determine primary owner node.
-	nodeIndex := c.Hasher.Hash(uint64(partitionID), len(c.nodes))
+	nodeIndex := c.Hasher.Hash(uint64(partitionID), len(ring))
 
 	// Collect nodes around the ring.
 	nodes := make([]*Node, replicaN)
 	for i := 0; i < replicaN; i++ {
-		nodes[i] = c.nodes[(nodeIndex+i)%len(c.nodes)]
+		nodes[i] = ring[(nodeIndex+i)%len(ring)]
 	}
 
 	return nodes
@@ -922,6 +963,7 @@ func (c *cluster) setup() error {
 	}
 
 	c.id = c.Topology.clusterID
+	c.leaving = c.Topology.leavingID
 
 	// Only the coordinator needs to consider the .topology file.
 	if c.isCoordinator() {
@@ -1181,6 +1223,7 @@ func (c *cluster) unprotectedGenerateResizeJobByAction(nodeAction nodeAction) (*
 	toCluster.nodes = Nodes(c.nodes).Clone()
 	toCluster.Hasher = c.Hasher
 	toCluster.partitionN = c.partitionN
+	toCluster.holder = c.holder
 	toCluster.ReplicaN = c.ReplicaN
 	if nodeAction.action == resizeJobActionRemove {
 		toCluster.removeNodeBasicSorted(nodeAction.node.ID)
@@ -1280,7 +1323,7 @@ func (c *cluster) followResizeInstruction(instr *ResizeInstruction) error {
 			// Sync the NodeStatus received in the resize instruction.
 			// Sync schema.
 			c.logger.Debugf("holder applySchema")
-			if err := c.holder.applySchema(instr.NodeStatus.Schema); err != nil {
+			if err := c.holder.applySchema(instr.NodeStatus.Schema, false); err != nil {
 				return errors.Wrap(err, "applying schema")
 			}
 
@@ -1320,8 +1363,7 @@ func (c *cluster) followResizeInstruction(instr *ResizeInstruction) error {
 				}
 
 				// Create the local fragment.
-				frag, err := v.CreateFragmentIfNotExists(src.Shard)
-				if err != nil {
+				if _, err := v.CreateFragmentIfNotExists(src.Shard); err != nil {
 					return errors.Wrap(err, "creating fragment")
 				}
 
@@ -1346,7 +1388,12 @@ func (c *cluster) followResizeInstruction(instr *ResizeInstruction) error {
 				// Write to local field and always close reader.
 				if err := func() error {
 					defer rd.Close()
-					_, err := frag.ReadFrom(rd)
+					frag, err := v.acquireFragmentIfNotExists(src.Shard)
+					if err != nil {
+						return err
+					}
+					defer frag.release()
+					_, err = frag.ReadFrom(rd)
 					return err
 				}(); err != nil {
 					return errors.Wrap(err, "copying remote shard")
@@ -1537,6 +1584,9 @@ type Topology struct {
 
 	clusterID string
 
+	// leavingID is the ID of the node being removed with a handoff, if any.
+	leavingID string
+
 	// nodeStates holds the state of each node according to
 	// the coordinator. Used during startup and data load.
 	nodeStates map[string]string
@@ -1630,8 +1680,12 @@ func (c *cluster) loadTopology() error {
 	return nil
 }
 
-// saveTopology writes the current topology to disk. unprotected.
+// saveTopology writes the current topology to disk, unless the holder is
+// read-only. unprotected.
 func (c *cluster) saveTopology() error {
+	if c.holder != nil && c.holder.ReadOnly {
+		return nil
+	}
 
 	if err := os.MkdirAll(c.Path, 0777); err != nil {
 		return errors.Wrap(err, "creating directory")
@@ -1639,7 +1693,7 @@ func (c *cluster) saveTopology() error {
 
 	if buf, err := proto.Marshal(encodeTopology(c.Topology)); err != nil {
 		return errors.Wrap(err, "marshalling")
-	} else if err := ioutil.WriteFile(filepath.Join(c.Path, ".topology"), buf, 0666); err != nil {
+	} else if err := writeMetaFile(filepath.Join(c.Path, ".topology"), buf, 0666); err != nil {
 		return errors.Wrap(err, "writing file")
 	}
 	return nil
@@ -1761,6 +1815,11 @@ func (c *cluster) nodeJoin(node *Node) error {
 		return c.unprotectedSetStateAndBroadcast(c.determineClusterState())
 	}
 
+	// The owners of shards can't change again until a removal completes.
+	if c.leaving != "" {
+		return fmt.Errorf("can't add node %s while node %s is being removed", node.ID, c.leaving)
+	}
+
 	// If the holder does not yet contain data, go ahead and add the node.
 	if ok, err := c.holder.HasData(); !ok && err == nil {
 		if err := c.addNode(node); err != nil {
@@ -1794,6 +1853,8 @@ func (c *cluster) nodeLeave(nodeID string) error {
 	if c.state != ClusterStateNormal && c.state != ClusterStateDegraded {
 		return fmt.Errorf("cluster must be '%s' to remove a node but is '%s'",
 			ClusterStateNormal, c.state)
+	} else if c.leaving != "" {
+		return fmt.Errorf("node %s is being removed with a handoff", c.leaving)
 	}
 
 	// Ensure that node is in the cluster.
@@ -1905,11 +1966,22 @@ func (c *cluster) mergeClusterStatus(cs *ClusterStatus) error {
 		}
 	}
 
+	// A removal with a handoff completes once the leaving node is dropped,
+	// after which the fragments this node no longer owns are removed.
+	leaving := c.leaving
+	if err := c.unprotectedSetLeaving(cs.LeavingID); err != nil {
+		return errors.Wrap(err, "setting leaving node")
+	}
+	cleanup := leaving != "" && cs.LeavingID == "" && !Nodes(c.nodes).ContainsID(leaving)
+
 	// If the cluster membership has changed, reset the primary for
 	// translate store replication.
 	c.holder.setPrimaryTranslateStore(c.unprotectedPreviousNode())
 
 	c.unprotectedSetState(cs.State)
+	if cleanup {
+		c.unprotectedCleanHolder()
+	}
 
 	c.markAsJoined()
 
@@ -1953,6 +2025,9 @@ type ClusterStatus struct {
 	ClusterID string
 	State     string
 	Nodes     []*Node
+
+	// The ID of the node being removed with a handoff, if any.
+	LeavingID string
 }
 
 type ResizeInstruction struct {
@@ -1984,6 +2059,7 @@ func encodeTopology(topology *Topology) *internal.Topology {
 	return &internal.Topology{
 		ClusterID: topology.clusterID,
 		NodeIDs:   topology.nodeIDs,
+		LeavingID: topology.leavingID,
 	}
 }
 
@@ -1995,6 +2071,7 @@ func decodeTopology(topology *internal.Topology) (*Topology, error) {
 	t := newTopology()
 	t.clusterID = topology.ClusterID
 	t.nodeIDs = topology.NodeIDs
+	t.leavingID = topology.LeavingID
 	sort.Slice(t.nodeIDs,
 		func(i, j int) bool {
 			return t.nodeIDs[i] < t.nodeIDs[j]
@@ -2029,6 +2106,32 @@ type DeleteFieldMessage struct {
 	Field string
 }
 
+type SetFieldTimeQuantumMessage struct {
+	Index       string
+	Field       string
+	TimeQuantum TimeQuantum
+}
+
+// SetFieldCacheSizeMessage changes the cache size of a field.
+type SetFieldCacheSizeMessage struct {
+	Index     string
+	Field     string
+	CacheSize uint32
+}
+
+// RenameIndexMessage renames an index, or only checks that it can be renamed
+// if Check is set.
+type RenameIndexMessage struct {
+	Index   string
+	NewName string
+	Check   bool
+}
+
+type SetIndexTimeQuantumMessage struct {
+	Index       string
+	TimeQuantum TimeQuantum
+}
+
 type DeleteAvailableShardMessage struct {
 	Index   string
 	Field   string
@@ -2046,6 +2149,22 @@ type DeleteViewMessage struct {
 	View  string
 }
 
+// DeleteColumnRangeMessage deletes the columns in [Min, Max] from an index.
+type DeleteColumnRangeMessage struct {
+	Index string
+	Min   uint64
+	Max   uint64
+}
+
+// RenameFieldMessage renames a field, or only checks that it can be renamed
+// if Check is set.
+type RenameFieldMessage struct {
+	Index   string
+	Field   string
+	NewName string
+	Check   bool
+}
+
 type ResizeInstructionComplete struct {
 	JobID int64
 	Node  *Node
@@ -2081,4 +2200,8 @@ type FieldStatus struct {
 	AvailableShards *roaring.Bitmap
 }
 
-type RecalculateCaches struct{}
+// RecalculateCaches is a message recalculating the caches of every node, or
+// rebuilding them from storage if Rebuild is set.
+type RecalculateCaches struct {
+	Rebuild bool
+}
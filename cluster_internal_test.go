@@ -16,6 +16,7 @@ package pilosa
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"math/rand"
 	"reflect"
@@ -336,6 +337,128 @@ func TestCluster_Owners(t *testing.T) {
 	}
 }
 
+// Ensure a leaving node stays the owner of its partitions, ahead of the
+// nodes which own them once it's removed.
+func TestCluster_Owners_Leaving(t *testing.T) {
+	c := cluster{
+		nodes: []*Node{
+			{ID: "a", URI: NewTestURIFromHostPort("serverA", 1000)},
+			{ID: "b", URI: NewTestURIFromHostPort("serverB", 1000)},
+			{ID: "c", URI: NewTestURIFromHostPort("serverC", 1000)},
+		},
+		Hasher:   NewTestModHasher(),
+		ReplicaN: 2,
+		leaving:  "b",
+	}
+
+	if a := c.partitionNodes(0); !reflect.DeepEqual(a, []*Node{c.nodes[0], c.nodes[1], c.nodes[2]}) {
+		t.Fatalf("unexpected owners: %s", spew.Sdump(a))
+	} else if a := c.partitionNodes(1); !reflect.DeepEqual(a, []*Node{c.nodes[1], c.nodes[2], c.nodes[0]}) {
+		t.Fatalf("unexpected owners: %s", spew.Sdump(a))
+	}
+
+	// Verify partitions which don't move keep their owners.
+	if a := c.partitionNodes(2); !reflect.DeepEqual(a, []*Node{c.nodes[2], c.nodes[0]}) {
+		t.Fatalf("unexpected owners: %s", spew.Sdump(a))
+	}
+
+	// Verify a leaving node which is gone doesn't change the owners.
+	c.leaving = "d"
+	if a := c.partitionNodes(0); !reflect.DeepEqual(a, []*Node{c.nodes[0], c.nodes[1]}) {
+		t.Fatalf("unexpected owners: %s", spew.Sdump(a))
+	}
+}
+
+// holderClient serves the views and fragments of the holders of other nodes
+// by host.
+type holderClient struct {
+	nopInternalClient
+	holders map[string]*Holder
+}
+
+func (c *holderClient) FieldViews(ctx context.Context, uri *URI, index, field string) ([]*ViewInfo, error) {
+	f := c.holders[uri.Host].Field(index, field)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+	var views []*ViewInfo
+	for _, v := range f.views() {
+		views = append(views, &ViewInfo{Name: v.name})
+	}
+	return views, nil
+}
+
+func (c *holderClient) FragmentBlocks(ctx context.Context, uri *URI, index, field, view string, shard uint64) ([]FragmentBlock, error) {
+	frag, err := c.holders[uri.Host].acquireFragment(index, field, view, shard)
+	if err != nil {
+		return nil, err
+	} else if frag == nil {
+		return nil, ErrFragmentNotFound
+	}
+	defer frag.release()
+	return frag.Blocks(), nil
+}
+
+func (c *holderClient) BlockData(ctx context.Context, uri *URI, index, field, view string, shard uint64, block, offset, limit int) (rowIDs, columnIDs []uint64, more bool, err error) {
+	frag, err := c.holders[uri.Host].acquireFragment(index, field, view, shard)
+	if err != nil || frag == nil {
+		return nil, nil, false, err
+	}
+	defer frag.release()
+	rowIDs, columnIDs, more = frag.blockData(block, offset, limit)
+	return rowIDs, columnIDs, more, nil
+}
+
+// Ensure a pulled shard only gets fragments in the views in which its other
+// owners hold it.
+func TestCluster_PullShard(t *testing.T) {
+	local, remote := newHolder(), newHolder()
+	for _, h := range []*tHolder{local, remote} {
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+	}
+
+	t2018 := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2019 := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	var fields []*Field
+	for _, h := range []*tHolder{local, remote} {
+		f, err := h.MustCreateIndexIfNotExists("i", IndexOptions{}).CreateFieldIfNotExists("f", OptFieldTypeTime(TimeQuantum("Y")))
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err := f.SetBit(1, 1, &t2018); err != nil {
+			t.Fatal(err)
+		}
+		fields = append(fields, f)
+	}
+	if _, err := fields[1].SetBit(2, ShardWidth+1, &t2019); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewTestCluster(2)
+	c.ReplicaN = 2
+	c.holder = local.Holder
+	c.InternalClient = &holderClient{holders: map[string]*Holder{
+		c.nodes[1].URI.Host: remote.Holder,
+	}}
+
+	if err := c.pullShard("i", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{viewStandard, viewStandard + "_2019"} {
+		if frag := local.fragment("i", "f", name, 1); frag == nil {
+			t.Fatalf("expected fragment in view %s", name)
+		} else if a := frag.row(2).Columns(); !reflect.DeepEqual(a, []uint64{ShardWidth + 1}) {
+			t.Fatalf("unexpected columns in view %s: %v", name, a)
+		}
+	}
+	if frag := local.fragment("i", "f", viewStandard+"_2018", 1); frag != nil {
+		t.Fatal("unexpected fragment in view which lacked the shard")
+	}
+}
+
 // Ensure the partitioner can assign a fragment to a partition.
 func TestCluster_Partition(t *testing.T) {
 	if err := quick.Check(func(index string, shard uint64, partitionN int) bool {
@@ -14,7 +14,11 @@
 
 package pilosa
 
-import "io"
+import (
+	"io"
+
+	"github.com/pilosa/pilosa/logger"
+)
 
 // CmdIO holds standard unix inputs and outputs.
 type CmdIO struct {
@@ -32,3 +36,8 @@ func NewCmdIO(stdin io.Reader, stdout, stderr io.Writer) *CmdIO {
 		Stderr: stderr,
 	}
 }
+
+// Logger returns a logger which writes to Stderr.
+func (c *CmdIO) Logger() logger.Logger {
+	return logger.NewStandardLogger(c.Stderr)
+}
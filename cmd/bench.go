@@ -0,0 +1,69 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/ctl"
+)
+
+var Bencher *ctl.BenchCommand
+
+func newBenchCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	Bencher = ctl.NewBenchCommand(stdin, stdout, stderr)
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Generate import or query load and report how fast it was served.",
+		Long: `
+Generates load on a host and reports the throughput, the latency percentiles
+and the number of errors of the requests once they are done. The index and
+field are created if they don't exist.
+
+With --op set-bit, sets --n random bits, of rows below --max-row-id and
+columns below --max-column-id, by importing them --batch-size at a time.
+
+With --op query, runs --n queries against the existing data, drawn from
+--query-mix, a list of name:weight pairs of Row, Intersect and TopN queries
+of random rows. The latencies of each query of the mix are reported as well.
+
+--concurrency requests are sent at once. The same --seed generates the same
+bits or queries whatever the concurrency; without one, the seed used is
+printed so that the run can be repeated.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Bencher.Run(context.Background())
+		},
+	}
+	flags := benchCmd.Flags()
+
+	flags.StringVarP(&Bencher.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&Bencher.Index, "index", "i", "", "Pilosa index to load")
+	flags.StringVarP(&Bencher.Field, "field", "f", "", "Field to load")
+	flags.StringVarP(&Bencher.Op, "op", "", Bencher.Op, "Operation to run. One of: set-bit, query")
+	flags.IntVarP(&Bencher.N, "n", "", Bencher.N, "Number of bits to set or queries to run")
+	flags.IntVarP(&Bencher.BatchSize, "batch-size", "", Bencher.BatchSize, "Number of bits imported by each request")
+	flags.Uint64VarP(&Bencher.MaxRowID, "max-row-id", "", Bencher.MaxRowID, "Rows are drawn from 0 up to this")
+	flags.Uint64VarP(&Bencher.MaxColumnID, "max-column-id", "", Bencher.MaxColumnID, "Columns are drawn from 0 up to this")
+	flags.StringVarP(&Bencher.QueryMix, "query-mix", "", Bencher.QueryMix, "Weights of the queries run, as name:weight pairs of Row, Intersect and TopN")
+	flags.IntVarP(&Bencher.Concurrency, "concurrency", "", Bencher.Concurrency, "Number of requests sent at once")
+	flags.Int64VarP(&Bencher.Seed, "seed", "", 0, "Seed of the random bits and queries - default from the time")
+	ctl.SetTLSConfig(flags, &Bencher.TLS.CertificatePath, &Bencher.TLS.CertificateKeyPath, &Bencher.TLS.SkipVerify)
+
+	return benchCmd
+}
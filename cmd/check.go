@@ -32,15 +32,31 @@ func newCheckCommand(stdin io.Reader, stdout io.Writer, stderr io.Writer) *cobra
 		Use:   "check <path> [path2]...",
 		Short: "Do a consistency check on a pilosa data file.",
 		Long: `
-Performs a consistency check on data files.
+Performs a consistency check on data files without modifying them. Each path
+is a fragment or cache file, or a data directory whose fragment and cache
+files are all checked, so the server should be stopped first.
+
+Each file is reported as ok or corrupt, and the command fails if any file is
+corrupt. With --verbose, the rows, bits and containers of each fragment and
+the highest shard found are also printed.
+
+With --schema, compares the schemas of the nodes in --hosts instead and prints
+how each differs from the first: missing or extra indexes, fields and views,
+and options which don't match.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
+			if len(args) == 0 && !checker.Schema {
 				return fmt.Errorf("path required")
 			}
 			checker.Paths = args
 			return checker.Run(context.Background())
 		},
 	}
+	flags := checkCmd.Flags()
+	flags.BoolVarP(&checker.Verbose, "verbose", "", false, "Print the statistics of each file checked")
+	flags.BoolVarP(&checker.Schema, "schema", "", false, "Compare the schemas of the nodes in --hosts")
+	flags.StringSliceVarP(&checker.Hosts, "hosts", "", nil, "Comma separated host:port of each node to compare")
+	ctl.SetTLSConfig(flags, &checker.TLS.CertificatePath, &checker.TLS.CertificateKeyPath, &checker.TLS.SkipVerify)
+
 	return checkCmd
 }
@@ -17,6 +17,7 @@ package cmd
 import (
 	"context"
 	"io"
+	"math"
 
 	"github.com/spf13/cobra"
 
@@ -39,6 +40,29 @@ The format of the CSV file is:
 	ROWID,COLUMNID
 
 The file does not contain any headers.
+
+With --format proto, every view of the field is exported instead as a stream
+of protobuf ImportRequest messages, each prefixed with its length as a uvarint
+and holding at most --chunk-size bits of one view. The stream can be loaded
+with "pilosa import --format proto" to rebuild the same fragments.
+
+The export can be restricted to the rows from --min-bitmap-id to
+--max-bitmap-id, and to the row IDs listed one per line in the --bitmap-ids
+file. Rows outside the selection are skipped on the server, so the output only
+holds part of the field.
+
+With --view, only the named view is exported, rather than the standard view
+of a CSV export or every view of a proto export. An output file ending in .gz
+is gzipped.
+
+With --checkpoint, the progress of the export is recorded in the given file
+after each shard is written. Running the same export again with the same
+checkpoint resumes after the last completed shard, discarding any partial
+shard at the end of the output file.
+
+With --concurrency, up to that many shards are fetched at once from the nodes
+which own them. The shards are buffered in memory until they can be written
+in order, so the output is the same as with the default of one.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return Exporter.Run(context.Background())
@@ -50,6 +74,14 @@ The file does not contain any headers.
 	flags.StringVarP(&Exporter.Index, "index", "i", "", "Pilosa index to export")
 	flags.StringVarP(&Exporter.Field, "field", "f", "", "Field to export")
 	flags.StringVarP(&Exporter.Path, "output-file", "o", "", "File to write export to - default stdout")
+	flags.StringVar(&Exporter.View, "view", "", "View to export - default standard for csv and all for proto")
+	flags.StringVar(&Exporter.Format, "format", ctl.ExportFormatCSV, "Format of the export. One of: csv, proto")
+	flags.IntVar(&Exporter.ChunkSize, "chunk-size", 65536, "Number of bits in each message of a proto export")
+	flags.Uint64Var(&Exporter.MinRowID, "min-bitmap-id", 0, "Lowest row ID to export")
+	flags.Uint64Var(&Exporter.MaxRowID, "max-bitmap-id", math.MaxUint64, "Highest row ID to export")
+	flags.StringVar(&Exporter.RowIDsPath, "bitmap-ids", "", "File of newline-delimited row IDs to export")
+	flags.StringVar(&Exporter.CheckpointPath, "checkpoint", "", "File recording the progress of the export, to resume after a failure")
+	flags.IntVar(&Exporter.Concurrency, "concurrency", 1, "Number of shards to fetch at once")
 	ctl.SetTLSConfig(flags, &Exporter.TLS.CertificatePath, &Exporter.TLS.CertificateKeyPath, &Exporter.TLS.SkipVerify)
 
 	return exportCmd
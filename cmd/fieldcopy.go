@@ -0,0 +1,64 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/ctl"
+)
+
+var FieldCopier *ctl.FieldCopyCommand
+
+func newFieldCopyCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	FieldCopier = ctl.NewFieldCopyCommand(stdin, stdout, stderr)
+	fieldCopyCmd := &cobra.Command{
+		Use:   "field-copy",
+		Short: "Copy a field into a new field.",
+		Long: `
+Creates a new field with the options of an existing field, optionally with a
+different cache, and copies the fragments and row attributes of the existing
+field into it. Each node copies its own data; the new field can't be queried
+or imported into until the copy has finished on every node.
+
+The new field may be in another index, which is created with the options of
+the source's index if it doesn't exist. An existing field is only replaced
+with --overwrite.
+
+Fields with keys can't be copied, nor can fields be copied between indexes
+with keys.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return FieldCopier.Run(context.Background())
+		},
+	}
+	flags := fieldCopyCmd.Flags()
+
+	flags.StringVarP(&FieldCopier.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&FieldCopier.Index, "index", "i", "", "Pilosa index")
+	flags.StringVarP(&FieldCopier.Field, "field", "f", "", "Field to copy")
+	flags.StringVarP(&FieldCopier.Destination, "destination", "d", "", "New field to copy into")
+	flags.StringVarP(&FieldCopier.DestinationIndex, "destination-index", "", "", "Index of the new field - default the source's")
+	flags.BoolVarP(&FieldCopier.Overwrite, "overwrite", "", false, "Replace the new field if it exists")
+	flags.StringVarP(&FieldCopier.CacheType, "cache-type", "", "", "Cache type of the new field - default the source's")
+	flags.Uint32VarP(&FieldCopier.CacheSize, "cache-size", "", 0, "Cache size of the new field - default the source's")
+	flags.DurationVarP(&FieldCopier.Interval, "interval", "", FieldCopier.Interval, "Time between progress checks")
+	ctl.SetTLSConfig(flags, &FieldCopier.TLS.CertificatePath, &FieldCopier.TLS.CertificateKeyPath, &FieldCopier.TLS.SkipVerify)
+
+	return fieldCopyCmd
+}
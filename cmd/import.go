@@ -20,6 +20,7 @@ import (
 
 	"github.com/pilosa/pilosa"
 	"github.com/pilosa/pilosa/ctl"
+	"github.com/pilosa/pilosa/http"
 	"github.com/spf13/cobra"
 )
 
@@ -38,8 +39,54 @@ The format of the CSV file is:
 
 	ROWID,COLUMNID,[TIME]
 
-The file should contain no headers. The TIME column is optional and can be
-omitted. If it is present then its format should be YYYY-MM-DDTHH:MM.
+The TIME column is optional and can be omitted. If it is present then its
+format should be YYYY-MM-DDTHH:MM.
+
+By default CSV files are read leniently: a byte order mark, carriage returns
+before newlines, blank lines and trailing commas are ignored, a first row
+whose IDs aren't numbers is skipped as a header, and malformed rows are
+logged and skipped. With --strict, any of these stops the import with the
+row number.
+
+With --attr-columns, bits also carry attributes of their rows, declared as a
+list of name:type pairs where the type is one of string, int, float or bool.
+The attributes are read from the CSV columns after the TIME column, in the
+order declared, or from the properties of each jsonl record with the same
+names. Empty or missing values are skipped. A row's attributes are only sent
+once per run, and when a row is given different values the last one wins and
+the conflict is logged.
+
+With --format jsonl, each line of the files is instead a JSON object such as:
+
+	{"bitmapID": 1, "profileID": 2, "timestamp": "2018-01-02T15:04"}
+
+The names of the properties are set with --bitmap-field, --profile-field and
+--time-field, other properties are ignored, and the timestamp is optional.
+Rows and columns are strings for fields and indexes which use keys.
+
+With --format columns, each line of the files instead holds a column and the
+rows it belongs to:
+
+	COLUMNID,ROWID1;ROWID2;...
+
+The separator of the rows is set with --list-delimiter. Each line is expanded
+into a bit per row as it is read, so lists may be arbitrarily long.
+
+With --format roaring-rows, the files instead contain whole rows of a set or
+time field as serialized roaring bitmaps. Each record is:
+
+	ROWID (uint64) SHARD (uint64) LENGTH (uint32) BITMAP (LENGTH bytes)
+
+The integers are little-endian and the bitmap, in the standard or pilosa
+roaring format, contains the offsets of the row's columns within the shard.
+Rows are only written to the standard view.
+
+With --format proto, the files are protobuf exports written by
+"pilosa export --format proto", and each bit is imported into the view it was
+exported from. The progress of each file is recorded in FILE.progress after
+every shard, and importing the same file again resumes after the last
+recorded shard unless --force is given. Messages for shards before
+--resume-from-shard are skipped.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			Importer.Paths = args
@@ -52,6 +99,7 @@ omitted. If it is present then its format should be YYYY-MM-DDTHH:MM.
 	flags.StringVarP(&Importer.Index, "index", "i", "", "Pilosa index to import into.")
 	flags.StringVarP(&Importer.Field, "field", "f", "", "Field to import into.")
 	flags.BoolVar(&Importer.IndexOptions.Keys, "index-keys", false, "Specify keys=true when creating an index")
+	flags.Uint64Var(&Importer.IndexOptions.ShardWidth, "index-shard-width", 0, "Specify the shard width, a power of 2, when creating an index")
 	flags.BoolVar(&Importer.FieldOptions.Keys, "field-keys", false, "Specify keys=true when creating a field")
 	flags.StringVar(&Importer.FieldOptions.Type, "field-type", "", "Specify the field type when creating a field. One of: set, int, time, bool, mutex")
 	flags.Int64Var(&Importer.FieldOptions.Min, "field-min", 0, "Specify the minimum for an int field on creation")
@@ -61,8 +109,19 @@ omitted. If it is present then its format should be YYYY-MM-DDTHH:MM.
 	flags.Var(&Importer.FieldOptions.TimeQuantum, "field-time-quantum", "Specify the time quantum for a time field on creation. One of: D, DH, H, M, MD, MDH, Y, YM, YMD, YMDH")
 	flags.IntVarP(&Importer.BufferSize, "buffer-size", "s", 10000000, "Number of bits to buffer/sort before importing.")
 	flags.BoolVarP(&Importer.Sort, "sort", "", false, "Enables sorting before import.")
+	flags.BoolVar(&Importer.Strict, "strict", false, "Stop at the first irregular or malformed CSV row instead of skipping it.")
+	flags.IntVar(&Importer.MaxErrors, "max-errors", -1, "Number of malformed CSV rows to skip before failing the import, or -1 for no limit.")
+	flags.BoolVar(&Importer.Compress, "compress", false, "Gzip import requests to servers which accept compressed imports.")
 	flags.BoolVarP(&Importer.CreateSchema, "create", "e", false, "Create the schema if it does not exist before import.")
 	flags.BoolVarP(&Importer.Clear, "clear", "", false, "Clear the data provided in the import.")
+	flags.StringVar(&Importer.Format, "format", ctl.ImportFormatCSV, "Format of the import files. One of: csv, columns, jsonl, roaring-rows, proto. For roaring-rows the buffer size is in bytes.")
+	flags.StringVar(&Importer.RowField, "bitmap-field", http.DefaultJSONLinesRowField, "Property holding the row of each jsonl record.")
+	flags.StringVar(&Importer.ColumnField, "profile-field", http.DefaultJSONLinesColumnField, "Property holding the column of each jsonl record.")
+	flags.StringVar(&Importer.TimeField, "time-field", http.DefaultJSONLinesTimeField, "Property holding the optional time of each jsonl record.")
+	flags.StringVar(&Importer.AttrColumns, "attr-columns", "", "Row attributes read alongside each bit, as name:type pairs. Types are string, int, float and bool.")
+	flags.Uint64Var(&Importer.ResumeFromShard, "resume-from-shard", 0, "Skip the shards of a proto import before this one.")
+	flags.BoolVar(&Importer.Force, "force", false, "Reimport the shards a proto import's progress file records as imported.")
+	flags.StringVar(&Importer.ListDelimiter, "list-delimiter", ";", "Separator of the rows on each line of a columns file.")
 	ctl.SetTLSConfig(flags, &Importer.TLS.CertificatePath, &Importer.TLS.CertificateKeyPath, &Importer.TLS.SkipVerify)
 
 	return importCmd
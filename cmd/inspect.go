@@ -30,12 +30,24 @@ func newInspectCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command
 	inspector = ctl.NewInspectCommand(stdin, stdout, stderr)
 
 	inspectCmd := &cobra.Command{
-		Use:   "inspect",
+		Use:   "inspect [path]",
 		Short: "Get stats on a pilosa data file.",
 		Long: `
 Inspects a data file and provides stats.
+
+With --index, inspects the cluster at --host instead: for each fragment of the
+index (or of the --field) which any node holds, it lists the nodes owning the
+fragment's shard, primary first, and whether each of them holds the fragment
+and its size on disk. --missing-only lists only the fragments which an owner
+doesn't hold.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if inspector.Index != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("path not allowed with --index")
+				}
+				return inspector.Run(context.Background())
+			}
 			if len(args) == 0 {
 				return fmt.Errorf("path required")
 			} else if len(args) > 1 {
@@ -45,5 +57,14 @@ Inspects a data file and provides stats.
 			return inspector.Run(context.Background())
 		},
 	}
+	flags := inspectCmd.Flags()
+
+	flags.StringVarP(&inspector.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&inspector.Index, "index", "i", "", "Pilosa index to inspect in the cluster")
+	flags.StringVarP(&inspector.Field, "field", "f", "", "Field to inspect - default every field of the index")
+	flags.BoolVarP(&inspector.MissingOnly, "missing-only", "", false, "Only list fragments an owner doesn't hold")
+	flags.BoolVarP(&inspector.JSON, "json", "", false, "Write JSON instead of a table")
+	ctl.SetTLSConfig(flags, &inspector.TLS.CertificatePath, &inspector.TLS.CertificateKeyPath, &inspector.TLS.SkipVerify)
+
 	return inspectCmd
 }
@@ -40,3 +40,10 @@ func TestInspectMultiPath(t *testing.T) {
 		t.Fatalf("Command 'inspect' without args should error but: err: '%v', output: '%v'", err, output)
 	}
 }
+
+func TestInspectIndexPath(t *testing.T) {
+	output, err := ExecNewRootCommand(t, "inspect", "--index", "i", "one")
+	if err == nil || !strings.Contains(err.Error(), "path not allowed") {
+		t.Fatalf("Command 'inspect' with an index and a path should error but: err: '%v', output: '%v'", err, output)
+	}
+}
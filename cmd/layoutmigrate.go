@@ -0,0 +1,50 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/ctl"
+)
+
+var LayoutMigrater *ctl.LayoutMigrateCommand
+
+func newLayoutMigrateCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	LayoutMigrater = ctl.NewLayoutMigrateCommand(stdin, stdout, stderr)
+	layoutMigrateCmd := &cobra.Command{
+		Use:   "layout-migrate",
+		Short: "Move fragment files to another layout.",
+		Long: `
+Moves the fragment files of every view in a data directory to the flat layout,
+fragments/<shard>, or the sharded layout, fragments/<shard % 256>/<shard>.
+
+Pilosa must not be running on the data directory. An interrupted migration is
+finished by running the command again or by starting Pilosa.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return LayoutMigrater.Run(context.Background())
+		},
+	}
+	flags := layoutMigrateCmd.Flags()
+
+	flags.StringVarP(&LayoutMigrater.DataDir, "data-dir", "d", "", "Pilosa data directory")
+	flags.StringVarP(&LayoutMigrater.Layout, "layout", "", "", "Layout to move fragments to: flat or sharded")
+
+	return layoutMigrateCmd
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/ctl"
+	"github.com/spf13/cobra"
+)
+
+var merger *ctl.MergeCommand
+
+// newMergeCommand runs the Pilosa merge subcommand for merging fragments.
+func newMergeCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	merger = ctl.NewMergeCommand(stdin, stdout, stderr)
+	mergeCmd := &cobra.Command{
+		Use:   "merge [flags] PATH PATH",
+		Short: "Merge two fragments of the same shard into a new fragment.",
+		Long: `Merges the fragment data files at the two PATHs, which must be of the same
+shard, and writes the union of their bits to a new fragment data file at
+--output-file, with a cache file of --cache-type and --cache-size rebuilt from
+the merged bits.
+
+With --index, the PATHs are data directories, and the fragment of --index,
+--field, --view and --shard is merged from each. A fragment which doesn't
+exist in one of them is copied from the other.
+
+The inputs are only read, and the output file must not exist. Pilosa must not
+be running on any of the data directories.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			merger.Paths = args
+			return merger.Run(context.Background())
+		},
+	}
+
+	flags := mergeCmd.Flags()
+	flags.StringVarP(&merger.OutputPath, "output-file", "o", "", "Fragment data file to write the merged fragment to.")
+	flags.StringVarP(&merger.Index, "index", "i", "", "Index of the fragment to merge from the data directories.")
+	flags.StringVarP(&merger.Field, "field", "f", "", "Field of the fragment to merge from the data directories.")
+	flags.StringVar(&merger.View, "view", "standard", "View of the fragment to merge from the data directories.")
+	flags.Uint64Var(&merger.Shard, "shard", 0, "Shard of the fragment to merge from the data directories.")
+	flags.StringVar(&merger.CacheType, "cache-type", pilosa.DefaultCacheType, "Cache type of the merged fragment: ranked, lru or none.")
+	flags.Uint32Var(&merger.CacheSize, "cache-size", pilosa.DefaultCacheSize, "Cache size of the merged fragment.")
+
+	return mergeCmd
+}
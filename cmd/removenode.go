@@ -0,0 +1,53 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/ctl"
+)
+
+var NodeRemover *ctl.RemoveNodeCommand
+
+func newRemoveNodeCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	NodeRemover = ctl.NewRemoveNodeCommand(stdin, stdout, stderr)
+	removeNodeCmd := &cobra.Command{
+		Use:   "remove-node",
+		Short: "Remove a node from the cluster, handing off its shards.",
+		Long: `
+Removes a node from the cluster while it keeps serving queries. The node is
+marked as leaving, and the nodes which own its shards once it's removed pull
+them from it. It's dropped from the cluster once they have all finished.
+
+The request is sent to the coordinator given by --host. A removal which fails
+or is interrupted by a restart is resumed by running the command again.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return NodeRemover.Run(context.Background())
+		},
+	}
+	flags := removeNodeCmd.Flags()
+
+	flags.StringVarP(&NodeRemover.Host, "host", "", "localhost:10101", "host:port of the coordinator.")
+	flags.StringVarP(&NodeRemover.ID, "id", "", "", "ID of the node to remove")
+	flags.DurationVarP(&NodeRemover.Interval, "interval", "", NodeRemover.Interval, "Time between progress checks")
+	ctl.SetTLSConfig(flags, &NodeRemover.TLS.CertificatePath, &NodeRemover.TLS.CertificateKeyPath, &NodeRemover.TLS.SkipVerify)
+
+	return removeNodeCmd
+}
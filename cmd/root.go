@@ -66,13 +66,20 @@ Build Time: ` + pilosa.BuildTime + "\n",
 	_ = rc.PersistentFlags().MarkHidden("dry-run")
 	rc.PersistentFlags().StringP("config", "c", "", "Configuration file to read from.")
 
+	rc.AddCommand(newBenchCommand(stdin, stdout, stderr))
 	rc.AddCommand(newCheckCommand(stdin, stdout, stderr))
 	rc.AddCommand(newConfigCommand(stdin, stdout, stderr))
 	rc.AddCommand(newExportCommand(stdin, stdout, stderr))
+	rc.AddCommand(newFieldCopyCommand(stdin, stdout, stderr))
 	rc.AddCommand(newGenerateConfigCommand(stdin, stdout, stderr))
 	rc.AddCommand(newImportCommand(stdin, stdout, stderr))
 	rc.AddCommand(newInspectCommand(stdin, stdout, stderr))
+	rc.AddCommand(newLayoutMigrateCommand(stdin, stdout, stderr))
+	rc.AddCommand(newMergeCommand(stdin, stdout, stderr))
+	rc.AddCommand(newRemoveNodeCommand(stdin, stdout, stderr))
 	rc.AddCommand(newServeCmd(stdin, stdout, stderr))
+	rc.AddCommand(newSortCommand(stdin, stdout, stderr))
+	rc.AddCommand(newTimeMigrateCommand(stdin, stdout, stderr))
 
 	rc.SetOutput(stderr)
 	return rc
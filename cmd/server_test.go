@@ -48,6 +48,8 @@ func TestServerConfig(t *testing.T) {
 	data-dir = "/tmp/myFileDatadir"
 	bind = "localhost:0"
 	max-writes-per-request = 3000
+	max-query-time = "45s"
+	max-const-row-columns = 500
 
 	[cluster]
 		disabled = true
@@ -56,6 +58,7 @@ func TestServerConfig(t *testing.T) {
 			"localhost:19444",
 		]
 		long-query-time = "1m10s"
+		disable-failover = true
 	`,
 			validation: func() error {
 				v := validator{}
@@ -64,7 +67,10 @@ func TestServerConfig(t *testing.T) {
 				v.Check(cmd.Server.Config.Cluster.ReplicaN, 2)
 				v.Check(cmd.Server.Config.Cluster.Hosts, []string{"localhost:10111", "localhost:10110"})
 				v.Check(cmd.Server.Config.Cluster.LongQueryTime, toml.Duration(time.Second*90))
+				v.Check(cmd.Server.Config.Cluster.DisableFailover, true)
 				v.Check(cmd.Server.Config.MaxWritesPerRequest, 2000)
+				v.Check(cmd.Server.Config.MaxQueryTime, toml.Duration(45*time.Second))
+				v.Check(cmd.Server.Config.MaxConstRowColumns, 500)
 				v.Check(cmd.Server.Config.Translation.MapSize, 100000)
 				return v.Error()
 			},
@@ -86,6 +92,16 @@ func TestServerConfig(t *testing.T) {
 				v := validator{}
 				v.Check(cmd.Server.Config.Cluster.Hosts, []string{"localhost:1110", "localhost:1111"})
 				v.Check(cmd.Server.Config.AntiEntropy.Interval, toml.Duration(time.Minute*9))
+				v.Check(cmd.Server.Config.AntiEntropy.RequestsPerSecond, 100)
+				v.Check(cmd.Server.Config.MaxOpN, 10000)
+				v.Check(cmd.Server.Config.FragmentCloseTimeout, toml.Duration(10*time.Second))
+				v.Check(cmd.Server.Config.FragmentOpenConcurrency, 0)
+				v.Check(cmd.Server.Config.FragmentOpenPolicy, "")
+				v.Check(cmd.Server.Config.FragmentIdleTimeout, toml.Duration(0))
+				v.Check(cmd.Server.Config.MaxOpenFragmentsPerView, 0)
+				v.Check(cmd.Server.Config.SnapshotWorkers, 2)
+				v.Check(cmd.Server.Config.MaxQueryTime, toml.Duration(0))
+				v.Check(cmd.Server.Config.MaxConstRowColumns, 100000)
 				v.Check(cmd.Server.Config.Translation.MapSize, 100000)
 				return v.Error()
 			},
@@ -103,6 +119,7 @@ func TestServerConfig(t *testing.T) {
 		]
 	[anti-entropy]
 		interval = "11m0s"
+		requests-per-second = 20
 	[metric]
 		service = "statsd"
 		host = "127.0.0.1:8125"
@@ -111,6 +128,7 @@ func TestServerConfig(t *testing.T) {
 				v := validator{}
 				v.Check(cmd.Server.Config.Cluster.Hosts, []string{"localhost:19444"})
 				v.Check(cmd.Server.Config.AntiEntropy.Interval, toml.Duration(time.Minute*11))
+				v.Check(cmd.Server.Config.AntiEntropy.RequestsPerSecond, 20)
 				v.Check(cmd.Server.Config.LogPath, logFile.Name())
 				v.Check(cmd.Server.Config.Metric.Service, "statsd")
 				v.Check(cmd.Server.Config.Metric.Host, "127.0.0.1:8125")
@@ -0,0 +1,68 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/ctl"
+	"github.com/spf13/cobra"
+)
+
+var sorter *ctl.SortCommand
+
+// newSortCommand runs the Pilosa sort subcommand for preparing import files.
+func newSortCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	sorter = ctl.NewSortCommand(stdin, stdout, stderr)
+	sortCmd := &cobra.Command{
+		Use:   "sort [flags] PATH",
+		Short: "Sort a CSV file by shard for faster import.",
+		Long: `Sorts a CSV file of bits, in the format read by "pilosa import", by shard
+and then by row, and writes it to --output-file. Importing the sorted file
+sends the bits of each shard together, without buffering and sorting them
+during the import.
+
+The format of the CSV file is:
+
+	ROWID,COLUMNID,[TIME]
+
+Files with more bits than --buffer-size are sorted in runs which are written
+to temporary files in --temp-dir, and then merged. The runs are removed when
+the sort ends. The input is read as leniently as by "pilosa import" unless
+--strict is given. A PATH or --output-file of "-" is standard input or output.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("path required")
+			} else if len(args) > 1 {
+				return fmt.Errorf("only one path allowed")
+			}
+			sorter.Path = args[0]
+			return sorter.Run(context.Background())
+		},
+	}
+
+	flags := sortCmd.Flags()
+	flags.StringVarP(&sorter.OutputPath, "output-file", "o", "", "File to write the sorted bits to.")
+	flags.IntVarP(&sorter.BufferSize, "buffer-size", "s", 10000000, "Number of bits to sort in memory at once.")
+	flags.Uint64Var(&sorter.ShardWidth, "shard-width", pilosa.ShardWidth, "Shard width of the index the file will be imported into.")
+	flags.BoolVar(&sorter.Strict, "strict", false, "Stop at the first irregular or malformed CSV row instead of skipping it.")
+	flags.StringVar(&sorter.TempDir, "temp-dir", "", "Directory of the sorted runs. Defaults to the system's temporary directory.")
+
+	return sortCmd
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pilosa/pilosa/ctl"
+)
+
+var TimeMigrater *ctl.TimeMigrateCommand
+
+func newTimeMigrateCommand(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
+	TimeMigrater = ctl.NewTimeMigrateCommand(stdin, stdout, stderr)
+	timeMigrateCmd := &cobra.Command{
+		Use:   "time-migrate",
+		Short: "Build the larger time views of a field.",
+		Long: `
+Builds the time views of larger units of a time field by unioning its views of
+a smaller unit, e.g. month views from day views. Use this after adding units to
+a field's time quantum so that existing data can be queried by them.
+
+Views of smaller units cannot be built from larger ones. The migration runs on
+every node of the cluster and continues when a node restarts.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return TimeMigrater.Run(context.Background())
+		},
+	}
+	flags := timeMigrateCmd.Flags()
+
+	flags.StringVarP(&TimeMigrater.Host, "host", "", "localhost:10101", "host:port of Pilosa.")
+	flags.StringVarP(&TimeMigrater.Index, "index", "i", "", "Pilosa index to migrate")
+	flags.StringVarP(&TimeMigrater.Field, "field", "f", "", "Time field to migrate")
+	flags.StringVarP(&TimeMigrater.From, "from", "", "", "Unit of the existing views, e.g. D")
+	flags.StringVarP(&TimeMigrater.To, "to", "", "", "Units of the views to build, e.g. YM - default all larger units of the field's quantum")
+	flags.DurationVarP(&TimeMigrater.Interval, "interval", "", TimeMigrater.Interval, "Time between progress checks")
+	ctl.SetTLSConfig(flags, &TimeMigrater.TLS.CertificatePath, &TimeMigrater.TLS.CertificateKeyPath, &TimeMigrater.TLS.SkipVerify)
+
+	return timeMigrateCmd
+}
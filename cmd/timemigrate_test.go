@@ -0,0 +1,57 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa/cmd"
+)
+
+func TestTimeMigrateHelp(t *testing.T) {
+	output, err := ExecNewRootCommand(t, "time-migrate", "--help")
+	if !strings.Contains(output, "Usage:") ||
+		!strings.Contains(output, "Flags:") ||
+		!strings.Contains(output, "pilosa time-migrate") || err != nil {
+		t.Fatalf("Command 'time-migrate --help' not working, err: '%v', output: '%s'", err, output)
+	}
+}
+
+func TestTimeMigrateConfig(t *testing.T) {
+	tests := []commandTest{
+		{
+			args: []string{"time-migrate", "--from", "D", "--interval", "5s"},
+			env:  map[string]string{"PILOSA_HOST": "localhost:12345"},
+			cfgFileContent: `
+index = "myindex"
+field = "f1"
+to = "YM"
+`,
+			validation: func() error {
+				v := validator{}
+				v.Check(cmd.TimeMigrater.Host, "localhost:12345")
+				v.Check(cmd.TimeMigrater.Index, "myindex")
+				v.Check(cmd.TimeMigrater.Field, "f1")
+				v.Check(cmd.TimeMigrater.From, "D")
+				v.Check(cmd.TimeMigrater.To, "YM")
+				v.Check(cmd.TimeMigrater.Interval, 5*time.Second)
+				return v.Error()
+			},
+		},
+	}
+	executeDry(t, tests)
+}
@@ -0,0 +1,436 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/http"
+	"github.com/pilosa/pilosa/server"
+	"github.com/pkg/errors"
+)
+
+// Bench operations.
+const (
+	BenchOpSetBit = "set-bit"
+	BenchOpQuery  = "query"
+)
+
+// Queries of a bench query mix.
+const (
+	benchQueryRow       = "Row"
+	benchQueryIntersect = "Intersect"
+	benchQueryTopN      = "TopN"
+)
+
+// BenchCommand represents a command for generating load on a server, by
+// importing random bits or querying existing ones, and reporting how fast it
+// was served.
+type BenchCommand struct {
+	// Remote host and port.
+	Host string
+
+	// Name of the index & field to load. Both are created if they don't
+	// exist.
+	Index string
+	Field string
+
+	// Operation to run, either "set-bit" or "query".
+	Op string
+
+	// Number of bits to set, or of queries to run.
+	N int
+
+	// Number of bits imported by each request of a set-bit run.
+	BatchSize int
+
+	// Rows and columns are drawn from [0, MaxRowID) and [0, MaxColumnID).
+	MaxRowID    uint64
+	MaxColumnID uint64
+
+	// Weights of the queries of a query run, given as "name:weight,...".
+	// Queries are Row, Intersect and TopN.
+	QueryMix string
+
+	// Number of requests sent at once.
+	Concurrency int
+
+	// Seed of the random rows, columns and queries. Zero uses a seed from
+	// the time, which is printed so that the run can be repeated.
+	Seed int64
+
+	// Reusable client.
+	client *http.InternalClient
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewBenchCommand returns a new instance of BenchCommand.
+func NewBenchCommand(stdin io.Reader, stdout, stderr io.Writer) *BenchCommand {
+	return &BenchCommand{
+		Op:          BenchOpSetBit,
+		N:           100000,
+		BatchSize:   10000,
+		MaxRowID:    1000,
+		MaxColumnID: 10 * pilosa.ShardWidth,
+		QueryMix:    "Row:1,Intersect:1,TopN:1",
+		Concurrency: 1,
+		CmdIO:       pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// benchOp is a request of a run: the bits of an import, or a query and its
+// name in the mix.
+type benchOp struct {
+	bits  []pilosa.Bit
+	name  string
+	query string
+}
+
+// benchResult is the outcome of a request of a run.
+type benchResult struct {
+	name     string
+	duration time.Duration
+	err      error
+}
+
+// Run generates the operations, sends them, and reports their throughput and
+// latencies.
+func (cmd *BenchCommand) Run(ctx context.Context) error {
+	// Validate arguments.
+	if cmd.Index == "" {
+		return pilosa.ErrIndexRequired
+	} else if cmd.Field == "" {
+		return pilosa.ErrFieldRequired
+	} else if cmd.Op != BenchOpSetBit && cmd.Op != BenchOpQuery {
+		return fmt.Errorf("unknown op: %q", cmd.Op)
+	} else if cmd.N < 1 {
+		return errors.New("number of operations must be positive")
+	} else if cmd.Concurrency < 1 {
+		return errors.New("concurrency must be positive")
+	} else if cmd.MaxRowID == 0 || cmd.MaxColumnID == 0 {
+		return errors.New("max row and column IDs must be positive")
+	} else if cmd.Op == BenchOpSetBit && cmd.BatchSize < 1 {
+		return errors.New("batch size must be positive")
+	}
+	var mix []benchWeight
+	if cmd.Op == BenchOpQuery {
+		var err error
+		if mix, err = parseBenchQueryMix(cmd.QueryMix); err != nil {
+			return errors.Wrap(err, "parsing query mix")
+		}
+	}
+
+	// Create a client to the server.
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+	cmd.client = client
+
+	if err := cmd.client.EnsureIndex(ctx, cmd.Index, pilosa.IndexOptions{}); err != nil {
+		return errors.Wrap(err, "creating index")
+	} else if err := cmd.client.EnsureField(ctx, cmd.Index, cmd.Field); err != nil {
+		return errors.Wrap(err, "creating field")
+	}
+	shardWidth, err := cmd.shardWidth(ctx)
+	if err != nil {
+		return err
+	}
+
+	seed := cmd.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	fmt.Fprintf(cmd.Stdout, "seed: %d\n", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	// Operations are generated in order from a single source, so that a seed
+	// sends the same ones whatever the concurrency.
+	ops := make(chan benchOp)
+	go func() {
+		defer close(ops)
+		for i := 0; i < cmd.N; {
+			var op benchOp
+			if cmd.Op == BenchOpSetBit {
+				n := cmd.BatchSize
+				if n > cmd.N-i {
+					n = cmd.N - i
+				}
+				op = benchOp{name: BenchOpSetBit, bits: cmd.randomBits(rnd, n)}
+				i += n
+			} else {
+				op = cmd.randomQuery(rnd, mix)
+				i++
+			}
+			select {
+			case ops <- op:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		mu      sync.Mutex
+		results []benchResult
+		counts  pilosa.ImportCount
+		wg      sync.WaitGroup
+	)
+	start := time.Now()
+	for i := 0; i < cmd.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for op := range ops {
+				opStart := time.Now()
+				c, err := cmd.send(ctx, shardWidth, op)
+				d := time.Since(opStart)
+
+				mu.Lock()
+				results = append(results, benchResult{name: op.name, duration: d, err: err})
+				counts.Bits += c.Bits
+				counts.Changed += c.Changed
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cmd.report(results, elapsed, counts, mix)
+	return nil
+}
+
+// shardWidth returns the shard width of the index, from the schema.
+func (cmd *BenchCommand) shardWidth(ctx context.Context) (uint64, error) {
+	schema, err := cmd.client.Schema(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting schema")
+	}
+	for _, index := range schema {
+		if index.Name == cmd.Index && index.ShardWidth != 0 {
+			return index.ShardWidth, nil
+		}
+	}
+	return pilosa.ShardWidth, nil
+}
+
+// randomBits returns n bits of random rows and columns.
+func (cmd *BenchCommand) randomBits(rnd *rand.Rand, n int) []pilosa.Bit {
+	bits := make([]pilosa.Bit, n)
+	for i := range bits {
+		bits[i] = pilosa.Bit{
+			RowID:    uint64(rnd.Int63n(int64(cmd.MaxRowID))),
+			ColumnID: uint64(rnd.Int63n(int64(cmd.MaxColumnID))),
+		}
+	}
+	return bits
+}
+
+// randomQuery returns a query drawn from the mix, of random rows.
+func (cmd *BenchCommand) randomQuery(rnd *rand.Rand, mix []benchWeight) benchOp {
+	var total int
+	for _, w := range mix {
+		total += w.weight
+	}
+	var name string
+	n := rnd.Intn(total)
+	for _, w := range mix {
+		if n < w.weight {
+			name = w.name
+			break
+		}
+		n -= w.weight
+	}
+
+	row := func() uint64 { return uint64(rnd.Int63n(int64(cmd.MaxRowID))) }
+	var query string
+	switch name {
+	case benchQueryRow:
+		query = fmt.Sprintf("Row(%s=%d)", cmd.Field, row())
+	case benchQueryIntersect:
+		query = fmt.Sprintf("Intersect(Row(%s=%d), Row(%s=%d))", cmd.Field, row(), cmd.Field, row())
+	case benchQueryTopN:
+		query = fmt.Sprintf("TopN(%s, n=10)", cmd.Field)
+	}
+	return benchOp{name: name, query: query}
+}
+
+// send sends an operation, importing its bits by shard or running its query.
+func (cmd *BenchCommand) send(ctx context.Context, shardWidth uint64, op benchOp) (pilosa.ImportCount, error) {
+	if op.query != "" {
+		_, err := cmd.client.Query(ctx, cmd.Index, &pilosa.QueryRequest{Index: cmd.Index, Query: op.query})
+		return pilosa.ImportCount{}, err
+	}
+
+	var counts pilosa.ImportCount
+	for shard, chunk := range http.Bits(op.bits).GroupByShard(shardWidth) {
+		c, err := cmd.client.Import(ctx, cmd.Index, cmd.Field, shard, chunk)
+		if err != nil {
+			return counts, errors.Wrapf(err, "importing shard %d", shard)
+		}
+		counts.Bits += c.Bits
+		counts.Changed += c.Changed
+	}
+	return counts, nil
+}
+
+// report prints the throughput and latencies of a run, and of each query of
+// the mix of a query run.
+func (cmd *BenchCommand) report(results []benchResult, elapsed time.Duration, counts pilosa.ImportCount, mix []benchWeight) {
+	s := newBenchStats(results, elapsed)
+	if cmd.Op == BenchOpSetBit {
+		fmt.Fprintf(cmd.Stdout, "set-bit: %d bits (%d new) in %d batches, %d errors, %s\n", counts.Bits, counts.Changed, s.n, s.errors, elapsed)
+		fmt.Fprintf(cmd.Stdout, "throughput: %.1f bits/s, %.1f batches/s\n", rate(int(counts.Bits), elapsed), s.rate())
+	} else {
+		fmt.Fprintf(cmd.Stdout, "query: %d queries, %d errors, %s\n", s.n, s.errors, elapsed)
+		fmt.Fprintf(cmd.Stdout, "throughput: %.1f queries/s\n", s.rate())
+	}
+	fmt.Fprintf(cmd.Stdout, "latency: %s\n", s.latencies())
+
+	for _, w := range mix {
+		var named []benchResult
+		for _, r := range results {
+			if r.name == w.name {
+				named = append(named, r)
+			}
+		}
+		ns := newBenchStats(named, elapsed)
+		fmt.Fprintf(cmd.Stdout, "%s: %d queries, %d errors, latency: %s\n", w.name, ns.n, ns.errors, ns.latencies())
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(cmd.Stderr, "first error: %s\n", r.err)
+			break
+		}
+	}
+}
+
+func (cmd *BenchCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *BenchCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
+
+// benchWeight is the weight of a query of the mix.
+type benchWeight struct {
+	name   string
+	weight int
+}
+
+// parseBenchQueryMix parses a list of "name:weight" pairs. Names are matched
+// without regard to case, and each weight must be positive.
+func parseBenchQueryMix(s string) ([]benchWeight, error) {
+	var mix []benchWeight
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.Index(pair, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid query %q, must be name:weight", pair)
+		}
+		var name string
+		for _, n := range []string{benchQueryRow, benchQueryIntersect, benchQueryTopN} {
+			if strings.EqualFold(strings.TrimSpace(pair[:i]), n) {
+				name = n
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("unknown query %q, must be Row, Intersect or TopN", pair[:i])
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(pair[i+1:]))
+		if err != nil || weight < 1 {
+			return nil, fmt.Errorf("invalid weight of %s: %q, must be a positive integer", name, pair[i+1:])
+		}
+		mix = append(mix, benchWeight{name: name, weight: weight})
+	}
+	return mix, nil
+}
+
+// benchStats summarizes the results of a run: the number of operations, of
+// them which failed, and the latencies of those which succeeded.
+type benchStats struct {
+	n, errors               int
+	elapsed                 time.Duration
+	min, p50, p90, p99, max time.Duration
+}
+
+// newBenchStats returns the statistics of results which took elapsed.
+func newBenchStats(results []benchResult, elapsed time.Duration) benchStats {
+	s := benchStats{n: len(results), elapsed: elapsed}
+	durations := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		if r.err != nil {
+			s.errors++
+			continue
+		}
+		durations = append(durations, r.duration)
+	}
+	if len(durations) == 0 {
+		return s
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	s.min, s.max = durations[0], durations[len(durations)-1]
+	s.p50 = percentile(durations, 50)
+	s.p90 = percentile(durations, 90)
+	s.p99 = percentile(durations, 99)
+	return s
+}
+
+// rate returns the number of operations per second.
+func (s benchStats) rate() float64 {
+	return rate(s.n, s.elapsed)
+}
+
+// latencies returns the latencies formatted for printing.
+func (s benchStats) latencies() string {
+	return fmt.Sprintf("min=%s p50=%s p90=%s p99=%s max=%s", s.min, s.p50, s.p90, s.p99, s.max)
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// rate returns n per second of elapsed.
+func rate(n int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(n) / elapsed.Seconds()
+}
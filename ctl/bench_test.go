@@ -0,0 +1,153 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/test"
+)
+
+func TestBenchCommand_Validation(t *testing.T) {
+	buf := bytes.Buffer{}
+	stdin, stdout, stderr := GetIO(buf)
+
+	cm := NewBenchCommand(stdin, stdout, stderr)
+	if err := cm.Run(context.Background()); err != pilosa.ErrIndexRequired {
+		t.Fatalf("Command not working, expect: %s, actual: '%s'", pilosa.ErrIndexRequired, err)
+	}
+	cm.Index = "i"
+	if err := cm.Run(context.Background()); err != pilosa.ErrFieldRequired {
+		t.Fatalf("Command not working, expect: %s, actual: '%s'", pilosa.ErrFieldRequired, err)
+	}
+	cm.Field = "f"
+	cm.Op = "get-bit"
+	if err := cm.Run(context.Background()); err == nil || err.Error() != `unknown op: "get-bit"` {
+		t.Fatalf("Command not working, expect: unknown op, actual: '%s'", err)
+	}
+	cm.Op = BenchOpQuery
+	cm.QueryMix = "Row:1,Union:2"
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), `unknown query "Union"`) {
+		t.Fatalf("Command not working, expect: unknown query, actual: '%s'", err)
+	}
+}
+
+func TestParseBenchQueryMix(t *testing.T) {
+	if mix, err := parseBenchQueryMix("row:2, TopN:1,Intersect:3"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(mix, []benchWeight{{"Row", 2}, {"TopN", 1}, {"Intersect", 3}}) {
+		t.Fatalf("unexpected mix: %v", mix)
+	}
+	for _, s := range []string{"", "Row", "Row:0", "Row:x", "Count:1"} {
+		if _, err := parseBenchQueryMix(s); err == nil {
+			t.Fatalf("expected error parsing %q", s)
+		}
+	}
+}
+
+// Ensure the percentiles are of the latencies of the operations which
+// succeeded, and the rate of all of them.
+func TestBenchStats(t *testing.T) {
+	var results []benchResult
+	for i := 100; i > 0; i-- {
+		results = append(results, benchResult{duration: time.Duration(i) * time.Millisecond})
+	}
+	results = append(results, benchResult{duration: time.Hour, err: errors.New("marker")}, benchResult{err: errors.New("marker")})
+
+	s := newBenchStats(results, 2*time.Second)
+	if s.n != 102 || s.errors != 2 {
+		t.Fatalf("unexpected counts: %d, %d errors", s.n, s.errors)
+	} else if s.rate() != 51 {
+		t.Fatalf("unexpected rate: %f", s.rate())
+	} else if s.min != time.Millisecond || s.p50 != 50*time.Millisecond || s.p90 != 90*time.Millisecond || s.p99 != 99*time.Millisecond || s.max != 100*time.Millisecond {
+		t.Fatalf("unexpected latencies: %s", s.latencies())
+	}
+
+	if s := newBenchStats(results[:1], 0); s.p50 != 100*time.Millisecond || s.p99 != 100*time.Millisecond || s.rate() != 0 {
+		t.Fatalf("unexpected stats of one result: %s, rate %f", s.latencies(), s.rate())
+	} else if s := newBenchStats(nil, time.Second); s.n != 0 || s.max != 0 {
+		t.Fatalf("unexpected stats of no results: %d, %s", s.n, s.latencies())
+	}
+}
+
+func TestBenchCommand_Run(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+
+	bench := func(index, op string, concurrency int) string {
+		stdout := bytes.Buffer{}
+		cm := NewBenchCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+		cm.Host = cmd.API.Node().URI.HostPort()
+		cm.Index = index
+		cm.Field = "f"
+		cm.Op = op
+		cm.N = 100
+		cm.BatchSize = 30
+		cm.MaxRowID = 5
+		cm.MaxColumnID = 2 * pilosa.ShardWidth
+		cm.QueryMix = "Row:1,TopN:2"
+		cm.Concurrency = concurrency
+		cm.Seed = 7
+		if err := cm.Run(context.Background()); err != nil {
+			t.Fatalf("Bench Run doesn't work: %s", err)
+		}
+		return stdout.String()
+	}
+
+	// The index and field are created, and the same seed sets the same
+	// bits whatever the concurrency.
+	if out := bench("i", BenchOpSetBit, 3); !strings.Contains(out, "seed: 7\n") || !strings.Contains(out, "set-bit: 100 bits (100 new) in 4 batches, 0 errors") {
+		t.Fatalf("unexpected output: %s", out)
+	} else if out := bench("j", BenchOpSetBit, 1); !strings.Contains(out, "in 4 batches, 0 errors") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	for row := 0; row < 5; row++ {
+		query := &pilosa.QueryRequest{Query: fmt.Sprintf("Row(f=%d)", row)}
+		query.Index = "i"
+		i := cmd.MustQuery(t, query).Results[0].(*pilosa.Row).Columns()
+		query.Index = "j"
+		j := cmd.MustQuery(t, query).Results[0].(*pilosa.Row).Columns()
+		if len(i) == 0 || !reflect.DeepEqual(i, j) {
+			t.Fatalf("unexpected columns of row %d: %v, %v", row, i, j)
+		}
+	}
+
+	out := bench("i", BenchOpQuery, 3)
+	if !strings.Contains(out, "query: 100 queries, 0 errors") || !strings.Contains(out, "throughput: ") || !strings.Contains(out, "latency: min=") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	var n int
+	for _, line := range strings.Split(out, "\n") {
+		for _, name := range []string{"Row", "TopN"} {
+			var queries int
+			if strings.HasPrefix(line, name+": ") {
+				if _, err := fmt.Sscanf(line[len(name)+2:], "%d queries, 0 errors", &queries); err != nil {
+					t.Fatalf("unexpected line: %s", line)
+				}
+				n += queries
+			}
+		}
+	}
+	if n != 100 {
+		t.Fatalf("unexpected queries by name: %s", out)
+	}
+}
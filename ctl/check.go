@@ -18,22 +18,41 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/cespare/xxhash"
 	"github.com/pilosa/pilosa"
 	"github.com/pilosa/pilosa/roaring"
+	"github.com/pilosa/pilosa/server"
 	"github.com/pkg/errors"
 )
 
 // CheckCommand represents a command for performing consistency checks on data files.
 type CheckCommand struct {
-	// Data file paths.
+	// Data file paths. Directories are walked for the fragment and cache
+	// files they hold.
 	Paths []string
 
+	// Verbose prints the statistics of each file checked.
+	Verbose bool
+
+	// Schema compares the schemas of Hosts instead of checking data files.
+	Schema bool
+	Hosts  []string
+
 	// Standard input/output
 	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+
+	// Shard widths of the indexes checked, by index directory.
+	shardWidths map[string]uint64
 }
 
 // NewCheckCommand returns a new instance of CheckCommand.
@@ -43,30 +62,80 @@ func NewCheckCommand(stdin io.Reader, stdout, stderr io.Writer) *CheckCommand {
 	}
 }
 
-// Run executes the check command.
-func (cmd *CheckCommand) Run(_ context.Context) error {
-	for _, path := range cmd.Paths {
+// Run executes the check command. Each file is reported as ok or corrupt, and
+// an error is returned if any file is corrupt. Files are only read.
+func (cmd *CheckCommand) Run(ctx context.Context) error {
+	if cmd.Schema {
+		return cmd.checkSchema(ctx)
+	}
+
+	var n, corrupt int
+	var maxShard uint64
+	check := func(path string) {
+		var err error
 		switch filepath.Ext(path) {
 		case "":
-			if err := cmd.checkBitmapFile(path); err != nil {
-				return errors.Wrap(err, "checking bitmap")
+			err = cmd.checkBitmapFile(path)
+			if shard, perr := strconv.ParseUint(filepath.Base(path), 10, 64); perr == nil && shard > maxShard {
+				maxShard = shard
 			}
-
 		case ".cache":
-			if err := cmd.checkCacheFile(path); err != nil {
-				return errors.Wrap(err, "checking cache")
-			}
-
+			err = cmd.checkCacheFile(path)
 		case ".snapshotting":
-			if err := cmd.checkSnapshotFile(path); err != nil {
-				return errors.Wrap(err, "checking snapshot")
+			cmd.checkSnapshotFile(path)
+			return
+		default:
+			return
+		}
+		n++
+		if err != nil {
+			corrupt++
+			fmt.Fprintf(cmd.Stdout, "%s: corrupt: %s\n", path, err)
+		}
+	}
+
+	for _, path := range cmd.Paths {
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			if err := filepath.Walk(path, func(path string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				} else if fi.Mode().IsRegular() && isFragmentPath(path) {
+					check(path)
+				}
+				return nil
+			}); err != nil {
+				return errors.Wrapf(err, "walking %s", path)
 			}
+			continue
 		}
+		check(path)
 	}
 
+	if cmd.Verbose {
+		fmt.Fprintf(cmd.Stdout, "checked %d files, max shard %d\n", n, maxShard)
+	}
+	if corrupt > 0 {
+		return errors.Errorf("%d of %d files corrupt", corrupt, n)
+	}
 	return nil
 }
 
+// isFragmentPath returns true if path is the data, cache or snapshot file of
+// a fragment, in the fragments directory of a view or one of its
+// subdirectories in the sharded layout.
+func isFragmentPath(path string) bool {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) != "fragments" && filepath.Base(filepath.Dir(dir)) != "fragments" {
+		return false
+	}
+	name := filepath.Base(path)
+	if ext := filepath.Ext(name); ext == ".cache" || ext == ".snapshotting" {
+		name = strings.TrimSuffix(name, ext)
+	}
+	_, err := strconv.ParseUint(name, 10, 64)
+	return err == nil
+}
+
 // checkBitmapFile performs a consistency check on path for a roaring bitmap file.
 func (cmd *CheckCommand) checkBitmapFile(path string) error {
 	// Open file handle.
@@ -81,46 +150,172 @@ func (cmd *CheckCommand) checkBitmapFile(path string) error {
 		return errors.Wrap(err, "statting file")
 	}
 
-	// Memory map the file.
-	data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
-	if err != nil {
-		return errors.Wrap(err, "mmapping")
-	}
-	defer syscall.Munmap(data)
-
-	// Attach the mmap file to the bitmap.
+	// An empty file is a fragment which has never been written.
 	bm := roaring.NewBitmap()
-	if err := bm.UnmarshalBinary(data); err != nil {
-		return errors.Wrap(err, "unmarshalling")
+	if fi.Size() > 0 {
+		// Memory map the file read-only. Operations replayed from the op log
+		// are applied to copies of the mapped containers.
+		data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			return errors.Wrap(err, "mmapping")
+		}
+		defer syscall.Munmap(data)
+
+		// Attach the mmap file to the bitmap.
+		if err := bm.UnmarshalBinary(data); err != nil {
+			return errors.Wrap(err, "unmarshalling")
+		}
 	}
 
-	// Perform consistency check.
+	// Perform consistency check, printing every error found.
 	if err := bm.Check(); err != nil {
-		// Print returned errors.
-		switch err := err.(type) {
-		case roaring.ErrorList:
-			for i := range err {
-				fmt.Fprintf(cmd.Stdout, "%s: %s\n", path, err[i].Error())
+		if errs, ok := err.(roaring.ErrorList); ok && len(errs) > 1 {
+			for i := range errs {
+				fmt.Fprintf(cmd.Stdout, "%s: %s\n", path, errs[i].Error())
 			}
-		default:
-			fmt.Fprintf(cmd.Stdout, "%s: %s\n", path, err.Error())
 		}
+		return err
 	}
 
-	// Print success message if no errors were found.
-	fmt.Fprintf(cmd.Stdout, "%s: ok\n", path)
-
+	if !cmd.Verbose {
+		fmt.Fprintf(cmd.Stdout, "%s: ok\n", path)
+		return nil
+	}
+	shardWidth, err := cmd.shardWidth(path)
+	if err != nil {
+		return errors.Wrap(err, "reading index shard width")
+	}
+	var rows, containers int
+	lastRow := uint64(math.MaxUint64)
+	citer, _ := bm.Containers.Iterator(0)
+	for citer.Next() {
+		key, _ := citer.Value()
+		if row := (key << 16) / shardWidth; row != lastRow {
+			rows, lastRow = rows+1, row
+		}
+		containers++
+	}
+	fmt.Fprintf(cmd.Stdout, "%s: ok, rows=%d bits=%d containers=%d ops=%d\n", path, rows, bm.Count(), containers, bm.Info().OpN)
 	return nil
 }
 
-// checkCacheFile performs a consistency check on path for a cache file.
+// shardWidth returns the shard width of the index holding the fragment file
+// at path, from the meta file of the index. Fragment files outside of a data
+// directory have the default width.
+func (cmd *CheckCommand) shardWidth(path string) (uint64, error) {
+	// Fragments are in <index>/<field>/views/<view>/fragments, or a
+	// subdirectory of it.
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) != "fragments" {
+		dir = filepath.Dir(dir)
+	}
+	if filepath.Base(dir) != "fragments" || filepath.Base(filepath.Dir(filepath.Dir(dir))) != "views" {
+		return pilosa.ShardWidth, nil
+	}
+	dir = filepath.Dir(filepath.Dir(filepath.Dir(filepath.Dir(dir))))
+
+	if w, ok := cmd.shardWidths[dir]; ok {
+		return w, nil
+	}
+	w, err := pilosa.IndexShardWidth(dir)
+	if err != nil {
+		return 0, err
+	}
+	if cmd.shardWidths == nil {
+		cmd.shardWidths = make(map[string]uint64)
+	}
+	cmd.shardWidths[dir] = w
+	return w, nil
+}
+
+// checkCacheFile performs a consistency check on path for a cache file. A
+// cache written against other data than the fragment now holds isn't
+// corrupt, as it is rebuilt when the fragment is opened.
 func (cmd *CheckCommand) checkCacheFile(path string) error {
-	fmt.Fprintf(cmd.Stderr, "%s: ignoring cache file\n", path)
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "reading file")
+	}
+	checksum, ids, err := pilosa.DecodeCacheFile(buf)
+	if err != nil {
+		return errors.Wrap(err, "decoding")
+	}
+
+	status := "ok"
+	data, err := ioutil.ReadFile(strings.TrimSuffix(path, ".cache"))
+	if os.IsNotExist(err) {
+		status = "ok, no data file"
+	} else if err != nil {
+		return errors.Wrap(err, "reading data file")
+	} else if xxhash.Sum64(data) != checksum {
+		status = "ok, stale"
+	}
+
+	if cmd.Verbose {
+		fmt.Fprintf(cmd.Stdout, "%s: %s, rows=%d\n", path, status, len(ids))
+	} else {
+		fmt.Fprintf(cmd.Stdout, "%s: %s\n", path, status)
+	}
 	return nil
 }
 
-// checkSnapshotFile performs a consistency check on path for a snapshot file.
-func (cmd *CheckCommand) checkSnapshotFile(path string) error {
+// checkSnapshotFile reports a snapshot file left by an interrupted snapshot.
+// It is replaced by the next snapshot of its fragment.
+func (cmd *CheckCommand) checkSnapshotFile(path string) {
 	fmt.Fprintf(cmd.Stderr, "%s: ignoring snapshot file\n", path)
+}
+
+// checkSchema compares the schema of each host with the schema of the first
+// host and prints the differences.
+func (cmd *CheckCommand) checkSchema(ctx context.Context) error {
+	if len(cmd.Hosts) < 2 {
+		return errors.New("at least two hosts required")
+	}
+
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+
+	schemas := make([]*pilosa.CanonicalSchema, len(cmd.Hosts))
+	for i, host := range cmd.Hosts {
+		uri, err := pilosa.NewURIFromAddress(host)
+		if err != nil {
+			return errors.Wrapf(err, "parsing host %s", host)
+		}
+		if schemas[i], err = client.CanonicalSchema(ctx, uri); err != nil {
+			return errors.Wrapf(err, "getting schema from %s", host)
+		}
+		fmt.Fprintf(cmd.Stdout, "%s: checksum %s\n", host, schemas[i].Checksum)
+	}
+
+	var diverged []string
+	for i := 1; i < len(schemas); i++ {
+		if schemas[i].Checksum == schemas[0].Checksum {
+			continue
+		}
+		diverged = append(diverged, cmd.Hosts[i])
+
+		// Differences are reported from the other host's point of view.
+		d := pilosa.DiffSchemas(schemas[i].Indexes, schemas[0].Indexes)
+		for _, line := range d.Lines() {
+			fmt.Fprintf(cmd.Stdout, "%s: %s\n", cmd.Hosts[i], line)
+		}
+	}
+	if len(diverged) > 0 {
+		return errors.Errorf("schema differs from %s: %s", cmd.Hosts[0], strings.Join(diverged, ", "))
+	}
+	fmt.Fprintf(cmd.Stdout, "schemas match\n")
 	return nil
 }
+
+func (cmd *CheckCommand) TLSHost() string {
+	if len(cmd.Hosts) == 0 {
+		return ""
+	}
+	return cmd.Hosts[0]
+}
+
+func (cmd *CheckCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
@@ -26,6 +26,9 @@ import (
 	"testing"
 
 	"context"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/test"
 )
 
 func TestCheckCommand_RunCacheFile(t *testing.T) {
@@ -42,8 +45,10 @@ func TestCheckCommand_RunCacheFile(t *testing.T) {
 	var buf bytes.Buffer
 	io.Copy(&buf, r)
 
-	if !strings.Contains(buf.String(), "ignoring cache file") {
-		t.Fatalf("expect: ignoring cache file, actual: '%s'", err)
+	if err == nil || err.Error() != "1 of 1 files corrupt" {
+		t.Fatalf("expected corrupt error, got: %v", err)
+	} else if !strings.Contains(buf.String(), cacheFile+": corrupt: reading file:") {
+		t.Fatalf("unexpected output: %s", buf.String())
 	}
 }
 
@@ -73,6 +78,7 @@ func TestCheckCommand_Run(t *testing.T) {
 	}
 	file.Write([]byte("1234,1223"))
 	file.Close()
+	defer os.Remove(file.Name())
 
 	rder := []byte{}
 	stdin := bytes.NewReader(rder)
@@ -85,10 +91,133 @@ func TestCheckCommand_Run(t *testing.T) {
 	var buf bytes.Buffer
 	io.Copy(&buf, r)
 
-	if !strings.HasPrefix(err.Error(), "checking bitmap: unmarshalling: reading roaring header:") {
-		t.Fatalf("expect error: invalid roaring file, actual: '%s'", err)
+	if err == nil || err.Error() != "1 of 1 files corrupt" {
+		t.Fatalf("expected corrupt error, got: %v", err)
+	} else if !strings.Contains(buf.String(), file.Name()+": corrupt: unmarshalling: reading roaring header:") {
+		t.Fatalf("expect error: invalid roaring file, actual: '%s'", buf.String())
+	}
+}
+
+func TestCheckCommand_RunDir(t *testing.T) {
+	h := test.MustOpenHolder()
+	defer os.RemoveAll(h.Path)
+	idx := h.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{})
+	if _, err := idx.CreateField("f"); err != nil {
+		t.Fatal(err)
+	}
+	h.MustSetBits("i", "f", 1, 1, 2, 3)
+	h.MustSetBits("i", "f", 2, 3, pilosa.ShardWidth+1)
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	fragments := filepath.Join(h.Path, "i", "f", "views", "standard", "fragments")
+
+	var stdout bytes.Buffer
+	cm := NewCheckCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+	cm.Paths = []string{h.Path}
+	cm.Verbose = true
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s\n%s", err, stdout.String())
+	}
+	for _, exp := range []string{
+		filepath.Join(fragments, "0") + ": ok, rows=2 bits=4 containers=2",
+		filepath.Join(fragments, "1") + ": ok, rows=1 bits=1 containers=1",
+		"max shard 1",
+	} {
+		if !strings.Contains(stdout.String(), exp) {
+			t.Fatalf("expected %q in output: %s", exp, stdout.String())
+		}
+	}
+
+	// Corrupt the first fragment, leaving its cache stale.
+	before, err := ioutil.ReadFile(filepath.Join(fragments, "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(fragments, "0"), []byte("1234,1223"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	cm.Verbose = false
+	if err := cm.Run(context.Background()); err == nil || !strings.HasPrefix(err.Error(), "1 of ") {
+		t.Fatalf("expected corrupt error, got: %v", err)
+	}
+	for _, exp := range []string{
+		filepath.Join(fragments, "0") + ": corrupt: unmarshalling:",
+		filepath.Join(fragments, "1") + ": ok",
+	} {
+		if !strings.Contains(stdout.String(), exp) {
+			t.Fatalf("expected %q in output: %s", exp, stdout.String())
+		}
+	}
+
+	// Checking never modifies files.
+	if after, err := ioutil.ReadFile(filepath.Join(fragments, "1")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(before, after) {
+		t.Fatal("fragment modified by check")
+	}
+}
+
+// Ensure rows are counted by the shard width of their index.
+func TestCheckCommand_RunDir_ShardWidth(t *testing.T) {
+	h := test.MustOpenHolder()
+	defer os.RemoveAll(h.Path)
+	idx := h.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{ShardWidth: pilosa.MinShardWidth})
+	if _, err := idx.CreateField("f"); err != nil {
+		t.Fatal(err)
+	}
+	h.MustSetBits("i", "f", 1, 1)
+	h.MustSetBits("i", "f", 2, 1)
+	h.MustSetBits("i", "f", 3, 1)
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	cm := NewCheckCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+	cm.Paths = []string{h.Path}
+	cm.Verbose = true
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s\n%s", err, stdout.String())
+	}
+	exp := filepath.Join(h.Path, "i", "f", "views", "standard", "fragments", "0") + ": ok, rows=3 bits=3 containers=3"
+	if !strings.Contains(stdout.String(), exp) {
+		t.Fatalf("expected %q in output: %s", exp, stdout.String())
+	}
+}
+
+func TestCheckCommand_RunSchema(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c[0].MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	c[0].MustCreateField(t, "i", "f")
+
+	var stdout bytes.Buffer
+	cm := NewCheckCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+	cm.Schema = true
+	cm.Hosts = []string{c[0].API.Node().URI.HostPort(), c[1].API.Node().URI.HostPort()}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(stdout.String(), "schemas match") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+
+	// Create a field on the second node only.
+	if _, err := c[1].Server.Holder().Index("i").CreateField("g"); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "schema differs") {
+		t.Fatalf("expected schema differs error, got: %v", err)
+	} else if exp := cm.Hosts[1] + ": extra i/g"; !strings.Contains(stdout.String(), exp) {
+		t.Fatalf("expected %q in output: %s", exp, stdout.String())
+	}
+
+	cm.Hosts = cm.Hosts[:1]
+	if err := cm.Run(context.Background()); err == nil || err.Error() != "at least two hosts required" {
+		t.Fatalf("expected hosts error, got: %v", err)
 	}
-	//	Todo: need correct roaring file for happy path
 }
 
 // TempFileName generates a temporary filename with extension
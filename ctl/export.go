@@ -15,16 +15,31 @@
 package ctl
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
-	"log"
+	"io/ioutil"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/http"
 	"github.com/pilosa/pilosa/server"
 	"github.com/pkg/errors"
 )
 
+// Export formats.
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatProto = "proto"
+)
+
 // ExportCommand represents a command for bulk exporting data from a server.
 type ExportCommand struct {
 	// Remote host and port.
@@ -34,9 +49,33 @@ type ExportCommand struct {
 	Index string
 	Field string
 
-	// Filename to export to.
+	// If set, the view to export. Otherwise a CSV export holds the standard
+	// view and a proto export every view.
+	View string
+
+	// Filename to export to. The output is gzipped if it ends in ".gz".
 	Path string
 
+	// Format of the export, either "csv" or "proto".
+	Format string
+
+	// Number of bits in each message of a proto export.
+	ChunkSize int
+
+	// Only rows between MinRowID and MaxRowID, inclusive, are exported.
+	MinRowID uint64
+	MaxRowID uint64
+
+	// If set, a file of newline-delimited row IDs to restrict the export to.
+	RowIDsPath string
+
+	// If set, the file recording the progress of the export, so that an
+	// interrupted export resumes from the last completed shard.
+	CheckpointPath string
+
+	// Number of shards fetched at once. Shards are still written in order.
+	Concurrency int
+
 	// Standard input/output
 	*pilosa.CmdIO
 
@@ -46,32 +85,91 @@ type ExportCommand struct {
 // NewExportCommand returns a new instance of ExportCommand.
 func NewExportCommand(stdin io.Reader, stdout, stderr io.Writer) *ExportCommand {
 	return &ExportCommand{
-		CmdIO: pilosa.NewCmdIO(stdin, stdout, stderr),
+		CmdIO:       pilosa.NewCmdIO(stdin, stdout, stderr),
+		Format:      ExportFormatCSV,
+		ChunkSize:   65536,
+		MaxRowID:    math.MaxUint64,
+		Concurrency: 1,
 	}
 }
 
 // Run executes the export.
 func (cmd *ExportCommand) Run(ctx context.Context) error {
-	logger := log.New(cmd.Stderr, "", log.LstdFlags)
+	logger := cmd.Logger()
 
 	// Validate arguments.
 	if cmd.Index == "" {
 		return pilosa.ErrIndexRequired
 	} else if cmd.Field == "" {
 		return pilosa.ErrFieldRequired
+	} else if cmd.Format != ExportFormatCSV && cmd.Format != ExportFormatProto {
+		return fmt.Errorf("unknown format: %q", cmd.Format)
+	} else if cmd.Format == ExportFormatProto && cmd.ChunkSize <= 0 {
+		return errors.New("chunk size must be positive")
+	} else if cmd.MinRowID > cmd.MaxRowID {
+		return errors.New("min bitmap id must not be greater than max bitmap id")
+	} else if cmd.CheckpointPath != "" && cmd.Path == "" {
+		return errors.New("checkpoint requires an output file")
+	} else if cmd.Concurrency <= 0 {
+		return errors.New("concurrency must be positive")
+	}
+
+	// Restrict the export to the selected rows.
+	opts := []pilosa.ExportOption{pilosa.OptExportOptionsRowRange(cmd.MinRowID, cmd.MaxRowID)}
+	if cmd.RowIDsPath != "" {
+		rowIDs, err := readRowIDs(cmd.RowIDsPath)
+		if err != nil {
+			return errors.Wrap(err, "reading bitmap ids")
+		}
+		opts = append(opts, pilosa.OptExportOptionsRowIDs(rowIDs))
+		logger.Printf("exporting %d listed rows between %d and %d", len(rowIDs), cmd.MinRowID, cmd.MaxRowID)
+	} else if cmd.MinRowID != 0 || cmd.MaxRowID != math.MaxUint64 {
+		logger.Printf("exporting rows between %d and %d", cmd.MinRowID, cmd.MaxRowID)
+	}
+
+	if cmd.View != "" {
+		opts = append(opts, pilosa.OptExportOptionsView(cmd.View))
+		logger.Printf("exporting view %s", cmd.View)
+	}
+
+	// Resume from the checkpoint, if one has been written.
+	checkpoint := cmd.newCheckpoint()
+	if cmd.CheckpointPath != "" {
+		cp, err := readExportCheckpoint(cmd.CheckpointPath)
+		if err != nil {
+			return errors.Wrap(err, "reading checkpoint")
+		} else if cp != nil {
+			started := *cp
+			started.NextShard, started.Offset = 0, 0
+			if started != checkpoint {
+				return errors.New("checkpoint is for a different export")
+			}
+			checkpoint = *cp
+			logger.Printf("resuming from shard %d at offset %d", checkpoint.NextShard, checkpoint.Offset)
+		}
 	}
 
 	// Use output file, if specified.
 	// Otherwise use STDOUT.
 	var w io.Writer = cmd.Stdout
+	var file *os.File
 	if cmd.Path != "" {
-		f, err := os.Create(cmd.Path)
+		f, err := cmd.openOutput(checkpoint.Offset)
 		if err != nil {
-			return errors.Wrap(err, "creating file")
+			return err
 		}
 		defer f.Close()
 
-		w = f
+		w, file = f, f
+	}
+
+	// Compress a .gz output, writing each shard as a separate gzip member so
+	// that a checkpoint can truncate the output after any of them.
+	out := w
+	var gz *gzip.Writer
+	if strings.HasSuffix(cmd.Path, ".gz") {
+		gz = gzip.NewWriter(file)
+		out = gz
 	}
 
 	// Create a client to the server.
@@ -87,11 +185,30 @@ func (cmd *ExportCommand) Run(ctx context.Context) error {
 	}
 
 	// Export each shard.
-	for shard := uint64(0); shard <= maxShards[cmd.Index]; shard++ {
-		logger.Printf("exporting shard: %d", shard)
-		if err := client.ExportCSV(ctx, cmd.Index, cmd.Field, shard, w); err != nil {
-			return errors.Wrap(err, "exporting")
+	err = cmd.exportShards(ctx, client, checkpoint.NextShard, maxShards[cmd.Index], out, opts, func(shard uint64) error {
+		if gz != nil {
+			if err := gz.Close(); err != nil {
+				return errors.Wrap(err, "compressing")
+			}
+			gz.Reset(file)
+		}
+
+		// Record the completed shard once its data is on disk.
+		if cmd.CheckpointPath == "" {
+			return nil
+		}
+		if err := file.Sync(); err != nil {
+			return errors.Wrap(err, "syncing")
 		}
+		offset, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return errors.Wrap(err, "getting offset")
+		}
+		checkpoint.NextShard, checkpoint.Offset = shard+1, offset
+		return errors.Wrap(writeExportCheckpoint(cmd.CheckpointPath, checkpoint), "writing checkpoint")
+	})
+	if err != nil {
+		return err
 	}
 
 	// Close writer, if applicable.
@@ -104,6 +221,204 @@ func (cmd *ExportCommand) Run(ctx context.Context) error {
 	return nil
 }
 
+// exportShards writes the shards from first to last to w in order, calling
+// done after each one. With a concurrency above one, the following shards are
+// fetched into memory while the current one is written, so at most that many
+// shards are buffered at once.
+func (cmd *ExportCommand) exportShards(ctx context.Context, client *http.InternalClient, first, last uint64, w io.Writer, opts []pilosa.ExportOption, done func(shard uint64) error) error {
+	logger := cmd.Logger()
+
+	export := func(shard uint64, w io.Writer) error {
+		logger.Printf("exporting shard: %d", shard)
+		var err error
+		if cmd.Format == ExportFormatProto {
+			err = client.ExportProto(ctx, cmd.Index, cmd.Field, shard, cmd.ChunkSize, w, opts...)
+		} else {
+			err = client.ExportCSV(ctx, cmd.Index, cmd.Field, shard, w, opts...)
+		}
+		return errors.Wrapf(err, "exporting shard %d", shard)
+	}
+
+	if cmd.Concurrency == 1 {
+		for shard := first; shard <= last; shard++ {
+			if err := export(shard, w); err != nil {
+				return err
+			} else if err := done(shard); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Start fetching each shard once there is room for it in the queue. The
+	// queue holds the results in shard order, and the shard being written
+	// takes up the last place.
+	type exportedShard struct {
+		shard uint64
+		buf   bytes.Buffer
+		err   error
+	}
+	queue := make(chan chan *exportedShard, cmd.Concurrency-1)
+	go func() {
+		defer close(queue)
+		for shard := first; shard <= last; shard++ {
+			ch := make(chan *exportedShard, 1)
+			select {
+			case queue <- ch:
+			case <-ctx.Done():
+				return
+			}
+			go func(shard uint64) {
+				res := &exportedShard{shard: shard}
+				res.err = export(shard, &res.buf)
+				ch <- res
+			}(shard)
+		}
+	}()
+
+	for ch := range queue {
+		res := <-ch
+		if res.err != nil {
+			return res.err
+		} else if _, err := res.buf.WriteTo(w); err != nil {
+			return errors.Wrap(err, "writing")
+		} else if err := done(res.shard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newCheckpoint returns the checkpoint of an export which hasn't started.
+func (cmd *ExportCommand) newCheckpoint() exportCheckpoint {
+	return exportCheckpoint{
+		Index:      cmd.Index,
+		Field:      cmd.Field,
+		Format:     cmd.Format,
+		MinRowID:   cmd.MinRowID,
+		MaxRowID:   cmd.MaxRowID,
+		RowIDsPath: cmd.RowIDsPath,
+		View:       cmd.View,
+	}
+}
+
+// openOutput opens the output file to be written from offset. Anything after
+// offset, such as part of a shard written before the export was interrupted,
+// is truncated.
+func (cmd *ExportCommand) openOutput(offset int64) (*os.File, error) {
+	if offset == 0 {
+		f, err := os.Create(cmd.Path)
+		return f, errors.Wrap(err, "creating file")
+	}
+
+	f, err := os.OpenFile(cmd.Path, os.O_RDWR, 0666)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening file")
+	}
+	if fi, err := f.Stat(); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "getting file size")
+	} else if fi.Size() < offset {
+		f.Close()
+		return nil, fmt.Errorf("output is shorter than the checkpoint offset %d", offset)
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "truncating file")
+	} else if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "seeking")
+	}
+	return f, nil
+}
+
+// exportCheckpoint records the progress of an export. The shards before
+// NextShard have been written to the first Offset bytes of the output.
+type exportCheckpoint struct {
+	Index      string `json:"index"`
+	Field      string `json:"field"`
+	Format     string `json:"format"`
+	MinRowID   uint64 `json:"minRowID"`
+	MaxRowID   uint64 `json:"maxRowID"`
+	RowIDsPath string `json:"rowIDsPath,omitempty"`
+	View       string `json:"view,omitempty"`
+
+	NextShard uint64 `json:"nextShard"`
+	Offset    int64  `json:"offset"`
+}
+
+// readExportCheckpoint returns the checkpoint at path, or nil if there is none.
+func readExportCheckpoint(path string) (*exportCheckpoint, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cp exportCheckpoint
+	if err := json.Unmarshal(buf, &cp); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+	return &cp, nil
+}
+
+// writeExportCheckpoint atomically replaces the checkpoint at path.
+func writeExportCheckpoint(path string, cp exportCheckpoint) error {
+	buf, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrap(err, "encoding")
+	}
+	return replaceFile(path, buf)
+}
+
+// replaceFile atomically replaces the file at path by writing buf to a
+// temporary file which is synced and renamed over the old one.
+func replaceFile(path string, buf []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readRowIDs reads a file of row IDs, one per line. Blank lines are skipped.
+func readRowIDs(path string) ([]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rowIDs := make([]uint64, 0)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" {
+			continue
+		}
+		rowID, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bitmap id on line %d: %q", line, s)
+		}
+		rowIDs = append(rowIDs, rowID)
+	}
+	return rowIDs, scanner.Err()
+}
+
 func (cmd *ExportCommand) TLSHost() string {
 	return cmd.Host
 }
@@ -15,13 +15,27 @@
 package ctl
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/encoding/proto"
+	"github.com/pilosa/pilosa/roaring"
 	"github.com/pilosa/pilosa/test"
 )
 
@@ -61,3 +75,481 @@ func TestExportCommand_Run(t *testing.T) {
 		t.Fatalf("Export Run doesn't work: %s", err)
 	}
 }
+
+// Ensure a proto export imports back into identical fragments.
+func TestExportCommand_RunProto(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	ctx := context.Background()
+	for _, index := range []string{"i", "j"} {
+		cmd.MustCreateIndex(t, index, pilosa.IndexOptions{})
+		cmd.MustCreateField(t, index, "f", pilosa.OptFieldTypeTime("YMD"))
+	}
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: `
+		Set(1, f=1, 2018-01-02T00:00)
+		Set(3, f=1, 2018-01-02T00:00)
+		Set(1048581, f=1, 2018-03-04T05:00)
+		Set(7, f=2)
+		Set(3, f=2, 2019-06-07T00:00)
+	`})
+
+	file, err := ioutil.TempFile("", "export.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	ex := NewExportCommand(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+	ex.Host = cmd.API.Node().URI.HostPort()
+	ex.Index, ex.Field = "i", "f"
+	ex.Path = file.Name()
+	ex.Format = ExportFormatProto
+	ex.ChunkSize = 1
+	if err := ex.Run(ctx); err != nil {
+		t.Fatalf("Export Run with proto doesn't work: %s", err)
+	}
+
+	im := NewImportCommand(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+	im.Host = ex.Host
+	im.Index, im.Field = "j", "f"
+	im.Format = ImportFormatProto
+	im.Paths = []string{file.Name()}
+	if err := im.Run(ctx); err != nil {
+		t.Fatalf("Import Run with proto doesn't work: %s", err)
+	}
+
+	exp, err := cmd.API.ViewInfos(ctx, "i", "f", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	views, err := cmd.API.ViewInfos(ctx, "j", "f", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(views) != len(exp) || len(exp) != 9 {
+		t.Fatalf("expected %d views, got %d", len(exp), len(views))
+	}
+	if bits := fragmentBits(t, cmd, "j", "standard_20180102", 0); !reflect.DeepEqual(bits, []uint64{pilosa.ShardWidth + 1, pilosa.ShardWidth + 3}) {
+		t.Fatalf("unexpected bits in standard_20180102: %v", bits)
+	}
+	for _, info := range exp {
+		for shard := uint64(0); shard <= info.MaxShard; shard++ {
+			if a, b := fragmentBits(t, cmd, "i", info.Name, shard), fragmentBits(t, cmd, "j", info.Name, shard); !reflect.DeepEqual(a, b) {
+				t.Fatalf("view %s, shard %d: expected %v, got %v", info.Name, shard, a, b)
+			}
+		}
+	}
+}
+
+// Ensure a proto import interrupted partway through a file skips the shards
+// it already imported when it's run again.
+func TestImportCommand_RunProtoResume(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	ctx := context.Background()
+	for _, index := range []string{"i", "j"} {
+		cmd.MustCreateIndex(t, index, pilosa.IndexOptions{})
+		cmd.MustCreateField(t, index, "f", pilosa.OptFieldTypeDefault())
+	}
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf(`
+		Set(1, f=1)
+		Set(%d, f=1)
+		Set(%d, f=1)
+	`, pilosa.ShardWidth+1, 2*pilosa.ShardWidth+1)})
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "export.pb")
+
+	ex := NewExportCommand(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+	ex.Host = cmd.API.Node().URI.HostPort()
+	ex.Index, ex.Field = "i", "f"
+	ex.Path = path
+	ex.Format = ExportFormatProto
+	ex.ChunkSize = 1
+	if err := ex.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newImport := func() *ImportCommand {
+		im := NewImportCommand(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+		im.Host = ex.Host
+		im.Index, im.Field = "j", "f"
+		im.Format = ImportFormatProto
+		im.Paths = []string{path}
+		return im
+	}
+	shards := func() (a []uint64) {
+		for shard := uint64(0); shard < 3; shard++ {
+			if bits := fragmentBits(t, cmd, "j", "standard", shard); len(bits) != 0 {
+				a = append(a, shard)
+			}
+		}
+		return a
+	}
+	clearAll := func() {
+		cmd.MustQuery(t, &pilosa.QueryRequest{Index: "j", Query: fmt.Sprintf(`
+			Clear(1, f=1)
+			Clear(%d, f=1)
+			Clear(%d, f=1)
+		`, pilosa.ShardWidth+1, 2*pilosa.ShardWidth+1)})
+	}
+
+	// Interrupt the import in the middle of the last message. Shard 1 was
+	// imported, but isn't known to be complete until shard 2 starts.
+	if err := ioutil.WriteFile(path, data[:len(data)-1], 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := newImport().Run(ctx); err == nil || !strings.Contains(err.Error(), "reading message 3") {
+		t.Fatalf("expected truncated message error, got %v", err)
+	}
+	p, err := readImportProgress(importProgressPath(path))
+	if err != nil {
+		t.Fatal(err)
+	} else if p == nil || p.NextShard != 1 {
+		t.Fatalf("unexpected progress: %+v", p)
+	}
+	if a := shards(); !reflect.DeepEqual(a, []uint64{0, 1}) {
+		t.Fatalf("unexpected shards before resuming: %v", a)
+	}
+
+	// Clear the imported shards so that reimporting them would show.
+	clearAll()
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := newImport().Run(ctx); err != nil {
+		t.Fatal(err)
+	} else if a := shards(); !reflect.DeepEqual(a, []uint64{1, 2}) {
+		t.Fatalf("unexpected shards after resuming: %v", a)
+	}
+
+	// A completed import imports nothing when run again.
+	clearAll()
+	if err := newImport().Run(ctx); err != nil {
+		t.Fatal(err)
+	} else if a := shards(); len(a) != 0 {
+		t.Fatalf("unexpected shards after rerunning: %v", a)
+	}
+
+	// The progress can't be used by another import.
+	im := newImport()
+	im.Clear = true
+	if err := im.Run(ctx); err == nil || !strings.Contains(err.Error(), "different import") {
+		t.Fatalf("expected different import error, got %v", err)
+	}
+
+	// Forcing the import starts from the beginning, and the shard to resume
+	// from is honored.
+	im = newImport()
+	im.Force = true
+	im.ResumeFromShard = 1
+	if err := im.Run(ctx); err != nil {
+		t.Fatal(err)
+	} else if a := shards(); !reflect.DeepEqual(a, []uint64{1, 2}) {
+		t.Fatalf("unexpected shards after forcing: %v", a)
+	}
+}
+
+// fragmentBits returns the bits stored in a fragment of field f.
+func fragmentBits(t *testing.T, cmd *test.Command, index, view string, shard uint64) []uint64 {
+	t.Helper()
+	path := filepath.Join(cmd.Server.Holder().IndexPath(index), "f", "views", view, "fragments", strconv.FormatUint(shard, 10))
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	bm := roaring.NewBitmap()
+	if err := bm.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	return bm.Slice()
+}
+
+// Ensure an export only holds the rows selected by its filters.
+func TestExportCommand_RunFiltered(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeDefault())
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: `
+		Set(1, f=1)
+		Set(2, f=2)
+		Set(1048577, f=3)
+		Set(3, f=4)
+		Set(4, f=5)
+	`})
+
+	ids, err := ioutil.TempFile("", "bitmap-ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(ids.Name())
+	if _, err := ids.WriteString("5\n\n3\n1\n"); err != nil {
+		t.Fatal(err)
+	}
+	ids.Close()
+
+	for _, tt := range []struct {
+		min, max uint64
+		ids      string
+		exp      string
+	}{
+		{min: 2, max: 4, exp: "2,2\n4,3\n3,1048577\n"},
+		{min: 4, max: math.MaxUint64, exp: "4,3\n5,4\n"},
+		{max: math.MaxUint64, ids: ids.Name(), exp: "1,1\n5,4\n3,1048577\n"},
+		{min: 2, max: 4, ids: ids.Name(), exp: "3,1048577\n"},
+	} {
+		var stdout bytes.Buffer
+		ex := NewExportCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+		ex.Host = cmd.API.Node().URI.HostPort()
+		ex.Index, ex.Field = "i", "f"
+		ex.MinRowID, ex.MaxRowID, ex.RowIDsPath = tt.min, tt.max, tt.ids
+		if err := ex.Run(context.Background()); err != nil {
+			t.Fatalf("Export Run with filters doesn't work: %s", err)
+		} else if stdout.String() != tt.exp {
+			t.Fatalf("rows %d-%d %q: expected %q, got %q", tt.min, tt.max, tt.ids, tt.exp, stdout.String())
+		}
+	}
+}
+
+// Ensure an export can be restricted to a view and gzipped.
+func TestExportCommand_RunViewGzip(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("Y"))
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf(`
+		Set(1, f=1, 2018-01-02T00:00)
+		Set(%d, f=2, 2019-01-02T00:00)
+		Set(%d, f=3, 2018-06-07T00:00)
+	`, pilosa.ShardWidth+1, 2*pilosa.ShardWidth+2)})
+
+	dir, err := ioutil.TempDir("", "export-gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tt := range []struct {
+		view string
+		exp  string
+	}{
+		{exp: fmt.Sprintf("1,1\n2,%d\n3,%d\n", pilosa.ShardWidth+1, 2*pilosa.ShardWidth+2)},
+		{view: "standard_2018", exp: fmt.Sprintf("1,1\n3,%d\n", 2*pilosa.ShardWidth+2)},
+		{view: "standard_2017"},
+	} {
+		path := filepath.Join(dir, "export"+tt.view+".csv.gz")
+		ex := NewExportCommand(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+		ex.Host = cmd.API.Node().URI.HostPort()
+		ex.Index, ex.Field, ex.View = "i", "f", tt.view
+		ex.Path = path
+		ex.Concurrency = 2
+		if err := ex.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := ioutil.ReadAll(gz)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		} else if string(buf) != tt.exp {
+			t.Fatalf("view %q: expected %q, got %q", tt.view, tt.exp, buf)
+		}
+	}
+
+	// A proto export of a view only holds messages of that view.
+	var stdout bytes.Buffer
+	ex := NewExportCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+	ex.Host = cmd.API.Node().URI.HostPort()
+	ex.Index, ex.Field, ex.View = "i", "f", "standard_2019"
+	ex.Format = ExportFormatProto
+	if err := ex.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(&stdout)
+	var n int
+	for ; ; n++ {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			t.Fatal(err)
+		}
+		var req pilosa.ImportRequest
+		if err := (proto.Serializer{}).Unmarshal(msg, &req); err != nil {
+			t.Fatal(err)
+		} else if req.View != "standard_2019" || req.Shard != 1 {
+			t.Fatalf("unexpected message: view %s, shard %d", req.View, req.Shard)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 message, got %d", n)
+	}
+}
+
+// Ensure an interrupted export resumes from its checkpoint with the same
+// output as an uninterrupted one.
+func TestExportCommand_RunCheckpoint(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeDefault())
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf(
+		`Set(1, f=1) Set(%d, f=2) Set(%d, f=3) Set(%d, f=4)`, pilosa.ShardWidth+1, pilosa.ShardWidth+2, 3*pilosa.ShardWidth)})
+
+	dir, err := ioutil.TempDir("", "export-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, format := range []string{ExportFormatCSV, ExportFormatProto} {
+		newExport := func(path, checkpoint string, stderr io.Writer) *ExportCommand {
+			ex := NewExportCommand(&bytes.Buffer{}, &bytes.Buffer{}, stderr)
+			ex.Host = cmd.API.Node().URI.HostPort()
+			ex.Index, ex.Field = "i", "f"
+			ex.Format = format
+			ex.Path, ex.CheckpointPath = path, checkpoint
+			return ex
+		}
+
+		exp := filepath.Join(dir, format+".exp")
+		if err := newExport(exp, "", &bytes.Buffer{}).Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		// Kill the export as it starts shard 2, leaving part of a shard at
+		// the end of the output.
+		out, checkpoint := filepath.Join(dir, format+".out"), filepath.Join(dir, format+".checkpoint")
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := newExport(out, checkpoint, cancelOnLog{"exporting shard: 2", cancel}).Run(ctx); err == nil {
+			t.Fatalf("%s: expected the export to be interrupted", format)
+		}
+		f, err := os.OpenFile(out, os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err := f.WriteString("2,20"); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		var stderr bytes.Buffer
+		if err := newExport(out, checkpoint, &stderr).Run(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if !strings.Contains(stderr.String(), "resuming from shard 2") {
+			t.Fatalf("%s: expected the export to resume, got: %s", format, stderr.String())
+		}
+		if a, b := mustReadFile(t, exp), mustReadFile(t, out); !bytes.Equal(a, b) {
+			t.Fatalf("%s: expected %q, got %q", format, a, b)
+		}
+
+		// A checkpoint can't be used for a different export.
+		ex := newExport(out, checkpoint, &bytes.Buffer{})
+		ex.MinRowID = 2
+		if err := ex.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "different export") {
+			t.Fatalf("%s: expected checkpoint error, got: %v", format, err)
+		}
+	}
+}
+
+// Ensure an export fetching shards concurrently from several nodes writes the
+// same output as a serial one.
+func TestExportCommand_RunConcurrent(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c[0].MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	c[0].MustCreateField(t, "i", "f", pilosa.OptFieldTypeDefault())
+	var q bytes.Buffer
+	for shard := uint64(0); shard < 8; shard++ {
+		fmt.Fprintf(&q, "Set(%d, f=%d) Set(%d, f=%d) ", shard*pilosa.ShardWidth+shard, shard, shard*pilosa.ShardWidth+100, shard+1)
+	}
+	c[0].MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: q.String()})
+
+	// The shards must be spread across both nodes for the test to mean much.
+	hosts := make(map[string]struct{})
+	for shard := uint64(0); shard < 8; shard++ {
+		nodes, err := c[0].API.ShardNodes(context.Background(), "i", shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, node := range nodes {
+			hosts[node.URI.HostPort()] = struct{}{}
+		}
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("expected shards on 2 nodes, got %d", len(hosts))
+	}
+
+	for _, format := range []string{ExportFormatCSV, ExportFormatProto} {
+		export := func(concurrency int) []byte {
+			var stdout bytes.Buffer
+			ex := NewExportCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+			ex.Host = c[0].API.Node().URI.HostPort()
+			ex.Index, ex.Field = "i", "f"
+			ex.Format = format
+			ex.Concurrency = concurrency
+			if err := ex.Run(context.Background()); err != nil {
+				t.Fatalf("%s, concurrency %d: %s", format, concurrency, err)
+			}
+			return stdout.Bytes()
+		}
+
+		exp := export(1)
+		if len(exp) == 0 {
+			t.Fatalf("%s: expected output", format)
+		}
+		for _, concurrency := range []int{2, 3, 16} {
+			if got := export(concurrency); !bytes.Equal(exp, got) {
+				t.Fatalf("%s, concurrency %d: expected %q, got %q", format, concurrency, exp, got)
+			}
+		}
+	}
+
+	// A failing shard aborts the export, naming the shard and the node.
+	ex := NewExportCommand(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{})
+	ex.Host = c[0].API.Node().URI.HostPort()
+	ex.Index, ex.Field = "i", "nosuchfield"
+	ex.Concurrency = 4
+	if err := ex.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "exporting shard 0: export node: host=") {
+		t.Fatalf("expected shard error, got %v", err)
+	}
+}
+
+// cancelOnLog cancels a context when a log line containing s is written.
+type cancelOnLog struct {
+	s      string
+	cancel func()
+}
+
+func (w cancelOnLog) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), w.s) {
+		w.cancel()
+	}
+	return len(p), nil
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
@@ -0,0 +1,161 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/server"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// FieldCopyCommand represents a command for copying a field into a new field
+// inside the cluster, of the same index or of another.
+type FieldCopyCommand struct {
+	// Remote host and port.
+	Host string
+
+	// Name of the index, and of the fields to copy from and to.
+	Index       string
+	Field       string
+	Destination string
+
+	// Index of the destination. Empty is the source's index.
+	DestinationIndex string
+
+	// Replace the destination if it exists.
+	Overwrite bool
+
+	// Cache options of the destination. Empty or zero keeps the source's.
+	CacheType string
+	CacheSize uint32
+
+	// Time between progress checks.
+	Interval time.Duration
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewFieldCopyCommand returns a new instance of FieldCopyCommand.
+func NewFieldCopyCommand(stdin io.Reader, stdout, stderr io.Writer) *FieldCopyCommand {
+	return &FieldCopyCommand{
+		Interval: time.Second,
+		CmdIO:    pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run starts the copy on every node, waits for it to finish, then marks the
+// destination ready on every node.
+func (cmd *FieldCopyCommand) Run(ctx context.Context) error {
+	// Validate arguments.
+	if cmd.Index == "" {
+		return pilosa.ErrIndexRequired
+	} else if cmd.Field == "" {
+		return pilosa.ErrFieldRequired
+	} else if cmd.Destination == "" {
+		return errors.New("destination field required")
+	}
+
+	// Every node runs the copy under the same ID.
+	req := pilosa.FieldCopyRequest{
+		ID:          uuid.NewV4().String(),
+		Index:       cmd.DestinationIndex,
+		Field:       cmd.Destination,
+		SourceIndex: cmd.Index,
+		Source:      cmd.Field,
+		Overwrite:   cmd.Overwrite,
+	}
+	if req.Index == "" {
+		req.Index = cmd.Index
+	}
+	if cmd.CacheType != "" {
+		req.Options.CacheType = &cmd.CacheType
+	}
+	if cmd.CacheSize != 0 {
+		req.Options.CacheSize = &cmd.CacheSize
+	}
+
+	// Create a client to the server.
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+
+	nodes, err := client.Nodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting nodes")
+	}
+
+	// Each node copies its own fragments.
+	fmt.Fprintf(cmd.Stdout, "copy %s\n", req.ID)
+	for _, node := range nodes {
+		c, err := client.StartFieldCopy(ctx, &node.URI, req)
+		if err != nil {
+			return errors.Wrapf(err, "starting copy on %s", node.URI)
+		}
+		fmt.Fprintf(cmd.Stdout, "%s: copying %d fragments\n", node.URI, c.Fragments)
+	}
+
+	// Report the progress of each node until every node is done.
+	copied := make(map[string]int)
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cmd.Interval):
+		}
+
+		done = true
+		for _, node := range nodes {
+			c, err := client.FieldCopyByID(ctx, &node.URI, req.ID)
+			if err != nil {
+				return errors.Wrapf(err, "getting copy on %s", node.URI)
+			} else if c.Error != "" {
+				return errors.Errorf("copy failed on %s: %s", node.URI, c.Error)
+			}
+
+			if n, ok := copied[node.URI.String()]; !ok || n != c.Copied {
+				copied[node.URI.String()] = c.Copied
+				fmt.Fprintf(cmd.Stdout, "%s: %d/%d fragments\n", node.URI, c.Copied, c.Fragments)
+			}
+			done = done && c.Done
+		}
+	}
+
+	// The destination is only used once every node has its data.
+	for _, node := range nodes {
+		if _, err := client.FinishFieldCopy(ctx, &node.URI, req.Index, req.Field); err != nil {
+			return errors.Wrapf(err, "finishing copy on %s", node.URI)
+		}
+	}
+	fmt.Fprintf(cmd.Stdout, "%s/%s is ready\n", req.Index, req.Field)
+	return nil
+}
+
+func (cmd *FieldCopyCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *FieldCopyCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
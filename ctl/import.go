@@ -15,22 +15,40 @@
 package ctl
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"io/ioutil"
+	"math"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/encoding/proto"
 	"github.com/pilosa/pilosa/http"
+	"github.com/pilosa/pilosa/logger"
 	"github.com/pilosa/pilosa/server"
 	"github.com/pkg/errors"
 )
 
+// Import file formats.
+const (
+	ImportFormatCSV         = "csv"
+	ImportFormatColumns     = "columns"
+	ImportFormatJSONLines   = "jsonl"
+	ImportFormatRoaringRows = "roaring-rows"
+	ImportFormatProto       = "proto"
+)
+
 // ImportCommand represents a command for bulk importing data.
 type ImportCommand struct { // nolint: maligned
 	// Destination host and port.
@@ -61,9 +79,59 @@ type ImportCommand struct { // nolint: maligned
 	// Enables sorting of data file before import.
 	Sort bool `json:"sort"`
 
+	// Stops a csv import at the first irregular or malformed row, instead
+	// of tolerating irregularities and skipping malformed rows.
+	Strict bool `json:"strict"`
+
+	// Number of malformed csv rows skipped before the import fails, or no
+	// limit if negative.
+	MaxErrors int `json:"maxErrors"`
+
+	// Malformed rows skipped during the run, printed when it ends.
+	badRows []string
+
+	// Gzips import requests to servers which advertise that they accept
+	// compressed imports.
+	Compress bool `json:"compress"`
+
+	// Format of the data files, either "csv", "columns", "jsonl",
+	// "roaring-rows" or "proto".
+	Format string `json:"format"`
+
+	// Separator of the row IDs listed on each line of a columns file.
+	ListDelimiter string `json:"listDelimiter"`
+
+	// Attributes of each bit's row held by the columns after the time of a
+	// csv file, or by the named properties of a jsonl record, given as
+	// "name:type,...".
+	AttrColumns string `json:"attrColumns"`
+
+	// Row attributes read during the run.
+	attrs *rowAttrBatch
+
+	// Skips the messages of a proto import for shards before ResumeFromShard.
+	ResumeFromShard uint64 `json:"resumeFromShard"`
+
+	// Reimports the shards a proto import's progress file records as
+	// already imported.
+	Force bool `json:"force"`
+
+	// Names of the properties holding the row, column and time of each
+	// record of a jsonl file.
+	RowField    string `json:"rowField"`
+	ColumnField string `json:"columnField"`
+	TimeField   string `json:"timeField"`
+
 	// Reusable client.
 	client pilosa.InternalClient
 
+	// Shard width of the index, read from the schema.
+	shardWidth uint64
+
+	// Distinct bits imported during the run, and how many of them changed,
+	// as counted by the servers.
+	counts *pilosa.ImportCount
+
 	// Standard input/output
 	*pilosa.CmdIO
 
@@ -73,14 +141,20 @@ type ImportCommand struct { // nolint: maligned
 // NewImportCommand returns a new instance of ImportCommand.
 func NewImportCommand(stdin io.Reader, stdout, stderr io.Writer) *ImportCommand {
 	return &ImportCommand{
-		CmdIO:      pilosa.NewCmdIO(stdin, stdout, stderr),
-		BufferSize: 10000000,
+		CmdIO:         pilosa.NewCmdIO(stdin, stdout, stderr),
+		BufferSize:    10000000,
+		MaxErrors:     -1,
+		Format:        ImportFormatCSV,
+		ListDelimiter: ";",
+		RowField:      http.DefaultJSONLinesRowField,
+		ColumnField:   http.DefaultJSONLinesColumnField,
+		TimeField:     http.DefaultJSONLinesTimeField,
 	}
 }
 
 // Run executes the main program execution.
 func (cmd *ImportCommand) Run(ctx context.Context) error {
-	logger := log.New(cmd.Stderr, "", log.LstdFlags)
+	logger := cmd.Logger()
 
 	// Validate arguments.
 	// Index and field are validated early before the files are parsed.
@@ -91,11 +165,35 @@ func (cmd *ImportCommand) Run(ctx context.Context) error {
 	} else if len(cmd.Paths) == 0 {
 		return errors.New("path required")
 	}
+	switch cmd.Format {
+	case ImportFormatCSV, ImportFormatJSONLines, ImportFormatRoaringRows, ImportFormatProto:
+	case ImportFormatColumns:
+		if len(cmd.ListDelimiter) != 1 || strings.ContainsAny(cmd.ListDelimiter, ",\r\n") {
+			return fmt.Errorf("invalid list delimiter: %q", cmd.ListDelimiter)
+		}
+	default:
+		return fmt.Errorf("unknown format: %q", cmd.Format)
+	}
+	if cmd.ResumeFromShard != 0 && cmd.Format != ImportFormatProto {
+		return errors.New("resuming from a shard requires the proto format")
+	}
+	cmd.attrs = nil
+	if cmd.AttrColumns != "" {
+		if cmd.Format != ImportFormatCSV && cmd.Format != ImportFormatJSONLines {
+			return fmt.Errorf("attribute columns are not supported by the %s format", cmd.Format)
+		}
+		columns, err := parseAttrColumns(cmd.AttrColumns)
+		if err != nil {
+			return errors.Wrap(err, "parsing attribute columns")
+		}
+		cmd.attrs = newRowAttrBatch(columns)
+	}
 	// Create a client to the server.
 	client, err := commandClient(cmd)
 	if err != nil {
 		return errors.Wrap(err, "creating client")
 	}
+	client.SetImportCompression(cmd.Compress)
 	cmd.client = client
 
 	if cmd.CreateSchema {
@@ -123,9 +221,13 @@ func (cmd *ImportCommand) Run(ctx context.Context) error {
 	}
 
 	var useColumnKeys, useRowKeys bool
+	cmd.shardWidth = pilosa.ShardWidth
 	for _, index := range schema {
 		if index.Name == cmd.Index {
 			useColumnKeys = index.Options.Keys
+			if index.ShardWidth != 0 {
+				cmd.shardWidth = index.ShardWidth
+			}
 			for _, field := range index.Fields {
 				if field.Name == cmd.Field {
 					useRowKeys = field.Options.Keys
@@ -137,7 +239,13 @@ func (cmd *ImportCommand) Run(ctx context.Context) error {
 		}
 	}
 
+	if cmd.attrs != nil && fieldType == pilosa.FieldTypeInt {
+		return errors.New("attribute columns are not supported for int fields")
+	}
+
 	// Import each path and import by shard.
+	cmd.badRows = nil
+	defer cmd.printBadRows()
 	for _, path := range cmd.Paths {
 		logger.Printf("parsing: %s", path)
 		if err := cmd.importPath(ctx, fieldType, useColumnKeys, useRowKeys, path); err != nil {
@@ -145,6 +253,16 @@ func (cmd *ImportCommand) Run(ctx context.Context) error {
 		}
 	}
 
+	if cmd.attrs != nil && cmd.attrs.conflicts > 0 {
+		logger.Printf("%d conflicting row attribute values were replaced by later ones", cmd.attrs.conflicts)
+	}
+
+	// Bits already set, or already clear, are counted but not new.
+	if c := cmd.counts; c != nil && cmd.Clear {
+		fmt.Fprintf(cmd.Stdout, "cleared %d bits (%d were set)\n", c.Bits, c.Changed)
+	} else if c != nil {
+		fmt.Fprintf(cmd.Stdout, "imported %d bits (%d new)\n", c.Bits, c.Changed)
+	}
 	return nil
 }
 
@@ -162,6 +280,33 @@ func (cmd *ImportCommand) ensureSchema(ctx context.Context) error {
 
 // importPath parses a path into bits and imports it to the server.
 func (cmd *ImportCommand) importPath(ctx context.Context, fieldType string, useColumnKeys, useRowKeys bool, path string) error {
+	if cmd.Format == ImportFormatJSONLines {
+		if fieldType == pilosa.FieldTypeInt {
+			return fmt.Errorf("%s format is not supported for int fields", cmd.Format)
+		}
+		return cmd.bufferJSONLines(ctx, useColumnKeys, useRowKeys, path)
+	}
+	if cmd.Format == ImportFormatColumns {
+		if fieldType == pilosa.FieldTypeInt {
+			return fmt.Errorf("%s format is not supported for int fields", cmd.Format)
+		}
+		return cmd.bufferColumns(ctx, useColumnKeys, useRowKeys, path)
+	}
+	if cmd.Format == ImportFormatProto {
+		if fieldType == pilosa.FieldTypeInt {
+			return fmt.Errorf("%s format is not supported for int fields", cmd.Format)
+		}
+		return cmd.importProto(ctx, path)
+	}
+	if cmd.Format == ImportFormatRoaringRows {
+		if fieldType != pilosa.FieldTypeSet && fieldType != pilosa.FieldTypeTime {
+			return fmt.Errorf("%s format is only supported for set and time fields", cmd.Format)
+		} else if useRowKeys {
+			return fmt.Errorf("%s format doesn't support row keys", cmd.Format)
+		}
+		return cmd.bufferRoaringRows(ctx, path)
+	}
+
 	// If fieldType is `int`, treat the import data as values to be range-encoded.
 	if fieldType == pilosa.FieldTypeInt {
 		return cmd.bufferValues(ctx, useColumnKeys, path)
@@ -173,8 +318,7 @@ func (cmd *ImportCommand) importPath(ctx context.Context, fieldType string, useC
 func (cmd *ImportCommand) bufferBits(ctx context.Context, useColumnKeys, useRowKeys bool, path string) error {
 	a := make([]pilosa.Bit, 0, cmd.BufferSize)
 
-	var r *csv.Reader
-
+	var input io.Reader = cmd.Stdin
 	if path != "-" {
 		// Open file for reading.
 		f, err := os.Open(path)
@@ -182,60 +326,236 @@ func (cmd *ImportCommand) bufferBits(ctx context.Context, useColumnKeys, useRowK
 			return errors.Wrap(err, "opening file")
 		}
 		defer f.Close()
+		input = f
+	}
 
-		// Read rows as bits.
-		r = csv.NewReader(f)
-	} else {
-		r = csv.NewReader(cmd.Stdin)
+	// Read rows as bits.
+	err := readCSVBits(input, cmd.newBadRows(path), useColumnKeys, useRowKeys, func(record []string, rnum int) error {
+		bit, attrs, err := cmd.parseBit(record, rnum, useColumnKeys, useRowKeys)
+		if err != nil {
+			return badRowError{err}
+		}
+		for i, v := range attrs {
+			if v == nil {
+				continue
+			} else if err := cmd.addRowAttr(ctx, bit, cmd.attrs.columns[i].name, v, rnum); err != nil {
+				return err
+			}
+		}
+
+		a = append(a, bit)
+
+		// If we've reached the buffer size then import bits.
+		if len(a) == cmd.BufferSize {
+			if err := cmd.importBits(ctx, useColumnKeys, useRowKeys, a); err != nil {
+				return err
+			}
+			a = a[:0]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	r.FieldsPerRecord = -1
-	rnum := 0
-	for {
-		rnum++
+	// If there are still bits in the buffer then flush them.
+	if err := cmd.importBits(ctx, useColumnKeys, useRowKeys, a); err != nil {
+		return err
+	}
+	return cmd.importRowAttrs(ctx)
+}
 
-		// Read CSV row.
+// readCSVBits reads the records of a CSV file of bits, passing each one to fn
+// with its line number. A header is skipped unless bad is strict. Errors from
+// reading a record, and those returned by fn, are reported to bad, which stops
+// the read at any that aren't badRowErrors, or at the first in strict mode.
+func readCSVBits(input io.Reader, bad *badRows, useColumnKeys, useRowKeys bool, fn func(record []string, rnum int) error) error {
+	r := newCSVReader(input, bad.strict)
+	for {
 		record, err := r.Read()
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			return errors.Wrap(err, "reading")
+			if err := bad.add(err); err != nil {
+				return err
+			}
+			continue
 		}
+		rnum := r.line
 
-		// Ignore blank rows.
-		if record[0] == "" {
+		// Skip a header, which is only recognized by its row and column not
+		// being numbers where IDs are expected.
+		if rnum == r.first && !bad.strict && isCSVHeader(record, !useRowKeys, !useColumnKeys) {
+			bad.logger.Printf("skipping header on row %d", rnum)
 			continue
-		} else if len(record) < 2 {
-			return fmt.Errorf("bad column count on row %d: col=%d", rnum, len(record))
 		}
 
-		var bit pilosa.Bit
-
-		// Parse row id.
-		if useRowKeys {
-			bit.RowKey = record[0]
-		} else {
-			if bit.RowID, err = strconv.ParseUint(record[0], 10, 64); err != nil {
-				return fmt.Errorf("invalid row id on row %d: %q", rnum, record[0])
+		if err := fn(record, rnum); err != nil {
+			if err := bad.add(err); err != nil {
+				return err
 			}
 		}
+	}
+	bad.summarize()
+	return nil
+}
+
+// parseCSVBit parses the row, column and time of a CSV record of a bit.
+func parseCSVBit(record []string, rnum int, useColumnKeys, useRowKeys bool) (pilosa.Bit, error) {
+	var bit pilosa.Bit
+	var err error
+
+	if len(record) < 2 {
+		return bit, fmt.Errorf("bad column count on row %d: col=%d", rnum, len(record))
+	}
+
+	// Parse row id.
+	if useRowKeys {
+		bit.RowKey = record[0]
+	} else {
+		if bit.RowID, err = strconv.ParseUint(record[0], 10, 64); err != nil {
+			return bit, fmt.Errorf("invalid row id on row %d: %q", rnum, record[0])
+		}
+	}
 
-		// Parse column id.
-		if useColumnKeys {
-			bit.ColumnKey = record[1]
-		} else {
-			if bit.ColumnID, err = strconv.ParseUint(record[1], 10, 64); err != nil {
-				return fmt.Errorf("invalid column id on row %d: %q", rnum, record[1])
+	// Parse column id.
+	if useColumnKeys {
+		bit.ColumnKey = record[1]
+	} else {
+		if bit.ColumnID, err = strconv.ParseUint(record[1], 10, 64); err != nil {
+			return bit, fmt.Errorf("invalid column id on row %d: %q", rnum, record[1])
+		}
+	}
+
+	// Parse time, if exists.
+	if len(record) > 2 && record[2] != "" {
+		t, err := parseImportTime(record[2])
+		if err != nil {
+			return bit, fmt.Errorf("invalid timestamp on row %d: %q", rnum, record[2])
+		} else if t.Before(time.Unix(0, 0)) {
+			return bit, fmt.Errorf("timestamp before the epoch on row %d: %q", rnum, record[2])
+		}
+		bit.Timestamp = t.UnixNano()
+	}
+	return bit, nil
+}
+
+// parseBit parses a CSV record of a bit into the bit and the values of its
+// row's attributes, which are nil where the record doesn't give one.
+func (cmd *ImportCommand) parseBit(record []string, rnum int, useColumnKeys, useRowKeys bool) (pilosa.Bit, []interface{}, error) {
+	bit, err := parseCSVBit(record, rnum, useColumnKeys, useRowKeys)
+	if err != nil {
+		return bit, nil, err
+	}
+
+	// Parse the row's attributes from the columns after the time.
+	if cmd.attrs == nil {
+		return bit, nil, nil
+	}
+	attrs := make([]interface{}, len(cmd.attrs.columns))
+	for i, col := range cmd.attrs.columns {
+		if len(record) <= 3+i || record[3+i] == "" {
+			continue
+		}
+		if attrs[i], err = col.parse(record[3+i]); err != nil {
+			return bit, nil, fmt.Errorf("invalid %s on row %d: %s", col.name, rnum, err)
+		}
+	}
+	return bit, attrs, nil
+}
+
+// bufferJSONLines buffers bits read from JSON lines to be imported as a batch.
+// The file is read a line at a time.
+func (cmd *ImportCommand) bufferJSONLines(ctx context.Context, useColumnKeys, useRowKeys bool, path string) error {
+	var r io.Reader = cmd.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+		r = f
+	}
+
+	jr := http.NewJSONLinesReader(r, http.JSONLinesFormat{
+		RowField:    cmd.RowField,
+		ColumnField: cmd.ColumnField,
+		TimeField:   cmd.TimeField,
+		RowKeys:     useRowKeys,
+		ColumnKeys:  useColumnKeys,
+	})
+
+	a := make([]pilosa.Bit, 0, cmd.BufferSize)
+	for {
+		bit, err := jr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "reading")
+		}
+
+		// Read the row's attributes from the record's properties.
+		if cmd.attrs != nil {
+			for _, col := range cmd.attrs.columns {
+				raw, ok := jr.Property(col.name)
+				if !ok {
+					continue
+				}
+				v, err := col.parseJSON(raw)
+				if err != nil {
+					return fmt.Errorf("invalid %s on line %d: %s", col.name, jr.Line(), err)
+				}
+				if err := cmd.addRowAttr(ctx, bit, col.name, v, jr.Line()); err != nil {
+					return err
+				}
 			}
 		}
 
-		// Parse time, if exists.
-		if len(record) > 2 && record[2] != "" {
-			t, err := time.Parse(pilosa.TimeFormat, record[2])
-			if err != nil {
-				return fmt.Errorf("invalid timestamp on row %d: %q", rnum, record[2])
+		a = append(a, bit)
+
+		// If we've reached the buffer size then import bits.
+		if len(a) == cmd.BufferSize {
+			if err := cmd.importBits(ctx, useColumnKeys, useRowKeys, a); err != nil {
+				return err
 			}
-			bit.Timestamp = t.UnixNano()
+			a = a[:0]
+		}
+	}
+
+	// If there are still bits in the buffer then flush them.
+	if err := cmd.importBits(ctx, useColumnKeys, useRowKeys, a); err != nil {
+		return err
+	}
+	return cmd.importRowAttrs(ctx)
+}
+
+// bufferColumns buffers bits expanded from lines of a column and its rows to
+// be imported as a batch.
+func (cmd *ImportCommand) bufferColumns(ctx context.Context, useColumnKeys, useRowKeys bool, path string) error {
+	var r io.Reader = cmd.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+		r = f
+	}
+
+	cr := &columnsReader{
+		r:          bufio.NewReader(r),
+		delim:      cmd.ListDelimiter[0],
+		rowKeys:    useRowKeys,
+		columnKeys: useColumnKeys,
+	}
+
+	a := make([]pilosa.Bit, 0, cmd.BufferSize)
+	for {
+		bit, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "reading")
 		}
 
 		a = append(a, bit)
@@ -253,22 +573,318 @@ func (cmd *ImportCommand) bufferBits(ctx context.Context, useColumnKeys, useRowK
 	return cmd.importBits(ctx, useColumnKeys, useRowKeys, a)
 }
 
+// columnsReader reads bits from lines of a column followed by a delimited
+// list of its rows, e.g. "profileID,bitmapID1;bitmapID2". Lines are read a
+// token at a time, so a long list is expanded without being held in memory.
+type columnsReader struct {
+	r          *bufio.Reader
+	delim      byte
+	rowKeys    bool
+	columnKeys bool
+
+	line   int
+	inList bool       // whether the rows of the current line are being read
+	column pilosa.Bit // column of the current line
+	token  []byte
+}
+
+// Read returns the next bit, or io.EOF when the stream is exhausted. Blank
+// lines and empty list items are skipped, and errors include the number of
+// the offending line.
+func (r *columnsReader) Read() (pilosa.Bit, error) {
+	for {
+		if !r.inList {
+			tok, term, err := r.next()
+			if err == io.EOF && len(tok) == 0 {
+				return pilosa.Bit{}, io.EOF
+			} else if err != nil && err != io.EOF {
+				return pilosa.Bit{}, err
+			}
+			r.line++
+
+			tok = bytes.TrimSpace(tok)
+			if term != ',' {
+				if len(tok) == 0 && term != r.delim {
+					continue
+				}
+				return pilosa.Bit{}, fmt.Errorf("expected a column and a list of rows on line %d", r.line)
+			}
+
+			r.column = pilosa.Bit{}
+			if r.columnKeys {
+				r.column.ColumnKey = string(tok)
+			} else if r.column.ColumnID, err = strconv.ParseUint(string(tok), 10, 64); err != nil {
+				return pilosa.Bit{}, fmt.Errorf("invalid column id on line %d: %q", r.line, tok)
+			}
+			r.inList = true
+		}
+
+		tok, term, err := r.next()
+		if err != nil && err != io.EOF {
+			return pilosa.Bit{}, err
+		} else if term == ',' {
+			return pilosa.Bit{}, fmt.Errorf("unexpected ',' in the list of rows on line %d", r.line)
+		} else if term != r.delim {
+			r.inList = false
+		}
+
+		if tok = bytes.TrimSpace(tok); len(tok) == 0 {
+			continue
+		}
+
+		bit := r.column
+		if r.rowKeys {
+			bit.RowKey = string(tok)
+		} else if bit.RowID, err = strconv.ParseUint(string(tok), 10, 64); err != nil {
+			return pilosa.Bit{}, fmt.Errorf("invalid row id on line %d: %q", r.line, tok)
+		}
+		return bit, nil
+	}
+}
+
+// next reads up to the next comma, list delimiter or newline. It returns
+// the token and the byte which ended it, which is 0 at the end of the stream.
+func (r *columnsReader) next() ([]byte, byte, error) {
+	r.token = r.token[:0]
+	for {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return r.token, 0, err
+		}
+		switch c {
+		case ',', r.delim, '\n':
+			return r.token, c, nil
+		}
+		r.token = append(r.token, c)
+	}
+}
+
+// addRowAttr buffers an attribute of the row of bit read from line, and sends
+// the buffered attributes once they cover rowAttrBatchSize rows.
+func (cmd *ImportCommand) addRowAttr(ctx context.Context, bit pilosa.Bit, name string, v interface{}, line int) error {
+	row := attrRow{id: bit.RowID, key: bit.RowKey}
+	if prev, ok := cmd.attrs.add(row, name, v); ok {
+		logger := cmd.Logger()
+		logger.Printf("conflicting %s of row %s on line %d: %v replaces %v", name, row, line, v, prev)
+	}
+	if len(cmd.attrs.order) < rowAttrBatchSize {
+		return nil
+	}
+	return cmd.importRowAttrs(ctx)
+}
+
+// importRowAttrs sends the buffered row attributes to the server.
+func (cmd *ImportCommand) importRowAttrs(ctx context.Context) error {
+	if cmd.attrs == nil || len(cmd.attrs.order) == 0 {
+		return nil
+	}
+	logger := cmd.Logger()
+	logger.Printf("importing row attributes: n=%d", len(cmd.attrs.order))
+
+	if _, err := cmd.client.Query(ctx, cmd.Index, &pilosa.QueryRequest{Query: cmd.attrs.query(cmd.Field)}); err != nil {
+		return errors.Wrap(err, "setting row attributes")
+	}
+	cmd.attrs.reset()
+	return nil
+}
+
+// rowAttrBatchSize is the number of rows whose attributes are set by each
+// query, which stays below the default limit of writes per request.
+const rowAttrBatchSize = 1000
+
+// attrColumn is a row attribute read alongside each bit.
+type attrColumn struct {
+	name string
+	typ  string
+}
+
+// parseAttrColumns parses a list of attributes of the form "name:type,...".
+func parseAttrColumns(s string) ([]attrColumn, error) {
+	var columns []attrColumn
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected name:type, got %q", item)
+		}
+		col := attrColumn{name: parts[0], typ: parts[1]}
+		if !attrNameRegexp.MatchString(col.name) {
+			return nil, fmt.Errorf("invalid attribute name: %q", col.name)
+		} else if seen[col.name] {
+			return nil, fmt.Errorf("duplicate attribute: %q", col.name)
+		}
+		switch col.typ {
+		case "string", "int", "float", "bool":
+		default:
+			return nil, fmt.Errorf("invalid type of attribute %s: %q", col.name, col.typ)
+		}
+		seen[col.name] = true
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// attrNameRegexp matches the attribute names accepted by PQL.
+var attrNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// parse returns the value of the attribute given as text.
+func (c attrColumn) parse(s string) (interface{}, error) {
+	switch c.typ {
+	case "int":
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", s)
+		}
+		return v, nil
+	case "float":
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("expected a number, got %q", s)
+		}
+		return v, nil
+	case "bool":
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, got %q", s)
+		}
+		return v, nil
+	default:
+		return s, nil
+	}
+}
+
+// parseJSON returns the value of the attribute given as JSON.
+func (c attrColumn) parseJSON(raw json.RawMessage) (interface{}, error) {
+	if c.typ != "string" {
+		return c.parse(string(raw))
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("expected a string, got %s", raw)
+	}
+	return s, nil
+}
+
+// attrRow identifies the row of an attribute by ID or key.
+type attrRow struct {
+	id  uint64
+	key string
+}
+
+func (r attrRow) String() string {
+	if r.key != "" {
+		return strconv.Quote(r.key)
+	}
+	return strconv.FormatUint(r.id, 10)
+}
+
+// rowAttrBatch buffers the row attributes read during an import. Values
+// already sent for a row aren't sent again.
+type rowAttrBatch struct {
+	columns []attrColumn
+
+	// Values read so far, by row and attribute name.
+	values map[attrRow]map[string]interface{}
+
+	// Values to be sent, by row in the order they were first buffered.
+	pending map[attrRow]map[string]interface{}
+	order   []attrRow
+
+	// Number of values which replaced a different one for the same row.
+	conflicts int
+}
+
+func newRowAttrBatch(columns []attrColumn) *rowAttrBatch {
+	return &rowAttrBatch{
+		columns: columns,
+		values:  make(map[attrRow]map[string]interface{}),
+		pending: make(map[attrRow]map[string]interface{}),
+	}
+}
+
+// add buffers an attribute value of row unless it has already been read. If a
+// different value was read before, the new one replaces it and the previous
+// value is returned.
+func (b *rowAttrBatch) add(row attrRow, name string, v interface{}) (prev interface{}, conflict bool) {
+	values := b.values[row]
+	if values == nil {
+		values = make(map[string]interface{})
+		b.values[row] = values
+	}
+	prev, conflict = values[name]
+	if conflict && prev == v {
+		return nil, false
+	} else if conflict {
+		b.conflicts++
+	}
+	values[name] = v
+
+	pending := b.pending[row]
+	if pending == nil {
+		pending = make(map[string]interface{})
+		b.pending[row] = pending
+		b.order = append(b.order, row)
+	}
+	pending[name] = v
+	return prev, conflict
+}
+
+// query returns SetRowAttrs() calls for the buffered attributes.
+func (b *rowAttrBatch) query(field string) string {
+	var buf bytes.Buffer
+	for _, row := range b.order {
+		fmt.Fprintf(&buf, "SetRowAttrs(%s, %s", field, row)
+		for _, col := range b.columns {
+			v, ok := b.pending[row][col.name]
+			if !ok {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				fmt.Fprintf(&buf, ", %s=%s", col.name, strconv.Quote(v))
+			case float64:
+				// Floats always have a decimal point so they aren't parsed
+				// as integers.
+				s := strconv.FormatFloat(v, 'f', -1, 64)
+				if !strings.Contains(s, ".") {
+					s += ".0"
+				}
+				fmt.Fprintf(&buf, ", %s=%s", col.name, s)
+			default:
+				fmt.Fprintf(&buf, ", %s=%v", col.name, v)
+			}
+		}
+		buf.WriteString(")\n")
+	}
+	return buf.String()
+}
+
+// reset empties the buffer after its attributes have been sent.
+func (b *rowAttrBatch) reset() {
+	b.pending = make(map[attrRow]map[string]interface{})
+	b.order = b.order[:0]
+}
+
 // importBits sends batches of bits to the server.
 func (cmd *ImportCommand) importBits(ctx context.Context, useColumnKeys, useRowKeys bool, bits []pilosa.Bit) error {
-	logger := log.New(cmd.Stderr, "", log.LstdFlags)
+	logger := cmd.Logger()
 
 	// If keys are used, all bits are sent to the primary translate store (i.e. coordinator).
 	if useColumnKeys || useRowKeys {
 		logger.Printf("importing keys: n=%d", len(bits))
-		if err := cmd.client.ImportK(ctx, cmd.Index, cmd.Field, bits, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+		counts, err := cmd.client.ImportK(ctx, cmd.Index, cmd.Field, bits, pilosa.OptImportOptionsClear(cmd.Clear))
+		if err != nil {
 			return errors.Wrap(err, "importing keys")
 		}
+		for _, c := range counts {
+			cmd.addImportCount(c)
+		}
 		return nil
 	}
 
 	// Group bits by shard.
 	logger.Printf("grouping %d bits", len(bits))
-	bitsByShard := http.Bits(bits).GroupByShard()
+	bitsByShard := http.Bits(bits).GroupByShard(cmd.shardWidth)
 
 	// Parse path into bits.
 	for shard, chunk := range bitsByShard {
@@ -277,71 +893,277 @@ func (cmd *ImportCommand) importBits(ctx context.Context, useColumnKeys, useRowK
 		}
 
 		logger.Printf("importing shard: %d, n=%d", shard, len(chunk))
-		if err := cmd.client.Import(ctx, cmd.Index, cmd.Field, shard, chunk, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+		c, err := cmd.client.Import(ctx, cmd.Index, cmd.Field, shard, chunk, pilosa.OptImportOptionsClear(cmd.Clear))
+		if err != nil {
 			return errors.Wrap(err, "importing")
 		}
+		cmd.addImportCount(c)
 	}
 
 	return nil
 }
 
-// bufferValues buffers slices of FieldValues to be imported as a batch.
-func (cmd *ImportCommand) bufferValues(ctx context.Context, useColumnKeys bool, path string) error {
-	a := make([]pilosa.FieldValue, 0, cmd.BufferSize)
+// addImportCount adds the counts of an import response to those of the run.
+func (cmd *ImportCommand) addImportCount(c pilosa.ImportCount) {
+	if cmd.counts == nil {
+		cmd.counts = &pilosa.ImportCount{}
+	}
+	cmd.counts.Bits += c.Bits
+	cmd.counts.Changed += c.Changed
+}
+
+// bufferRoaringRows reads a file of serialized roaring rows and imports them
+// by shard. Each record is the row ID and shard as little-endian uint64s, the
+// length of the bitmap as a little-endian uint32, and the roaring bitmap of the
+// row's column offsets within the shard. Rows are buffered until their bitmaps
+// reach BufferSize bytes.
+func (cmd *ImportCommand) bufferRoaringRows(ctx context.Context, path string) error {
+	var r io.Reader = cmd.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+		r = f
+	}
+	r = bufio.NewReader(r)
+
+	rows := make(map[uint64][]pilosa.ImportRoaringRow)
+	var n int
+	for rnum := 1; ; rnum++ {
+		var header [20]byte
+		if _, err := io.ReadFull(r, header[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "reading record %d", rnum)
+		}
+
+		row := pilosa.ImportRoaringRow{RowID: binary.LittleEndian.Uint64(header[0:8])}
+		shard := binary.LittleEndian.Uint64(header[8:16])
+		row.Data = make([]byte, binary.LittleEndian.Uint32(header[16:20]))
+		if _, err := io.ReadFull(r, row.Data); err != nil {
+			return errors.Wrapf(err, "reading bitmap of record %d", rnum)
+		}
+		rows[shard] = append(rows[shard], row)
 
-	var r *csv.Reader
+		// If we've reached the buffer size then import the rows.
+		if n += len(row.Data); n >= cmd.BufferSize {
+			if err := cmd.importRoaringRows(ctx, rows); err != nil {
+				return err
+			}
+			rows, n = make(map[uint64][]pilosa.ImportRoaringRow), 0
+		}
+	}
+
+	// If there are still rows in the buffer then flush them.
+	return cmd.importRoaringRows(ctx, rows)
+}
+
+// importRoaringRows sends buffered roaring rows to the server by shard.
+func (cmd *ImportCommand) importRoaringRows(ctx context.Context, rowsByShard map[uint64][]pilosa.ImportRoaringRow) error {
+	logger := cmd.Logger()
+
+	for shard, rows := range rowsByShard {
+		logger.Printf("importing shard: %d, rows=%d", shard, len(rows))
+		if err := cmd.client.ImportRoaringRows(ctx, cmd.Index, cmd.Field, shard, rows, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+			return errors.Wrap(err, "importing roaring rows")
+		}
+	}
+	return nil
+}
 
+// importProto imports a protobuf export, a stream of ImportRequest messages
+// each prefixed with its length as a uvarint. Each message is imported into
+// the view it names, in the command's index and field.
+//
+// Exports hold the messages of each shard in turn, so after the last message
+// of a shard is imported the progress of a file is recorded in a sidecar
+// file. Importing the same file again skips the shards it records, unless
+// the command is forced.
+func (cmd *ImportCommand) importProto(ctx context.Context, path string) error {
+	logger := cmd.Logger()
+
+	var r io.Reader = cmd.Stdin
+	var progressPath string
+	progress := cmd.newImportProgress()
 	if path != "-" {
-		// Open file for reading.
 		f, err := os.Open(path)
 		if err != nil {
 			return errors.Wrap(err, "opening file")
 		}
 		defer f.Close()
+		r = f
 
-		// Read rows as bits.
-		r = csv.NewReader(f)
-	} else {
-		r = csv.NewReader(cmd.Stdin)
+		progressPath = importProgressPath(path)
+		if !cmd.Force {
+			p, err := readImportProgress(progressPath)
+			if err != nil {
+				return errors.Wrap(err, "reading progress")
+			} else if p != nil {
+				started := *p
+				started.NextShard, started.Offset = 0, 0
+				if started != cmd.newImportProgress() {
+					return errors.New("progress file is for a different import, use --force to import anyway")
+				}
+				if fi, err := f.Stat(); err != nil {
+					return errors.Wrap(err, "getting file info")
+				} else if fi.Size() < p.Offset {
+					return fmt.Errorf("file is shorter than the progress offset %d", p.Offset)
+				}
+				if _, err := f.Seek(p.Offset, io.SeekStart); err != nil {
+					return errors.Wrap(err, "seeking")
+				}
+				progress = *p
+				logger.Printf("resuming from shard %d at offset %d", progress.NextShard, progress.Offset)
+			}
+		}
 	}
+	br := bufio.NewReader(r)
 
-	r.FieldsPerRecord = -1
-	rnum := 0
-	for {
-		rnum++
+	// Record the shards completed before offset, unless the input is stdin.
+	offset, shard, inShard := progress.Offset, uint64(0), false
+	record := func() error {
+		if progressPath == "" || !inShard {
+			return nil
+		}
+		progress.NextShard, progress.Offset = shard+1, offset
+		return errors.Wrap(writeImportProgress(progressPath, progress), "writing progress")
+	}
 
-		// Read CSV row.
-		record, err := r.Read()
+	var lenbuf [binary.MaxVarintLen64]byte
+	var skipped int
+	for num := 1; ; num++ {
+		size, err := binary.ReadUvarint(br)
 		if err == io.EOF {
-			break
+			if skipped > 0 {
+				logger.Printf("skipped %d messages before shard %d", skipped, cmd.ResumeFromShard)
+			}
+			return record()
 		} else if err != nil {
-			return errors.Wrap(err, "reading")
+			return errors.Wrapf(err, "reading length of message %d", num)
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return errors.Wrapf(err, "reading message %d", num)
+		}
+
+		var req pilosa.ImportRequest
+		if err := (proto.Serializer{}).Unmarshal(buf, &req); err != nil {
+			return errors.Wrapf(err, "decoding message %d", num)
+		} else if req.View == "" {
+			return fmt.Errorf("message %d has no view", num)
+		}
+		req.Index, req.Field = cmd.Index, cmd.Field
+
+		// The previous shard is complete once a message of another begins.
+		if inShard && req.Shard != shard {
+			if err := record(); err != nil {
+				return err
+			}
 		}
+		offset += int64(binary.PutUvarint(lenbuf[:], size)) + int64(size)
+		shard, inShard = req.Shard, true
 
-		// Ignore blank rows.
-		if record[0] == "" {
+		if req.Shard < cmd.ResumeFromShard {
+			skipped++
 			continue
-		} else if len(record) < 2 {
-			return fmt.Errorf("bad column count on row %d: col=%d", rnum, len(record))
 		}
+		logger.Printf("importing view: %s, shard: %d, n=%d", req.View, req.Shard, len(req.ColumnIDs))
+		if err := cmd.client.ImportView(ctx, &req, pilosa.OptImportOptionsClear(cmd.Clear)); err != nil {
+			return errors.Wrap(err, "importing")
+		}
+	}
+}
+
+// importProgress records the progress of a proto import. The shards before
+// NextShard have been imported from the first Offset bytes of the file.
+type importProgress struct {
+	Index string `json:"index"`
+	Field string `json:"field"`
+	Clear bool   `json:"clear"`
+
+	NextShard uint64 `json:"nextShard"`
+	Offset    int64  `json:"offset"`
+}
+
+// newImportProgress returns the progress of an import which hasn't started.
+func (cmd *ImportCommand) newImportProgress() importProgress {
+	return importProgress{Index: cmd.Index, Field: cmd.Field, Clear: cmd.Clear}
+}
+
+// importProgressPath returns the path of the progress file of an import file.
+func importProgressPath(path string) string {
+	return path + ".progress"
+}
+
+// readImportProgress returns the progress at path, or nil if there is none.
+func readImportProgress(path string) (*importProgress, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var p importProgress
+	if err := json.Unmarshal(buf, &p); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+	return &p, nil
+}
+
+// writeImportProgress atomically replaces the progress at path.
+func writeImportProgress(path string, p importProgress) error {
+	buf, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "encoding")
+	}
+	return replaceFile(path, buf)
+}
+
+// bufferValues buffers slices of FieldValues to be imported as a batch.
+func (cmd *ImportCommand) bufferValues(ctx context.Context, useColumnKeys bool, path string) error {
+	a := make([]pilosa.FieldValue, 0, cmd.BufferSize)
 
-		var val pilosa.FieldValue
+	var input io.Reader = cmd.Stdin
+	if path != "-" {
+		// Open file for reading.
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+		input = f
+	}
 
-		// Parse column id.
-		if useColumnKeys {
-			val.ColumnKey = record[0]
-		} else {
-			if val.ColumnID, err = strconv.ParseUint(record[0], 10, 64); err != nil {
-				return fmt.Errorf("invalid column id on row %d: %q", rnum, record[0])
+	r := newCSVReader(input, cmd.Strict)
+	bad := cmd.newBadRows(path)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if err := bad.add(err); err != nil {
+				return err
 			}
+			continue
 		}
+		rnum := r.line
 
-		// Parse FieldValue.
-		value, err := strconv.ParseInt(record[1], 10, 64)
+		// Skip a header, which is recognized by its value not being a number.
+		if rnum == r.first && !cmd.Strict && isCSVHeader(record, false, true) {
+			bad.logger.Printf("skipping header on row %d", rnum)
+			continue
+		}
+
+		val, err := parseValue(record, rnum, useColumnKeys)
 		if err != nil {
-			return fmt.Errorf("invalid value on row %d: %q", rnum, record[1])
+			if err := bad.add(badRowError{err}); err != nil {
+				return err
+			}
+			continue
 		}
-		val.Value = value
 
 		a = append(a, val)
 
@@ -353,14 +1175,197 @@ func (cmd *ImportCommand) bufferValues(ctx context.Context, useColumnKeys bool,
 			a = a[:0]
 		}
 	}
+	bad.summarize()
 
 	// If there are still values in the buffer then flush them.
 	return cmd.importValues(ctx, useColumnKeys, a)
 }
 
+// parseValue parses a CSV record of a column and its integer value.
+func parseValue(record []string, rnum int, useColumnKeys bool) (pilosa.FieldValue, error) {
+	var val pilosa.FieldValue
+	var err error
+
+	if len(record) < 2 {
+		return val, fmt.Errorf("bad column count on row %d: col=%d", rnum, len(record))
+	}
+
+	// Parse column id.
+	if useColumnKeys {
+		val.ColumnKey = record[0]
+	} else {
+		if val.ColumnID, err = strconv.ParseUint(record[0], 10, 64); err != nil {
+			return val, fmt.Errorf("invalid column id on row %d: %q", rnum, record[0])
+		}
+	}
+
+	// Parse FieldValue.
+	if val.Value, err = strconv.ParseInt(record[1], 10, 64); err != nil {
+		return val, fmt.Errorf("invalid value on row %d: %q", rnum, record[1])
+	}
+	return val, nil
+}
+
+// isCSVHeader returns true if none of the first fields of record which are
+// expected to be integers, as given by numeric, are integers.
+func isCSVHeader(record []string, numeric ...bool) bool {
+	var header bool
+	for i, ok := range numeric {
+		if !ok {
+			continue
+		} else if i >= len(record) {
+			return false
+		} else if _, err := strconv.ParseInt(record[i], 10, 64); err == nil {
+			return false
+		}
+		header = true
+	}
+	return header
+}
+
+// csvReader reads the records of a CSV file a line at a time. Unless strict
+// is set, a byte order mark at the start of the file, a carriage return at
+// the end of a line, blank lines and trailing empty fields are accepted and
+// removed. In strict mode each of them is an error.
+type csvReader struct {
+	r      *bufio.Reader
+	strict bool
+
+	line  int // number of the last line read
+	first int // number of the first line holding a record
+}
+
+func newCSVReader(r io.Reader, strict bool) *csvReader {
+	return &csvReader{r: bufio.NewReader(r), strict: strict}
+}
+
+// Read returns the fields of the next record, or io.EOF at the end of the
+// file. Errors in the contents of a line are returned as badRowErrors.
+func (r *csvReader) Read() ([]string, error) {
+	for {
+		line, err := r.r.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return nil, io.EOF
+		} else if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "reading")
+		}
+		r.line++
+		line = strings.TrimSuffix(line, "\n")
+
+		if r.line == 1 && strings.HasPrefix(line, "\ufeff") {
+			if r.strict {
+				return nil, badRowError{fmt.Errorf("byte order mark on row %d", r.line)}
+			}
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.HasSuffix(line, "\r") {
+			if r.strict {
+				return nil, badRowError{fmt.Errorf("carriage return at the end of row %d", r.line)}
+			}
+			line = strings.TrimSuffix(line, "\r")
+		}
+		if line == "" {
+			if r.strict {
+				return nil, badRowError{fmt.Errorf("blank row %d", r.line)}
+			}
+			continue
+		}
+
+		cr := csv.NewReader(strings.NewReader(line))
+		record, err := cr.Read()
+		if err != nil {
+			return nil, badRowError{fmt.Errorf("invalid CSV on row %d: %s", r.line, errors.Cause(err))}
+		}
+
+		if n := len(record); n > 2 && record[n-1] == "" {
+			if r.strict {
+				return nil, badRowError{fmt.Errorf("trailing comma on row %d", r.line)}
+			}
+			for n > 0 && record[n-1] == "" {
+				n--
+			}
+			record = record[:n]
+		}
+		if len(record) == 0 {
+			continue
+		}
+
+		if r.first == 0 {
+			r.first = r.line
+		}
+		return record, nil
+	}
+}
+
+// parseImportTime parses the time of a bit in a csv file, given in the
+// standard time format or as RFC 3339.
+func parseImportTime(s string) (time.Time, error) {
+	t, err := time.Parse(pilosa.TimeFormat, s)
+	if err != nil {
+		return time.Parse(time.RFC3339, s)
+	}
+	return t, nil
+}
+
+// badRowError is an error in the contents of a single row of an import file.
+type badRowError struct{ error }
+
+// badRows reports the malformed rows of an import file. In strict mode the
+// first one stops the import; otherwise each is logged and skipped. If
+// skipped is set, the rows are also appended to it, and more than max of
+// them stop the import unless max is negative.
+type badRows struct {
+	path    string
+	strict  bool
+	logger  logger.Logger
+	n       int
+	skipped *[]string
+	max     int
+}
+
+func (cmd *ImportCommand) newBadRows(path string) *badRows {
+	return &badRows{path: path, strict: cmd.Strict, logger: cmd.Logger(), skipped: &cmd.badRows, max: cmd.MaxErrors}
+}
+
+// add reports a malformed row, returning the error if it should stop the
+// import.
+func (b *badRows) add(err error) error {
+	if _, ok := err.(badRowError); b.strict || !ok {
+		return err
+	}
+	b.n++
+	b.logger.Printf("skipping bad row: %s", err)
+	if b.skipped == nil {
+		return nil
+	}
+	*b.skipped = append(*b.skipped, fmt.Sprintf("%s: %s", b.path, err))
+	if b.max >= 0 && len(*b.skipped) > b.max {
+		return fmt.Errorf("more than %d bad rows", b.max)
+	}
+	return nil
+}
+
+// summarize logs the number of rows skipped.
+func (b *badRows) summarize() {
+	if b.n > 0 {
+		b.logger.Printf("skipped %d bad rows of %s", b.n, b.path)
+	}
+}
+
+// printBadRows prints the malformed rows skipped during the run.
+func (cmd *ImportCommand) printBadRows() {
+	if len(cmd.badRows) == 0 {
+		return
+	}
+	fmt.Fprintf(cmd.Stderr, "skipped %d bad rows:\n", len(cmd.badRows))
+	for _, row := range cmd.badRows {
+		fmt.Fprintln(cmd.Stderr, row)
+	}
+}
+
 // importValues sends batches of FieldValues to the server.
 func (cmd *ImportCommand) importValues(ctx context.Context, useColumnKeys bool, vals []pilosa.FieldValue) error {
-	logger := log.New(cmd.Stderr, "", log.LstdFlags)
+	logger := cmd.Logger()
 
 	// If keys are used, all values are sent to the primary translate store (i.e. coordinator).
 	if useColumnKeys {
@@ -373,7 +1378,7 @@ func (cmd *ImportCommand) importValues(ctx context.Context, useColumnKeys bool,
 
 	// Group vals by shard.
 	logger.Printf("grouping %d vals", len(vals))
-	valsByShard := http.FieldValues(vals).GroupByShard()
+	valsByShard := http.FieldValues(vals).GroupByShard(cmd.shardWidth)
 
 	// Parse path into FieldValues.
 	for shard, vals := range valsByShard {
@@ -18,15 +18,18 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/roaring"
 	"github.com/pilosa/pilosa/test"
 )
 
@@ -103,6 +106,44 @@ func TestImportCommand_Basic(t *testing.T) {
 	})
 }
 
+// Ensure the import command reports how many of the imported bits are new.
+func TestImportCommand_Counts(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	file, err := ioutil.TempFile("", "import.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write([]byte("1,2\n3,4\n1,2\n")); err != nil {
+		t.Fatal(err)
+	} else if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		clear bool
+		exp   string
+	}{
+		{false, "imported 2 bits (2 new)\n"},
+		{false, "imported 2 bits (0 new)\n"},
+		{true, "cleared 2 bits (2 were set)\n"},
+	} {
+		var stdout bytes.Buffer
+		cm := NewImportCommand(os.Stdin, &stdout, ioutil.Discard)
+		cm.Host = cmd.API.Node().URI.HostPort()
+		cm.Index = "i"
+		cm.Field = "f"
+		cm.CreateSchema = true
+		cm.Clear = tt.clear
+		cm.Paths = []string{file.Name()}
+		if err := cm.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if got := stdout.String(); got != tt.exp {
+			t.Fatalf("unexpected output: %q, expected %q", got, tt.exp)
+		}
+	}
+}
+
 // Ensure that the ImportValue path runs.
 func TestImportCommand_RunValue(t *testing.T) {
 	t.Run("set", func(t *testing.T) {
@@ -159,6 +200,199 @@ func TestImportCommand_RunValue(t *testing.T) {
 	})
 }
 
+// Ensure that rows of serialized roaring bitmaps are imported.
+func TestImportCommand_RunRoaringRows(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{TrackExistence: true})
+	cmd.MustCreateField(t, "i", "f")
+
+	var data bytes.Buffer
+	writeRecord := func(rowID, shard uint64, columns ...uint64) {
+		var bm bytes.Buffer
+		if _, err := roaring.NewBitmap(columns...).WriteTo(&bm); err != nil {
+			t.Fatal(err)
+		}
+		binary.Write(&data, binary.LittleEndian, rowID)
+		binary.Write(&data, binary.LittleEndian, shard)
+		binary.Write(&data, binary.LittleEndian, uint32(bm.Len()))
+		data.Write(bm.Bytes())
+	}
+	writeRecord(1, 0, 2, 4)
+	writeRecord(1, 1, 3)
+	writeRecord(2, 0, 4)
+
+	cm := NewImportCommand(&data, &bytes.Buffer{}, &bytes.Buffer{})
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index = "i"
+	cm.Field = "f"
+	cm.Format = ImportFormatRoaringRows
+	cm.Paths = []string{"-"}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("Import Run with roaring rows doesn't work: %s", err)
+	}
+
+	resp := cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Row(f=1) Row(f=2) Not(Row(f=3))"})
+	for i, exp := range [][]uint64{{2, 4, pilosa.ShardWidth + 3}, {4}, {2, 4, pilosa.ShardWidth + 3}} {
+		if cols := resp.Results[i].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, exp) {
+			t.Fatalf("result %d: expected %v, got %v", i, exp, cols)
+		}
+	}
+
+	// Offsets beyond the shard width are rejected.
+	data.Reset()
+	writeRecord(3, 0, pilosa.ShardWidth)
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "not within the shard width") {
+		t.Fatalf("expected shard width error, got: %v", err)
+	}
+}
+
+// Ensure that JSON lines are imported with mapped property names.
+func TestImportCommand_RunJSONLines(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("YMD"))
+
+	cm := NewImportCommand(strings.NewReader(`{"tag": 1, "user": 2, "at": "2018-01-02T00:00", "extra": "x"}
+{"tag": 1, "user": 5}
+`), &bytes.Buffer{}, &bytes.Buffer{})
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index = "i"
+	cm.Field = "f"
+	cm.Format = ImportFormatJSONLines
+	cm.RowField, cm.ColumnField, cm.TimeField = "tag", "user", "at"
+	cm.Paths = []string{"-"}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("Import Run with JSON lines doesn't work: %s", err)
+	}
+
+	resp := cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Row(f=1) Row(f=1, from=2018-01-01T00:00, to=2018-01-03T00:00)"})
+	for i, exp := range [][]uint64{{2, 5}, {2}} {
+		if cols := resp.Results[i].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, exp) {
+			t.Fatalf("result %d: expected %v, got %v", i, exp, cols)
+		}
+	}
+
+	cm.Stdin = strings.NewReader(`{"tag": 1, "user": 2}` + "\n" + `{"tag": 1}`)
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "missing user on line 2") {
+		t.Fatalf("expected missing field error, got: %v", err)
+	}
+}
+
+// Ensure that row attributes are imported alongside bits.
+func TestImportCommand_RunAttrColumns(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeDefault())
+
+	var stderr bytes.Buffer
+	cm := NewImportCommand(strings.NewReader(`1,2,,"a b",3,true,1
+1,5,,"a b",3,true,1
+2,5,,c,,false,2.5
+1,7,,d
+`), &bytes.Buffer{}, &stderr)
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index = "i"
+	cm.Field = "f"
+	cm.AttrColumns = "name:string,n:int,ok:bool,x:float"
+	cm.Paths = []string{"-"}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("Import Run with attribute columns doesn't work: %s", err)
+	} else if !strings.Contains(stderr.String(), `conflicting name of row 1 on line 4: d replaces a b`) {
+		t.Fatalf("expected conflict to be reported, got: %s", stderr.String())
+	} else if strings.Count(stderr.String(), "conflicting") != 2 {
+		t.Fatalf("expected a single conflict, got: %s", stderr.String())
+	}
+
+	cm.Format = ImportFormatJSONLines
+	cm.Stdin = strings.NewReader(`{"bitmapID": 3, "profileID": 1, "name": "e", "n": -4, "x": 2}` + "\n")
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("Import Run with jsonl attributes doesn't work: %s", err)
+	}
+
+	resp := cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Row(f=1) Row(f=2) Row(f=3)"})
+	for i, exp := range []map[string]interface{}{
+		{"name": "d", "n": int64(3), "ok": true, "x": float64(1)},
+		{"name": "c", "ok": false, "x": 2.5},
+		{"name": "e", "n": int64(-4), "x": float64(2)},
+	} {
+		if attrs := resp.Results[i].(*pilosa.Row).Attrs; !reflect.DeepEqual(attrs, exp) {
+			t.Fatalf("row %d: expected %v, got %v", i+1, exp, attrs)
+		}
+	}
+
+	for _, tt := range []struct {
+		attrs string
+		data  string
+		err   string
+	}{
+		{attrs: "n:int", data: "1,2,,x", err: `invalid n on row 1: expected an integer, got "x"`},
+		{attrs: "n", err: "expected name:type"},
+		{attrs: "n:date", err: "invalid type of attribute n"},
+		{attrs: "1n:int", err: "invalid attribute name"},
+	} {
+		cm.Format = ImportFormatCSV
+		cm.Strict = true
+		cm.AttrColumns = tt.attrs
+		cm.Stdin = strings.NewReader(tt.data)
+		if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%s: expected error %q, got: %v", tt.attrs, tt.err, err)
+		}
+	}
+}
+
+// Ensure that lines of a column and its rows are expanded into bits.
+func TestImportCommand_RunColumns(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeDefault())
+
+	var data strings.Builder
+	data.WriteString("2,1|3\n\n5, 3 |\n7,\n7,")
+	for row := 10; row < 1000; row++ {
+		fmt.Fprintf(&data, "%d|", row)
+	}
+	data.WriteString("10")
+
+	cm := NewImportCommand(strings.NewReader(data.String()), &bytes.Buffer{}, &bytes.Buffer{})
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index = "i"
+	cm.Field = "f"
+	cm.Format = ImportFormatColumns
+	cm.ListDelimiter = "|"
+	cm.BufferSize = 7
+	cm.Paths = []string{"-"}
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("Import Run with columns doesn't work: %s", err)
+	}
+
+	resp := cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Row(f=1) Row(f=3) Row(f=10) Row(f=999)"})
+	for i, exp := range [][]uint64{{2}, {2, 5}, {7}, {7}} {
+		if cols := resp.Results[i].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, exp) {
+			t.Fatalf("result %d: expected %v, got %v", i, exp, cols)
+		}
+	}
+
+	for _, tt := range []struct {
+		data string
+		err  string
+	}{
+		{data: "1,2|3\n\n4,5|6|x|7", err: `invalid row id on line 3: "x"`},
+		{data: "1,2|3\ny,4", err: `invalid column id on line 2: "y"`},
+		{data: "1,2|3,4", err: "unexpected ',' in the list of rows on line 1"},
+		{data: "1,2\n3", err: "expected a column and a list of rows on line 2"},
+	} {
+		cm.Stdin = strings.NewReader(tt.data)
+		if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%q: expected error %q, got: %v", tt.data, tt.err, err)
+		}
+	}
+
+	cm.ListDelimiter = ","
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid list delimiter") {
+		t.Fatalf("expected delimiter error, got: %v", err)
+	}
+}
+
 // Ensure that import with keys runs.
 func TestImportCommand_RunKeys(t *testing.T) {
 	buf := bytes.Buffer{}
@@ -285,6 +519,7 @@ func TestImportCommand_InvalidFile(t *testing.T) {
 	cm.Host = cmd.API.Node().URI.HostPort()
 	cm.Index = "i"
 	cm.Field = "f"
+	cm.Strict = true
 	file, err := ioutil.TempFile("", "import.csv")
 	file.Write([]byte("a,2\n3,5\n5,6"))
 	if err != nil {
@@ -331,6 +566,70 @@ func TestImportCommand_InvalidFile(t *testing.T) {
 
 }
 
+// Ensure irregular CSV input is rejected in strict mode and tolerated
+// otherwise, with malformed rows skipped.
+func TestImportCommand_StrictAndLenient(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+
+	for i, tt := range []struct {
+		int     bool
+		keys    bool
+		data    string
+		strict  string // error in strict mode
+		columns []uint64
+		skipped string // logged in lenient mode
+	}{
+		{data: "1,1\n\n1,2\n", strict: "blank row 2", columns: []uint64{1, 2}},
+		{data: "\ufeff1,1\n1,2", strict: "byte order mark on row 1", columns: []uint64{1, 2}},
+		{data: "1,1\r\n1,2\r\n", strict: "carriage return at the end of row 1", columns: []uint64{1, 2}},
+		{data: "1,1,\n1,2,,", strict: "trailing comma on row 1", columns: []uint64{1, 2}},
+		{data: "bitmapID,profileID\n1,1\n1,2", strict: `invalid row id on row 1: "bitmapID"`, columns: []uint64{1, 2}, skipped: "skipping header on row 1"},
+		{data: "\n1,1\nrow,col\n1,2", strict: "blank row 1", columns: []uint64{1, 2}, skipped: `skipping bad row: invalid row id on row 3: "row"`},
+		{data: "1,1\n1\n1,2", strict: "bad column count on row 2", columns: []uint64{1, 2}, skipped: "skipping bad row: bad column count on row 2"},
+		{data: "1,1\n1,x\n1,2", strict: `invalid column id on row 2: "x"`, columns: []uint64{1, 2}, skipped: "skipped 1 bad rows"},
+		{data: "1,1,2018\n1,2", strict: `invalid timestamp on row 1: "2018"`, columns: []uint64{2}, skipped: "skipping bad row: invalid timestamp"},
+		{data: "1,1\n1,2,1969-12-31T23:00", strict: `timestamp before the epoch on row 2: "1969-12-31T23:00"`, columns: []uint64{1}, skipped: "skipping bad row: timestamp before the epoch"},
+		{data: "1,\"1\n1,2", strict: "invalid CSV on row 1", columns: []uint64{2}, skipped: "skipping bad row: invalid CSV on row 1"},
+		{keys: true, data: "name,profileID\n1,1\n1,2", strict: "invalid column id on row 1", columns: []uint64{1, 2}, skipped: "skipping header on row 1"},
+		{int: true, data: "column,value\r\n1,5\r\n2,x\r\n", strict: "carriage return at the end of row 1", columns: []uint64{1}, skipped: `skipping bad row: invalid value on row 3: "x"`},
+	} {
+		field, query := fmt.Sprintf("f%d", i), fmt.Sprintf("Row(f%d=1)", i)
+		if tt.int {
+			cmd.MustCreateField(t, "i", field, pilosa.OptFieldTypeInt(0, 100))
+			query = fmt.Sprintf("Row(f%d > 0)", i)
+		} else if tt.keys {
+			cmd.MustCreateField(t, "i", field, pilosa.OptFieldTypeDefault(), pilosa.OptFieldKeys())
+			query = fmt.Sprintf(`Row(f%d="1")`, i)
+		} else {
+			cmd.MustCreateField(t, "i", field, pilosa.OptFieldTypeDefault())
+		}
+
+		var stderr bytes.Buffer
+		cm := NewImportCommand(strings.NewReader(tt.data), &bytes.Buffer{}, &stderr)
+		cm.Host = cmd.API.Node().URI.HostPort()
+		cm.Index, cm.Field = "i", field
+		cm.Paths = []string{"-"}
+		cm.Strict = true
+		if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), tt.strict) {
+			t.Fatalf("%d: expected strict error %q, got: %v", i, tt.strict, err)
+		}
+
+		cm.Stdin = strings.NewReader(tt.data)
+		cm.Strict = false
+		if err := cm.Run(context.Background()); err != nil {
+			t.Fatalf("%d: lenient import failed: %s", i, err)
+		} else if !strings.Contains(stderr.String(), tt.skipped) {
+			t.Fatalf("%d: expected %q to be logged, got: %s", i, tt.skipped, stderr.String())
+		}
+
+		resp := cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: query})
+		if cols := resp.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, tt.columns) {
+			t.Fatalf("%d: expected columns %v, got %v", i, tt.columns, cols)
+		}
+	}
+}
+
 // MustNewHTTPRequest creates a new HTTP request. Panic on error.
 func MustNewHTTPRequest(method, urlStr string, body io.Reader) *http.Request {
 	req, err := http.NewRequest(method, urlStr, body)
@@ -350,6 +649,55 @@ func GetIO(buf bytes.Buffer) (io.Reader, io.Writer, io.Writer) {
 	return stdin, stdout, stderr
 }
 
+// Ensure bad rows are skipped and reported until there are more than
+// MaxErrors, and that RFC 3339 timestamps are imported into time views.
+func TestImportCommand_MaxErrors(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("YMD"))
+
+	data := "1,1,2018-01-01T00:00\n" +
+		"abc,123\n" +
+		"1,2,2018-01-02T10:00:00Z\n" +
+		"1,3,2018-13-01T00:00\n" +
+		"1,x\n" +
+		"1,4,2018-01-05T00:00:00+02:00\n"
+
+	var stderr bytes.Buffer
+	cm := NewImportCommand(strings.NewReader(data), &bytes.Buffer{}, &stderr)
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index, cm.Field = "i", "f"
+	cm.Paths = []string{"-"}
+	cm.MaxErrors = 2
+	if err := cm.Run(context.Background()); err == nil || err.Error() != "more than 2 bad rows" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stderr.Reset()
+	cm.Stdin = strings.NewReader(data)
+	cm.MaxErrors = 3
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"skipped 3 bad rows:\n",
+		"-: invalid row id on row 2: \"abc\"\n",
+		"-: invalid timestamp on row 4: \"2018-13-01T00:00\"\n",
+		"-: invalid column id on row 5: \"x\"\n",
+	} {
+		if !strings.Contains(stderr.String(), s) {
+			t.Fatalf("expected %q to be printed, got: %s", s, stderr.String())
+		}
+	}
+
+	resp := cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Row(f=1) Row(f=1, from=2018-01-02T00:00, to=2018-01-05T00:00)"})
+	if cols := resp.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, []uint64{1, 2, 4}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if cols := resp.Results[1].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, []uint64{2, 4}) {
+		t.Fatalf("unexpected time range columns: %v", cols)
+	}
+}
+
 func TestImportCommand_BugOverwriteValue(t *testing.T) {
 	cmd := test.MustRunCluster(t, 1)[0]
 
@@ -16,9 +16,11 @@ package ctl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"syscall"
 	"text/tabwriter"
 	"time"
@@ -26,14 +28,30 @@ import (
 
 	"github.com/pilosa/pilosa"
 	"github.com/pilosa/pilosa/roaring"
+	"github.com/pilosa/pilosa/server"
 	"github.com/pkg/errors"
 )
 
-// InspectCommand represents a command for inspecting fragment data files.
+// InspectCommand represents a command for inspecting fragment data files, or
+// the distribution of an index's fragments across a cluster.
 type InspectCommand struct {
 	// Path to data file
 	Path string
 
+	// Remote host and port, and the index, and optionally the field, whose
+	// fragments are inspected in the cluster instead of a data file.
+	Host  string
+	Index string
+	Field string
+
+	// Only list the fragments which an owner of the shard doesn't hold.
+	MissingOnly bool
+
+	// Write JSON instead of a table.
+	JSON bool
+
+	TLS server.TLSConfig
+
 	// Standard input/output
 	*pilosa.CmdIO
 }
@@ -46,7 +64,13 @@ func NewInspectCommand(stdin io.Reader, stdout, stderr io.Writer) *InspectComman
 }
 
 // Run executes the inspect command.
-func (cmd *InspectCommand) Run(_ context.Context) error {
+func (cmd *InspectCommand) Run(ctx context.Context) error {
+	if cmd.Index != "" {
+		return cmd.runCluster(ctx)
+	} else if cmd.Field != "" {
+		return errors.New("field requires an index")
+	}
+
 	// Open file handle.
 	f, err := os.Open(cmd.Path)
 	if err != nil {
@@ -104,3 +128,136 @@ func (cmd *InspectCommand) Run(_ context.Context) error {
 
 	return nil
 }
+
+// inspectFragment is a fragment of a view which at least one node holds, and
+// whether each node owning its shard holds it.
+type inspectFragment struct {
+	Field  string          `json:"field"`
+	View   string          `json:"view"`
+	Shard  uint64          `json:"shard"`
+	Owners []*inspectOwner `json:"owners"`
+}
+
+// inspectOwner is a node owning the shard of an inspectFragment.
+type inspectOwner struct {
+	ID        string `json:"id"`
+	URI       string `json:"uri"`
+	Primary   bool   `json:"primary"`
+	Exists    bool   `json:"exists"`
+	FileBytes int64  `json:"fileBytes"`
+}
+
+// missing returns true if an owner doesn't hold the fragment.
+func (f *inspectFragment) missing() bool {
+	for _, o := range f.Owners {
+		if !o.Exists {
+			return true
+		}
+	}
+	return false
+}
+
+// runCluster lists the owners of the shard of each fragment of the index
+// which any node holds, and whether each owner holds it.
+func (cmd *InspectCommand) runCluster(ctx context.Context) error {
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "getting client")
+	}
+	nodes, err := client.Nodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting nodes")
+	}
+
+	// Collect the size of each fragment on every node holding it.
+	type fragmentKey struct {
+		field, view string
+		shard       uint64
+	}
+	held := make(map[fragmentKey]map[string]int64)
+	for _, node := range nodes {
+		stats, err := client.HolderStats(ctx, &node.URI, cmd.Index, cmd.Field)
+		if err != nil {
+			return errors.Wrapf(err, "getting holder stats of node %s", node.ID)
+		}
+		for _, vs := range stats {
+			for _, fs := range vs.Fragments {
+				k := fragmentKey{field: vs.Field, view: vs.View.Name, shard: fs.Shard}
+				if held[k] == nil {
+					held[k] = make(map[string]int64)
+				}
+				held[k][node.ID] = fs.FileBytes
+			}
+		}
+	}
+
+	keys := make([]fragmentKey, 0, len(held))
+	for k := range held {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].field != keys[j].field {
+			return keys[i].field < keys[j].field
+		} else if keys[i].view != keys[j].view {
+			return keys[i].view < keys[j].view
+		}
+		return keys[i].shard < keys[j].shard
+	})
+
+	// The primary owner of a shard is listed first.
+	owners := make(map[uint64][]*pilosa.Node)
+	frags := make([]*inspectFragment, 0, len(keys))
+	for _, k := range keys {
+		shardNodes, ok := owners[k.shard]
+		if !ok {
+			if shardNodes, err = client.FragmentNodes(ctx, cmd.Index, k.shard); err != nil {
+				return errors.Wrapf(err, "getting owners of shard %d", k.shard)
+			}
+			owners[k.shard] = shardNodes
+		}
+
+		f := &inspectFragment{Field: k.field, View: k.view, Shard: k.shard}
+		for i, node := range shardNodes {
+			n, ok := held[k][node.ID]
+			f.Owners = append(f.Owners, &inspectOwner{
+				ID:        node.ID,
+				URI:       node.URI.String(),
+				Primary:   i == 0,
+				Exists:    ok,
+				FileBytes: n,
+			})
+		}
+		if cmd.MissingOnly && !f.missing() {
+			continue
+		}
+		frags = append(frags, f)
+	}
+
+	if cmd.JSON {
+		return errors.Wrap(json.NewEncoder(cmd.Stdout).Encode(frags), "encoding")
+	}
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVIEW\tSHARD\tNODE\tROLE\tFRAGMENT\tBYTES")
+	for _, f := range frags {
+		for _, o := range f.Owners {
+			role, exists := "replica", "MISSING"
+			if o.Primary {
+				role = "primary"
+			}
+			if o.Exists {
+				exists = "yes"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%d\n", f.Field, f.View, f.Shard, o.ID, role, exists, o.FileBytes)
+		}
+	}
+	return errors.Wrap(tw.Flush(), "flushing")
+}
+
+func (cmd *InspectCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *InspectCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
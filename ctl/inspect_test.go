@@ -17,11 +17,18 @@ package ctl
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/server"
+	"github.com/pilosa/pilosa/test"
 )
 
 func TestInspectCommand_Run(t *testing.T) {
@@ -48,3 +55,86 @@ func TestInspectCommand_Run(t *testing.T) {
 
 	//	Todo: need correct roaring file for happy path
 }
+
+// Ensure the owners of each shard are listed with whether they hold its
+// fragments.
+func TestInspectCommand_RunCluster(t *testing.T) {
+	c := test.MustRunCluster(t, 3, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(2))})
+	defer c.Close()
+	ctx := context.Background()
+
+	client := c[0].Client()
+	if err := client.CreateIndex(ctx, "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client.CreateField(ctx, "i", "f"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: "Set(1, f=1)"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the primary owner of shard 1 holds its fragment.
+	owners, err := client.FragmentNodes(ctx, "i", 1)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(owners) != 2 {
+		t.Fatalf("unexpected owners: %v", owners)
+	}
+	for _, m := range c {
+		if m.API.Node().ID != owners[0].ID {
+			continue
+		}
+		if _, err := m.API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf("Set(%d, f=1)", pilosa.ShardWidth+1), Shards: []uint64{1}, Remote: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(cm *InspectCommand) string {
+		var buf bytes.Buffer
+		cm.CmdIO = pilosa.NewCmdIO(os.Stdin, &buf, ioutil.Discard)
+		cm.Host = c[1].API.Node().URI.HostPort()
+		cm.Index = "i"
+		cm.Field = "f"
+		if err := cm.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		var frags []*inspectFragment
+		if err := json.Unmarshal([]byte(run(&InspectCommand{JSON: true})), &frags); err != nil {
+			t.Fatal(err)
+		} else if len(frags) != 2 {
+			t.Fatalf("unexpected fragments: %d", len(frags))
+		}
+		for shard, f := range frags {
+			if f.Field != "f" || f.View != "standard" || f.Shard != uint64(shard) {
+				t.Fatalf("unexpected fragment: %+v", f)
+			} else if len(f.Owners) != 2 || !f.Owners[0].Primary || f.Owners[1].Primary {
+				t.Fatalf("unexpected owners of shard %d: %+v", shard, f.Owners)
+			}
+			for i, o := range f.Owners {
+				if exp := shard == 0 || i == 0; o.Exists != exp {
+					t.Fatalf("shard %d, owner %d: unexpected exists: %v", shard, i, o.Exists)
+				} else if o.Exists != (o.FileBytes > 0) {
+					t.Fatalf("shard %d, owner %d: unexpected size: %d", shard, i, o.FileBytes)
+				}
+			}
+		}
+		if ids := []string{frags[1].Owners[0].ID, frags[1].Owners[1].ID}; !reflect.DeepEqual(ids, []string{owners[0].ID, owners[1].ID}) {
+			t.Fatalf("unexpected owners of shard 1: %v", ids)
+		}
+	})
+
+	t.Run("MissingOnly", func(t *testing.T) {
+		out := run(&InspectCommand{MissingOnly: true})
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("unexpected output: %q", out)
+		} else if fields := strings.Fields(lines[1]); !reflect.DeepEqual(fields, []string{"f", "standard", "1", owners[0].ID, "primary", "yes", fields[6]}) {
+			t.Fatalf("unexpected primary: %q", lines[1])
+		} else if fields := strings.Fields(lines[2]); !reflect.DeepEqual(fields, []string{"f", "standard", "1", owners[1].ID, "replica", "MISSING", "0"}) {
+			t.Fatalf("unexpected replica: %q", lines[2])
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pkg/errors"
+)
+
+// LayoutMigrateCommand represents a command for moving the fragment files of
+// a data directory to another layout.
+type LayoutMigrateCommand struct {
+	// Path to the data directory.
+	DataDir string
+
+	// Layout to move fragments to.
+	Layout string
+
+	// Standard input/output
+	*pilosa.CmdIO
+}
+
+// NewLayoutMigrateCommand returns a new instance of LayoutMigrateCommand.
+func NewLayoutMigrateCommand(stdin io.Reader, stdout, stderr io.Writer) *LayoutMigrateCommand {
+	return &LayoutMigrateCommand{
+		CmdIO: pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run moves the fragments of every view in the data directory.
+func (cmd *LayoutMigrateCommand) Run(_ context.Context) error {
+	if cmd.DataDir == "" {
+		return errors.New("data directory required")
+	} else if cmd.Layout == "" {
+		return errors.New("layout required")
+	}
+	l, err := pilosa.ParseFragmentLayout(cmd.Layout)
+	if err != nil {
+		return err
+	}
+
+	views, fragments, err := pilosa.MigrateFragmentLayout(cmd.DataDir, l)
+	if err != nil {
+		return errors.Wrap(err, "migrating")
+	}
+	fmt.Fprintf(cmd.Stdout, "moved %d fragments of %d views to the %s layout\n", fragments, views, l)
+	return nil
+}
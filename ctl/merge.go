@@ -0,0 +1,92 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pkg/errors"
+)
+
+// MergeCommand represents a command for merging two fragments of the same
+// shard, from two data directories for example, into a new fragment.
+type MergeCommand struct {
+	// Paths of the two fragment data files to merge, or of the two data
+	// directories holding them if Index is set.
+	Paths []string
+
+	// Fragment to merge from each data directory.
+	Index string
+	Field string
+	View  string
+	Shard uint64
+
+	// Path to write the merged fragment data file to.
+	OutputPath string
+
+	// Type and size of the merged fragment's cache.
+	CacheType string
+	CacheSize uint32
+
+	// Standard input/output
+	*pilosa.CmdIO
+}
+
+// NewMergeCommand returns a new instance of MergeCommand.
+func NewMergeCommand(stdin io.Reader, stdout, stderr io.Writer) *MergeCommand {
+	return &MergeCommand{
+		CmdIO:     pilosa.NewCmdIO(stdin, stdout, stderr),
+		View:      "standard",
+		CacheType: pilosa.DefaultCacheType,
+		CacheSize: pilosa.DefaultCacheSize,
+	}
+}
+
+// Run merges the fragments into the output file.
+func (cmd *MergeCommand) Run(_ context.Context) error {
+	if len(cmd.Paths) != 2 {
+		return errors.New("two paths required")
+	} else if cmd.OutputPath == "" {
+		return errors.New("output file required")
+	}
+
+	// Find the fragments in the data directories.
+	paths := cmd.Paths
+	if cmd.Index != "" {
+		if cmd.Field == "" {
+			return errors.New("field required")
+		} else if cmd.View == "" {
+			return errors.New("view required")
+		}
+		paths = make([]string, len(cmd.Paths))
+		for i, dir := range cmd.Paths {
+			path, err := pilosa.FragmentFilePath(dir, cmd.Index, cmd.Field, cmd.View, cmd.Shard)
+			if err != nil {
+				return errors.Wrapf(err, "finding fragment in %s", dir)
+			}
+			paths[i] = path
+		}
+	}
+
+	n, err := pilosa.MergeFragmentFiles(paths[0], paths[1], cmd.OutputPath, cmd.CacheType, cmd.CacheSize)
+	if err != nil {
+		return errors.Wrap(err, "merging")
+	}
+	fmt.Fprintf(cmd.Stdout, "merged %s and %s into %s with %d bits\n", paths[0], paths[1], cmd.OutputPath, n)
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/roaring"
+)
+
+func TestMergeCommand_Run(t *testing.T) {
+	t.Run("Files", func(t *testing.T) {
+		dir := mustTempDir(t)
+		defer os.RemoveAll(dir)
+
+		// Overlapping bits, across rows and containers, in each input.
+		var bitsA, bitsB []uint64
+		for row := uint64(0); row < 10; row++ {
+			for col := uint64(0); col < 1000; col += 3 {
+				bitsA = append(bitsA, row*pilosa.ShardWidth+col)
+			}
+			for col := uint64(0); col < 1000; col += 5 {
+				bitsB = append(bitsB, row*pilosa.ShardWidth+col+70000)
+				bitsB = append(bitsB, row*pilosa.ShardWidth+col)
+			}
+		}
+		pathA := mustWriteFragmentFile(t, filepath.Join(dir, "a", "3"), bitsA)
+		pathB := mustWriteFragmentFile(t, filepath.Join(dir, "b", "3"), bitsB)
+		dataA, dataB := mustReadFile(t, pathA), mustReadFile(t, pathB)
+
+		cm := NewMergeCommand(os.Stdin, ioutil.Discard, ioutil.Discard)
+		cm.Paths = []string{pathA, pathB}
+		cm.OutputPath = filepath.Join(dir, "out", "3")
+		if err := cm.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		want := roaring.NewBitmap(bitsA...).Union(roaring.NewBitmap(bitsB...))
+		if got := mustReadFragmentFile(t, cm.OutputPath); got.Count() != want.Count() {
+			t.Fatalf("merged count=%d, want %d", got.Count(), want.Count())
+		} else if got.Difference(want).Count() != 0 || want.Difference(got).Count() != 0 {
+			t.Fatal("merged bits differ from union")
+		}
+
+		// The cache is rebuilt for the merged rows.
+		_, ids, err := pilosa.DecodeCacheFile(mustReadFile(t, cm.OutputPath+".cache"))
+		if err != nil {
+			t.Fatal(err)
+		} else if len(ids) != 10 {
+			t.Fatalf("cached rows=%d, want 10", len(ids))
+		}
+
+		// The inputs are untouched.
+		if !bytes.Equal(mustReadFile(t, pathA), dataA) || !bytes.Equal(mustReadFile(t, pathB), dataB) {
+			t.Fatal("inputs modified")
+		}
+	})
+
+	t.Run("DataDirs", func(t *testing.T) {
+		dir := mustTempDir(t)
+		defer os.RemoveAll(dir)
+
+		viewPath := filepath.Join("i", "f", "views", "standard", "fragments")
+		mustWriteFragmentFile(t, filepath.Join(dir, "a", viewPath, "1"), []uint64{1, 2, pilosa.ShardWidth + 2})
+		mustWriteFragmentFile(t, filepath.Join(dir, "b", viewPath, "1"), []uint64{2, 3})
+
+		cm := NewMergeCommand(os.Stdin, ioutil.Discard, ioutil.Discard)
+		cm.Paths = []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}
+		cm.Index, cm.Field, cm.Shard = "i", "f", 1
+		cm.OutputPath = filepath.Join(dir, "out", "1")
+		if err := cm.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if n := mustReadFragmentFile(t, cm.OutputPath).Count(); n != 4 {
+			t.Fatalf("merged count=%d, want 4", n)
+		}
+	})
+
+	t.Run("Absent", func(t *testing.T) {
+		dir := mustTempDir(t)
+		defer os.RemoveAll(dir)
+
+		pathA := mustWriteFragmentFile(t, filepath.Join(dir, "a", "0"), []uint64{1, 5, 9})
+
+		cm := NewMergeCommand(os.Stdin, ioutil.Discard, ioutil.Discard)
+		cm.Paths = []string{pathA, filepath.Join(dir, "b", "0")}
+		cm.OutputPath = filepath.Join(dir, "out", "0")
+		if err := cm.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if n := mustReadFragmentFile(t, cm.OutputPath).Count(); n != 3 {
+			t.Fatalf("merged count=%d, want 3", n)
+		}
+	})
+
+	t.Run("DifferentShards", func(t *testing.T) {
+		dir := mustTempDir(t)
+		defer os.RemoveAll(dir)
+
+		pathA := mustWriteFragmentFile(t, filepath.Join(dir, "a", "0"), []uint64{1})
+		pathB := mustWriteFragmentFile(t, filepath.Join(dir, "b", "1"), []uint64{2})
+
+		cm := NewMergeCommand(os.Stdin, ioutil.Discard, ioutil.Discard)
+		cm.Paths = []string{pathA, pathB}
+		cm.OutputPath = filepath.Join(dir, "out", "0")
+		if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "different shards") {
+			t.Fatalf("unexpected error: %v", err)
+		} else if _, err := os.Stat(cm.OutputPath); !os.IsNotExist(err) {
+			t.Fatalf("output written: %v", err)
+		}
+	})
+
+	t.Run("OutputExists", func(t *testing.T) {
+		dir := mustTempDir(t)
+		defer os.RemoveAll(dir)
+
+		pathA := mustWriteFragmentFile(t, filepath.Join(dir, "a", "0"), []uint64{1})
+		pathB := mustWriteFragmentFile(t, filepath.Join(dir, "b", "0"), []uint64{2})
+		data := mustReadFile(t, pathB)
+
+		cm := NewMergeCommand(os.Stdin, ioutil.Discard, ioutil.Discard)
+		cm.Paths = []string{pathA, pathB}
+		cm.OutputPath = pathB
+		if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Fatalf("unexpected error: %v", err)
+		} else if !bytes.Equal(mustReadFile(t, pathB), data) {
+			t.Fatal("input modified")
+		}
+	})
+}
+
+func mustTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "pilosa-ctl-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// mustWriteFragmentFile writes a fragment data file with bits at path.
+func mustWriteFragmentFile(t *testing.T, path string, bits []uint64) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := roaring.NewBitmap(bits...).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(path, buf.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func mustReadFragmentFile(t *testing.T, path string) *roaring.Bitmap {
+	t.Helper()
+	bm := roaring.NewBitmap()
+	if err := bm.UnmarshalBinary(mustReadFile(t, path)); err != nil {
+		t.Fatal(err)
+	}
+	return bm
+}
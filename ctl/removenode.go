@@ -0,0 +1,111 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/server"
+	"github.com/pkg/errors"
+)
+
+// RemoveNodeCommand represents a command for removing a node from a cluster
+// while it keeps serving queries, once the other nodes have pulled its
+// shards.
+type RemoveNodeCommand struct {
+	// Host and port of the coordinator.
+	Host string
+
+	// ID of the node to remove.
+	ID string
+
+	// Time between progress checks.
+	Interval time.Duration
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewRemoveNodeCommand returns a new instance of RemoveNodeCommand.
+func NewRemoveNodeCommand(stdin io.Reader, stdout, stderr io.Writer) *RemoveNodeCommand {
+	return &RemoveNodeCommand{
+		Interval: time.Second,
+		CmdIO:    pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run starts or resumes the removal on the coordinator, and reports its
+// progress until the node has been removed.
+func (cmd *RemoveNodeCommand) Run(ctx context.Context) error {
+	if cmd.ID == "" {
+		return errors.New("node ID required")
+	}
+
+	// Create a client to the server.
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+	uri, err := pilosa.NewURIFromAddress(cmd.Host)
+	if err != nil {
+		return errors.Wrap(err, "parsing host")
+	}
+
+	r, err := client.RemoveNodeWithHandoff(ctx, uri, cmd.ID)
+	if err != nil {
+		return errors.Wrap(err, "removing node")
+	}
+	fmt.Fprintf(cmd.Stdout, "removing node %s: %d shards to transfer\n", r.ID, r.Shards)
+
+	// Report the progress until the node is removed.
+	transferred := r.Transferred
+	for !r.Done {
+		if r.Error != "" {
+			return errors.Errorf("removal failed, run the command again to resume it: %s", r.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cmd.Interval):
+		}
+
+		if r, err = client.NodeRemoval(ctx, uri); err != nil {
+			return errors.Wrap(err, "getting removal")
+		} else if r.ID != cmd.ID {
+			return errors.Errorf("node %s is being removed", r.ID)
+		}
+		if r.Transferred != transferred {
+			transferred = r.Transferred
+			fmt.Fprintf(cmd.Stdout, "%d/%d shards\n", r.Transferred, r.Shards)
+		}
+	}
+	fmt.Fprintf(cmd.Stdout, "node %s is removed\n", r.ID)
+	return nil
+}
+
+func (cmd *RemoveNodeCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *RemoveNodeCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
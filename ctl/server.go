@@ -28,14 +28,36 @@ func BuildServerFlags(cmd *cobra.Command, srv *server.Command) {
 	flags.StringVarP(&srv.Config.Bind, "bind", "b", srv.Config.Bind, "Default URI on which pilosa should listen.")
 	flags.StringVar(&srv.Config.Advertise, "advertise", srv.Config.Advertise, "Address to advertise externally.")
 	flags.IntVarP(&srv.Config.MaxWritesPerRequest, "max-writes-per-request", "", srv.Config.MaxWritesPerRequest, "Number of write commands per request.")
+	flags.IntVarP(&srv.Config.MaxConstRowColumns, "max-const-row-columns", "", srv.Config.MaxConstRowColumns, "Number of column IDs a ConstRow() call may list; 0 is unlimited.")
+	flags.DurationVar((*time.Duration)(&srv.Config.MaxQueryTime), "max-query-time", (time.Duration)(srv.Config.MaxQueryTime), "Time after which a query without a deadline times out; 0 is unlimited.")
+	flags.Int64Var(&srv.Config.CacheMaxMemory, "cache-max-memory", srv.Config.CacheMaxMemory, "Approximate memory in bytes for all row count caches; 0 is unlimited.")
+	flags.IntVar(&srv.Config.OpenWorkers, "open-workers", srv.Config.OpenWorkers, "Number of fields opened concurrently at startup; 0 is one per CPU.")
+	flags.BoolVar(&srv.Config.AllowLegacyNames, "allow-legacy-names", srv.Config.AllowLegacyNames, "Open indexes and fields whose names break the naming rules instead of skipping them.")
+	flags.IntVar(&srv.Config.MaxIndexes, "max-indexes", srv.Config.MaxIndexes, "Maximum number of indexes which can be created; 0 is unlimited.")
+	flags.IntVar(&srv.Config.MaxFieldsPerIndex, "max-fields-per-index", srv.Config.MaxFieldsPerIndex, "Maximum number of fields which can be created in each index; 0 is unlimited.")
+	flags.IntVar(&srv.Config.MaxViewsPerField, "max-views-per-field", srv.Config.MaxViewsPerField, "Maximum number of views which can be created in each field; 0 is unlimited.")
+	flags.StringVar(&srv.Config.Durability, "durability", srv.Config.Durability, "Which writes are synced to disk: relaxed, default or strict.")
+	flags.IntVar(&srv.Config.MaxOpN, "max-op-n", srv.Config.MaxOpN, "Number of ops appended to the op log of a fragment before it is snapshotted; 0 is the default.")
+	flags.DurationVar((*time.Duration)(&srv.Config.FragmentCloseTimeout), "fragment-close-timeout", (time.Duration)(srv.Config.FragmentCloseTimeout), "Time closing a fragment waits for the queries and exports reading it to finish.")
+	flags.IntVar(&srv.Config.FragmentOpenConcurrency, "fragment-open-concurrency", srv.Config.FragmentOpenConcurrency, "Number of fragments of a view opened at once at startup; 0 is GOMAXPROCS.")
+	flags.StringVar(&srv.Config.FragmentOpenPolicy, "fragment-open-policy", srv.Config.FragmentOpenPolicy, "What to do with a fragment which fails to open at startup: strict fails startup, quarantine moves it aside and opens the rest.")
+	flags.DurationVar((*time.Duration)(&srv.Config.FragmentIdleTimeout), "fragment-idle-timeout", (time.Duration)(srv.Config.FragmentIdleTimeout), "Time a fragment may go unaccessed before it is closed until next accessed; 0 keeps fragments open.")
+	flags.IntVar(&srv.Config.MaxOpenFragmentsPerView, "max-open-fragments-per-view", srv.Config.MaxOpenFragmentsPerView, "Number of fragments of a view kept open before the least recently accessed are closed; 0 is unlimited.")
+	flags.IntVar(&srv.Config.SnapshotWorkers, "snapshot-workers", srv.Config.SnapshotWorkers, "Number of fragments snapshotted at once in the background once their op logs are full.")
+	flags.BoolVar(&srv.Config.PreserveOrphans, "preserve-orphans", srv.Config.PreserveOrphans, "Move temporary files left by a crash aside at startup instead of removing them.")
+	flags.BoolVar(&srv.Config.StrictLimits, "strict-limits", srv.Config.StrictLimits, "Fail at startup if the open file or memory mapping limits are too low for the data.")
+	flags.StringVar(&srv.Config.FragmentLayout, "fragment-layout", srv.Config.FragmentLayout, "Layout of the fragment files of new views: flat or sharded.")
+	flags.BoolVar(&srv.Config.ReadOnly, "read-only", srv.Config.ReadOnly, "Serve queries without modifying the data directory, rejecting writes.")
 	flags.StringVar(&srv.Config.LogPath, "log-path", srv.Config.LogPath, "Log path")
 	flags.BoolVar(&srv.Config.Verbose, "verbose", srv.Config.Verbose, "Enable verbose logging")
+	flags.StringVar(&srv.Config.LogLevel, "log-level", srv.Config.LogLevel, "Least severe level logged: debug, info or error. Overrides --verbose.")
 
 	// TLS
 	SetTLSConfig(flags, &srv.Config.TLS.CertificatePath, &srv.Config.TLS.CertificateKeyPath, &srv.Config.TLS.SkipVerify)
 
 	// Handler
 	flags.StringSliceVarP(&srv.Config.Handler.AllowedOrigins, "handler.allowed-origins", "", []string{}, "Comma separated list of allowed origin URIs (for CORS/WebUI).")
+	flags.Int64Var(&srv.Config.Handler.MaxDecompressedImportSize, "handler.max-decompressed-import-size", srv.Config.Handler.MaxDecompressedImportSize, "Maximum size in bytes of a gzip-encoded import body once decompressed; 0 is unlimited.")
 
 	// Cluster
 	flags.BoolVarP(&srv.Config.Cluster.Disabled, "cluster.disabled", "", srv.Config.Cluster.Disabled, "Disabled multi-node cluster communication (used for testing)")
@@ -43,6 +65,7 @@ func BuildServerFlags(cmd *cobra.Command, srv *server.Command) {
 	flags.IntVarP(&srv.Config.Cluster.ReplicaN, "cluster.replicas", "", 1, "Number of hosts each piece of data should be stored on.")
 	flags.StringSliceVarP(&srv.Config.Cluster.Hosts, "cluster.hosts", "", []string{}, "Comma separated list of hosts in cluster. Only used for testing.")
 	flags.DurationVarP((*time.Duration)(&srv.Config.Cluster.LongQueryTime), "cluster.long-query-time", "", time.Minute, "Duration that will trigger log and stat messages for slow queries.")
+	flags.BoolVarP(&srv.Config.Cluster.DisableFailover, "cluster.disable-failover", "", srv.Config.Cluster.DisableFailover, "Fail reads whose shards are on an unavailable node instead of retrying them against replicas.")
 
 	// Translation
 	flags.StringVarP(&srv.Config.Translation.PrimaryURL, "translation.primary-url", "", srv.Config.Translation.PrimaryURL, "DEPRECATED: URL for primary translation node for replication.")
@@ -66,9 +89,13 @@ func BuildServerFlags(cmd *cobra.Command, srv *server.Command) {
 
 	// AntiEntropy
 	flags.DurationVarP((*time.Duration)(&srv.Config.AntiEntropy.Interval), "anti-entropy.interval", "", (time.Duration)(srv.Config.AntiEntropy.Interval), "Interval at which to run anti-entropy routine.")
+	flags.IntVarP(&srv.Config.AntiEntropy.RequestsPerSecond, "anti-entropy.requests-per-second", "", srv.Config.AntiEntropy.RequestsPerSecond, "Maximum number of requests per second anti-entropy sends to other nodes. 0 is unlimited.")
+
+	// Retention
+	flags.DurationVarP((*time.Duration)(&srv.Config.Retention.Interval), "retention.interval", "", (time.Duration)(srv.Config.Retention.Interval), "Interval at which to delete expired time views; 0 disables.")
 
 	// Metric
-	flags.StringVarP(&srv.Config.Metric.Service, "metric.service", "", srv.Config.Metric.Service, "Where to send stats: can be expvar (in-memory served at /debug/vars), statsd or none.")
+	flags.StringVarP(&srv.Config.Metric.Service, "metric.service", "", srv.Config.Metric.Service, "Where to send stats: can be expvar (in-memory served at /debug/vars), statsd, none, or a backend registered by the build.")
 	flags.StringVarP(&srv.Config.Metric.Host, "metric.host", "", srv.Config.Metric.Host, "URI to send metrics when metric.service is statsd.")
 	flags.DurationVarP((*time.Duration)(&srv.Config.Metric.PollInterval), "metric.poll-interval", "", (time.Duration)(srv.Config.Metric.PollInterval), "Polling interval metrics.")
 	flags.BoolVarP((&srv.Config.Metric.Diagnostics), "metric.diagnostics", "", srv.Config.Metric.Diagnostics, "Enabled diagnostics reporting.")
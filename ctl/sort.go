@@ -0,0 +1,313 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pkg/errors"
+)
+
+// sortRecordSize is the size of a bit in a sorted run: its row, column and
+// timestamp.
+const sortRecordSize = 24
+
+// SortCommand represents a command for sorting a CSV file of bits, as read by
+// ImportCommand, by shard and then by row, so that importing it sends the
+// bits of each shard together. Files larger than the buffer are sorted in
+// runs spilled to temporary files, which are then merged.
+type SortCommand struct {
+	// Filename to sort, or "-" for standard input.
+	Path string `json:"path"`
+
+	// Filename to write the sorted bits to, or "-" for standard output.
+	OutputPath string `json:"outputPath"`
+
+	// Number of bits sorted in memory at once.
+	BufferSize int `json:"bufferSize"`
+
+	// Shard width of the index the bits will be imported into.
+	ShardWidth uint64 `json:"shardWidth"`
+
+	// Stops at the first irregular or malformed row, instead of tolerating
+	// irregularities and skipping malformed rows.
+	Strict bool `json:"strict"`
+
+	// Directory of the sorted runs. The system's temporary directory if empty.
+	TempDir string `json:"tempDir"`
+
+	// Standard input/output
+	*pilosa.CmdIO
+}
+
+// NewSortCommand returns a new instance of SortCommand.
+func NewSortCommand(stdin io.Reader, stdout, stderr io.Writer) *SortCommand {
+	return &SortCommand{
+		CmdIO:      pilosa.NewCmdIO(stdin, stdout, stderr),
+		BufferSize: 10000000,
+		ShardWidth: pilosa.ShardWidth,
+	}
+}
+
+// Run executes the sort command.
+func (cmd *SortCommand) Run(ctx context.Context) error {
+	if cmd.Path == "" {
+		return errors.New("path required")
+	} else if cmd.OutputPath == "" {
+		return errors.New("output file required")
+	} else if cmd.BufferSize <= 0 {
+		return errors.New("buffer size must be positive")
+	} else if cmd.ShardWidth == 0 || cmd.ShardWidth&(cmd.ShardWidth-1) != 0 {
+		return fmt.Errorf("shard width must be a power of 2: %d", cmd.ShardWidth)
+	}
+
+	var input io.Reader = cmd.Stdin
+	if cmd.Path != "-" {
+		f, err := os.Open(cmd.Path)
+		if err != nil {
+			return errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+		input = f
+	}
+
+	// Sorted runs are removed however the sort ends.
+	dir, err := ioutil.TempDir(cmd.TempDir, "pilosa-sort-")
+	if err != nil {
+		return errors.Wrap(err, "creating temp directory")
+	}
+	defer os.RemoveAll(dir)
+
+	// Read the bits, spilling each full buffer as a sorted run.
+	var runs []string
+	a := make([]pilosa.Bit, 0, cmd.BufferSize)
+	bad := &badRows{path: cmd.Path, strict: cmd.Strict, logger: cmd.Logger()}
+	err = readCSVBits(input, bad, false, false, func(record []string, rnum int) error {
+		bit, err := parseCSVBit(record, rnum, false, false)
+		if err != nil {
+			return badRowError{err}
+		}
+		if a = append(a, bit); len(a) == cmd.BufferSize {
+			path, err := cmd.writeRun(dir, len(runs), a)
+			if err != nil {
+				return err
+			}
+			runs, a = append(runs, path), a[:0]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cmd.sortBits(a)
+
+	// Write the merged runs and the remaining bits to the output.
+	if cmd.OutputPath == "-" {
+		w := bufio.NewWriter(cmd.Stdout)
+		if err := cmd.merge(w, runs, a); err != nil {
+			return err
+		} else if err := w.Flush(); err != nil {
+			return errors.Wrap(err, "writing output")
+		}
+	} else if err := cmd.writeOutput(runs, a); err != nil {
+		os.Remove(cmd.OutputPath)
+		return err
+	}
+	cmd.Logger().Printf("sorted %s into %s with %d runs", cmd.Path, cmd.OutputPath, len(runs))
+	return nil
+}
+
+// writeOutput writes the merged runs and bits to the output file.
+func (cmd *SortCommand) writeOutput(runs []string, a []pilosa.Bit) error {
+	f, err := os.Create(cmd.OutputPath)
+	if err != nil {
+		return errors.Wrap(err, "creating output file")
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := cmd.merge(w, runs, a); err != nil {
+		return err
+	} else if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "writing output")
+	} else if err := f.Close(); err != nil {
+		return errors.Wrap(err, "closing output file")
+	}
+	return nil
+}
+
+// less returns true if a sorts before b: by shard, row, column and then time.
+func (cmd *SortCommand) less(a, b pilosa.Bit) bool {
+	if as, bs := a.ColumnID/cmd.ShardWidth, b.ColumnID/cmd.ShardWidth; as != bs {
+		return as < bs
+	} else if a.RowID != b.RowID {
+		return a.RowID < b.RowID
+	} else if a.ColumnID != b.ColumnID {
+		return a.ColumnID < b.ColumnID
+	}
+	return a.Timestamp < b.Timestamp
+}
+
+func (cmd *SortCommand) sortBits(a []pilosa.Bit) {
+	sort.Slice(a, func(i, j int) bool { return cmd.less(a[i], a[j]) })
+}
+
+// writeRun sorts a and writes it to the n-th run file in dir.
+func (cmd *SortCommand) writeRun(dir string, n int, a []pilosa.Bit) (string, error) {
+	cmd.sortBits(a)
+
+	path := filepath.Join(dir, fmt.Sprintf("run%d", n))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", errors.Wrap(err, "creating run")
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	var buf [sortRecordSize]byte
+	for _, bit := range a {
+		binary.LittleEndian.PutUint64(buf[0:8], bit.RowID)
+		binary.LittleEndian.PutUint64(buf[8:16], bit.ColumnID)
+		binary.LittleEndian.PutUint64(buf[16:24], uint64(bit.Timestamp))
+		if _, err := w.Write(buf[:]); err != nil {
+			return "", errors.Wrap(err, "writing run")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", errors.Wrap(err, "writing run")
+	} else if err := f.Close(); err != nil {
+		return "", errors.Wrap(err, "closing run")
+	}
+	return path, nil
+}
+
+// merge writes the bits of the sorted runs and of the sorted slice a to w in
+// order.
+func (cmd *SortCommand) merge(w io.Writer, runs []string, a []pilosa.Bit) error {
+	h := &sortRunHeap{less: cmd.less}
+	if len(a) > 0 {
+		h.runs = append(h.runs, &sortRun{bits: a})
+	}
+	for _, path := range runs {
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err, "opening run")
+		}
+		defer f.Close()
+		h.runs = append(h.runs, &sortRun{r: bufio.NewReader(f)})
+	}
+
+	// Read the first bit of each run.
+	runs0 := h.runs
+	h.runs = h.runs[:0]
+	for _, run := range runs0 {
+		if ok, err := run.next(); err != nil {
+			return err
+		} else if ok {
+			h.runs = append(h.runs, run)
+		}
+	}
+	heap.Init(h)
+
+	var line []byte
+	for h.Len() > 0 {
+		run := h.runs[0]
+		line = appendCSVBit(line[:0], run.bit)
+		if _, err := w.Write(line); err != nil {
+			return errors.Wrap(err, "writing output")
+		}
+
+		if ok, err := run.next(); err != nil {
+			return err
+		} else if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
+
+// appendCSVBit appends a bit as a line of a CSV file to buf.
+func appendCSVBit(buf []byte, bit pilosa.Bit) []byte {
+	buf = strconv.AppendUint(buf, bit.RowID, 10)
+	buf = append(buf, ',')
+	buf = strconv.AppendUint(buf, bit.ColumnID, 10)
+	if bit.Timestamp != 0 {
+		buf = append(buf, ',')
+		buf = time.Unix(0, bit.Timestamp).UTC().AppendFormat(buf, pilosa.TimeFormat)
+	}
+	return append(buf, '\n')
+}
+
+// sortRun is a sorted run of bits, read from a file or held in memory.
+type sortRun struct {
+	r    *bufio.Reader
+	bits []pilosa.Bit
+	bit  pilosa.Bit // current bit
+}
+
+// next reads the next bit of the run, returning false at the end.
+func (run *sortRun) next() (bool, error) {
+	if run.r == nil {
+		if len(run.bits) == 0 {
+			return false, nil
+		}
+		run.bit, run.bits = run.bits[0], run.bits[1:]
+		return true, nil
+	}
+
+	var buf [sortRecordSize]byte
+	if _, err := io.ReadFull(run.r, buf[:]); err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "reading run")
+	}
+	run.bit = pilosa.Bit{
+		RowID:     binary.LittleEndian.Uint64(buf[0:8]),
+		ColumnID:  binary.LittleEndian.Uint64(buf[8:16]),
+		Timestamp: int64(binary.LittleEndian.Uint64(buf[16:24])),
+	}
+	return true, nil
+}
+
+// sortRunHeap is a heap of runs ordered by their current bits.
+type sortRunHeap struct {
+	runs []*sortRun
+	less func(a, b pilosa.Bit) bool
+}
+
+func (h *sortRunHeap) Len() int           { return len(h.runs) }
+func (h *sortRunHeap) Less(i, j int) bool { return h.less(h.runs[i].bit, h.runs[j].bit) }
+func (h *sortRunHeap) Swap(i, j int)      { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+func (h *sortRunHeap) Push(x interface{}) { h.runs = append(h.runs, x.(*sortRun)) }
+
+func (h *sortRunHeap) Pop() interface{} {
+	run := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return run
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa"
+)
+
+func TestSortCommand_Validation(t *testing.T) {
+	buf := bytes.Buffer{}
+	stdin, stdout, stderr := GetIO(buf)
+	cm := NewSortCommand(stdin, stdout, stderr)
+	if err := cm.Run(context.Background()); err == nil || err.Error() != "path required" {
+		t.Fatalf("expected path required, got: %v", err)
+	}
+
+	cm.Path = "in.csv"
+	if err := cm.Run(context.Background()); err == nil || err.Error() != "output file required" {
+		t.Fatalf("expected output file required, got: %v", err)
+	}
+
+	cm.OutputPath = "out.csv"
+	cm.ShardWidth = 3
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "power of 2") {
+		t.Fatalf("expected shard width error, got: %v", err)
+	}
+}
+
+func TestSortCommand_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pilosa-sort-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tempDir := filepath.Join(dir, "tmp")
+	if err := os.Mkdir(tempDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write a million shuffled bits, some with timestamps.
+	const n = 1000000
+	rnd := rand.New(rand.NewSource(1))
+	bits := make([]pilosa.Bit, n)
+	for i := range bits {
+		bits[i] = pilosa.Bit{RowID: uint64(rnd.Intn(100)), ColumnID: uint64(rnd.Int63n(20 * pilosa.ShardWidth))}
+		if i%10 == 0 {
+			bits[i].Timestamp = time.Date(2018, 1, 1+rnd.Intn(28), rnd.Intn(24), 0, 0, 0, time.UTC).UnixNano()
+		}
+	}
+	input := filepath.Join(dir, "input.csv")
+	f, err := os.Create(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := bufio.NewWriter(f)
+	for _, bit := range bits {
+		w.Write(appendCSVBit(nil, bit))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	} else if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sort the bits in memory for the expected output.
+	sort.Slice(bits, func(i, j int) bool {
+		a, b := bits[i], bits[j]
+		if as, bs := a.ColumnID/pilosa.ShardWidth, b.ColumnID/pilosa.ShardWidth; as != bs {
+			return as < bs
+		} else if a.RowID != b.RowID {
+			return a.RowID < b.RowID
+		} else if a.ColumnID != b.ColumnID {
+			return a.ColumnID < b.ColumnID
+		}
+		return a.Timestamp < b.Timestamp
+	})
+	var exp []byte
+	for _, bit := range bits {
+		exp = appendCSVBit(exp, bit)
+	}
+
+	for _, bufferSize := range []int{n * 2, 65536, 99999} {
+		t.Run(fmt.Sprintf("buffer%d", bufferSize), func(t *testing.T) {
+			stdin, stdout, stderr := GetIO(bytes.Buffer{})
+			cm := NewSortCommand(stdin, stdout, stderr)
+			cm.Path = input
+			cm.OutputPath = filepath.Join(dir, "output.csv")
+			cm.BufferSize = bufferSize
+			cm.TempDir = tempDir
+			if err := cm.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf, err := ioutil.ReadFile(cm.OutputPath); err != nil {
+				t.Fatal(err)
+			} else if !bytes.Equal(buf, exp) {
+				t.Fatal("unexpected output")
+			}
+			assertEmptyDir(t, tempDir)
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		buf, err := ioutil.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bad := filepath.Join(dir, "bad.csv")
+		if err := ioutil.WriteFile(bad, append(buf, "1,x\n"...), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		stdin, stdout, stderr := GetIO(bytes.Buffer{})
+		cm := NewSortCommand(stdin, stdout, stderr)
+		cm.Path = bad
+		cm.OutputPath = filepath.Join(dir, "bad-output.csv")
+		cm.BufferSize = 65536
+		cm.Strict = true
+		cm.TempDir = tempDir
+		if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("row %d", n+1)) {
+			t.Fatalf("expected error on row %d, got: %v", n+1, err)
+		}
+		if _, err := os.Stat(cm.OutputPath); !os.IsNotExist(err) {
+			t.Fatalf("expected no output file, got: %v", err)
+		}
+		assertEmptyDir(t, tempDir)
+	})
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	if fis, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 0 {
+		t.Fatalf("expected empty %s, got %d files", dir, len(fis))
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/server"
+	"github.com/pkg/errors"
+)
+
+// TimeMigrateCommand represents a command for building the larger time views
+// of a field from its existing smaller ones.
+type TimeMigrateCommand struct {
+	// Remote host and port.
+	Host string
+
+	// Name of the index & field to migrate.
+	Index string
+	Field string
+
+	// Unit of the views to read and units of the views to build. An empty
+	// To builds every unit of the field's quantum larger than From.
+	From string
+	To   string
+
+	// Time between progress checks.
+	Interval time.Duration
+
+	// Standard input/output
+	*pilosa.CmdIO
+
+	TLS server.TLSConfig
+}
+
+// NewTimeMigrateCommand returns a new instance of TimeMigrateCommand.
+func NewTimeMigrateCommand(stdin io.Reader, stdout, stderr io.Writer) *TimeMigrateCommand {
+	return &TimeMigrateCommand{
+		Interval: time.Second,
+		CmdIO:    pilosa.NewCmdIO(stdin, stdout, stderr),
+	}
+}
+
+// Run starts the migration on every node and waits for it to finish.
+func (cmd *TimeMigrateCommand) Run(ctx context.Context) error {
+	// Validate arguments.
+	if cmd.Index == "" {
+		return pilosa.ErrIndexRequired
+	} else if cmd.Field == "" {
+		return pilosa.ErrFieldRequired
+	} else if cmd.From == "" {
+		return errors.New("source unit required")
+	}
+
+	// Create a client to the server.
+	client, err := commandClient(cmd)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+
+	nodes, err := client.Nodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting nodes")
+	}
+
+	// Each node builds the views of its own shards.
+	for _, node := range nodes {
+		m, err := client.StartTimeMigration(ctx, &node.URI, cmd.Index, cmd.Field, pilosa.TimeQuantum(cmd.From), pilosa.TimeQuantum(cmd.To))
+		if err != nil {
+			return errors.Wrapf(err, "starting migration on %s", node.URI)
+		}
+		fmt.Fprintf(cmd.Stdout, "%s: building %d views\n", node.URI, len(m.Views))
+	}
+
+	// Report the progress of each view until every node is done.
+	built := make(map[string]int)
+	for {
+		done := true
+		for _, node := range nodes {
+			m, err := client.TimeMigration(ctx, &node.URI, cmd.Index, cmd.Field)
+			if err != nil {
+				return errors.Wrapf(err, "getting migration on %s", node.URI)
+			} else if m.Error != "" {
+				return errors.Errorf("migration failed on %s: %s", node.URI, m.Error)
+			}
+
+			for _, v := range m.Views {
+				key := node.URI.String() + "/" + v.View
+				if n, ok := built[key]; ok && n == v.Built {
+					continue
+				}
+				built[key] = v.Built
+				fmt.Fprintf(cmd.Stdout, "%s: %s %d/%d shards\n", node.URI, v.View, v.Built, v.Shards)
+			}
+			done = done && m.Done
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cmd.Interval):
+		}
+	}
+}
+
+func (cmd *TimeMigrateCommand) TLSHost() string {
+	return cmd.Host
+}
+
+func (cmd *TimeMigrateCommand) TLSConfiguration() server.TLSConfig {
+	return cmd.TLS
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/test"
+)
+
+func TestTimeMigrateCommand_Validation(t *testing.T) {
+	buf := bytes.Buffer{}
+	stdin, stdout, stderr := GetIO(buf)
+
+	cm := NewTimeMigrateCommand(stdin, stdout, stderr)
+
+	err := cm.Run(context.Background())
+	if err != pilosa.ErrIndexRequired {
+		t.Fatalf("Command not working, expect: %s, actual: '%s'", pilosa.ErrIndexRequired, err)
+	}
+
+	cm.Index = "i"
+	err = cm.Run(context.Background())
+	if err != pilosa.ErrFieldRequired {
+		t.Fatalf("Command not working, expect: %s, actual: '%s'", pilosa.ErrFieldRequired, err)
+	}
+
+	cm.Field = "f"
+	err = cm.Run(context.Background())
+	if err == nil || err.Error() != "source unit required" {
+		t.Fatalf("Command not working, expect: source unit required, actual: '%s'", err)
+	}
+}
+
+func TestTimeMigrateCommand_Run(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("YMD"))
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Set(1, f=1, 2018-01-02T00:00)"})
+
+	stdout := bytes.Buffer{}
+	cm := NewTimeMigrateCommand(&bytes.Buffer{}, &stdout, &bytes.Buffer{})
+	cm.Host = cmd.API.Node().URI.HostPort()
+	cm.Index = "i"
+	cm.Field = "f"
+	cm.From = "D"
+	cm.Interval = 10 * time.Millisecond
+	if err := cm.Run(context.Background()); err != nil {
+		t.Fatalf("TimeMigrate Run doesn't work: %s", err)
+	} else if out := stdout.String(); !strings.Contains(out, "building 2 views") || !strings.Contains(out, "standard_201801 1/1 shards") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	cm.From = "Y"
+	cm.To = "D"
+	if err := cm.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "only larger units can be built") {
+		t.Fatalf("expected error, got: %v", err)
+	}
+}
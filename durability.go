@@ -0,0 +1,106 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Durability determines which writes are synced to disk before they are
+// acknowledged, and so how much a crash of the host can lose.
+//
+//	write                     relaxed   default   strict
+//	fragment op log           no        no        yes
+//	attribute stores          no        yes       yes
+//	fragment snapshots        yes       yes       yes
+//	fragment caches           yes       yes       yes
+//	meta and shard files      yes       yes       yes
+//
+// Writes to the op log which aren't synced are lost if the host crashes
+// before the operating system writes them back or the fragment is next
+// snapshotted. The process crashing on its own loses nothing, as the writes
+// have already been handed to the operating system.
+type Durability string
+
+// Durability policies.
+const (
+	DurabilityRelaxed Durability = "relaxed"
+	DurabilityDefault Durability = "default"
+	DurabilityStrict  Durability = "strict"
+)
+
+// ParseDurability returns the named durability policy. An empty name is the
+// default policy.
+func ParseDurability(s string) (Durability, error) {
+	switch d := Durability(s); d {
+	case "":
+		return DurabilityDefault, nil
+	case DurabilityRelaxed, DurabilityDefault, DurabilityStrict:
+		return d, nil
+	default:
+		return "", errors.Errorf("invalid durability %q, must be relaxed, default or strict", s)
+	}
+}
+
+// syncsOpLog returns true if each write to a fragment's op log is synced.
+func (d Durability) syncsOpLog() bool { return d == DurabilityStrict }
+
+// syncsAttrs returns true if each attribute store transaction is synced.
+func (d Durability) syncsAttrs() bool { return d != DurabilityRelaxed }
+
+// syncer is implemented by attribute stores which can skip syncing writes. It
+// is called before the store is opened.
+type syncer interface {
+	SetSync(sync bool)
+}
+
+// syncWriter syncs a file after every write.
+type syncWriter struct {
+	file *os.File
+}
+
+func (w syncWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, w.file.Sync()
+}
+
+// metaTmpExt is the file extension of a meta file being written.
+const metaTmpExt = ".tmp"
+
+// writeMetaFile replaces the file at path with data. The data is synced to a
+// temporary file which is then moved into place, so a crash leaves either
+// the old or the new file, whatever the durability policy.
+func writeMetaFile(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + metaTmpExt
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	} else if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	} else if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// syncAttrStore records whether it was told to sync its writes.
+type syncAttrStore struct {
+	nopAttrStore
+	sync *bool
+}
+
+func (s syncAttrStore) SetSync(sync bool) { *s.sync = sync }
+
+// durabilityMatrix lists which writes are synced under each durability
+// policy. Writes without a check are synced whatever the policy.
+var durabilityMatrix = []struct {
+	write  string
+	synced map[Durability]bool
+	check  func(t *testing.T, h *Holder) bool
+}{
+	{
+		write:  "fragment op log",
+		synced: map[Durability]bool{DurabilityRelaxed: false, DurabilityDefault: false, DurabilityStrict: true},
+		check: func(t *testing.T, h *Holder) bool {
+			frag := h.fragment("i", "f", viewStandard, 0)
+			hw, ok := frag.storage.OpWriter.(hashingWriter)
+			if !ok {
+				t.Fatalf("unexpected op writer: %T", frag.storage.OpWriter)
+			}
+			switch w := hw.w.(type) {
+			case syncWriter:
+				return true
+			case *os.File:
+				return false
+			default:
+				t.Fatalf("unexpected op writer: %T", w)
+				return false
+			}
+		},
+	},
+	{
+		write:  "attribute stores",
+		synced: map[Durability]bool{DurabilityRelaxed: false, DurabilityDefault: true, DurabilityStrict: true},
+		check: func(t *testing.T, h *Holder) bool {
+			column := *h.Index("i").ColumnAttrStore().(syncAttrStore).sync
+			row := *h.Field("i", "f").RowAttrStore().(syncAttrStore).sync
+			if column != row {
+				t.Fatalf("column attrs synced %v, row attrs synced %v", column, row)
+			}
+			return column
+		},
+	},
+	{write: "fragment snapshots", synced: map[Durability]bool{DurabilityRelaxed: true, DurabilityDefault: true, DurabilityStrict: true}},
+	{write: "fragment caches", synced: map[Durability]bool{DurabilityRelaxed: true, DurabilityDefault: true, DurabilityStrict: true}},
+	{write: "meta and shard files", synced: map[Durability]bool{DurabilityRelaxed: true, DurabilityDefault: true, DurabilityStrict: true}},
+}
+
+// Ensure each durability policy syncs the writes listed in the matrix.
+func TestDurability_Matrix(t *testing.T) {
+	for _, d := range []Durability{DurabilityRelaxed, DurabilityDefault, DurabilityStrict} {
+		t.Run(string(d), func(t *testing.T) {
+			h := newHolder()
+			h.Durability = d
+			h.NewAttrStore = func(string) AttrStore { return syncAttrStore{sync: new(bool)} }
+			if err := h.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer h.Close()
+
+			h.SetBit("i", "f", 1, 1)
+			for _, w := range durabilityMatrix {
+				if w.check == nil {
+					if !w.synced[d] {
+						t.Fatalf("%s: unconditional writes must be synced", w.write)
+					}
+					continue
+				}
+				if synced := w.check(t, h.Holder); synced != w.synced[d] {
+					t.Fatalf("%s: synced=%v, expected %v", w.write, synced, w.synced[d])
+				}
+			}
+		})
+	}
+}
+
+// Ensure durability policies are parsed by name.
+func TestParseDurability(t *testing.T) {
+	for s, exp := range map[string]Durability{"": DurabilityDefault, "relaxed": DurabilityRelaxed, "default": DurabilityDefault, "strict": DurabilityStrict} {
+		if d, err := ParseDurability(s); err != nil {
+			t.Fatal(err)
+		} else if d != exp {
+			t.Fatalf("%q: got %q, expected %q", s, d, exp)
+		}
+	}
+	if _, err := ParseDurability("always"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// Ensure meta files are replaced without leaving temporary files behind.
+func TestWriteMetaFile(t *testing.T) {
+	f := MustOpenField(OptFieldTypeDefault())
+	defer f.Close()
+
+	path := filepath.Join(f.Path(), ".meta")
+	for i := 0; i < 2; i++ {
+		if err := writeMetaFile(path, []byte{byte(i)}, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if len(buf) != 1 || buf[0] != 1 {
+		t.Fatalf("unexpected data: %v", buf)
+	} else if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected temporary file to be removed: %v", err)
+	}
+}
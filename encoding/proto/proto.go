@@ -17,6 +17,7 @@ package proto
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pilosa/pilosa"
@@ -81,6 +82,38 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		decodeDeleteFieldMessage(msg, mt)
 		return nil
+	case *pilosa.SetFieldTimeQuantumMessage:
+		msg := &internal.SetFieldTimeQuantumMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetFieldTimeQuantumMessage")
+		}
+		decodeSetFieldTimeQuantumMessage(msg, mt)
+		return nil
+	case *pilosa.SetIndexTimeQuantumMessage:
+		msg := &internal.SetIndexTimeQuantumMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetIndexTimeQuantumMessage")
+		}
+		decodeSetIndexTimeQuantumMessage(msg, mt)
+		return nil
+	case *pilosa.SetFieldCacheSizeMessage:
+		msg := &internal.SetFieldCacheSizeMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling SetFieldCacheSizeMessage")
+		}
+		decodeSetFieldCacheSizeMessage(msg, mt)
+		return nil
+	case *pilosa.RenameIndexMessage:
+		msg := &internal.RenameIndexMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RenameIndexMessage")
+		}
+		decodeRenameIndexMessage(msg, mt)
+		return nil
 	case *pilosa.DeleteAvailableShardMessage:
 		msg := &internal.DeleteAvailableShardMessage{}
 		err := proto.Unmarshal(buf, msg)
@@ -105,6 +138,38 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		decodeDeleteViewMessage(msg, mt)
 		return nil
+	case *pilosa.DeleteColumnRangeMessage:
+		msg := &internal.DeleteColumnRangeMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling DeleteColumnRangeMessage")
+		}
+		decodeDeleteColumnRangeMessage(msg, mt)
+		return nil
+	case *pilosa.RenameFieldMessage:
+		msg := &internal.RenameFieldMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RenameFieldMessage")
+		}
+		decodeRenameFieldMessage(msg, mt)
+		return nil
+	case *pilosa.NodeHandoffMessage:
+		msg := &internal.NodeHandoffMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling NodeHandoffMessage")
+		}
+		decodeNodeHandoffMessage(msg, mt)
+		return nil
+	case *pilosa.NodeHandoffProgress:
+		msg := &internal.NodeHandoffProgress{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling NodeHandoffProgress")
+		}
+		decodeNodeHandoffProgress(msg, mt)
+		return nil
 	case *pilosa.ClusterStatus:
 		msg := &internal.ClusterStatus{}
 		err := proto.Unmarshal(buf, msg)
@@ -225,6 +290,14 @@ func (Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		decodeImportRoaringRequest(msg, mt)
 		return nil
+	case *pilosa.ImportRoaringRowsRequest:
+		msg := &internal.ImportRoaringRowsRequest{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling ImportRoaringRowsRequest")
+		}
+		decodeImportRoaringRowsRequest(msg, mt)
+		return nil
 	case *pilosa.ImportResponse:
 		msg := &internal.ImportResponse{}
 		err := proto.Unmarshal(buf, msg)
@@ -282,12 +355,28 @@ func encodeToProto(m pilosa.Message) proto.Message {
 		return encodeCreateFieldMessage(mt)
 	case *pilosa.DeleteFieldMessage:
 		return encodeDeleteFieldMessage(mt)
+	case *pilosa.SetFieldTimeQuantumMessage:
+		return encodeSetFieldTimeQuantumMessage(mt)
+	case *pilosa.SetIndexTimeQuantumMessage:
+		return encodeSetIndexTimeQuantumMessage(mt)
+	case *pilosa.SetFieldCacheSizeMessage:
+		return encodeSetFieldCacheSizeMessage(mt)
+	case *pilosa.RenameIndexMessage:
+		return encodeRenameIndexMessage(mt)
 	case *pilosa.DeleteAvailableShardMessage:
 		return encodeDeleteAvailableShardMessage(mt)
 	case *pilosa.CreateViewMessage:
 		return encodeCreateViewMessage(mt)
 	case *pilosa.DeleteViewMessage:
 		return encodeDeleteViewMessage(mt)
+	case *pilosa.DeleteColumnRangeMessage:
+		return encodeDeleteColumnRangeMessage(mt)
+	case *pilosa.RenameFieldMessage:
+		return encodeRenameFieldMessage(mt)
+	case *pilosa.NodeHandoffMessage:
+		return encodeNodeHandoffMessage(mt)
+	case *pilosa.NodeHandoffProgress:
+		return encodeNodeHandoffProgress(mt)
 	case *pilosa.ClusterStatus:
 		return encodeClusterStatus(mt)
 	case *pilosa.ResizeInstruction:
@@ -318,6 +407,8 @@ func encodeToProto(m pilosa.Message) proto.Message {
 		return encodeImportValueRequest(mt)
 	case *pilosa.ImportRoaringRequest:
 		return encodeImportRoaringRequest(mt)
+	case *pilosa.ImportRoaringRowsRequest:
+		return encodeImportRoaringRowsRequest(mt)
 	case *pilosa.ImportResponse:
 		return encodeImportResponse(mt)
 	case *pilosa.BlockDataRequest:
@@ -334,23 +425,37 @@ func encodeToProto(m pilosa.Message) proto.Message {
 
 func encodeBlockDataRequest(m *pilosa.BlockDataRequest) *internal.BlockDataRequest {
 	return &internal.BlockDataRequest{
-		Index: m.Index,
-		Field: m.Field,
-		View:  m.View,
-		Shard: m.Shard,
-		Block: m.Block,
+		Index:  m.Index,
+		Field:  m.Field,
+		View:   m.View,
+		Shard:  m.Shard,
+		Block:  m.Block,
+		Offset: m.Offset,
+		Limit:  m.Limit,
 	}
 }
 func encodeBlockDataResponse(m *pilosa.BlockDataResponse) *internal.BlockDataResponse {
 	return &internal.BlockDataResponse{
 		RowIDs:    m.RowIDs,
 		ColumnIDs: m.ColumnIDs,
+		More:      m.More,
 	}
 }
 
 func encodeImportResponse(m *pilosa.ImportResponse) *internal.ImportResponse {
+	var counts []*internal.ImportCount
+	for _, c := range m.Counts {
+		counts = append(counts, &internal.ImportCount{
+			Shard:   c.Shard,
+			Bits:    c.Bits,
+			Changed: c.Changed,
+		})
+	}
 	return &internal.ImportResponse{
-		Err: m.Err,
+		Err:                  m.Err,
+		PendingCacheRebuilds: m.PendingCacheRebuilds,
+		Views:                m.Views,
+		Counts:               counts,
 	}
 }
 
@@ -364,6 +469,7 @@ func encodeImportRequest(m *pilosa.ImportRequest) *internal.ImportRequest {
 		RowKeys:    m.RowKeys,
 		ColumnKeys: m.ColumnKeys,
 		Timestamps: m.Timestamps,
+		View:       m.View,
 	}
 }
 
@@ -394,6 +500,22 @@ func encodeImportRoaringRequest(m *pilosa.ImportRoaringRequest) *internal.Import
 	}
 }
 
+func encodeImportRoaringRowsRequest(m *pilosa.ImportRoaringRowsRequest) *internal.ImportRoaringRowsRequest {
+	rows := make([]*internal.ImportRoaringRow, len(m.Rows))
+	for i, row := range m.Rows {
+		rows[i] = &internal.ImportRoaringRow{
+			RowID: row.RowID,
+			Data:  row.Data,
+		}
+	}
+	return &internal.ImportRoaringRowsRequest{
+		Index: m.Index,
+		Field: m.Field,
+		Shard: m.Shard,
+		Rows:  rows,
+	}
+}
+
 func encodeQueryRequest(m *pilosa.QueryRequest) *internal.QueryRequest {
 	return &internal.QueryRequest{
 		Query:           m.Query,
@@ -402,6 +524,7 @@ func encodeQueryRequest(m *pilosa.QueryRequest) *internal.QueryRequest {
 		Remote:          m.Remote,
 		ExcludeRowAttrs: m.ExcludeRowAttrs,
 		ExcludeColumns:  m.ExcludeColumns,
+		Profile:         m.Profile,
 	}
 }
 
@@ -409,6 +532,8 @@ func encodeQueryResponse(m *pilosa.QueryResponse) *internal.QueryResponse {
 	pb := &internal.QueryResponse{
 		Results:        make([]*internal.QueryResult, len(m.Results)),
 		ColumnAttrSets: encodeColumnAttrSets(m.ColumnAttrSets),
+		TimeRoundings:  encodeTimeRoundings(m.TimeRoundings),
+		Profile:        encodeQueryProfile(m.Profile),
 	}
 
 	for i := range m.Results {
@@ -439,6 +564,12 @@ func encodeQueryResponse(m *pilosa.QueryResponse) *internal.QueryResponse {
 		case pilosa.RowIdentifiers:
 			pb.Results[i].Type = queryResultTypeRowIdentifiers
 			pb.Results[i].RowIdentifiers = encodeRowIdentifiers(result)
+		case []pilosa.TimeCount:
+			pb.Results[i].Type = queryResultTypeTimeCounts
+			pb.Results[i].TimeCounts = encodeTimeCounts(result)
+		case []bool:
+			pb.Results[i].Type = queryResultTypeBools
+			pb.Results[i].Bools = result
 		case nil:
 			pb.Results[i].Type = queryResultTypeNil
 		}
@@ -446,6 +577,7 @@ func encodeQueryResponse(m *pilosa.QueryResponse) *internal.QueryResponse {
 
 	if m.Err != nil {
 		pb.Err = m.Err.Error()
+		pb.ErrCode = pilosa.ErrorCode(m.Err)
 	}
 
 	return pb
@@ -498,6 +630,7 @@ func encodeIndexInfo(idx *pilosa.IndexInfo) *internal.Index {
 	return &internal.Index{
 		Name:   idx.Name,
 		Fields: encodeFieldInfos(idx.Fields),
+		Meta:   encodeIndexMeta(&idx.Options),
 	}
 }
 
@@ -534,6 +667,36 @@ func encodeFieldOptions(o *pilosa.FieldOptions) *internal.FieldOptions {
 		Max:         o.Max,
 		TimeQuantum: string(o.TimeQuantum),
 		Keys:        o.Keys,
+		Retention:   encodeTimeRetention(o.Retention),
+
+		TimeQuantumInherited: o.TimeQuantumInherited,
+		RowLabel:             o.RowLabel,
+		AttrCommit:           encodeAttrCommit(o.AttrCommit),
+	}
+}
+
+func encodeAttrCommit(c pilosa.AttrCommit) *internal.AttrCommit {
+	if c.IsZero() {
+		return nil
+	}
+	return &internal.AttrCommit{
+		Mode:     c.Mode,
+		Interval: int64(c.Interval),
+		MaxOps:   int64(c.MaxOps),
+	}
+}
+
+func encodeTimeRetention(r pilosa.TimeRetention) *internal.TimeRetention {
+	if r.IsZero() {
+		return nil
+	}
+	return &internal.TimeRetention{
+		Year:    int64(r.Year),
+		Quarter: int64(r.Quarter),
+		Month:   int64(r.Month),
+		Day:     int64(r.Day),
+		Hour:    int64(r.Hour),
+		Minute:  int64(r.Minute),
 	}
 }
 
@@ -569,6 +732,7 @@ func encodeClusterStatus(m *pilosa.ClusterStatus) *internal.ClusterStatus {
 		State:     m.State,
 		ClusterID: m.ClusterID,
 		Nodes:     encodeNodes(m.Nodes),
+		LeavingID: m.LeavingID,
 	}
 }
 
@@ -591,6 +755,10 @@ func encodeIndexMeta(m *pilosa.IndexOptions) *internal.IndexMeta {
 	return &internal.IndexMeta{
 		Keys:           m.Keys,
 		TrackExistence: m.TrackExistence,
+		TimeQuantum:    string(m.TimeQuantum),
+		ColumnLabel:    m.ColumnLabel,
+		ShardWidth:     m.ShardWidth,
+		PartitionKey:   m.PartitionKey,
 	}
 }
 
@@ -615,6 +783,37 @@ func encodeDeleteFieldMessage(m *pilosa.DeleteFieldMessage) *internal.DeleteFiel
 	}
 }
 
+func encodeSetFieldTimeQuantumMessage(m *pilosa.SetFieldTimeQuantumMessage) *internal.SetFieldTimeQuantumMessage {
+	return &internal.SetFieldTimeQuantumMessage{
+		Index:       m.Index,
+		Field:       m.Field,
+		TimeQuantum: string(m.TimeQuantum),
+	}
+}
+
+func encodeSetIndexTimeQuantumMessage(m *pilosa.SetIndexTimeQuantumMessage) *internal.SetIndexTimeQuantumMessage {
+	return &internal.SetIndexTimeQuantumMessage{
+		Index:       m.Index,
+		TimeQuantum: string(m.TimeQuantum),
+	}
+}
+
+func encodeSetFieldCacheSizeMessage(m *pilosa.SetFieldCacheSizeMessage) *internal.SetFieldCacheSizeMessage {
+	return &internal.SetFieldCacheSizeMessage{
+		Index:     m.Index,
+		Field:     m.Field,
+		CacheSize: m.CacheSize,
+	}
+}
+
+func encodeRenameIndexMessage(m *pilosa.RenameIndexMessage) *internal.RenameIndexMessage {
+	return &internal.RenameIndexMessage{
+		Index:   m.Index,
+		NewName: m.NewName,
+		Check:   m.Check,
+	}
+}
+
 func encodeDeleteAvailableShardMessage(m *pilosa.DeleteAvailableShardMessage) *internal.DeleteAvailableShardMessage {
 	return &internal.DeleteAvailableShardMessage{
 		Index:   m.Index,
@@ -639,6 +838,41 @@ func encodeDeleteViewMessage(m *pilosa.DeleteViewMessage) *internal.DeleteViewMe
 	}
 }
 
+func encodeDeleteColumnRangeMessage(m *pilosa.DeleteColumnRangeMessage) *internal.DeleteColumnRangeMessage {
+	return &internal.DeleteColumnRangeMessage{
+		Index: m.Index,
+		Min:   m.Min,
+		Max:   m.Max,
+	}
+}
+
+func encodeRenameFieldMessage(m *pilosa.RenameFieldMessage) *internal.RenameFieldMessage {
+	return &internal.RenameFieldMessage{
+		Index:   m.Index,
+		Field:   m.Field,
+		NewName: m.NewName,
+		Check:   m.Check,
+	}
+}
+
+func encodeNodeHandoffMessage(m *pilosa.NodeHandoffMessage) *internal.NodeHandoffMessage {
+	return &internal.NodeHandoffMessage{
+		LeavingID:   m.LeavingID,
+		Coordinator: encodeNode(m.Coordinator),
+	}
+}
+
+func encodeNodeHandoffProgress(m *pilosa.NodeHandoffProgress) *internal.NodeHandoffProgress {
+	return &internal.NodeHandoffProgress{
+		LeavingID:   m.LeavingID,
+		Node:        encodeNode(m.Node),
+		Shards:      m.Shards,
+		Transferred: m.Transferred,
+		Done:        m.Done,
+		Error:       m.Error,
+	}
+}
+
 func encodeResizeInstructionComplete(m *pilosa.ResizeInstructionComplete) *internal.ResizeInstructionComplete {
 	return &internal.ResizeInstructionComplete{
 		JobID: m.JobID,
@@ -711,8 +945,8 @@ func encodeFieldStatuses(a []*pilosa.FieldStatus) []*internal.FieldStatus {
 	return other
 }
 
-func encodeRecalculateCaches(*pilosa.RecalculateCaches) *internal.RecalculateCaches {
-	return &internal.RecalculateCaches{}
+func encodeRecalculateCaches(m *pilosa.RecalculateCaches) *internal.RecalculateCaches {
+	return &internal.RecalculateCaches{Rebuild: m.Rebuild}
 }
 
 func encodeTranslateKeysResponse(response *pilosa.TranslateKeysResponse) *internal.TranslateKeysResponse {
@@ -773,6 +1007,9 @@ func decodeIndexes(idxs []*internal.Index, m []*pilosa.IndexInfo) {
 
 func decodeIndex(idx *internal.Index, m *pilosa.IndexInfo) {
 	m.Name = idx.Name
+	if idx.Meta != nil {
+		decodeIndexMeta(idx.Meta, &m.Options)
+	}
 	m.Fields = make([]*pilosa.FieldInfo, len(idx.Fields))
 	decodeFields(idx.Fields, m.Fields)
 }
@@ -802,6 +1039,35 @@ func decodeFieldOptions(options *internal.FieldOptions, m *pilosa.FieldOptions)
 	m.Max = options.Max
 	m.TimeQuantum = pilosa.TimeQuantum(options.TimeQuantum)
 	m.Keys = options.Keys
+	m.Retention = decodeTimeRetention(options.Retention)
+	m.TimeQuantumInherited = options.TimeQuantumInherited
+	m.RowLabel = options.RowLabel
+	m.AttrCommit = decodeAttrCommit(options.AttrCommit)
+}
+
+func decodeAttrCommit(pb *internal.AttrCommit) pilosa.AttrCommit {
+	if pb == nil {
+		return pilosa.AttrCommit{}
+	}
+	return pilosa.AttrCommit{
+		Mode:     pb.Mode,
+		Interval: time.Duration(pb.Interval),
+		MaxOps:   int(pb.MaxOps),
+	}
+}
+
+func decodeTimeRetention(pb *internal.TimeRetention) pilosa.TimeRetention {
+	if pb == nil {
+		return pilosa.TimeRetention{}
+	}
+	return pilosa.TimeRetention{
+		Year:    time.Duration(pb.Year),
+		Quarter: time.Duration(pb.Quarter),
+		Month:   time.Duration(pb.Month),
+		Day:     time.Duration(pb.Day),
+		Hour:    time.Duration(pb.Hour),
+		Minute:  time.Duration(pb.Minute),
+	}
 }
 
 func decodeNodes(a []*internal.Node, m []*pilosa.Node) {
@@ -816,6 +1082,7 @@ func decodeClusterStatus(cs *internal.ClusterStatus, m *pilosa.ClusterStatus) {
 	m.ClusterID = cs.ClusterID
 	m.Nodes = make([]*pilosa.Node, len(cs.Nodes))
 	decodeNodes(cs.Nodes, m.Nodes)
+	m.LeavingID = cs.LeavingID
 }
 
 func decodeNode(node *internal.Node, m *pilosa.Node) {
@@ -846,6 +1113,10 @@ func decodeCreateIndexMessage(pb *internal.CreateIndexMessage, m *pilosa.CreateI
 func decodeIndexMeta(pb *internal.IndexMeta, m *pilosa.IndexOptions) {
 	m.Keys = pb.Keys
 	m.TrackExistence = pb.TrackExistence
+	m.TimeQuantum = pilosa.TimeQuantum(pb.TimeQuantum)
+	m.ColumnLabel = pb.ColumnLabel
+	m.ShardWidth = pb.ShardWidth
+	m.PartitionKey = pb.PartitionKey
 }
 
 func decodeDeleteIndexMessage(pb *internal.DeleteIndexMessage, m *pilosa.DeleteIndexMessage) {
@@ -864,6 +1135,29 @@ func decodeDeleteFieldMessage(pb *internal.DeleteFieldMessage, m *pilosa.DeleteF
 	m.Field = pb.Field
 }
 
+func decodeSetFieldTimeQuantumMessage(pb *internal.SetFieldTimeQuantumMessage, m *pilosa.SetFieldTimeQuantumMessage) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.TimeQuantum = pilosa.TimeQuantum(pb.TimeQuantum)
+}
+
+func decodeSetIndexTimeQuantumMessage(pb *internal.SetIndexTimeQuantumMessage, m *pilosa.SetIndexTimeQuantumMessage) {
+	m.Index = pb.Index
+	m.TimeQuantum = pilosa.TimeQuantum(pb.TimeQuantum)
+}
+
+func decodeSetFieldCacheSizeMessage(pb *internal.SetFieldCacheSizeMessage, m *pilosa.SetFieldCacheSizeMessage) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.CacheSize = pb.CacheSize
+}
+
+func decodeRenameIndexMessage(pb *internal.RenameIndexMessage, m *pilosa.RenameIndexMessage) {
+	m.Index = pb.Index
+	m.NewName = pb.NewName
+	m.Check = pb.Check
+}
+
 func decodeDeleteAvailableShardMessage(pb *internal.DeleteAvailableShardMessage, m *pilosa.DeleteAvailableShardMessage) {
 	m.Index = pb.Index
 	m.Field = pb.Field
@@ -882,6 +1176,35 @@ func decodeDeleteViewMessage(pb *internal.DeleteViewMessage, m *pilosa.DeleteVie
 	m.View = pb.View
 }
 
+func decodeDeleteColumnRangeMessage(pb *internal.DeleteColumnRangeMessage, m *pilosa.DeleteColumnRangeMessage) {
+	m.Index = pb.Index
+	m.Min = pb.Min
+	m.Max = pb.Max
+}
+
+func decodeRenameFieldMessage(pb *internal.RenameFieldMessage, m *pilosa.RenameFieldMessage) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.NewName = pb.NewName
+	m.Check = pb.Check
+}
+
+func decodeNodeHandoffMessage(pb *internal.NodeHandoffMessage, m *pilosa.NodeHandoffMessage) {
+	m.LeavingID = pb.LeavingID
+	m.Coordinator = &pilosa.Node{}
+	decodeNode(pb.Coordinator, m.Coordinator)
+}
+
+func decodeNodeHandoffProgress(pb *internal.NodeHandoffProgress, m *pilosa.NodeHandoffProgress) {
+	m.LeavingID = pb.LeavingID
+	m.Node = &pilosa.Node{}
+	decodeNode(pb.Node, m.Node)
+	m.Shards = pb.Shards
+	m.Transferred = pb.Transferred
+	m.Done = pb.Done
+	m.Error = pb.Error
+}
+
 func decodeResizeInstructionComplete(pb *internal.ResizeInstructionComplete, m *pilosa.ResizeInstructionComplete) {
 	m.JobID = pb.JobID
 	m.Node = &pilosa.Node{}
@@ -945,7 +1268,9 @@ func decodeFieldStatus(pb *internal.FieldStatus, m *pilosa.FieldStatus) {
 	m.AvailableShards = roaring.NewBitmap(pb.AvailableShards...)
 }
 
-func decodeRecalculateCaches(pb *internal.RecalculateCaches, m *pilosa.RecalculateCaches) {}
+func decodeRecalculateCaches(pb *internal.RecalculateCaches, m *pilosa.RecalculateCaches) {
+	m.Rebuild = pb.Rebuild
+}
 
 func decodeQueryRequest(pb *internal.QueryRequest, m *pilosa.QueryRequest) {
 	m.Query = pb.Query
@@ -954,6 +1279,7 @@ func decodeQueryRequest(pb *internal.QueryRequest, m *pilosa.QueryRequest) {
 	m.Remote = pb.Remote
 	m.ExcludeRowAttrs = pb.ExcludeRowAttrs
 	m.ExcludeColumns = pb.ExcludeColumns
+	m.Profile = pb.Profile
 }
 
 func decodeImportRequest(pb *internal.ImportRequest, m *pilosa.ImportRequest) {
@@ -965,6 +1291,7 @@ func decodeImportRequest(pb *internal.ImportRequest, m *pilosa.ImportRequest) {
 	m.RowKeys = pb.RowKeys
 	m.ColumnKeys = pb.ColumnKeys
 	m.Timestamps = pb.Timestamps
+	m.View = pb.View
 }
 
 func decodeImportValueRequest(pb *internal.ImportValueRequest, m *pilosa.ImportValueRequest) {
@@ -985,8 +1312,31 @@ func decodeImportRoaringRequest(pb *internal.ImportRoaringRequest, m *pilosa.Imp
 	m.Views = views
 }
 
+func decodeImportRoaringRowsRequest(pb *internal.ImportRoaringRowsRequest, m *pilosa.ImportRoaringRowsRequest) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.Shard = pb.Shard
+	m.Rows = make([]pilosa.ImportRoaringRow, len(pb.Rows))
+	for i, row := range pb.Rows {
+		m.Rows[i] = pilosa.ImportRoaringRow{
+			RowID: row.RowID,
+			Data:  row.Data,
+		}
+	}
+}
+
 func decodeImportResponse(pb *internal.ImportResponse, m *pilosa.ImportResponse) {
 	m.Err = pb.Err
+	m.PendingCacheRebuilds = pb.PendingCacheRebuilds
+	m.Views = pb.Views
+	m.Counts = nil
+	for _, c := range pb.Counts {
+		m.Counts = append(m.Counts, pilosa.ImportCount{
+			Shard:   c.Shard,
+			Bits:    c.Bits,
+			Changed: c.Changed,
+		})
+	}
 }
 
 func decodeBlockDataRequest(pb *internal.BlockDataRequest, m *pilosa.BlockDataRequest) {
@@ -995,11 +1345,14 @@ func decodeBlockDataRequest(pb *internal.BlockDataRequest, m *pilosa.BlockDataRe
 	m.View = pb.View
 	m.Shard = pb.Shard
 	m.Block = pb.Block
+	m.Offset = pb.Offset
+	m.Limit = pb.Limit
 }
 
 func decodeBlockDataResponse(pb *internal.BlockDataResponse, m *pilosa.BlockDataResponse) {
 	m.RowIDs = pb.RowIDs
 	m.ColumnIDs = pb.ColumnIDs
+	m.More = pb.More
 }
 
 func decodeQueryResponse(pb *internal.QueryResponse, m *pilosa.QueryResponse) {
@@ -1008,10 +1361,124 @@ func decodeQueryResponse(pb *internal.QueryResponse, m *pilosa.QueryResponse) {
 	if pb.Err == "" {
 		m.Err = nil
 	} else {
-		m.Err = errors.New(pb.Err)
+		m.Err = pilosa.ErrorWithCode(pb.ErrCode, pb.Err)
 	}
 	m.Results = make([]interface{}, len(pb.Results))
 	decodeQueryResults(pb.Results, m.Results)
+	m.TimeRoundings = decodeTimeRoundings(pb.TimeRoundings)
+	m.Profile = decodeQueryProfile(pb.Profile)
+}
+
+func encodeQueryProfile(m *pilosa.QueryProfile) *internal.QueryProfile {
+	if m == nil {
+		return nil
+	}
+	pb := &internal.QueryProfile{Parse: int64(m.Parse)}
+	for _, c := range m.Calls {
+		pb.Calls = append(pb.Calls, encodeCallProfile(c))
+	}
+	return pb
+}
+
+func encodeCallProfile(m *pilosa.CallProfile) *internal.CallProfile {
+	if m == nil {
+		return nil
+	}
+	pb := &internal.CallProfile{
+		Name:     m.Name,
+		Duration: int64(m.Duration),
+		Reduce:   int64(m.Reduce),
+		Exact:    m.Exact,
+	}
+	for _, s := range m.Shards {
+		pb.Shards = append(pb.Shards, &internal.ShardProfile{Shard: s.Shard, Duration: int64(s.Duration)})
+	}
+	for _, n := range m.Nodes {
+		pb.Nodes = append(pb.Nodes, &internal.NodeProfile{
+			ID:       n.ID,
+			Shards:   n.Shards,
+			Duration: int64(n.Duration),
+			Call:     encodeCallProfile(n.Call),
+			Err:      n.Err,
+		})
+	}
+	for _, c := range m.Children {
+		pb.Children = append(pb.Children, encodeCallProfile(c))
+	}
+	return pb
+}
+
+func decodeQueryProfile(pb *internal.QueryProfile) *pilosa.QueryProfile {
+	if pb == nil {
+		return nil
+	}
+	m := &pilosa.QueryProfile{Parse: time.Duration(pb.Parse)}
+	for _, c := range pb.Calls {
+		m.Calls = append(m.Calls, decodeCallProfile(c))
+	}
+	return m
+}
+
+func decodeCallProfile(pb *internal.CallProfile) *pilosa.CallProfile {
+	if pb == nil {
+		return nil
+	}
+	m := &pilosa.CallProfile{
+		Name:     pb.Name,
+		Duration: time.Duration(pb.Duration),
+		Reduce:   time.Duration(pb.Reduce),
+		Exact:    pb.Exact,
+	}
+	for _, s := range pb.Shards {
+		m.Shards = append(m.Shards, &pilosa.ShardProfile{Shard: s.Shard, Duration: time.Duration(s.Duration)})
+	}
+	for _, n := range pb.Nodes {
+		m.Nodes = append(m.Nodes, &pilosa.NodeProfile{
+			ID:       n.ID,
+			Shards:   n.Shards,
+			Duration: time.Duration(n.Duration),
+			Call:     decodeCallProfile(n.Call),
+			Err:      n.Err,
+		})
+	}
+	for _, c := range pb.Children {
+		m.Children = append(m.Children, decodeCallProfile(c))
+	}
+	return m
+}
+
+func encodeTimeRoundings(a []*pilosa.TimeRounding) []*internal.TimeRounding {
+	if len(a) == 0 {
+		return nil
+	}
+	other := make([]*internal.TimeRounding, len(a))
+	for i, r := range a {
+		other[i] = &internal.TimeRounding{
+			Field:       r.Field,
+			Arg:         r.Arg,
+			Time:        r.Time.UnixNano(),
+			Rounded:     r.Rounded.UnixNano(),
+			Granularity: r.Granularity,
+		}
+	}
+	return other
+}
+
+func decodeTimeRoundings(a []*internal.TimeRounding) []*pilosa.TimeRounding {
+	if len(a) == 0 {
+		return nil
+	}
+	other := make([]*pilosa.TimeRounding, len(a))
+	for i, r := range a {
+		other[i] = &pilosa.TimeRounding{
+			Field:       r.Field,
+			Arg:         r.Arg,
+			Time:        time.Unix(0, r.Time).UTC(),
+			Rounded:     time.Unix(0, r.Rounded).UTC(),
+			Granularity: r.Granularity,
+		}
+	}
+	return other
 }
 
 func decodeColumnAttrSets(pb []*internal.ColumnAttrSet, m []*pilosa.ColumnAttrSet) {
@@ -1054,6 +1521,8 @@ const (
 	queryResultTypeRowIDs
 	queryResultTypeGroupCounts
 	queryResultTypeRowIdentifiers
+	queryResultTypeTimeCounts
+	queryResultTypeBools
 )
 
 func decodeQueryResult(pb *internal.QueryResult) interface{} {
@@ -1076,6 +1545,13 @@ func decodeQueryResult(pb *internal.QueryResult) interface{} {
 		return decodeRowIdentifiers(pb.RowIdentifiers)
 	case queryResultTypeGroupCounts:
 		return decodeGroupCounts(pb.GroupCounts)
+	case queryResultTypeTimeCounts:
+		return decodeTimeCounts(pb.TimeCounts)
+	case queryResultTypeBools:
+		if pb.Bools == nil {
+			return []bool{}
+		}
+		return pb.Bools
 	}
 	panic(fmt.Sprintf("unknown type: %d", pb.Type))
 }
@@ -1144,6 +1620,17 @@ func decodeGroupCounts(a []*internal.GroupCount) []pilosa.GroupCount {
 	return other
 }
 
+func decodeTimeCounts(a []*internal.TimeCount) []pilosa.TimeCount {
+	other := make([]pilosa.TimeCount, len(a))
+	for i := range a {
+		other[i] = pilosa.TimeCount{
+			Time:  time.Unix(0, a[i].Time).UTC(),
+			Count: a[i].Count,
+		}
+	}
+	return other
+}
+
 func decodeFieldRows(a []*internal.FieldRow) []pilosa.FieldRow {
 	other := make([]pilosa.FieldRow, len(a))
 	for i := range a {
@@ -1228,6 +1715,17 @@ func encodeGroupCounts(counts []pilosa.GroupCount) []*internal.GroupCount {
 	return result
 }
 
+func encodeTimeCounts(counts []pilosa.TimeCount) []*internal.TimeCount {
+	result := make([]*internal.TimeCount, len(counts))
+	for i := range counts {
+		result[i] = &internal.TimeCount{
+			Time:  counts[i].Time.UnixNano(),
+			Count: counts[i].Count,
+		}
+	}
+	return result
+}
+
 func encodeFieldRows(a []pilosa.FieldRow) []*internal.FieldRow {
 	other := make([]*internal.FieldRow, len(a))
 	for i := range a {
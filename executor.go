@@ -18,10 +18,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
 	"github.com/pilosa/pilosa/pql"
+	"github.com/pilosa/pilosa/roaring"
 	"github.com/pilosa/pilosa/tracing"
 	"github.com/pkg/errors"
 )
@@ -36,6 +38,9 @@ const (
 
 	columnLabel = "col"
 	rowLabel    = "row"
+
+	// maxCountRangePeriods is the most periods a CountRange() call may count.
+	maxCountRangePeriods = 10000
 )
 
 // executor recursively executes calls in a PQL query across all shards.
@@ -52,6 +57,18 @@ type executor struct {
 	// Maximum number of Set() or Clear() commands per request.
 	MaxWritesPerRequest int
 
+	// Maximum number of columns listed by a ConstRow() call. Zero is
+	// unlimited.
+	MaxConstRowColumns int
+
+	// Maximum time a query runs for when its context has no deadline.
+	// Zero is unlimited.
+	MaxQueryTime time.Duration
+
+	// DisableFailover fails a read when a node holding some of its shards
+	// is unavailable, instead of retrying those shards against replicas.
+	DisableFailover bool
+
 	// Stores key/id translation data.
 	TranslateStore TranslateStore
 }
@@ -87,6 +104,13 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 
 	resp := QueryResponse{}
 
+	// Queries without a deadline of their own time out after MaxQueryTime.
+	if _, ok := ctx.Deadline(); !ok && e.MaxQueryTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.MaxQueryTime)
+		defer cancel()
+	}
+
 	// Check for query cancellation.
 	if err := validateQueryContext(ctx); err != nil {
 		return resp, err
@@ -105,6 +129,8 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 	// Verify that the number of writes do not exceed the maximum.
 	if e.MaxWritesPerRequest > 0 && q.WriteCallN() > e.MaxWritesPerRequest {
 		return resp, ErrTooManyWrites
+	} else if e.Holder.ReadOnly && writesData(q.Calls) {
+		return resp, newForbiddenError(ErrReadOnly)
 	}
 
 	// Default options.
@@ -112,39 +138,118 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 		opt = &execOptions{}
 	}
 
-	// Translate query keys to ids, if necessary.
-	// No need to translate a remote call.
+	// Column attribute filters are applied by the coordinator once the
+	// results are reduced, so they are taken out of the calls before they
+	// are translated or sent to other nodes.
+	filters, err := extractColumnAttrFilters(q.Calls)
+	if err != nil {
+		return resp, err
+	}
+
+	// Translate labels to argument names and query keys to ids, if
+	// necessary. No need to translate a remote call.
 	if !opt.Remote {
-		if err := e.translateCalls(ctx, index, idx, q.Calls); err != nil {
+		if err := translateLabels(idx, q.Calls); err != nil {
+			return resp, err
+		} else if err := e.translateCalls(ctx, index, idx, q.Calls); err != nil {
 			return resp, err
 		} else if err := validateQueryContext(ctx); err != nil {
 			return resp, err
 		}
 	}
 
+	// The columns of ConstRow() calls are checked and sorted once, rather
+	// than by every shard.
+	if err := e.checkConstRows(q.Calls); err != nil {
+		return resp, err
+	}
+
+	// Fields being copied into can't be used until the copy is ready.
+	if err := e.checkFieldsCopying(index, q.Calls); err != nil {
+		return resp, err
+	}
+
+	// Time ranges which views can't answer exactly are rejected or rounded
+	// before any shard is read.
+	roundings, err := e.checkTimeRanges(idx, q.Calls)
+	if err != nil {
+		return resp, err
+	}
+
+	// Views named by calls must exist. Remote calls have been checked by
+	// the coordinator, and their node may not hold the view yet.
+	if !opt.Remote {
+		if err := checkViews(idx, q.Calls); err != nil {
+			return resp, err
+		}
+	}
+
+	// Fields named by calls, to check that none was renamed or deleted
+	// while the query read it.
+	fields := callFields(idx, q.Calls, make(map[string]*Field))
+
+	// A profiled query records where its time goes in a profile mirroring
+	// its calls, which the executor finds in the context.
+	var profiler *queryProfiler
+	if opt.Profile {
+		profiler = newQueryProfiler(q.Calls)
+		ctx = withQueryProfiler(ctx, profiler)
+	}
+
 	results, err := e.execute(ctx, index, q, shards, opt)
+
+	// Results or errors of reading an index or field while it was being
+	// deleted or renamed may be incomplete or misleading.
+	if e.Holder.Index(index) != idx {
+		return resp, ErrIndexNotFound
+	}
+	for name, f := range fields {
+		if idx.Field(name) != f {
+			return resp, ErrFieldNotFound
+		}
+	}
+
 	if err != nil {
 		return resp, err
 	} else if err := validateQueryContext(ctx); err != nil {
 		return resp, err
 	}
 
+	// Drop the columns whose attributes don't match the filter of their call.
+	for i, f := range filters {
+		if f == nil {
+			continue
+		}
+		row, ok := results[i].(*Row)
+		if !ok {
+			continue
+		}
+		if results[i], err = e.filterColumnAttrs(ctx, idx, row, f); err != nil {
+			return resp, err
+		}
+	}
+
 	resp.Results = results
+	resp.TimeRoundings = roundings
+	if profiler != nil {
+		resp.Profile = profiler.finish()
+	}
 
-	// Fill column attributes if requested.
-	if opt.ColumnAttrs {
-		// Consolidate all column ids across all calls.
+	// Fill column attributes of the calls which requested them.
+	if attrCalls := columnAttrCalls(q.Calls, opt.ColumnAttrs); attrCalls != nil {
+		// Consolidate the column ids across the calls, so that columns in
+		// several results are only read once.
 		var columnIDs []uint64
-		for _, result := range results {
+		for i, result := range results {
 			bm, ok := result.(*Row)
-			if !ok {
+			if !ok || !attrCalls[i] {
 				continue
 			}
 			columnIDs = uint64Slice(columnIDs).merge(bm.Columns())
 		}
 
-		// Retrieve column attributes across all calls.
-		columnAttrSets, err := e.readColumnAttrSets(e.Holder.Index(index), columnIDs)
+		// Retrieve column attributes across all calls at once.
+		columnAttrSets, err := e.readColumnAttrSets(idx, columnIDs)
 		if err != nil {
 			return resp, errors.Wrap(err, "reading column attrs")
 		}
@@ -176,27 +281,175 @@ func (e *executor) Execute(ctx context.Context, index string, q *pql.Query, shar
 	return resp, nil
 }
 
+// columnAttrCalls returns whether the column attributes of the result of each
+// call are returned, or nil if none are. The columnAttrs argument of an
+// Options() call overrides all, which is whether the query requested them.
+func columnAttrCalls(calls []*pql.Call, all bool) []bool {
+	var a []bool
+	for i, c := range calls {
+		want := all
+		if c.Name == "Options" {
+			if v, ok := c.Args["columnAttrs"].(bool); ok {
+				want = v
+			}
+		}
+		if !want {
+			continue
+		} else if a == nil {
+			a = make([]bool, len(calls))
+		}
+		a[i] = true
+	}
+	return a
+}
+
 // readColumnAttrSets returns a list of column attribute objects by id.
 func (e *executor) readColumnAttrSets(index *Index, ids []uint64) ([]*ColumnAttrSet, error) {
 	if index == nil {
 		return nil, nil
 	}
 
-	ax := make([]*ColumnAttrSet, 0, len(ids))
+	// Read the attributes of all the columns at once.
+	m, err := index.ColumnAttrStore().BlockAttrs(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attrs")
+	}
+
+	// Append the columns with attributes, in order.
+	ax := make([]*ColumnAttrSet, 0, len(m))
 	for _, id := range ids {
-		// Read attributes for column. Skip column if empty.
-		attrs, err := index.ColumnAttrStore().Attrs(id)
-		if err != nil {
-			return nil, errors.Wrap(err, "getting attrs")
-		} else if len(attrs) == 0 {
+		if attrs := m[id]; len(attrs) > 0 {
+			ax = append(ax, &ColumnAttrSet{ID: id, Attrs: attrs})
+		}
+	}
+
+	return ax, nil
+}
+
+// columnAttrFilterBatchSize is the number of columns whose attributes are
+// read at once when filtering a result.
+const columnAttrFilterBatchSize = 1000
+
+// columnAttrFilter keeps the columns of a result whose attributes equal all
+// of its attributes, up to limit columns if limit isn't zero.
+type columnAttrFilter struct {
+	attrs map[string]interface{}
+	limit uint64
+}
+
+// extractColumnAttrFilters removes the filter and limit arguments of the
+// Row(), Intersect() and Union() calls of a query, optionally wrapped in
+// Options(), and returns the filter of each call, or nil if it has none. A
+// filter is given as Attrs(key=value, ...), so Row(f=1) with a filter
+// argument which isn't a call still reads a field named filter.
+func extractColumnAttrFilters(calls []*pql.Call) ([]*columnAttrFilter, error) {
+	var filters []*columnAttrFilter
+	for i, c := range calls {
+		if c.Name == "Options" && len(c.Children) == 1 {
+			c = c.Children[0]
+		}
+		fc, ok := c.Args["filter"].(*pql.Call)
+		if !ok {
 			continue
 		}
+		switch c.Name {
+		case "Row", "Intersect", "Union":
+		default:
+			continue
+		}
+		if fc.Name != "Attrs" || len(fc.Children) > 0 || len(fc.Args) == 0 {
+			return nil, errors.Errorf("%s(): filter must be Attrs() with at least one attribute", c.Name)
+		}
+		limit, _, err := c.UintArg("limit")
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s(): reading limit", c.Name)
+		}
+		delete(c.Args, "filter")
+		delete(c.Args, "limit")
 
-		// Append column with attributes.
-		ax = append(ax, &ColumnAttrSet{ID: id, Attrs: attrs})
+		if filters == nil {
+			filters = make([]*columnAttrFilter, len(calls))
+		}
+		filters[i] = &columnAttrFilter{attrs: fc.Args, limit: limit}
 	}
+	return filters, nil
+}
 
-	return ax, nil
+// match returns true if attrs has every attribute of the filter. A nil
+// filter value matches a column without the attribute, and a column whose
+// attribute is nil doesn't have it. Setting an attribute to nil removes it,
+// so a stored nil value is only seen for a value of an unknown type.
+func (f *columnAttrFilter) match(attrs map[string]interface{}) bool {
+	for k, want := range f.attrs {
+		got := attrs[k]
+		if want == nil {
+			if got != nil {
+				return false
+			}
+		} else if got == nil || !attrValuesEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// attrValuesEqual returns true if two attribute values are equal. Integers
+// and floats are compared by value, so age=30 matches an age of 30.0.
+func attrValuesEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return a == b
+		case float64:
+			return float64(a) == b
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return a == float64(b)
+		case float64:
+			return a == b
+		}
+	}
+	return a == b
+}
+
+// filterColumnAttrs returns the columns of row whose attributes match f. The
+// attributes are read in batches, and no more are read once the limit of
+// the filter is reached.
+func (e *executor) filterColumnAttrs(ctx context.Context, idx *Index, row *Row, f *columnAttrFilter) (*Row, error) {
+	columns := row.Columns()
+	var matched []uint64
+	for len(columns) > 0 && (f.limit == 0 || uint64(len(matched)) < f.limit) {
+		if err := validateQueryContext(ctx); err != nil {
+			return nil, err
+		}
+
+		batch := columns
+		if len(batch) > columnAttrFilterBatchSize {
+			batch = batch[:columnAttrFilterBatchSize]
+		}
+		columns = columns[len(batch):]
+
+		attrs, err := idx.ColumnAttrStore().BlockAttrs(batch)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading column attrs")
+		}
+		for _, id := range batch {
+			if !f.match(attrs[id]) {
+				continue
+			}
+			matched = append(matched, id)
+			if f.limit > 0 && uint64(len(matched)) == f.limit {
+				break
+			}
+		}
+	}
+
+	other := NewRow(matched...)
+	other.Attrs = row.Attrs
+	return other, nil
 }
 
 func (e *executor) execute(ctx context.Context, index string, q *pql.Query, shards []uint64, opt *execOptions) ([]interface{}, error) {
@@ -214,29 +467,37 @@ func (e *executor) execute(ctx context.Context, index string, q *pql.Query, shar
 		if idx == nil {
 			return nil, ErrIndexNotFound
 		}
-		shards = idx.AvailableShards().Slice()
+		shards = constRowShards(idx, q.Calls, idx.AvailableShards()).Slice()
 		if len(shards) == 0 {
 			shards = []uint64{0}
 		}
 	}
 
-	// Optimize handling for bulk attribute insertion.
-	if hasOnlySetRowAttrs(q.Calls) {
-		return e.executeBulkSetRowAttrs(ctx, index, q.Calls, opt)
-	}
-
-	// Execute each call serially.
+	// Execute each call serially. Consecutive SetRowAttrs() calls are
+	// executed together, so that the attributes of each field are set in a
+	// single transaction.
 	results := make([]interface{}, 0, len(q.Calls))
-	for _, call := range q.Calls {
+	for i := 0; i < len(q.Calls); {
 		if err := validateQueryContext(ctx); err != nil {
 			return nil, err
 		}
 
-		v, err := e.executeCall(ctx, index, call, shards, opt)
+		if n := setRowAttrsRunLen(q.Calls[i:]); n > 1 {
+			v, err := e.executeBulkSetRowAttrs(ctx, index, q.Calls[i:i+n], opt)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, v...)
+			i += n
+			continue
+		}
+
+		v, err := e.executeCall(ctx, index, q.Calls[i], shards, opt)
 		if err != nil {
 			return nil, err
 		}
 		results = append(results, v)
+		i++
 	}
 	return results, nil
 }
@@ -251,6 +512,9 @@ func (e *executor) executeCall(ctx context.Context, index string, c *pql.Call, s
 	} else if err := e.validateCallArgs(c); err != nil {
 		return nil, errors.Wrap(err, "validating args")
 	}
+	if p := queryProfilerFrom(ctx); p != nil {
+		defer p.call(c, p.start())
+	}
 	indexTag := fmt.Sprintf("index:%s", index)
 	// Special handling for mutation and top-n calls.
 	switch c.Name {
@@ -272,6 +536,12 @@ func (e *executor) executeCall(ctx context.Context, index string, c *pql.Call, s
 	case "Count":
 		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
 		return e.executeCount(ctx, index, c, shards, opt)
+	case "CountRange":
+		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		return e.executeCountRange(ctx, index, c, shards, opt)
+	case "Contains":
+		e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{indexTag})
+		return e.executeContains(ctx, index, c, shards, opt)
 	case "Set":
 		return e.executeSet(ctx, index, c, opt)
 	case "SetRowAttrs":
@@ -320,10 +590,10 @@ func (e *executor) executeOptionsCall(ctx context.Context, index string, c *pql.
 
 	optCopy := &execOptions{}
 	*optCopy = *opt
+	// Column attributes are filled in by Execute for the result of this call
+	// only, so the argument is just checked here.
 	if arg, ok := c.Args["columnAttrs"]; ok {
-		if value, ok := arg.(bool); ok {
-			opt.ColumnAttrs = value
-		} else {
+		if _, ok := arg.(bool); !ok {
 			return nil, errors.New("Query(): columnAttrs must be a bool")
 		}
 	}
@@ -545,6 +815,10 @@ func (e *executor) executeBitmapCallShard(ctx context.Context, index string, c *
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeBitmapCallShard")
 	defer span.Finish()
 
+	if p := queryProfilerFrom(ctx); p != nil {
+		defer p.shard(c, shard, p.start(), false)
+	}
+
 	switch c.Name {
 	case "Row", "Range":
 		return e.executeRowShard(ctx, index, c, shard)
@@ -560,11 +834,178 @@ func (e *executor) executeBitmapCallShard(ctx context.Context, index string, c *
 		return e.executeNotShard(ctx, index, c, shard)
 	case "Shift":
 		return e.executeShiftShard(ctx, index, c, shard)
+	case "ConstRow":
+		return e.executeConstRowShard(ctx, index, c, shard)
 	default:
 		return nil, fmt.Errorf("unknown call: %s", c.Name)
 	}
 }
 
+// streamShardWindow is the number of shards a streamed query reads ahead of
+// the shard whose columns are being written.
+const streamShardWindow = 4
+
+// streamColumnChunk is the largest number of columns passed to the function
+// of a streamed query at once.
+const streamColumnChunk = 1 << 16
+
+// ExecuteStream executes a query of a single call returning a row, and calls
+// fn with the columns of the row in ascending order, as the row of each shard
+// arrives, rather than merging the rows of every shard. The slice passed to
+// fn is reused once it returns. It returns the number of columns.
+func (e *executor) ExecuteStream(ctx context.Context, index string, q *pql.Query, shards []uint64, opt *execOptions, fn func(columns []uint64) error) (uint64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.ExecuteStream")
+	defer span.Finish()
+
+	// Queries without a deadline of their own time out after MaxQueryTime.
+	if _, ok := ctx.Deadline(); !ok && e.MaxQueryTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.MaxQueryTime)
+		defer cancel()
+	}
+
+	if err := validateQueryContext(ctx); err != nil {
+		return 0, err
+	} else if index == "" {
+		return 0, ErrIndexRequired
+	}
+	idx := e.Holder.Index(index)
+	if idx == nil {
+		return 0, ErrIndexNotFound
+	}
+	if opt == nil {
+		opt = &execOptions{}
+	}
+
+	// Only the columns of a row are streamed, so there must be nothing else
+	// to return, and the columns must not need translating to keys.
+	if len(q.Calls) != 1 {
+		return 0, NewBadRequestError(ErrQueryNotStreamable)
+	}
+	c := q.Calls[0]
+	switch c.Name {
+	case "Row", "Range", "Difference", "Intersect", "Union", "Xor", "Not", "Shift", "ConstRow":
+	default:
+		return 0, NewBadRequestError(errors.Wrapf(ErrQueryNotStreamable, "%s()", c.Name))
+	}
+	if filters, err := extractColumnAttrFilters(q.Calls); err != nil {
+		return 0, err
+	} else if filters != nil {
+		return 0, NewBadRequestError(errors.New("column attribute filters can't be streamed"))
+	} else if opt.ColumnAttrs {
+		return 0, NewBadRequestError(errors.New("column attributes can't be streamed"))
+	} else if idx.Keys() {
+		return 0, NewBadRequestError(errors.New("queries of indexes with keys can't be streamed"))
+	}
+
+	if err := translateLabels(idx, q.Calls); err != nil {
+		return 0, err
+	} else if err := e.translateCalls(ctx, index, idx, q.Calls); err != nil {
+		return 0, err
+	} else if err := e.checkConstRows(q.Calls); err != nil {
+		return 0, err
+	} else if err := e.checkFieldsCopying(index, q.Calls); err != nil {
+		return 0, err
+	} else if _, err := e.checkTimeRanges(idx, q.Calls); err != nil {
+		return 0, err
+	} else if err := checkViews(idx, q.Calls); err != nil {
+		return 0, err
+	} else if err := e.validateCallArgs(c); err != nil {
+		return 0, errors.Wrap(err, "validating args")
+	}
+	fields := callFields(idx, q.Calls, make(map[string]*Field))
+	e.Holder.Stats.CountWithCustomTags(c.Name, 1, 1.0, []string{fmt.Sprintf("index:%s", index)})
+
+	if len(shards) == 0 {
+		shards = constRowShards(idx, q.Calls, idx.AvailableShards()).Slice()
+	} else {
+		shards = append([]uint64(nil), shards...)
+		sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+	}
+
+	n, err := e.streamShards(ctx, index, c, shards, opt, fn)
+
+	// Results of reading an index or field while it was being deleted or
+	// renamed may be incomplete.
+	if e.Holder.Index(index) != idx {
+		return n, ErrIndexNotFound
+	}
+	for name, f := range fields {
+		if idx.Field(name) != f {
+			return n, ErrFieldNotFound
+		}
+	}
+	return n, err
+}
+
+// streamShards reads the row of c in each shard, on whichever node owns it,
+// and passes its columns to fn in shard order. Shards are read ahead of fn
+// with a bounded window, so only a few shards' rows are held at once.
+func (e *executor) streamShards(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions, fn func(columns []uint64) error) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mapFn := func(shard uint64) (interface{}, error) {
+		return e.executeBitmapCallShard(ctx, index, c, shard)
+	}
+	reduceFn := func(prev, v interface{}) interface{} {
+		other, _ := prev.(*Row)
+		if other == nil {
+			other = NewRow()
+		}
+		other.Merge(v.(*Row))
+		return other
+	}
+
+	// Each shard's result is sent on its own channel, and the channels are
+	// queued in shard order.
+	type shardResult struct {
+		row *Row
+		err error
+	}
+	pending := make(chan chan shardResult, streamShardWindow)
+	go func() {
+		defer close(pending)
+		for _, shard := range shards {
+			ch := make(chan shardResult, 1)
+			select {
+			case <-ctx.Done():
+				return
+			case pending <- ch:
+			}
+			go func(shard uint64) {
+				v, err := e.mapReduce(ctx, index, []uint64{shard}, c, opt, mapFn, reduceFn)
+				row, _ := v.(*Row)
+				ch <- shardResult{row: row, err: err}
+			}(shard)
+		}
+	}()
+
+	var n uint64
+	buf := make([]uint64, 0, streamColumnChunk)
+	for ch := range pending {
+		var res shardResult
+		select {
+		case <-ctx.Done():
+			return n, validateQueryContext(ctx)
+		case res = <-ch:
+		}
+		if res.err != nil {
+			return n, errors.Wrap(res.err, "map reduce")
+		} else if res.row == nil {
+			continue
+		}
+
+		if err := res.row.forEachColumns(buf, func(columns []uint64) error {
+			n += uint64(len(columns))
+			return fn(columns)
+		}); err != nil {
+			return n, err
+		}
+	}
+	return n, validateQueryContext(ctx)
+}
+
 // executeSumCountShard calculates the sum and count for bsiGroups on a shard.
 func (e *executor) executeSumCountShard(ctx context.Context, index string, c *pql.Call, shard uint64) (ValCount, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSumCountShard")
@@ -591,10 +1032,13 @@ func (e *executor) executeSumCountShard(ctx context.Context, index string, c *pq
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-	if fragment == nil {
+	fragment, err := e.Holder.acquireFragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+	if err != nil {
+		return ValCount{}, err
+	} else if fragment == nil {
 		return ValCount{}, nil
 	}
+	defer fragment.release()
 
 	vsum, vcount, err := fragment.sum(filter, bsig.BitDepth())
 	if err != nil {
@@ -632,10 +1076,13 @@ func (e *executor) executeMinShard(ctx context.Context, index string, c *pql.Cal
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-	if fragment == nil {
+	fragment, err := e.Holder.acquireFragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+	if err != nil {
+		return ValCount{}, err
+	} else if fragment == nil {
 		return ValCount{}, nil
 	}
+	defer fragment.release()
 
 	fmin, fcount, err := fragment.min(filter, bsig.BitDepth())
 	if err != nil {
@@ -673,10 +1120,13 @@ func (e *executor) executeMaxShard(ctx context.Context, index string, c *pql.Cal
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-	if fragment == nil {
+	fragment, err := e.Holder.acquireFragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+	if err != nil {
+		return ValCount{}, err
+	} else if fragment == nil {
 		return ValCount{}, nil
 	}
+	defer fragment.release()
 
 	fmax, fcount, err := fragment.max(filter, bsig.BitDepth())
 	if err != nil {
@@ -703,6 +1153,33 @@ func (e *executor) executeTopN(ctx context.Context, index string, c *pql.Call, s
 	if err != nil {
 		return nil, fmt.Errorf("executeTopN: %v", err)
 	}
+	exact, _, err := c.BoolArg("exact")
+	if err != nil {
+		return nil, fmt.Errorf("executeTopN: %v", err)
+	} else if exact {
+		queryProfilerFrom(ctx).exact(c)
+	}
+	offset, _, err := c.UintArg("offset")
+	if err != nil {
+		return nil, fmt.Errorf("executeTopN: %v", err)
+	}
+
+	// Skipping the first offset pairs needs the top n+offset pairs of every
+	// node, which the cache may not hold.
+	if offset > 0 {
+		if n > 0 && !exact && len(idsArg) == 0 {
+			if err := e.validateTopNCache(index, c, n+offset); err != nil {
+				return nil, err
+			}
+		}
+		clone := c.Clone()
+		queryProfilerFrom(ctx).alias(clone, c)
+		c = clone
+		delete(c.Args, "offset")
+		if n > 0 {
+			c.Args["n"] = n + offset
+		}
+	}
 
 	// Execute original query.
 	pairs, err := e.executeTopNShards(ctx, index, c, shards, opt)
@@ -710,13 +1187,27 @@ func (e *executor) executeTopN(ctx context.Context, index string, c *pql.Call, s
 		return nil, errors.Wrap(err, "finding top results")
 	}
 
+	// Exact queries return the full counts of every matching row from each
+	// shard so the merged results only need to be trimmed by the caller.
+	if exact {
+		span.LogKV("exact", true)
+		if !opt.Remote {
+			pairs = pagePairs(pairs, offset, n)
+		}
+		return pairs, nil
+	}
+
 	// If this call is against specific ids, or we didn't get results,
 	// or we are part of a larger distributed query then don't refetch.
 	if len(pairs) == 0 || len(idsArg) > 0 || opt.Remote {
+		if !opt.Remote && offset > 0 {
+			pairs = pagePairs(pairs, offset, n)
+		}
 		return pairs, nil
 	}
 	// Only the original caller should refetch the full counts.
 	other := c.Clone()
+	queryProfilerFrom(ctx).alias(other, c)
 
 	ids := Pairs(pairs).Keys()
 	sort.Sort(uint64Slice(ids))
@@ -727,10 +1218,41 @@ func (e *executor) executeTopN(ctx context.Context, index string, c *pql.Call, s
 		return nil, errors.Wrap(err, "retrieving full counts")
 	}
 
-	if n != 0 && int(n) < len(trimmedList) {
-		trimmedList = trimmedList[0:n]
+	return pagePairs(trimmedList, offset, n), nil
+}
+
+// pagePairs returns the n pairs after the first offset, or all of them if n
+// is zero.
+func pagePairs(pairs []Pair, offset, n uint64) []Pair {
+	if offset >= uint64(len(pairs)) {
+		return nil
+	}
+	pairs = pairs[offset:]
+	if n != 0 && n < uint64(len(pairs)) {
+		pairs = pairs[:n]
+	}
+	return pairs
+}
+
+// validateTopNCache returns an error if the TopN call c needs more pairs from
+// each shard than the cache of its field holds, as pairs past the end of the
+// cache would be missing from the results rather than ranked.
+func (e *executor) validateTopNCache(index string, c *pql.Call, n uint64) error {
+	fieldName, _ := c.Args["_field"].(string)
+	if fieldName == "" {
+		fieldName = defaultField
 	}
-	return trimmedList, nil
+	f := e.Holder.Field(index, fieldName)
+	if f == nil {
+		return nil
+	}
+	switch opts := f.Options(); opts.CacheType {
+	case CacheTypeRanked, CacheTypeLRU:
+		if n > uint64(opts.CacheSize) {
+			return NewBadRequestError(errors.Errorf("TopN() offset+n %d exceeds the cache size %d of field %s; use exact=true", n, opts.CacheSize, fieldName))
+		}
+	}
+	return nil
 }
 
 func (e *executor) executeTopNShards(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) ([]Pair, error) {
@@ -784,6 +1306,18 @@ func (e *executor) executeTopNShard(ctx context.Context, index string, c *pql.Ca
 	if err != nil {
 		return nil, fmt.Errorf("executeTopNShard: %v", err)
 	}
+	exact, _, err := c.BoolArg("exact")
+	if err != nil {
+		return nil, fmt.Errorf("executeTopNShard: %v", err)
+	}
+
+	// Exact queries scan every row in storage so stop if the query has
+	// been cancelled or timed out.
+	if exact {
+		if err := validateQueryContext(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Retrieve bitmap used to intersect.
 	var src *Row
@@ -802,10 +1336,18 @@ func (e *executor) executeTopNShard(ctx context.Context, index string, c *pql.Ca
 		field = defaultField
 	}
 
-	f := e.Holder.fragment(index, field, viewStandard, shard)
-	if f == nil {
+	view := viewStandard
+	if v, ok := c.Args["view"].(string); ok {
+		view = v
+	}
+
+	f, err := e.Holder.acquireFragment(index, field, view, shard)
+	if err != nil {
+		return nil, err
+	} else if f == nil {
 		return nil, nil
 	}
+	defer f.release()
 
 	if minThreshold == 0 {
 		minThreshold = defaultMinThreshold
@@ -814,7 +1356,7 @@ func (e *executor) executeTopNShard(ctx context.Context, index string, c *pql.Ca
 	if tanimotoThreshold > 100 {
 		return nil, errors.New("Tanimoto Threshold is from 1 to 100 only")
 	}
-	return f.top(topOptions{
+	return f.top(ctx, topOptions{
 		N:                 int(n),
 		Src:               src,
 		RowIDs:            rowIDs,
@@ -822,6 +1364,7 @@ func (e *executor) executeTopNShard(ctx context.Context, index string, c *pql.Ca
 		FilterValues:      attrValues,
 		MinThreshold:      minThreshold,
 		TanimotoThreshold: tanimotoThreshold,
+		Exact:             exact,
 	})
 }
 
@@ -1074,6 +1617,7 @@ func (e *executor) executeGroupByShard(ctx context.Context, index string, c *pql
 	if iter == nil {
 		return []GroupCount{}, nil
 	}
+	defer iter.release()
 
 	limit := int(^uint(0) >> 1)
 	if lim, hasLimit, err := c.UintArg("limit"); err != nil {
@@ -1110,7 +1654,11 @@ func (e *executor) executeRows(ctx context.Context, index string, c *pql.Call, s
 	if columnID, ok, err := c.UintArg("column"); err != nil {
 		return nil, errors.Wrap(err, "getting column")
 	} else if ok {
-		shards = []uint64{columnID / ShardWidth}
+		idx := e.Holder.Index(index)
+		if idx == nil {
+			return nil, ErrIndexNotFound
+		}
+		shards = []uint64{columnID / idx.ShardWidth()}
 	}
 
 	// Execute calls in bulk on each remote node and merge.
@@ -1234,11 +1782,11 @@ func (e *executor) executeRowsShard(_ context.Context, index string, fieldName s
 	if columnID, ok, err := c.UintArg("column"); err != nil {
 		return nil, err
 	} else if ok {
-		colShard := columnID >> shardWidthExponent
+		colShard := columnID / f.shardWidth
 		if colShard != shard {
 			return rowIDs, nil
 		}
-		filters = append(filters, filterColumn(columnID))
+		filters = append(filters, filterColumn(columnID, f.shardWidth))
 	}
 
 	limit := int(^uint(0) >> 1)
@@ -1250,12 +1798,15 @@ func (e *executor) executeRowsShard(_ context.Context, index string, fieldName s
 	}
 
 	for _, view := range views {
-		frag := e.Holder.fragment(index, fieldName, view, shard)
-		if frag == nil {
+		frag, err := e.Holder.acquireFragment(index, fieldName, view, shard)
+		if err != nil {
+			return nil, err
+		} else if frag == nil {
 			continue
 		}
 
 		viewRows := frag.rows(start, filters...)
+		frag.release()
 		rowIDs = rowIDs.merge(viewRows, limit)
 	}
 
@@ -1314,12 +1865,19 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 		}
 	}
 
-	// Simply return row if times are not set.
+	// Simply return row if times are not set, from the view if one is named.
 	if c.Name == "Row" && fromTime.IsZero() && toTime.IsZero() {
-		frag := e.Holder.fragment(index, fieldName, viewStandard, shard)
-		if frag == nil {
+		view := viewStandard
+		if v, ok := c.Args["view"].(string); ok {
+			view = v
+		}
+		frag, err := e.Holder.acquireFragment(index, fieldName, view, shard)
+		if err != nil {
+			return nil, err
+		} else if frag == nil {
 			return NewRow(), nil
 		}
+		defer frag.release()
 		return frag.row(rowID), nil
 	}
 
@@ -1336,14 +1894,22 @@ func (e *executor) executeRowShard(ctx context.Context, index string, c *pql.Cal
 		toTime = time.Now().AddDate(0, 0, 1)
 	}
 
+	// Widen the range to the periods of the smallest unit containing it.
+	// Unaligned bounds have been rejected by checkTimeRanges unless
+	// precise=true was given.
+	fromTime, toTime = roundTime(fromTime, q, false), roundTime(toTime, q, true)
+
 	// Union bitmaps across all time-based views.
 	row := &Row{}
 	for _, view := range viewsByTimeRange(viewStandard, fromTime, toTime, q) {
-		f := e.Holder.fragment(index, fieldName, view, shard)
-		if f == nil {
+		f, err := e.Holder.acquireFragment(index, fieldName, view, shard)
+		if err != nil {
+			return nil, err
+		} else if f == nil {
 			continue
 		}
 		row = row.Union(f.row(rowID))
+		f.release()
 	}
 	f.Stats.Count("range", 1, 1.0)
 	return row, nil
@@ -1394,10 +1960,13 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 		}
 
 		// Retrieve fragment.
-		frag := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-		if frag == nil {
+		frag, err := e.Holder.acquireFragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+		if err != nil {
+			return nil, err
+		} else if frag == nil {
 			return NewRow(), nil
 		}
+		defer frag.release()
 
 		return frag.notNull(bsig.BitDepth())
 
@@ -1429,10 +1998,13 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 		}
 
 		// Retrieve fragment.
-		frag := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-		if frag == nil {
+		frag, err := e.Holder.acquireFragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+		if err != nil {
+			return nil, err
+		} else if frag == nil {
 			return NewRow(), nil
 		}
+		defer frag.release()
 
 		// If the query is asking for the entire valid range, just return
 		// the not-null bitmap for the bsiGroup.
@@ -1462,10 +2034,13 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, index string, c
 		}
 
 		// Retrieve fragment.
-		frag := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-		if frag == nil {
+		frag, err := e.Holder.acquireFragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
+		if err != nil {
+			return nil, err
+		} else if frag == nil {
 			return NewRow(), nil
 		}
+		defer frag.release()
 
 		// LT[E] and GT[E] should return all not-null if selected range fully encompasses valid bsiGroup range.
 		if (cond.Op == pql.LT && value > bsig.Max) || (cond.Op == pql.LTE && value >= bsig.Max) ||
@@ -1492,7 +2067,14 @@ func (e *executor) executeIntersectShard(ctx context.Context, index string, c *p
 	if len(c.Children) == 0 {
 		return nil, fmt.Errorf("empty Intersect query is currently not supported")
 	}
-	for i, input := range c.Children {
+
+	// Intersect the inputs from the smallest estimated count up, so that the
+	// intersection shrinks as early as possible, and stop once it's empty.
+	children, err := e.orderByEstimatedCount(index, c.Children, shard)
+	if err != nil {
+		return nil, err
+	}
+	for i, input := range children {
 		row, err := e.executeBitmapCallShard(ctx, index, input, shard)
 		if err != nil {
 			return nil, err
@@ -1503,24 +2085,95 @@ func (e *executor) executeIntersectShard(ctx context.Context, index string, c *p
 		} else {
 			other = other.Intersect(row)
 		}
+		if other.IsEmpty() {
+			break
+		}
 	}
 	other.invalidateCount()
 	return other, nil
 }
 
+// orderByEstimatedCount returns the calls ordered by the estimated counts of
+// their rows in the shard. Calls whose counts aren't estimated keep their
+// order after those which are.
+func (e *executor) orderByEstimatedCount(index string, calls []*pql.Call, shard uint64) ([]*pql.Call, error) {
+	type estimate struct {
+		call *pql.Call
+		n    uint64
+	}
+	estimates := make([]estimate, len(calls))
+	for i, call := range calls {
+		n, ok, err := e.estimateRowCount(index, call, shard)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			n = math.MaxUint64
+		}
+		estimates[i] = estimate{call: call, n: n}
+	}
+	sort.SliceStable(estimates, func(i, j int) bool { return estimates[i].n < estimates[j].n })
+
+	ordered := make([]*pql.Call, len(calls))
+	for i := range estimates {
+		ordered[i] = estimates[i].call
+	}
+	return ordered, nil
+}
+
+// estimateRowCount returns an estimate of the number of columns set in the
+// shard for a Row call without times or conditions. The estimate is the
+// count in the fragment's cache, which may be stale, or else the count of the
+// row's containers. It returns false for other calls.
+func (e *executor) estimateRowCount(index string, c *pql.Call, shard uint64) (uint64, bool, error) {
+	if c.Name != "Row" || c.HasConditionArg() {
+		return 0, false, nil
+	} else if _, ok := c.Args["from"]; ok {
+		return 0, false, nil
+	} else if _, ok := c.Args["to"]; ok {
+		return 0, false, nil
+	}
+
+	fieldName, err := c.FieldArg()
+	if err != nil {
+		return 0, false, nil
+	} else if e.Holder.Field(index, fieldName) == nil {
+		return 0, false, ErrFieldNotFound
+	}
+	rowID, ok, err := c.UintArg(fieldName)
+	if err != nil || !ok {
+		return 0, false, nil
+	}
+
+	view := viewStandard
+	if v, ok := c.Args["view"].(string); ok {
+		view = v
+	}
+	frag, err := e.Holder.acquireFragment(index, fieldName, view, shard)
+	if err != nil {
+		return 0, false, err
+	} else if frag == nil {
+		return 0, true, nil
+	}
+	defer frag.release()
+	return frag.estimateRowCount(rowID), true, nil
+}
+
 // executeUnionShard executes a union() call for a local shard.
 func (e *executor) executeUnionShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeUnionShard")
 	defer span.Finish()
 
 	other := NewRow()
-	for i, input := range c.Children {
+	for _, input := range c.Children {
 		row, err := e.executeBitmapCallShard(ctx, index, input, shard)
 		if err != nil {
 			return nil, err
 		}
 
-		if i == 0 {
+		// Skip empty inputs, which don't change the union.
+		if row.IsEmpty() {
+			continue
+		} else if other.IsEmpty() {
 			other = row
 		} else {
 			other = other.Union(row)
@@ -1572,75 +2225,386 @@ func (e *executor) executeNotShard(ctx context.Context, index string, c *pql.Cal
 	}
 
 	var existenceRow *Row
-	existenceFrag := e.Holder.fragment(index, existenceFieldName, viewStandard, shard)
-	if existenceFrag == nil {
+	existenceFrag, err := e.Holder.acquireFragment(index, existenceFieldName, viewStandard, shard)
+	if err != nil {
+		return nil, err
+	} else if existenceFrag == nil {
 		existenceRow = NewRow()
 	} else {
 		existenceRow = existenceFrag.row(0)
+		existenceFrag.release()
+	}
+
+	row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+	if err != nil {
+		return nil, err
+	}
+
+	return existenceRow.Difference(row), nil
+}
+
+// executeShiftShard executes a shift() call for a local shard.
+func (e *executor) executeShiftShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
+	n, _, err := c.IntArg("n")
+	if err != nil {
+		return nil, fmt.Errorf("executeShiftShard: %v", err)
+	}
+
+	if len(c.Children) == 0 {
+		return nil, errors.New("Shift() requires an input row")
+	} else if len(c.Children) > 1 {
+		return nil, errors.New("Shift() only accepts a single row input")
+	}
+
+	row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.Shift(n)
+}
+
+// executeConstRowShard executes a ConstRow() call for a local shard. Its
+// columns have been sorted by checkConstRows.
+func (e *executor) executeConstRowShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
+	idx := e.Holder.Index(index)
+	if idx == nil {
+		return nil, ErrIndexNotFound
+	}
+	columns, ok := c.Args["columns"].([]uint64)
+	if !ok {
+		return nil, errors.New("ConstRow() columns have not been checked")
+	}
+
+	w := idx.ShardWidth()
+	i := sort.Search(len(columns), func(i int) bool { return columns[i] >= shard*w })
+	j := sort.Search(len(columns), func(i int) bool { return columns[i] >= (shard+1)*w })
+	return NewRow(columns[i:j]...), nil
+}
+
+// executeCount executes a count() call.
+func (e *executor) executeCount(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) (uint64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeCount")
+	defer span.Finish()
+
+	if len(c.Children) == 0 {
+		return 0, errors.New("Count() requires an input bitmap")
+	} else if len(c.Children) > 1 {
+		return 0, errors.New("Count() only accepts a single bitmap input")
+	}
+
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(shard uint64) (interface{}, error) {
+		row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+		if err != nil {
+			return 0, err
+		}
+		return row.Count(), nil
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(prev, v interface{}) interface{} {
+		other, _ := prev.(uint64)
+		return other + v.(uint64)
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.(uint64)
+
+	return n, nil
+}
+
+// TimeCount is the number of columns in a row during one period of a
+// CountRange() series.
+type TimeCount struct {
+	Time  time.Time `json:"timestamp"`
+	Count uint64    `json:"count"`
+}
+
+// executeCountRange executes a CountRange() call, counting the columns of a
+// row in each period of a time range.
+func (e *executor) executeCountRange(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) ([]TimeCount, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeCountRange")
+	defer span.Finish()
+
+	fieldName, err := countRangeField(c)
+	if err != nil {
+		return nil, err
+	}
+	f := e.Holder.Field(index, fieldName)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+	rowID, ok, err := c.UintArg(fieldName)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CountRange() row")
+	} else if !ok {
+		return nil, fmt.Errorf("CountRange() must specify %v", rowLabel)
+	}
+
+	q := f.TimeQuantum()
+	if q == "" {
+		return nil, errors.Errorf("CountRange() field %s has no time quantum", fieldName)
+	}
+	units := q.units()
+	smallest := units[len(units)-1]
+
+	// Each period is one step long, which must be at least the smallest unit
+	// of the quantum.
+	unit := smallest
+	if step, ok := c.Args["step"]; ok {
+		name, _ := step.(string)
+		if unit = timeUnitByName(name); unit == nil {
+			return nil, errors.Errorf("invalid CountRange() step %v, must be year, quarter, month, day, hour or minute", step)
+		} else if timeUnitIndex(unit.char) > timeUnitIndex(smallest.char) {
+			return nil, errors.Errorf("CountRange() step %s is finer than the time quantum %s of field %s", name, q, fieldName)
+		}
+	}
+
+	var from, to time.Time
+	if v, ok := c.Args["from"]; !ok {
+		return nil, errors.New("CountRange() from required")
+	} else if from, err = parseTime(v); err != nil {
+		return nil, errors.Wrap(err, "parsing from time")
+	}
+	if v, ok := c.Args["to"]; !ok {
+		return nil, errors.New("CountRange() to required")
+	} else if to, err = parseTime(v); err != nil {
+		return nil, errors.Wrap(err, "parsing to time")
+	} else if !from.Before(to) {
+		return nil, errors.New("CountRange() from must be before to")
+	}
+
+	// Periods start on a boundary of the step, like the views, so the first
+	// one may start before from. Each is counted from the views covering it,
+	// and periods without views count zero.
+	var periods []TimeCount
+	var periodViews [][]string
+	for t := unit.truncate(from.UTC()); t.Before(to); t = unit.next(t) {
+		if len(periods) == maxCountRangePeriods {
+			return nil, errors.Errorf("CountRange() range holds more than %d periods of %s", maxCountRangePeriods, unit.name)
+		}
+		periods = append(periods, TimeCount{Time: t})
+		periodViews = append(periodViews, viewsByTimeRange(viewStandard, t, unit.next(t), q))
+	}
+
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(shard uint64) (interface{}, error) {
+		counts := make([]TimeCount, len(periods))
+		for i, views := range periodViews {
+			row := &Row{}
+			for _, view := range views {
+				frag, err := e.Holder.acquireFragment(index, fieldName, view, shard)
+				if err != nil {
+					return nil, err
+				} else if frag != nil {
+					row = row.Union(frag.row(rowID))
+					frag.release()
+				}
+			}
+			counts[i] = TimeCount{Time: periods[i].Time, Count: row.Count()}
+		}
+		return counts, nil
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(prev, v interface{}) interface{} {
+		other, _ := prev.([]TimeCount)
+		counts := v.([]TimeCount)
+		if other == nil {
+			return counts
+		}
+		for i := range other {
+			other[i].Count += counts[i].Count
+		}
+		return other
 	}
 
-	row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
 	if err != nil {
 		return nil, err
 	}
+	if counts, _ := result.([]TimeCount); counts != nil {
+		return counts, nil
+	}
+	return periods, nil
+}
 
-	return existenceRow.Difference(row), nil
+// countRangeField returns the field argument of a CountRange() call, which is
+// the only argument other than the reserved ones and the step.
+func countRangeField(c *pql.Call) (string, error) {
+	for arg := range c.Args {
+		if !pql.IsReservedArg(arg) && arg != "step" {
+			return arg, nil
+		}
+	}
+	return "", errors.New("CountRange() argument required: field")
 }
 
-// executeShiftShard executes a shift() call for a local shard.
-func (e *executor) executeShiftShard(ctx context.Context, index string, c *pql.Call, shard uint64) (*Row, error) {
-	n, _, err := c.IntArg("n")
+// executeContains executes a Contains() call, which returns true if a row
+// holds a column, or, given a list of columns, whether it holds each of them.
+// Each column is only read from the fragment of its shard, and columns in
+// shards which were never written are false.
+func (e *executor) executeContains(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) (interface{}, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeContains")
+	defer span.Finish()
+
+	fieldName, err := containsField(c)
 	if err != nil {
-		return nil, fmt.Errorf("executeShiftShard: %v", err)
+		return nil, err
 	}
-
-	if len(c.Children) == 0 {
-		return nil, errors.New("Shift() requires an input row")
-	} else if len(c.Children) > 1 {
-		return nil, errors.New("Shift() only accepts a single row input")
+	f := e.Holder.Field(index, fieldName)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	} else if f.Type() == FieldTypeInt {
+		return nil, errors.Errorf("Contains() is not supported on %s fields", f.Type())
+	} else if f.options.NoStandardView {
+		return nil, errors.Errorf("Contains() field %s has no standard view", fieldName)
+	}
+	rowID, ok, err := c.UintArg(fieldName)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading Contains() row")
+	} else if !ok {
+		return nil, fmt.Errorf("Contains() must specify %v", rowLabel)
 	}
 
-	row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+	columnIDs, batched, err := containsColumns(c)
 	if err != nil {
 		return nil, err
 	}
 
-	return row.Shift(n)
-}
-
-// executeCount executes a count() call.
-func (e *executor) executeCount(ctx context.Context, index string, c *pql.Call, shards []uint64, opt *execOptions) (uint64, error) {
-	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeCount")
-	defer span.Finish()
-
-	if len(c.Children) == 0 {
-		return 0, errors.New("Count() requires an input bitmap")
-	} else if len(c.Children) > 1 {
-		return 0, errors.New("Count() only accepts a single bitmap input")
+	// Only map the shards holding the columns, so that a single column is
+	// read from the nodes owning its shard.
+	queried := make(map[uint64]struct{}, len(shards))
+	for _, shard := range shards {
+		queried[shard] = struct{}{}
+	}
+	var columnShards []uint64
+	for _, columnID := range columnIDs {
+		shard := columnID / f.shardWidth
+		if _, ok := queried[shard]; ok {
+			columnShards = append(columnShards, shard)
+			delete(queried, shard)
+		}
 	}
 
 	// Execute calls in bulk on each remote node and merge.
 	mapFn := func(shard uint64) (interface{}, error) {
-		row, err := e.executeBitmapCallShard(ctx, index, c.Children[0], shard)
+		found := make([]bool, len(columnIDs))
+		frag, err := e.Holder.acquireFragment(index, fieldName, viewStandard, shard)
 		if err != nil {
-			return 0, err
+			return nil, err
+		} else if frag == nil {
+			return found, nil
 		}
-		return row.Count(), nil
+		defer frag.release()
+		for i, columnID := range columnIDs {
+			if columnID/f.shardWidth != shard {
+				continue
+			}
+			ok, err := frag.contains(rowID, columnID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "checking column %d", columnID)
+			}
+			found[i] = ok
+		}
+		return found, nil
 	}
 
 	// Merge returned results at coordinating node.
 	reduceFn := func(prev, v interface{}) interface{} {
-		other, _ := prev.(uint64)
-		return other + v.(uint64)
+		other, _ := prev.([]bool)
+		found := v.([]bool)
+		if other == nil {
+			return found
+		}
+		for i := range other {
+			other[i] = other[i] || found[i]
+		}
+		return other
 	}
 
-	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
-	if err != nil {
-		return 0, err
+	found := make([]bool, len(columnIDs))
+	if len(columnShards) > 0 {
+		result, err := e.mapReduce(ctx, index, columnShards, c, opt, mapFn, reduceFn)
+		if err != nil {
+			return nil, err
+		}
+		found = result.([]bool)
 	}
-	n, _ := result.(uint64)
+	// Remote nodes always answer with a list, which the coordinator merges.
+	if !batched && !opt.Remote {
+		return found[0], nil
+	}
+	return found, nil
+}
 
-	return n, nil
+// containsField returns the field argument of a Contains() call, which is the
+// only argument other than the reserved ones and its columns.
+func containsField(c *pql.Call) (string, error) {
+	for arg := range c.Args {
+		if !pql.IsReservedArg(arg) && arg != "column" && arg != "columns" {
+			return arg, nil
+		}
+	}
+	return "", errors.New("Contains() argument required: field")
+}
+
+// containsColumns returns the columns of a Contains() call, given either as
+// a single column or as a list of them, and whether they were a list.
+func containsColumns(c *pql.Call) ([]uint64, bool, error) {
+	column, hasColumn := c.Args["column"]
+	columns, hasColumns := c.Args["columns"]
+	if hasColumn == hasColumns {
+		return nil, false, errors.New("Contains() requires one of column or columns")
+	} else if hasColumn {
+		columnID, err := containsColumn(column)
+		if err != nil {
+			return nil, false, err
+		}
+		return []uint64{columnID}, false, nil
+	}
+
+	var a []interface{}
+	switch v := columns.(type) {
+	case []interface{}:
+		a = v
+	case []uint64:
+		for _, columnID := range v {
+			a = append(a, columnID)
+		}
+	default:
+		return nil, false, errors.Errorf("Contains() columns must be a list, but got %T", columns)
+	}
+	columnIDs := make([]uint64, len(a))
+	for i, v := range a {
+		columnID, err := containsColumn(v)
+		if err != nil {
+			return nil, false, err
+		}
+		columnIDs[i] = columnID
+	}
+	return columnIDs, true, nil
+}
+
+// containsColumn returns the ID of a column of a Contains() call.
+func containsColumn(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return 0, errors.Errorf("Contains() column must be positive, but got %d", v)
+		}
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case string:
+		return 0, errors.New("string column value not allowed unless index 'keys' option enabled")
+	default:
+		return 0, errors.Errorf("invalid Contains() column %v of type %T", v, v)
+	}
 }
 
 // executeClearBit executes a Clear() call.
@@ -1686,7 +2650,7 @@ func (e *executor) executeClearBitField(ctx context.Context, index string, c *pq
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeClearBitField")
 	defer span.Finish()
 
-	shard := colID / ShardWidth
+	shard := colID / f.shardWidth
 	ret := false
 	for _, node := range e.Cluster.shardNodes(index, shard) {
 		// Update locally if host matches.
@@ -1783,11 +2747,14 @@ func (e *executor) executeClearRowShard(ctx context.Context, index string, c *pq
 	// Remove the row from all views.
 	changed := false
 	for _, view := range field.views() {
-		fragment := e.Holder.fragment(index, fieldName, view.name, shard)
-		if fragment == nil {
+		fragment, err := e.Holder.acquireFragment(index, fieldName, view.name, shard)
+		if err != nil {
+			return false, err
+		} else if fragment == nil {
 			continue
 		}
 		cleared, err := fragment.clearRow(rowID)
+		fragment.release()
 		if err != nil {
 			return false, errors.Wrapf(err, "clearing row %d on view %s shard %d", rowID, view.name, shard)
 		}
@@ -1864,18 +2831,25 @@ func (e *executor) executeSetRowShard(ctx context.Context, index string, c *pql.
 
 	// Set the row on the standard view.
 	changed := false
-	fragment := e.Holder.fragment(index, fieldName, viewStandard, shard)
-	if fragment == nil {
+	fragment, err := e.Holder.acquireFragment(index, fieldName, viewStandard, shard)
+	if err != nil {
+		return false, err
+	} else if fragment == nil {
 		// Since the destination fragment doesn't exist, create one.
 		view, err := field.createViewIfNotExists(viewStandard)
 		if err != nil {
 			return false, errors.Wrap(err, "creating view")
 		}
-		fragment, err = view.CreateFragmentIfNotExists(shard)
-		if err != nil {
+		if _, err := view.CreateFragmentIfNotExists(shard); err != nil {
 			return false, errors.Wrapf(err, "creating fragment: %d", shard)
 		}
+		if fragment, err = view.acquireFragment(shard); err != nil {
+			return false, err
+		} else if fragment == nil {
+			return false, ErrFragmentNotFound
+		}
 	}
+	defer fragment.release()
 	set, err := fragment.setRow(src, rowID)
 	if err != nil {
 		return false, errors.Wrapf(err, "storing row %d on view %s shard %d", rowID, viewStandard, shard)
@@ -1960,7 +2934,7 @@ func (e *executor) executeSetBitField(ctx context.Context, index string, c *pql.
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSetBitField")
 	defer span.Finish()
 
-	shard := colID / ShardWidth
+	shard := colID / f.shardWidth
 	ret := false
 
 	for _, node := range e.Cluster.shardNodes(index, shard) {
@@ -1995,7 +2969,7 @@ func (e *executor) executeSetValueField(ctx context.Context, index string, c *pq
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSetValueField")
 	defer span.Finish()
 
-	shard := colID / ShardWidth
+	shard := colID / f.shardWidth
 	ret := false
 
 	for _, node := range e.Cluster.shardNodes(index, shard) {
@@ -2234,22 +3208,30 @@ func (e *executor) executeSetColumnAttrs(ctx context.Context, index string, c *p
 
 // remoteExec executes a PQL query remotely for a set of shards on a node.
 func (e *executor) remoteExec(ctx context.Context, node *Node, index string, q *pql.Query, shards []uint64) (results []interface{}, err error) { // nolint: interfacer
+	results, _, err = e.remoteExecProfile(ctx, node, index, q, shards, false)
+	return results, err
+}
+
+// remoteExecProfile executes a PQL query remotely for a set of shards on a
+// node, and returns the node's profile of it if profile is set.
+func (e *executor) remoteExecProfile(ctx context.Context, node *Node, index string, q *pql.Query, shards []uint64, profile bool) ([]interface{}, *QueryProfile, error) { // nolint: interfacer
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeExec")
 	defer span.Finish()
 
 	// Encode request object.
 	pbreq := &QueryRequest{
-		Query:  q.String(),
-		Shards: shards,
-		Remote: true,
+		Query:   q.String(),
+		Shards:  shards,
+		Remote:  true,
+		Profile: profile,
 	}
 
 	pb, err := e.client.QueryNode(ctx, &node.URI, index, pbreq)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return pb.Results, pb.Err
+	return pb.Results, pb.Profile, pb.Err
 }
 
 // shardsByNode returns a mapping of nodes to shards.
@@ -2295,6 +3277,11 @@ func (e *executor) mapReduce(ctx context.Context, index string, shards []uint64,
 		nodes = []*Node{e.Cluster.nodeByID(e.Node.ID)}
 	}
 
+	// The shards of the call are timed here, rather than by the call it is
+	// nested in.
+	p := queryProfilerFrom(ctx)
+	p.mapping(c)
+
 	// Start mapping across all primary owners.
 	if err := e.mapper(ctx, ch, nodes, index, shards, c, opt, mapFn, reduceFn); err != nil {
 		return nil, errors.Wrap(err, "starting mapper")
@@ -2306,12 +3293,22 @@ func (e *executor) mapReduce(ctx context.Context, index string, shards []uint64,
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, errors.Wrap(ctx.Err(), "context done")
+			return nil, validateQueryContext(ctx)
 		case resp := <-ch:
 			// On error retry against remaining nodes. If an error returns then
 			// the context will cancel and cause all open goroutines to return.
-
+			//
+			// Only reads are retried, and only when the node couldn't serve
+			// them, so that a replica never applies a write in place of an
+			// unreachable owner.
 			if resp.err != nil {
+				if !e.canFailover(c, resp.err) {
+					return nil, resp.err
+				}
+				e.Holder.Stats.Count("query.failover", 1, 1.0)
+				e.Holder.Stats.Count("query.failover.shards", int64(len(resp.shards)), 1.0)
+				e.Holder.Logger.Printf("retrying %d shards of %s against replicas of node %s: %s", len(resp.shards), c.Name, resp.node.ID, resp.err)
+
 				// Filter out unavailable nodes.
 				nodes = Nodes(nodes).Filter(resp.node)
 
@@ -2325,7 +3322,9 @@ func (e *executor) mapReduce(ctx context.Context, index string, shards []uint64,
 			}
 
 			// Reduce value.
+			start := p.start()
 			result = reduceFn(result, resp.result)
+			p.reduce(c, start)
 
 			// If all shards have been processed then return.
 			shardN += len(resp.shards)
@@ -2336,6 +3335,16 @@ func (e *executor) mapReduce(ctx context.Context, index string, shards []uint64,
 	}
 }
 
+// canFailover returns true if the shards of c which failed with err can be
+// retried against replicas.
+func (e *executor) canFailover(c *pql.Call, err error) bool {
+	if e.DisableFailover || writesData([]*pql.Call{c}) {
+		return false
+	}
+	_, ok := errors.Cause(err).(NodeUnavailableError)
+	return ok
+}
+
 func (e *executor) mapper(ctx context.Context, ch chan mapResponse, nodes []*Node, index string, shards []uint64, c *pql.Call, opt *execOptions, mapFn mapFunc, reduceFn reduceFunc) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.mapper")
 	defer span.Finish()
@@ -2353,9 +3362,12 @@ func (e *executor) mapper(ctx context.Context, ch chan mapResponse, nodes []*Nod
 
 			// Send local shards to mapper, otherwise remote exec.
 			if n.ID == e.Node.ID {
-				resp.result, resp.err = e.mapperLocal(ctx, nodeShards, mapFn, reduceFn)
+				resp.result, resp.err = e.mapperLocal(ctx, c, nodeShards, mapFn, reduceFn)
 			} else if !opt.Remote {
-				results, err := e.remoteExec(ctx, n, index, &pql.Query{Calls: []*pql.Call{c}}, nodeShards)
+				p := queryProfilerFrom(ctx)
+				start := p.start()
+				results, profile, err := e.remoteExecProfile(ctx, n, index, &pql.Query{Calls: []*pql.Call{c}}, nodeShards, p != nil)
+				p.node(c, n, nodeShards, start, profile, err)
 				if len(results) > 0 {
 					resp.result = results[0]
 				}
@@ -2373,16 +3385,19 @@ func (e *executor) mapper(ctx context.Context, ch chan mapResponse, nodes []*Nod
 	return nil
 }
 
-// mapperLocal performs map & reduce entirely on the local node.
-func (e *executor) mapperLocal(ctx context.Context, shards []uint64, mapFn mapFunc, reduceFn reduceFunc) (interface{}, error) {
+// mapperLocal performs map & reduce of c entirely on the local node.
+func (e *executor) mapperLocal(ctx context.Context, c *pql.Call, shards []uint64, mapFn mapFunc, reduceFn reduceFunc) (interface{}, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.mapperLocal")
 	defer span.Finish()
 
+	p := queryProfilerFrom(ctx)
 	ch := make(chan mapResponse, len(shards))
 
 	for _, shard := range shards {
 		go func(shard uint64) {
+			start := p.start()
 			result, err := mapFn(shard)
+			p.shard(c, shard, start, true)
 
 			// Return response to the channel.
 			select {
@@ -2398,12 +3413,14 @@ func (e *executor) mapperLocal(ctx context.Context, shards []uint64, mapFn mapFu
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, validateQueryContext(ctx)
 		case resp := <-ch:
 			if resp.err != nil {
 				return nil, resp.err
 			}
+			start := p.start()
 			result = reduceFn(result, resp.result)
+			p.reduce(c, start)
 			maxShard++
 		}
 
@@ -2414,6 +3431,284 @@ func (e *executor) mapperLocal(ctx context.Context, shards []uint64, mapFn mapFu
 	}
 }
 
+// checkFieldsCopying returns ErrFieldCopying if any call uses a field which
+// is the destination of a copy which isn't ready. Fields are named by the
+// keys of arguments, or by the values of field arguments.
+func (e *executor) checkFieldsCopying(index string, calls []*pql.Call) error {
+	for _, c := range calls {
+		for k, v := range c.Args {
+			name := k
+			if s, ok := v.(string); ok && (k == "field" || k == "_field") {
+				name = s
+			}
+			if e.Holder.fieldCopying(index, name) {
+				return errors.Wrapf(ErrFieldCopying, "field %s", name)
+			}
+		}
+		if err := e.checkFieldsCopying(index, c.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConstRows ensures that each ConstRow() call lists no more than
+// MaxConstRowColumns column IDs, and replaces its columns argument with the
+// IDs sorted and without repeats.
+func (e *executor) checkConstRows(calls []*pql.Call) error {
+	for _, c := range calls {
+		if err := e.checkConstRows(c.Children); err != nil {
+			return err
+		} else if c.Name != "ConstRow" {
+			continue
+		}
+
+		if len(c.Children) > 0 {
+			return NewBadRequestError(errors.New("ConstRow() does not accept child calls"))
+		}
+		for k := range c.Args {
+			if k != "columns" {
+				return NewBadRequestError(errors.Errorf("ConstRow() does not accept the %s argument", k))
+			}
+		}
+
+		var columns []uint64
+		switch v := c.Args["columns"].(type) {
+		case nil:
+			return NewBadRequestError(errors.New("ConstRow() requires a columns argument"))
+		case []uint64:
+			columns = append(make([]uint64, 0, len(v)), v...)
+		case []interface{}:
+			columns = make([]uint64, 0, len(v))
+			for _, id := range v {
+				n, ok := id.(int64)
+				if !ok || n < 0 {
+					return NewBadRequestError(errors.Errorf("ConstRow() columns must be non-negative integers: %v", id))
+				}
+				columns = append(columns, uint64(n))
+			}
+		default:
+			return NewBadRequestError(errors.Errorf("ConstRow() columns must be a list: %v", v))
+		}
+
+		if e.MaxConstRowColumns > 0 && len(columns) > e.MaxConstRowColumns {
+			return NewBadRequestError(errors.Wrapf(ErrTooManyConstRowColumns, "%d columns, maximum %d", len(columns), e.MaxConstRowColumns))
+		}
+		c.Args["columns"] = uniquePositions(columns)
+	}
+	return nil
+}
+
+// constRowShards returns the shards of the columns listed by the ConstRow()
+// calls among calls, added to shards, since a listed column may fall in a
+// shard which holds no data in the index.
+func constRowShards(idx *Index, calls []*pql.Call, shards *roaring.Bitmap) *roaring.Bitmap {
+	for _, c := range calls {
+		if columns, ok := c.Args["columns"].([]uint64); ok && c.Name == "ConstRow" {
+			for _, id := range columns {
+				shards.Add(id / idx.ShardWidth())
+			}
+		}
+		constRowShards(idx, c.Children, shards)
+	}
+	return shards
+}
+
+// callFields adds the fields of idx named by calls and their children to m,
+// and returns m.
+func callFields(idx *Index, calls []*pql.Call, m map[string]*Field) map[string]*Field {
+	for _, c := range calls {
+		for k, v := range c.Args {
+			name := k
+			if s, ok := v.(string); ok && (k == "field" || k == "_field") {
+				name = s
+			}
+			if f := idx.Field(name); f != nil {
+				m[name] = f
+			}
+		}
+		callFields(idx, c.Children, m)
+	}
+	return m
+}
+
+// checkTimeRanges ensures the from and to bounds of each time ranged Row()
+// call fall on boundaries of the smallest unit of the field's time quantum,
+// since a view can only return all of the bits of its period. Unaligned
+// bounds are an error unless the call sets precise=true, in which case the
+// range is widened to the containing periods and the rounding is returned.
+func (e *executor) checkTimeRanges(idx *Index, calls []*pql.Call) ([]*TimeRounding, error) {
+	var roundings []*TimeRounding
+	for _, c := range calls {
+		a, err := e.checkTimeRanges(idx, c.Children)
+		if err != nil {
+			return nil, err
+		}
+		roundings = append(roundings, a...)
+
+		if (c.Name != "Row" && c.Name != "Range") || c.HasConditionArg() {
+			continue
+		}
+		fieldName, err := c.FieldArg()
+		if err != nil {
+			continue
+		}
+		f := idx.Field(fieldName)
+		if f == nil || f.TimeQuantum() == "" {
+			continue
+		}
+		q := f.TimeQuantum()
+		units := q.units()
+		if len(units) == 0 {
+			continue
+		}
+
+		precise, _, err := c.BoolArg("precise")
+		if err != nil {
+			return nil, errors.Wrap(err, "getting precise")
+		}
+
+		for _, arg := range []string{"from", "to"} {
+			v, ok := c.Args[arg]
+			if !ok {
+				continue
+			}
+			t, err := parseTime(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing %s time", arg)
+			}
+
+			rounded := roundTime(t, q, arg == "to")
+			if rounded.Equal(t) {
+				continue
+			} else if !precise {
+				return nil, errors.Errorf("%s(): %s=%v is within a %s, the finest granularity of field %s (time quantum %s); align it or set precise=true to round it",
+					c.Name, arg, v, units[len(units)-1].name, fieldName, q)
+			}
+			roundings = append(roundings, &TimeRounding{
+				Field:       fieldName,
+				Arg:         arg,
+				Time:        t,
+				Rounded:     rounded,
+				Granularity: units[len(units)-1].name,
+			})
+		}
+	}
+	return roundings, nil
+}
+
+// checkViews ensures the view argument of each Row() and TopN() call names
+// a view of its field. The view of a Row() call can't be combined with a
+// time range, which chooses its own views.
+func checkViews(idx *Index, calls []*pql.Call) error {
+	for _, c := range calls {
+		if err := checkViews(idx, c.Children); err != nil {
+			return err
+		}
+
+		v, ok := c.Args["view"]
+		if !ok || (c.Name != "Row" && c.Name != "TopN") {
+			continue
+		}
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return errors.Errorf("%s(): view must be a non-empty string", c.Name)
+		}
+
+		var fieldName string
+		if c.Name == "TopN" {
+			fieldName, _ = c.Args["_field"].(string)
+		} else {
+			_, hasFrom := c.Args["from"]
+			_, hasTo := c.Args["to"]
+			if hasFrom || hasTo {
+				return errors.New("Row(): view can't be combined with from or to")
+			}
+			fieldName, _ = c.FieldArg()
+		}
+		f := idx.Field(fieldName)
+		if f == nil {
+			continue // reported when the call is executed
+		} else if f.view(name) == nil {
+			return errors.Errorf("%s(): unknown view %q of field %s", c.Name, name, fieldName)
+		}
+	}
+	return nil
+}
+
+// translateLabels replaces arguments named with the index's column label or
+// a field's row label with the arguments they stand for, so Set(user=1,
+// site=2) is executed as Set(1, traffic=2) if the index labels its columns
+// "user" and the traffic field labels its rows "site".
+func translateLabels(idx *Index, calls []*pql.Call) error {
+	for _, c := range calls {
+		if err := translateCallLabels(idx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func translateCallLabels(idx *Index, c *pql.Call) error {
+	var columns, rows bool
+	switch c.Name {
+	case "Set", "Clear", "SetColumnAttrs":
+		columns, rows = true, c.Name != "SetColumnAttrs"
+	case "Row", "Range", "ClearRow":
+		rows = true
+	}
+
+	if columns || rows {
+		args := make([]string, 0, len(c.Args))
+		for arg := range c.Args {
+			args = append(args, arg)
+		}
+		sort.Strings(args)
+
+		for _, arg := range args {
+			if arg == "field" || pql.IsReservedArg(arg) || idx.Field(arg) != nil {
+				continue
+			}
+
+			// Replace the column label with the positional column.
+			if columns && arg == idx.ColumnLabel() {
+				if _, ok := c.Args["_"+columnLabel]; ok {
+					return errors.Errorf("%s() column specified twice: %s", c.Name, arg)
+				}
+				c.Args["_"+columnLabel] = c.Args[arg]
+				delete(c.Args, arg)
+				continue
+			}
+
+			// Replace a row label with the name of its field.
+			if !rows {
+				continue
+			}
+			fieldName, _ := c.Args["field"].(string)
+			f, err := idx.fieldByRowLabel(arg, fieldName)
+			if err != nil {
+				return errors.Wrapf(err, "%s()", c.Name)
+			} else if f == nil {
+				continue
+			} else if _, ok := c.Args[f.Name()]; ok {
+				return errors.Errorf("%s() field %s specified twice: %s", c.Name, f.Name(), arg)
+			}
+			c.Args[f.Name()] = c.Args[arg]
+			delete(c.Args, arg)
+			if f.Name() == fieldName {
+				delete(c.Args, "field")
+			}
+		}
+	}
+
+	for _, child := range c.Children {
+		if err := translateCallLabels(idx, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *executor) translateCalls(ctx context.Context, index string, idx *Index, calls []*pql.Call) error {
 	span, _ := tracing.StartSpanFromContext(ctx, "Executor.translateCalls")
 	defer span.Finish()
@@ -2434,6 +3729,16 @@ func (e *executor) translateCall(index string, idx *Index, c *pql.Call) error {
 		colKey = "_" + columnLabel
 		fieldName, _ = c.FieldArg()
 		rowKey = fieldName
+	case "CountRange":
+		fieldName, _ = countRangeField(c)
+		rowKey = fieldName
+	case "Contains":
+		if err := e.translateContainsColumns(index, idx, c); err != nil {
+			return err
+		}
+		colKey = "column"
+		fieldName, _ = containsField(c)
+		rowKey = fieldName
 	case "SetRowAttrs":
 		// Positional args in new PQL syntax require special handling here.
 		rowKey = "_" + rowLabel
@@ -2519,6 +3824,27 @@ func (e *executor) translateCall(index string, idx *Index, c *pql.Call) error {
 	return nil
 }
 
+// translateContainsColumns translates the keys of the list of columns of a
+// Contains() call to IDs.
+func (e *executor) translateContainsColumns(index string, idx *Index, c *pql.Call) error {
+	columns, ok := c.Args["columns"].([]interface{})
+	if !ok || !idx.Keys() {
+		return nil
+	}
+	keys := make([]string, len(columns))
+	for i, v := range columns {
+		if keys[i], ok = v.(string); !ok {
+			return errors.New("column value must be a string when index 'keys' option enabled")
+		}
+	}
+	ids, err := e.TranslateStore.TranslateColumnsToUint64(index, keys)
+	if err != nil {
+		return err
+	}
+	c.Args["columns"] = ids
+	return nil
+}
+
 func (e *executor) translateGroupByCall(index string, idx *Index, c *pql.Call) error {
 	if c.Name != "GroupBy" {
 		panic("translateGroupByCall called with '" + c.Name + "'")
@@ -2719,20 +4045,20 @@ type execOptions struct {
 	ExcludeRowAttrs bool
 	ExcludeColumns  bool
 	ColumnAttrs     bool
-}
 
-// hasOnlySetRowAttrs returns true if calls only contains SetRowAttrs() calls.
-func hasOnlySetRowAttrs(calls []*pql.Call) bool {
-	if len(calls) == 0 {
-		return false
-	}
+	// Record a profile of the query in the response.
+	Profile bool
+}
 
-	for _, call := range calls {
+// setRowAttrsRunLen returns the number of SetRowAttrs() calls at the start
+// of calls.
+func setRowAttrsRunLen(calls []*pql.Call) int {
+	for i, call := range calls {
 		if call.Name != "SetRowAttrs" {
-			return false
+			return i
 		}
 	}
-	return true
+	return len(calls)
 }
 
 func needsShards(calls []*pql.Call) bool {
@@ -2837,12 +4163,15 @@ type groupByIterator struct {
 	fields []FieldRow
 	done   bool
 
+	// frags holds the fragments of the rowIters, acquired until release.
+	frags []*fragment
+
 	// Optional filter row to intersect against first level of values.
 	filter *Row
 }
 
 // newGroupByIterator initializes a new groupByIterator.
-func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, index string, shard uint64, holder *Holder) (*groupByIterator, error) {
+func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, index string, shard uint64, holder *Holder) (_ *groupByIterator, err error) {
 	gbi := &groupByIterator{
 		rowIters: make([]*rowIterator, len(children)),
 		rows: make([]struct {
@@ -2852,6 +4181,11 @@ func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, inde
 		filter: filter,
 		fields: make([]FieldRow, len(children)),
 	}
+	defer func() {
+		if err != nil {
+			gbi.release()
+		}
+	}()
 
 	var fieldName string
 	var ok bool
@@ -2865,10 +4199,14 @@ func newGroupByIterator(rowIDs []RowIDs, children []*pql.Call, filter *Row, inde
 		}
 		gbi.fields[i].Field = fieldName
 		// Fetch fragment.
-		frag := holder.fragment(index, fieldName, viewStandard, shard)
-		if frag == nil { // this means this whole shard doesn't have all it needs to continue
+		frag, err := holder.acquireFragment(index, fieldName, viewStandard, shard)
+		if err != nil {
+			return nil, err
+		} else if frag == nil { // this means this whole shard doesn't have all it needs to continue
+			gbi.release()
 			return nil, nil
 		}
+		gbi.frags = append(gbi.frags, frag)
 		filters := []rowFilter{}
 		if len(rowIDs[i]) > 0 {
 			filters = append(filters, filterWithRows(rowIDs[i]))
@@ -2956,6 +4294,14 @@ func (gbi *groupByIterator) nextAtIdx(i int) {
 	}
 }
 
+// release releases the fragments acquired by newGroupByIterator.
+func (gbi *groupByIterator) release() {
+	for _, frag := range gbi.frags {
+		frag.release()
+	}
+	gbi.frags = nil
+}
+
 // Next returns a GroupCount representing the next group by record. When there
 // are no more records it will return an empty GroupCount and done==true.
 func (gbi *groupByIterator) Next() (ret GroupCount, done bool) {
@@ -1,9 +1,11 @@
 package pilosa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -222,3 +224,84 @@ func TestFieldRowMarshalJSON(t *testing.T) {
 		t.Fatalf("unexpected json: %s", b)
 	}
 }
+
+// Ensure Intersect and Union return the same columns however stale the cached
+// counts used to order their inputs are.
+func TestExecutor_IntersectUnionShard_StaleEstimates(t *testing.T) {
+	e := &executor{
+		Holder: NewHolder(),
+	}
+	e.Holder.Path, _ = ioutil.TempDir(*TempDir, "")
+	if err := e.Holder.Open(); err != nil {
+		t.Fatalf("opening holder: %v", err)
+	}
+	defer e.Holder.Close()
+
+	idx, err := e.Holder.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatalf("creating index: %v", err)
+	}
+	f, err := idx.CreateField("f")
+	if err != nil {
+		t.Fatalf("creating field: %v", err)
+	}
+	for rowID, columnIDs := range map[uint64][]uint64{
+		1: {1, 2, 3, 4, 5, 6},
+		2: {2, 3, 4},
+		3: {3, 4, 9},
+		4: {9},
+	} {
+		for _, columnID := range columnIDs {
+			if _, err := f.SetBit(rowID, columnID, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Give the largest row the smallest cached count and vice versa.
+	frag := e.Holder.fragment("i", "f", viewStandard, 0)
+	frag.cache.BulkAdd(1, 1)
+	frag.cache.BulkAdd(3, 100)
+	frag.cache.Recalculate()
+
+	if calls, err := e.orderByEstimatedCount("i", mustParseCalls(t, `Row(f=3) Union(Row(f=4)) Row(f=2) Row(f=1) Row(f=5)`), 0); err != nil {
+		t.Fatal(err)
+	} else if s := fmt.Sprint(calls); s != "[Row(f=5) Row(f=1) Row(f=2) Row(f=3) Union(Row(f=4))]" {
+		t.Fatalf("unexpected order: %s", s)
+	}
+
+	for query, columns := range map[string][]uint64{
+		`Intersect(Row(f=1), Row(f=2), Row(f=3))`:           {3, 4},
+		`Intersect(Row(f=3), Union(Row(f=1), Row(f=4)))`:    {3, 4, 9},
+		`Intersect(Row(f=1), Row(f=4), Row(f=2))`:           nil,
+		`Intersect(Row(f=5), Row(f=1))`:                     nil,
+		`Union(Row(f=5), Row(f=2), Row(f=5), Row(f=4))`:     {2, 3, 4, 9},
+		`Union(Row(f=5), Intersect(Row(f=4), Row(f=1)))`:    nil,
+		`Union(Intersect(Row(f=1), Row(f=2)), Row(f=3))`:    {2, 3, 4, 9},
+		`Intersect(Row(f=2), Union(Row(f=5), Row(f=3)))`:    {3, 4},
+		`Intersect(Union(Row(f=2), Row(f=4)), Row(f=3))`:    {3, 4, 9},
+		`Intersect(Row(f=3), Row(f=4), Row(f=3), Row(f=4))`: {9},
+	} {
+		row, err := e.executeBitmapCallShard(context.Background(), "i", mustParseCalls(t, query)[0], 0)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		} else if got := row.Columns(); !reflect.DeepEqual(got, columns) && (len(got) != 0 || len(columns) != 0) {
+			t.Fatalf("%s: unexpected columns: %v, expected %v", query, got, columns)
+		}
+	}
+
+	// Inputs of unknown fields fail even once the intersection is empty.
+	if _, err := e.executeBitmapCallShard(context.Background(), "i", mustParseCalls(t, `Intersect(Row(f=5), Row(g=1))`)[0], 0); err != ErrFieldNotFound {
+		t.Fatalf("expected %v, got %v", ErrFieldNotFound, err)
+	}
+}
+
+// mustParseCalls parses a query and returns its calls.
+func mustParseCalls(t *testing.T, s string) []*pql.Call {
+	t.Helper()
+	q, err := pql.ParseString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q.Calls
+}
@@ -15,6 +15,7 @@
 package pilosa_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
@@ -22,8 +23,11 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"reflect"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -31,6 +35,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/boltdb"
+	"github.com/pilosa/pilosa/pql"
+	"github.com/pilosa/pilosa/roaring"
 	"github.com/pilosa/pilosa/server"
 	"github.com/pilosa/pilosa/test"
 	"github.com/pkg/errors"
@@ -150,6 +157,29 @@ func TestExecutor_Execute_Difference(t *testing.T) {
 		}
 	})
 
+	t.Run("NestedCount", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		hldr := test.Holder{Holder: c[0].Server.Holder()}
+		hldr.SetBit("i", "general", 10, 1)
+		hldr.SetBit("i", "general", 10, ShardWidth+1)
+		hldr.SetBit("i", "general", 11, 2)
+		hldr.SetBit("i", "general", 11, ShardWidth+2)
+		hldr.SetBit("i", "general", 12, 2)
+		hldr.SetBit("i", "general", 12, ShardWidth+1)
+
+		res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `
+			Difference(Union(Row(general=10), Row(general=11)), Row(general=12))
+			Count(Difference(Union(Row(general=10), Row(general=11)), Row(general=12)))`})
+		if err != nil {
+			t.Fatal(err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1, ShardWidth + 2}) {
+			t.Fatalf("unexpected columns: %+v", columns)
+		} else if n := res.Results[1].(uint64); n != 2 {
+			t.Fatalf("unexpected count: %d", n)
+		}
+	})
+
 	t.Run("RowIDColumnKey", func(t *testing.T) {
 		writeQuery := `
 			Set("one", f=10)
@@ -395,6 +425,28 @@ func TestExecutor_Execute_Xor(t *testing.T) {
 		}
 	})
 
+	t.Run("NestedCount", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		hldr := test.Holder{Holder: c[0].Server.Holder()}
+		hldr.SetBit("i", "general", 10, 1)
+		hldr.SetBit("i", "general", 10, ShardWidth+1)
+		hldr.SetBit("i", "general", 11, 2)
+		hldr.SetBit("i", "general", 11, ShardWidth+1)
+		hldr.SetBit("i", "general", 12, ShardWidth+1)
+
+		res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `
+			Xor(Intersect(Row(general=10), Row(general=12)), Row(general=11))
+			Count(Xor(Intersect(Row(general=10), Row(general=12)), Row(general=11)))`})
+		if err != nil {
+			t.Fatal(err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{2}) {
+			t.Fatalf("unexpected columns: %+v", columns)
+		} else if n := res.Results[1].(uint64); n != 1 {
+			t.Fatalf("unexpected count: %d", n)
+		}
+	})
+
 	t.Run("RowIDColumnKey", func(t *testing.T) {
 		writeQuery := `
 			Set("one", f=10)
@@ -886,6 +938,42 @@ func TestExecutor_Execute_SetRowAttrs(t *testing.T) {
 	})
 }
 
+// Ensure consecutive SetRowAttrs() calls in a query are applied in order,
+// with their typed values, on every node.
+func TestExecutor_Execute_SetRowAttrs_Consecutive(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "g")
+
+	res := c.Query(t, "i", `
+		SetRowAttrs(f, 10, a="x", b=true, x=1.0, y=0.30000000000000004)
+		SetRowAttrs(g, 10, a=1)
+		SetRowAttrs(f, 10, a=null, c="", z=0.000000001)
+		Set(1, f=10)
+		SetRowAttrs(f, 10, b=false)
+		Row(f=10)
+	`)
+	if len(res.Results) != 6 {
+		t.Fatalf("unexpected results: %#v", res.Results)
+	}
+
+	exp := map[string]interface{}{"b": false, "c": "", "x": 1.0, "y": 0.30000000000000004, "z": 1e-9}
+	for i, m := range c {
+		res, err := m.API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=10)`})
+		if err != nil {
+			t.Fatal(err)
+		} else if attrs := res.Results[0].(*pilosa.Row).Attrs; !reflect.DeepEqual(attrs, exp) {
+			t.Fatalf("unexpected attrs on node %d: %#v", i, attrs)
+		}
+		if attrs, err := m.Server.Holder().Field("i", "g").RowAttrStore().Attrs(10); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(attrs, map[string]interface{}{"a": int64(1)}) {
+			t.Fatalf("unexpected attrs of g on node %d: %#v", i, attrs)
+		}
+	}
+}
+
 // Ensure a TopN() query can be executed.
 func TestExecutor_Execute_TopN(t *testing.T) {
 	t.Run("RowIDColumnID", func(t *testing.T) {
@@ -1055,6 +1143,150 @@ func TestExecutor_Execute_TopN(t *testing.T) {
 	})
 }
 
+// Ensure TopN with exact=true counts rows which aren't in any cache.
+func TestExecutor_Execute_TopN_Exact(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+
+	// Use a cache which only holds the top row of each shard.
+	if idx, err := hldr.CreateIndex("i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if _, err := idx.CreateField("f", pilosa.OptFieldTypeSet(pilosa.CacheTypeRanked, 1)); err != nil {
+		t.Fatal(err)
+	}
+	hldr.MustSetBits("i", "f", 0, 0, 1, 2)
+	hldr.MustSetBits("i", "f", 2, 3, 4)
+	hldr.MustSetBits("i", "f", 1, ShardWidth, ShardWidth+1, ShardWidth+2)
+	hldr.MustSetBits("i", "f", 2, ShardWidth+3, ShardWidth+4)
+
+	if err := c[0].RecalculateCaches(); err != nil {
+		t.Fatalf("recalculating caches: %v", err)
+	}
+
+	if result, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `TopN(f, n=1, exact=true)`}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(result.Results, []interface{}{[]pilosa.Pair{
+		{ID: 2, Count: 4},
+	}}) {
+		t.Fatalf("unexpected result: %s", spew.Sdump(result))
+	}
+
+	// Exact counts also apply to intersections.
+	if result, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `TopN(f, Row(f=2), n=2, exact=true)`}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(result.Results, []interface{}{[]pilosa.Pair{
+		{ID: 2, Count: 4},
+	}}) {
+		t.Fatalf("unexpected result: %s", spew.Sdump(result))
+	}
+}
+
+// cancelAttrStore is an attribute store which cancels a query when the
+// attributes of a row are first read.
+type cancelAttrStore struct {
+	pilosa.AttrStore
+	cancel *atomic.Value
+}
+
+func (s *cancelAttrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	if cancel, ok := s.cancel.Load().(context.CancelFunc); ok {
+		cancel()
+	}
+	return s.AttrStore.BlockAttrs(ids)
+}
+
+// Ensure a TopN() cancelled while its shards are read stops reading them, and
+// that every goroutine it started exits.
+func TestExecutor_Execute_TopN_Cancel(t *testing.T) {
+	var cancelValue atomic.Value
+	c := test.MustRunCluster(t, 1, []server.CommandOption{
+		server.OptCommandServerOptions(pilosa.OptServerAttrStoreFunc(func(path string) pilosa.AttrStore {
+			return &cancelAttrStore{AttrStore: boltdb.NewAttrStore(path), cancel: &cancelValue}
+		})),
+	})
+	defer c.Close()
+
+	// No row matches the filter, so every row of every shard is scanned and
+	// has its attributes read, unless the query is cancelled.
+	const shardN, rowN = 16, 1000
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	bits := make([][2]uint64, 0, shardN*rowN)
+	for shard := uint64(0); shard < shardN; shard++ {
+		for rowID := uint64(0); rowID < rowN; rowID++ {
+			bits = append(bits, [2]uint64{rowID, shard*ShardWidth + rowID})
+		}
+	}
+	c.ImportBits(t, "i", "f", bits)
+	if err := c[0].RecalculateCaches(); err != nil {
+		t.Fatalf("recalculating caches: %v", err)
+	}
+
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelValue.Store(cancel)
+	if _, err := c[0].API.Query(ctx, &pilosa.QueryRequest{Index: "i", Query: `TopN(f, n=5, attrName="category", attrValues=["a"])`}); errors.Cause(err) != pilosa.ErrQueryCancelled {
+		t.Fatalf("expected %v, got %v", pilosa.ErrQueryCancelled, err)
+	}
+
+	// The goroutines started for the shards of the query exit promptly.
+	deadline := time.Now().Add(5 * time.Second)
+	for n := runtime.NumGoroutine(); n > baseline; n = runtime.NumGoroutine() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at most %d goroutines, got %d", baseline, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Ensure TopN() results can be read a page at a time.
+func TestExecutor_Execute_TopN_Offset(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "small", pilosa.OptFieldTypeSet(pilosa.CacheTypeRanked, 5))
+
+	// Spread rows with tied counts across shards.
+	var bits [][2]uint64
+	for row := uint64(0); row < 20; row++ {
+		for i := uint64(0); i < row*7%11+1; i++ {
+			bits = append(bits, [2]uint64{row, (i%3)*ShardWidth + i})
+		}
+	}
+	c.ImportBits(t, "i", "f", bits)
+	c.ImportBits(t, "i", "small", bits)
+	for _, m := range c {
+		if err := m.RecalculateCaches(); err != nil {
+			t.Fatalf("recalculating caches: %v", err)
+		}
+	}
+
+	all := c.Query(t, "i", `TopN(f, n=20)`).Results[0].([]pilosa.Pair)
+	if len(all) != 20 {
+		t.Fatalf("unexpected results: %+v", all)
+	}
+	var pages []pilosa.Pair
+	for _, q := range []string{`TopN(f, n=6)`, `TopN(f, n=6, offset=6)`, `TopN(f, n=6, offset=12)`, `TopN(f, n=6, offset=18)`} {
+		pages = append(pages, c.Query(t, "i", q).Results[0].([]pilosa.Pair)...)
+	}
+	if !reflect.DeepEqual(pages, all) {
+		t.Fatalf("pages differ:\npages=%+v\nall=%+v", pages, all)
+	}
+	if pairs := c.Query(t, "i", `TopN(f, offset=15)`).Results[0].([]pilosa.Pair); !reflect.DeepEqual(pairs, all[15:]) {
+		t.Fatalf("unexpected results without n: %+v", pairs)
+	} else if pairs := c.Query(t, "i", `TopN(f, n=5, offset=20)`).Results[0].([]pilosa.Pair); len(pairs) != 0 {
+		t.Fatalf("unexpected results past the end: %+v", pairs)
+	}
+
+	// Pages beyond the cache can't be ranked, except by exact queries.
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `TopN(small, n=3, offset=3)`}); err == nil || !strings.Contains(err.Error(), "exceeds the cache size 5") {
+		t.Fatalf("expected cache size error, got %v", err)
+	} else if pairs := c.Query(t, "i", `TopN(small, n=3, offset=3, exact=true)`).Results[0].([]pilosa.Pair); !reflect.DeepEqual(pairs, all[3:6]) {
+		t.Fatalf("unexpected exact results: %+v", pairs)
+	}
+}
+
 func TestExecutor_Execute_TopN_fill(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -1494,6 +1726,51 @@ func TestExecutor_Execute_Sum(t *testing.T) {
 			}
 		})
 	})
+
+	// Values at the bounds of a field are stored with every bit of its bit
+	// depth clear, or set, and sum like any other.
+	t.Run("BitDepthLimits", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "x")
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "n", pilosa.OptFieldTypeInt(-8, 7))
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "u", pilosa.OptFieldTypeInt(0, 255))
+
+		c.Query(t, "i", fmt.Sprintf(`
+			Set(0, n=-8)
+			Set(1, n=7)
+			Set(2, n=7)
+			Set(2, n=-8)
+			Set(%[1]d, n=7)
+			Set(%[2]d, n=0)
+			Set(0, u=255)
+			Set(1, u=0)
+			Set(%[1]d, u=255)
+			Set(1, x=0)
+			Set(%[1]d, x=0)
+		`, ShardWidth, 2*ShardWidth+1))
+
+		for query, exp := range map[string]pilosa.ValCount{
+			`Sum(field=n)`:           {Val: -2, Count: 5},
+			`Sum(Row(x=0), field=n)`: {Val: 14, Count: 2},
+			`Sum(field=u)`:           {Val: 510, Count: 3},
+			`Sum(Row(x=0), field=u)`: {Val: 255, Count: 2},
+		} {
+			if res := c.Query(t, "i", query).Results[0]; !reflect.DeepEqual(res, exp) {
+				t.Fatalf("%s: unexpected result: %+v", query, res)
+			}
+		}
+
+		// Values past the bounds don't fit in the bit depth and are rejected.
+		for _, query := range []string{`Set(3, n=8)`, `Set(3, n=-9)`, `Set(3, u=256)`, `Set(3, u=-1)`} {
+			if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err == nil {
+				t.Fatalf("%s: expected error", query)
+			}
+		}
+		if res := c.Query(t, "i", `Sum(field=n)`).Results[0]; !reflect.DeepEqual(res, pilosa.ValCount{Val: -2, Count: 5}) {
+			t.Fatalf("unexpected sum after rejected values: %+v", res)
+		}
+	})
 }
 
 // Ensure a range query can be executed.
@@ -1722,6 +1999,31 @@ func TestExecutor_Execute_Range_Deprecated(t *testing.T) {
 		})
 	})
 
+	// A range across a month boundary reads the hour views of the partial
+	// days, not the month views, and hours without data are empty.
+	t.Run("MonthBoundary", func(t *testing.T) {
+		writeQuery := `
+		Set(1, f=1, 2000-01-15T00:00)
+		Set(2, f=1, 2000-01-31T21:00)
+		Set(3, f=1, 2000-01-31T22:00)
+		Set(4, f=1, 2000-02-01T00:00)
+		Set(5, f=1, 2000-02-01T02:00)
+		Set(6, f=1, 2000-02-01T03:00)
+		Set(7, f=1, 2000-02-15T00:00)`
+		readQueries := []string{
+			`Range(f=1, from=2000-01-31T22:00, to=2000-02-01T03:00)`,
+			`Range(f=1, from=2000-01-31T00:00, to=2000-02-02T00:00)`,
+		}
+		responses := runCallTest(t, writeQuery, readQueries,
+			nil, pilosa.OptFieldTypeTime(pilosa.TimeQuantum("YMDH")))
+
+		if columns := responses[0].Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{3, 4, 5}) {
+			t.Fatalf("unexpected hour range columns: %+v", columns)
+		} else if columns := responses[1].Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{2, 3, 4, 5, 6}) {
+			t.Fatalf("unexpected day range columns: %+v", columns)
+		}
+	})
+
 	t.Run("RowIDColumnKey", func(t *testing.T) {
 		writeQuery := `
 		Set("two", f=1, 1999-12-31T00:00)
@@ -2187,6 +2489,80 @@ func TestExecutor_Execute_Range_BSIGroup_Deprecated(t *testing.T) {
 	})
 }
 
+// Ensure Row() and TopN() can read a named view, including from remote
+// nodes which don't have it.
+func TestExecutor_Execute_Remote_RowView(t *testing.T) {
+	c := test.MustRunCluster(t, 2,
+		[]server.CommandOption{
+			server.OptCommandServerOptions(pilosa.OptServerNodeID("node0"), pilosa.OptServerClusterHasher(&test.ModHasher{}))},
+		[]server.CommandOption{
+			server.OptCommandServerOptions(pilosa.OptServerNodeID("node1"), pilosa.OptServerClusterHasher(&test.ModHasher{}))},
+	)
+	defer c.Close()
+
+	if _, err := c[0].API.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatalf("creating index: %v", err)
+	} else if _, err := c[0].API.CreateField(context.Background(), "i", "f", pilosa.OptFieldTypeTime("Y")); err != nil {
+		t.Fatalf("creating field: %v", err)
+	}
+
+	// Shard 0 is on node0 and shard 1 on node1.
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf(`
+		Set(1, f=10, 2017-03-01T00:00)
+		Set(2, f=10, 2018-03-01T00:00)
+		Set(%d, f=10, 2017-03-01T00:00)
+		Set(%d, f=11, 2017-03-01T00:00)`, ShardWidth+1, ShardWidth+2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Row", func(t *testing.T) {
+		if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=10, view="standard_2017")`}); err != nil {
+			t.Fatal(err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1, ShardWidth + 1}) {
+			t.Fatalf("unexpected columns: %+v", columns)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Count(Row(f=10, view="standard_2018"))`}); err != nil {
+			t.Fatal(err)
+		} else if res.Results[0] != uint64(1) {
+			t.Fatalf("unexpected n: %d", res.Results[0])
+		}
+	})
+
+	t.Run("TopN", func(t *testing.T) {
+		// Time fields keep no cache, so rows are counted exactly.
+		if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `TopN(f, view="standard_2017", n=5, exact=true)`}); err != nil {
+			t.Fatal(err)
+		} else if pairs := res.Results[0].([]pilosa.Pair); !reflect.DeepEqual(pairs, []pilosa.Pair{{ID: 10, Count: 2}, {ID: 11, Count: 1}}) {
+			t.Fatalf("unexpected pairs: %+v", pairs)
+		}
+	})
+
+	t.Run("UnknownView", func(t *testing.T) {
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=10, view="standard_2016")`}); err == nil || !strings.Contains(err.Error(), `unknown view "standard_2016"`) {
+			t.Fatalf("expected unknown view error, got: %v", err)
+		} else if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Count(TopN(f, view="standard_2016"))`}); err == nil || !strings.Contains(err.Error(), `unknown view "standard_2016"`) {
+			t.Fatalf("expected unknown view error, got: %v", err)
+		} else if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=10, view="standard_2017", from=2017-01-01T00:00)`}); err == nil || !strings.Contains(err.Error(), "view can't be combined") {
+			t.Fatalf("expected time range error, got: %v", err)
+		}
+	})
+
+	// A node without the view returns no columns for its shards.
+	if err := c[1].Server.Holder().Field("i", "f").DeleteView("standard_2017"); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("RemoteWithoutView", func(t *testing.T) {
+		if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=10, view="standard_2017")`}); err != nil {
+			t.Fatal(err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1}) {
+			t.Fatalf("unexpected columns: %+v", columns)
+		}
+	})
+}
+
 // Ensure a remote query can return a row.
 func TestExecutor_Execute_Remote_Row(t *testing.T) {
 	c := test.MustRunCluster(t, 2,
@@ -2382,6 +2758,305 @@ func TestExecutor_SetColumnAttrs_ExcludeField(t *testing.T) {
 
 }
 
+// Ensure Row(), Intersect() and Union() results can be filtered by column
+// attributes.
+func TestExecutor_Execute_ColumnAttrFilter(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+
+	index := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{})
+	if _, err := index.CreateField("f", pilosa.OptFieldTypeDefault()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Column 3 has its region removed by setting it to null, and column
+	// ShardWidth+1 has no attributes at all.
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `
+		Set(0, f=10) Set(1, f=10) Set(2, f=10) Set(3, f=10) Set(%d, f=10)
+		Set(2, f=11) Set(3, f=11) Set(%d, f=11)
+		SetColumnAttrs(0, region="west", age=30)
+		SetColumnAttrs(1, region="west", age=40)
+		SetColumnAttrs(2, region="east", age=30)
+		SetColumnAttrs(3, region="west", age=30)
+		SetColumnAttrs(3, region=null)
+	`, ShardWidth+1, ShardWidth+1)
+
+	// Matching columns in different batches of attribute reads.
+	for col := 0; col < 2500; col++ {
+		fmt.Fprintf(&buf, "Set(%d, f=12)\n", 2*ShardWidth+col)
+	}
+	fmt.Fprintf(&buf, `SetColumnAttrs(%d, region="north") SetColumnAttrs(%d, region="north")`, 2*ShardWidth+5, 2*ShardWidth+2400)
+
+	if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: buf.String()}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range []struct {
+		query   string
+		columns []uint64
+	}{
+		{query: `Row(f=10, filter=Attrs(region="west"))`, columns: []uint64{0, 1}},
+		{query: `Row(f=10, filter=Attrs(region="west", age=30))`, columns: []uint64{0}},
+		{query: `Row(f=10, filter=Attrs(age=30.0))`, columns: []uint64{0, 2, 3}},
+		{query: `Row(f=10, filter=Attrs(region=null))`, columns: []uint64{3, ShardWidth + 1}},
+		{query: `Row(f=10, filter=Attrs(region="south"))`, columns: []uint64{}},
+		{query: `Row(f=10, filter=Attrs(age=30), limit=2)`, columns: []uint64{0, 2}},
+		{query: `Intersect(Row(f=10), Row(f=11), filter=Attrs(age=30))`, columns: []uint64{2, 3}},
+		{query: `Union(Row(f=10), Row(f=11), filter=Attrs(region="east"))`, columns: []uint64{2}},
+		{query: `Options(Row(f=10, filter=Attrs(region="east")), shards=[0])`, columns: []uint64{2}},
+		{query: `Row(f=12, filter=Attrs(region="north"))`, columns: []uint64{2*ShardWidth + 5, 2*ShardWidth + 2400}},
+		{query: `Row(f=12, filter=Attrs(region="north"), limit=1)`, columns: []uint64{2*ShardWidth + 5}},
+	} {
+		res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: tt.query})
+		if err != nil {
+			t.Fatalf("test %d: %s: %v", i, tt.query, err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, tt.columns) {
+			t.Fatalf("test %d: %s: unexpected columns: %v", i, tt.query, columns)
+		}
+	}
+
+	// Only the attributes of the remaining columns are returned.
+	res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=10, filter=Attrs(age=40))`, ColumnAttrs: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if attrs := res.ColumnAttrSets; !reflect.DeepEqual(attrs, []*pilosa.ColumnAttrSet{{ID: 1, Attrs: map[string]interface{}{"region": "west", "age": int64(40)}}}) {
+		t.Fatalf("unexpected attrs: %s", spew.Sdump(attrs))
+	}
+
+	for _, query := range []string{
+		`Row(f=10, filter=Row(f=11))`,
+		`Row(f=10, filter=Attrs())`,
+		`Row(f=10, filter=Attrs(age=30), limit=-1)`,
+	} {
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err == nil || !strings.Contains(err.Error(), "Row(): ") {
+			t.Fatalf("%s: unexpected error: %v", query, err)
+		}
+	}
+}
+
+// Ensure the column attributes of only the calls which request them are
+// returned, read at once for all of them.
+func TestExecutor_Execute_ColumnAttrs_PerCall(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	store := mustCreateCountingAttrIndex(t, c[0], "i")
+	if _, err := c[0].API.CreateField(context.Background(), "i", "f", pilosa.OptFieldTypeDefault()); err != nil {
+		t.Fatal(err)
+	} else if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `
+		Set(1, f=1) Set(2, f=1) Set(2, f=2) Set(3, f=2) Set(4, f=3)
+		SetColumnAttrs(1, a=1) SetColumnAttrs(2, a=2) SetColumnAttrs(3, a=3) SetColumnAttrs(4, a=4)`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	query := `Row(f=1) Options(Row(f=2), columnAttrs=true) Options(Row(f=3), columnAttrs=false) Count(Row(f=1))`
+	for i, tt := range []struct {
+		all bool
+		ids []uint64
+	}{
+		{all: false, ids: []uint64{2, 3}},
+		{all: true, ids: []uint64{1, 2, 3}},
+	} {
+		store.reset()
+		res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query, ColumnAttrs: tt.all})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]uint64, len(res.ColumnAttrSets))
+		for j, set := range res.ColumnAttrSets {
+			ids[j] = set.ID
+		}
+		if !reflect.DeepEqual(ids, tt.ids) {
+			t.Fatalf("test %d: unexpected columns with attrs: %v", i, ids)
+		} else if store.reads != 1 || store.ids != len(tt.ids) {
+			t.Fatalf("test %d: unexpected reads: %d of %d ids", i, store.reads, store.ids)
+		}
+	}
+
+	// Without any call requesting them, none are read or returned.
+	store.reset()
+	if res, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=1) Options(Row(f=2), columnAttrs=false)`}); err != nil {
+		t.Fatal(err)
+	} else if res.ColumnAttrSets != nil || store.reads != 0 {
+		t.Fatalf("unexpected attrs: %v, reads: %d", res.ColumnAttrSets, store.reads)
+	} else if buf, err := json.Marshal(res); err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(string(buf), "columnAttrs") {
+		t.Fatalf("unexpected JSON: %s", buf)
+	}
+}
+
+// countingAttrStore counts the reads of column attributes by BlockAttrs.
+type countingAttrStore struct {
+	pilosa.AttrStore
+	mu         sync.Mutex
+	reads, ids int
+}
+
+func (s *countingAttrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	s.mu.Lock()
+	s.reads++
+	s.ids += len(ids)
+	s.mu.Unlock()
+	return s.AttrStore.BlockAttrs(ids)
+}
+
+func (s *countingAttrStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reads, s.ids = 0, 0
+}
+
+// mustCreateCountingAttrIndex creates an index on the node whose column
+// attribute store counts its reads, and returns the store.
+func mustCreateCountingAttrIndex(tb testing.TB, m *test.Command, index string) *countingAttrStore {
+	holder := m.Server.Holder()
+	newAttrStore := holder.NewAttrStore
+	holder.NewAttrStore = func(path string) pilosa.AttrStore {
+		return &countingAttrStore{AttrStore: newAttrStore(path)}
+	}
+	defer func() { holder.NewAttrStore = newAttrStore }()
+
+	idx, err := m.API.CreateIndex(context.Background(), index, pilosa.IndexOptions{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return idx.ColumnAttrStore().(*countingAttrStore)
+}
+
+// Ensure CountRange() counts a row in each period of a time range, across
+// shards on several nodes.
+func TestExecutor_Execute_CountRange(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f", pilosa.OptFieldTypeTime("YMD"))
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "k", pilosa.OptFieldTypeTime("YMD"), pilosa.OptFieldKeys())
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "plain")
+
+	c.Query(t, "i", fmt.Sprintf(`
+		Set(1, f=1, 2018-12-30T10:00)
+		Set(2, f=1, 2018-12-31T00:00)
+		Set(2, f=1, 2018-12-31T05:00)
+		Set(%[1]d, f=1, 2018-12-31T23:59)
+		Set(4, f=1, 2019-01-02T00:00)
+		Set(4, f=1, 2019-01-20T00:00)
+		Set(%[2]d, f=1, 2019-01-02T05:00)
+		Set(6, f=2, 2019-01-01T00:00)
+		Set(7, f=1, 2019-02-15T00:00)
+		Set(1, k="a", 2018-12-31T00:00)
+		Set(%[1]d, k="a", 2019-01-01T00:00)
+	`, ShardWidth+3, ShardWidth+5))
+
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, tt := range []struct {
+		query string
+		exp   []pilosa.TimeCount
+	}{
+		{
+			query: `CountRange(f=1, from=2018-12-30T00:00, to=2019-01-03T00:00, step="day")`,
+			exp:   []pilosa.TimeCount{{date(2018, 12, 30), 1}, {date(2018, 12, 31), 2}, {date(2019, 1, 1), 0}, {date(2019, 1, 2), 2}},
+		},
+		{
+			// The step defaults to the smallest unit of the quantum.
+			query: `CountRange(f=1, from=2018-12-31T12:00, to=2019-01-02T00:00)`,
+			exp:   []pilosa.TimeCount{{date(2018, 12, 31), 2}, {date(2019, 1, 1), 0}},
+		},
+		{
+			query: `CountRange(f=1, from=2018-12-01T00:00, to=2019-03-01T00:00, step="month")`,
+			exp:   []pilosa.TimeCount{{date(2018, 12, 1), 3}, {date(2019, 1, 1), 2}, {date(2019, 2, 1), 1}},
+		},
+		{
+			// Quarters aren't in the quantum, so they're counted from months.
+			query: `CountRange(f=1, from=2018-11-15T00:00, to=2019-04-01T00:00, step="quarter")`,
+			exp:   []pilosa.TimeCount{{date(2018, 10, 1), 3}, {date(2019, 1, 1), 3}},
+		},
+		{
+			query: `CountRange(f=1, from=2017-06-01T00:00, to=2020-01-01T00:00, step="year")`,
+			exp:   []pilosa.TimeCount{{date(2017, 1, 1), 0}, {date(2018, 1, 1), 3}, {date(2019, 1, 1), 3}},
+		},
+		{
+			query: `CountRange(k="a", from=2018-12-31T00:00, to=2019-01-02T00:00, step="day")`,
+			exp:   []pilosa.TimeCount{{date(2018, 12, 31), 1}, {date(2019, 1, 1), 1}},
+		},
+	} {
+		if res := c.Query(t, "i", tt.query).Results[0]; !reflect.DeepEqual(res, tt.exp) {
+			t.Fatalf("%s: unexpected result: %+v", tt.query, res)
+		}
+	}
+
+	for query, msg := range map[string]string{
+		`CountRange(f=1, from=2019-01-01T00:00, to=2019-01-02T00:00, step="hour")`: "finer than the time quantum YMD",
+		`CountRange(f=1, from=2019-01-01T00:00, to=2019-01-02T00:00, step="week")`: "invalid CountRange() step",
+		`CountRange(f=1, from=2019-01-02T00:00, to=2019-01-01T00:00)`:              "from must be before to",
+		`CountRange(f=1, to=2019-01-01T00:00)`:                                     "from required",
+		`CountRange(plain=1, from=2019-01-01T00:00, to=2019-01-02T00:00)`:          "has no time quantum",
+		`CountRange(f=1, from=1900-01-01T00:00, to=2019-01-01T00:00)`:              "more than 10000 periods of day",
+	} {
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("%s: expected error containing %q, got %v", query, msg, err)
+		}
+	}
+}
+
+// Ensure Contains() checks single bits, and lists of them, across shards on
+// several nodes.
+func TestExecutor_Execute_Contains(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "b", pilosa.OptFieldTypeBool())
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "n", pilosa.OptFieldTypeInt(0, 100))
+	c.CreateField(t, "k", pilosa.IndexOptions{Keys: true}, "f", pilosa.OptFieldKeys())
+
+	c.Query(t, "i", fmt.Sprintf(`
+		Set(1, f=10)
+		Set(%[1]d, f=10)
+		Set(%[2]d, f=10)
+		Set(2, f=11)
+		Set(3, b=true)
+	`, ShardWidth+1, 2*ShardWidth+1))
+	c.Query(t, "k", `Set("alice", f="red") Set("bob", f="blue")`)
+
+	for _, tt := range []struct {
+		index string
+		query string
+		exp   interface{}
+	}{
+		{"i", `Contains(f=10, column=1)`, true},
+		{"i", `Contains(f=10, column=2)`, false},
+		{"i", fmt.Sprintf(`Contains(f=10, column=%d)`, ShardWidth+1), true},
+		{"i", `Contains(f=12, column=1)`, false},
+		{"i", `Contains(b=true, column=3)`, true},
+		{"i", `Contains(b=false, column=3)`, false},
+		// Shards which were never written answer false.
+		{"i", fmt.Sprintf(`Contains(f=10, column=%d)`, 10*ShardWidth+1), false},
+		{"i", fmt.Sprintf(`Contains(f=10, columns=[%d, 2, 1, %d, %d, 1])`, 2*ShardWidth+1, ShardWidth+2, 10*ShardWidth), []bool{true, false, true, false, false, true}},
+		{"k", `Contains(f="red", column="alice")`, true},
+		{"k", `Contains(f="red", columns=["bob", "alice", "eve"])`, []bool{false, true, false}},
+	} {
+		if res := c.Query(t, tt.index, tt.query).Results[0]; !reflect.DeepEqual(res, tt.exp) {
+			t.Fatalf("%s: unexpected result: %#v", tt.query, res)
+		}
+	}
+
+	for query, msg := range map[string]string{
+		`Contains(f=10)`:                        "requires one of column or columns",
+		`Contains(f=10, column=1, columns=[2])`: "requires one of column or columns",
+		`Contains(column=1)`:                    "argument required: field",
+		`Contains(f=10, columns=1)`:             "columns must be a list",
+		`Contains(f=10, column="a")`:            "value not allowed unless index 'keys' option enabled",
+		`Contains(f=10, columns=[1, "a"])`:      "value not allowed unless index 'keys' option enabled",
+		`Contains(n=10, column=1)`:              "not supported on int fields",
+		`Contains(missing=10, column=1)`:        "field not found",
+	} {
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("%s: expected error containing %q, got %v", query, msg, err)
+		}
+	}
+}
+
 func TestExecutor_Time_Clear_Quantums(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -2391,16 +3066,19 @@ func TestExecutor_Time_Clear_Quantums(t *testing.T) {
 		quantum  pilosa.TimeQuantum
 		expected []uint64
 	}{
-		{quantum: "Y", expected: []uint64{3, 4, 5, 6}},
-		{quantum: "M", expected: []uint64{3, 4, 5, 6}},
-		{quantum: "D", expected: []uint64{3, 4, 5, 6}},
+		{quantum: "Y", expected: []uint64{3, 4, 5, 6, 7}},
+		{quantum: "M", expected: []uint64{3, 4, 5, 6, 7}},
+		{quantum: "D", expected: []uint64{3, 4, 5, 6, 7}},
 		{quantum: "H", expected: []uint64{3, 4, 5, 6, 7}},
-		{quantum: "YM", expected: []uint64{3, 4, 5, 6}},
-		{quantum: "YMD", expected: []uint64{3, 4, 5, 6}},
+		{quantum: "YM", expected: []uint64{3, 4, 5, 6, 7}},
+		{quantum: "YMD", expected: []uint64{3, 4, 5, 6, 7}},
 		{quantum: "YMDH", expected: []uint64{3, 4, 5, 6, 7}},
-		{quantum: "MD", expected: []uint64{3, 4, 5, 6}},
+		{quantum: "MD", expected: []uint64{3, 4, 5, 6, 7}},
 		{quantum: "MDH", expected: []uint64{3, 4, 5, 6, 7}},
 		{quantum: "DH", expected: []uint64{3, 4, 5, 6, 7}},
+		{quantum: "YQ", expected: []uint64{3, 4, 5, 6, 7}},
+		{quantum: "QD", expected: []uint64{3, 4, 5, 6, 7}},
+		{quantum: "YQMDHT", expected: []uint64{3, 4, 5, 6, 7}},
 	}
 	populateBatch := `
 				  Set(2, f=1, 1999-12-31T00:00)
@@ -2414,7 +3092,8 @@ func TestExecutor_Time_Clear_Quantums(t *testing.T) {
 				  Set(2, f=10, 2001-01-01T00:00)
 			`
 	clearColumn := `Clear( 2, f=1)`
-	rangeCheckQuery := `Row(f=1, from=1999-12-31T00:00, to=2002-01-01T03:00)`
+	// The bounds are rounded out to the periods of the smallest unit.
+	rangeCheckQuery := `Row(f=1, from=1999-12-31T00:00, to=2002-01-01T03:00, precise=true)`
 
 	for i, tt := range rangeTests {
 		t.Run(fmt.Sprintf("#%d Quantum %s", i+1, tt.quantum), func(t *testing.T) {
@@ -2443,6 +3122,83 @@ func TestExecutor_Time_Clear_Quantums(t *testing.T) {
 
 }
 
+func TestExecutor_Execute_RowTimeBoundaries(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+
+	const format = "2006-01-02T15:04:05"
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		quantum     pilosa.TimeQuantum
+		granularity string
+		next        func(time.Time) time.Time
+	}{
+		{"Y", "year", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+		{"YQ", "quarter", func(t time.Time) time.Time { return t.AddDate(0, 3, 0) }},
+		{"YM", "month", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+		{"YMD", "day", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+		{"YMDH", "hour", func(t time.Time) time.Time { return t.Add(time.Hour) }},
+		{"YMDHT", "minute", func(t time.Time) time.Time { return t.Add(time.Minute) }},
+	} {
+		t.Run(tt.granularity, func(t *testing.T) {
+			indexName := "b" + strings.ToLower(string(tt.quantum))
+			index := hldr.MustCreateIndexIfNotExists(indexName, pilosa.IndexOptions{})
+			if _, err := index.CreateFieldIfNotExists("f", pilosa.OptFieldTypeTime(tt.quantum)); err != nil {
+				t.Fatal(err)
+			}
+
+			// Set a column at the start of each of three periods.
+			t1, t2 := tt.next(start), tt.next(tt.next(start))
+			if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: indexName, Query: fmt.Sprintf(
+				"Set(1, f=1, %s) Set(2, f=1, %s) Set(3, f=1, %s)",
+				start.Format(pilosa.TimeFormat), t1.Format(pilosa.TimeFormat), t2.Format(pilosa.TimeFormat),
+			)}); err != nil {
+				t.Fatal(err)
+			}
+
+			query := func(args string) (pilosa.QueryResponse, error) {
+				return c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: indexName, Query: "Row(f=1, " + args + ")"})
+			}
+
+			// Bounds on period boundaries are exact.
+			if res, err := query(fmt.Sprintf(`from="%s", to="%s"`, start.Format(format), t1.Format(format))); err != nil {
+				t.Fatal(err)
+			} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1}) {
+				t.Fatalf("unexpected columns: %v", columns)
+			} else if res.TimeRoundings != nil {
+				t.Fatalf("unexpected roundings: %v", res.TimeRoundings)
+			}
+
+			// Bounds within a period are rejected, down to the second.
+			from, to := start.Add(30*time.Second), t1.Add(30*time.Second)
+			for _, args := range []string{
+				fmt.Sprintf(`from="%s", to="%s"`, from.Format(format), t1.Format(format)),
+				fmt.Sprintf(`from="%s", to="%s"`, start.Format(format), to.Format(format)),
+				fmt.Sprintf(`from=%d`, from.Unix()),
+			} {
+				if _, err := query(args); err == nil || !strings.Contains(err.Error(), "is within a "+tt.granularity+", the finest granularity of field f") {
+					t.Fatalf("%s: unexpected error: %v", args, err)
+				}
+			}
+
+			// With precise=true the range is widened to the periods containing
+			// its bounds, and the rounding is reported.
+			res, err := query(fmt.Sprintf(`from="%s", to="%s", precise=true`, from.Format(format), to.Format(format)))
+			if err != nil {
+				t.Fatal(err)
+			} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1, 2}) {
+				t.Fatalf("unexpected columns: %v", columns)
+			} else if !reflect.DeepEqual(res.TimeRoundings, []*pilosa.TimeRounding{
+				{Field: "f", Arg: "from", Time: from, Rounded: start, Granularity: tt.granularity},
+				{Field: "f", Arg: "to", Time: to, Rounded: t2, Granularity: tt.granularity},
+			}) {
+				t.Fatalf("unexpected roundings: %+v %+v", res.TimeRoundings[0], res.TimeRoundings[1])
+			}
+		})
+	}
+}
+
 func TestExecutor_ExecuteOptions(t *testing.T) {
 	t.Run("excludeRowAttrs", func(t *testing.T) {
 		writeQuery := `
@@ -2773,10 +3529,10 @@ func TestExecutor_Execute_ClearRow(t *testing.T) {
 			Set(2, f=1, 2002-02-01T00:00)
 			Set(2, f=10, 2001-01-01T00:00)`
 		readQueries := []string{
-			`Row(f=1, from=1999-12-31T00:00, to=2003-01-01T03:00)`,
+			`Row(f=1, from=1999-12-31T00:00, to=2003-01-01T00:00)`,
 			`ClearRow(f=1)`,
-			`Row(f=1, from=1999-12-31T00:00, to=2003-01-01T03:00)`,
-			`Row(f=10, from=1999-12-31T00:00, to=2003-01-01T03:00)`,
+			`Row(f=1, from=1999-12-31T00:00, to=2003-01-01T00:00)`,
+			`Row(f=10, from=1999-12-31T00:00, to=2003-01-01T00:00)`,
 		}
 		responses := runCallTest(t, writeQuery, readQueries,
 			&pilosa.IndexOptions{TrackExistence: true},
@@ -3079,7 +3835,7 @@ func benchmarkExistence(nn bool, b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if err := c[0].API.Import(context.Background(), req); err != nil {
+		if _, err := c[0].API.Import(context.Background(), req); err != nil {
 			b.Fatal(err)
 		}
 	}
@@ -3088,6 +3844,114 @@ func benchmarkExistence(nn bool, b *testing.B) {
 func BenchmarkExecutor_Existence_True(b *testing.B)  { benchmarkExistence(true, b) }
 func BenchmarkExecutor_Existence_False(b *testing.B) { benchmarkExistence(false, b) }
 
+// Benchmark reading the column attributes of 10 rows sharing 90% of their
+// columns, in one query and in a query per row, reporting the number of
+// column attributes read.
+func BenchmarkExecutor_ColumnAttrs(b *testing.B) {
+	c := test.MustRunCluster(b, 1)
+	defer c.Close()
+	store := mustCreateCountingAttrIndex(b, c[0], "i")
+	if _, err := c[0].API.CreateField(context.Background(), "i", "f", pilosa.OptFieldTypeDefault()); err != nil {
+		b.Fatal(err)
+	}
+
+	// Each row has columns [0, 900) and 100 of its own.
+	const rowN, shared, own = 10, 900, 100
+	for col := 0; col < shared+rowN*own; col += 50 {
+		var buf bytes.Buffer
+		for j := col; j < col+50; j++ {
+			fmt.Fprintf(&buf, "SetColumnAttrs(%d, x=%d)\n", j, j)
+			for row := 0; row < rowN; row++ {
+				if j < shared || (j-shared)/own == row {
+					fmt.Fprintf(&buf, "Set(%d, f=%d)\n", j, row)
+				}
+			}
+		}
+		if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: buf.String()}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	var all string
+	queries := make([]string, rowN)
+	for row := range queries {
+		queries[row] = fmt.Sprintf("Row(f=%d)", row)
+		all += queries[row]
+	}
+	for _, bm := range []struct {
+		name    string
+		queries []string
+	}{
+		{name: "query", queries: []string{all}},
+		{name: "call", queries: queries},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			store.reset()
+			for i := 0; i < b.N; i++ {
+				for _, query := range bm.queries {
+					if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query, ColumnAttrs: true}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(store.ids)/float64(b.N), "attrs/op")
+		})
+	}
+}
+
+// Benchmark intersecting a 10 bit row with the union of two rows, which sets
+// every column of 10 shards, and the union of two rows of which one is empty
+// in most shards.
+func BenchmarkExecutor_IntersectSmallLarge(b *testing.B) {
+	c := test.MustRunCluster(b, 1)
+	defer c.Close()
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+	hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{})
+	if _, err := c[0].API.CreateField(context.Background(), "i", "f", pilosa.OptFieldTypeDefault()); err != nil {
+		b.Fatal(err)
+	}
+
+	// Row 1 has 10 bits in the first shard, and rows 2 and 3 the even and
+	// odd columns of each shard.
+	const shardN = 10
+	for shard := uint64(0); shard < shardN; shard++ {
+		bm := roaring.NewBitmap()
+		if shard == 0 {
+			for i := uint64(0); i < 10; i++ {
+				bm.DirectAdd(ShardWidth + i*1000)
+			}
+		}
+		for i := uint64(0); i < ShardWidth; i++ {
+			bm.DirectAdd((2+i%2)*ShardWidth + i)
+		}
+		var buf bytes.Buffer
+		if _, err := bm.WriteTo(&buf); err != nil {
+			b.Fatal(err)
+		} else if err := c[0].API.ImportRoaring(context.Background(), "i", "f", shard, false, &pilosa.ImportRoaringRequest{Views: map[string][]byte{"": buf.Bytes()}}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if resp, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Count(Intersect(Union(Row(f=2), Row(f=3)), Row(f=1))) Count(Union(Row(f=2), Row(f=3)))`}); err != nil {
+		b.Fatal(err)
+	} else if !reflect.DeepEqual(resp.Results, []interface{}{uint64(10), uint64(shardN * ShardWidth)}) {
+		b.Fatalf("unexpected counts: %v", resp.Results)
+	}
+
+	for _, query := range []string{
+		`Count(Intersect(Union(Row(f=2), Row(f=3)), Row(f=1)))`,
+		`Count(Intersect(Row(f=1), Union(Row(f=2), Row(f=3))))`,
+		`Count(Union(Row(f=1), Row(f=2)))`,
+	} {
+		b.Run(query, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func TestExecutor_Execute_Rows(t *testing.T) {
 	c := test.MustRunCluster(t, 3)
 	defer c.Close()
@@ -3879,3 +4743,340 @@ func TestExecutor_Execute_Shift(t *testing.T) {
 		}
 	})
 }
+
+// Ensure ConstRow() returns the columns it lists, and can be combined with
+// the rows of fields.
+func TestExecutor_Execute_ConstRow(t *testing.T) {
+	t.Run("Shards", func(t *testing.T) {
+		c := test.MustRunCluster(t, 3)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+		c.ImportBits(t, "i", "f", [][2]uint64{
+			{1, 1},
+			{1, ShardWidth + 2},
+			{1, 3*ShardWidth + 3},
+		})
+
+		// Columns in shards 5 and 9 hold no data in the index.
+		cols := fmt.Sprintf("[%d, 1, %d, %d, 1, %d]", 9*ShardWidth+9, ShardWidth+2, 5*ShardWidth, ShardWidth+2)
+		exp := []uint64{1, ShardWidth + 2, 5 * ShardWidth, 9*ShardWidth + 9}
+		res := c.Query(t, "i", `ConstRow(columns=`+cols+`)`)
+		if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, exp) {
+			t.Fatalf("unexpected columns: %v", columns)
+		}
+
+		for q, exp := range map[string][]uint64{
+			`Intersect(Row(f=1), ConstRow(columns=` + cols + `))`:  {1, ShardWidth + 2},
+			`Union(Row(f=1), ConstRow(columns=` + cols + `))`:      {1, ShardWidth + 2, 3*ShardWidth + 3, 5 * ShardWidth, 9*ShardWidth + 9},
+			`Difference(Row(f=1), ConstRow(columns=` + cols + `))`: {3*ShardWidth + 3},
+			`Difference(ConstRow(columns=` + cols + `), Row(f=1))`: {5 * ShardWidth, 9*ShardWidth + 9},
+		} {
+			if columns := c.Query(t, "i", q).Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, exp) {
+				t.Fatalf("%s: unexpected columns: %v, expected %v", q, columns, exp)
+			}
+		}
+		if n := c.Query(t, "i", `Count(ConstRow(columns=`+cols+`))`).Results[0]; n != uint64(4) {
+			t.Fatalf("unexpected count: %v", n)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+		c.ImportBits(t, "i", "f", [][2]uint64{{1, 1}})
+
+		if columns := c.Query(t, "i", `ConstRow(columns=[])`).Results[0].(*pilosa.Row).Columns(); len(columns) != 0 {
+			t.Fatalf("unexpected columns: %v", columns)
+		} else if columns := c.Query(t, "i", `Intersect(Row(f=1), ConstRow(columns=[]))`).Results[0].(*pilosa.Row).Columns(); len(columns) != 0 {
+			t.Fatalf("unexpected columns: %v", columns)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1, []server.CommandOption{
+			server.OptCommandServerOptions(pilosa.OptServerMaxConstRowColumns(3)),
+		})
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+		for q, msg := range map[string]string{
+			`ConstRow(columns=[1, 2, 3, 4])`:   pilosa.ErrTooManyConstRowColumns.Error(),
+			`ConstRow()`:                       "requires a columns argument",
+			`ConstRow(columns=[1, "a"])`:       "must be non-negative integers",
+			`ConstRow(columns=[1], field=f)`:   "does not accept the field argument",
+			`ConstRow(Row(f=1), columns=[1])`:  "does not accept child calls",
+			`Count(ConstRow(columns=[1, -2]))`: "must be non-negative integers",
+		} {
+			if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: q}); err == nil || !strings.Contains(err.Error(), msg) {
+				t.Fatalf("%s: unexpected error: %v", q, err)
+			}
+		}
+
+		// Repeated columns are removed after the list is checked against the
+		// limit.
+		if columns := c.Query(t, "i", `ConstRow(columns=[2, 2, 1])`).Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{1, 2}) {
+			t.Fatalf("unexpected columns: %v", columns)
+		}
+	})
+}
+
+// Ensure queries can name columns and rows by their labels.
+func TestExecutor_Execute_Labels(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	hldr := test.Holder{Holder: c[0].Server.Holder()}
+
+	index := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{ColumnLabel: "user"})
+	if _, err := index.CreateField("traffic", pilosa.OptFieldTypeDefault(), pilosa.OptFieldRowLabel("site")); err != nil {
+		t.Fatal(err)
+	}
+
+	query := func(q string) (pilosa.QueryResponse, error) {
+		return c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: q})
+	}
+	for _, q := range []string{`Set(user=10, site=3)`, `Set(11, traffic=3)`, `SetColumnAttrs(user=10, x=1)`} {
+		if _, err := query(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	for _, q := range []string{`Row(site=3)`, `Row(traffic=3)`, `Row(site=3, field="traffic")`} {
+		if res, err := query(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{10, 11}) {
+			t.Fatalf("%s: unexpected columns: %+v", q, columns)
+		}
+	}
+	if attrs, err := index.ColumnAttrStore().Attrs(10); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(attrs, map[string]interface{}{"x": int64(1)}) {
+		t.Fatalf("unexpected attrs: %#v", attrs)
+	}
+
+	// A label shared by fields needs the field to be named.
+	if _, err := index.CreateField("visits", pilosa.OptFieldTypeDefault(), pilosa.OptFieldRowLabel("site")); err != nil {
+		t.Fatal(err)
+	} else if _, err := query(`Set(user=12, site=3, field="visits")`); err != nil {
+		t.Fatal(err)
+	} else if _, err := query(`Row(site=3)`); err == nil || !strings.Contains(err.Error(), "used by fields traffic and visits") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if res, err := query(`Row(site=3, field="visits")`); err != nil {
+		t.Fatal(err)
+	} else if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, []uint64{12}) {
+		t.Fatalf("unexpected columns: %+v", columns)
+	}
+
+	if _, err := query(`Set(1, user=10, traffic=3)`); err == nil || !strings.Contains(err.Error(), "column specified twice") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure the columns of a row can be streamed shard by shard.
+func TestExecutor_ExecuteStream(t *testing.T) {
+	stream := func(c test.Cluster, q string) ([]uint64, uint64, error) {
+		var columns []uint64
+		n, err := c[0].API.QueryStream(context.Background(), &pilosa.QueryRequest{Index: "i", Query: q}, func(cols []uint64) error {
+			columns = append(columns, cols...)
+			return nil
+		})
+		return columns, n, err
+	}
+
+	t.Run("Cluster", func(t *testing.T) {
+		c := test.MustRunCluster(t, 3)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+		var rowcols [][2]uint64
+		for shard := uint64(0); shard < 12; shard++ {
+			for _, col := range []uint64{shard * ShardWidth, shard*ShardWidth + 65536, (shard+1)*ShardWidth - 1} {
+				rowcols = append(rowcols, [2]uint64{1, col}, [2]uint64{2, col + shard%2})
+			}
+		}
+		c.ImportBits(t, "i", "f", rowcols)
+
+		for _, q := range []string{`Row(f=1)`, `Union(Row(f=1), Row(f=2))`, `Difference(Row(f=2), Row(f=1))`, `Shift(Row(f=1), n=1)`} {
+			exp := c.Query(t, "i", q).Results[0].(*pilosa.Row).Columns()
+			if columns, n, err := stream(c, q); err != nil {
+				t.Fatalf("%s: %v", q, err)
+			} else if !reflect.DeepEqual(columns, exp) {
+				t.Fatalf("%s: unexpected columns: %v, expected %v", q, columns, exp)
+			} else if n != uint64(len(exp)) {
+				t.Fatalf("%s: unexpected count: %d", q, n)
+			}
+		}
+	})
+
+	t.Run("NotStreamable", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+		for _, q := range []string{`Count(Row(f=1))`, `Row(f=1) Row(f=2)`, `TopN(f)`} {
+			if _, _, err := stream(c, q); err == nil || !strings.Contains(err.Error(), pilosa.ErrQueryNotStreamable.Error()) {
+				t.Fatalf("%s: unexpected error: %v", q, err)
+			}
+		}
+		if _, _, err := stream(c, `Row(f=1) `); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// A row with millions of columns is written out without holding the
+	// whole row, and its columns, in memory.
+	t.Run("Large", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+
+		const shardN = 10
+		bm := roaring.NewBitmap()
+		for i := uint64(0); i < ShardWidth; i++ {
+			bm.DirectAdd(i)
+		}
+		var buf bytes.Buffer
+		if _, err := bm.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		for shard := uint64(0); shard < shardN; shard++ {
+			if err := c[0].API.ImportRoaring(context.Background(), "i", "f", shard, false, &pilosa.ImportRoaringRequest{Views: map[string][]byte{"": buf.Bytes()}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		var next uint64
+		n, err := c[0].API.QueryStream(context.Background(), &pilosa.QueryRequest{Index: "i", Query: `Row(f=0)`}, func(cols []uint64) error {
+			for _, col := range cols {
+				if col != next {
+					return fmt.Errorf("unexpected column %d, expected %d", col, next)
+				}
+				next++
+			}
+			return nil
+		})
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			t.Fatal(err)
+		} else if n != shardN*ShardWidth || next != n {
+			t.Fatalf("unexpected count: %d (%d)", n, next)
+		}
+
+		// The columns alone would take 8 bytes each.
+		if alloc := after.TotalAlloc - before.TotalAlloc; alloc > n*8/4 {
+			t.Fatalf("allocated %d bytes streaming %d columns", alloc, n)
+		}
+	})
+}
+
+// Ensure a profiled query returns a profile mirroring its calls, with the
+// shards of each node and the nodes the calls were sent to.
+func TestExecutor_Execute_Profile(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "g")
+
+	var fbits, gbits [][2]uint64
+	for shard := uint64(0); shard < 8; shard++ {
+		fbits = append(fbits, [2]uint64{1, shard*ShardWidth + 1}, [2]uint64{2, shard*ShardWidth + 2})
+		gbits = append(gbits, [2]uint64{1, shard*ShardWidth + 1}, [2]uint64{2, shard*ShardWidth + 1})
+	}
+	c.ImportBits(t, "i", "f", fbits)
+	c.ImportBits(t, "i", "g", gbits)
+
+	// Find the shards of each node.
+	shards := make(map[string][]uint64)
+	for shard := uint64(0); shard < 8; shard++ {
+		nodes, err := c[0].API.ShardNodes(context.Background(), "i", shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shards[nodes[0].ID] = append(shards[nodes[0].ID], shard)
+	}
+	local, remote := c[0].API.Node().ID, c[1].API.Node().ID
+	if len(shards[local]) == 0 || len(shards[remote]) == 0 {
+		t.Skipf("shards aren't spread across nodes: %v", shards)
+	}
+
+	query := `TopN(f, Intersect(Row(g=1), Row(g=2)), n=2) Count(Intersect(Row(f=1), Union(Row(g=1), Row(g=2))))`
+	resp, err := c[0].Client().Query(context.Background(), "i", &pilosa.QueryRequest{Index: "i", Query: query, Profile: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if resp.Profile == nil {
+		t.Fatal("expected profile")
+	} else if resp.Results[1] != uint64(8) {
+		t.Fatalf("unexpected count: %v", resp.Results[1])
+	}
+	q, err := pql.ParseString(query)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(resp.Profile.Calls) != len(q.Calls) {
+		t.Fatalf("unexpected calls: %d", len(resp.Profile.Calls))
+	}
+
+	for i, call := range q.Calls {
+		cp := resp.Profile.Calls[i]
+		checkCallProfile(t, cp, call, shards[local])
+		if cp.Duration <= 0 {
+			t.Fatalf("%s: unexpected duration: %v", call.Name, cp.Duration)
+		}
+
+		// TopN asks the other node twice: for its top rows, then for their
+		// full counts.
+		n := 1
+		if call.Name == "TopN" {
+			n = 2
+		}
+		if len(cp.Nodes) != n {
+			t.Fatalf("%s: unexpected nodes: %d", call.Name, len(cp.Nodes))
+		}
+		for _, np := range cp.Nodes {
+			if np.ID != remote || !reflect.DeepEqual(np.Shards, shards[remote]) || np.Duration <= 0 || np.Err != "" {
+				t.Fatalf("%s: unexpected node: %+v", call.Name, np)
+			}
+			checkCallProfile(t, np.Call, call, shards[remote])
+		}
+	}
+
+	// Exact TopN calls are flagged on every node.
+	if resp.Profile.Calls[0].Exact {
+		t.Fatal("unexpected exact TopN")
+	}
+	resp, err = c[0].Client().Query(context.Background(), "i", &pilosa.QueryRequest{Index: "i", Query: `TopN(f, n=2, exact=true)`, Profile: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if cp := resp.Profile.Calls[0]; !cp.Exact || len(cp.Nodes) != 1 || !cp.Nodes[0].Call.Exact {
+		t.Fatalf("expected exact TopN: %+v", cp)
+	}
+
+	// Queries aren't profiled unless asked.
+	if resp, err := c[0].Client().Query(context.Background(), "i", &pilosa.QueryRequest{Index: "i", Query: query}); err != nil {
+		t.Fatal(err)
+	} else if resp.Profile != nil {
+		t.Fatalf("unexpected profile: %+v", resp.Profile)
+	}
+}
+
+// checkCallProfile checks that cp mirrors call and its children, and that
+// each of them was executed on shards.
+func checkCallProfile(t *testing.T, cp *pilosa.CallProfile, call *pql.Call, shards []uint64) {
+	t.Helper()
+	if cp == nil || cp.Name != call.Name || len(cp.Children) != len(call.Children) {
+		t.Fatalf("profile of %s doesn't match: %+v", call, cp)
+	}
+	var got []uint64
+	for _, sp := range cp.Shards {
+		got = append(got, sp.Shard)
+	}
+	if !reflect.DeepEqual(got, shards) {
+		t.Fatalf("%s: unexpected shards: %v, expected %v", call, got, shards)
+	} else if cp.Duration <= 0 {
+		t.Fatalf("%s: unexpected duration: %v", call, cp.Duration)
+	}
+	for i, child := range call.Children {
+		checkCallProfile(t, cp.Children[i], child, shards)
+	}
+}
@@ -15,7 +15,7 @@
 package pilosa
 
 import (
-	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -81,6 +82,52 @@ type Field struct {
 	// Shards with data on any node in the cluster, according to this node.
 	remoteAvailableShards *roaring.Bitmap
 
+	// Accounts for cache memory across the holder.
+	cacheAccountant *cacheAccountant
+
+	// Rebuilds caches after imports.
+	cacheRebuilder *cacheRebuilder
+
+	// Snapshots fragments whose op logs are full.
+	snapshotQueue *snapshotQueue
+
+	// Highest column ID set in any field of the index.
+	maxColumnID *maxID
+
+	// Number of column IDs in each shard of the index.
+	shardWidth uint64
+
+	// Limits the number of views which can be created. Zero is unlimited.
+	maxViews int
+
+	// Determines which writes of the field's fragments are synced.
+	durability Durability
+	maxOpN     int
+
+	// How long closing a fragment waits for its readers.
+	fragmentCloseTimeout time.Duration
+
+	// Number of fragments of a view opened at once.
+	fragmentOpenConcurrency int
+
+	// What opening a view does with fragments which fail to open.
+	fragmentOpenPolicy FragmentOpenPolicy
+
+	// Opens the field's files without modifying them.
+	readOnly bool
+
+	// Layout of the fragments of new views.
+	fragmentLayout FragmentLayout
+
+	// Highest row ID written to the meta file. MaxRowID never reports less,
+	// even if the rows have since been cleared.
+	savedMaxRowID uint64
+
+	// Bits set and cleared in the field's fragments, and those last written
+	// to the meta file.
+	writeStats      writeStats
+	savedWriteStats FieldWriteStats
+
 	logger logger.Logger
 }
 
@@ -94,6 +141,30 @@ func OptFieldKeys() FieldOption {
 	}
 }
 
+// OptFieldRowLabel sets the name queries may use for the field's rows in
+// place of the field name.
+func OptFieldRowLabel(label string) FieldOption {
+	return func(fo *FieldOptions) error {
+		if err := validateLabel(label); err != nil {
+			return NewBadRequestError(err)
+		}
+		fo.RowLabel = label
+		return nil
+	}
+}
+
+// OptFieldAttrCommit sets when writes to the field's row attributes are
+// committed.
+func OptFieldAttrCommit(c AttrCommit) FieldOption {
+	return func(fo *FieldOptions) error {
+		if err := c.validate(); err != nil {
+			return NewBadRequestError(err)
+		}
+		fo.AttrCommit = c
+		return nil
+	}
+}
+
 func OptFieldTypeDefault() FieldOption {
 	return func(fo *FieldOptions) error {
 		if fo.Type != "" {
@@ -150,6 +221,18 @@ func OptFieldTypeTime(timeQuantum TimeQuantum, opt ...bool) FieldOption {
 	}
 }
 
+// OptFieldRetention sets the period for which the views of a time field
+// are kept. It must follow OptFieldTypeTime.
+func OptFieldRetention(r TimeRetention) FieldOption {
+	return func(fo *FieldOptions) error {
+		if fo.Type != FieldTypeTime {
+			return errors.Errorf("retention does not apply to field type: %s", fo.Type)
+		}
+		fo.Retention = r
+		return nil
+	}
+}
+
 func OptFieldTypeMutex(cacheType string, cacheSize uint32) FieldOption {
 	return func(fo *FieldOptions) error {
 		if fo.Type != "" {
@@ -174,7 +257,7 @@ func OptFieldTypeBool() FieldOption {
 
 // NewField returns a new instance of field.
 func NewField(path, index, name string, opts FieldOption) (*Field, error) {
-	err := validateName(name)
+	err := ValidateFieldName(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "validating name")
 	}
@@ -200,6 +283,8 @@ func newField(path, index, name string, opts FieldOption) (*Field, error) {
 
 		rowAttrStore: nopStore,
 
+		shardWidth: ShardWidth,
+
 		broadcaster: NopBroadcaster,
 		Stats:       stats.NopStatsClient,
 
@@ -280,21 +365,18 @@ func (f *Field) saveAvailableShards() error {
 }
 
 func (f *Field) unprotectedSaveAvailableShards() error {
-	// Open or create file.
-	path := filepath.Join(f.path, ".available.shards")
-
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
-	if err != nil {
-		return errors.Wrap(err, "opening available shards file")
+	// Read-only fields only keep the shards of other nodes in memory.
+	if f.readOnly {
+		return nil
 	}
-	defer file.Close()
 
 	// Write available shards to file.
-	bw := bufio.NewWriter(file)
-	if _, err = f.remoteAvailableShards.WriteTo(bw); err != nil {
+	var buf bytes.Buffer
+	if _, err := f.remoteAvailableShards.WriteTo(&buf); err != nil {
 		return errors.Wrap(err, "writing bitmap to buffer")
+	} else if err := writeMetaFile(filepath.Join(f.path, ".available.shards"), buf.Bytes(), 0666); err != nil {
+		return errors.Wrap(err, "writing available shards file")
 	}
-	bw.Flush()
 
 	return nil
 }
@@ -322,8 +404,9 @@ func (f *Field) Type() string {
 	return f.options.Type
 }
 
-// SetCacheSize sets the cache size for ranked fames. Persists to meta file on update.
-// defaults to DefaultCacheSize 50000
+// SetCacheSize sets the cache size for ranked and LRU fields. Persists to
+// meta file on update, and resizes the cache of every fragment of the field,
+// evicting the rows beyond the new size. Defaults to DefaultCacheSize 50000.
 func (f *Field) SetCacheSize(v uint32) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -339,6 +422,10 @@ func (f *Field) SetCacheSize(v uint32) error {
 		return errors.Wrap(err, "saving")
 	}
 
+	for _, view := range f.viewMap {
+		view.setCacheSize(v)
+	}
+
 	return nil
 }
 
@@ -360,9 +447,11 @@ func (f *Field) Options() FieldOptions {
 // Open opens and initializes the field.
 func (f *Field) Open() error {
 	if err := func() error {
-		// Ensure the field's path exists.
-		if err := os.MkdirAll(f.path, 0777); err != nil {
-			return errors.Wrap(err, "creating field dir")
+		// Ensure the field's path exists, unless the field is read-only.
+		if !f.readOnly {
+			if err := os.MkdirAll(f.path, 0777); err != nil {
+				return errors.Wrap(err, "creating field dir")
+			}
 		}
 
 		if err := f.loadMeta(); err != nil {
@@ -415,7 +504,12 @@ func (f *Field) openViews() error {
 			continue
 		}
 
+		// Views created before their names were validated are still opened,
+		// so that their data isn't lost on upgrade.
 		name := filepath.Base(fi.Name())
+		if err := ValidateViewName(name); err != nil {
+			f.logger.Printf("WARNING opening view with invalid name: index=%s, field=%s, view=%q, err=%s", f.index, f.name, name, err)
+		}
 		view := f.newView(f.viewPath(name), name)
 		if err := view.open(); err != nil {
 			return fmt.Errorf("opening view: view=%s, err=%s", view.name, err)
@@ -444,35 +538,128 @@ func (f *Field) loadMeta() error {
 	}
 
 	// Copy metadata fields.
-	f.options.Type = pb.Type
-	f.options.CacheType = pb.CacheType
-	f.options.CacheSize = pb.CacheSize
-	f.options.Min = pb.Min
-	f.options.Max = pb.Max
-	f.options.TimeQuantum = TimeQuantum(pb.TimeQuantum)
-	f.options.Keys = pb.Keys
-	f.options.NoStandardView = pb.NoStandardView
+	persisted := FieldOptions{
+		Type:           pb.Type,
+		CacheType:      pb.CacheType,
+		CacheSize:      pb.CacheSize,
+		Min:            pb.Min,
+		Max:            pb.Max,
+		TimeQuantum:    TimeQuantum(pb.TimeQuantum),
+		Keys:           pb.Keys,
+		NoStandardView: pb.NoStandardView,
+		Retention:      decodeTimeRetention(pb.Retention),
+
+		TimeQuantumInherited: pb.TimeQuantumInherited,
+		RowLabel:             pb.RowLabel,
+		AttrCommit:           decodeAttrCommit(pb.AttrCommit),
+	}
+
+	// The persisted options replace those the field was constructed with,
+	// which are only worth a warning if they weren't the defaults.
+	if f.options != persisted && f.options != applyDefaultOptions(FieldOptions{}) {
+		f.logger.Printf("WARNING field options differ from meta file, using meta file: index=%s, field=%s, options=%+v, meta=%+v", f.index, f.name, f.options, persisted)
+	}
+	f.options = persisted
+	f.savedMaxRowID = pb.MaxRowID
+	f.savedWriteStats = FieldWriteStats{BitsSet: pb.BitsSet, BitsCleared: pb.BitsCleared, LastWrite: decodeWriteTime(pb.LastWrite)}
+	f.writeStats.reset(f.savedWriteStats)
 
 	return nil
 }
 
 // saveMeta writes meta data for the field.
 func (f *Field) saveMeta() error {
+	// Options applied while opening a read-only field are kept in memory.
+	if f.readOnly {
+		return nil
+	}
+
 	// Marshal metadata.
 	fo := f.options
-	buf, err := proto.Marshal(fo.encode())
+	pb := fo.encode()
+	pb.MaxRowID = f.unprotectedMaxRowID()
+	ws := f.writeStats.value()
+	pb.BitsSet, pb.BitsCleared, pb.LastWrite = ws.BitsSet, ws.BitsCleared, encodeWriteTime(ws.LastWrite)
+	buf, err := proto.Marshal(pb)
 	if err != nil {
 		return errors.Wrap(err, "marshaling")
 	}
 
 	// Write to meta file.
-	if err := ioutil.WriteFile(filepath.Join(f.path, ".meta"), buf, 0666); err != nil {
+	if err := writeMetaFile(filepath.Join(f.path, ".meta"), buf, 0666); err != nil {
 		return errors.Wrap(err, "writing meta")
 	}
+	f.savedMaxRowID = pb.MaxRowID
+	f.savedWriteStats = ws
 
 	return nil
 }
 
+// MaxRowID returns the highest row ID ever set in the field. Rows of
+// int fields are bits of their values so they are not included.
+func (f *Field) MaxRowID() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.unprotectedMaxRowID()
+}
+
+func (f *Field) unprotectedMaxRowID() uint64 {
+	max := f.savedMaxRowID
+	for name, view := range f.viewMap {
+		if strings.HasPrefix(name, viewBSIGroupPrefix) {
+			continue
+		}
+		if rowID := view.maxRowID(); rowID > max {
+			max = rowID
+		}
+	}
+	return max
+}
+
+// saveMaxRowID writes the meta file if the max row ID has increased since
+// it was last written.
+func (f *Field) saveMaxRowID() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.unprotectedSaveMaxRowID()
+}
+
+func (f *Field) unprotectedSaveMaxRowID() error {
+	if f.readOnly || f.unprotectedMaxRowID() == f.savedMaxRowID {
+		return nil
+	}
+	return f.saveMeta()
+}
+
+// WriteStats returns the number of bits set and cleared in the field on this
+// node, and when it was last written, or nil if it has never been written.
+func (f *Field) WriteStats() *FieldWriteStats {
+	ws := f.writeStats.value()
+	if ws == (FieldWriteStats{}) {
+		return nil
+	}
+	return &ws
+}
+
+// saveWriteStats writes the field's write statistics to the meta file if
+// they have changed since they were last written, and reports them.
+func (f *Field) saveWriteStats() error {
+	ws := f.writeStats.value()
+	f.Stats.Gauge("writes.bitsSet", float64(ws.BitsSet), 1.0)
+	f.Stats.Gauge("writes.bitsCleared", float64(ws.BitsCleared), 1.0)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.unprotectedSaveWriteStats()
+}
+
+func (f *Field) unprotectedSaveWriteStats() error {
+	if f.readOnly || f.writeStats.value() == f.savedWriteStats {
+		return nil
+	}
+	return f.saveMeta()
+}
+
 // applyOptions configures the field based on opt.
 func (f *Field) applyOptions(opt FieldOptions) error {
 	switch opt.Type {
@@ -496,6 +683,7 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.Max = 0
 		f.options.TimeQuantum = ""
 		f.options.Keys = opt.Keys
+		f.options.RowLabel = opt.RowLabel
 	case FieldTypeInt:
 		f.options.Type = opt.Type
 		f.options.CacheType = CacheTypeNone
@@ -527,11 +715,14 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.Max = 0
 		f.options.Keys = opt.Keys
 		f.options.NoStandardView = opt.NoStandardView
+		f.options.Retention = opt.Retention
+		f.options.RowLabel = opt.RowLabel
 		// Set the time quantum.
 		if err := f.setTimeQuantum(opt.TimeQuantum); err != nil {
 			f.Close()
 			return errors.Wrap(err, "setting time quantum")
 		}
+		f.options.TimeQuantumInherited = opt.TimeQuantumInherited
 	case FieldTypeBool:
 		f.options.Type = FieldTypeBool
 		f.options.CacheType = CacheTypeNone
@@ -544,6 +735,14 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		return errors.New("invalid field type")
 	}
 
+	// Stores which can't batch commits commit every write.
+	f.options.AttrCommit = opt.AttrCommit
+	if s, ok := f.rowAttrStore.(attrCommitter); ok {
+		if err := s.SetAttrCommit(opt.AttrCommit); err != nil {
+			return errors.Wrap(err, "setting attr commit")
+		}
+	}
+
 	return nil
 }
 
@@ -552,20 +751,27 @@ func (f *Field) Close() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// Close the attribute store.
-	if f.rowAttrStore != nil {
-		_ = f.rowAttrStore.Close()
-	}
+	// Persist the max row ID before the fragments holding it are closed.
+	var errs closeErrors
+	errs.append(f.unprotectedSaveMaxRowID(), "index=%s field=%s: saving max row id", f.index, f.name)
+	errs.append(f.unprotectedSaveWriteStats(), "index=%s field=%s: saving write stats", f.index, f.name)
 
-	// Close all views.
-	for _, view := range f.viewMap {
-		if err := view.close(); err != nil {
-			return err
-		}
+	// Close all views, in name order, then the attribute store.
+	names := make([]string, 0, len(f.viewMap))
+	for name := range f.viewMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs.append(f.viewMap[name].close(), "index=%s field=%s view=%s", f.index, f.name, name)
 	}
 	f.viewMap = make(map[string]*view)
 
-	return nil
+	if f.rowAttrStore != nil {
+		errs.append(f.rowAttrStore.Close(), "index=%s field=%s: closing row attr store", f.index, f.name)
+	}
+
+	return errs.err()
 }
 
 // keys returns true if the field uses string keys.
@@ -605,8 +811,9 @@ func (f *Field) createBSIGroup(bsig *bsiGroup) error {
 	// Append bsiGroup.
 	if err := f.addBSIGroup(bsig); err != nil {
 		return err
+	} else if err := f.saveMeta(); err != nil {
+		return errors.Wrap(err, "saving meta")
 	}
-	f.saveMeta()
 	return nil
 }
 
@@ -629,6 +836,13 @@ func (f *Field) addBSIGroup(bsig *bsiGroup) error {
 	return nil
 }
 
+// RowLabel returns the name queries may use for the field's rows.
+func (f *Field) RowLabel() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.options.RowLabel
+}
+
 // TimeQuantum returns the time quantum for the field.
 func (f *Field) TimeQuantum() TimeQuantum {
 	f.mu.Lock()
@@ -646,8 +860,9 @@ func (f *Field) setTimeQuantum(q TimeQuantum) error {
 		return ErrInvalidTimeQuantum
 	}
 
-	// Update value on field.
+	// Update value on field. A quantum set on the field is its own.
 	f.options.TimeQuantum = q
+	f.options.TimeQuantumInherited = false
 
 	// Persist meta data to disk.
 	if err := f.saveMeta(); err != nil {
@@ -697,6 +912,37 @@ func (f *Field) views() []*view {
 	return other
 }
 
+// expiredViews returns the names of time views whose period is older than
+// the field's retention, in sorted order.
+func (f *Field) expiredViews(now time.Time) []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.options.Retention.IsZero() {
+		return nil
+	}
+
+	var names []string
+	for name := range f.viewMap {
+		if f.options.Retention.expired(name, now) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deleteExpiredView deletes a view found by expiredViews. Views which no
+// longer exist are ignored.
+func (f *Field) deleteExpiredView(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.deleteView(name); err != nil && err != ErrInvalidView {
+		return err
+	}
+	return nil
+}
+
 // recalculateCaches recalculates caches on every view in the field.
 func (f *Field) recalculateCaches() {
 	for _, view := range f.views() {
@@ -704,6 +950,28 @@ func (f *Field) recalculateCaches() {
 	}
 }
 
+// rebuildCaches rebuilds the caches of every view in the field from storage
+// in the background.
+func (f *Field) rebuildCaches() error {
+	for _, view := range f.views() {
+		if err := view.rebuildCaches(); err != nil {
+			return errors.Wrapf(err, "view %s", view.name)
+		}
+	}
+	return nil
+}
+
+// clearColumnRange clears the columns in [min, max] from every view of the
+// field on this node.
+func (f *Field) clearColumnRange(min, max uint64) error {
+	for _, view := range f.views() {
+		if _, err := view.clearColumnRange(min, max); err != nil {
+			return errors.Wrapf(err, "clearing view %s", view.name)
+		}
+	}
+	return nil
+}
+
 // createViewIfNotExists returns the named view, creating it if necessary.
 // Additionally, a CreateViewMessage is sent to the cluster.
 func (f *Field) createViewIfNotExists(name string) (*view, error) {
@@ -736,6 +1004,12 @@ func (f *Field) createViewIfNotExistsBase(name string) (*view, bool, error) {
 
 	if view := f.viewMap[name]; view != nil {
 		return view, false, nil
+	} else if err := f.validateViewName(name); err != nil {
+		return nil, false, NewBadRequestError(errors.Wrapf(err, "view %q", name))
+	} else if f.readOnly {
+		return nil, false, newForbiddenError(ErrReadOnly)
+	} else if f.maxViews > 0 && len(f.viewMap) >= f.maxViews {
+		return nil, false, NewBadRequestError(errors.Wrapf(ErrTooManyViews, "limit %d", f.maxViews))
 	}
 	view := f.newView(f.viewPath(name), name)
 
@@ -748,20 +1022,53 @@ func (f *Field) createViewIfNotExistsBase(name string) (*view, bool, error) {
 	return view, true, nil
 }
 
+// validateViewName returns an error if a view of the field can't be created
+// with name. The BSI group view of a field with a legacy name keeps that
+// name.
+func (f *Field) validateViewName(name string) error {
+	if name == viewBSIGroupPrefix+f.name {
+		return nil
+	}
+	return ValidateViewName(name)
+}
+
 func (f *Field) newView(path, name string) *view {
 	view := newView(path, f.index, f.name, name, f.options)
 	view.logger = f.logger
 	view.rowAttrStore = f.rowAttrStore
 	view.stats = f.Stats.WithTags(fmt.Sprintf("view:%s", name))
 	view.broadcaster = f.broadcaster
+	view.cacheAccountant = f.cacheAccountant
+	view.cacheRebuilder = f.cacheRebuilder
+	view.snapshotQueue = f.snapshotQueue
+	view.maxColumnID = f.maxColumnID
+	view.writeStats = &f.writeStats
+	view.shardWidth = f.shardWidth
+	view.durability = f.durability
+	view.maxOpN = f.maxOpN
+	view.fragmentCloseTimeout = f.fragmentCloseTimeout
+	view.fragmentOpenConcurrency = f.fragmentOpenConcurrency
+	view.fragmentOpenPolicy = f.fragmentOpenPolicy
+	view.readOnly = f.readOnly
+	view.fragmentLayout = f.fragmentLayout
 	return view
 }
 
-// deleteView removes the view from the field.
+// DeleteView closes a view of the field and removes its files. It returns
+// ErrInvalidView if the field has no such view.
+func (f *Field) DeleteView(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.deleteView(name)
+}
+
+// deleteView removes the view from the field. The field must be locked.
 func (f *Field) deleteView(name string) error {
 	view := f.viewMap[name]
 	if view == nil {
 		return ErrInvalidView
+	} else if f.readOnly {
+		return newForbiddenError(ErrReadOnly)
 	}
 
 	// Close data files before deletion.
@@ -880,6 +1187,24 @@ func (f *Field) ClearBit(rowID, colID uint64) (changed bool, err error) {
 	return changed, nil
 }
 
+// ClearRow clears a row in every view and shard of the field held locally.
+func (f *Field) ClearRow(rowID uint64) (changed bool, err error) {
+	switch f.Type() {
+	case FieldTypeSet, FieldTypeTime, FieldTypeMutex, FieldTypeBool:
+	default:
+		return false, errors.Errorf("row can't be cleared on a %s field", f.Type())
+	}
+
+	for _, view := range f.views() {
+		cleared, err := view.clearRow(rowID)
+		if err != nil {
+			return changed, errors.Wrapf(err, "clearing on view %s", view.name)
+		}
+		changed = changed || cleared
+	}
+	return changed, nil
+}
+
 func groupCompare(a, b string, offset int) (lt, eq bool) {
 	if len(a) > offset {
 		a = a[:offset]
@@ -1050,15 +1375,16 @@ func (f *Field) Range(name string, op pql.Token, predicate int64) (*Row, error)
 	return view.rangeOp(op, bsig.BitDepth(), baseValue)
 }
 
-// Import bulk imports data.
-func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts ...ImportOption) error {
+// Import bulk imports data. It returns the number of distinct bits imported
+// into each shard, and how many of them changed.
+func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts ...ImportOption) ([]ImportCount, error) {
 
 	// Set up import options.
 	options := &ImportOptions{}
 	for _, opt := range opts {
 		err := opt(options)
 		if err != nil {
-			return errors.Wrap(err, "applying option")
+			return nil, errors.Wrap(err, "applying option")
 		}
 	}
 
@@ -1066,9 +1392,9 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 	q := f.TimeQuantum()
 	if hasTime(timestamps) {
 		if q == "" {
-			return errors.New("time quantum not set in field")
+			return nil, errors.New("time quantum not set in field")
 		} else if options.Clear {
-			return errors.New("import clear is not supported with timestamps")
+			return nil, errors.New("import clear is not supported with timestamps")
 		}
 	}
 
@@ -1081,7 +1407,7 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 
 		// Bool-specific data validation.
 		if fieldType == FieldTypeBool && rowID > 1 {
-			return errors.New("bool field imports only support values 0 and 1")
+			return nil, errors.New("bool field imports only support values 0 and 1")
 		}
 
 		var timestamp *time.Time
@@ -1089,21 +1415,9 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 			timestamp = timestamps[i]
 		}
 
-		var standard []string
-		if timestamp == nil {
-			standard = []string{viewStandard}
-		} else {
-			standard = viewsByTime(viewStandard, *timestamp, q)
-			if !f.options.NoStandardView {
-				// In order to match the logic of `SetBit()`, we want bits
-				// with timestamps to write to both time and standard views.
-				standard = append(standard, viewStandard)
-			}
-		}
-
 		// Attach bit to each standard view.
-		for _, name := range standard {
-			key := importKey{View: name, Shard: columnID / ShardWidth}
+		for _, name := range f.importViews(timestamp, q) {
+			key := importKey{View: name, Shard: columnID / f.shardWidth}
 			data := dataByFragment[key]
 			data.RowIDs = append(data.RowIDs, rowID)
 			data.ColumnIDs = append(data.ColumnIDs, columnID)
@@ -1111,24 +1425,135 @@ func (f *Field) Import(rowIDs, columnIDs []uint64, timestamps []*time.Time, opts
 		}
 	}
 
-	// Import into each fragment.
+	return f.importFragments(dataByFragment, options, func(view string) bool {
+		return f.importCountedView(view, q)
+	})
+}
+
+// importView imports bits into a single view, such as one written by a
+// protobuf export, instead of the views derived from their timestamps.
+func (f *Field) importView(name string, rowIDs, columnIDs []uint64, opts ...ImportOption) ([]ImportCount, error) {
+	options := &ImportOptions{}
+	for _, opt := range opts {
+		err := opt(options)
+		if err != nil {
+			return nil, errors.Wrap(err, "applying option")
+		}
+	}
+
+	dataByFragment := make(map[importKey]importData)
+	for i := range rowIDs {
+		rowID, columnID := rowIDs[i], columnIDs[i]
+		if f.Type() == FieldTypeBool && rowID > 1 {
+			return nil, errors.New("bool field imports only support values 0 and 1")
+		}
+
+		key := importKey{View: name, Shard: columnID / f.shardWidth}
+		data := dataByFragment[key]
+		data.RowIDs = append(data.RowIDs, rowID)
+		data.ColumnIDs = append(data.ColumnIDs, columnID)
+		dataByFragment[key] = data
+	}
+	return f.importFragments(dataByFragment, options, nil)
+}
+
+// importFragments bulk imports bits grouped by fragment. It returns the bits
+// imported into each shard, and how many of them changed, as counted by the
+// fragments of the views for which counted returns true, or of every view if
+// counted is nil.
+func (f *Field) importFragments(dataByFragment map[importKey]importData, options *ImportOptions, counted func(view string) bool) ([]ImportCount, error) {
+	counts := make(map[uint64]*ImportCount)
 	for key, data := range dataByFragment {
 		view, err := f.createViewIfNotExists(key.View)
 		if err != nil {
-			return errors.Wrap(err, "creating view")
+			return nil, errors.Wrap(err, "creating view")
 		}
 
-		frag, err := view.CreateFragmentIfNotExists(key.Shard)
+		frag, err := view.acquireFragmentIfNotExists(key.Shard)
 		if err != nil {
-			return errors.Wrap(err, "creating fragment")
+			return nil, errors.Wrap(err, "creating fragment")
 		}
 
-		if err := frag.bulkImport(data.RowIDs, data.ColumnIDs, options); err != nil {
-			return err
+		n, changed, err := frag.bulkImportCount(data.RowIDs, data.ColumnIDs, options)
+		frag.release()
+		if err != nil {
+			return nil, err
+		} else if counted != nil && !counted(key.View) {
+			continue
 		}
+		c := counts[key.Shard]
+		if c == nil {
+			c = &ImportCount{Shard: key.Shard}
+			counts[key.Shard] = c
+		}
+		c.Bits += uint64(n)
+		c.Changed += uint64(changed)
 	}
 
-	return nil
+	a := make([]ImportCount, 0, len(counts))
+	for _, c := range counts {
+		a = append(a, *c)
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Shard < a[j].Shard })
+	return a, nil
+}
+
+// importCountedView returns true if the bits imported into the view of f with
+// the given name are counted by an import, which counts each bit once: in the
+// standard view, or, for a bit with a timestamp in a field without a standard
+// view, in the view of the smallest unit of the field's time quantum.
+func (f *Field) importCountedView(name string, q TimeQuantum) bool {
+	if name == viewStandard {
+		return true
+	} else if !f.options.NoStandardView || q == "" {
+		return false
+	}
+	unit, _, err := viewTimeUnit(name)
+	return err == nil && unit.char == rune(q[len(q)-1])
+}
+
+// importableView returns true if bits can be imported directly into the view
+// of f with the given name, i.e. the standard view or one of its time views.
+func (f *Field) importableView(name string) bool {
+	if name == viewStandard {
+		return true
+	} else if f.Type() != FieldTypeTime {
+		return false
+	}
+	unit, start, err := viewTimeUnit(name)
+	return err == nil && name == viewByTimeUnit(viewStandard, start, unit.char)
+}
+
+// importViews returns the views a bit with an optional timestamp is imported
+// into. Bits without a timestamp are only imported into the standard view.
+func (f *Field) importViews(timestamp *time.Time, q TimeQuantum) []string {
+	if timestamp == nil {
+		return []string{viewStandard}
+	}
+	views := viewsByTime(viewStandard, *timestamp, q)
+	if !f.options.NoStandardView {
+		// In order to match the logic of `SetBit()`, we want bits
+		// with timestamps to write to both time and standard views.
+		views = append(views, viewStandard)
+	}
+	return views
+}
+
+// importViewCounts returns the number of bits imported into each view for a
+// set of bits with optional timestamps.
+func (f *Field) importViewCounts(n int, timestamps []*time.Time) map[string]uint64 {
+	q := f.TimeQuantum()
+	m := make(map[string]uint64)
+	for i := 0; i < n; i++ {
+		var timestamp *time.Time
+		if len(timestamps) > i {
+			timestamp = timestamps[i]
+		}
+		for _, name := range f.importViews(timestamp, q) {
+			m[name]++
+		}
+	}
+	return m
 }
 
 // importValue bulk imports range-encoded value data.
@@ -1152,7 +1577,7 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 
 		// Attach value to each bsiGroup view.
 		for _, name := range []string{viewName} {
-			key := importKey{View: name, Shard: columnID / ShardWidth}
+			key := importKey{View: name, Shard: columnID / f.shardWidth}
 			data := dataByFragment[key]
 			data.ColumnIDs = append(data.ColumnIDs, columnID)
 			data.Values = append(data.Values, value)
@@ -1170,7 +1595,7 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 			return errors.Wrap(err, "creating view")
 		}
 
-		frag, err := view.CreateFragmentIfNotExists(key.Shard)
+		frag, err := view.acquireFragmentIfNotExists(key.Shard)
 		if err != nil {
 			return errors.Wrap(err, "creating fragment")
 		}
@@ -1180,7 +1605,9 @@ func (f *Field) importValue(columnIDs []uint64, values []int64, options *ImportO
 			baseValues[i] = uint64(value - bsig.Min)
 		}
 
-		if err := frag.importValue(data.ColumnIDs, baseValues, bsig.BitDepth(), options.Clear); err != nil {
+		err = frag.importValue(data.ColumnIDs, baseValues, bsig.BitDepth(), options.Clear)
+		frag.release()
+		if err != nil {
 			return err
 		}
 	}
@@ -1197,10 +1624,11 @@ func (f *Field) importRoaring(data []byte, shard uint64, viewName string, clear
 		return errors.Wrap(err, "creating view")
 	}
 
-	frag, err := view.CreateFragmentIfNotExists(shard)
+	frag, err := view.acquireFragmentIfNotExists(shard)
 	if err != nil {
 		return errors.Wrap(err, "creating fragment")
 	}
+	defer frag.release()
 
 	if err := frag.importRoaring(data, clear); err != nil {
 		return err
@@ -1209,6 +1637,22 @@ func (f *Field) importRoaring(data []byte, shard uint64, viewName string, clear
 	return nil
 }
 
+// importRoaringRows unions, or clears, whole rows of a shard in the standard
+// view. Each bitmap holds the column offsets of its row within the shard.
+func (f *Field) importRoaringRows(shard uint64, rows map[uint64]*roaring.Bitmap, clear bool) error {
+	view, err := f.createViewIfNotExists(viewStandard)
+	if err != nil {
+		return errors.Wrap(err, "creating view")
+	}
+
+	frag, err := view.acquireFragmentIfNotExists(shard)
+	if err != nil {
+		return errors.Wrap(err, "creating fragment")
+	}
+	defer frag.release()
+	return frag.importRoaringRows(rows, clear)
+}
+
 type fieldSlice []*Field
 
 func (p fieldSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -1217,9 +1661,122 @@ func (p fieldSlice) Less(i, j int) bool { return p[i].Name() < p[j].Name() }
 
 // FieldInfo represents schema information for a field.
 type FieldInfo struct {
-	Name    string       `json:"name"`
-	Options FieldOptions `json:"options"`
-	Views   []*ViewInfo  `json:"views,omitempty"`
+	Name     string           `json:"name"`
+	Options  FieldOptions     `json:"options"`
+	Views    []*ViewInfo      `json:"views,omitempty"`
+	Cache    *FieldCacheStats `json:"cache,omitempty"`
+	Writes   *FieldWriteStats `json:"writes,omitempty"`
+	MaxRowID uint64           `json:"maxRowID,omitempty"`
+}
+
+// FieldCacheStats holds statistics about the row count caches of a field's
+// standard view, aggregated across the local fragments.
+type FieldCacheStats struct {
+	// Cache type and configured size per fragment.
+	Type string `json:"type"`
+	Size uint32 `json:"size"`
+
+	// Total number of rows cached.
+	Rows int `json:"rows"`
+
+	// Lowest count of the lowest ranked cached row of any fragment.
+	Threshold uint64 `json:"threshold"`
+
+	// Number of row counts TopN read from storage rather than the cache.
+	Scans uint64 `json:"scans"`
+}
+
+// cacheStats returns statistics about the field's caches from in-memory state.
+func (f *Field) cacheStats() *FieldCacheStats {
+	opts := f.Options()
+	stats := &FieldCacheStats{
+		Type: opts.CacheType,
+		Size: opts.CacheSize,
+	}
+
+	view := f.view(viewStandard)
+	if view == nil {
+		return stats
+	}
+	for _, frag := range view.loadedFragments() {
+		fs := frag.cacheStats()
+		stats.Rows += fs.rows
+		stats.Scans += fs.scans
+		if fs.rows > 0 && (stats.Threshold == 0 || fs.threshold < stats.Threshold) {
+			stats.Threshold = fs.threshold
+		}
+	}
+	return stats
+}
+
+// FieldWriteStats counts the bits set and cleared in the fragments of a field
+// on a node, including those of its time views and of the bits of its values,
+// and holds the time any of them was last changed. Writes which changed no
+// bits aren't counted. They are kept across restarts, less the writes of the
+// last few seconds before a crash.
+type FieldWriteStats struct {
+	BitsSet     uint64    `json:"bitsSet"`
+	BitsCleared uint64    `json:"bitsCleared"`
+	LastWrite   time.Time `json:"lastWrite"`
+}
+
+// writeStats accumulates the FieldWriteStats of a field. It is safe for
+// concurrent use and a nil writeStats ignores the writes it observes.
+type writeStats struct {
+	set     uint64
+	cleared uint64
+	last    int64 // Unix nanoseconds
+}
+
+// observe counts bits set and cleared by a write. Writes which changed no
+// bits are ignored.
+func (s *writeStats) observe(set, cleared int) {
+	if s == nil || (set <= 0 && cleared <= 0) {
+		return
+	}
+	if set > 0 {
+		atomic.AddUint64(&s.set, uint64(set))
+	}
+	if cleared > 0 {
+		atomic.AddUint64(&s.cleared, uint64(cleared))
+	}
+	atomic.StoreInt64(&s.last, time.Now().UnixNano())
+}
+
+// value returns the statistics observed.
+func (s *writeStats) value() FieldWriteStats {
+	if s == nil {
+		return FieldWriteStats{}
+	}
+	return FieldWriteStats{
+		BitsSet:     atomic.LoadUint64(&s.set),
+		BitsCleared: atomic.LoadUint64(&s.cleared),
+		LastWrite:   decodeWriteTime(atomic.LoadInt64(&s.last)),
+	}
+}
+
+// reset replaces the statistics observed with ws.
+func (s *writeStats) reset(ws FieldWriteStats) {
+	atomic.StoreUint64(&s.set, ws.BitsSet)
+	atomic.StoreUint64(&s.cleared, ws.BitsCleared)
+	atomic.StoreInt64(&s.last, encodeWriteTime(ws.LastWrite))
+}
+
+// encodeWriteTime returns t in Unix nanoseconds, or zero for the zero time.
+func encodeWriteTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// decodeWriteTime returns the UTC time of n Unix nanoseconds, or the zero
+// time for zero.
+func decodeWriteTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
 }
 
 type fieldInfoSlice []*FieldInfo
@@ -1230,14 +1787,25 @@ func (p fieldInfoSlice) Less(i, j int) bool { return p[i].Name < p[j].Name }
 
 // FieldOptions represents options to set when initializing a field.
 type FieldOptions struct {
-	Min            int64       `json:"min,omitempty"`
-	Max            int64       `json:"max,omitempty"`
-	Keys           bool        `json:"keys"`
-	NoStandardView bool        `json:"noStandardView,omitempty"`
-	CacheSize      uint32      `json:"cacheSize,omitempty"`
-	CacheType      string      `json:"cacheType,omitempty"`
-	Type           string      `json:"type,omitempty"`
-	TimeQuantum    TimeQuantum `json:"timeQuantum,omitempty"`
+	Min            int64         `json:"min,omitempty"`
+	Max            int64         `json:"max,omitempty"`
+	Keys           bool          `json:"keys"`
+	NoStandardView bool          `json:"noStandardView,omitempty"`
+	CacheSize      uint32        `json:"cacheSize,omitempty"`
+	CacheType      string        `json:"cacheType,omitempty"`
+	Type           string        `json:"type,omitempty"`
+	TimeQuantum    TimeQuantum   `json:"timeQuantum,omitempty"`
+	Retention      TimeRetention `json:"retention,omitempty"`
+
+	// TimeQuantumInherited is true if the time quantum was copied from the
+	// index's default when the field was created.
+	TimeQuantumInherited bool `json:"timeQuantumInherited,omitempty"`
+
+	// RowLabel is the name queries may use for the field's rows.
+	RowLabel string `json:"rowLabel,omitempty"`
+
+	// AttrCommit is when writes to the field's row attributes are committed.
+	AttrCommit AttrCommit `json:"attrCommit,omitempty"`
 }
 
 // applyDefaultOptions returns a new FieldOptions object
@@ -1271,6 +1839,61 @@ func encodeFieldOptions(o *FieldOptions) *internal.FieldOptions {
 		TimeQuantum:    string(o.TimeQuantum),
 		Keys:           o.Keys,
 		NoStandardView: o.NoStandardView,
+		Retention:      encodeTimeRetention(o.Retention),
+
+		TimeQuantumInherited: o.TimeQuantumInherited,
+		RowLabel:             o.RowLabel,
+		AttrCommit:           encodeAttrCommit(o.AttrCommit),
+	}
+}
+
+func encodeAttrCommit(c AttrCommit) *internal.AttrCommit {
+	if c.IsZero() {
+		return nil
+	}
+	return &internal.AttrCommit{
+		Mode:     c.Mode,
+		Interval: int64(c.Interval),
+		MaxOps:   int64(c.MaxOps),
+	}
+}
+
+func decodeAttrCommit(pb *internal.AttrCommit) AttrCommit {
+	if pb == nil {
+		return AttrCommit{}
+	}
+	return AttrCommit{
+		Mode:     pb.Mode,
+		Interval: time.Duration(pb.Interval),
+		MaxOps:   int(pb.MaxOps),
+	}
+}
+
+func encodeTimeRetention(r TimeRetention) *internal.TimeRetention {
+	if r.IsZero() {
+		return nil
+	}
+	return &internal.TimeRetention{
+		Year:    int64(r.Year),
+		Quarter: int64(r.Quarter),
+		Month:   int64(r.Month),
+		Day:     int64(r.Day),
+		Hour:    int64(r.Hour),
+		Minute:  int64(r.Minute),
+	}
+}
+
+func decodeTimeRetention(pb *internal.TimeRetention) TimeRetention {
+	if pb == nil {
+		return TimeRetention{}
+	}
+	return TimeRetention{
+		Year:    time.Duration(pb.Year),
+		Quarter: time.Duration(pb.Quarter),
+		Month:   time.Duration(pb.Month),
+		Day:     time.Duration(pb.Day),
+		Hour:    time.Duration(pb.Hour),
+		Minute:  time.Duration(pb.Minute),
 	}
 }
 
@@ -1278,62 +1901,100 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 	switch o.Type {
 	case FieldTypeSet:
 		return json.Marshal(struct {
-			Type      string `json:"type"`
-			CacheType string `json:"cacheType"`
-			CacheSize uint32 `json:"cacheSize"`
-			Keys      bool   `json:"keys"`
+			Type       string      `json:"type"`
+			CacheType  string      `json:"cacheType"`
+			CacheSize  uint32      `json:"cacheSize"`
+			Keys       bool        `json:"keys"`
+			RowLabel   string      `json:"rowLabel,omitempty"`
+			AttrCommit *AttrCommit `json:"attrCommit,omitempty"`
 		}{
 			o.Type,
 			o.CacheType,
 			o.CacheSize,
 			o.Keys,
+			o.RowLabel,
+			o.attrCommit(),
 		})
 	case FieldTypeInt:
 		return json.Marshal(struct {
-			Type string `json:"type"`
-			Min  int64  `json:"min"`
-			Max  int64  `json:"max"`
-			Keys bool   `json:"keys"`
+			Type       string      `json:"type"`
+			Min        int64       `json:"min"`
+			Max        int64       `json:"max"`
+			Keys       bool        `json:"keys"`
+			AttrCommit *AttrCommit `json:"attrCommit,omitempty"`
 		}{
 			o.Type,
 			o.Min,
 			o.Max,
 			o.Keys,
+			o.attrCommit(),
 		})
 	case FieldTypeTime:
 		return json.Marshal(struct {
-			Type           string      `json:"type"`
-			TimeQuantum    TimeQuantum `json:"timeQuantum"`
-			Keys           bool        `json:"keys"`
-			NoStandardView bool        `json:"noStandardView"`
+			Type                 string         `json:"type"`
+			TimeQuantum          TimeQuantum    `json:"timeQuantum"`
+			TimeQuantumInherited bool           `json:"timeQuantumInherited"`
+			Keys                 bool           `json:"keys"`
+			NoStandardView       bool           `json:"noStandardView"`
+			Retention            *TimeRetention `json:"retention,omitempty"`
+			RowLabel             string         `json:"rowLabel,omitempty"`
+			AttrCommit           *AttrCommit    `json:"attrCommit,omitempty"`
 		}{
 			o.Type,
 			o.TimeQuantum,
+			o.TimeQuantumInherited,
 			o.Keys,
 			o.NoStandardView,
+			o.retention(),
+			o.RowLabel,
+			o.attrCommit(),
 		})
 	case FieldTypeMutex:
 		return json.Marshal(struct {
-			Type      string `json:"type"`
-			CacheType string `json:"cacheType"`
-			CacheSize uint32 `json:"cacheSize"`
-			Keys      bool   `json:"keys"`
+			Type       string      `json:"type"`
+			CacheType  string      `json:"cacheType"`
+			CacheSize  uint32      `json:"cacheSize"`
+			Keys       bool        `json:"keys"`
+			RowLabel   string      `json:"rowLabel,omitempty"`
+			AttrCommit *AttrCommit `json:"attrCommit,omitempty"`
 		}{
 			o.Type,
 			o.CacheType,
 			o.CacheSize,
 			o.Keys,
+			o.RowLabel,
+			o.attrCommit(),
 		})
 	case FieldTypeBool:
 		return json.Marshal(struct {
-			Type string `json:"type"`
+			Type       string      `json:"type"`
+			AttrCommit *AttrCommit `json:"attrCommit,omitempty"`
 		}{
 			o.Type,
+			o.attrCommit(),
 		})
 	}
 	return nil, errors.New("invalid field type")
 }
 
+// attrCommit returns the attribute commit mode for JSON encoding, or nil if
+// every write is committed.
+func (o *FieldOptions) attrCommit() *AttrCommit {
+	if o.AttrCommit.IsZero() {
+		return nil
+	}
+	return &o.AttrCommit
+}
+
+// retention returns the retention of a time field for JSON encoding, or nil
+// if views are kept forever.
+func (o *FieldOptions) retention() *TimeRetention {
+	if o.Retention.IsZero() {
+		return nil
+	}
+	return &o.Retention
+}
+
 // List of bsiGroup types.
 const (
 	bsiGroupTypeInt = "int"
@@ -15,14 +15,22 @@
 package pilosa
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/pilosa/pilosa/logger"
 	"github.com/pilosa/pilosa/pql"
 	"github.com/pilosa/pilosa/roaring"
+	"golang.org/x/sync/errgroup"
 )
 
 // Ensure a bsiGroup can adjust to its baseValue.
@@ -157,7 +165,7 @@ func TestField_DeleteView(t *testing.T) {
 	f := MustOpenField(OptFieldTypeDefault())
 	defer f.Close()
 
-	viewName := viewStandard + "_v"
+	viewName := viewStandard + "_2019"
 
 	// Create view.
 	view, err := f.createViewIfNotExists(viewName)
@@ -258,7 +266,7 @@ func TestField_CreateViewIfNotExists(t *testing.T) {
 	defer f.Close()
 
 	// Create view.
-	view, err := f.createViewIfNotExists("v")
+	view, err := f.createViewIfNotExists("standard_2019")
 	if err != nil {
 		t.Fatal(err)
 	} else if view == nil {
@@ -266,16 +274,42 @@ func TestField_CreateViewIfNotExists(t *testing.T) {
 	}
 
 	// Retrieve existing view.
-	view2, err := f.createViewIfNotExists("v")
+	view2, err := f.createViewIfNotExists("standard_2019")
 	if err != nil {
 		t.Fatal(err)
 	} else if view != view2 {
 		t.Fatal("view mismatch")
 	}
 
-	if view != f.view("v") {
+	if view != f.view("standard_2019") {
 		t.Fatal("view mismatch")
 	}
+
+	// Names which aren't given to views, such as ones outside the field's
+	// directory, are rejected.
+	for _, name := range []string{"v", "../../escape", "standard_2019/.."} {
+		if _, err := f.createViewIfNotExists(name); err == nil || !strings.Contains(err.Error(), ErrInvalidViewName.Error()) {
+			t.Fatalf("%s: expected invalid view name, got %v", name, err)
+		} else if f.view(name) != nil {
+			t.Fatalf("%s: unexpected view", name)
+		}
+	}
+}
+
+// Ensure views created before view names were validated still open.
+func TestField_OpenInvalidViewName(t *testing.T) {
+	f := MustOpenField(OptFieldTypeDefault())
+	defer f.Close()
+
+	if err := os.MkdirAll(filepath.Join(f.Path(), "views", "v"), 0777); err != nil {
+		t.Fatal(err)
+	} else if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if f.view("v") == nil {
+		t.Fatal("expected view")
+	} else if v, err := f.createViewIfNotExists("v"); err != nil || v != f.view("v") {
+		t.Fatalf("unexpected view: %v, err=%v", v, err)
+	}
 }
 
 func TestField_SetTimeQuantum(t *testing.T) {
@@ -297,6 +331,198 @@ func TestField_SetTimeQuantum(t *testing.T) {
 	}
 }
 
+// Ensure changing the cache size of a field resizes the caches of its
+// fragments, evicting the rows beyond it.
+func TestField_SetCacheSize_Fragments(t *testing.T) {
+	for _, cacheType := range []string{CacheTypeRanked, CacheTypeLRU} {
+		t.Run(cacheType, func(t *testing.T) {
+			f := MustOpenField(OptFieldTypeSet(cacheType, 100))
+			defer f.Close()
+			for row := uint64(0); row < 10; row++ {
+				for col := uint64(0); col <= row; col++ {
+					f.MustSetBit(row, col)
+					f.MustSetBit(row, ShardWidth+col)
+				}
+			}
+			frag := f.view(viewStandard).Fragment(0)
+			frag.RecalculateCache()
+
+			// A TopN already running keeps the rows it read.
+			top, err := frag.top(context.Background(), topOptions{N: 10})
+			if err != nil {
+				t.Fatal(err)
+			} else if len(top) != 10 {
+				t.Fatalf("unexpected top: %v", top)
+			}
+			before := append([]Pair(nil), top...)
+
+			if err := f.SetCacheSize(3); err != nil {
+				t.Fatal(err)
+			}
+			for _, shard := range []uint64{0, 1} {
+				frag := f.view(viewStandard).Fragment(shard)
+				if n := frag.cache.Len(); n != 3 {
+					t.Fatalf("shard %d: unexpected cache size: %d", shard, n)
+				} else if frag.CacheSize != 3 {
+					t.Fatalf("shard %d: unexpected fragment cache size: %d", shard, frag.CacheSize)
+				}
+			}
+			if !reflect.DeepEqual(top, before) {
+				t.Fatalf("top changed: %v", top)
+			}
+
+			// The ranked cache keeps the rows with the most bits, and the
+			// LRU cache the rows most recently written.
+			if top, err := frag.top(context.Background(), topOptions{N: 10}); err != nil {
+				t.Fatal(err)
+			} else if exp := []Pair{{ID: 9, Count: 10}, {ID: 8, Count: 9}, {ID: 7, Count: 8}}; !reflect.DeepEqual(top, exp) {
+				t.Fatalf("unexpected top after resize: %v", top)
+			}
+
+			// Fragments created later use the new size.
+			f.MustSetBit(1, 2*ShardWidth)
+			if frag := f.view(viewStandard).Fragment(2); frag.CacheSize != 3 {
+				t.Fatalf("unexpected new fragment cache size: %d", frag.CacheSize)
+			}
+		})
+	}
+}
+
+// Ensure options persisted in the meta file win over conflicting options the
+// field is opened with.
+func TestField_MetaConflict(t *testing.T) {
+	f := MustOpenField(OptFieldTypeTime(TimeQuantum("YMD")))
+	defer f.Close()
+	if err := f.saveMeta(); err != nil {
+		t.Fatal(err)
+	} else if err := f.Field.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	for _, opt := range []FieldOption{OptFieldTypeDefault(), OptFieldTypeTime(TimeQuantum("YMD")), OptFieldTypeInt(0, 10)} {
+		buf.Reset()
+		fld, err := NewField(f.Path(), "i", "f", opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fld.logger = logger.NewStandardLogger(&buf)
+		if err := fld.Open(); err != nil {
+			t.Fatal(err)
+		}
+		typ, q := fld.Type(), fld.TimeQuantum()
+		if err := fld.Close(); err != nil {
+			t.Fatal(err)
+		} else if typ != FieldTypeTime || q != TimeQuantum("YMD") {
+			t.Fatalf("unexpected options: %s, %s", typ, q)
+		}
+	}
+
+	// Only the int options conflicted with the meta file.
+	if !strings.Contains(buf.String(), "WARNING field options differ from meta file, using meta file: index=i, field=f") {
+		t.Fatalf("expected warning, got: %s", buf.String())
+	}
+}
+
+// Ensure time views older than the field's retention are found and deleted.
+func TestField_Retention(t *testing.T) {
+	f := MustOpenField(func(fo *FieldOptions) error {
+		if err := OptFieldTypeTime(TimeQuantum("DH"))(fo); err != nil {
+			return err
+		}
+		return OptFieldRetention(TimeRetention{Hour: 24 * time.Hour})(fo)
+	})
+	defer f.Close()
+
+	f.MustSetBit(1, 1, time.Date(2018, time.January, 1, 5, 0, 0, 0, time.UTC))
+	f.MustSetBit(1, 2, time.Date(2018, time.January, 2, 5, 0, 0, 0, time.UTC))
+
+	// Retention is persisted.
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if r := f.Options().Retention; r != (TimeRetention{Hour: 24 * time.Hour}) {
+		t.Fatalf("unexpected retention (reopen): %+v", r)
+	}
+
+	now := time.Date(2018, time.January, 2, 8, 0, 0, 0, time.UTC)
+	if views := f.expiredViews(now); !reflect.DeepEqual(views, []string{"standard_2018010105"}) {
+		t.Fatalf("unexpected expired views: %v", views)
+	} else if err := f.deleteExpiredView(views[0]); err != nil {
+		t.Fatal(err)
+	} else if f.view("standard_2018010105") != nil {
+		t.Fatal("expected view to be deleted")
+	} else if f.view("standard_20180101") == nil {
+		t.Fatal("expected day view to be kept")
+	} else if views := f.expiredViews(now); len(views) != 0 {
+		t.Fatalf("unexpected expired views: %v", views)
+	}
+
+	// Deleting a view which no longer exists is not an error.
+	if err := f.deleteExpiredView("standard_2018010105"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitAttrStore records when it was told to commit its writes.
+type commitAttrStore struct {
+	nopAttrStore
+	commit *AttrCommit
+}
+
+func (s commitAttrStore) SetAttrCommit(c AttrCommit) error { *s.commit = c; return nil }
+
+// Ensure a field's attribute commit mode is persisted and set on its row
+// attribute store when the field is opened.
+func TestField_AttrCommit(t *testing.T) {
+	c := AttrCommit{Mode: AttrCommitBatched, Interval: 5 * time.Millisecond}
+	f := NewTestField(func(fo *FieldOptions) error {
+		if err := OptFieldTypeDefault()(fo); err != nil {
+			return err
+		}
+		return OptFieldAttrCommit(c)(fo)
+	})
+	defer f.Close()
+
+	var got AttrCommit
+	f.rowAttrStore = commitAttrStore{commit: &got}
+	if err := f.Open(); err != nil {
+		t.Fatal(err)
+	} else if got != c {
+		t.Fatalf("unexpected store commit: %+v", got)
+	} else if err := f.saveMeta(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Field.Close(); err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewField(f.Path(), "i", "f", OptFieldTypeDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = AttrCommit{}
+	other.rowAttrStore = commitAttrStore{commit: &got}
+	if err := other.Open(); err != nil {
+		t.Fatal(err)
+	}
+	f.Field = other
+	if got != c {
+		t.Fatalf("unexpected store commit (reopen): %+v", got)
+	} else if opt := f.Options().AttrCommit; opt != c {
+		t.Fatalf("unexpected options (reopen): %+v", opt)
+	}
+
+	for _, c := range []AttrCommit{
+		{Mode: "sometimes"},
+		{Mode: AttrCommitAlways, MaxOps: 10},
+		{Mode: AttrCommitBatched, Interval: -time.Second},
+	} {
+		if err := OptFieldAttrCommit(c)(&FieldOptions{}); err == nil {
+			t.Fatalf("expected error: %+v", c)
+		}
+	}
+}
+
 func TestField_RowTime(t *testing.T) {
 	f := MustOpenField(OptFieldTypeTime(TimeQuantum("")))
 	defer f.Close()
@@ -402,3 +628,265 @@ func TestField_PersistAvailableShardsFootprint(t *testing.T) {
 	}
 
 }
+
+// Ensure the write statistics of a field are persisted periodically and
+// when the field is closed.
+func TestField_WriteStats(t *testing.T) {
+	f := MustOpenField(OptFieldTypeDefault())
+	defer f.Close()
+	if ws := f.WriteStats(); ws != nil {
+		t.Fatalf("unexpected write stats: %+v", ws)
+	}
+
+	start := time.Now()
+	check := func(ws FieldWriteStats, set, cleared uint64) {
+		t.Helper()
+		if ws.BitsSet != set || ws.BitsCleared != cleared {
+			t.Fatalf("unexpected write stats: %+v, expected %d set and %d cleared", ws, set, cleared)
+		} else if ws.LastWrite.Before(start) || ws.LastWrite.After(time.Now()) {
+			t.Fatalf("unexpected last write: %s", ws.LastWrite)
+		}
+	}
+
+	// Only writes which change a bit are counted.
+	for i := uint64(0); i < 10; i++ {
+		f.MustSetBit(1, i*ShardWidth/4)
+	}
+	f.MustSetBit(1, 0)
+	if _, err := f.ClearBit(1, 0); err != nil {
+		t.Fatal(err)
+	} else if _, err := f.ClearBit(2, 0); err != nil {
+		t.Fatal(err)
+	}
+	check(*f.WriteStats(), 10, 1)
+
+	// The stats saved periodically are what a restart after a crash sees.
+	persisted := func() FieldWriteStats {
+		t.Helper()
+		other, err := NewField(f.Path(), "i", "f", OptFieldTypeDefault())
+		if err != nil {
+			t.Fatal(err)
+		} else if err := other.loadMeta(); err != nil {
+			t.Fatal(err)
+		}
+		return other.writeStats.value()
+	}
+	if err := f.saveWriteStats(); err != nil {
+		t.Fatal(err)
+	}
+	f.MustSetBit(1, 1)
+	check(persisted(), 10, 1)
+
+	// Closing the field saves the rest.
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check(*f.WriteStats(), 11, 1)
+
+	// Imports count the bits they change.
+	if _, err := f.Import([]uint64{1, 1, 3}, []uint64{1, 2, 3}, nil); err != nil {
+		t.Fatal(err)
+	} else if _, err := f.Import([]uint64{3, 4}, []uint64{3, 4}, nil, OptImportOptionsClear(true)); err != nil {
+		t.Fatal(err)
+	}
+	check(*f.WriteStats(), 13, 2)
+	if err := f.saveWriteStats(); err != nil {
+		t.Fatal(err)
+	}
+	check(persisted(), 13, 2)
+}
+
+// Ensure the write statistics of a field count every bit set concurrently.
+func TestField_WriteStats_Concurrent(t *testing.T) {
+	f := MustOpenField(OptFieldTypeDefault())
+	defer f.Close()
+
+	// Pairs of goroutines set the same bits, which are only counted once.
+	const goroutineN, bitN = 8, 500
+	var wg sync.WaitGroup
+	for g := 0; g < goroutineN; g++ {
+		wg.Add(1)
+		go func(rowID uint64) {
+			defer wg.Done()
+			for i := uint64(0); i < bitN; i++ {
+				if _, err := f.SetBit(rowID, i*ShardWidth/64, nil); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(uint64(g % (goroutineN / 2)))
+	}
+	wg.Wait()
+
+	var n uint64
+	for _, frag := range f.view(viewStandard).allFragments() {
+		n += frag.BitCount()
+	}
+	if ws := f.WriteStats(); ws.BitsSet != goroutineN/2*bitN || ws.BitsSet != n || ws.BitsCleared != 0 {
+		t.Fatalf("unexpected write stats: %+v, %d bits", ws, n)
+	}
+}
+
+// Ensure bits set and imported while idle fragments are closed are written
+// to the fragments' storage, and not lost with their closed bitmaps.
+func TestField_WritesCloseIdleFragments(t *testing.T) {
+	f := MustOpenField(OptFieldTypeDefault())
+	defer f.Close()
+	g := MustOpenField(OptFieldTypeInt(0, 1000))
+	defer g.Close()
+
+	const shardN, writeN = 8, 500
+	done := make(chan struct{})
+	var closer errgroup.Group
+	closer.Go(func() error {
+		for {
+			select {
+			case <-done:
+				return nil
+			default:
+			}
+			for _, v := range append(f.views(), g.views()...) {
+				v.closeIdleFragments(time.Nanosecond, 0)
+			}
+		}
+	})
+
+	var eg errgroup.Group
+	for shard := uint64(0); shard < shardN; shard++ {
+		col := shard * ShardWidth
+		eg.Go(func() error {
+			for i := uint64(0); i < writeN; i++ {
+				if _, err := f.SetBit(1, col+i, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		eg.Go(func() error {
+			for i := uint64(0); i < writeN; i++ {
+				if _, err := f.Import([]uint64{2}, []uint64{col + i}, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		eg.Go(func() error {
+			for i := uint64(0); i < writeN; i++ {
+				if err := g.importValue([]uint64{col + i}, []int64{int64(i)}, &ImportOptions{}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	err := eg.Wait()
+	close(done)
+	if cerr := closer.Wait(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every fragment is read back from its file.
+	for _, v := range append(f.views(), g.views()...) {
+		v.closeIdleFragments(time.Nanosecond, 0)
+	}
+	for _, rowID := range []uint64{1, 2} {
+		if row, err := f.Row(rowID); err != nil {
+			t.Fatal(err)
+		} else if n := row.Count(); n != shardN*writeN {
+			t.Fatalf("unexpected count of row %d: %d", rowID, n)
+		}
+	}
+	for shard := uint64(0); shard < shardN; shard++ {
+		for i := uint64(0); i < writeN; i++ {
+			if value, exists, err := g.Value(shard*ShardWidth + i); err != nil {
+				t.Fatal(err)
+			} else if !exists || value != int64(i) {
+				t.Fatalf("unexpected value of column %d: %d, exists=%v", shard*ShardWidth+i, value, exists)
+			}
+		}
+	}
+}
+
+// BenchmarkField_Import compares setting bits spread across shards one at a
+// time with importing them, which groups them by fragment.
+func BenchmarkField_Import(b *testing.B) {
+	const n, shards = 1 << 16, 8
+	rowIDs, columnIDs := make([]uint64, n), make([]uint64, n)
+	for i := range rowIDs {
+		rowIDs[i] = uint64(i % 100)
+		columnIDs[i] = uint64(i%shards)*ShardWidth + uint64(i/shards)
+	}
+
+	b.Run("SetBit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			f := MustOpenField(OptFieldTypeDefault())
+			b.StartTimer()
+			for j := range rowIDs {
+				if _, err := f.SetBit(rowIDs[j], columnIDs[j], nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			f.Close()
+		}
+	})
+
+	b.Run("Import", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			f := MustOpenField(OptFieldTypeDefault())
+			b.StartTimer()
+			if _, err := f.Import(rowIDs, columnIDs, nil); err != nil {
+				b.Fatal(err)
+			}
+			b.StopTimer()
+			f.Close()
+		}
+	})
+}
+
+// BenchmarkField_SetCacheSize shrinks the cache of a field with 1M distinct
+// rows to the default size, and reports the heap in use before and after.
+func BenchmarkField_SetCacheSize(b *testing.B) {
+	const n = 1000000
+	rowIDs, columnIDs := make([]uint64, n), make([]uint64, n)
+	for i := range rowIDs {
+		rowIDs[i] = uint64(i)
+		columnIDs[i] = uint64(i) % ShardWidth
+	}
+
+	heapInUse := func() float64 {
+		var m runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&m)
+		return float64(m.HeapAlloc)
+	}
+
+	for _, cacheType := range []string{CacheTypeRanked, CacheTypeLRU} {
+		b.Run(cacheType, func(b *testing.B) {
+			var before, after float64
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				f := MustOpenField(OptFieldTypeSet(cacheType, n))
+				if _, err := f.Import(rowIDs, columnIDs, nil); err != nil {
+					b.Fatal(err)
+				}
+				f.view(viewStandard).Fragment(0).RecalculateCache()
+				before = heapInUse()
+				b.StartTimer()
+				if err := f.SetCacheSize(DefaultCacheSize); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				after = heapInUse()
+				f.Close()
+			}
+			b.ReportMetric(before/(1<<20), "MB-before")
+			b.ReportMetric(after/(1<<20), "MB-after")
+		})
+	}
+}
@@ -16,7 +16,9 @@ package pilosa_test
 
 import (
 	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pilosa/pilosa"
@@ -223,3 +225,46 @@ func TestField_AvailableShards(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+// Ensure a row can be cleared in every view of a field.
+func TestField_ClearRow(t *testing.T) {
+	idx := test.MustOpenIndex()
+	defer idx.Close()
+
+	f, err := idx.CreateField("f", pilosa.OptFieldTypeTime(pilosa.TimeQuantum("YM")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, col := range []uint64{1, 2 * ShardWidth} {
+		if _, err := f.SetBit(1, col, &ts); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.SetBit(2, col, &ts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if changed, err := f.ClearRow(1); err != nil {
+		t.Fatal(err)
+	} else if !changed {
+		t.Fatal("expected change")
+	}
+	for _, quantum := range []string{"Y", "M"} {
+		if row, err := f.RowTime(1, ts, quantum); err != nil {
+			t.Fatal(err)
+		} else if n := row.Count(); n != 0 {
+			t.Fatalf("unexpected count in %s view: %d", quantum, n)
+		}
+	}
+	if row, err := f.RowTime(2, ts, "M"); err != nil {
+		t.Fatal(err)
+	} else if diff := cmp.Diff(row.Columns(), []uint64{1, 2 * ShardWidth}); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if g, err := idx.CreateField("g", pilosa.OptFieldTypeInt(0, 10)); err != nil {
+		t.Fatal(err)
+	} else if _, err := g.ClearRow(1); err == nil || !strings.Contains(err.Error(), "row can't be cleared on a int field") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
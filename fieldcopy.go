@@ -0,0 +1,627 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pilosa/pilosa/roaring"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// fieldCopyFile is the name of the file in a field's directory which holds
+// the state of the copy into the field until the field is ready.
+const fieldCopyFile = ".fieldcopy"
+
+// FieldCopy is a job which copies the fragments and row attributes of a
+// field on this node into a new field, of the same index or of another. The
+// shards of another index have other owners, so a copy between indexes copies
+// the shards this node owns in the destination instead, fetching those it
+// doesn't own in the source from their owners. Copying unions into the destination, so an interrupted copy is resumed by
+// copying everything again. The destination can't be queried or imported
+// into until it is marked ready, which is done once the copy has finished on
+// every node.
+type FieldCopy struct {
+	// ID identifies the copy. A copy run on every node of a cluster has the
+	// same ID on each of them.
+	ID string `json:"id"`
+
+	Index       string `json:"index"`
+	Field       string `json:"field"`
+	SourceIndex string `json:"sourceIndex"`
+	Source      string `json:"source"`
+
+	// Options overrides the options of the source for the destination.
+	Options FieldCopyOptions `json:"options"`
+
+	// The number of local fragments to copy and the number copied so far.
+	Fragments int `json:"fragments"`
+	Copied    int `json:"copied"`
+
+	Done  bool   `json:"done"`
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+
+	// The destination, so that a copy isn't mistaken for one into a field
+	// of the same name created after the destination is deleted.
+	dst *Field
+}
+
+// FieldCopyRequest describes a copy to start.
+type FieldCopyRequest struct {
+	// ID of the copy. A new one is generated if it is empty.
+	ID string
+
+	// The destination, and the field to copy into it. The source index
+	// defaults to the destination's, which is created with the options of
+	// the source index if it doesn't exist.
+	Index       string
+	Field       string
+	SourceIndex string
+	Source      string
+
+	Options FieldCopyOptions
+
+	// Overwrite replaces an existing destination field with the copy.
+	// Otherwise copying into an existing field is an error.
+	Overwrite bool
+}
+
+// FieldCopyOptions lists the options of a copy's destination which can differ
+// from those of its source. Nil options are the same as the source's.
+type FieldCopyOptions struct {
+	CacheType *string `json:"cacheType,omitempty"`
+	CacheSize *uint32 `json:"cacheSize,omitempty"`
+}
+
+// apply validates o and applies it to the options of a source field.
+func (o FieldCopyOptions) apply(fo *FieldOptions) error {
+	if o.CacheType == nil && o.CacheSize == nil {
+		return nil
+	} else if fo.Type != FieldTypeSet && fo.Type != FieldTypeMutex {
+		return errors.Errorf("cache options can only be changed for set and mutex fields")
+	}
+	if o.CacheType != nil {
+		if !isValidCacheType(*o.CacheType) {
+			return ErrInvalidCacheType
+		}
+		fo.CacheType = *o.CacheType
+	}
+	if o.CacheSize != nil {
+		fo.CacheSize = *o.CacheSize
+	}
+	return nil
+}
+
+// copy returns a copy of c.
+func (c *FieldCopy) copy() *FieldCopy {
+	other := *c
+	return &other
+}
+
+// fieldCopies tracks the copies into the fields of a holder.
+type fieldCopies struct {
+	mu sync.Mutex
+
+	// Copies by index and destination field name.
+	m map[string]*FieldCopy
+}
+
+func newFieldCopies() *fieldCopies {
+	return &fieldCopies{m: make(map[string]*FieldCopy)}
+}
+
+// StartFieldCopy creates the destination field of req with the options of
+// its source and req.Options, and starts copying the local data of the
+// source into it.
+func (h *Holder) StartFieldCopy(req FieldCopyRequest) (*FieldCopy, error) {
+	if req.SourceIndex == "" {
+		req.SourceIndex = req.Index
+	}
+	srcIndex := h.Index(req.SourceIndex)
+	if srcIndex == nil {
+		return nil, ErrIndexNotFound
+	}
+	src := srcIndex.Field(req.Source)
+	if src == nil {
+		return nil, ErrFieldNotFound
+	} else if h.fieldCopying(req.SourceIndex, req.Source) {
+		return nil, ErrFieldCopying
+	} else if src.keys() {
+		return nil, errors.New("fields with keys can't be copied")
+	} else if req.SourceIndex == req.Index && req.Source == req.Field {
+		return nil, errors.New("a field can't be copied into itself")
+	} else if err := ValidateFieldName(req.Field); err != nil {
+		return nil, err
+	}
+
+	fo := src.Options()
+	if err := req.Options.apply(&fo); err != nil {
+		return nil, err
+	}
+
+	// Columns of indexes with keys are translated by their index, so their
+	// bits only mean the same in another index with the same translation.
+	idx := h.Index(req.Index)
+	if req.Index != req.SourceIndex {
+		if srcIndex.Keys() || (idx != nil && idx.Keys()) {
+			return nil, errors.New("fields can't be copied between indexes with keys")
+		} else if idx != nil && idx.ShardWidth() != srcIndex.ShardWidth() {
+			return nil, errors.New("fields can't be copied between indexes with different shard widths")
+		} else if idx == nil {
+			var err error
+			if idx, err = h.CreateIndexIfNotExists(req.Index, srcIndex.Options()); err != nil {
+				return nil, errors.Wrap(err, "creating index")
+			}
+		}
+	} else if idx == nil {
+		return nil, ErrIndexNotFound
+	}
+
+	if req.ID == "" {
+		req.ID = uuid.NewV4().String()
+	}
+
+	// An existing destination is only replaced if asked to, and never while
+	// it is used by another copy.
+	if f := idx.Field(req.Field); f != nil {
+		if h.copyingInto(f) || h.copyingFrom(f) {
+			return nil, newConflictError(ErrFieldCopying)
+		} else if !req.Overwrite {
+			return nil, newConflictError(ErrFieldExists)
+		}
+	}
+
+	h.fieldCopies.mu.Lock()
+	defer h.fieldCopies.mu.Unlock()
+
+	for _, c := range h.fieldCopies.m {
+		if c.ID == req.ID && (c.Index != req.Index || c.Field != req.Field) {
+			return nil, newConflictError(errors.Errorf("field copy ID already used: %s", req.ID))
+		}
+	}
+	if req.Overwrite && idx.Field(req.Field) != nil {
+		if err := idx.DeleteField(req.Field); err != nil {
+			return nil, errors.Wrap(err, "deleting destination")
+		}
+	}
+
+	dst, err := idx.CreateField(req.Field, func(o *FieldOptions) error { *o = fo; return nil })
+	if err != nil {
+		return nil, err
+	}
+	c := &FieldCopy{
+		ID:          req.ID,
+		Index:       req.Index,
+		Field:       req.Field,
+		SourceIndex: req.SourceIndex,
+		Source:      req.Source,
+		Options:     req.Options,
+		dst:         dst,
+	}
+	if err := saveFieldCopy(dst, c); err != nil {
+		return nil, errors.Wrap(err, "saving field copy")
+	}
+	h.fieldCopies.m[req.Index+"/"+req.Field] = c
+	h.runFieldCopy(src, dst, c)
+	return c.copy(), nil
+}
+
+// FieldCopy returns the state of the copy into a field, or nil if the field
+// hasn't been copied into since the holder was opened.
+func (h *Holder) FieldCopy(index, field string) *FieldCopy {
+	f := h.Field(index, field)
+
+	h.fieldCopies.mu.Lock()
+	defer h.fieldCopies.mu.Unlock()
+	if c := h.fieldCopies.m[index+"/"+field]; c != nil && c.dst == f {
+		return c.copy()
+	}
+	return nil
+}
+
+// FieldCopyByID returns the state of the copy with the given ID, or nil if
+// there is no such copy into a field which still exists.
+func (h *Holder) FieldCopyByID(id string) *FieldCopy {
+	if id == "" {
+		return nil
+	}
+
+	var c *FieldCopy
+	h.fieldCopies.mu.Lock()
+	for _, fc := range h.fieldCopies.m {
+		if fc.ID == id {
+			c = fc.copy()
+			break
+		}
+	}
+	h.fieldCopies.mu.Unlock()
+
+	if c == nil || h.Field(c.Index, c.Field) != c.dst {
+		return nil
+	}
+	return c
+}
+
+// FinishFieldCopy marks the destination of a finished copy ready, so it can
+// be queried and imported into, and announces the shards this node holds in
+// it to the cluster. Shards announced as they were copied are missed by
+// nodes which hadn't yet created the destination.
+func (h *Holder) FinishFieldCopy(index, field string) (*FieldCopy, error) {
+	f := h.Field(index, field)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+
+	h.fieldCopies.mu.Lock()
+	c := h.fieldCopies.m[index+"/"+field]
+	if c == nil || c.dst != f {
+		h.fieldCopies.mu.Unlock()
+		return nil, ErrFieldCopyNotFound
+	} else if !c.Done {
+		h.fieldCopies.mu.Unlock()
+		return nil, errors.New("field copy hasn't finished")
+	} else if c.Error != "" {
+		h.fieldCopies.mu.Unlock()
+		return nil, errors.Errorf("field copy failed: %s", c.Error)
+	}
+	if err := os.Remove(filepath.Join(f.Path(), fieldCopyFile)); err != nil && !os.IsNotExist(err) {
+		h.fieldCopies.mu.Unlock()
+		return nil, errors.Wrap(err, "removing field copy")
+	}
+	c.Ready = true
+	other := c.copy()
+	h.fieldCopies.mu.Unlock()
+
+	shards := roaring.NewBitmap()
+	for _, v := range f.views() {
+		shards.UnionInPlace(v.availableShards())
+	}
+	var err error
+	shards.ForEach(func(shard uint64) {
+		if err == nil {
+			err = h.broadcaster.SendSync(&CreateShardMessage{Index: index, Field: field, Shard: shard})
+		}
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "announcing shards")
+	}
+	return other, nil
+}
+
+// fieldCopying returns true if a field is the destination of a copy which
+// isn't ready.
+func (h *Holder) fieldCopying(index, field string) bool {
+	h.fieldCopies.mu.Lock()
+	c := h.fieldCopies.m[index+"/"+field]
+	if c == nil || c.Ready {
+		h.fieldCopies.mu.Unlock()
+		return false
+	}
+	dst := c.dst
+	h.fieldCopies.mu.Unlock()
+	return h.Field(index, field) == dst
+}
+
+// copyingInto returns true if f is the destination of a copy which isn't
+// ready. Unlike fieldCopying, it doesn't take the holder lock.
+func (h *Holder) copyingInto(f *Field) bool {
+	h.fieldCopies.mu.Lock()
+	defer h.fieldCopies.mu.Unlock()
+	c := h.fieldCopies.m[f.Index()+"/"+f.Name()]
+	return c != nil && !c.Ready && c.dst == f
+}
+
+// copyingFrom returns true if f is the source of a copy which isn't done.
+func (h *Holder) copyingFrom(f *Field) bool {
+	h.fieldCopies.mu.Lock()
+	defer h.fieldCopies.mu.Unlock()
+	for _, c := range h.fieldCopies.m {
+		if c.SourceIndex == f.Index() && c.Source == f.Name() && !c.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeFieldCopies restarts the unfinished copies into every field, and
+// keeps the destinations of finished ones which aren't ready from being
+// used.
+func (h *Holder) resumeFieldCopies() {
+	for _, index := range h.Indexes() {
+		for _, f := range index.Fields() {
+			c, err := loadFieldCopy(f)
+			if err != nil {
+				h.Logger.Printf("loading field copy: index=%s, field=%s, err=%s", f.Index(), f.Name(), err)
+				continue
+			} else if c == nil {
+				continue
+			}
+			c.dst = f
+			src := h.Field(c.SourceIndex, c.Source)
+
+			h.fieldCopies.mu.Lock()
+			h.fieldCopies.m[f.Index()+"/"+f.Name()] = c
+			if !c.Done && src != nil {
+				h.Logger.Printf("resuming field copy: index=%s, field=%s, source=%s/%s", f.Index(), f.Name(), c.SourceIndex, c.Source)
+				h.runFieldCopy(src, f, c)
+			} else if !c.Done {
+				c.Done, c.Error = true, ErrFieldNotFound.Error()
+			}
+			h.fieldCopies.mu.Unlock()
+		}
+	}
+}
+
+// fieldCopyShard is a fragment of the source of a copy, by view and shard.
+type fieldCopyShard struct {
+	view  string
+	shard uint64
+}
+
+// shardFetcher locates and fetches the shards of a copy between indexes.
+// It's implemented by the cluster.
+type shardFetcher interface {
+	// localShard returns true if this node owns a shard of an index.
+	localShard(index string, shard uint64) bool
+
+	// fetchFragment returns the data file of a fragment from another owner
+	// of its shard, or nil if the owners don't have the fragment.
+	fetchFragment(index, field, view string, shard uint64) ([]byte, error)
+}
+
+// fieldCopyShards returns the fragments of src to copy into dst on this
+// node, in order: its own fragments for a copy within an index, or every
+// fragment of the shards this node owns in the other index.
+func (h *Holder) fieldCopyShards(src, dst *Field) []fieldCopyShard {
+	views := src.views()
+	sort.Slice(views, func(i, j int) bool { return views[i].name < views[j].name })
+
+	var shards []fieldCopyShard
+	for _, v := range views {
+		if src.Index() == dst.Index() || h.shardFetcher == nil {
+			frags := v.allFragments()
+			sort.Slice(frags, func(i, j int) bool { return frags[i].shard < frags[j].shard })
+			for _, frag := range frags {
+				shards = append(shards, fieldCopyShard{view: v.name, shard: frag.shard})
+			}
+			continue
+		}
+		src.AvailableShards().ForEach(func(shard uint64) {
+			if h.shardFetcher.localShard(dst.Index(), shard) {
+				shards = append(shards, fieldCopyShard{view: v.name, shard: shard})
+			}
+		})
+	}
+	return shards
+}
+
+// runFieldCopy copies src into dst in the background. The state of the copy
+// is saved when it finishes. The caller must hold the fieldCopies lock.
+func (h *Holder) runFieldCopy(src, dst *Field, c *FieldCopy) {
+	shards := h.fieldCopyShards(src, dst)
+	c.Fragments, c.Copied = len(shards), 0
+
+	// Every view of the source is created, so that nodes without any of its
+	// fragments have the same views as the others.
+	for _, v := range src.views() {
+		if _, _, err := dst.createViewIfNotExistsBase(v.name); err != nil {
+			h.Logger.Printf("creating field copy view: index=%s, field=%s, view=%s, err=%s", c.Index, c.Field, v.name, err)
+		}
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		err := h.copyField(src, dst, c, shards)
+		if err == errFieldCopyInterrupted {
+			return
+		}
+
+		h.fieldCopies.mu.Lock()
+		defer h.fieldCopies.mu.Unlock()
+		c.Done = true
+		if err != nil {
+			h.Logger.Printf("field copy error: index=%s, field=%s, source=%s/%s, err=%s", c.Index, c.Field, c.SourceIndex, c.Source, err)
+			c.Error = err.Error()
+		}
+		if err := saveFieldCopy(dst, c); err != nil {
+			h.Logger.Printf("saving field copy: index=%s, field=%s, err=%s", c.Index, c.Field, err)
+		}
+	}()
+}
+
+// errFieldCopyInterrupted is returned when the holder closes during a field
+// copy.
+var errFieldCopyInterrupted = errors.New("field copy interrupted")
+
+// copyField copies the row attributes of src into dst, then each of the
+// fragments in shards.
+func (h *Holder) copyField(src, dst *Field, c *FieldCopy, shards []fieldCopyShard) error {
+	if err := copyAttrStore(src.RowAttrStore(), dst.RowAttrStore()); err != nil {
+		return errors.Wrap(err, "copying row attributes")
+	}
+
+	for _, s := range shards {
+		select {
+		case <-h.closing:
+			return errFieldCopyInterrupted
+		default:
+		}
+
+		if h.Field(c.SourceIndex, c.Source) != src || h.Field(c.Index, c.Field) != dst {
+			return ErrFieldNotFound
+		} else if err := h.copyFragment(src, dst, s); err != nil {
+			return errors.Wrapf(err, "copying view %s, shard %d", s.view, s.shard)
+		}
+
+		h.fieldCopies.mu.Lock()
+		c.Copied++
+		h.fieldCopies.mu.Unlock()
+	}
+	h.Stats.Count("fieldCopy", 1, 1.0)
+	return nil
+}
+
+// copyFragment unions a fragment of src into the same shard of the same view
+// of dst. A fragment of a shard this node doesn't own in the source is
+// fetched from its owners. Missing fragments are skipped.
+//
+// The views of dst aren't announced to the cluster, as the other nodes may
+// not have created dst yet; every node creates them as it copies.
+func (h *Holder) copyFragment(src, dst *Field, s fieldCopyShard) error {
+	var data []byte
+	if src.Index() == dst.Index() || h.shardFetcher == nil || h.shardFetcher.localShard(src.Index(), s.shard) {
+		frag, err := h.acquireFragment(src.Index(), src.Name(), s.view, s.shard)
+		if err != nil {
+			return errors.Wrap(err, "acquiring fragment")
+		} else if frag == nil {
+			return nil
+		}
+		data, err = frag.marshalStorage()
+		frag.release()
+		if err != nil {
+			return errors.Wrap(err, "reading fragment")
+		}
+	} else {
+		var err error
+		if data, err = h.shardFetcher.fetchFragment(src.Index(), src.Name(), s.view, s.shard); err != nil {
+			return errors.Wrap(err, "fetching fragment")
+		} else if data == nil {
+			return nil
+		}
+	}
+
+	view, _, err := dst.createViewIfNotExistsBase(s.view)
+	if err != nil {
+		return errors.Wrap(err, "creating view")
+	}
+	target, err := view.acquireFragmentIfNotExists(s.shard)
+	if err != nil {
+		return errors.Wrap(err, "creating fragment")
+	}
+	defer target.release()
+	return errors.Wrap(target.importRoaring(data, false), "importing fragment")
+}
+
+// copyAttrStore copies every attribute of src into dst.
+func copyAttrStore(src, dst AttrStore) error {
+	blks, err := src.Blocks()
+	if err != nil {
+		return errors.Wrap(err, "reading blocks")
+	}
+	for _, blk := range blks {
+		m, err := src.BlockData(blk.ID)
+		if err != nil {
+			return errors.Wrapf(err, "reading block %d", blk.ID)
+		} else if err := dst.SetBulkAttrs(m); err != nil {
+			return errors.Wrapf(err, "writing block %d", blk.ID)
+		}
+	}
+	return nil
+}
+
+// loadFieldCopy reads the state of the copy into f, if it isn't ready.
+func loadFieldCopy(f *Field) (*FieldCopy, error) {
+	buf, err := ioutil.ReadFile(filepath.Join(f.Path(), fieldCopyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var c FieldCopy
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling")
+	}
+
+	// Copies saved before copies between indexes were from the same index.
+	if c.SourceIndex == "" {
+		c.SourceIndex = c.Index
+	}
+	return &c, nil
+}
+
+// saveFieldCopy writes the state of c to the directory of f.
+func saveFieldCopy(f *Field, c *FieldCopy) error {
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "marshaling")
+	}
+	return writeMetaFile(filepath.Join(f.Path(), fieldCopyFile), buf, 0666)
+}
+
+// localShard returns true if this node owns a shard of an index.
+func (c *cluster) localShard(index string, shard uint64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ownsShard(c.Node.ID, index, shard)
+}
+
+// fetchFragment returns the data file of a fragment from the first of the
+// other owners of its shard which has it, or nil if none of them do.
+func (c *cluster) fetchFragment(index, field, view string, shard uint64) ([]byte, error) {
+	c.mu.RLock()
+	nodes := c.shardNodes(index, shard)
+	c.mu.RUnlock()
+
+	var err error
+	for _, node := range nodes {
+		if node.ID == c.Node.ID {
+			continue
+		}
+		var data []byte
+		if data, err = c.fetchFragmentFrom(node, index, field, view, shard); err == nil {
+			return data, nil
+		} else if err == ErrFragmentNotFound {
+			err = nil
+			continue
+		}
+		c.logger.Printf("fetching fragment from %s: index=%s, field=%s, view=%s, shard=%d, err=%s", node.ID, index, field, view, shard, err)
+	}
+	return nil, err
+}
+
+// fetchFragmentFrom returns the data file of a fragment from the archive of
+// it sent by node.
+func (c *cluster) fetchFragmentFrom(node *Node, index, field, view string, shard uint64) ([]byte, error) {
+	rd, err := c.InternalClient.RetrieveShardFromURI(context.Background(), index, field, view, shard, node.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.New("no data in fragment archive")
+		} else if err != nil {
+			return nil, errors.Wrap(err, "reading archive")
+		} else if hdr.Name == "data" {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
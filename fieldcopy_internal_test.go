@@ -0,0 +1,264 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHolder_FieldCopy(t *testing.T) {
+	// newField returns a set field with bits in two shards.
+	newField := func(t *testing.T, h *tHolder) *Field {
+		f, err := h.MustCreateIndexIfNotExists("i", IndexOptions{}).CreateFieldIfNotExists("f", OptFieldTypeSet(CacheTypeRanked, 100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, col := range []uint64{1, 2, ShardWidth + 3} {
+			if _, err := f.SetBit(10, col, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return f
+	}
+
+	t.Run("Copy", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		newField(t, h)
+
+		cacheType := CacheTypeLRU
+		c, err := h.StartFieldCopy(FieldCopyRequest{Index: "i", Field: "g", Source: "f", Options: FieldCopyOptions{CacheType: &cacheType}})
+		if err != nil {
+			t.Fatal(err)
+		} else if c.Fragments != 2 {
+			t.Fatalf("unexpected fragments: %d", c.Fragments)
+		}
+
+		c = mustWaitFieldCopy(t, h, "i", "g")
+		if c.Error != "" {
+			t.Fatal(c.Error)
+		} else if c.Copied != 2 || c.Ready || c.SourceIndex != "i" {
+			t.Fatalf("unexpected copy: %+v", c)
+		} else if other := h.FieldCopyByID(c.ID); other == nil || *other != *c {
+			t.Fatalf("unexpected copy by ID %q: %+v", c.ID, other)
+		} else if !h.fieldCopying("i", "g") {
+			t.Fatal("expected destination not to be ready")
+		}
+
+		g := h.Field("i", "g")
+		if opt := g.Options(); opt.CacheType != CacheTypeLRU || opt.CacheSize != 100 {
+			t.Fatalf("unexpected cache: %s %d", opt.CacheType, opt.CacheSize)
+		} else if row, err := g.Row(10); err != nil {
+			t.Fatal(err)
+		} else if cols := row.Columns(); !reflect.DeepEqual(cols, []uint64{1, 2, ShardWidth + 3}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+
+		if c, err := h.FinishFieldCopy("i", "g"); err != nil {
+			t.Fatal(err)
+		} else if !c.Ready || h.fieldCopying("i", "g") {
+			t.Fatalf("expected destination to be ready: %+v", c)
+		} else if _, err := os.Stat(filepath.Join(g.Path(), fieldCopyFile)); !os.IsNotExist(err) {
+			t.Fatalf("expected state file to be removed: %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		newField(t, h)
+		idx := h.Index("i")
+		if _, err := idx.CreateField("k", OptFieldKeys()); err != nil {
+			t.Fatal(err)
+		} else if _, err := idx.CreateField("n", OptFieldTypeInt(0, 10)); err != nil {
+			t.Fatal(err)
+		}
+		h.MustCreateIndexIfNotExists("ik", IndexOptions{Keys: true})
+
+		cacheType, cacheSize := "nope", uint32(10)
+		for _, tt := range []struct {
+			index, source, dest string
+			opt                 FieldCopyOptions
+			err                 string
+		}{
+			{source: "nope", dest: "g", err: ErrFieldNotFound.Error()},
+			{source: "f", dest: "n", err: ErrFieldExists.Error()},
+			{source: "f", dest: "f", err: "can't be copied into itself"},
+			{source: "k", dest: "g", err: "fields with keys can't be copied"},
+			{source: "n", dest: "g", opt: FieldCopyOptions{CacheSize: &cacheSize}, err: "cache options can only be changed"},
+			{source: "f", dest: "g", opt: FieldCopyOptions{CacheType: &cacheType}, err: ErrInvalidCacheType.Error()},
+			{source: "f", dest: "_g", err: "invalid name"},
+			{index: "ik", source: "f", dest: "g", err: "between indexes with keys"},
+		} {
+			req := FieldCopyRequest{Index: tt.index, Field: tt.dest, SourceIndex: "i", Source: tt.source, Options: tt.opt}
+			if req.Index == "" {
+				req.Index = "i"
+			}
+			if _, err := h.StartFieldCopy(req); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s to %s/%s: expected %q, got %v", tt.source, req.Index, tt.dest, tt.err, err)
+			}
+		}
+		if f := h.Field("i", "g"); f != nil {
+			t.Fatal("unexpected destination")
+		} else if f := h.Field("ik", "g"); f != nil {
+			t.Fatal("unexpected destination")
+		} else if _, err := h.FinishFieldCopy("i", "f"); err != ErrFieldCopyNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("OtherIndex", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		h.MustCreateIndexIfNotExists("i", IndexOptions{TrackExistence: true})
+		newField(t, h)
+
+		// The destination index is created with the options of the source's.
+		c, err := h.StartFieldCopy(FieldCopyRequest{ID: "scratch", Index: "j", Field: "g", SourceIndex: "i", Source: "f"})
+		if err != nil {
+			t.Fatal(err)
+		} else if c.ID != "scratch" {
+			t.Fatalf("unexpected ID: %s", c.ID)
+		} else if opt := h.Index("j").Options(); opt != h.Index("i").Options() {
+			t.Fatalf("unexpected index options: %+v", opt)
+		}
+
+		c = mustWaitFieldCopy(t, h, "j", "g")
+		if c.Error != "" {
+			t.Fatal(c.Error)
+		} else if c.Copied != 2 || c.Index != "j" || c.SourceIndex != "i" {
+			t.Fatalf("unexpected copy: %+v", c)
+		} else if h.FieldCopyByID("scratch") == nil {
+			t.Fatal("expected copy by ID")
+		} else if cols := h.Row("j", "g", 10).Columns(); !reflect.DeepEqual(cols, []uint64{1, 2, ShardWidth + 3}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		newField(t, h)
+		g, err := h.MustCreateIndexIfNotExists("j", IndexOptions{}).CreateField("g", OptFieldTypeInt(0, 10))
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err := g.SetValue(100, 5); err != nil {
+			t.Fatal(err)
+		}
+
+		req := FieldCopyRequest{Index: "j", Field: "g", SourceIndex: "i", Source: "f"}
+		if _, err := h.StartFieldCopy(req); err == nil || !strings.Contains(err.Error(), ErrFieldExists.Error()) {
+			t.Fatalf("expected field exists, got %v", err)
+		} else if h.Field("j", "g") != g {
+			t.Fatal("expected destination to be kept")
+		}
+
+		req.Overwrite = true
+		c, err := h.StartFieldCopy(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// While the copy runs, nothing else can replace its destination.
+		if _, err := h.StartFieldCopy(req); err == nil || !strings.Contains(err.Error(), ErrFieldCopying.Error()) {
+			t.Fatalf("expected field copying, got %v", err)
+		}
+
+		c = mustWaitFieldCopy(t, h, "j", "g")
+		if c.Error != "" {
+			t.Fatal(c.Error)
+		}
+		g = h.Field("j", "g")
+		if typ := g.Type(); typ != FieldTypeSet {
+			t.Fatalf("unexpected type: %s", typ)
+		} else if cols := h.Row("j", "g", 10).Columns(); !reflect.DeepEqual(cols, []uint64{1, 2, ShardWidth + 3}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		} else if cols := h.Row("j", "g", 0).Columns(); len(cols) != 0 {
+			t.Fatalf("unexpected columns of the replaced field: %v", cols)
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		newField(t, h)
+
+		// Save an unfinished copy into an empty destination, as if the holder
+		// was closed while it was running.
+		g, err := h.Index("i").CreateField("g", OptFieldTypeSet(CacheTypeRanked, 100))
+		if err != nil {
+			t.Fatal(err)
+		} else if err := saveFieldCopy(g, &FieldCopy{Index: "i", Field: "g", Source: "f"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		} else if err := h.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+
+		c := mustWaitFieldCopy(t, h, "i", "g")
+		if c.Error != "" {
+			t.Fatal(c.Error)
+		} else if cols := h.Row("i", "g", 10).Columns(); !reflect.DeepEqual(cols, []uint64{1, 2, ShardWidth + 3}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+
+		// A finished copy stays unready across a restart.
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		} else if err := h.Reopen(); err != nil {
+			t.Fatal(err)
+		} else if !h.fieldCopying("i", "g") {
+			t.Fatal("expected destination not to be ready")
+		} else if _, err := h.FinishFieldCopy("i", "g"); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// mustWaitFieldCopy waits for the copy into a field to finish.
+func mustWaitFieldCopy(tb testing.TB, h *tHolder, index, field string) *FieldCopy {
+	tb.Helper()
+	for i := 0; i < 1000; i++ {
+		if c := h.FieldCopy(index, field); c != nil && c.Done {
+			return c
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("copy into %s/%s didn't finish", index, field)
+	return nil
+}
@@ -26,10 +26,12 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
+	"math/bits"
 	"os"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
@@ -46,7 +48,8 @@ import (
 )
 
 const (
-	// ShardWidth is the number of column IDs in a shard. It must be a power of 2 greater than or equal to 16.
+	// ShardWidth is the number of column IDs in a shard of an index created
+	// without a shard width. It must be a power of 2 greater than or equal to 16.
 	shardWidthExponent = 20
 	ShardWidth         = 1 << shardWidthExponent
 
@@ -60,6 +63,10 @@ const (
 	// exponent.
 	shardVsContainerExponent = shardWidthExponent - 16
 
+	// MinShardWidth and MaxShardWidth bound the shard width of an index.
+	MinShardWidth = containerWidth
+	MaxShardWidth = 1 << 32
+
 	// width of roaring containers is 2^16
 	containerWidth = 1 << 16
 
@@ -72,12 +79,48 @@ const (
 	// cacheExt is the file extension for persisted cache ids.
 	cacheExt = ".cache"
 
+	// cacheFileMagic is the prefix of a versioned cache file. Cache files
+	// written before versioning was introduced contain only the protobuf
+	// encoded ids and are never trusted.
+	cacheFileMagic = "PLCACHE"
+
+	// cacheFileVersion is the current version of the cache file format.
+	cacheFileVersion = 1
+
+	// cacheFileHeaderSize is the size of the cache file header: the magic,
+	// a one byte version, and the 8 byte storage checksum.
+	cacheFileHeaderSize = len(cacheFileMagic) + 1 + 8
+
 	// HashBlockSize is the number of rows in a merkle hash block.
 	HashBlockSize = 100
 
+	// cacheWarmBatchSize is the number of rows counted into the cache per
+	// acquisition of the fragment lock by a background rebuild.
+	cacheWarmBatchSize = 1000
+
+	// defaultCacheDeferThreshold is the default number of single bit writes
+	// per second above which a fragment defers rank cache maintenance.
+	defaultCacheDeferThreshold = 1000
+
+	// topAttrBatchSize is the number of rows whose attributes are read at
+	// once when the top rows are filtered by an attribute.
+	topAttrBatchSize = 100
+
+	// forEachBitBatchSize is the number of bits read at a time, under the
+	// fragment's lock, when iterating over the bits of a fragment.
+	forEachBitBatchSize = 4096
+
+	// queryCheckRowN is the number of rows a query scans between checks
+	// of whether it was cancelled or timed out.
+	queryCheckRowN = 64
+
 	// defaultFragmentMaxOpN is the default value for Fragment.MaxOpN.
 	defaultFragmentMaxOpN = 10000
 
+	// defaultFragmentCloseTimeout is the default value for
+	// Fragment.CloseTimeout.
+	defaultFragmentCloseTimeout = 10 * time.Second
+
 	// Row ids used for boolean fields.
 	falseRowID = uint64(0)
 	trueRowID  = uint64(1)
@@ -93,6 +136,9 @@ type fragment struct {
 	view  string
 	shard uint64
 
+	// Number of column IDs in the shard, a power of 2 set by the index.
+	shardWidth uint64
+
 	// File-backed storage
 	path        string
 	file        *os.File
@@ -100,14 +146,78 @@ type fragment struct {
 	storageData []byte
 	opN         int // number of ops since snapshot
 
+	// Hash of the data file, hashed when the storage is opened and then
+	// kept up to date as ops are appended to it, so that the cache file can
+	// be tied to the storage without rehashing it. Nil if an append failed.
+	storageHash hash.Hash64
+
+	// Number of bits set in the storage, and of rows with any bits set.
+	// They are kept up to date by the writes to the op log, and rebuilt from
+	// the cardinalities of the containers whenever the storage is opened,
+	// which writes replacing it, such as snapshots and roaring imports, do.
+	bitN, rowN uint64
+
+	// Time of the last snapshot written by this process, zero if none.
+	snapshotAt time.Time
+
 	// Cache for row counts.
 	CacheType string // passed in by field
 	cache     cache
 	CacheSize uint32
 
+	// Tracks background rebuilds of the cache from storage.
+	cacheRebuild sync.WaitGroup
+
+	// True while the cache is rebuilt from storage in the background, during
+	// which TopN reads counts from storage instead. cacheWarmGen identifies
+	// the latest rebuild, so that an earlier one stops when another starts.
+	cacheWarming bool
+	cacheWarmGen int
+
+	// Accounts for cache memory across the holder. Set by the parent view.
+	cacheAccountant *cacheAccountant
+
+	// Rebuilds the cache in the background after imports. Set by the
+	// parent view.
+	cacheRebuilder *cacheRebuilder
+
+	// Snapshots the fragment in the background once its op log passes
+	// MaxOpN. Set by the parent view.
+	snapshotQueue *snapshotQueue
+
+	// Time of the last TopN served by the cache, in unix nanoseconds.
+	// Accessed atomically.
+	cacheUsed int64
+
+	// Number of row counts read from storage by TopN because they weren't
+	// available in the cache. Accessed atomically.
+	cacheScans uint64
+
+	// Rows whose cached counts are out of date because rank maintenance was
+	// deferred during a burst of writes. See updateCache.
+	dirtyRows map[uint64]struct{}
+
+	// Number of single bit writes in the current one second window, used to
+	// decide when to defer rank maintenance.
+	cacheWriteWindow    time.Time
+	cacheWriteN         int
+	cacheDeferThreshold int
+
 	// Stats reporting.
 	maxRowID uint64
 
+	// Highest column ID set in any fragment of the index.
+	maxColumnID *maxID
+
+	// Counts the bits set and cleared in the fragments of the field.
+	writeStats *writeStats
+
+	// Determines whether writes to the op log are synced.
+	durability Durability
+
+	// Opens the data file read-only and rejects writes.
+	readOnly bool
+
 	// Cache containing full rows (not just counts).
 	rowCache bitmapCache
 
@@ -119,6 +229,20 @@ type fragment struct {
 	// so that they can be mmapped and heap utilization can be kept low.
 	MaxOpN int
 
+	// Readers which hold the fragment across several reads of its storage,
+	// such as exports. Close waits up to CloseTimeout for them to release
+	// the fragment, and new readers are refused once it has begun.
+	readerMu     sync.Mutex
+	readerN      int
+	closing      bool
+	readersDone  chan struct{} // closed by the last reader to release a closing fragment
+	CloseTimeout time.Duration
+
+	// Time the fragment was last opened or returned by its view, in
+	// nanoseconds since the epoch, used to close idle fragments. Accessed
+	// atomically.
+	accessed int64
+
 	// Logger used for out-of-band log entries.
 	Logger logger.Logger
 
@@ -136,21 +260,34 @@ type fragment struct {
 // newFragment returns a new instance of Fragment.
 func newFragment(path, index, field, view string, shard uint64) *fragment {
 	return &fragment{
-		path:      path,
-		index:     index,
-		field:     field,
-		view:      view,
-		shard:     shard,
-		CacheType: DefaultCacheType,
-		CacheSize: DefaultCacheSize,
+		path:       path,
+		index:      index,
+		field:      field,
+		view:       view,
+		shard:      shard,
+		shardWidth: ShardWidth,
+		CacheType:  DefaultCacheType,
+		CacheSize:  DefaultCacheSize,
 
 		Logger: logger.NopLogger,
 		MaxOpN: defaultFragmentMaxOpN,
 
+		CloseTimeout: defaultFragmentCloseTimeout,
+
+		dirtyRows:           make(map[uint64]struct{}),
+		cacheDeferThreshold: defaultCacheDeferThreshold,
+
 		stats: stats.NopStatsClient,
 	}
 }
 
+// containerExponent is the counterpart of shardVsContainerExponent for the
+// fragment's shard width: 2^containerExponent is the number of containers in
+// a row of the fragment.
+func (f *fragment) containerExponent() uint {
+	return uint(bits.TrailingZeros64(f.shardWidth)) - 16
+}
+
 // cachePath returns the path to the fragment's cache data.
 func (f *fragment) cachePath() string { return f.path + cacheExt }
 
@@ -159,6 +296,10 @@ func (f *fragment) Open() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	f.readerMu.Lock()
+	f.closing = false
+	f.readerMu.Unlock()
+
 	if err := func() error {
 		// Initialize storage in a function so we can close if anything goes wrong.
 		if err := f.openStorage(); err != nil {
@@ -169,14 +310,16 @@ func (f *fragment) Open() error {
 		if err := f.openCache(); err != nil {
 			return errors.Wrap(err, "opening cache")
 		}
+		f.cacheAccountant.register(f)
 
 		// Clear checksums.
 		f.checksums = make(map[int][]byte)
 
 		// Read last bit to determine max row.
 		pos := f.storage.Max()
-		f.maxRowID = pos / ShardWidth
+		f.maxRowID = pos / f.shardWidth
 		f.stats.Gauge("rows", float64(f.maxRowID), 1.0)
+		f.touch()
 		return nil
 	}(); err != nil {
 		f.close()
@@ -192,22 +335,38 @@ func (f *fragment) openStorage() error {
 	if f.storage == nil {
 		f.storage = roaring.NewFileBitmap()
 	}
-	// Open the data file to be mmap'd and used as an ops log.
-	file, err := os.OpenFile(f.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	// Open the data file to be mmap'd and used as an ops log. Read-only
+	// fragments only read it, and don't lock it, so that they can read the
+	// fragments of a process which has them open for writing. Such a process
+	// only appends to the file or replaces it, so the part mapped here is
+	// left as it was.
+	flag := os.O_RDWR | os.O_CREATE | os.O_APPEND
+	if f.readOnly {
+		flag = os.O_RDONLY
+	}
+	file, err := os.OpenFile(f.path, flag, 0666)
 	if err != nil {
 		return fmt.Errorf("open file: %s", err)
 	}
 	f.file = file
 
 	// Lock the underlying file.
-	if err := syscall.Flock(int(f.file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
-		return fmt.Errorf("flock: %s", err)
+	if !f.readOnly {
+		if err := syscall.Flock(int(f.file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			return fmt.Errorf("flock: %s", err)
+		}
 	}
 
-	// If the file is empty then initialize it with an empty bitmap.
+	// If the file is empty then initialize it with an empty bitmap, unless
+	// it is read-only, in which case the storage is left empty.
 	fi, err := f.file.Stat()
 	if err != nil {
 		return errors.Wrap(err, "statting file before")
+	} else if fi.Size() == 0 && f.readOnly {
+		f.storage.OpWriter = readOnlyWriter{}
+		f.rowCache = &simpleCache{make(map[uint64]*Row)}
+		f.bitN, f.rowN = 0, 0
+		return nil
 	} else if fi.Size() == 0 {
 		bi := bufio.NewWriter(f.file)
 		if _, err := f.storage.WriteTo(bi); err != nil {
@@ -235,13 +394,36 @@ func (f *fragment) openStorage() error {
 	// Attach the mmap file to the bitmap.
 	data := f.storageData
 	if err := f.storage.UnmarshalBinary(data); err != nil {
-		return fmt.Errorf("unmarshal storage: file=%s, err=%s", f.file.Name(), err)
+		// A crash while an op was appended can leave part of it at the end
+		// of the file. Everything before it is intact, so the op is
+		// dropped, as if it had never been written. An unreadable op
+		// anywhere else is corruption, which fails the open.
+		opErr, ok := errors.Cause(err).(*roaring.OpLogError)
+		if !ok || !opErr.Tail {
+			return fmt.Errorf("unmarshal storage: file=%s, err=%s", f.file.Name(), err)
+		}
+		f.Logger.Errorf("dropping %d bytes of unreadable op log: file=%s, err=%s", len(data)-opErr.Offset, f.file.Name(), opErr.Err)
+		if !f.readOnly {
+			if err := f.file.Truncate(int64(opErr.Offset)); err != nil {
+				return errors.Wrap(err, "trimming op log")
+			}
+		}
+		data = data[:opErr.Offset]
 	}
+	f.storageHash = xxhash.New()
+	f.storageHash.Write(data) // nolint: errcheck
 
 	f.opN = f.storage.Info().OpN
+	f.countStorage()
 
 	// Attach the file to the bitmap to act as a write-ahead log.
-	f.storage.OpWriter = f.file
+	if f.readOnly {
+		f.storage.OpWriter = readOnlyWriter{}
+	} else if f.durability.syncsOpLog() {
+		f.storage.OpWriter = hashingWriter{syncWriter{f.file}, f}
+	} else {
+		f.storage.OpWriter = hashingWriter{f.file, f}
+	}
 	f.rowCache = &simpleCache{make(map[uint64]*Row)}
 
 	return nil
@@ -250,69 +432,439 @@ func (f *fragment) openStorage() error {
 
 // openCache initializes the cache from row ids persisted to disk.
 func (f *fragment) openCache() error {
-	// Determine cache type from field name.
+	c, err := f.newCache()
+	if err != nil {
+		return err
+	}
+	f.cache = c
+	if f.CacheType == CacheTypeNone {
+		return nil
+	}
+
+	// Read cache data from disk. If the cache can't be trusted to reflect the
+	// current storage then rebuild it from storage in the background.
+	ids, counts, ok := f.readCache()
+	if !ok {
+		if f.storage.Any() {
+			f.scheduleCacheRebuild(nil)
+		}
+		return nil
+	}
+
+	// Cache files written before counts were persisted only hold the row
+	// ids, whose counts are read from storage in the background.
+	if len(counts) != len(ids) {
+		f.scheduleCacheRebuild(ids)
+		return nil
+	}
+	for i, id := range ids {
+		f.cache.BulkAdd(id, counts[i])
+	}
+	f.cache.Invalidate()
+	f.cacheRebuilder.warmupStarted()
+	f.cacheRebuilder.warmupFinished(true)
+
+	return nil
+}
+
+// newCache returns an empty cache for the fragment's cache type.
+func (f *fragment) newCache() (cache, error) {
 	switch f.CacheType {
 	case CacheTypeRanked:
-		f.cache = NewRankCache(f.CacheSize)
+		return NewRankCache(f.CacheSize), nil
 	case CacheTypeLRU:
-		f.cache = newLRUCache(f.CacheSize)
+		return newLRUCache(f.CacheSize), nil
 	case CacheTypeNone:
-		f.cache = globalNopCache
-		return nil
+		return globalNopCache, nil
 	default:
-		return ErrInvalidCacheType
+		return nil, ErrInvalidCacheType
 	}
+}
 
-	// Read cache data from disk.
+// readCache reads the row ids and their counts persisted in the cache file.
+// The counts are nil if the file doesn't hold them. It returns false if the
+// file doesn't exist, can't be decoded, or was written against storage which
+// differs from the current storage.
+func (f *fragment) readCache() (ids, counts []uint64, ok bool) {
 	path := f.cachePath()
 	buf, err := ioutil.ReadFile(path)
 	if os.IsNotExist(err) {
-		return nil
+		return nil, nil, false
 	} else if err != nil {
-		return fmt.Errorf("open cache: %s", err)
+		f.Logger.Errorf("reading cache data, skipping: path=%s, err=%s", path, err)
+		return nil, nil, false
 	}
 
-	// Unmarshal cache data.
-	var pb internal.Cache
-	if err := proto.Unmarshal(buf, &pb); err != nil {
-		f.Logger.Printf("error unmarshaling cache data, skipping: path=%s, err=%s", path, err)
-		return nil
+	checksum, ids, counts, err := decodeCacheFile(buf)
+	if err != nil {
+		f.Logger.Errorf("unmarshaling cache data, skipping: path=%s, err=%s", path, err)
+		return nil, nil, false
 	}
 
-	// Read in all rows by ID.
-	// This will cause them to be added to the cache.
-	for _, id := range pb.IDs {
-		n := f.storage.CountRange(id*ShardWidth, (id+1)*ShardWidth)
-		f.cache.BulkAdd(id, n)
+	current, err := f.storageChecksum()
+	if err != nil {
+		f.Logger.Errorf("computing storage checksum, skipping cache: path=%s, err=%s", path, err)
+		return nil, nil, false
+	} else if checksum != current {
+		f.Logger.Debugf("cache checksum mismatch, skipping: path=%s", path)
+		return nil, nil, false
 	}
-	f.cache.Invalidate()
+	return ids, counts, true
+}
 
-	return nil
+// storageChecksum returns a checksum of the data file as it currently exists
+// on disk, including any operations appended since the last snapshot. The
+// file is only read if the hash kept as it's written was lost.
+func (f *fragment) storageChecksum() (uint64, error) {
+	if f.storageHash != nil {
+		return f.storageHash.Sum64(), nil
+	}
+
+	fi, err := f.file.Stat()
+	if err != nil {
+		return 0, errors.Wrap(err, "statting")
+	}
+
+	h := xxhash.New()
+	if _, err := io.Copy(h, io.NewSectionReader(f.file, 0, fi.Size())); err != nil {
+		return 0, errors.Wrap(err, "hashing")
+	}
+	return h.Sum64(), nil
 }
 
-// Close flushes the underlying storage, closes the file and unlocks it.
+// hashingWriter appends ops to the data file of a fragment, adding them to
+// the fragment's storage hash. A failed write loses the hash, since the
+// file may hold part of the op.
+type hashingWriter struct {
+	w io.Writer
+	f *fragment
+}
+
+func (w hashingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if h := w.f.storageHash; h != nil {
+		if err != nil {
+			w.f.storageHash = nil
+		} else {
+			h.Write(p[:n]) // nolint: errcheck
+		}
+	}
+	return n, err
+}
+
+// scheduleCacheRebuild counts the given rows, or every row in storage if
+// rowIDs is nil, into a new cache in a separate goroutine, which replaces
+// the fragment's cache once it's complete. The fragment lock is only held
+// to read the counts of cacheWarmBatchSize rows at a time from storage, and
+// to swap the new cache in, so that writes and queries aren't held up for
+// the duration of the rebuild. Writes in the meantime go to the current
+// cache, which remembers their rows so that they're counted again when the
+// new cache is swapped in, and TopN reads counts from storage until then
+// rather than returning partial rankings. f.mu must be held.
+func (f *fragment) scheduleCacheRebuild(rowIDs []uint64) {
+	c, err := f.newCache()
+	if err != nil {
+		f.Logger.Errorf("fragment: rebuilding cache: err=%s, path=%s", err, f.path)
+		return
+	}
+	if rowIDs == nil {
+		rowIDs = f.rows(0)
+	}
+	if w, ok := f.cache.(*warmingCache); ok {
+		f.cache = newWarmingCache(w.cache)
+	} else {
+		f.cache = newWarmingCache(f.cache)
+	}
+	f.cacheWarming = true
+	f.cacheWarmGen++
+	gen, size := f.cacheWarmGen, f.CacheSize
+
+	f.cacheRebuilder.warmupStarted()
+	f.cacheRebuild.Add(1)
+	go func() {
+		defer f.cacheRebuild.Done()
+		f.cacheRebuilder.warmupFinished(f.warmCache(gen, size, c, rowIDs))
+	}()
+}
+
+// warmCache counts rowIDs into c for the rebuild identified by gen, and
+// swaps it in as the fragment's cache. It returns false if the fragment was
+// closed or another rebuild started. size is the cache size c was created
+// with.
+func (f *fragment) warmCache(gen int, size uint32, c cache, rowIDs []uint64) bool {
+	f.stats.Count("cache.rebuild", 1, 1.0)
+	for len(rowIDs) > 0 {
+		n := cacheWarmBatchSize
+		if n > len(rowIDs) {
+			n = len(rowIDs)
+		}
+		counts, ok := f.countWarmRows(gen, rowIDs[:n])
+		if !ok {
+			return false
+		}
+		for i, count := range counts {
+			c.BulkAdd(rowIDs[i], count)
+		}
+		rowIDs = rowIDs[n:]
+	}
+	c.Recalculate()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.storageData == nil || gen != f.cacheWarmGen {
+		return false
+	}
+
+	// Count the rows written during the rebuild again, as they may have
+	// changed since their batch was counted.
+	recount := len(f.dirtyRows) > 0
+	if w, ok := f.cache.(*warmingCache); ok {
+		for rowID := range w.written {
+			c.Add(rowID, f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth))
+		}
+		recount = recount || len(w.written) > 0
+	}
+	for rowID := range f.dirtyRows {
+		c.Add(rowID, f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth))
+	}
+	if recount {
+		c.Recalculate()
+	}
+	if f.CacheSize != size {
+		c.SetMaxEntries(f.CacheSize)
+	}
+	f.cache = c
+	f.cacheWarming = false
+	return true
+}
+
+// countWarmRows returns the counts of a batch of rows for the rebuild
+// identified by gen, or false if the fragment was closed or the rebuild
+// superseded.
+func (f *fragment) countWarmRows(gen int, rowIDs []uint64) ([]uint64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.storageData == nil || gen != f.cacheWarmGen {
+		return nil, false
+	}
+	counts := make([]uint64, len(rowIDs))
+	for i, rowID := range rowIDs {
+		counts[i] = f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+	}
+	return counts, true
+}
+
+// warmingCache is the cache of a fragment while a new one is rebuilt in the
+// background. It records the rows written to it, to be counted again in the
+// new cache.
+type warmingCache struct {
+	cache
+	written map[uint64]struct{}
+}
+
+func newWarmingCache(c cache) *warmingCache {
+	return &warmingCache{cache: c, written: make(map[uint64]struct{})}
+}
+
+func (c *warmingCache) Add(id uint64, n uint64) {
+	c.written[id] = struct{}{}
+	c.cache.Add(id, n)
+}
+
+func (c *warmingCache) BulkAdd(id uint64, n uint64) {
+	c.written[id] = struct{}{}
+	c.cache.BulkAdd(id, n)
+}
+
+// isCacheWarming returns true while the cache is rebuilt in the background.
+func (f *fragment) isCacheWarming() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.cacheWarming
+}
+
+// encodeCacheFile returns the contents of a cache file for a set of row ids
+// which were cached against storage with the given checksum.
+func encodeCacheFile(checksum uint64, ids, counts []uint64) ([]byte, error) {
+	body, err := proto.Marshal(&internal.Cache{IDs: ids, Counts: counts})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling")
+	}
+
+	buf := make([]byte, cacheFileHeaderSize, cacheFileHeaderSize+len(body))
+	copy(buf, cacheFileMagic)
+	buf[len(cacheFileMagic)] = cacheFileVersion
+	binary.BigEndian.PutUint64(buf[len(cacheFileMagic)+1:], checksum)
+	return append(buf, body...), nil
+}
+
+// DecodeCacheFile returns the storage checksum and row ids from the
+// contents of a cache file. The checksum is the xxhash of the data file the
+// row ids were cached against.
+func DecodeCacheFile(buf []byte) (checksum uint64, ids []uint64, err error) {
+	checksum, ids, _, err = decodeCacheFile(buf)
+	return checksum, ids, err
+}
+
+// decodeCacheFile is DecodeCacheFile which also returns the counts of the
+// rows, or nil for cache files which don't hold them.
+func decodeCacheFile(buf []byte) (checksum uint64, ids, counts []uint64, err error) {
+	if len(buf) < cacheFileHeaderSize || string(buf[:len(cacheFileMagic)]) != cacheFileMagic {
+		return 0, nil, nil, errors.New("unversioned cache file")
+	} else if v := buf[len(cacheFileMagic)]; v != cacheFileVersion {
+		return 0, nil, nil, fmt.Errorf("unsupported cache file version: %d", v)
+	}
+	checksum = binary.BigEndian.Uint64(buf[len(cacheFileMagic)+1:])
+
+	var pb internal.Cache
+	if err := proto.Unmarshal(buf[cacheFileHeaderSize:], &pb); err != nil {
+		return 0, nil, nil, errors.Wrap(err, "unmarshalling")
+	}
+	return checksum, pb.IDs, pb.Counts, nil
+}
+
+// Close flushes the underlying storage, closes the file and unlocks it. It
+// first waits up to CloseTimeout for the readers holding the fragment to
+// release it, and closes it under any which haven't.
 func (f *fragment) Close() error {
+	return f.closeWithin(f.CloseTimeout)
+}
+
+// closeWithin closes the fragment like Close, but waits up to timeout for
+// its readers, so that fragments closed together can share one deadline.
+func (f *fragment) closeWithin(timeout time.Duration) error {
+	f.waitReaders(timeout)
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	return f.close()
 }
 
+// closeUnlessRead closes the fragment unless a reader holds it, returning
+// false if one does. Unlike Close, it never waits for readers, and new ones
+// are refused once it has begun.
+func (f *fragment) closeUnlessRead() (bool, error) {
+	f.readerMu.Lock()
+	if f.readerN > 0 {
+		f.readerMu.Unlock()
+		return false, nil
+	}
+	f.closing = true
+	f.readerMu.Unlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return true, f.close()
+}
+
+// touch records that the fragment was accessed now.
+func (f *fragment) touch() {
+	atomic.StoreInt64(&f.accessed, time.Now().UnixNano())
+}
+
+// lastAccessed returns the time the fragment was last opened or touched.
+func (f *fragment) lastAccessed() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&f.accessed))
+}
+
+// acquire registers a reader which holds the fragment across several reads,
+// so that Close waits for it before closing the storage. It returns
+// ErrFragmentClosing once Close has begun. Each successful call must be
+// paired with a call to release.
+func (f *fragment) acquire() error {
+	f.readerMu.Lock()
+	defer f.readerMu.Unlock()
+	if f.closing {
+		return ErrFragmentClosing
+	}
+	f.readerN++
+	return nil
+}
+
+// release unregisters a reader registered by acquire.
+func (f *fragment) release() {
+	f.readerMu.Lock()
+	defer f.readerMu.Unlock()
+	f.readerN--
+	if f.readerN == 0 && f.readersDone != nil {
+		close(f.readersDone)
+		f.readersDone = nil
+	}
+}
+
+// refuseReaders refuses new readers of the fragment, as it's about to be
+// closed.
+func (f *fragment) refuseReaders() {
+	f.readerMu.Lock()
+	defer f.readerMu.Unlock()
+	f.closing = true
+}
+
+// waitReaders refuses new readers and waits up to timeout for the current
+// ones to release the fragment.
+func (f *fragment) waitReaders(timeout time.Duration) {
+	f.readerMu.Lock()
+	f.closing = true
+	if f.readerN == 0 {
+		f.readerMu.Unlock()
+		return
+	}
+	if f.readersDone == nil {
+		f.readersDone = make(chan struct{})
+	}
+	done, n := f.readersDone, f.readerN
+	f.readerMu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		f.Logger.Printf("fragment: closing under readers after waiting %s: readers=%d, path=%s", timeout, n, f.path)
+	}
+}
+
 func (f *fragment) close() error {
-	// Flush cache if closing gracefully.
-	if err := f.flushCache(); err != nil {
-		f.Logger.Printf("fragment: error flushing cache on close: err=%s, path=%s", err, f.path)
-		return errors.Wrap(err, "flushing cache")
+	f.cacheAccountant.unregister(f)
+	f.cacheRebuilder.done(f)
+
+	// A fragment waiting in the snapshot queue is snapshotted now instead,
+	// so that it isn't left with an op log past its threshold.
+	if f.snapshotQueue.queued(f) {
+		if err := f.snapshot(); err != nil {
+			f.Logger.Errorf("fragment: snapshot on close: err=%s, path=%s", err, f.path)
+		}
 	}
+	f.snapshotQueue.done(f)
+
+	// Flush cache if closing gracefully. The storage is closed even if the
+	// cache can't be flushed, as the cache can be rebuilt from it.
+	flushErr := f.flushCache()
+	if flushErr != nil {
+		f.Logger.Errorf("fragment: flushing cache on close: err=%s, path=%s", flushErr, f.path)
+	}
+
+	// Stop any rebuild of the cache in the background.
+	f.cacheWarming = false
+	f.cacheWarmGen++
 
 	// Close underlying storage.
 	if err := f.closeStorage(); err != nil {
-		f.Logger.Printf("fragment: error closing storage: err=%s, path=%s", err, f.path)
+		f.Logger.Errorf("fragment: closing storage: err=%s, path=%s", err, f.path)
 		return errors.Wrap(err, "closing storage")
+	} else if flushErr != nil {
+		return errors.Wrap(flushErr, "flushing cache")
 	}
 
 	// Remove checksums.
 	f.checksums = nil
 
+	// Replace the storage, whose containers referenced the unmapped file, so
+	// callers still holding the fragment read an empty one instead.
+	f.storage = roaring.NewFileBitmap()
+	f.rowCache = &simpleCache{make(map[uint64]*Row)}
+	f.bitN, f.rowN = 0, 0
+
 	return nil
 }
 
@@ -346,6 +898,7 @@ func (f *fragment) closeStorage() error {
 	// write log, so once the storage is closed it should be 0. Opening new
 	// storage will set opN appropriately.
 	f.opN = 0
+	f.storageHash = nil
 
 	return nil
 }
@@ -357,6 +910,19 @@ func (f *fragment) row(rowID uint64) *Row {
 	return f.unprotectedRow(rowID)
 }
 
+// estimateRowCount returns the count of a row in the cache, which may be
+// stale, or else the count of its bits in storage.
+func (f *fragment) estimateRowCount(rowID uint64) uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.CacheType != CacheTypeNone && !f.cacheWarming {
+		if n := f.cache.Get(rowID); n > 0 {
+			return n
+		}
+	}
+	return f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+}
+
 // unprotectedRow returns a row from the row cache if available or from storage
 // (updating the cache).
 func (f *fragment) unprotectedRow(rowID uint64) *Row {
@@ -373,22 +939,33 @@ func (f *fragment) unprotectedRow(rowID uint64) *Row {
 // rowFromStorage clones a row data out of fragment storage and returns it as a
 // Row object.
 func (f *fragment) rowFromStorage(rowID uint64) *Row {
-	// Only use a subset of the containers.
-	// NOTE: The start & end ranges must be divisible by container width.
-	data := f.storage.OffsetRange(f.shard*ShardWidth, rowID*ShardWidth, (rowID+1)*ShardWidth)
-
-	// Reference bitmap subrange in storage. We Clone() data because otherwise
-	// row will contain pointers to containers in storage. This causes
-	// unexpected results when we cache the row and try to use it later.
-	// Basically, since we return the Row and release the fragment lock, the
-	// underlying fragment storage could be changed or snapshotted and thrown
-	// out at any point.
-	row := &Row{
-		segments: []rowSegment{{
+	// Rows are segmented by the default shard width whatever the width of
+	// the fragment, so the fragment's columns are in one segment, or span
+	// several if the fragment is wider.
+	colStart := f.shard * f.shardWidth
+	segmentWidth := f.shardWidth
+	if segmentWidth > ShardWidth {
+		segmentWidth = ShardWidth
+	}
+
+	row := &Row{}
+	for offset := uint64(0); offset < f.shardWidth; offset += segmentWidth {
+		// Only use a subset of the containers.
+		// NOTE: The start & end ranges must be divisible by container width.
+		start := rowID*f.shardWidth + offset
+		data := f.storage.OffsetRange(colStart+offset, start, start+segmentWidth)
+
+		// Reference bitmap subrange in storage. We Clone() data because otherwise
+		// row will contain pointers to containers in storage. This causes
+		// unexpected results when we cache the row and try to use it later.
+		// Basically, since we return the Row and release the fragment lock, the
+		// underlying fragment storage could be changed or snapshotted and thrown
+		// out at any point.
+		row.segments = append(row.segments, rowSegment{
 			data:     *data.Clone(),
-			shard:    f.shard,
+			shard:    (colStart + offset) / ShardWidth,
 			writable: false, // this Row will probably be cached and shared, so it must be read only.
-		}},
+		})
 	}
 	row.invalidateCount()
 
@@ -437,6 +1014,7 @@ func (f *fragment) unprotectedSetBit(rowID, columnID uint64) (changed bool, err
 	if changed, err = f.storage.Add(pos); err != nil {
 		return false, errors.Wrap(err, "writing")
 	}
+	f.maxColumnID.observe(columnID)
 
 	// Don't update the cache if nothing changed.
 	if !changed {
@@ -446,25 +1024,30 @@ func (f *fragment) unprotectedSetBit(rowID, columnID uint64) (changed bool, err
 	// Invalidate block checksum.
 	delete(f.checksums, int(rowID/HashBlockSize))
 
-	// Increment number of operations until snapshot is required.
-	if err := f.incrementOpN(); err != nil {
-		return false, errors.Wrap(err, "incrementing")
-	}
-
 	// Get the row from row cache or fragment.storage.
 	row := f.unprotectedRow(rowID)
 	row.SetBit(columnID)
 
-	// Update the cache.
-	f.cache.Add(rowID, row.Count())
+	// The row is new if this is its only bit.
+	f.bitN++
+	if row.Count() == 1 {
+		f.rowN++
+	}
+
+	// Update the cache. This must happen before a snapshot is triggered so
+	// that the cache persisted with the snapshot includes this row.
+	f.updateCache(rowID, row)
+
+	// Increment number of operations until snapshot is required.
+	if err := f.incrementOpN(); err != nil {
+		return false, errors.Wrap(err, "incrementing")
+	}
 
 	f.stats.Count("setBit", 1, 0.001)
+	f.writeStats.observe(1, 0)
 
 	// Update row count if they have increased.
-	if rowID > f.maxRowID {
-		f.maxRowID = rowID
-		f.stats.Gauge("rows", float64(f.maxRowID), 1.0)
-	}
+	f.observeRowID(rowID)
 
 	return changed, nil
 }
@@ -500,21 +1083,66 @@ func (f *fragment) unprotectedClearBit(rowID, columnID uint64) (changed bool, er
 	// Invalidate block checksum.
 	delete(f.checksums, int(rowID/HashBlockSize))
 
+	// Get the row from cache or fragment.storage.
+	row := f.unprotectedRow(rowID)
+	row.clearBit(columnID)
+
+	f.bitN--
+	if row.Count() == 0 {
+		f.rowN--
+	}
+
+	// Update the cache before a snapshot may be triggered.
+	f.updateCache(rowID, row)
+
 	// Increment number of operations until snapshot is required.
 	if err := f.incrementOpN(); err != nil {
 		return false, errors.Wrap(err, "incrementing")
 	}
 
-	// Get the row from cache or fragment.storage.
-	row := f.unprotectedRow(rowID)
-	row.clearBit(columnID)
+	f.stats.Count("clearBit", 1, 1.0)
+	f.writeStats.observe(0, 1)
 
-	// Update the cache.
+	return changed, nil
+}
+
+// updateCache updates the cached count of a row after a single bit write.
+//
+// Maintaining the rank order on every write is expensive during bulk loads,
+// so once writes exceed cacheDeferThreshold per second the count is not
+// recomputed. Instead the row is marked dirty and its count is brought up to
+// date by applyDirtyRows before the cache is next read. Readers such as TopN
+// block while pending rows are applied rather than returning stale results.
+func (f *fragment) updateCache(rowID uint64, row *Row) {
+	now := time.Now()
+	if now.Sub(f.cacheWriteWindow) >= time.Second {
+		f.cacheWriteWindow, f.cacheWriteN = now, 0
+	}
+	f.cacheWriteN++
+
+	if f.cacheWriteN > f.cacheDeferThreshold {
+		f.dirtyRows[rowID] = struct{}{}
+		return
+	}
 	f.cache.Add(rowID, row.Count())
+}
 
-	f.stats.Count("clearBit", 1, 1.0)
+// applyDirtyRows recomputes the cached counts of all rows marked dirty by
+// deferred rank maintenance and rebuilds the rankings.
+func (f *fragment) applyDirtyRows() {
+	if len(f.dirtyRows) == 0 {
+		return
+	}
+	f.stats.Count("cache.deferred", int64(len(f.dirtyRows)), 1.0)
 
-	return changed, nil
+	for rowID := range f.dirtyRows {
+		f.cache.Add(rowID, f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth))
+	}
+	f.dirtyRows = make(map[uint64]struct{})
+	f.cache.Recalculate()
+
+	// Any background rebuild queued by an import is now redundant.
+	f.cacheRebuilder.done(f)
 }
 
 // setRow replaces an existing row (specified by rowID) with the given
@@ -533,28 +1161,31 @@ func (f *fragment) unprotectedSetRow(row *Row, rowID uint64) (changed bool, err
 	changed = true
 
 	// First container of the row in storage.
-	headContainerKey := rowID << shardVsContainerExponent
+	headContainerKey := rowID << f.containerExponent()
+	before := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
 
 	// Remove every existing container in the row.
-	for i := uint64(0); i < (1 << shardVsContainerExponent); i++ {
+	for i := uint64(0); i < (1 << f.containerExponent()); i++ {
 		f.storage.Containers.Remove(headContainerKey + i)
 	}
 
-	// From the given row, get the rowSegment for this shard.
-	seg := row.segment(f.shard)
-	if seg == nil {
-		return changed, nil
-	}
-
-	// Put each container from rowSegment to fragment storage.
-	citer, _ := seg.data.Containers.Iterator(f.shard << shardVsContainerExponent)
-	for citer.Next() {
-		k, c := citer.Value()
-		f.storage.Containers.Put(headContainerKey+(k%(1<<shardVsContainerExponent)), c)
+	// Put each container of the given row within this shard to fragment
+	// storage. The row's segments may be wider or narrower than the shard.
+	startKey := (f.shard * f.shardWidth) >> 16
+	endKey := startKey + (1 << f.containerExponent())
+	for _, seg := range row.segments {
+		citer, _ := seg.data.Containers.Iterator(startKey)
+		for citer.Next() {
+			k, c := citer.Value()
+			if k >= endKey {
+				break
+			}
+			f.storage.Containers.Put(headContainerKey+(k-startKey), c)
+		}
 	}
 
 	// Update the row in cache.
-	n := f.storage.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
+	n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
 	f.cache.BulkAdd(rowID, n)
 
 	// Snapshot storage.
@@ -564,6 +1195,13 @@ func (f *fragment) unprotectedSetRow(row *Row, rowID uint64) (changed bool, err
 
 	f.stats.Count("setRow", 1, 1.0)
 
+	// Only the change in the row's count is known, not which bits changed.
+	if n > before {
+		f.writeStats.observe(int(n-before), 0)
+	} else {
+		f.writeStats.observe(0, int(before-n))
+	}
+
 	return changed, nil
 }
 
@@ -579,15 +1217,17 @@ func (f *fragment) unprotectedClearRow(rowID uint64) (changed bool, err error) {
 	changed = false
 
 	// First container of the row in storage.
-	headContainerKey := rowID << shardVsContainerExponent
+	headContainerKey := rowID << f.containerExponent()
 
 	// Remove every container in the row.
-	for i := uint64(0); i < (1 << shardVsContainerExponent); i++ {
+	var cleared int
+	for i := uint64(0); i < (1 << f.containerExponent()); i++ {
 		k := headContainerKey + i
 		// Technically we could bypass the Get() call and only
 		// call Remove(), but the Get() gives us the ability
 		// to return true if any existing data was removed.
 		if cont := f.storage.Containers.Get(k); cont != nil {
+			cleared += int(cont.N())
 			f.storage.Containers.Remove(k)
 			changed = true
 		}
@@ -602,10 +1242,115 @@ func (f *fragment) unprotectedClearRow(rowID uint64) (changed bool, err error) {
 	}
 
 	f.stats.Count("clearRow", 1, 1.0)
+	f.writeStats.observe(0, cleared)
 
 	return changed, nil
 }
 
+// clearColumnRange clears the columns in [min, max] which fall within the
+// fragment's shard from every row, and returns the number of bits cleared.
+// Containers wholly within the range are dropped without reading their bits,
+// and only the containers at either end are cleared bit by bit. The fragment
+// is then snapshotted, as dropped containers aren't written to the op log.
+func (f *fragment) clearColumnRange(min, max uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.readerMu.Lock()
+	closing := f.closing
+	f.readerMu.Unlock()
+	if closing {
+		return 0, ErrFragmentClosing
+	} else if f.readOnly {
+		return 0, newForbiddenError(ErrReadOnly)
+	}
+
+	// Convert the range to column offsets within the shard.
+	shardMin := f.shard * f.shardWidth
+	shardMax := shardMin + f.shardWidth - 1
+	if min > max || max < shardMin || min > shardMax {
+		return 0, nil
+	}
+	lo, hi := uint64(0), f.shardWidth-1
+	if min > shardMin {
+		lo = min - shardMin
+	}
+	if max < shardMax {
+		hi = max - shardMin
+	}
+
+	// Find the containers to drop and the bits to clear from the storage.
+	exp := f.containerExponent()
+	var keys, positions []uint64
+	var n uint64
+	rowSet := make(map[uint64]struct{})
+	itr, _ := f.storage.Containers.Iterator(0)
+	for itr.Next() {
+		key, c := itr.Value()
+		rowID := key >> exp
+		start := (key & (1<<exp - 1)) << 16
+		end := start + 1<<16 - 1
+		if c.N() == 0 || end < lo || start > hi {
+			continue
+		}
+
+		if start >= lo && end <= hi {
+			keys = append(keys, key)
+			n += uint64(c.N())
+			rowSet[rowID] = struct{}{}
+			continue
+		}
+
+		first, last := start, end
+		if lo > first {
+			first = lo
+		}
+		if hi < last {
+			last = hi
+		}
+		before := len(positions)
+		f.storage.ForEachRange(rowID*f.shardWidth+first, rowID*f.shardWidth+last+1, func(pos uint64) {
+			positions = append(positions, pos)
+		})
+		if len(positions) > before {
+			rowSet[rowID] = struct{}{}
+		}
+	}
+	if len(rowSet) == 0 {
+		return 0, nil
+	}
+
+	for _, key := range keys {
+		f.storage.Containers.Remove(key)
+	}
+	n += uint64(f.storage.DirectRemoveN(positions...))
+
+	// Update cache counts for all affected rows.
+	deferCache := f.deferCacheRebuild()
+	for rowID := range rowSet {
+		delete(f.checksums, int(rowID/HashBlockSize))
+		if deferCache {
+			f.dirtyRows[rowID] = struct{}{}
+		} else {
+			f.cache.BulkAdd(rowID, f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth))
+		}
+	}
+	if deferCache {
+		f.cacheRebuilder.enqueue(f)
+	} else {
+		f.cache.Recalculate()
+	}
+
+	if err := f.snapshot(); err != nil {
+		return 0, errors.Wrap(err, "snapshotting")
+	}
+
+	f.stats.Count("clearColumnRange", 1, 1.0)
+	f.writeStats.observe(0, int(n))
+
+	return n, nil
+}
+
 func (f *fragment) bit(rowID, columnID uint64) (bool, error) {
 	pos, err := f.pos(rowID, columnID)
 	if err != nil {
@@ -614,6 +1359,14 @@ func (f *fragment) bit(rowID, columnID uint64) (bool, error) {
 	return f.storage.Contains(pos), nil
 }
 
+// contains returns true if the bit of the given row and column is set, without
+// reading the rest of the row.
+func (f *fragment) contains(rowID, columnID uint64) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.bit(rowID, columnID)
+}
+
 // value uses a column of bits to read a multi-bit value.
 func (f *fragment) value(columnID uint64, bitDepth uint) (value uint64, exists bool, err error) {
 	f.mu.Lock()
@@ -716,6 +1469,7 @@ func (f *fragment) setValueBase(columnID uint64, bitDepth uint, value uint64, cl
 
 // importSetValue is a more efficient SetValue just for imports.
 func (f *fragment) importSetValue(columnID uint64, bitDepth uint, value uint64, clear bool) (changed bool, err error) { // nolint: unparam
+	var set, cleared int
 	for i := uint(0); i < bitDepth; i++ {
 		if value&(1<<i) != 0 {
 			bit, err := f.pos(uint64(i), columnID)
@@ -726,6 +1480,7 @@ func (f *fragment) importSetValue(columnID uint64, bitDepth uint, value uint64,
 				return changed, errors.Wrap(err, "adding")
 			} else if c {
 				changed = true
+				set++
 			}
 		} else {
 			bit, err := f.pos(uint64(i), columnID)
@@ -736,6 +1491,7 @@ func (f *fragment) importSetValue(columnID uint64, bitDepth uint, value uint64,
 				return changed, errors.Wrap(err, "removing")
 			} else if c {
 				changed = true
+				cleared++
 			}
 		}
 	}
@@ -750,14 +1506,18 @@ func (f *fragment) importSetValue(columnID uint64, bitDepth uint, value uint64,
 			return changed, errors.Wrap(err, "removing not-null from storage")
 		} else if c {
 			changed = true
+			cleared++
 		}
 	} else {
 		if c, err := f.storage.Add(p); err != nil {
 			return changed, errors.Wrap(err, "adding not-null to storage")
 		} else if c {
 			changed = true
+			set++
 		}
+		f.maxColumnID.observe(columnID)
 	}
+	f.writeStats.observe(set, cleared)
 
 	return changed, nil
 }
@@ -1035,41 +1795,104 @@ func (f *fragment) rangeBetween(bitDepth uint, predicateMin, predicateMax uint64
 // pos translates the row ID and column ID into a position in the storage bitmap.
 func (f *fragment) pos(rowID, columnID uint64) (uint64, error) {
 	// Return an error if the column ID is out of the range of the fragment's shard.
-	minColumnID := f.shard * ShardWidth
-	if columnID < minColumnID || columnID >= minColumnID+ShardWidth {
+	minColumnID := f.shard * f.shardWidth
+	if columnID < minColumnID || columnID >= minColumnID+f.shardWidth {
 		return 0, errors.New("column out of bounds")
 	}
-	return pos(rowID, columnID), nil
+	return pos(rowID, columnID, f.shardWidth), nil
 }
 
-// forEachBit executes fn for every bit set in the fragment.
-// Errors returned from fn are passed through.
+// forEachBit executes fn for every bit set in the fragment, in order.
+// Errors returned from fn stop the iteration and are passed through.
 func (f *fragment) forEachBit(fn func(rowID, columnID uint64) error) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+	return f.forEachRowRangeBit(0, math.MaxUint64, fn)
+}
 
-	var err error
-	f.storage.ForEach(func(i uint64) {
-		// Skip if an error has already occurred.
-		if err != nil {
-			return
+// forEachExportBit executes fn for every bit set in the rows selected by opt.
+// Rows outside the selection are skipped without being read.
+func (f *fragment) forEachExportBit(opt *ExportOptions, fn func(rowID, columnID uint64) error) error {
+	if opt.RowIDs == nil {
+		return f.forEachRowRangeBit(opt.MinRowID, opt.MaxRowID, fn)
+	}
+	for _, rowID := range opt.rowIDs() {
+		if err := f.forEachRowRangeBit(rowID, rowID, fn); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		// Invoke caller's function.
-		err = fn(i/ShardWidth, (f.shard*ShardWidth)+(i%ShardWidth))
-	})
-	return err
+// forEachRowRangeBit executes fn, in order, for every bit set in the rows from
+// first to last, inclusive. The bits are read in batches under the lock, and
+// fn is called without it, so writes aren't held up by a long scan and fn may
+// write to the fragment itself. Each bit is seen as it was when its batch was
+// read. It returns ErrFragmentClosing if the fragment is being closed, and
+// holds the fragment open until it returns.
+func (f *fragment) forEachRowRangeBit(first, last uint64, fn func(rowID, columnID uint64) error) error {
+	if err := f.acquire(); err != nil {
+		return err
+	}
+	defer f.release()
+
+	// Rows beyond maxRowID cannot be stored in a fragment.
+	maxRowID := uint64(math.MaxUint64) / f.shardWidth
+	if first > maxRowID {
+		return nil
+	} else if last > maxRowID {
+		last = maxRowID
+	}
+	start, end := first*f.shardWidth, last*f.shardWidth+(f.shardWidth-1)
+
+	batch := make([]uint64, 0, forEachBitBatchSize)
+	for {
+		batch = batch[:0]
+		f.mu.Lock()
+		itr := f.storage.Iterator()
+		itr.Seek(start)
+		for v, eof := itr.Next(); !eof && v <= end; v, eof = itr.Next() {
+			if batch = append(batch, v); len(batch) == cap(batch) {
+				break
+			}
+		}
+		f.mu.Unlock()
+
+		for _, v := range batch {
+			if err := fn(v/f.shardWidth, (f.shard*f.shardWidth)+(v%f.shardWidth)); err != nil {
+				return err
+			}
+		}
+
+		// A short batch reached the end of the range.
+		if len(batch) < cap(batch) || batch[len(batch)-1] == end {
+			return nil
+		}
+		start = batch[len(batch)-1] + 1
+	}
 }
 
 // top returns the top rows from the fragment.
 // If opt.Src is specified then only rows which intersect src are returned.
 // If opt.FilterValues exist then the row attribute specified by field is matched.
-func (f *fragment) top(opt topOptions) ([]Pair, error) {
-	// Retrieve pairs. If no row ids specified then return from cache.
-	pairs := f.topBitmapPairs(opt.RowIDs)
+func (f *fragment) top(ctx context.Context, opt topOptions) ([]Pair, error) {
+	// Retrieve pairs. If no row ids specified then return from cache, unless
+	// exact counts are requested in which case every row in storage is read.
+	var pairs []bitmapPair
+	if len(opt.RowIDs) == 0 && (opt.Exact || f.isCacheWarming()) {
+		var err error
+		if pairs, err = f.storageBitmapPairs(ctx); err != nil {
+			return nil, err
+		}
+		atomic.AddUint64(&f.cacheScans, uint64(len(pairs)))
+	} else {
+		pairs = f.topBitmapPairs(opt.RowIDs)
+		if len(opt.RowIDs) == 0 {
+			atomic.StoreInt64(&f.cacheUsed, time.Now().UnixNano())
+		}
+	}
 
-	// If row ids are provided, we don't want to truncate the result set
-	if len(opt.RowIDs) > 0 {
+	// If row ids are provided, or exact results are required, we don't want
+	// to truncate the result set.
+	if len(opt.RowIDs) > 0 || opt.Exact {
 		opt.N = 0
 	}
 
@@ -1078,10 +1901,15 @@ func (f *fragment) top(opt topOptions) ([]Pair, error) {
 	if opt.FilterName != "" && len(opt.FilterValues) > 0 {
 		filters = make(map[interface{}]struct{})
 		for _, v := range opt.FilterValues {
-			filters[v] = struct{}{}
+			filters[attrFilterKey(v)] = struct{}{}
 		}
 	}
 
+	// The attributes of the rows are read in batches as they're reached,
+	// those of the pairs before attrsEnd having been read.
+	var attrs map[uint64]map[string]interface{}
+	var attrsEnd int
+
 	// Use `tanimotoThreshold > 0` to indicate whether or not we are considering Tanimoto.
 	var tanimotoThreshold uint64
 	var minTanimoto, maxTanimoto float64
@@ -1095,9 +1923,16 @@ func (f *fragment) top(opt topOptions) ([]Pair, error) {
 
 	// Iterate over rankings and add to results until we have enough.
 	results := &pairHeap{}
-	for _, pair := range pairs {
+	for i, pair := range pairs {
 		rowID, cnt := pair.ID, pair.Count
 
+		// Stop scanning once the query is cancelled or times out.
+		if i%queryCheckRowN == 0 {
+			if err := validateQueryContext(ctx); err != nil {
+				return nil, err
+			}
+		}
+
 		// Ignore empty rows.
 		if cnt == 0 {
 			continue
@@ -1116,16 +1951,25 @@ func (f *fragment) top(opt topOptions) ([]Pair, error) {
 			}
 		}
 
-		// Apply filter, if set.
+		// Apply filter, if set. Rows without the attribute never match.
 		if filters != nil {
-			attr, err := f.RowAttrStore.Attrs(rowID)
-			if err != nil {
-				return nil, errors.Wrap(err, "getting attrs")
-			} else if attr == nil {
-				continue
-			} else if attrValue := attr[opt.FilterName]; attrValue == nil {
+			if i >= attrsEnd {
+				attrsEnd = i + topAttrBatchSize
+				if attrsEnd > len(pairs) {
+					attrsEnd = len(pairs)
+				}
+				ids := make([]uint64, 0, attrsEnd-i)
+				for _, p := range pairs[i:attrsEnd] {
+					ids = append(ids, p.ID)
+				}
+				var err error
+				if attrs, err = f.RowAttrStore.BlockAttrs(ids); err != nil {
+					return nil, errors.Wrap(err, "getting attrs")
+				}
+			}
+			if attrValue := attrs[rowID][opt.FilterName]; attrValue == nil {
 				continue
-			} else if _, ok := filters[attrValue]; !ok {
+			} else if _, ok := filters[attrFilterKey(attrValue)]; !ok {
 				continue
 			}
 		}
@@ -1198,37 +2042,68 @@ func (f *fragment) top(opt topOptions) ([]Pair, error) {
 	return r, nil
 }
 
+// storageBitmapPairs returns the count of every row in storage, bypassing the
+// cache, ordered by count.
+func (f *fragment) storageBitmapPairs(ctx context.Context) ([]bitmapPair, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	rowIDs := f.rows(0)
+	pairs := make([]bitmapPair, 0, len(rowIDs))
+	for i, rowID := range rowIDs {
+		if i%queryCheckRowN == 0 {
+			if err := validateQueryContext(ctx); err != nil {
+				return nil, err
+			}
+		}
+		pairs = append(pairs, bitmapPair{
+			ID:    rowID,
+			Count: f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth),
+		})
+	}
+	sort.Sort(bitmapPairs(pairs))
+	return pairs, nil
+}
+
 func (f *fragment) topBitmapPairs(rowIDs []uint64) []bitmapPair {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	// Don't retrieve from storage if CacheTypeNone.
 	if f.CacheType == CacheTypeNone {
 		return f.cache.Top()
 	}
 	// If no specific rows are requested, retrieve top rows.
 	if len(rowIDs) == 0 {
-		f.mu.Lock()
-		defer f.mu.Unlock()
+		f.applyDirtyRows()
 		f.cache.Invalidate()
 		return f.cache.Top()
 	}
 
-	// Otherwise retrieve specific rows.
+	// Bring any deferred counts up to date before reading from the cache.
+	f.applyDirtyRows()
+
+	// Otherwise retrieve specific rows. A cache being rebuilt holds only
+	// some of the rows, so they're all counted from storage until then.
 	pairs := make([]bitmapPair, 0, len(rowIDs))
 	for _, rowID := range rowIDs {
 		// Look up cache first, if available.
-		if n := f.cache.Get(rowID); n > 0 {
-			pairs = append(pairs, bitmapPair{
-				ID:    rowID,
-				Count: n,
-			})
-			continue
+		if !f.cacheWarming {
+			if n := f.cache.Get(rowID); n > 0 {
+				pairs = append(pairs, bitmapPair{
+					ID:    rowID,
+					Count: n,
+				})
+				continue
+			}
 		}
 
-		row := f.row(rowID)
-		if row.Count() > 0 {
-			// Otherwise load from storage.
+		// Otherwise load from storage.
+		atomic.AddUint64(&f.cacheScans, 1)
+		if n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth); n > 0 {
 			pairs = append(pairs, bitmapPair{
 				ID:    rowID,
-				Count: row.Count(),
+				Count: n,
 			})
 		}
 	}
@@ -1236,6 +2111,22 @@ func (f *fragment) topBitmapPairs(rowIDs []uint64) []bitmapPair {
 	return pairs
 }
 
+// attrFilterKey returns the key of an attribute value in a filter, so that
+// an integer and a float of the same value match. Strings never match numbers.
+func attrFilterKey(v interface{}) interface{} {
+	switch v := v.(type) {
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v)
+		}
+	}
+	return v
+}
+
 // topOptions represents options passed into the Top() function.
 type topOptions struct {
 	// Number of rows to return.
@@ -1252,6 +2143,10 @@ type topOptions struct {
 	FilterName        string
 	FilterValues      []interface{}
 	TanimotoThreshold uint64
+
+	// Compute counts from storage rather than the cache and return every
+	// matching row so that results can be merged exactly.
+	Exact bool
 }
 
 // Checksum returns a checksum for the entire fragment.
@@ -1290,16 +2185,16 @@ func (f *fragment) Blocks() []FragmentBlock {
 	if eof {
 		return nil
 	}
-	blockID := int(v / (HashBlockSize * ShardWidth))
+	blockID := int(v / (HashBlockSize * f.shardWidth))
 	for {
 		// Check for multiple block checksums in a row.
 		if n := f.readContiguousChecksums(&a, blockID); n > 0 {
-			itr.Seek(uint64(blockID+n) * HashBlockSize * ShardWidth)
+			itr.Seek(uint64(blockID+n) * HashBlockSize * f.shardWidth)
 			v, eof = itr.Next()
 			if eof {
 				break
 			}
-			blockID = int(v / (HashBlockSize * ShardWidth))
+			blockID = int(v / (HashBlockSize * f.shardWidth))
 			continue
 		}
 
@@ -1310,7 +2205,7 @@ func (f *fragment) Blocks() []FragmentBlock {
 		// Read all values for the block.
 		for ; ; v, eof = itr.Next() {
 			// Once we hit the next block, save the value for the next iteration.
-			blockID = int(v / (HashBlockSize * ShardWidth))
+			blockID = int(v / (HashBlockSize * f.shardWidth))
 			if blockID != h.blockID || eof {
 				break
 			}
@@ -1352,24 +2247,54 @@ func (f *fragment) readContiguousChecksums(a *[]FragmentBlock, blockID int) (n i
 	}
 }
 
-// blockData returns bits in a block as row & column ID pairs.
-func (f *fragment) blockData(id int) (rowIDs, columnIDs []uint64) {
+// blockData returns a page of the bits in a block as row & column ID pairs,
+// skipping the first offset pairs and returning at most limit pairs. A zero
+// limit returns all pairs from offset. more is true if the block has pairs
+// after the page.
+func (f *fragment) blockData(id, offset, limit int) (rowIDs, columnIDs []uint64, more bool) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	f.storage.ForEachRange(uint64(id)*HashBlockSize*ShardWidth, (uint64(id)+1)*HashBlockSize*ShardWidth, func(i uint64) {
-		rowIDs = append(rowIDs, i/ShardWidth)
-		columnIDs = append(columnIDs, i%ShardWidth)
-	})
-	return rowIDs, columnIDs
+
+	start := uint64(id) * HashBlockSize * f.shardWidth
+	end := uint64(id+1) * HashBlockSize * f.shardWidth
+
+	// Skip whole containers before the page, so that later pages don't cost
+	// more than earlier ones.
+	skip := uint64(offset)
+	for skip > 0 && start < end {
+		next := (start>>16 + 1) << 16
+		n := f.storage.CountRange(start, next)
+		if n > skip {
+			break
+		}
+		skip -= n
+		start = next
+	}
+
+	itr := f.storage.Iterator()
+	itr.Seek(start)
+	for v, eof := itr.Next(); !eof && v < end; v, eof = itr.Next() {
+		if skip > 0 {
+			skip--
+			continue
+		} else if limit > 0 && len(rowIDs) == limit {
+			return rowIDs, columnIDs, true
+		}
+		rowIDs = append(rowIDs, v/f.shardWidth)
+		columnIDs = append(columnIDs, v%f.shardWidth)
+	}
+	return rowIDs, columnIDs, false
 }
 
-// mergeBlock compares the block's bits and computes a diff with another set of block bits.
+// mergeBlock compares the bits of the fragment between the positions from and
+// to, inclusive, with other sets of bits over the same range and computes a
+// diff for each. A position is rowID*shardWidth+columnID.
 // The state of a bit is determined by consensus from all blocks being considered.
 //
 // For example, if 3 blocks are compared and two have a set bit and one has a
 // cleared bit then the bit is considered cleared. The function returns the
 // diff per incoming block so that all can be in sync.
-func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, err error) {
+func (f *fragment) mergeBlock(from, to uint64, data []pairSet) (sets, clears []pairSet, err error) {
 	// Ensure that all pair sets are of equal length.
 	for i := range data {
 		if len(data[i].rowIDs) != len(data[i].columnIDs) {
@@ -1385,14 +2310,13 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 	clears = make([]pairSet, len(data)+1)
 
 	// Limit upper row/column pair.
-	maxRowID := uint64(id+1) * HashBlockSize
-	maxColumnID := uint64(ShardWidth)
+	maxRowID, maxColumnID := to/f.shardWidth, to%f.shardWidth
 
 	// Create buffered iterator for local block.
 	itrs := make([]*bufIterator, 1, len(data)+1)
 	itrs[0] = newBufIterator(
 		newLimitIterator(
-			newRoaringIterator(f.storage.Iterator()), maxRowID, maxColumnID,
+			newRoaringIterator(f.storage.Iterator(), f.shardWidth), maxRowID, maxColumnID,
 		),
 	)
 
@@ -1405,7 +2329,7 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 
 	// Seek to initial pair.
 	for _, itr := range itrs {
-		itr.Seek(uint64(id)*HashBlockSize, 0)
+		itr.Seek(from/f.shardWidth, from%f.shardWidth)
 	}
 
 	// Determine the number of blocks needed to meet consensus.
@@ -1466,22 +2390,22 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 				sets[i].rowIDs = append(sets[i].rowIDs, min.rowID)
 				sets[i].columnIDs = append(sets[i].columnIDs, min.columnID)
 			} else {
-				clears[i].rowIDs = append(sets[i].rowIDs, min.rowID)
-				clears[i].columnIDs = append(sets[i].columnIDs, min.columnID)
+				clears[i].rowIDs = append(clears[i].rowIDs, min.rowID)
+				clears[i].columnIDs = append(clears[i].columnIDs, min.columnID)
 			}
 		}
 	}
 
 	// Set local bits.
 	for i := range sets[0].columnIDs {
-		if _, err := f.unprotectedSetBit(sets[0].rowIDs[i], (f.shard*ShardWidth)+sets[0].columnIDs[i]); err != nil {
+		if _, err := f.unprotectedSetBit(sets[0].rowIDs[i], (f.shard*f.shardWidth)+sets[0].columnIDs[i]); err != nil {
 			return nil, nil, errors.Wrap(err, "setting")
 		}
 	}
 
 	// Clear local bits.
 	for i := range clears[0].columnIDs {
-		if _, err := f.unprotectedClearBit(clears[0].rowIDs[i], (f.shard*ShardWidth)+clears[0].columnIDs[i]); err != nil {
+		if _, err := f.unprotectedClearBit(clears[0].rowIDs[i], (f.shard*f.shardWidth)+clears[0].columnIDs[i]); err != nil {
 			return nil, nil, errors.Wrap(err, "clearing")
 		}
 	}
@@ -1492,9 +2416,18 @@ func (f *fragment) mergeBlock(id int, data []pairSet) (sets, clears []pairSet, e
 // bulkImport bulk imports a set of bits and then snapshots the storage.
 // The cache is updated to reflect the new data.
 func (f *fragment) bulkImport(rowIDs, columnIDs []uint64, options *ImportOptions) error {
+	_, _, err := f.bulkImportCount(rowIDs, columnIDs, options)
+	return err
+}
+
+// bulkImportCount bulk imports a set of bits like bulkImport. It returns the
+// number of distinct bits imported, and how many of them were set, or cleared,
+// by the import rather than already so. Bits of a mutex field are counted by
+// column, since only the last bit imported into a column is kept.
+func (f *fragment) bulkImportCount(rowIDs, columnIDs []uint64, options *ImportOptions) (n, changed int, err error) {
 	// Verify that there are an equal number of row ids and column ids.
 	if len(rowIDs) != len(columnIDs) {
-		return fmt.Errorf("mismatch of row/column len: %d != %d", len(rowIDs), len(columnIDs))
+		return 0, 0, fmt.Errorf("mismatch of row/column len: %d != %d", len(rowIDs), len(columnIDs))
 	}
 
 	if f.mutexVector != nil && !options.Clear {
@@ -1505,7 +2438,7 @@ func (f *fragment) bulkImport(rowIDs, columnIDs []uint64, options *ImportOptions
 
 // bulkImportStandard performs a bulk import on a standard fragment. May mutate
 // its rowIDs and columnIDs arguments.
-func (f *fragment) bulkImportStandard(rowIDs, columnIDs []uint64, options *ImportOptions) (err error) {
+func (f *fragment) bulkImportStandard(rowIDs, columnIDs []uint64, options *ImportOptions) (n, changed int, err error) {
 	// rowSet maintains the set of rowIDs present in this import. It allows the
 	// cache to be updated once per row, instead of once per bit. TODO: consider
 	// sorting by rowID/columnID first and avoiding the map allocation here. (we
@@ -1518,7 +2451,7 @@ func (f *fragment) bulkImportStandard(rowIDs, columnIDs []uint64, options *Impor
 		rowID, columnID := rowIDs[i], columnIDs[i]
 		pos, err := f.pos(rowID, columnID)
 		if err != nil {
-			return err
+			return 0, 0, err
 		}
 		columnIDs[i] = pos
 
@@ -1528,15 +2461,35 @@ func (f *fragment) bulkImportStandard(rowIDs, columnIDs []uint64, options *Impor
 			rowSet[rowID] = struct{}{}
 		}
 	}
-	positions := columnIDs
+
+	// Drop repeated positions so that a bit imported twice is counted once.
+	positions := uniquePositions(columnIDs)
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if options.Clear {
-		err = f.importPositions(nil, positions, rowSet)
+		_, changed, err = f.importPositions(nil, positions, rowSet)
 	} else {
-		err = f.importPositions(positions, nil, rowSet)
+		changed, _, err = f.importPositions(positions, nil, rowSet)
 	}
-	return errors.Wrap(err, "bulkImportStandard")
+	return len(positions), changed, errors.Wrap(err, "bulkImportStandard")
+}
+
+// uniquePositions sorts positions, unless they're sorted already, and returns
+// them without repeats. It reuses the storage of positions.
+func uniquePositions(positions []uint64) []uint64 {
+	if !sort.IsSorted(uint64Slice(positions)) {
+		sort.Sort(uint64Slice(positions))
+	}
+	n := 0
+	for i, pos := range positions {
+		if i > 0 && pos == positions[n-1] {
+			continue
+		}
+		positions[n] = pos
+		n++
+	}
+	return positions[:n]
 }
 
 // importPositions takes slices of positions within the fragment to set and
@@ -1546,8 +2499,9 @@ func (f *fragment) bulkImportStandard(rowIDs, columnIDs []uint64, options *Impor
 //
 // importPositions tries to intelligently decide whether or not to do a full
 // snapshot of the fragment or just do in-memory updates while appending
-// operations to the op log.
-func (f *fragment) importPositions(set, clear []uint64, rowSet map[uint64]struct{}) error {
+// operations to the op log. It returns the number of bits which were set and
+// cleared, i.e. which weren't already so.
+func (f *fragment) importPositions(set, clear []uint64, rowSet map[uint64]struct{}) (setN, clearN int, err error) {
 	smallWrite := false
 	if len(set)+len(clear)+f.opN < f.MaxOpN {
 		smallWrite = true
@@ -1555,54 +2509,191 @@ func (f *fragment) importPositions(set, clear []uint64, rowSet map[uint64]struct
 		f.storage.OpWriter = nil
 	}
 
+	// Rows without bits before the write, to count those it leaves with any.
+	empty := make(map[uint64]struct{})
+	for rowID := range rowSet {
+		if f.rowEmpty(rowID) {
+			empty[rowID] = struct{}{}
+		}
+	}
+
 	if len(set) > 0 {
 		f.stats.Count("ImportingN", int64(len(set)), 1)
-		changedN, err := f.storage.AddN(set...) // TODO benchmark Add/RemoveN behavior with sorted/unsorted positions
+		setN, err = f.storage.AddN(set...) // TODO benchmark Add/RemoveN behavior with sorted/unsorted positions
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "adding positions")
+		}
+		f.stats.Count("ImportedN", int64(setN), 1)
+		f.opN += setN
+		f.observePositions(set)
+	}
+
+	if len(clear) > 0 {
+		f.stats.Count("ClearingN", int64(len(clear)), 1)
+		clearN, err = f.storage.RemoveN(clear...)
 		if err != nil {
-			return errors.Wrap(err, "adding positions")
+			return 0, 0, errors.Wrap(err, "clearing positions")
+		}
+		f.stats.Count("ClearedN", int64(clearN), 1)
+		f.opN += clearN
+	}
+	f.writeStats.observe(setN, clearN)
+
+	f.bitN = f.bitN + uint64(setN) - uint64(clearN)
+	for rowID := range rowSet {
+		_, wasEmpty := empty[rowID]
+		if isEmpty := f.rowEmpty(rowID); wasEmpty && !isEmpty {
+			f.rowN++
+		} else if !wasEmpty && isEmpty {
+			f.rowN--
+		}
+	}
+
+	// Update cache counts for all affected rows. If the holder rebuilds
+	// caches in the background then the rows are only marked dirty here.
+	deferCache := f.deferCacheRebuild()
+	for rowID := range rowSet {
+		// Invalidate block checksum.
+		delete(f.checksums, int(rowID/HashBlockSize))
+
+		if deferCache {
+			f.dirtyRows[rowID] = struct{}{}
+		} else {
+			n := f.storage.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+			f.cache.BulkAdd(rowID, n)
+		}
+
+		if smallWrite {
+			if _, ok := f.rowCache.Fetch(rowID); ok { // we won't update the rowCache if it wasn't already in there.
+				f.rowCache.Add(rowID, f.rowFromStorage(rowID))
+			}
+		}
+	}
+
+	if deferCache {
+		f.cacheRebuilder.enqueue(f)
+	} else {
+		f.cache.Recalculate()
+	}
+
+	if !smallWrite {
+		return setN, clearN, f.snapshot()
+	}
+	return setN, clearN, nil
+}
+
+// observePositions raises the fragment's max row ID and the index's max
+// column ID to cover a set of bit positions.
+func (f *fragment) observePositions(positions []uint64) {
+	var maxRowID, maxOffset uint64
+	for _, pos := range positions {
+		if rowID := pos / f.shardWidth; rowID > maxRowID {
+			maxRowID = rowID
+		}
+		if offset := pos % f.shardWidth; offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+	if len(positions) > 0 {
+		f.observeRowID(maxRowID)
+		f.maxColumnID.observe(f.shard*f.shardWidth + maxOffset)
+	}
+}
+
+// observeRows raises the fragment's max row ID and the index's max column ID
+// to cover the given rows of bm, which must be sorted.
+func (f *fragment) observeRows(bm *roaring.Bitmap, rowIDs []uint64) {
+	if len(rowIDs) == 0 {
+		return
+	}
+	f.observeRowID(rowIDs[len(rowIDs)-1])
+	if offset, ok := maxColumnOffset(bm, rowIDs, f.shardWidth); ok {
+		f.maxColumnID.observe(f.shard*f.shardWidth + offset)
+	}
+}
+
+// observeRowID raises the fragment's max row ID.
+func (f *fragment) observeRowID(rowID uint64) {
+	if rowID > f.maxRowID {
+		f.maxRowID = rowID
+		f.stats.Gauge("rows", float64(f.maxRowID), 1.0)
+	}
+}
+
+// maxRow returns the highest row ID set in the fragment since it was opened,
+// or stored in it when it was opened.
+func (f *fragment) maxRow() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.maxRowID
+}
+
+// maxColumn returns the highest column ID stored in the fragment. It reads
+// every container of the fragment so it is only used when opening an index.
+func (f *fragment) maxColumn() (uint64, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.storage == nil || !f.storage.Any() {
+		return 0, false
+	}
+
+	var rowIDs []uint64
+	lastRow := uint64(math.MaxUint64)
+	iter, _ := f.storage.Containers.Iterator(0)
+	for iter.Next() {
+		key, _ := iter.Value()
+		if vRow := key >> f.containerExponent(); vRow != lastRow {
+			rowIDs = append(rowIDs, vRow)
+			lastRow = vRow
 		}
-		f.stats.Count("ImportedN", int64(changedN), 1)
-		f.opN += changedN
 	}
+	offset, ok := maxColumnOffset(f.storage, rowIDs, f.shardWidth)
+	return f.shard*f.shardWidth + offset, ok
+}
 
-	if len(clear) > 0 {
-		f.stats.Count("ClearingN", int64(len(clear)), 1)
-		changedN, err := f.storage.RemoveN(clear...)
-		if err != nil {
-			return errors.Wrap(err, "clearing positions")
+// maxColumnOffset returns the highest column offset set in any of the given
+// rows of bm.
+func maxColumnOffset(bm *roaring.Bitmap, rowIDs []uint64, shardWidth uint64) (max uint64, ok bool) {
+	for _, rowID := range rowIDs {
+		row := bm.OffsetRange(0, rowID*shardWidth, (rowID+1)*shardWidth)
+		if !row.Any() {
+			continue
+		}
+		if offset := row.Max(); !ok || offset > max {
+			max, ok = offset, true
 		}
-		f.stats.Count("ClearedN", int64(changedN), 1)
-		f.opN += changedN
 	}
+	return max, ok
+}
 
-	// Update cache counts for all affected rows.
-	for rowID := range rowSet {
-		// Invalidate block checksum.
-		delete(f.checksums, int(rowID/HashBlockSize))
+// deferCacheRebuild returns true if cache counts for imported rows should be
+// recalculated in the background rather than during the import.
+func (f *fragment) deferCacheRebuild() bool {
+	return f.cacheRebuilder != nil && f.CacheType != CacheTypeNone
+}
 
-		n := f.storage.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
-		f.cache.BulkAdd(rowID, n)
+// rebuildDirtyCache brings the cached counts of all imported rows up to date
+// and persists the cache. It is called by the holder's cacheRebuilder.
+func (f *fragment) rebuildDirtyCache() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-		if smallWrite {
-			if _, ok := f.rowCache.Fetch(rowID); ok { // we won't update the rowCache if it wasn't already in there.
-				f.rowCache.Add(rowID, f.rowFromStorage(rowID))
-			}
-		}
+	// Ignore if the fragment was closed before the rebuild started.
+	if f.storageData == nil {
+		return
 	}
+	f.applyDirtyRows()
 
-	f.cache.Recalculate()
-
-	if !smallWrite {
-		return f.snapshot()
+	if err := f.flushCache(); err != nil {
+		f.Logger.Errorf("fragment: flushing cache after rebuild: err=%s, path=%s", err, f.path)
 	}
-	return nil
 }
 
 // bulkImportMutex performs a bulk import on a fragment while ensuring
 // mutex restrictions. Because the mutex requirements must be checked
 // against storage, this method must acquire a write lock on the fragment
 // during the entire process, and it handles every bit independently.
-func (f *fragment) bulkImportMutex(rowIDs, columnIDs []uint64) error {
+func (f *fragment) bulkImportMutex(rowIDs, columnIDs []uint64) (n, changed int, err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -1611,6 +2702,9 @@ func (f *fragment) bulkImportMutex(rowIDs, columnIDs []uint64) error {
 	// we don't end up setting multiple bits in the same column if a column is
 	// repeated within the import.
 	colSet := make(map[uint64]uint64)
+	// columns holds every column imported, including those already set to
+	// their imported row, so that each is counted once.
+	columns := make(map[uint64]struct{})
 
 	// Since each imported bit will at most set one bit and clear one bit, we
 	// can reuse the rowIDs and columnIDs slices as the set and clear slice
@@ -1621,13 +2715,14 @@ func (f *fragment) bulkImportMutex(rowIDs, columnIDs []uint64) error {
 	clearIdx := 0
 	for i := range rowIDs {
 		rowID, columnID := rowIDs[i], columnIDs[i]
+		columns[columnID] = struct{}{}
 		if existingRowID, found, err := f.mutexVector.Get(columnID); err != nil {
-			return errors.Wrap(err, "getting mutex vector data")
+			return 0, 0, errors.Wrap(err, "getting mutex vector data")
 		} else if found && existingRowID != rowID {
 			// Determine the position of the bit in the storage.
 			clearPos, err := f.pos(existingRowID, columnID)
 			if err != nil {
-				return err
+				return 0, 0, err
 			}
 			columnIDs[clearIdx] = clearPos
 			clearIdx++
@@ -1638,7 +2733,7 @@ func (f *fragment) bulkImportMutex(rowIDs, columnIDs []uint64) error {
 		}
 		pos, err := f.pos(rowID, columnID)
 		if err != nil {
-			return err
+			return 0, 0, err
 		}
 		colSet[columnID] = pos
 		rowSet[rowID] = struct{}{}
@@ -1653,7 +2748,8 @@ func (f *fragment) bulkImportMutex(rowIDs, columnIDs []uint64) error {
 	toSet := rowIDs[:i]
 	toClear := columnIDs[:clearIdx]
 
-	return errors.Wrap(f.importPositions(toSet, toClear, rowSet), "importing positions")
+	changed, _, err = f.importPositions(toSet, toClear, rowSet)
+	return len(columns), changed, errors.Wrap(err, "importing positions")
 }
 
 // importValue bulk imports a set of range-encoded values.
@@ -1663,7 +2759,8 @@ func (f *fragment) importValue(columnIDs, values []uint64, bitDepth uint, clear
 		return fmt.Errorf("mismatch of column/value len: %d != %d", len(columnIDs), len(values))
 	}
 	var toSet, toClear []uint64
-	f.mu.RLock()
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	smallWrite := false
 	if len(columnIDs)*int(bitDepth+1)+f.opN < f.MaxOpN {
 		smallWrite = true
@@ -1675,11 +2772,8 @@ func (f *fragment) importValue(columnIDs, values []uint64, bitDepth uint, clear
 		toSet = make([]uint64, 0, len(columnIDs)*int(bitDepth+1)*(5/8))
 		toClear = make([]uint64, 0, len(columnIDs)*int(bitDepth+1)*(5/8))
 	}
-	f.mu.RUnlock()
 
 	if !smallWrite {
-		f.mu.Lock()
-		defer f.mu.Unlock()
 		f.storage.OpWriter = nil
 	}
 	// Process every value.
@@ -1708,7 +2802,7 @@ func (f *fragment) importValue(columnIDs, values []uint64, bitDepth uint, clear
 		for i := uint(0); i < bitDepth+1; i++ {
 			rowSet[uint64(i)] = struct{}{}
 		}
-		err := f.importPositions(toSet, toClear, rowSet)
+		_, _, err := f.importPositions(toSet, toClear, rowSet)
 		return errors.Wrap(err, "importing positions")
 	}
 	err := f.snapshot()
@@ -1726,7 +2820,29 @@ func (f *fragment) importRoaring(data []byte, clear bool) error {
 	if err != nil {
 		return err
 	}
+	return f.unprotectedImportRoaring(bm, clear)
+}
+
+// importRoaringRows unions, or clears, each bitmap of column offsets into the
+// row with the same ID. The bitmaps are moved into place a container at a
+// time, so their bits are never iterated. Offsets must be within the shard width.
+func (f *fragment) importRoaringRows(rows map[uint64]*roaring.Bitmap, clear bool) error {
+	bm := roaring.NewBitmap()
+	for rowID, row := range rows {
+		if row.Max() >= f.shardWidth {
+			return errors.Errorf("row %d: column offset %d is not within the shard width %d", rowID, row.Max(), f.shardWidth)
+		}
+		bm.UnionInPlace(row.OffsetRange(rowID*f.shardWidth, 0, f.shardWidth))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.unprotectedImportRoaring(bm, clear)
+}
 
+// unprotectedImportRoaring unions, or clears, bm into the fragment's storage
+// and updates the cache counts of the rows it touches.
+func (f *fragment) unprotectedImportRoaring(bm *roaring.Bitmap, clear bool) error {
 	// get a list of keys in order to update the cache
 	iter, _ := bm.Containers.Iterator(0)
 	rowSet := make([]uint64, 0)
@@ -1736,7 +2852,7 @@ func (f *fragment) importRoaring(data []byte, clear bool) error {
 		key, _ := iter.Value()
 
 		// virtual row for the current container
-		vRow := key >> shardVsContainerExponent
+		vRow := key >> f.containerExponent()
 
 		// skip dups
 		if vRow == lastRow {
@@ -1746,30 +2862,151 @@ func (f *fragment) importRoaring(data []byte, clear bool) error {
 		lastRow = vRow
 	}
 
+	before := f.storage.Count()
 	if clear {
 		bm = f.storage.Difference(bm)
+		f.writeStats.observe(0, int(before-bm.Count()))
 	} else {
-		if f.storage.Count() > 0 {
+		f.observeRows(bm, rowSet)
+		if before > 0 {
 			bm = f.storage.Union(bm)
 		}
+		f.writeStats.observe(int(bm.Count()-before), 0)
 	}
 
-	for _, rowID := range rowSet {
-		n := bm.CountRange(rowID*ShardWidth, (rowID+1)*ShardWidth)
-		f.cache.BulkAdd(rowID, n)
+	if f.deferCacheRebuild() {
+		for _, rowID := range rowSet {
+			f.dirtyRows[rowID] = struct{}{}
+		}
+		f.cacheRebuilder.enqueue(f)
+	} else {
+		for _, rowID := range rowSet {
+			n := bm.CountRange(rowID*f.shardWidth, (rowID+1)*f.shardWidth)
+			f.cache.BulkAdd(rowID, n)
+		}
+		f.cache.Recalculate()
 	}
-	f.cache.Recalculate()
 
-	err = unprotectedWriteToFragment(f, bm)
-	return err
+	return unprotectedWriteToFragment(f, bm)
+}
+
+// marshalStorage returns the fragment's storage in pilosa's roaring format.
+func (f *fragment) marshalStorage() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var buf bytes.Buffer
+	if _, err := f.storage.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BitCount returns the number of bits set in the fragment.
+func (f *fragment) BitCount() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.bitN
+}
+
+// RowCount returns the number of rows of the fragment with any bits set.
+func (f *fragment) RowCount() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.rowN
+}
+
+// rowEmpty returns true if a row has no bits set. It reads only the
+// cardinalities of the row's containers, up to the first with any bits.
+func (f *fragment) rowEmpty(rowID uint64) bool {
+	exp := f.containerExponent()
+	itr, _ := f.storage.Containers.Iterator(rowID << exp)
+	for itr.Next() {
+		key, c := itr.Value()
+		if key>>exp != rowID {
+			return true
+		} else if c.N() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// countStorage rebuilds the bit and row counts of the fragment from the
+// cardinalities of its containers, without reading their bits. The
+// containers of a row are adjacent, so each row is counted once.
+func (f *fragment) countStorage() {
+	f.bitN, f.rowN = 0, 0
+	exp := f.containerExponent()
+	lastRowID := uint64(math.MaxUint64)
+	itr, _ := f.storage.Containers.Iterator(0)
+	for itr.Next() {
+		key, c := itr.Value()
+		if c.N() == 0 {
+			continue
+		}
+		f.bitN += uint64(c.N())
+		if rowID := key >> exp; rowID != lastRowID {
+			f.rowN++
+			lastRowID = rowID
+		}
+	}
+}
+
+// FragmentStats holds statistics about a fragment on this node.
+type FragmentStats struct {
+	Shard uint64 `json:"shard"`
+
+	// Whether the storage file is currently open and mapped.
+	Open bool `json:"open"`
+
+	// The number of bits set, and of rows with any bits set.
+	BitCount uint64 `json:"bitCount"`
+	RowCount uint64 `json:"rowCount"`
+
+	// The size of the storage file, and the number of roaring containers
+	// and cached row counts held in memory.
+	FileBytes    int64 `json:"fileBytes"`
+	Containers   int   `json:"containers"`
+	CacheEntries int   `json:"cacheEntries"`
+
+	// The time of the last snapshot written since the fragment was opened.
+	SnapshotAt *time.Time `json:"snapshotAt,omitempty"`
+}
+
+// statistics returns the current statistics of the fragment.
+func (f *fragment) statistics() *FragmentStats {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	s := &FragmentStats{
+		Shard:     f.shard,
+		Open:      f.storageData != nil,
+		BitCount:  f.bitN,
+		RowCount:  f.rowN,
+		FileBytes: fileSize(f.path),
+	}
+	if f.storage != nil {
+		s.Containers = f.storage.Containers.Size()
+	}
+	if f.cache != nil {
+		s.CacheEntries = f.cache.Len()
+	}
+	if !f.snapshotAt.IsZero() {
+		t := f.snapshotAt
+		s.SnapshotAt = &t
+	}
+	return s
 }
 
 // incrementOpN increase the operation count by one.
-// If the count exceeds the maximum allowed then a snapshot is performed.
+// If the count exceeds the maximum allowed then a snapshot is performed, by
+// the holder's snapshot queue if there is one.
 func (f *fragment) incrementOpN() error {
 	f.opN++
 	if f.opN <= f.MaxOpN {
 		return nil
+	} else if f.snapshotQueue.enqueue(f) {
+		return nil
 	}
 
 	if err := f.snapshot(); err != nil {
@@ -1784,10 +3021,62 @@ func (f *fragment) Snapshot() error {
 	defer f.mu.Unlock()
 	return f.snapshot()
 }
+
+// snapshotQueued snapshots a fragment taken off the snapshot queue, unless it
+// was snapshotted or closed since it was queued, which resets its op count.
+// It returns true if the fragment was snapshotted.
+func (f *fragment) snapshotQueued() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.opN <= f.MaxOpN {
+		return false
+	} else if err := f.snapshot(); err != nil {
+		f.Logger.Errorf("fragment: queued snapshot: err=%s, path=%s", err, f.path)
+		return false
+	}
+	return true
+}
+
+// Compact rewrites the data file as a snapshot, which folds in the op log and
+// drops empty containers, and returns the number of bytes reclaimed. It holds
+// the fragment lock like every other snapshot, so two rewrites of a fragment
+// never run at once.
+func (f *fragment) Compact() (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.readerMu.Lock()
+	closing := f.closing
+	f.readerMu.Unlock()
+	if closing {
+		return 0, ErrFragmentClosing
+	}
+
+	before, err := os.Stat(f.path)
+	if err != nil {
+		return 0, errors.Wrap(err, "statting data file")
+	}
+	if err := f.snapshot(); err != nil {
+		return 0, err
+	}
+	after, err := os.Stat(f.path)
+	if err != nil {
+		return 0, errors.Wrap(err, "statting data file")
+	}
+
+	reclaimed := before.Size() - after.Size()
+	if reclaimed < 0 {
+		reclaimed = 0
+	}
+	f.stats.Count("fragment.compact.reclaimed_bytes", reclaimed, 1.0)
+	return reclaimed, nil
+}
+
 func track(start time.Time, message string, stats stats.StatsClient, logger logger.Logger) {
 	elapsed := time.Since(start)
 	logger.Printf("%s took %s", message, elapsed)
 	stats.Histogram("snapshot", elapsed.Seconds(), 1.0)
+	stats.Timing("fragment.snapshot.duration", elapsed, 1.0)
 }
 
 func (f *fragment) snapshot() error {
@@ -1797,6 +3086,9 @@ func (f *fragment) snapshot() error {
 // unprotectedWriteToFragment writes the fragment f with bm as the data. It is unprotected, and
 // f.mu must be locked when calling it.
 func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) error { // nolint: interfacer
+	if f.readOnly {
+		return newForbiddenError(ErrReadOnly)
+	}
 
 	completeMessage := fmt.Sprintf("fragment: snapshot complete %s/%s/%s/%d", f.index, f.field, f.view, f.shard)
 	start := time.Now()
@@ -1820,6 +3112,11 @@ func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) error { // noli
 		return fmt.Errorf("flush: %s", err)
 	}
 
+	// Ensure the snapshot is durable before it replaces the data file.
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync snapshot: %s", err)
+	}
+
 	// Close current storage.
 	if err := f.closeStorage(); err != nil {
 		return fmt.Errorf("close storage: %s", err)
@@ -1837,6 +3134,21 @@ func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) error { // noli
 
 	// Reset operation count.
 	f.opN = 0
+	f.snapshotAt = time.Now()
+	f.snapshotQueue.done(f)
+
+	// Persist the cache against the new storage file. This must only happen
+	// once the rename has succeeded: a crash at any earlier point leaves the
+	// previous cache file, whose checksum can't match the new storage, and a
+	// failure here only means the cache is rebuilt on the next open.
+	//
+	// If a background rebuild is pending then it persists the cache once
+	// done, rather than the import paying for the recalculation here.
+	if f.cacheRebuilder.queued(f) {
+		return nil
+	} else if err := f.flushCache(); err != nil {
+		f.Logger.Errorf("fragment: flushing cache after snapshot: err=%s, path=%s", err, f.path)
+	}
 
 	return nil
 }
@@ -1844,10 +3156,134 @@ func unprotectedWriteToFragment(f *fragment, bm *roaring.Bitmap) error { // noli
 // RecalculateCache rebuilds the cache regardless of invalidate time delay.
 func (f *fragment) RecalculateCache() {
 	f.mu.Lock()
+	f.applyDirtyRows()
 	f.cache.Recalculate()
 	f.mu.Unlock()
 }
 
+// rebuildCacheInBackground replaces the cache with one rebuilt from storage
+// in the background, as when the fragment is opened without a valid cache
+// file.
+func (f *fragment) rebuildCacheInBackground() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.storageData == nil || f.CacheType == CacheTypeNone {
+		return nil
+	}
+	c, err := f.newCache()
+	if err != nil {
+		return err
+	}
+	f.cache = c
+	f.dirtyRows = make(map[uint64]struct{})
+	f.cacheRebuilder.done(f)
+	f.scheduleCacheRebuild(nil)
+	return nil
+}
+
+// cachePairs returns the row counts currently held in the cache, ordered by
+// count for ranked caches.
+func (f *fragment) cachePairs() []Pair {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.applyDirtyRows()
+
+	bps := f.cache.Top()
+	pairs := make([]Pair, len(bps))
+	for i, bp := range bps {
+		pairs[i] = Pair{ID: bp.ID, Count: bp.Count}
+	}
+	return pairs
+}
+
+// cacheLen returns the number of rows held in the cache.
+func (f *fragment) cacheLen() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.applyDirtyRows()
+	return f.cache.Len()
+}
+
+// fragmentCacheStats holds in-memory statistics about a fragment's cache.
+type fragmentCacheStats struct {
+	rows      int
+	threshold uint64
+	scans     uint64
+}
+
+// cacheStats returns statistics about the cache without reading storage.
+// The threshold is the count of the lowest ranked cached row.
+func (f *fragment) cacheStats() fragmentCacheStats {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	stats := fragmentCacheStats{
+		rows:  f.cache.Len(),
+		scans: atomic.LoadUint64(&f.cacheScans),
+	}
+	if top := f.cache.Top(); len(top) > 0 {
+		stats.threshold = top[len(top)-1].Count
+	}
+	return stats
+}
+
+// cacheLastUsed returns the time the cache last served a TopN, in unix
+// nanoseconds, or zero if it has never been used.
+func (f *fragment) cacheLastUsed() int64 {
+	return atomic.LoadInt64(&f.cacheUsed)
+}
+
+// shrinkCache evicts the least valuable cache entries until no more than n
+// remain.
+func (f *fragment) shrinkCache(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cache.Shrink(n)
+}
+
+// setCacheSize changes the size of the fragment's cache, evicting the rows
+// beyond it. TopN calls already reading the cache keep the rows they read.
+// A rebuild in the background is started again at the new size, as the rows
+// it has counted so far were kept to the old one.
+func (f *fragment) setCacheSize(n uint32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.CacheSize = n
+	f.cache.SetMaxEntries(n)
+	if f.cacheWarming {
+		f.scheduleCacheRebuild(nil)
+	}
+}
+
+// resetCache drops all cached row counts and rebuilds the cache from storage
+// in the background, like rebuildCacheInBackground. Any rebuild already
+// running is superseded. Callers which need the rebuilt cache wait on
+// f.cacheRebuild.
+func (f *fragment) resetCache() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.newCache()
+	if err != nil {
+		return err
+	}
+	f.cache = c
+	f.dirtyRows = make(map[uint64]struct{})
+
+	// Stop any rebuild in the background, which would otherwise replace the
+	// new cache with its own.
+	f.cacheWarming = false
+	f.cacheWarmGen++
+
+	if f.storageData == nil || f.CacheType == CacheTypeNone {
+		return nil
+	}
+	f.cacheRebuilder.done(f)
+	f.scheduleCacheRebuild(nil)
+	return nil
+}
+
 // FlushCache writes the cache data to disk.
 func (f *fragment) FlushCache() error {
 	f.mu.Lock()
@@ -1860,27 +3296,63 @@ func (f *fragment) flushCache() error {
 		return nil
 	}
 
-	if f.CacheType == CacheTypeNone {
+	// A cache being rebuilt holds only some of the rows in storage. The
+	// existing file is left as it is, to be found stale when it's next read.
+	if f.CacheType == CacheTypeNone || f.readOnly || f.cacheWarming {
 		return nil
 	}
 
-	// Retrieve a list of row ids from the cache.
+	// Persist up to date counts for rows written while rank maintenance
+	// was deferred.
+	f.applyDirtyRows()
+
+	// Retrieve a list of row ids and their counts from the cache.
 	ids := f.cache.IDs()
+	counts := make([]uint64, len(ids))
+	for i, id := range ids {
+		counts[i] = f.cache.Get(id)
+	}
+
+	// Tie the cache to the current state of storage.
+	checksum, err := f.storageChecksum()
+	if err != nil {
+		return errors.Wrap(err, "computing storage checksum")
+	}
 
 	// Marshal cache data to bytes.
-	buf, err := proto.Marshal(&internal.Cache{IDs: ids})
+	buf, err := encodeCacheFile(checksum, ids, counts)
 	if err != nil {
-		return errors.Wrap(err, "marshalling")
+		return errors.Wrap(err, "encoding")
 	}
 
-	// Write to disk.
-	if err := ioutil.WriteFile(f.cachePath(), buf, 0666); err != nil {
+	// Write to a temporary file and move it into place so that a crash
+	// never leaves a partially written cache file behind.
+	tmpPath := f.cachePath() + snapshotExt
+	if err := writeFileSync(tmpPath, buf); err != nil {
 		return errors.Wrap(err, "writing")
+	} else if err := os.Rename(tmpPath, f.cachePath()); err != nil {
+		return errors.Wrap(err, "renaming")
 	}
 
 	return nil
 }
 
+// writeFileSync writes data to a new file at path and syncs it to disk.
+func writeFileSync(path string, data []byte) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	} else if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
 // WriteTo writes the fragment's data to w.
 func (f *fragment) WriteTo(w io.Writer) (n int64, err error) {
 	// Force cache flush.
@@ -2075,10 +3547,10 @@ func filterWithLimit(limit uint64) rowFilter {
 	}
 }
 
-func filterColumn(col uint64) rowFilter {
+func filterColumn(col, shardWidth uint64) rowFilter {
 	return func(rowID, key uint64, c *roaring.Container) (include, done bool) {
-		colID := col % ShardWidth
-		colKey := ((rowID * ShardWidth) + colID) >> 16
+		colID := col % shardWidth
+		colKey := ((rowID * shardWidth) + colID) >> 16
 		colVal := uint16(colID & 0xFFFF) // columnID within the container
 		return colKey == key && c.Contains(colVal), false
 	}
@@ -2122,7 +3594,7 @@ func filterWithRows(rows []uint64) rowFilter {
 // this container have been processed. The rows accumulated up to this point
 // (including this row if all filters passed) will be returned.
 func (f *fragment) rows(start uint64, filters ...rowFilter) []uint64 {
-	startKey := rowToKey(start)
+	startKey := rowToKey(start, f.shardWidth)
 	i, _ := f.storage.Containers.Iterator(startKey)
 	rows := make([]uint64, 0)
 	var lastRow uint64 = math.MaxUint64
@@ -2132,7 +3604,7 @@ func (f *fragment) rows(start uint64, filters ...rowFilter) []uint64 {
 		key, c := i.Value()
 
 		// virtual row for the current container
-		vRow := key >> shardVsContainerExponent
+		vRow := key >> f.containerExponent()
 
 		// skip dups
 		if vRow == lastRow {
@@ -2203,6 +3675,13 @@ type FragmentBlock struct {
 	Checksum []byte `json:"checksum"`
 }
 
+// FragmentChecksum is the checksum of the bits of a fragment. Fragments
+// with the same bits have the same checksum, whatever their storage layout.
+type FragmentChecksum struct {
+	Shard    uint64 `json:"shard"`
+	Checksum []byte `json:"checksum"`
+}
+
 type blockHasher struct {
 	blockID int
 	buf     [8]byte
@@ -2235,9 +3714,20 @@ type fragmentSyncer struct {
 	Node    *Node
 	Cluster *cluster
 
+	// Limits the rate of requests to other nodes. Nil doesn't limit them.
+	Limiter *syncLimiter
+
+	// The number of pairs read from another node in each request for a
+	// block's data. Zero uses blockDataPageSize.
+	PageSize int
+
 	Closing <-chan struct{}
 }
 
+// blockDataPageSize is the default number of pairs read from another node in
+// each request for a block's data.
+const blockDataPageSize = 1 << 18
+
 // isClosing returns true if the closing channel is closed.
 func (s *fragmentSyncer) isClosing() bool {
 	select {
@@ -2271,6 +3761,9 @@ func (s *fragmentSyncer) syncFragment() error {
 		}
 
 		// Retrieve remote blocks.
+		if !s.Limiter.wait(s.Closing) {
+			return nil
+		}
 		blocks, err := s.Cluster.InternalClient.FragmentBlocks(ctx, &node.URI, s.Fragment.index, s.Fragment.field, s.Fragment.view, s.Fragment.shard)
 		if err != nil && err != ErrFragmentNotFound {
 			return errors.Wrap(err, "getting blocks")
@@ -2285,6 +3778,8 @@ func (s *fragmentSyncer) syncFragment() error {
 
 	// Iterate over all blocks and find differences.
 	checksums := make([][]byte, len(nodes))
+	var compared int64
+	defer func() { s.Fragment.stats.Count("BlockCompared", compared, 1.0) }()
 	for {
 		// Find min block id.
 		blockID := -1
@@ -2300,6 +3795,7 @@ func (s *fragmentSyncer) syncFragment() error {
 		if blockID == -1 {
 			break
 		}
+		compared++
 
 		// Read the checksum for the current block.
 		for i, blocks := range blockSets {
@@ -2328,89 +3824,160 @@ func (s *fragmentSyncer) syncFragment() error {
 	return nil
 }
 
+// remoteBlock holds the pairs read from a block of another node which
+// haven't been merged yet.
+type remoteBlock struct {
+	uri    *URI
+	pairs  pairSet
+	offset int  // offset of the next page
+	more   bool // true if the node has pairs after those read
+}
+
 // syncBlock sends and receives all rows for a given block.
 // Returns an error if any remote hosts are unreachable.
+//
+// The remote blocks are read a page at a time, and merged up to the last
+// pair read from any node with more pairs, so that a large block is never
+// held in memory at once.
 func (s *fragmentSyncer) syncBlock(id int) error {
 	span, ctx := tracing.StartSpanFromContext(context.Background(), "FragmentSyncer.syncBlock")
 	defer span.Finish()
 
 	f := s.Fragment
+	pageSize := s.PageSize
+	if pageSize <= 0 {
+		pageSize = blockDataPageSize
+	}
 
-	// Read pairs from each remote block.
-	var uris []*URI
-	var pairSets []pairSet
+	var remotes []*remoteBlock
 	for _, node := range s.Cluster.shardNodes(f.index, f.shard) {
 		if s.Node.ID == node.ID {
 			continue
 		}
+		remotes = append(remotes, &remoteBlock{uri: &node.URI, more: true})
+	}
+
+	from := uint64(id) * HashBlockSize * s.Fragment.shardWidth
+	end := uint64(id+1)*HashBlockSize*s.Fragment.shardWidth - 1
+	for {
+		// Read the next page from each node whose pairs have all been merged.
+		for _, r := range remotes {
+			if len(r.pairs.rowIDs) > 0 || !r.more {
+				continue
+			}
+
+			// Verify sync is not prematurely closing.
+			if !s.Limiter.wait(s.Closing) || s.isClosing() {
+				return nil
+			}
+
+			// Only sync the standard block.
+			rowIDs, columnIDs, more, err := s.Cluster.InternalClient.BlockData(ctx, r.uri, f.index, f.field, f.view, f.shard, id, r.offset, pageSize)
+			if err != nil {
+				return errors.Wrap(err, "getting block")
+			} else if len(rowIDs) != len(columnIDs) {
+				return fmt.Errorf("pair set mismatch: %d != %d", len(rowIDs), len(columnIDs))
+			}
+			r.pairs = pairSet{rowIDs: rowIDs, columnIDs: columnIDs}
+			r.offset += len(rowIDs)
+			r.more = more && len(rowIDs) > 0
+		}
+
+		// Merge up to the last pair read from any node with more pairs.
+		to := end
+		for _, r := range remotes {
+			if n := len(r.pairs.rowIDs); r.more && n > 0 {
+				if pos := r.pairs.rowIDs[n-1]*s.Fragment.shardWidth + r.pairs.columnIDs[n-1]; pos < to {
+					to = pos
+				}
+			}
+		}
+
+		data := make([]pairSet, len(remotes))
+		for i, r := range remotes {
+			n := sort.Search(len(r.pairs.rowIDs), func(j int) bool {
+				return r.pairs.rowIDs[j]*s.Fragment.shardWidth+r.pairs.columnIDs[j] > to
+			})
+			data[i] = pairSet{rowIDs: r.pairs.rowIDs[:n], columnIDs: r.pairs.columnIDs[:n]}
+			r.pairs = pairSet{rowIDs: r.pairs.rowIDs[n:], columnIDs: r.pairs.columnIDs[n:]}
+		}
+
+		// A node written to since its last page can return pairs which were
+		// already merged; read on past them.
+		if to < from {
+			continue
+		}
 
 		// Verify sync is not prematurely closing.
 		if s.isClosing() {
 			return nil
 		}
 
-		uri := &node.URI
-		uris = append(uris, uri)
-
-		// Only sync the standard block.
-		rowIDs, columnIDs, err := s.Cluster.InternalClient.BlockData(ctx, &node.URI, f.index, f.field, f.view, f.shard, id)
+		// Merge blocks together.
+		sets, clears, err := f.mergeBlock(from, to, data)
 		if err != nil {
-			return errors.Wrap(err, "getting block")
+			return errors.Wrap(err, "merging")
 		}
 
-		pairSets = append(pairSets, pairSet{
-			columnIDs: columnIDs,
-			rowIDs:    rowIDs,
-		})
-	}
-
-	// Verify sync is not prematurely closing.
-	if s.isClosing() {
-		return nil
-	}
+		// Write updates to remote blocks. The updates are all before the
+		// next page of each node, so they move its offset.
+		for i, r := range remotes {
+			if err := s.importBlockDiff(ctx, r.uri, sets[i], clears[i]); err != nil {
+				return err
+			} else if s.isClosing() {
+				return nil
+			}
+			r.offset += len(sets[i].rowIDs) - len(clears[i].rowIDs)
+		}
 
-	// Merge blocks together.
-	sets, clears, err := f.mergeBlock(id, pairSets)
-	if err != nil {
-		return errors.Wrap(err, "merging")
+		if to == end {
+			return nil
+		}
+		from = to + 1
 	}
+}
 
-	// Write updates to remote blocks.
-	for i := 0; i < len(uris); i++ {
-		set, clear := sets[i], clears[i]
+// importBlockDiff sets and clears bits of the fragment on another node.
+func (s *fragmentSyncer) importBlockDiff(ctx context.Context, uri *URI, set, clear pairSet) error {
+	f := s.Fragment
 
-		// Handle Sets.
-		if len(set.columnIDs) > 0 {
-			setData, err := bitsToRoaringData(set)
-			if err != nil {
-				return errors.Wrap(err, "converting bits to roaring data (set)")
-			}
+	// Handle Sets.
+	if len(set.columnIDs) > 0 {
+		setData, err := bitsToRoaringData(set, s.Fragment.shardWidth)
+		if err != nil {
+			return errors.Wrap(err, "converting bits to roaring data (set)")
+		}
 
-			setReq := &ImportRoaringRequest{
-				Clear: false,
-				Views: map[string][]byte{cleanViewName(f.view): setData},
-			}
+		setReq := &ImportRoaringRequest{
+			Clear: false,
+			Views: map[string][]byte{cleanViewName(f.view): setData},
+		}
 
-			if err := s.Cluster.InternalClient.ImportRoaring(ctx, uris[i], f.index, f.field, f.shard, true, setReq); err != nil {
-				return errors.Wrap(err, "sending roaring data (set)")
-			}
+		if !s.Limiter.wait(s.Closing) {
+			return nil
+		}
+		if err := s.Cluster.InternalClient.ImportRoaring(ctx, uri, f.index, f.field, f.shard, true, setReq); err != nil {
+			return errors.Wrap(err, "sending roaring data (set)")
 		}
+	}
 
-		// Handle Clears.
-		if len(clear.columnIDs) > 0 {
-			clearData, err := bitsToRoaringData(clear)
-			if err != nil {
-				return errors.Wrap(err, "converting bits to roaring data (clear)")
-			}
+	// Handle Clears.
+	if len(clear.columnIDs) > 0 {
+		clearData, err := bitsToRoaringData(clear, s.Fragment.shardWidth)
+		if err != nil {
+			return errors.Wrap(err, "converting bits to roaring data (clear)")
+		}
 
-			clearReq := &ImportRoaringRequest{
-				Clear: true,
-				Views: map[string][]byte{"": clearData},
-			}
+		clearReq := &ImportRoaringRequest{
+			Clear: true,
+			Views: map[string][]byte{"": clearData},
+		}
 
-			if err := s.Cluster.InternalClient.ImportRoaring(ctx, uris[i], f.index, f.field, f.shard, true, clearReq); err != nil {
-				return errors.Wrap(err, "sending roaring data (clear)")
-			}
+		if !s.Limiter.wait(s.Closing) {
+			return nil
+		}
+		if err := s.Cluster.InternalClient.ImportRoaring(ctx, uri, f.index, f.field, f.shard, true, clearReq); err != nil {
+			return errors.Wrap(err, "sending roaring data (clear)")
 		}
 	}
 
@@ -2434,11 +4001,11 @@ func cleanViewName(v string) string {
 }
 
 // bitsToRoaringData converts a pairSet into a roaring.Bitmap
-// which represents the data within a single shard.
-func bitsToRoaringData(ps pairSet) ([]byte, error) {
+// which represents the data within a single shard of the given width.
+func bitsToRoaringData(ps pairSet, shardWidth uint64) ([]byte, error) {
 	bmp := roaring.NewBitmap()
 	for j := 0; j < len(ps.columnIDs); j++ {
-		bmp.DirectAdd(ps.rowIDs[j]*ShardWidth + (ps.columnIDs[j] % ShardWidth))
+		bmp.DirectAdd(ps.rowIDs[j]*shardWidth + (ps.columnIDs[j] % shardWidth))
 	}
 
 	var buf bytes.Buffer
@@ -2478,9 +4045,10 @@ func byteSlicesEqual(a [][]byte) bool {
 	return true
 }
 
-// pos returns the row position of a row/column pair.
-func pos(rowID, columnID uint64) uint64 {
-	return (rowID * ShardWidth) + (columnID % ShardWidth)
+// pos returns the row position of a row/column pair in a shard of the given
+// width.
+func pos(rowID, columnID, shardWidth uint64) uint64 {
+	return (rowID * shardWidth) + (columnID % shardWidth)
 }
 
 // vector stores the mapping of colID to rowID.
@@ -2506,7 +4074,7 @@ func newRowsVector(f *fragment) *rowsVector {
 // Additionally, it returns true if a value was found,
 // otherwise it returns false.
 func (v *rowsVector) Get(colID uint64) (uint64, bool, error) {
-	rows := v.f.rows(0, filterColumn(colID))
+	rows := v.f.rows(0, filterColumn(colID, v.f.shardWidth))
 	if len(rows) > 1 {
 		return 0, false, errors.New("found multiple row values for column")
 	} else if len(rows) == 1 {
@@ -2518,8 +4086,8 @@ func (v *rowsVector) Get(colID uint64) (uint64, bool, error) {
 // rowToKey converts a Pilosa row ID to the key of the container which starts
 // that row in the bitmap which represents this entire fragment. A fragment is
 // all the rows within a shard within a field concatenated together.
-func rowToKey(rowID uint64) (key uint64) {
-	return rowID * (ShardWidth / containerWidth)
+func rowToKey(rowID, shardWidth uint64) (key uint64) {
+	return rowID * (shardWidth / containerWidth)
 }
 
 // boolVector implements the vector interface by looking
@@ -2539,7 +4107,7 @@ func newBoolVector(f *fragment) *boolVector {
 // Additionally, it returns true if a value was found,
 // otherwise it returns false.
 func (v *boolVector) Get(colID uint64) (uint64, bool, error) {
-	rows := v.f.rows(0, filterColumn(colID))
+	rows := v.f.rows(0, filterColumn(colID, v.f.shardWidth))
 	if len(rows) > 1 {
 		return 0, false, errors.New("found multiple row values for column")
 	} else if len(rows) == 1 {
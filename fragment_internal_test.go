@@ -16,6 +16,7 @@ package pilosa
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -25,12 +26,16 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/cespare/xxhash"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/pilosa/pilosa/logger"
 	"github.com/pilosa/pilosa/pql"
 	"github.com/pilosa/pilosa/roaring"
 )
@@ -381,6 +386,38 @@ func TestFragment_Sum(t *testing.T) {
 	})
 }
 
+// Ensure a fragment sums values using every bit of the bit depth.
+func TestFragment_Sum_BitDepthLimit(t *testing.T) {
+	const bitDepth = 16
+	const max = 1<<bitDepth - 1
+
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	if _, err := f.setValue(1, bitDepth, 0); err != nil {
+		t.Fatal(err)
+	} else if _, err := f.setValue(2, bitDepth, max); err != nil {
+		t.Fatal(err)
+	} else if _, err := f.setValue(3, bitDepth, max); err != nil {
+		t.Fatal(err)
+	}
+	if sum, n, err := f.sum(nil, bitDepth); err != nil {
+		t.Fatal(err)
+	} else if n != 3 || sum != 2*max {
+		t.Fatalf("unexpected sum: %d, count: %d", sum, n)
+	}
+
+	// Overwriting the largest value with the smallest clears all of its bits.
+	if _, err := f.setValue(3, bitDepth, 0); err != nil {
+		t.Fatal(err)
+	}
+	if sum, n, err := f.sum(nil, bitDepth); err != nil {
+		t.Fatal(err)
+	} else if n != 3 || sum != max {
+		t.Fatalf("unexpected sum: %d, count: %d", sum, n)
+	}
+}
+
 // Ensure a fragment can find the min and max of values.
 func TestFragment_MinMax(t *testing.T) {
 	const bitDepth = 16
@@ -749,7 +786,7 @@ func BenchmarkFragment_RepeatedSmallImports(b *testing.B) {
 							}
 							b.StartTimer()
 							for i := 0; i < numUpdates; i++ {
-								err := f.bulkImportStandard(
+								_, _, err := f.bulkImportStandard(
 									updateRows[bitsPerUpdate*i:bitsPerUpdate*(i+1)],
 									updateRows[bitsPerUpdate*i:bitsPerUpdate*(i+1)],
 									&ImportOptions{},
@@ -847,6 +884,274 @@ func TestFragment_Snapshot(t *testing.T) {
 	}
 }
 
+// Ensure a column range is cleared from every row, dropping the containers
+// wholly within it, and that the cache and storage reflect it.
+func TestFragment_ClearColumnRange(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 1, CacheTypeRanked)
+	defer f.Clean(t)
+
+	// Row 1 has bits in the first, second and fourth containers of the
+	// shard, and row 2 fills the second container and part of the third.
+	base := uint64(ShardWidth)
+	f.mustSetBits(1, base+1, base+2, base+70000, base+200000)
+	var rowIDs, columnIDs []uint64
+	for col := base + 65536; col < base+131172; col++ {
+		rowIDs, columnIDs = append(rowIDs, 2), append(columnIDs, col)
+	}
+	rowIDs, columnIDs = append(rowIDs, 2), append(columnIDs, base+140001)
+	if err := f.bulkImport(rowIDs, columnIDs, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	f.RecalculateCache()
+
+	// Ranges outside the shard are ignored.
+	if n, err := f.clearColumnRange(0, base-1); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("unexpected bits cleared: %d", n)
+	}
+
+	if n, err := f.clearColumnRange(base+65000, base+140000); err != nil {
+		t.Fatal(err)
+	} else if n != 1+65536+100 {
+		t.Fatalf("unexpected bits cleared: %d", n)
+	}
+
+	check := func(t *testing.T) {
+		t.Helper()
+		if cols := f.row(1).Columns(); !reflect.DeepEqual(cols, []uint64{base + 1, base + 2, base + 200000}) {
+			t.Fatalf("unexpected row 1: %v", cols)
+		} else if cols := f.row(2).Columns(); !reflect.DeepEqual(cols, []uint64{base + 140001}) {
+			t.Fatalf("unexpected row 2: %v", cols)
+		}
+		if pairs, err := f.top(context.Background(), topOptions{N: 2}); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(pairs, []Pair{{ID: 1, Count: 3}, {ID: 2, Count: 1}}) {
+			t.Fatalf("unexpected pairs: %s", spew.Sdump(pairs))
+		}
+	}
+	check(t)
+
+	// The cleared range is persisted, including the dropped containers.
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check(t)
+}
+
+// Ensure a fragment's statistics reflect its storage, cache and snapshots.
+func TestFragment_Statistics(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	path := f.path
+	defer os.Remove(path)
+	defer os.Remove(f.cachePath())
+
+	f.mustSetBits(1, 1, 2)
+	f.mustSetBits(2, 1<<16)
+	if s := f.statistics(); !s.Open || s.Containers != 2 || s.CacheEntries != 2 || s.SnapshotAt != nil {
+		t.Fatalf("unexpected statistics: %+v", s)
+	}
+
+	before := time.Now()
+	if err := f.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	s := f.statistics()
+	if s.SnapshotAt == nil || s.SnapshotAt.Before(before) {
+		t.Fatalf("unexpected snapshot time: %v", s.SnapshotAt)
+	} else if fi, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if s.FileBytes != fi.Size() {
+		t.Fatalf("unexpected file size: %d != %d", s.FileBytes, fi.Size())
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	} else if s := f.statistics(); s.Open || s.Containers != 0 {
+		t.Fatalf("unexpected statistics after close: %+v", s)
+	}
+}
+
+// Ensure a fragment's row and bit counts follow its writes, and are rebuilt
+// from its storage when it's reopened.
+func TestFragment_Counts(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer os.Remove(f.path)
+	defer os.Remove(f.cachePath())
+
+	check := func(t *testing.T, rowN, bitN uint64) {
+		t.Helper()
+		if n := f.RowCount(); n != rowN {
+			t.Fatalf("unexpected row count: %d != %d", n, rowN)
+		} else if n := f.BitCount(); n != bitN {
+			t.Fatalf("unexpected bit count: %d != %d", n, bitN)
+		} else if n := f.storage.Count(); n != bitN {
+			t.Fatalf("unexpected bits in storage: %d != %d", n, bitN)
+		}
+	}
+	check(t, 0, 0)
+
+	f.mustSetBits(1, 1, 2, 1<<16)
+	f.mustSetBits(2, 1)
+	if _, err := f.setBit(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 2, 4)
+
+	if _, err := f.clearBit(2, 1); err != nil {
+		t.Fatal(err)
+	} else if _, err := f.clearBit(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 1, 3)
+
+	if err := f.bulkImport([]uint64{1, 3, 3, 4}, []uint64{2, 5, 6, 7}, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 3, 6)
+	if err := f.bulkImport([]uint64{3, 3, 4}, []uint64{5, 6, 8}, &ImportOptions{Clear: true}); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 2, 4)
+
+	if err := f.importRoaringRows(map[uint64]*roaring.Bitmap{5: roaring.NewBitmap(0, 70000)}, false); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 3, 6)
+	if _, err := f.setRow(NewRow(3, 4), 6); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 4, 8)
+	if _, err := f.clearRow(1); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 3, 5)
+	if _, err := f.clearColumnRange(0, 5); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 2, 2)
+
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 2, 2)
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	} else if f.RowCount() != 0 || f.BitCount() != 0 {
+		t.Fatalf("unexpected counts after close: %d rows, %d bits", f.RowCount(), f.BitCount())
+	}
+}
+
+// Ensure a fragment's row and bit counts stay exact while the same row is set
+// and cleared concurrently.
+func TestFragment_Counts_Concurrent(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	var eg errgroup.Group
+	for i := 0; i < 8; i++ {
+		set := i%2 == 0
+		eg.Go(func() error {
+			for j := uint64(0); j < 500; j++ {
+				var err error
+				if set {
+					_, err = f.setBit(7, j%50)
+				} else {
+					_, err = f.clearBit(7, j%50)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	bitN := f.storage.Count()
+	var rowN uint64
+	if bitN > 0 {
+		rowN = 1
+	}
+	if n := f.BitCount(); n != bitN {
+		t.Fatalf("unexpected bit count: %d != %d", n, bitN)
+	} else if n := f.RowCount(); n != rowN {
+		t.Fatalf("unexpected row count: %d != %d", n, rowN)
+	}
+
+	// Clearing the rest of the row leaves it uncounted.
+	for col := uint64(0); col < 50; col++ {
+		if _, err := f.clearBit(7, col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if f.BitCount() != 0 || f.RowCount() != 0 {
+		t.Fatalf("unexpected counts: %d rows, %d bits", f.RowCount(), f.BitCount())
+	}
+}
+
+// Ensure compacting a fragment folds in its op log and drops the containers
+// it emptied.
+func TestFragment_Compact(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	// Import a large row then clear it bit by bit, which both append to the
+	// op log.
+	rowIDs, columnIDs := make([]uint64, 4000), make([]uint64, 4000)
+	for i := range columnIDs {
+		columnIDs[i] = uint64(i) * 20
+	}
+	if err := f.bulkImport(rowIDs, columnIDs, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, columnID := range columnIDs {
+		if _, err := f.clearBit(0, columnID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.mustSetBits(1, 1)
+
+	before, err := os.Stat(f.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reclaimed, err := f.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.Stat(f.path)
+	if err != nil {
+		t.Fatal(err)
+	} else if after.Size() >= before.Size() {
+		t.Fatalf("file didn't shrink: before=%d, after=%d", before.Size(), after.Size())
+	} else if reclaimed != before.Size()-after.Size() {
+		t.Fatalf("unexpected reclaimed bytes: %d, want %d", reclaimed, before.Size()-after.Size())
+	} else if f.opN != 0 {
+		t.Fatalf("unexpected op count: %d", f.opN)
+	} else if n := f.storage.Containers.Size(); n != 1 {
+		t.Fatalf("unexpected container count: %d", n)
+	}
+
+	// Compacting again has nothing to reclaim.
+	if reclaimed, err := f.Compact(); err != nil {
+		t.Fatal(err)
+	} else if reclaimed != 0 {
+		t.Fatalf("unexpected reclaimed bytes: %d", reclaimed)
+	}
+
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	} else if n := f.row(0).Count(); n != 0 {
+		t.Fatalf("unexpected count: %d", n)
+	} else if columns := f.row(1).Columns(); !reflect.DeepEqual(columns, []uint64{1}) {
+		t.Fatalf("unexpected columns: %v", columns)
+	}
+}
+
 // Ensure a fragment can iterate over all bits in order.
 func TestFragment_ForEachBit(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, "")
@@ -876,6 +1181,237 @@ func TestFragment_ForEachBit(t *testing.T) {
 	}
 }
 
+// Ensure iterating over the bits of a fragment spanning several batches
+// stops at the first error, and that bits can be set during the iteration,
+// even by the iterating function, without blocking it or disturbing the
+// bits it reads.
+func TestFragment_ForEachBit_Concurrent(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	const n = 3*forEachBitBatchSize + 10
+	for i := uint64(0); i < n; i++ {
+		if _, err := f.setBit(i%10, i*2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errStop := fmt.Errorf("stop")
+	var seen int
+	if err := f.forEachBit(func(rowID, columnID uint64) error {
+		if seen++; seen == forEachBitBatchSize+1 {
+			return errStop
+		}
+		return nil
+	}); err != errStop {
+		t.Fatalf("expected stop error, got %v", err)
+	} else if seen != forEachBitBatchSize+1 {
+		t.Fatalf("expected iteration to stop at %d, got %d", forEachBitBatchSize+1, seen)
+	}
+
+	// Set bits of row 20, ahead of the scan, from another goroutine and from
+	// the iterating function.
+	done := make(chan struct{})
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		for i := uint64(0); ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := f.setBit(20, i%ShardWidth); err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	var prev uint64
+	var even int
+	if err := f.forEachBit(func(rowID, columnID uint64) error {
+		pos := rowID*ShardWidth + columnID
+		if even > 0 && pos <= prev {
+			return fmt.Errorf("bit %d,%d out of order", rowID, columnID)
+		}
+		prev = pos
+		if rowID < 10 {
+			if columnID%2 != 0 {
+				return fmt.Errorf("unexpected bit %d,%d", rowID, columnID)
+			}
+			even++
+			_, err := f.setBit(20, ShardWidth-1-columnID/2)
+			return err
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	close(done)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	} else if even != n {
+		t.Fatalf("expected %d bits, got %d", n, even)
+	}
+}
+
+// Ensure closing a fragment waits for an iteration over its bits to finish,
+// and refuses new ones in the meantime.
+func TestFragment_Close_Readers(t *testing.T) {
+	// forEachBlocked starts iterating over the bits of f and blocks on the
+	// first one until proceed is closed. The number of bits seen is sent on
+	// the returned channel.
+	forEachBlocked := func(f *fragment, proceed chan struct{}) chan int {
+		started, seen := make(chan struct{}), make(chan int, 1)
+		go func() {
+			var n int
+			if err := f.forEachBit(func(rowID, columnID uint64) error {
+				if n++; n == 1 {
+					close(started)
+					<-proceed
+				}
+				return nil
+			}); err != nil {
+				t.Error(err)
+			}
+			seen <- n
+		}()
+		<-started
+		return seen
+	}
+
+	// waitClosing waits for Close to begin refusing new readers.
+	waitClosing := func(f *fragment) {
+		for {
+			if err := f.acquire(); err == ErrFragmentClosing {
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			f.release()
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("Drain", func(t *testing.T) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+
+		const n = 3 * forEachBitBatchSize
+		for i := uint64(0); i < n; i++ {
+			f.mustSetBits(i%3, i)
+		}
+
+		proceed := make(chan struct{})
+		seen := forEachBlocked(f, proceed)
+		closed := make(chan error, 1)
+		go func() { closed <- f.Close() }()
+		waitClosing(f)
+
+		if err := f.forEachBit(func(rowID, columnID uint64) error { return nil }); err != ErrFragmentClosing {
+			t.Fatalf("expected closing error, got %v", err)
+		}
+		select {
+		case err := <-closed:
+			t.Fatalf("closed with a reader: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		close(proceed)
+		if a := <-seen; a != n {
+			t.Fatalf("expected %d bits, got %d", n, a)
+		} else if err := <-closed; err != nil {
+			t.Fatal(err)
+		}
+
+		// Reopening accepts readers again.
+		if err := f.Open(); err != nil {
+			t.Fatal(err)
+		} else if err := f.acquire(); err != nil {
+			t.Fatal(err)
+		}
+		f.release()
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+		l := logger.NewCaptureLogger()
+		f.Logger = l
+		f.CloseTimeout = 10 * time.Millisecond
+
+		for i := uint64(0); i < 2*forEachBitBatchSize; i++ {
+			f.mustSetBits(0, i)
+		}
+
+		proceed := make(chan struct{})
+		seen := forEachBlocked(f, proceed)
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		} else if !l.Contains(logger.InfoLevel, "closing under readers after waiting 10ms: readers=1") {
+			t.Fatalf("expected the reader to be logged: %v", l.Entries())
+		}
+
+		// The reader left behind sees the rest of the closed fragment as
+		// empty.
+		close(proceed)
+		if a := <-seen; a != forEachBitBatchSize {
+			t.Fatalf("expected %d bits, got %d", forEachBitBatchSize, a)
+		} else if err := f.Open(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// Ensure a fragment only iterates over the rows selected for an export.
+func TestFragment_ForEachExportBit(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	for _, rowID := range []uint64{0, 2, 3, 5, math.MaxUint64 / ShardWidth} {
+		if _, err := f.setBit(rowID, 10); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.setBit(rowID, ShardWidth-1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct {
+		opts []ExportOption
+		exp  []uint64
+	}{
+		{opts: nil, exp: []uint64{0, 2, 3, 5, math.MaxUint64 / ShardWidth}},
+		{opts: []ExportOption{OptExportOptionsRowRange(2, 5)}, exp: []uint64{2, 3, 5}},
+		{opts: []ExportOption{OptExportOptionsRowRange(4, math.MaxUint64)}, exp: []uint64{5, math.MaxUint64 / ShardWidth}},
+		{opts: []ExportOption{OptExportOptionsRowRange(math.MaxUint64, math.MaxUint64)}, exp: nil},
+		{opts: []ExportOption{OptExportOptionsRowIDs([]uint64{5, 1, 0, 5, math.MaxUint64})}, exp: []uint64{0, 5}},
+		{opts: []ExportOption{OptExportOptionsRowIDs([]uint64{0, 3, 5}), OptExportOptionsRowRange(1, 4)}, exp: []uint64{3}},
+		{opts: []ExportOption{OptExportOptionsRowIDs([]uint64{})}, exp: nil},
+	} {
+		options, err := NewExportOptions(tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var rows []uint64
+		if err := f.forEachExportBit(options, func(rowID, columnID uint64) error {
+			if columnID == 10 {
+				rows = append(rows, rowID)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(rows, tt.exp) {
+			t.Fatalf("%+v: expected rows %v, got %v", options, tt.exp, rows)
+		}
+	}
+
+	if _, err := NewExportOptions(OptExportOptionsRowRange(2, 1)); err == nil {
+		t.Fatal("expected error for an empty range")
+	}
+}
+
 // Ensure a fragment can return the top n results.
 func TestFragment_Top(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
@@ -887,7 +1423,7 @@ func TestFragment_Top(t *testing.T) {
 	f.RecalculateCache()
 
 	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{N: 2}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{N: 2}); err != nil {
 		t.Fatal(err)
 	} else if len(pairs) != 2 {
 		t.Fatalf("unexpected count: %d", len(pairs))
@@ -913,7 +1449,7 @@ func TestFragment_Top_Filter(t *testing.T) {
 	f.RowAttrStore.SetAttrs(102, map[string]interface{}{"x": int64(20)})
 
 	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{
+	if pairs, err := f.top(context.Background(), topOptions{
 		N:            2,
 		FilterName:   "x",
 		FilterValues: []interface{}{int64(10), int64(15), int64(20)},
@@ -928,45 +1464,166 @@ func TestFragment_Top_Filter(t *testing.T) {
 	}
 }
 
-// Ensure a fragment can return top rows that intersect with an input row.
-func TestFragment_TopN_Intersect(t *testing.T) {
-	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
-	defer f.Clean(t)
-
-	// Create an intersecting input row.
-	src := NewRow(1, 2, 3)
-
-	// Set bits on various rows.
-	f.mustSetBits(100, 1, 10, 11, 12)    // one intersection
-	f.mustSetBits(101, 1, 2, 3, 4)       // three intersections
-	f.mustSetBits(102, 1, 2, 4, 5, 6)    // two intersections
-	f.mustSetBits(103, 1000, 1001, 1002) // no intersection
-	f.RecalculateCache()
+// bulkMemAttrStore is a memAttrStore which counts the reads of attributes.
+type bulkMemAttrStore struct {
+	memAttrStore
+	attrsN, bulkN int
+}
 
-	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{N: 3, Src: src}); err != nil {
-		t.Fatal(err)
-	} else if !reflect.DeepEqual(pairs, []Pair{
-		{ID: 101, Count: 3},
-		{ID: 102, Count: 2},
-		{ID: 100, Count: 1},
-	}) {
-		t.Fatalf("unexpected pairs: %s", spew.Sdump(pairs))
-	}
+func (s *bulkMemAttrStore) Attrs(id uint64) (map[string]interface{}, error) {
+	s.attrsN++
+	return s.memAttrStore.Attrs(id)
 }
 
-// Ensure a fragment can return top rows that have many columns set.
-func TestFragment_TopN_Intersect_Large(t *testing.T) {
-	if testing.Short() {
-		t.Skip("short mode")
-	}
+func (s *bulkMemAttrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	s.bulkN++
+	return s.memAttrStore.BlockAttrs(ids)
+}
 
+// Ensure filtering the top rows reads the attributes of the rows in bulk,
+// excludes rows without the attribute, and matches numbers of either type
+// but not strings.
+func TestFragment_Top_FilterBulk(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
 	defer f.Clean(t)
+	store := &bulkMemAttrStore{memAttrStore: memAttrStore{store: make(map[uint64]map[string]interface{})}}
+	f.RowAttrStore = store
 
-	// Create an intersecting input row.
-	src := NewRow(
-		980, 981, 982, 983, 984, 985, 986, 987, 988, 989,
+	// Row n has n bits, so the rows are ranked by ID.
+	for rowID := uint64(1); rowID <= 6; rowID++ {
+		for columnID := uint64(0); columnID < rowID; columnID++ {
+			f.mustSetBits(rowID, columnID)
+		}
+	}
+	f.RecalculateCache()
+	f.RowAttrStore.SetAttrs(1, map[string]interface{}{"category": "a"})
+	f.RowAttrStore.SetAttrs(2, map[string]interface{}{"category": int64(7)})
+	f.RowAttrStore.SetAttrs(3, map[string]interface{}{"category": float64(7)})
+	f.RowAttrStore.SetAttrs(4, map[string]interface{}{"category": "7"})
+	f.RowAttrStore.SetAttrs(5, map[string]interface{}{"other": "a"})
+	f.RowAttrStore.SetAttrs(6, map[string]interface{}{"category": "b"})
+
+	for _, tt := range []struct {
+		n      int
+		values []interface{}
+		exp    []Pair
+	}{
+		{n: 50, values: []interface{}{"a", "b"}, exp: []Pair{{ID: 6, Count: 6}, {ID: 1, Count: 1}}},
+		{n: 1, values: []interface{}{"a", "b"}, exp: []Pair{{ID: 6, Count: 6}}},
+		{n: 50, values: []interface{}{int64(7)}, exp: []Pair{{ID: 3, Count: 3}, {ID: 2, Count: 2}}},
+		{n: 50, values: []interface{}{float64(7)}, exp: []Pair{{ID: 3, Count: 3}, {ID: 2, Count: 2}}},
+		{n: 50, values: []interface{}{"7"}, exp: []Pair{{ID: 4, Count: 4}}},
+		{n: 50, values: []interface{}{"c"}, exp: []Pair{}},
+	} {
+		store.attrsN, store.bulkN = 0, 0
+		if pairs, err := f.top(context.Background(), topOptions{N: tt.n, FilterName: "category", FilterValues: tt.values}); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(pairs, tt.exp) {
+			t.Fatalf("n=%d %v: expected %v, got %v", tt.n, tt.values, tt.exp, pairs)
+		} else if store.attrsN != 0 || store.bulkN != 1 {
+			t.Fatalf("n=%d %v: expected 1 bulk read, got %d and %d single reads", tt.n, tt.values, store.bulkN, store.attrsN)
+		}
+	}
+}
+
+// cancelAttrStore is a bulkMemAttrStore which cancels a query when the
+// attributes are first read in bulk.
+type cancelAttrStore struct {
+	bulkMemAttrStore
+	cancel func()
+}
+
+func (s *cancelAttrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	s.cancel()
+	return s.bulkMemAttrStore.BlockAttrs(ids)
+}
+
+// Ensure the top rows stop being scanned once the query is cancelled or
+// times out.
+func TestFragment_Top_Cancel(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	for rowID := uint64(0); rowID < 1000; rowID++ {
+		f.mustSetBits(rowID, rowID)
+	}
+	f.RecalculateCache()
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, exact := range []bool{false, true} {
+			if _, err := f.top(ctx, topOptions{N: 10, Exact: exact}); err != ErrQueryCancelled {
+				t.Fatalf("exact=%v: expected %v, got %v", exact, ErrQueryCancelled, err)
+			}
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+		defer cancel()
+		if _, err := f.top(ctx, topOptions{Exact: true}); err != ErrQueryTimeout {
+			t.Fatalf("expected %v, got %v", ErrQueryTimeout, err)
+		}
+	})
+
+	// A filter which no row matches scans every row, reading the attributes
+	// in batches, unless the scan stops when the query is cancelled.
+	t.Run("Scanning", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		store := &cancelAttrStore{
+			bulkMemAttrStore: bulkMemAttrStore{memAttrStore: memAttrStore{store: make(map[uint64]map[string]interface{})}},
+			cancel:           cancel,
+		}
+		f.RowAttrStore = store
+		if _, err := f.top(ctx, topOptions{N: 10, FilterName: "category", FilterValues: []interface{}{"a"}}); err != ErrQueryCancelled {
+			t.Fatalf("expected %v, got %v", ErrQueryCancelled, err)
+		} else if store.bulkN != 1 {
+			t.Fatalf("expected 1 bulk read, got %d", store.bulkN)
+		}
+	})
+}
+
+// Ensure a fragment can return top rows that intersect with an input row.
+func TestFragment_TopN_Intersect(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	// Create an intersecting input row.
+	src := NewRow(1, 2, 3)
+
+	// Set bits on various rows.
+	f.mustSetBits(100, 1, 10, 11, 12)    // one intersection
+	f.mustSetBits(101, 1, 2, 3, 4)       // three intersections
+	f.mustSetBits(102, 1, 2, 4, 5, 6)    // two intersections
+	f.mustSetBits(103, 1000, 1001, 1002) // no intersection
+	f.RecalculateCache()
+
+	// Retrieve top rows.
+	if pairs, err := f.top(context.Background(), topOptions{N: 3, Src: src}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{
+		{ID: 101, Count: 3},
+		{ID: 102, Count: 2},
+		{ID: 100, Count: 1},
+	}) {
+		t.Fatalf("unexpected pairs: %s", spew.Sdump(pairs))
+	}
+}
+
+// Ensure a fragment can return top rows that have many columns set.
+func TestFragment_TopN_Intersect_Large(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short mode")
+	}
+
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	// Create an intersecting input row.
+	src := NewRow(
+		980, 981, 982, 983, 984, 985, 986, 987, 988, 989,
 		990, 991, 992, 993, 994, 995, 996, 997, 998, 999,
 	)
 
@@ -979,7 +1636,7 @@ func TestFragment_TopN_Intersect_Large(t *testing.T) {
 	f.RecalculateCache()
 
 	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{N: 10, Src: src}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{N: 10, Src: src}); err != nil {
 		t.Fatal(err)
 	} else if !reflect.DeepEqual(pairs, []Pair{
 		{ID: 999, Count: 19},
@@ -1008,7 +1665,7 @@ func TestFragment_TopN_IDs(t *testing.T) {
 	f.mustSetBits(102, 8, 9, 10, 11, 12)
 
 	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{RowIDs: []uint64{100, 101, 200}}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{RowIDs: []uint64{100, 101, 200}}); err != nil {
 		t.Fatal(err)
 	} else if !reflect.DeepEqual(pairs, []Pair{
 		{ID: 101, Count: 4},
@@ -1029,7 +1686,7 @@ func TestFragment_TopN_NopCache(t *testing.T) {
 	f.mustSetBits(102, 8, 9, 10, 11, 12)
 
 	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{RowIDs: []uint64{100, 101, 200}}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{RowIDs: []uint64{100, 101, 200}}); err != nil {
 		t.Fatal(err)
 	} else if !reflect.DeepEqual(pairs, []Pair{}) {
 		t.Fatalf("unexpected pairs: %s", spew.Sdump(pairs))
@@ -1088,7 +1745,7 @@ func TestFragment_TopN_CacheSize(t *testing.T) {
 	}
 
 	// Retrieve top rows.
-	if pairs, err := f.top(topOptions{N: 5}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{N: 5}); err != nil {
 		t.Fatal(err)
 	} else if len(pairs) > int(cacheSize) {
 		t.Fatalf("TopN count cannot exceed cache size: %d", cacheSize)
@@ -1118,6 +1775,43 @@ func TestFragment_Checksum(t *testing.T) {
 	}
 }
 
+// Ensure fragments with the same bits have the same checksum, whatever the
+// order the bits were set in and whether they were snapshotted since.
+func TestFragment_Checksum_Order(t *testing.T) {
+	f0 := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f0.Clean(t)
+	f1 := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f1.Clean(t)
+
+	rowIDs := []uint64{1, 1, 2, HashBlockSize, HashBlockSize * 3}
+	columnIDs := []uint64{200, 5, 70, 9, ShardWidth - 1}
+	for i := range rowIDs {
+		if _, err := f0.setBit(rowIDs[i], columnIDs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := len(rowIDs) - 1; i >= 0; i-- {
+		if _, err := f1.setBit(rowIDs[i], columnIDs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c0, c1 := f0.Checksum(), f1.Checksum(); !bytes.Equal(c0, c1) {
+		t.Fatalf("checksums differ: %x != %x", c0, c1)
+	}
+
+	orig := f1.Checksum()
+	f1.mu.Lock()
+	err := f1.snapshot()
+	f1.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1.InvalidateChecksums()
+	if chksum := f1.Checksum(); !bytes.Equal(chksum, orig) {
+		t.Fatalf("checksum changed after snapshot: %x != %x", chksum, orig)
+	}
+}
+
 // Ensure fragment can return a checksum for a given block.
 func TestFragment_Blocks(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, "")
@@ -1179,83 +1873,931 @@ func TestFragment_LRUCache_Persistence(t *testing.T) {
 	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeLRU)
 	defer f.Clean(t)
 
-	// Set bits on the fragment.
-	for i := uint64(0); i < 1000; i++ {
-		if _, err := f.setBit(i, 0); err != nil {
+	// Set bits on the fragment.
+	for i := uint64(0); i < 1000; i++ {
+		if _, err := f.setBit(i, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Verify correct cache type and size.
+	if cache, ok := f.cache.(*lruCache); !ok {
+		t.Fatalf("unexpected cache: %T", f.cache)
+	} else if cache.Len() != 1000 {
+		t.Fatalf("unexpected cache len: %d", cache.Len())
+	}
+
+	// Reopen the fragment.
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-verify correct cache type and size.
+	if cache, ok := f.cache.(*lruCache); !ok {
+		t.Fatalf("unexpected cache: %T", f.cache)
+	} else if cache.Len() != 1000 {
+		t.Fatalf("unexpected cache len: %d", cache.Len())
+	}
+}
+
+// Ensure a fragment's cache can be persisted between restarts.
+func TestFragment_RankCache_Persistence(t *testing.T) {
+	index := mustOpenIndex(IndexOptions{})
+	defer index.Close()
+
+	// Create field.
+	field, err := index.CreateFieldIfNotExists("f", OptFieldTypeSet(CacheTypeRanked, DefaultCacheSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create view.
+	view, err := field.createViewIfNotExists(viewStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create fragment.
+	f, err := view.CreateFragmentIfNotExists(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set bits on the fragment.
+	for i := uint64(0); i < 1000; i++ {
+		if _, err := f.setBit(i, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Verify correct cache type and size.
+	if cache, ok := f.cache.(*rankCache); !ok {
+		t.Fatalf("unexpected cache: %T", f.cache)
+	} else if cache.Len() != 1000 {
+		t.Fatalf("unexpected cache len: %d", cache.Len())
+	}
+
+	// Reopen the index.
+	if err := index.reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-fetch fragment.
+	f = index.Field("f").view(viewStandard).Fragment(0)
+
+	// Re-verify correct cache type and size.
+	if cache, ok := f.cache.(*rankCache); !ok {
+		t.Fatalf("unexpected cache: %T", f.cache)
+	} else if cache.Len() != 1000 {
+		t.Fatalf("unexpected cache len: %d", cache.Len())
+	}
+}
+
+// Ensure a ranked cache saved on a clean close is trusted on reopen.
+func TestFragment_RankCache_CleanRestart(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	for i := uint64(0); i < 100; i++ {
+		f.mustSetBits(i, 1, 2)
+	}
+
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := f.readCache(); !ok {
+		t.Fatal("expected cache file to be trusted")
+	} else if n := f.cache.Len(); n != 100 {
+		t.Fatalf("unexpected cache len: %d", n)
+	}
+}
+
+// Ensure a ranked cache saved before further writes is rebuilt on reopen.
+func TestFragment_RankCache_RestartWithoutSnapshot(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+
+	f.mustSetBits(1, 1)
+	if err := f.FlushCache(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write more bits and simulate a crash so the cache isn't saved again.
+	f.mustSetBits(2, 1, 2)
+	f.mustSetBits(3, 1, 2, 3)
+	if err := f.closeStorage(); err != nil {
+		t.Fatal(err)
+	}
+
+	f2 := newFragment(f.path, "i", "f", viewStandard, 0)
+	f2.CacheType = f.CacheType
+	if err := f2.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Clean(t)
+
+	if _, _, ok := f2.readCache(); ok {
+		t.Fatal("expected stale cache file to be untrusted")
+	}
+
+	f2.cacheRebuild.Wait()
+	f2.mu.Lock()
+	pairs := f2.cache.Top()
+	f2.mu.Unlock()
+	if !reflect.DeepEqual(pairs, []bitmapPair{{ID: 3, Count: 3}, {ID: 2, Count: 2}, {ID: 1, Count: 1}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+}
+
+// Ensure a corrupted cache file is ignored and the cache is rebuilt.
+func TestFragment_RankCache_Corrupted(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	f.mustSetBits(1, 1, 2)
+	f.mustSetBits(2, 1)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(f.cachePath(), []byte("garbage"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := f.readCache(); ok {
+		t.Fatal("expected corrupted cache file to be untrusted")
+	}
+
+	f.cacheRebuild.Wait()
+	f.mu.Lock()
+	pairs := f.cache.Top()
+	f.mu.Unlock()
+	if !reflect.DeepEqual(pairs, []bitmapPair{{ID: 1, Count: 2}, {ID: 2, Count: 1}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+}
+
+// Ensure the first TopN after a restart matches the TopN before it, whether
+// the cache is loaded with its counts, rebuilt in the background, or still
+// being rebuilt.
+func TestFragment_RankCache_Warmup(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+	f.cacheRebuilder = newCacheRebuilder()
+
+	for i := uint64(1); i <= 50; i++ {
+		for j := uint64(0); j < i%7; j++ {
+			f.mustSetBits(i, i*10+j)
+		}
+	}
+	f.RecalculateCache()
+	before, err := f.top(context.Background(), topOptions{N: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustTop := func() {
+		t.Helper()
+		if pairs, err := f.top(context.Background(), topOptions{N: 10}); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(pairs, before) {
+			t.Fatalf("unexpected pairs: %v, expected %v", pairs, before)
+		}
+	}
+
+	// The cache is loaded with the persisted counts.
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	} else if f.isCacheWarming() {
+		t.Fatal("expected cache to be warm")
+	} else if _, counts, ok := f.readCache(); !ok || len(counts) != 43 {
+		t.Fatalf("unexpected persisted counts: %v", counts)
+	}
+	mustTop()
+
+	// A cache file without counts has its counts read in the background.
+	checksum, err := f.storageChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := f.cache.IDs()
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	} else if buf, err := encodeCacheFile(checksum, ids, nil); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(f.cachePath(), buf, 0666); err != nil {
+		t.Fatal(err)
+	} else if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+	f.cacheRebuild.Wait()
+	mustTop()
+
+	// TopN reads counts from storage while the cache is rebuilt. Stop the
+	// rebuild partway, leaving the cache empty.
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := os.Remove(f.cachePath()); err != nil {
+		t.Fatal(err)
+	} else if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	f.cacheWarmGen++
+	f.cache = NewRankCache(f.CacheSize)
+	f.mu.Unlock()
+	f.cacheRebuild.Wait()
+	if !f.isCacheWarming() {
+		t.Fatal("expected cache to be warming")
+	}
+	mustTop()
+
+	// A forced rebuild warms the cache again.
+	if err := f.rebuildCacheInBackground(); err != nil {
+		t.Fatal(err)
+	}
+	f.cacheRebuild.Wait()
+	if f.isCacheWarming() {
+		t.Fatal("expected cache to be warm")
+	} else if n := f.cache.Len(); n != 43 {
+		t.Fatalf("unexpected cache len: %d", n)
+	}
+	mustTop()
+
+	if warming, warmed := f.cacheRebuilder.Warmup(); warming != 0 || warmed != 3 {
+		t.Fatalf("unexpected warm up counts: warming=%d, warmed=%d", warming, warmed)
+	}
+}
+
+// Ensure rows written while the cache is rebuilt in the background are
+// counted again when the rebuilt cache is swapped in.
+func TestFragment_RankCache_WarmupWrites(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	f.mustSetBits(1, 1)
+	f.mustSetBits(2, 1)
+	f.mustSetBits(3, 1, 2, 3)
+
+	f.mu.Lock()
+	f.cache = newWarmingCache(f.cache)
+	f.cacheWarming = true
+	f.cacheWarmGen++
+	gen := f.cacheWarmGen
+	f.mu.Unlock()
+
+	// Row 2 was counted before it was written to.
+	c := NewRankCache(f.CacheSize)
+	c.BulkAdd(2, 1)
+	f.mustSetBits(2, 2, 3, 4)
+	if !f.warmCache(gen, f.CacheSize, c, []uint64{1, 3}) {
+		t.Fatal("expected rebuild to complete")
+	} else if f.isCacheWarming() {
+		t.Fatal("expected cache to be warm")
+	}
+	f.mu.Lock()
+	pairs := f.cache.Top()
+	f.mu.Unlock()
+	if !reflect.DeepEqual(pairs, []bitmapPair{{ID: 2, Count: 4}, {ID: 3, Count: 3}, {ID: 1, Count: 1}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+}
+
+// Ensure TopN of specific rows counts them from storage while the cache is
+// rebuilt, rather than reading the partial counts of the cache.
+func TestFragment_TopN_IDs_Warming(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	f.mustSetBits(1, 1, 2, 3)
+	f.mustSetBits(2, 1)
+
+	f.mu.Lock()
+	c := NewRankCache(f.CacheSize)
+	c.BulkAdd(1, 1)
+	f.cache = newWarmingCache(c)
+	f.cacheWarming = true
+	f.cacheWarmGen++
+	f.mu.Unlock()
+
+	if pairs, err := f.top(context.Background(), topOptions{RowIDs: []uint64{1, 2, 3}}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{{ID: 1, Count: 3}, {ID: 2, Count: 1}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+}
+
+// Ensure a rebuild of the cache already running when the cache is resized
+// or reset doesn't swap its cache in over the changed one.
+func TestFragment_RankCache_WarmupSupersededBySizeAndReset(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	for i := uint64(1); i <= 5; i++ {
+		f.mustSetBits(i, 1)
+	}
+
+	startWarming := func() int {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.cache = newWarmingCache(f.cache)
+		f.cacheWarming = true
+		f.cacheWarmGen++
+		return f.cacheWarmGen
+	}
+
+	// A rebuild at the old size is dropped for one at the new size.
+	gen := startWarming()
+	f.setCacheSize(3)
+	if f.warmCache(gen, 2, NewRankCache(2), []uint64{1, 2}) {
+		t.Fatal("expected rebuild at the old size to be superseded")
+	}
+	f.cacheRebuild.Wait()
+	f.mu.Lock()
+	n, warming := f.cache.Len(), f.cacheWarming
+	_, wrapped := f.cache.(*warmingCache)
+	f.mu.Unlock()
+	if warming || wrapped {
+		t.Fatal("expected cache to be warm")
+	} else if n != 3 {
+		t.Fatalf("unexpected cache len: %d", n)
+	}
+
+	// A reset cache isn't replaced by a rebuild started before it.
+	gen = startWarming()
+	if err := f.resetCache(); err != nil {
+		t.Fatal(err)
+	} else if f.warmCache(gen, f.CacheSize, NewRankCache(f.CacheSize), nil) {
+		t.Fatal("expected rebuild to be superseded by the reset")
+	}
+	f.cacheRebuild.Wait()
+	if f.isCacheWarming() {
+		t.Fatal("expected cache to be warm")
+	}
+	f.mu.Lock()
+	n = f.cache.Len()
+	f.mu.Unlock()
+	if n != 3 {
+		t.Fatalf("unexpected cache len: %d", n)
+	}
+}
+
+// Ensure the storage checksum kept as ops are appended matches the hash of
+// the data file.
+func TestFragment_StorageChecksum(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	mustMatch := func() {
+		t.Helper()
+		data, err := ioutil.ReadFile(f.path)
+		if err != nil {
+			t.Fatal(err)
+		} else if checksum, err := f.storageChecksum(); err != nil {
+			t.Fatal(err)
+		} else if checksum != xxhash.Sum64(data) {
+			t.Fatalf("checksum %x doesn't match data file %x", checksum, xxhash.Sum64(data))
+		}
+	}
+
+	mustMatch()
+	f.mustSetBits(1, 1, 2, 3)
+	if _, err := f.clearBit(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	mustMatch()
+	if err := f.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	mustMatch()
+	f.mustSetBits(2, 5)
+	if err := f.reopen(); err != nil {
+		t.Fatal(err)
+	}
+	mustMatch()
+}
+
+// Ensure a fragment opens with or without an op which a crash cut short.
+func TestFragment_Open_TornOp(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	f.mustSetBits(1, 1, 2)
+	before, err := os.Stat(f.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mustSetBits(1, 3)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		t.Fatal(err)
+	} else if int64(len(data)) <= before.Size() {
+		t.Fatalf("expected an op to be appended: %d <= %d", len(data), before.Size())
+	}
+
+	for n := int(before.Size()); n <= len(data); n++ {
+		if err := ioutil.WriteFile(f.path, data[:n], 0666); err != nil {
+			t.Fatal(err)
+		} else if err := f.Open(); err != nil {
+			t.Fatalf("opening with %d bytes: %s", n, err)
+		}
+
+		want := []uint64{1, 2}
+		if n == len(data) {
+			want = append(want, 3)
+		}
+		if a := f.row(1).Columns(); !reflect.DeepEqual(a, want) {
+			t.Fatalf("unexpected columns with %d bytes: %v", n, a)
+		}
+
+		// The dropped op is trimmed, so later ops can be read.
+		f.mustSetBits(1, 4)
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		} else if err := f.Open(); err != nil {
+			t.Fatal(err)
+		} else if a := f.row(1).Columns(); !reflect.DeepEqual(a, append(want, 4)) {
+			t.Fatalf("unexpected columns after reopening with %d bytes: %v", n, a)
+		} else if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure an unreadable op in the middle of the op log fails the open and
+// leaves the file alone, while one at its tail is trimmed.
+func TestFragment_Open_CorruptOp(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mustSetBits(1, 1)
+	f.mustSetBits(1, 2)
+	f.mustSetBits(1, 3)
+	f.mustSetBits(1, 4)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const opSize = 13
+	if n := int(fi.Size()) + 4*opSize; len(data) != n {
+		t.Fatalf("unexpected file size: %d != %d", len(data), n)
+	}
+
+	// Flip a checksum byte of the second op.
+	corrupt := append([]byte(nil), data...)
+	corrupt[int(fi.Size())+opSize+9] ^= 0xff
+	if err := ioutil.WriteFile(f.path, corrupt, 0666); err != nil {
+		t.Fatal(err)
+	} else if err := f.Open(); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, err := ioutil.ReadFile(f.path); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(got, corrupt) {
+		t.Fatalf("expected file to be left alone: %d bytes, was %d", len(got), len(corrupt))
+	}
+
+	// Flip a checksum byte of the last op.
+	corrupt = append([]byte(nil), data...)
+	corrupt[int(fi.Size())+3*opSize+9] ^= 0xff
+	if err := ioutil.WriteFile(f.path, corrupt, 0666); err != nil {
+		t.Fatal(err)
+	} else if err := f.Open(); err != nil {
+		t.Fatal(err)
+	} else if a := f.row(1).Columns(); !reflect.DeepEqual(a, []uint64{1, 2, 3}) {
+		t.Fatalf("unexpected columns: %v", a)
+	} else if fi, err := os.Stat(f.path); err != nil {
+		t.Fatal(err)
+	} else if fi.Size() != int64(len(data)-opSize) {
+		t.Fatalf("unexpected trimmed size: %d", fi.Size())
+	}
+}
+
+// Ensure the cache accountant shrinks the least recently used caches first.
+func TestCacheAccountant_Enforce(t *testing.T) {
+	a := newCacheAccountant()
+
+	f0 := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f0.Clean(t)
+	f1 := mustOpenFragment("i", "f", viewStandard, 1, CacheTypeRanked)
+	defer f1.Clean(t)
+	for i := uint64(0); i < 10; i++ {
+		f0.mustSetBits(i, i+1)
+		f1.mustSetBits(i, ShardWidth+i+1)
+	}
+	a.register(f0)
+	a.register(f1)
+
+	// Only f1 is used by TopN.
+	if _, err := f1.top(context.Background(), topOptions{N: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Usage is reported without eviction when there is no budget.
+	a.enforce(0)
+	if n := a.Usage(); n != 20*rankCacheEntrySize {
+		t.Fatalf("unexpected usage: %d", n)
+	}
+
+	a.enforce(15 * rankCacheEntrySize)
+	if n := a.Usage(); n != 15*rankCacheEntrySize {
+		t.Fatalf("unexpected usage: %d", n)
+	} else if n := f0.cache.Len(); n != 5 {
+		t.Fatalf("unexpected unused cache size: %d", n)
+	} else if n := f1.cache.Len(); n != 10 {
+		t.Fatalf("unexpected used cache size: %d", n)
+	}
+
+	// Unregistered fragments are no longer accounted for.
+	a.unregister(f0)
+	a.enforce(0)
+	if n := a.Usage(); n != 10*rankCacheEntrySize {
+		t.Fatalf("unexpected usage: %d", n)
+	}
+}
+
+// Ensure TopN is correct when rank maintenance is deferred.
+func TestFragment_TopN_DeferredCache(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	// Defer maintenance for every write.
+	f.cacheDeferThreshold = 0
+
+	f.mustSetBits(100, 1, 2, 3, 4)
+	f.mustSetBits(101, 1, 2)
+	f.mustSetBits(102, 1)
+	if _, err := f.clearBit(100, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes only mark rows dirty.
+	if n := len(f.dirtyRows); n != 3 {
+		t.Fatalf("unexpected dirty rows: %d", n)
+	} else if n := f.cache.Len(); n != 0 {
+		t.Fatalf("unexpected cache len: %d", n)
+	}
+
+	if pairs, err := f.top(context.Background(), topOptions{N: 3}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{
+		{ID: 100, Count: 3},
+		{ID: 101, Count: 2},
+		{ID: 102, Count: 1},
+	}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	} else if n := len(f.dirtyRows); n != 0 {
+		t.Fatalf("unexpected dirty rows after TopN: %d", n)
+	}
+
+	// Deferred counts are also applied before reading specific rows.
+	f.mustSetBits(102, 2, 3, 4)
+	if pairs, err := f.top(context.Background(), topOptions{RowIDs: []uint64{102}}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{{ID: 102, Count: 4}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+}
+
+// Ensure imports defer cache maintenance to a background rebuild.
+func TestFragment_ImportCacheRebuild(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+	r := newCacheRebuilder()
+	f.cacheRebuilder = r
+
+	// Import twice before any rebuild runs.
+	if err := f.bulkImport([]uint64{1, 1, 1, 2}, []uint64{1, 2, 3, 1}, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := f.bulkImport([]uint64{3, 3}, []uint64{1, 2}, &ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fragment is only queued once and its cache is still empty.
+	if n := r.Pending(); n != 1 {
+		t.Fatalf("unexpected pending rebuilds: %d", n)
+	} else if n := f.cache.Len(); n != 0 {
+		t.Fatalf("unexpected cache len: %d", n)
+	}
+
+	closing := make(chan struct{})
+	defer close(closing)
+	go r.run(closing)
+
+	for i := 0; r.Pending() > 0; i++ {
+		if i > 100 {
+			t.Fatal("timed out waiting for cache rebuild")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !reflect.DeepEqual(f.cache.Top(), []bitmapPair{
+		{ID: 1, Count: 3},
+		{ID: 3, Count: 2},
+		{ID: 2, Count: 1},
+	}) {
+		t.Fatalf("unexpected cache: %+v", f.cache.Top())
+	}
+
+	// The rebuilt cache is persisted against the imported storage.
+	if ids, _, ok := f.readCache(); !ok {
+		t.Fatal("expected valid cache file")
+	} else if len(ids) != 3 {
+		t.Fatalf("unexpected cached ids: %v", ids)
+	}
+}
+
+// Ensure TopN against a fragment with a pending rebuild does not wait for a
+// worker and returns up to date results.
+func TestFragment_ImportCacheRebuild_TopN(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+	r := newCacheRebuilder()
+	f.cacheRebuilder = r
+
+	data := roaring.NewBitmap(ShardWidth+1, ShardWidth+2, 2*ShardWidth+1)
+	var buf bytes.Buffer
+	if _, err := data.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if err := f.importRoaring(buf.Bytes(), false); err != nil {
+		t.Fatal(err)
+	} else if n := r.Pending(); n != 1 {
+		t.Fatalf("unexpected pending rebuilds: %d", n)
+	}
+
+	// Both the cache and an exact scan reflect the import.
+	for _, exact := range []bool{false, true} {
+		if pairs, err := f.top(context.Background(), topOptions{N: 2, Exact: exact}); err != nil {
 			t.Fatal(err)
+		} else if !reflect.DeepEqual(pairs, []Pair{{ID: 1, Count: 2}, {ID: 2, Count: 1}}) {
+			t.Fatalf("unexpected pairs (exact=%v): %+v", exact, pairs)
 		}
 	}
 
-	// Verify correct cache type and size.
-	if cache, ok := f.cache.(*lruCache); !ok {
-		t.Fatalf("unexpected cache: %T", f.cache)
-	} else if cache.Len() != 1000 {
-		t.Fatalf("unexpected cache len: %d", cache.Len())
+	// Reading the cache completed the rebuild.
+	if n := r.Pending(); n != 0 {
+		t.Fatalf("unexpected pending rebuilds: %d", n)
 	}
+}
 
-	// Reopen the fragment.
-	if err := f.reopen(); err != nil {
+// Ensure fragments whose op logs are full are each queued once, however many
+// writes pass the threshold at once, and snapshotted once by the workers.
+func TestFragment_SnapshotQueue(t *testing.T) {
+	q := newSnapshotQueue()
+	frags := make([]*fragment, 100)
+	for i := range frags {
+		frags[i] = mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer frags[i].Clean(t)
+		frags[i].MaxOpN = 5
+		frags[i].snapshotQueue = q
+	}
+
+	var eg errgroup.Group
+	for _, f := range frags {
+		for w := uint64(0); w < 4; w++ {
+			f, w := f, w
+			eg.Go(func() error {
+				for col := uint64(0); col < 10; col++ {
+					if _, err := f.setBit(w, col); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
 		t.Fatal(err)
+	} else if n := q.Pending(); n != 100 {
+		t.Fatalf("unexpected pending snapshots: %d", n)
+	} else if n := frags[0].opN; n != 40 {
+		t.Fatalf("unexpected op count: %d", n)
 	}
 
-	// Re-verify correct cache type and size.
-	if cache, ok := f.cache.(*lruCache); !ok {
-		t.Fatalf("unexpected cache: %T", f.cache)
-	} else if cache.Len() != 1000 {
-		t.Fatalf("unexpected cache len: %d", cache.Len())
+	closing := make(chan struct{})
+	defer close(closing)
+	for i := 0; i < 2; i++ {
+		go q.run(closing)
+	}
+	for i := 0; q.Pending() > 0; i++ {
+		if i > 500 {
+			t.Fatal("timed out waiting for snapshots")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if n := q.Snapshots(); n != 100 {
+		t.Fatalf("unexpected snapshots: %d", n)
+	}
+	for _, f := range frags {
+		f.mu.Lock()
+		opN := f.opN
+		f.mu.Unlock()
+		if opN != 0 {
+			t.Fatalf("unexpected op count after snapshot: %d", opN)
+		} else if n := f.row(3).Count(); n != 10 {
+			t.Fatalf("unexpected row count: %d", n)
+		}
 	}
 }
 
-// Ensure a fragment's cache can be persisted between restarts.
-func TestFragment_RankCache_Persistence(t *testing.T) {
-	index := mustOpenIndex(IndexOptions{})
-	defer index.Close()
+// Ensure closing or compacting a queued fragment snapshots it at once, and
+// removes it from the queue.
+func TestFragment_SnapshotQueue_JumpQueue(t *testing.T) {
+	for _, name := range []string{"Close", "Compact"} {
+		t.Run(name, func(t *testing.T) {
+			q := newSnapshotQueue()
+			f := mustOpenFragment("i", "f", viewStandard, 0, "")
+			defer f.Clean(t)
+			f.MaxOpN = 2
+			f.snapshotQueue = q
+			f.mustSetBits(1, 1, 2, 3)
+			if !q.queued(f) {
+				t.Fatal("expected fragment to be queued")
+			}
 
-	// Create field.
-	field, err := index.CreateFieldIfNotExists("f", OptFieldTypeSet(CacheTypeRanked, DefaultCacheSize))
-	if err != nil {
-		t.Fatal(err)
-	}
+			if name == "Close" {
+				if err := f.Close(); err != nil {
+					t.Fatal(err)
+				} else if err := f.Open(); err != nil {
+					t.Fatal(err)
+				}
+			} else if _, err := f.Compact(); err != nil {
+				t.Fatal(err)
+			}
 
-	// Create view.
-	view, err := field.createViewIfNotExists(viewStandard)
-	if err != nil {
-		t.Fatal(err)
+			if n := q.Pending(); n != 0 {
+				t.Fatalf("unexpected pending snapshots: %d", n)
+			} else if f.opN != 0 {
+				t.Fatalf("unexpected op count: %d", f.opN)
+			} else if n := f.row(1).Count(); n != 3 {
+				t.Fatalf("unexpected row count: %d", n)
+			}
+		})
 	}
+}
 
-	// Create fragment.
-	f, err := view.CreateFragmentIfNotExists(0)
-	if err != nil {
-		t.Fatal(err)
+// Ensure the cache restored after a crash at any point of a snapshot matches
+// storage.
+func TestFragment_SnapshotCache_Crash(t *testing.T) {
+	// setup returns a fragment with a flushed cache, followed by writes
+	// which aren't reflected in the cache file.
+	setup := func(t *testing.T) (*fragment, []byte) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+		f.mustSetBits(1, 1)
+		if err := f.FlushCache(); err != nil {
+			t.Fatal(err)
+		}
+		buf, err := ioutil.ReadFile(f.cachePath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.mustSetBits(2, 1, 2)
+		f.mustSetBits(3, 1, 2, 3)
+		return f, buf
 	}
 
-	// Set bits on the fragment.
-	for i := uint64(0); i < 1000; i++ {
-		if _, err := f.setBit(i, 0); err != nil {
+	// restart simulates a crash by closing storage without flushing the
+	// cache and opening a new fragment on the same files.
+	restart := func(t *testing.T, f *fragment, trusted bool) *fragment {
+		if err := f.closeStorage(); err != nil {
 			t.Fatal(err)
 		}
+		f2 := newFragment(f.path, "i", "f", viewStandard, 0)
+		f2.CacheType = f.CacheType
+		if err := f2.Open(); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, ok := f2.readCache(); ok != trusted {
+			t.Fatalf("unexpected cache trust: %v", ok)
+		}
+		f2.cacheRebuild.Wait()
+
+		f2.mu.Lock()
+		defer f2.mu.Unlock()
+		f2.cache.Recalculate()
+		if pairs := f2.cache.Top(); !reflect.DeepEqual(pairs, []bitmapPair{{ID: 3, Count: 3}, {ID: 2, Count: 2}, {ID: 1, Count: 1}}) {
+			t.Fatalf("unexpected pairs: %+v", pairs)
+		}
+		return f2
 	}
 
-	// Verify correct cache type and size.
-	if cache, ok := f.cache.(*rankCache); !ok {
-		t.Fatalf("unexpected cache: %T", f.cache)
-	} else if cache.Len() != 1000 {
-		t.Fatalf("unexpected cache len: %d", cache.Len())
+	t.Run("BeforeStorageRename", func(t *testing.T) {
+		f, _ := setup(t)
+
+		// A partially written snapshot file is left behind.
+		if err := ioutil.WriteFile(f.path+snapshotExt, []byte("partial"), 0666); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.path + snapshotExt)
+
+		restart(t, f, false).Clean(t)
+	})
+
+	t.Run("AfterStorageRename", func(t *testing.T) {
+		f, old := setup(t)
+		if err := f.snapshot(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The cache write never happened.
+		if err := ioutil.WriteFile(f.cachePath(), old, 0666); err != nil {
+			t.Fatal(err)
+		}
+
+		restart(t, f, false).Clean(t)
+	})
+
+	t.Run("DuringCacheWrite", func(t *testing.T) {
+		f, old := setup(t)
+		if err := f.snapshot(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The new cache was partially written but not moved into place.
+		if err := ioutil.WriteFile(f.cachePath(), old, 0666); err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(f.cachePath()+snapshotExt, []byte("PLCACHE"), 0666); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.cachePath() + snapshotExt)
+
+		restart(t, f, false).Clean(t)
+	})
+
+	t.Run("AfterCacheWrite", func(t *testing.T) {
+		f, _ := setup(t)
+		if err := f.snapshot(); err != nil {
+			t.Fatal(err)
+		}
+
+		restart(t, f, true).Clean(t)
+	})
+
+	t.Run("SnapshotTriggeredByWrite", func(t *testing.T) {
+		f, _ := setup(t)
+
+		// The write which triggers the snapshot must be in the cache.
+		f.MaxOpN = f.opN
+		f.mustSetBits(4, 1)
+		if f.opN != 0 {
+			t.Fatalf("expected snapshot, opN=%d", f.opN)
+		}
+		if err := f.closeStorage(); err != nil {
+			t.Fatal(err)
+		}
+		f2 := newFragment(f.path, "i", "f", viewStandard, 0)
+		f2.CacheType = f.CacheType
+		if err := f2.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer f2.Clean(t)
+		if ids, _, ok := f2.readCache(); !ok {
+			t.Fatal("expected cache to be trusted")
+		} else if !reflect.DeepEqual(ids, []uint64{1, 2, 3, 4}) {
+			t.Fatalf("unexpected ids: %v", ids)
+		}
+	})
+}
+
+// Ensure cache statistics reflect the cache and TopN storage reads.
+func TestFragment_CacheStats(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	f.mustSetBits(1, 1, 2, 3)
+	f.mustSetBits(2, 1, 2)
+	f.RecalculateCache()
+
+	if stats := f.cacheStats(); stats != (fragmentCacheStats{rows: 2, threshold: 2}) {
+		t.Fatalf("unexpected stats: %+v", stats)
 	}
 
-	// Reopen the index.
-	if err := index.reopen(); err != nil {
+	// Rows which aren't cached are read from storage.
+	if _, err := f.top(context.Background(), topOptions{RowIDs: []uint64{1, 3}}); err != nil {
+		t.Fatal(err)
+	} else if _, err := f.top(context.Background(), topOptions{Exact: true}); err != nil {
 		t.Fatal(err)
 	}
-
-	// Re-fetch fragment.
-	f = index.Field("f").view(viewStandard).Fragment(0)
-
-	// Re-verify correct cache type and size.
-	if cache, ok := f.cache.(*rankCache); !ok {
-		t.Fatalf("unexpected cache: %T", f.cache)
-	} else if cache.Len() != 1000 {
-		t.Fatalf("unexpected cache len: %d", cache.Len())
+	if stats := f.cacheStats(); stats.scans != 3 {
+		t.Fatalf("unexpected scans: %d", stats.scans)
 	}
 }
 
@@ -1313,6 +2855,82 @@ func TestFragment_WriteTo_ReadFrom(t *testing.T) {
 	}
 }
 
+// Ensure the cache copied with a fragment gives the same top rows on the
+// destination, truncated to the destination's cache size, for each cache
+// type which ranks rows.
+func TestFragment_WriteTo_ReadFrom_Cache(t *testing.T) {
+	for _, cacheType := range []string{CacheTypeRanked, CacheTypeLRU} {
+		t.Run(cacheType, func(t *testing.T) {
+			f0 := mustOpenFragment("i", "f", viewStandard, 0, cacheType)
+			defer f0.Clean(t)
+
+			// Row i has i+1 columns.
+			for rowID := uint64(0); rowID < 10; rowID++ {
+				for columnID := uint64(0); columnID <= rowID; columnID++ {
+					if _, err := f0.setBit(rowID, columnID); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			f0.RecalculateCache()
+
+			var buf bytes.Buffer
+			if _, err := f0.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			f1 := mustOpenFragment("i", "f", viewStandard, 0, cacheType)
+			defer f1.Clean(t)
+			f1.CacheSize = 3
+			if err := f1.reopen(); err != nil {
+				t.Fatal(err)
+			} else if _, err := f1.ReadFrom(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			if pairs, err := f1.top(context.Background(), topOptions{N: 10}); err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(pairs, []Pair{{ID: 9, Count: 10}, {ID: 8, Count: 9}, {ID: 7, Count: 8}}) {
+				t.Fatalf("unexpected top rows: %+v", pairs)
+			}
+		})
+	}
+}
+
+// BenchmarkFragment_Top compares the top rows of a fragment with and without
+// a filter on an attribute of the rows.
+func BenchmarkFragment_Top(b *testing.B) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(b)
+
+	const rowN = 1000
+	for rowID := uint64(0); rowID < rowN; rowID++ {
+		for columnID := uint64(0); columnID <= rowID%100; columnID++ {
+			f.mustSetBits(rowID, columnID)
+		}
+		f.RowAttrStore.SetAttrs(rowID, map[string]interface{}{"category": int64(rowID % 10)})
+	}
+	f.RecalculateCache()
+
+	for _, bm := range []struct {
+		name string
+		opt  topOptions
+	}{
+		{name: "Unfiltered", opt: topOptions{N: 50}},
+		{name: "Filtered", opt: topOptions{N: 50, FilterName: "category", FilterValues: []interface{}{int64(1), int64(2)}}},
+		{name: "FilteredNone", opt: topOptions{N: 50, FilterName: "category", FilterValues: []interface{}{int64(-1)}}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := f.top(context.Background(), bm.opt); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkFragment_Blocks(b *testing.B) {
 	if *FragmentPath == "" {
 		b.Skip("no fragment specified")
@@ -1377,7 +2995,7 @@ func TestFragment_Tanimoto(t *testing.T) {
 	f.mustSetBits(102, 1, 2, 10, 12)
 	f.RecalculateCache()
 
-	if pairs, err := f.top(topOptions{TanimotoThreshold: 50, Src: src}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{TanimotoThreshold: 50, Src: src}); err != nil {
 		t.Fatal(err)
 	} else if len(pairs) != 2 {
 		t.Fatalf("unexpected count: %d", len(pairs))
@@ -1400,7 +3018,7 @@ func TestFragment_Zero_Tanimoto(t *testing.T) {
 	f.mustSetBits(102, 1, 2, 10, 12)
 	f.RecalculateCache()
 
-	if pairs, err := f.top(topOptions{TanimotoThreshold: 0, Src: src}); err != nil {
+	if pairs, err := f.top(context.Background(), topOptions{TanimotoThreshold: 0, Src: src}); err != nil {
 		t.Fatal(err)
 	} else if len(pairs) != 3 {
 		t.Fatalf("unexpected count: %d", len(pairs))
@@ -1595,8 +3213,14 @@ func TestFragment_ConcurrentImport(t *testing.T) {
 		defer f.Clean(t)
 
 		eg := errgroup.Group{}
-		eg.Go(func() error { return f.bulkImportStandard([]uint64{1, 2}, []uint64{1, 2}, &ImportOptions{}) })
-		eg.Go(func() error { return f.bulkImportStandard([]uint64{3, 4}, []uint64{3, 4}, &ImportOptions{}) })
+		eg.Go(func() error {
+			_, _, err := f.bulkImportStandard([]uint64{1, 2}, []uint64{1, 2}, &ImportOptions{})
+			return err
+		})
+		eg.Go(func() error {
+			_, _, err := f.bulkImportStandard([]uint64{3, 4}, []uint64{3, 4}, &ImportOptions{})
+			return err
+		})
 		err := eg.Wait()
 		if err != nil {
 			t.Fatalf("importing data to fragment: %v", err)
@@ -1722,6 +3346,53 @@ func TestFragment_ImportMutex(t *testing.T) {
 	}
 }
 
+// Ensure a bulk import counts the distinct bits imported and those it changed.
+func TestFragment_ImportCount(t *testing.T) {
+	t.Run("Standard", func(t *testing.T) {
+		f := mustOpenFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+
+		// A bit imported twice in one batch is counted once.
+		if n, changed, err := f.bulkImportCount([]uint64{1, 2, 1, 1}, []uint64{3, 3, 3, 4}, &ImportOptions{}); err != nil {
+			t.Fatal(err)
+		} else if n != 3 || changed != 3 {
+			t.Fatalf("unexpected counts: n=%d changed=%d", n, changed)
+		}
+
+		// Bits which are already set are counted but haven't changed.
+		if n, changed, err := f.bulkImportCount([]uint64{1, 1, 5}, []uint64{3, 4, 6}, &ImportOptions{}); err != nil {
+			t.Fatal(err)
+		} else if n != 3 || changed != 1 {
+			t.Fatalf("unexpected counts: n=%d changed=%d", n, changed)
+		}
+
+		// Clearing counts the bits which were set.
+		if n, changed, err := f.bulkImportCount([]uint64{1, 1, 7}, []uint64{3, 3, 8}, &ImportOptions{Clear: true}); err != nil {
+			t.Fatal(err)
+		} else if n != 2 || changed != 1 {
+			t.Fatalf("unexpected counts: n=%d changed=%d", n, changed)
+		}
+	})
+
+	t.Run("Mutex", func(t *testing.T) {
+		f := mustOpenMutexFragment("i", "f", viewStandard, 0, "")
+		defer f.Clean(t)
+
+		if n, changed, err := f.bulkImportCount([]uint64{1, 1, 2}, []uint64{3, 3, 4}, &ImportOptions{}); err != nil {
+			t.Fatal(err)
+		} else if n != 2 || changed != 2 {
+			t.Fatalf("unexpected counts: n=%d changed=%d", n, changed)
+		}
+
+		// Moving a column to another row changes it; keeping its row doesn't.
+		if n, changed, err := f.bulkImportCount([]uint64{2, 2}, []uint64{3, 4}, &ImportOptions{}); err != nil {
+			t.Fatal(err)
+		} else if n != 2 || changed != 1 {
+			t.Fatalf("unexpected counts: n=%d changed=%d", n, changed)
+		}
+	})
+}
+
 // Ensure a fragment can import bool values.
 func TestFragment_ImportBool(t *testing.T) {
 	tests := []struct {
@@ -2403,7 +4074,7 @@ func TestFragment_RowsIteration(t *testing.T) {
 			t.Fatalf("Do not match %v %v", expectedAll, ids)
 		}
 
-		ids = f.rows(0, filterColumn(1))
+		ids = f.rows(0, filterColumn(1, ShardWidth))
 		if !reflect.DeepEqual(expectedOdd, ids) {
 			t.Fatalf("Do not match %v %v", expectedOdd, ids)
 		}
@@ -2427,7 +4098,7 @@ func TestFragment_RowsIteration(t *testing.T) {
 			t.Fatalf("Do not match %v %v", expected, ids)
 		}
 
-		ids = f.rows(0, filterColumn(66000))
+		ids = f.rows(0, filterColumn(66000, ShardWidth))
 		if !reflect.DeepEqual(expected, ids) {
 			t.Fatalf("Do not match %v %v", expected, ids)
 		}
@@ -2449,7 +4120,7 @@ func TestFragment_RowsIteration(t *testing.T) {
 				if !reflect.DeepEqual(expectedRows, ids) {
 					t.Fatalf("Do not match %v %v", expectedRows, ids)
 				}
-				ids = f.rows(0, filterColumn(c))
+				ids = f.rows(0, filterColumn(c, ShardWidth))
 				if !reflect.DeepEqual(expectedRows, ids) {
 					t.Fatalf("Do not match %v %v", expectedRows, ids)
 				}
@@ -2504,6 +4175,56 @@ func TestFragment_RoaringImport(t *testing.T) {
 	}
 }
 
+// Ensure whole rows can be imported as bitmaps of column offsets.
+func TestFragment_ImportRoaringRows(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, CacheTypeRanked)
+	defer f.Clean(t)
+
+	if err := f.importRoaringRows(map[uint64]*roaring.Bitmap{
+		1: roaring.NewBitmap(0, 5, 70000),
+		3: roaring.NewBitmap(ShardWidth - 1),
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.importRoaringRows(map[uint64]*roaring.Bitmap{
+		1: roaring.NewBitmap(5, 6),
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if cols := f.row(1).Columns(); !reflect.DeepEqual(cols, []uint64{0, 5, 6, 70000}) {
+		t.Fatalf("unexpected row 1: %v", cols)
+	} else if cols := f.row(3).Columns(); !reflect.DeepEqual(cols, []uint64{ShardWidth - 1}) {
+		t.Fatalf("unexpected row 3: %v", cols)
+	} else if cols := f.row(2).Columns(); len(cols) != 0 {
+		t.Fatalf("unexpected row 2: %v", cols)
+	}
+
+	if pairs, err := f.top(context.Background(), topOptions{}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(pairs, []Pair{{ID: 1, Count: 4}, {ID: 3, Count: 1}}) {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+
+	// Clear part of a row.
+	if err := f.importRoaringRows(map[uint64]*roaring.Bitmap{
+		1: roaring.NewBitmap(0, 70000),
+	}, true); err != nil {
+		t.Fatal(err)
+	} else if cols := f.row(1).Columns(); !reflect.DeepEqual(cols, []uint64{5, 6}) {
+		t.Fatalf("unexpected row 1 after clear: %v", cols)
+	}
+
+	// Offsets must be within the shard.
+	if err := f.importRoaringRows(map[uint64]*roaring.Bitmap{
+		2: roaring.NewBitmap(1, ShardWidth),
+	}, false); err == nil || !strings.Contains(err.Error(), "not within the shard width") {
+		t.Fatalf("expected shard width error, got: %v", err)
+	} else if cols := f.row(2).Columns(); len(cols) != 0 {
+		t.Fatalf("unexpected row 2 after rejected import: %v", cols)
+	}
+}
+
 // Test Importing roaring data.
 func TestFragment_RoaringImportTopN(t *testing.T) {
 	tests := []struct {
@@ -2533,7 +4254,7 @@ func TestFragment_RoaringImportTopN(t *testing.T) {
 				t.Fatalf("bulk importing ids: %v", err)
 			}
 			expPairs := calcTop(test.rowIDs, test.colIDs)
-			pairs, err := f.top(topOptions{})
+			pairs, err := f.top(context.Background(), topOptions{})
 			if err != nil {
 				t.Fatalf("executing top after bulk import: %v", err)
 			}
@@ -2548,7 +4269,7 @@ func TestFragment_RoaringImportTopN(t *testing.T) {
 			test.rowIDs = append(test.rowIDs, test.rowIDs2...)
 			test.colIDs = append(test.colIDs, test.colIDs2...)
 			expPairs = calcTop(test.rowIDs, test.colIDs)
-			pairs, err = f.top(topOptions{})
+			pairs, err = f.top(context.Background(), topOptions{})
 			if err != nil {
 				t.Fatalf("executing top after bulk import: %v", err)
 			}
@@ -2565,7 +4286,7 @@ func TestFragment_RoaringImportTopN(t *testing.T) {
 			f.importRoaring(buf.Bytes(), false)
 			rows, cols := toRowsCols(test.roaring)
 			expPairs = calcTop(append(test.rowIDs, rows...), append(test.colIDs, cols...))
-			pairs, err = f.top(topOptions{})
+			pairs, err = f.top(context.Background(), topOptions{})
 			if err != nil {
 				t.Fatalf("executing top after roaring import: %v", err)
 			}
@@ -2656,6 +4377,131 @@ func calcExpected(inputs ...[]uint64) [][]uint64 {
 	return ret
 }
 
+// Ensure a fragment can return the pairs of a block a page at a time.
+func TestFragment_BlockData(t *testing.T) {
+	f := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f.Clean(t)
+
+	// Fill more than one container, and set a bit in the next block.
+	var wantRowIDs, wantColumnIDs []uint64
+	for col := uint64(0); col < 70000; col += 7 {
+		if _, err := f.setBit(3, col); err != nil {
+			t.Fatal(err)
+		}
+		wantRowIDs, wantColumnIDs = append(wantRowIDs, 3), append(wantColumnIDs, col)
+	}
+	if _, err := f.setBit(HashBlockSize, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if rowIDs, columnIDs, more := f.blockData(0, 0, 0); more {
+		t.Fatal("expected whole block")
+	} else if !reflect.DeepEqual(rowIDs, wantRowIDs) || !reflect.DeepEqual(columnIDs, wantColumnIDs) {
+		t.Fatalf("unexpected block: %d pairs", len(rowIDs))
+	}
+
+	for _, limit := range []int{1, 1000, 9999, 10000} {
+		var rowIDs, columnIDs []uint64
+		for offset, more := 0, true; more; {
+			var r, c []uint64
+			r, c, more = f.blockData(0, offset, limit)
+			if len(r) > limit {
+				t.Fatalf("limit %d: unexpected page of %d pairs", limit, len(r))
+			}
+			rowIDs, columnIDs = append(rowIDs, r...), append(columnIDs, c...)
+			offset += len(r)
+		}
+		if !reflect.DeepEqual(rowIDs, wantRowIDs) || !reflect.DeepEqual(columnIDs, wantColumnIDs) {
+			t.Fatalf("limit %d: unexpected pages: %d pairs", limit, len(rowIDs))
+		}
+	}
+
+	if rowIDs, columnIDs, more := f.blockData(0, 9999, 2); more || !reflect.DeepEqual(rowIDs, []uint64{3}) || !reflect.DeepEqual(columnIDs, []uint64{69993}) {
+		t.Fatalf("unexpected last page: %v %v %v", rowIDs, columnIDs, more)
+	} else if rowIDs, _, more := f.blockData(0, 10000, 2); more || len(rowIDs) != 0 {
+		t.Fatalf("unexpected page past the end: %v %v", rowIDs, more)
+	}
+}
+
+// blockDataClient serves the blocks of fragments on other nodes by host.
+type blockDataClient struct {
+	nopInternalClient
+	fragments map[string]*fragment
+}
+
+func (c *blockDataClient) BlockData(ctx context.Context, uri *URI, index, field, view string, shard uint64, block, offset, limit int) (rowIDs, columnIDs []uint64, more bool, err error) {
+	rowIDs, columnIDs, more = c.fragments[uri.Host].blockData(block, offset, limit)
+	return rowIDs, columnIDs, more, nil
+}
+
+func (c *blockDataClient) ImportRoaring(ctx context.Context, uri *URI, index, field string, shard uint64, remote bool, req *ImportRoaringRequest) error {
+	for _, data := range req.Views {
+		if err := c.fragments[uri.Host].importRoaring(data, req.Clear); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ensure a block is merged by consensus when it is read a page at a time.
+func TestFragmentSyncer_SyncBlock(t *testing.T) {
+	cluster := NewTestCluster(3)
+	cluster.ReplicaN = 3
+
+	f0 := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f0.Clean(t)
+	f1 := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f1.Clean(t)
+	f2 := mustOpenFragment("i", "f", viewStandard, 0, "")
+	defer f2.Clean(t)
+	cluster.InternalClient = &blockDataClient{fragments: map[string]*fragment{
+		cluster.nodes[1].URI.Host: f1,
+		cluster.nodes[2].URI.Host: f2,
+	}}
+
+	// Each bit is set on two of the three nodes if its column is a multiple
+	// of at least two of 2, 3 and 5.
+	var want []uint64
+	for col := uint64(0); col < 300; col++ {
+		var n int
+		for i, f := range []*fragment{f0, f1, f2} {
+			if col%[]uint64{2, 3, 5}[i] == 0 {
+				n++
+				if _, err := f.setBit(HashBlockSize-1, col); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if n >= 2 {
+			want = append(want, col)
+		}
+	}
+
+	// The first bit of the next block isn't synced.
+	if _, err := f1.setBit(HashBlockSize, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	s := fragmentSyncer{
+		Fragment: f0,
+		Node:     cluster.nodes[0],
+		Cluster:  cluster,
+		PageSize: 7,
+		Closing:  make(chan struct{}),
+	}
+	if err := s.syncBlock(0); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, f := range []*fragment{f0, f1, f2} {
+		if a := f.row(HashBlockSize - 1).Columns(); !reflect.DeepEqual(a, want) {
+			t.Fatalf("unexpected columns(%d): %v", i, a)
+		} else if n := f.row(HashBlockSize).Count(); n != map[int]uint64{1: 1}[i] {
+			t.Fatalf("unexpected count of next block(%d): %d", i, n)
+		}
+	}
+}
+
 func TestFragmentRowIterator(t *testing.T) {
 	t.Run("basic", func(t *testing.T) {
 		f := mustOpenFragment("i", "f", "v", 0, CacheTypeRanked)
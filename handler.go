@@ -2,6 +2,10 @@ package pilosa
 
 import (
 	"encoding/json"
+	"time"
+
+	"github.com/pilosa/pilosa/pql"
+	"github.com/pkg/errors"
 )
 
 // QueryRequest represent a request to process a query.
@@ -25,9 +29,16 @@ type QueryRequest struct {
 	// Do not return columns, if true.
 	ExcludeColumns bool
 
+	// Use the index's column label and the fields' row labels in place of
+	// "id" in JSON results, if true.
+	Labels bool
+
 	// If true, indicates that query is part of a larger distributed query.
 	// If false, this request is on the originating node.
 	Remote bool
+
+	// Return a profile of where the time of the query went, if true.
+	Profile bool
 }
 
 // QueryResponse represent a response from a processed query.
@@ -39,26 +50,111 @@ type QueryResponse struct {
 	// Set of column attribute objects matching IDs returned in Result.
 	ColumnAttrSets []*ColumnAttrSet
 
+	// Time ranges which were widened because of precise=true.
+	TimeRoundings []*TimeRounding
+
+	// Labels used in place of "id" when encoding JSON: ColumnLabel for
+	// column attribute sets, and RowLabels[i] for the pairs of Results[i].
+	ColumnLabel string
+	RowLabels   []string
+
+	// Profile of the query, if requested.
+	Profile *QueryProfile
+
 	// Error during parsing or execution.
 	Err error
 }
 
+// TimeRounding describes a bound of a time ranged Row() query which was
+// rounded to a boundary of the smallest unit of the field's time quantum.
+type TimeRounding struct {
+	Field       string    `json:"field"`
+	Arg         string    `json:"arg"`
+	Time        time.Time `json:"time"`
+	Rounded     time.Time `json:"rounded"`
+	Granularity string    `json:"granularity"`
+}
+
 // MarshalJSON marshals QueryResponse into a JSON-encoded byte slice
 func (resp *QueryResponse) MarshalJSON() ([]byte, error) {
 	var output struct {
-		Results        []interface{}    `json:"results,omitempty"`
-		ColumnAttrSets []*ColumnAttrSet `json:"columnAttrs,omitempty"`
-		Err            string           `json:"error,omitempty"`
+		Results        []interface{}   `json:"results,omitempty"`
+		ColumnAttrSets []interface{}   `json:"columnAttrs,omitempty"`
+		TimeRoundings  []*TimeRounding `json:"timeRoundings,omitempty"`
+		Profile        *QueryProfile   `json:"profile,omitempty"`
+		Err            string          `json:"error,omitempty"`
+		Code           string          `json:"code,omitempty"`
+		Position       *errorPosition  `json:"position,omitempty"`
 	}
 	output.Results = resp.Results
-	output.ColumnAttrSets = resp.ColumnAttrSets
+	output.TimeRoundings = resp.TimeRoundings
+	output.Profile = resp.Profile
+
+	// Pairs results are relabeled with the row label of their field.
+	if resp.RowLabels != nil {
+		output.Results = make([]interface{}, len(resp.Results))
+		for i, result := range resp.Results {
+			pairs, ok := result.([]Pair)
+			if !ok || i >= len(resp.RowLabels) || resp.RowLabels[i] == "" {
+				output.Results[i] = result
+				continue
+			}
+			labeled := make([]json.RawMessage, len(pairs))
+			for j := range pairs {
+				buf, err := relabelJSON(pairs[j], resp.RowLabels[i])
+				if err != nil {
+					return nil, err
+				}
+				labeled[j] = buf
+			}
+			output.Results[i] = labeled
+		}
+	}
+
+	for _, cas := range resp.ColumnAttrSets {
+		if resp.ColumnLabel == "" {
+			output.ColumnAttrSets = append(output.ColumnAttrSets, cas)
+			continue
+		}
+		buf, err := relabelJSON(cas, resp.ColumnLabel)
+		if err != nil {
+			return nil, err
+		}
+		output.ColumnAttrSets = append(output.ColumnAttrSets, buf)
+	}
 
 	if resp.Err != nil {
 		output.Err = resp.Err.Error()
+		output.Code = ErrorCode(resp.Err)
+		if pe, ok := errors.Cause(resp.Err).(*pql.ParseError); ok {
+			output.Position = &errorPosition{Line: pe.Line, Symbol: pe.Symbol}
+		}
 	}
 	return json.Marshal(output)
 }
 
+// errorPosition is the position in a query of a parse error.
+type errorPosition struct {
+	Line   int `json:"line"`
+	Symbol int `json:"symbol"`
+}
+
+// relabelJSON encodes v as a JSON object with its "id" key renamed to label.
+func relabelJSON(v interface{}, label string) (json.RawMessage, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, err
+	} else if id, ok := m["id"]; ok {
+		delete(m, "id")
+		m[label] = id
+	}
+	return json.Marshal(m)
+}
+
 type Handler interface {
 	Serve() error
 	Close() error
@@ -94,6 +190,27 @@ type ImportRequest struct {
 	RowKeys    []string
 	ColumnKeys []string
 	Timestamps []int64
+
+	// View, if set, is the only view the bits are imported into. The IDs
+	// are used as given and the timestamps are ignored.
+	View string
+}
+
+// ImportRoaringRowsRequest imports whole rows of a single shard, each given
+// as a serialized roaring bitmap of the column offsets within the shard.
+type ImportRoaringRowsRequest struct {
+	Index string
+	Field string
+	Shard uint64
+	Rows  []ImportRoaringRow
+}
+
+// ImportRoaringRow is a row of an ImportRoaringRowsRequest. Data is a roaring
+// bitmap, in either the standard or pilosa format, whose bits are column
+// offsets from the start of the shard.
+type ImportRoaringRow struct {
+	RowID uint64
+	Data  []byte
 }
 
 type ImportRoaringRequest struct {
@@ -103,19 +220,46 @@ type ImportRoaringRequest struct {
 
 type ImportResponse struct {
 	Err string
+
+	// The number of fragments on the node whose caches are still being
+	// rebuilt after imports. TopN results are only served from the cache
+	// once this reaches zero.
+	PendingCacheRebuilds uint64
+
+	// The number of bits written to each view by the import.
+	Views map[string]uint64
+
+	// The number of distinct bits of the import in each shard, and how many
+	// of them changed.
+	Counts []ImportCount
+}
+
+// ImportCount is the number of distinct bits imported into a shard, and how
+// many of them were set, or cleared, by the import rather than already so.
+type ImportCount struct {
+	Shard   uint64
+	Bits    uint64
+	Changed uint64
 }
 
+// BlockDataRequest requests the pairs of a fragment block. Offset and Limit
+// select a page of the pairs; a zero Limit returns all pairs from Offset.
 type BlockDataRequest struct {
-	Index string
-	Field string
-	View  string
-	Shard uint64
-	Block uint64
+	Index  string
+	Field  string
+	View   string
+	Shard  uint64
+	Block  uint64
+	Offset uint64
+	Limit  uint64
 }
 
+// BlockDataResponse holds a page of the pairs of a fragment block. More is
+// true if the block has pairs after the page.
 type BlockDataResponse struct {
 	RowIDs    []uint64
 	ColumnIDs []uint64
+	More      bool
 }
 
 type TranslateKeysRequest struct {
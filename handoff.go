@@ -0,0 +1,488 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pilosa/pilosa/tracing"
+	"github.com/pkg/errors"
+)
+
+// A node removed with a handoff, unlike one removed by a resize job, keeps
+// serving queries while it's removed. It's marked as leaving on every node,
+// which makes the owners of each shard both its owners with the node and
+// those without it, the former first so that reads go to the nodes which
+// hold the shard's data. Each node which gains shards pulls them from their
+// other owners by block sync, and reports its progress to the coordinator,
+// which drops the leaving node from the cluster once every node has pulled
+// its shards.
+//
+// The leaving node is saved with the topology, so that the owners stay the
+// same across restarts. A removal interrupted by a restart or an error is
+// resumed by requesting it again: the shards already pulled have the same
+// block checksums on every owner, and aren't copied again.
+
+// NodeRemoval is the progress of the removal of a node with a handoff, as
+// tracked by the coordinator.
+type NodeRemoval struct {
+	// ID of the node being removed.
+	ID string `json:"id"`
+
+	// The number of shards the other nodes pull, and the number pulled so
+	// far.
+	Shards      int `json:"shards"`
+	Transferred int `json:"transferred"`
+
+	// Done is set once the node has been dropped from the cluster. Error is
+	// the first error of a node, which stops the removal until it's
+	// requested again.
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+
+	// The progress of each node which stays in the cluster.
+	Nodes []*NodeRemovalProgress `json:"nodes"`
+}
+
+// NodeRemovalProgress is the progress of a node in pulling the shards it
+// gains when a node is removed.
+type NodeRemovalProgress struct {
+	ID          string `json:"id"`
+	Shards      int    `json:"shards"`
+	Transferred int    `json:"transferred"`
+	Done        bool   `json:"done"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NodeHandoffMessage is sent by the coordinator to each node which stays in
+// the cluster when node LeavingID is removed, to pull the shards it gains.
+type NodeHandoffMessage struct {
+	LeavingID   string
+	Coordinator *Node
+}
+
+// NodeHandoffProgress is sent by a node to the coordinator as it pulls the
+// shards it gains when node LeavingID is removed.
+type NodeHandoffProgress struct {
+	LeavingID   string
+	Node        *Node
+	Shards      uint64
+	Transferred uint64
+	Done        bool
+	Error       string
+}
+
+// nodeRemoval is the state of a removal on the coordinator. It's only held
+// in memory; after the coordinator restarts, the removal is resumed by
+// requesting it again.
+type nodeRemoval struct {
+	id    string
+	done  bool
+	nodes map[string]*NodeRemovalProgress
+}
+
+// status returns the progress of the removal, with the nodes sorted by ID.
+func (r *nodeRemoval) status() *NodeRemoval {
+	ids := make([]string, 0, len(r.nodes))
+	for id := range r.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	s := &NodeRemoval{ID: r.id, Done: r.done, Nodes: make([]*NodeRemovalProgress, 0, len(ids))}
+	for _, id := range ids {
+		p := *r.nodes[id]
+		s.Shards += p.Shards
+		s.Transferred += p.Transferred
+		if s.Error == "" && p.Error != "" {
+			s.Error = fmt.Sprintf("node %s: %s", id, p.Error)
+		}
+		s.Nodes = append(s.Nodes, &p)
+	}
+	return s
+}
+
+// handoffShard is a shard of an index which a node pulls during a handoff.
+type handoffShard struct {
+	index string
+	shard uint64
+}
+
+// removeNodeWithHandoff starts, or resumes, the removal of node nodeID with
+// a handoff. It should only be called on the coordinator. The nodes which
+// had finished pulling their shards when a removal is resumed aren't asked
+// to pull them again.
+func (c *cluster) removeNodeWithHandoff(nodeID string) (*NodeRemoval, error) {
+	c.mu.Lock()
+	nodes, err := c.unprotectedStartNodeRemoval(nodeID)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The nodes are asked to pull their shards once the lock is released,
+	// since they report their progress to this node as they go.
+	m := &NodeHandoffMessage{LeavingID: nodeID, Coordinator: c.Node}
+	for _, node := range nodes {
+		if node.ID == c.Node.ID {
+			err = c.followHandoff(m)
+		} else {
+			err = c.sendTo(node, m)
+		}
+		if err != nil {
+			c.reportHandoff(c.Node, NodeHandoffProgress{LeavingID: nodeID, Node: node, Error: err.Error()})
+		}
+	}
+	return c.nodeRemoval()
+}
+
+// unprotectedStartNodeRemoval marks node nodeID as leaving on every node and
+// returns the nodes to ask to pull their shards.
+func (c *cluster) unprotectedStartNodeRemoval(nodeID string) ([]*Node, error) {
+	if !c.unprotectedIsCoordinator() {
+		return nil, fmt.Errorf("node removal requests are only valid on the coordinator node: %s",
+			c.unprotectedCoordinatorNode().ID)
+	} else if c.state != ClusterStateNormal && c.state != ClusterStateDegraded {
+		return nil, fmt.Errorf("cluster must be '%s' to remove a node but is '%s'",
+			ClusterStateNormal, c.state)
+	} else if c.leaving != "" && c.leaving != nodeID {
+		return nil, fmt.Errorf("node %s is already being removed", c.leaving)
+	} else if c.unprotectedNodeByID(nodeID) == nil {
+		// The node must be up, as the other nodes pull its shards from it.
+		return nil, errors.Wrap(ErrNodeIDNotExists, "finding node to remove")
+	} else if nodeID == c.Node.ID {
+		return nil, fmt.Errorf("coordinator cannot be removed; first, make a different node the new coordinator")
+	}
+
+	if c.removal == nil || c.removal.id != nodeID {
+		c.removal = &nodeRemoval{id: nodeID, nodes: make(map[string]*NodeRemovalProgress)}
+	}
+	if err := c.unprotectedSetLeaving(nodeID); err != nil {
+		return nil, errors.Wrap(err, "marking node as leaving")
+	}
+
+	// Nodes restarted since the removal started learn of it here too.
+	if err := c.unprotectedSetStateAndBroadcast(c.state); err != nil {
+		return nil, errors.Wrap(err, "broadcasting status")
+	}
+
+	var nodes []*Node
+	for _, id := range c.unprotectedRemainingIDs(nodeID) {
+		if p := c.removal.nodes[id]; p != nil && p.Done {
+			continue
+		}
+		p := &NodeRemovalProgress{ID: id}
+		c.removal.nodes[id] = p
+
+		node := c.unprotectedNodeByID(id)
+		if node == nil {
+			p.Error = "node is unavailable"
+			continue
+		}
+		p.Shards = len(c.unprotectedHandoffShards(nodeID, id))
+		nodes = append(nodes, node)
+	}
+	c.logger.Printf("removing node %s, %d nodes pull its shards", nodeID, len(nodes))
+
+	return nodes, c.unprotectedConsiderNodeRemoval()
+}
+
+// unprotectedRemainingIDs returns the IDs of the nodes which stay in the
+// cluster when node leavingID is removed, including those which are down.
+func (c *cluster) unprotectedRemainingIDs(leavingID string) []string {
+	var ids []string
+	if c.Topology != nil {
+		c.Topology.mu.RLock()
+		ids = append(ids, c.Topology.nodeIDs...)
+		c.Topology.mu.RUnlock()
+	}
+	if len(ids) == 0 {
+		ids = c.nodeIDs()
+	}
+
+	remaining := ids[:0]
+	for _, id := range ids {
+		if id != leavingID {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
+
+// nodeRemoval returns the progress of the current or last removal. It should
+// only be called on the coordinator.
+func (c *cluster) nodeRemoval() (*NodeRemoval, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.unprotectedIsCoordinator() {
+		return nil, ErrNodeNotCoordinator
+	}
+	if c.removal == nil {
+		if c.leaving != "" {
+			return &NodeRemoval{
+				ID:    c.leaving,
+				Error: "the removal was interrupted by a restart of the coordinator, request it again to resume it",
+			}, nil
+		}
+		return nil, ErrNodeRemovalNotStarted
+	}
+	return c.removal.status(), nil
+}
+
+// markNodeHandoff records the progress of a node during a removal, and
+// drops the leaving node from the cluster once every node has pulled its
+// shards.
+func (c *cluster) markNodeHandoff(p *NodeHandoffProgress) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := c.removal
+	if r == nil || r.id != p.LeavingID || r.done {
+		return fmt.Errorf("node %s is not being removed", p.LeavingID)
+	}
+	r.nodes[p.Node.ID] = &NodeRemovalProgress{
+		ID:          p.Node.ID,
+		Shards:      int(p.Shards),
+		Transferred: int(p.Transferred),
+		Done:        p.Done,
+		Error:       p.Error,
+	}
+	if p.Error != "" {
+		c.logger.Printf("node %s failed to pull the shards of node %s: %s", p.Node.ID, p.LeavingID, p.Error)
+	}
+	return c.unprotectedConsiderNodeRemoval()
+}
+
+// unprotectedConsiderNodeRemoval drops the leaving node from the cluster if
+// every other node has pulled its shards.
+func (c *cluster) unprotectedConsiderNodeRemoval() error {
+	r := c.removal
+	for _, id := range c.unprotectedRemainingIDs(r.id) {
+		if p := r.nodes[id]; p == nil || !p.Done {
+			return nil
+		}
+	}
+
+	c.logger.Printf("every node has pulled the shards of node %s, removing it", r.id)
+	r.done = true
+	if err := c.unprotectedSetLeaving(""); err != nil {
+		return errors.Wrap(err, "clearing leaving node")
+	} else if err := c.removeNode(r.id); err != nil {
+		return errors.Wrap(err, "removing node")
+	} else if err := c.unprotectedSetStateAndBroadcast(c.determineClusterState()); err != nil {
+		return errors.Wrap(err, "broadcasting status")
+	}
+	c.unprotectedCleanHolder()
+	return nil
+}
+
+// unprotectedSetLeaving marks node id as leaving, or no node if id is empty,
+// and saves it with the topology.
+func (c *cluster) unprotectedSetLeaving(id string) error {
+	if id == c.leaving {
+		return nil
+	}
+	c.logger.Printf("change leaving node from %q to %q on %s", c.leaving, id, c.Node.ID)
+	c.leaving = id
+
+	if c.Topology == nil {
+		return nil
+	}
+	c.Topology.mu.Lock()
+	c.Topology.leavingID = id
+	c.Topology.mu.Unlock()
+	return c.saveTopology()
+}
+
+// followHandoff starts pulling the shards this node gains when node
+// m.LeavingID is removed, unless it's already pulling them.
+func (c *cluster) followHandoff(m *NodeHandoffMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leaving != m.LeavingID {
+		return fmt.Errorf("node %s is not being removed", m.LeavingID)
+	} else if c.handoff == m.LeavingID {
+		return nil
+	}
+	c.handoff = m.LeavingID
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.pullHandoffShards(m.LeavingID, m.Coordinator)
+
+		c.mu.Lock()
+		c.handoff = ""
+		c.mu.Unlock()
+	}()
+	return nil
+}
+
+// pullHandoffShards syncs each shard this node gains when node leavingID is
+// removed with its other owners, reporting its progress to coordinator
+// after each one. It stops if the cluster closes or the node stops leaving.
+func (c *cluster) pullHandoffShards(leavingID string, coordinator *Node) {
+	c.holder.opened.Recv()
+
+	c.mu.RLock()
+	shards := c.unprotectedHandoffShards(leavingID, c.Node.ID)
+	c.mu.RUnlock()
+	c.logger.Printf("pulling %d shards for the removal of node %s", len(shards), leavingID)
+
+	p := NodeHandoffProgress{LeavingID: leavingID, Node: c.Node, Shards: uint64(len(shards))}
+	for _, s := range shards {
+		select {
+		case <-c.closing:
+			return
+		default:
+		}
+		if c.leavingID() != leavingID {
+			return
+		}
+
+		if err := c.pullShard(s.index, s.shard); err != nil {
+			p.Error = fmt.Sprintf("pulling shard %d of index %s: %s", s.shard, s.index, err)
+			c.reportHandoff(coordinator, p)
+			return
+		}
+		p.Transferred++
+		if p.Transferred < p.Shards {
+			c.reportHandoff(coordinator, p)
+		}
+	}
+	p.Done = true
+	c.reportHandoff(coordinator, p)
+}
+
+// reportHandoff sends the progress of a node to coordinator.
+func (c *cluster) reportHandoff(coordinator *Node, p NodeHandoffProgress) {
+	var err error
+	if coordinator.ID == c.Node.ID {
+		err = c.markNodeHandoff(&p)
+	} else {
+		err = c.sendTo(coordinator, &p)
+	}
+	if err != nil {
+		c.logger.Printf("reporting handoff progress: %s", err)
+	}
+}
+
+// leavingID returns the ID of the node being removed, if any.
+func (c *cluster) leavingID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.leaving
+}
+
+// unprotectedHandoffShards returns the shards of each index which node
+// nodeID owns without node leavingID in the cluster, but not with it.
+func (c *cluster) unprotectedHandoffShards(leavingID, nodeID string) []handoffShard {
+	remaining := Nodes(c.nodes).FilterID(leavingID)
+	if len(remaining) == len(c.nodes) {
+		return nil
+	}
+
+	var shards []handoffShard
+	for _, idx := range c.holder.Indexes() {
+		idx.AvailableShards().ForEach(func(shard uint64) {
+			p := c.partition(idx.Name(), shard)
+			if !Nodes(c.ringNodes(c.nodes, p)).ContainsID(nodeID) && Nodes(c.ringNodes(remaining, p)).ContainsID(nodeID) {
+				shards = append(shards, handoffShard{index: idx.Name(), shard: shard})
+			}
+		})
+	}
+	return shards
+}
+
+// pullShard syncs each fragment of a shard of an index which its other
+// owners hold, creating it if it doesn't exist on this node. Views in which
+// no owner holds the shard get no fragment.
+func (c *cluster) pullShard(index string, shard uint64) error {
+	span, ctx := tracing.StartSpanFromContext(context.Background(), "Cluster.pullShard")
+	defer span.Finish()
+
+	idx := c.holder.Index(index)
+	if idx == nil {
+		return ErrIndexNotFound
+	}
+	c.mu.RLock()
+	owners := Nodes(c.shardNodes(index, shard)).FilterID(c.Node.ID)
+	c.mu.RUnlock()
+
+	for _, f := range idx.Fields() {
+		views, err := c.shardViews(ctx, owners, index, f.Name(), shard)
+		if err != nil {
+			return errors.Wrapf(err, "listing views of field %s", f.Name())
+		}
+		for _, name := range views {
+			v, err := f.createViewIfNotExists(name)
+			if err != nil {
+				return errors.Wrap(err, "creating view")
+			}
+			frag, err := v.acquireFragmentIfNotExists(shard)
+			if err != nil {
+				return errors.Wrap(err, "creating fragment")
+			}
+
+			fs := fragmentSyncer{
+				Fragment: frag,
+				Node:     c.Node,
+				Cluster:  c,
+				Closing:  c.closing,
+			}
+			err = fs.syncFragment()
+			frag.release()
+			if err != nil {
+				return errors.Wrapf(err, "syncing field %s, view %s", f.Name(), name)
+			}
+		}
+	}
+	return nil
+}
+
+// shardViews returns the names of the views of a field in which any of nodes
+// holds a fragment of shard, sorted.
+func (c *cluster) shardViews(ctx context.Context, nodes []*Node, index, field string, shard uint64) ([]string, error) {
+	held := make(map[string]struct{})
+	for _, node := range nodes {
+		views, err := c.InternalClient.FieldViews(ctx, &node.URI, index, field)
+		if err == ErrFieldNotFound {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "getting views from node %s", node.ID)
+		}
+
+		for _, view := range views {
+			if _, ok := held[view.Name]; ok {
+				continue
+			}
+			if _, err := c.InternalClient.FragmentBlocks(ctx, &node.URI, index, field, view.Name, shard); err == ErrFragmentNotFound {
+				continue
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "getting blocks of view %s from node %s", view.Name, node.ID)
+			}
+			held[view.Name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(held))
+	for name := range held {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -21,9 +21,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -39,6 +41,26 @@ const (
 	// defaultCacheFlushInterval is the default value for Fragment.CacheFlushInterval.
 	defaultCacheFlushInterval = 1 * time.Minute
 
+	// defaultWriteStatsInterval is the default interval at which the write
+	// statistics of fields are persisted to their meta files.
+	defaultWriteStatsInterval = 5 * time.Second
+
+	// defaultCacheMemoryCheckInterval is the default interval at which the
+	// memory used by fragment caches is checked against the budget.
+	defaultCacheMemoryCheckInterval = 10 * time.Second
+
+	// defaultFragmentIdleCheckInterval is the default interval at which
+	// idle fragments are closed, if enabled.
+	defaultFragmentIdleCheckInterval = 10 * time.Second
+
+	// defaultCacheRebuildWorkers is the default number of fragment caches
+	// which may be rebuilt concurrently after imports.
+	defaultCacheRebuildWorkers = 2
+
+	// defaultSnapshotWorkers is the default number of fragments which may be
+	// snapshotted concurrently once their op logs are full.
+	defaultSnapshotWorkers = 2
+
 	// fileLimit is the maximum open file limit (ulimit -n) to automatically set.
 	fileLimit = 262144 // (512^2)
 
@@ -62,6 +84,10 @@ type Holder struct {
 
 	broadcaster broadcaster
 
+	// Fetches the shards of field copies between indexes. Nil outside of a
+	// cluster, where this node holds every shard.
+	shardFetcher shardFetcher
+
 	NewAttrStore func(string) AttrStore
 
 	// Close management
@@ -77,6 +103,106 @@ type Holder struct {
 	// The interval at which the cached row ids are persisted to disk.
 	cacheFlushInterval time.Duration
 
+	// The interval at which the write statistics of fields are persisted.
+	writeStatsInterval time.Duration
+
+	// CacheMaxMemory is the approximate number of bytes that the caches of
+	// all fragments may use before the least recently used are shrunk.
+	// Zero disables the limit.
+	CacheMaxMemory int64
+
+	// AllowLegacyNames opens indexes and fields whose names, created before
+	// the naming rules were enforced, break them. Otherwise they are skipped.
+	AllowLegacyNames bool
+
+	// OpenWorkers is the number of fields across all indexes which are
+	// opened concurrently by Open. Values less than one open them one at
+	// a time.
+	OpenWorkers int
+
+	// Limits on the number of indexes, fields per index, and views per
+	// field which can be created. Existing ones beyond the limits are still
+	// opened. Zero is unlimited.
+	MaxIndexes        int
+	MaxFieldsPerIndex int
+	MaxViewsPerField  int
+
+	// Durability determines which writes of fragments and attribute stores
+	// are synced to disk.
+	Durability Durability
+
+	// MaxOpN is the number of ops appended to the op log of a fragment
+	// before the fragment is snapshotted. Zero uses the default.
+	MaxOpN int
+
+	// FragmentCloseTimeout is how long closing a fragment waits for the
+	// readers holding it, such as exports, to finish. Zero uses the default.
+	FragmentCloseTimeout time.Duration
+
+	// FragmentOpenConcurrency is the number of fragments of a view opened at
+	// once. Zero uses GOMAXPROCS.
+	FragmentOpenConcurrency int
+
+	// FragmentOpenPolicy determines whether a fragment which fails to open
+	// fails opening the holder, or is quarantined.
+	FragmentOpenPolicy FragmentOpenPolicy
+
+	// FragmentIdleTimeout is how long a fragment may go without being
+	// accessed before it is closed, and MaxOpenFragmentsPerView the number
+	// of fragments of a view kept open before the least recently accessed
+	// are closed. Closed fragments are reopened when next accessed. Zero
+	// disables either limit.
+	FragmentIdleTimeout     time.Duration
+	MaxOpenFragmentsPerView int
+
+	// The interval at which idle fragments are closed.
+	fragmentIdleCheckInterval time.Duration
+
+	// SnapshotWorkers is the number of fragments snapshotted at once in the
+	// background once their op logs pass MaxOpN. Zero uses the default.
+	SnapshotWorkers int
+
+	// PreserveOrphans moves the temporary files a crash left behind under
+	// the .orphans directory when the holder is opened, instead of removing
+	// them, so they can be inspected.
+	PreserveOrphans bool
+
+	// StrictLimits fails Open if the open file limit or the maximum number
+	// of memory mappings is less than the data is expected to need, instead
+	// of logging a warning.
+	StrictLimits bool
+
+	// FragmentLayout is the layout of the fragments of new views. Existing
+	// views keep the layout they were created with.
+	FragmentLayout FragmentLayout
+
+	// ReadOnly opens the data directory without modifying it. Files are
+	// opened read-only, nothing is created, migrated or cleaned up, and
+	// writes fail with ErrReadOnly.
+	ReadOnly bool
+
+	// Tracks memory used by fragment caches.
+	cacheAccountant *cacheAccountant
+
+	// The interval at which cache memory usage is checked.
+	cacheMemoryCheckInterval time.Duration
+
+	// Rebuilds the caches of imported fragments in the background.
+	cacheRebuilder      *cacheRebuilder
+	cacheRebuildWorkers int
+
+	// Snapshots fragments whose op logs are full in the background.
+	snapshotQueue *snapshotQueue
+
+	// Time view migrations by field.
+	timeMigrations *timeMigrations
+
+	// Copies by destination field.
+	fieldCopies *fieldCopies
+
+	// Changes whenever an index or field is created, deleted or renamed.
+	schemaGen *schemaGeneration
+
 	Logger logger.Logger
 }
 
@@ -123,6 +249,28 @@ func NewHolder() *Holder {
 		NewAttrStore: newNopAttrStore,
 
 		cacheFlushInterval: defaultCacheFlushInterval,
+		writeStatsInterval: defaultWriteStatsInterval,
+
+		OpenWorkers: runtime.NumCPU(),
+		Durability:  DurabilityDefault,
+
+		FragmentLayout:     FragmentLayoutFlat,
+		FragmentOpenPolicy: FragmentOpenStrict,
+
+		cacheAccountant:          newCacheAccountant(),
+		cacheMemoryCheckInterval: defaultCacheMemoryCheckInterval,
+
+		fragmentIdleCheckInterval: defaultFragmentIdleCheckInterval,
+
+		cacheRebuilder:      newCacheRebuilder(),
+		cacheRebuildWorkers: defaultCacheRebuildWorkers,
+
+		snapshotQueue: newSnapshotQueue(),
+
+		timeMigrations: newTimeMigrations(),
+		fieldCopies:    newFieldCopies(),
+
+		schemaGen: newSchemaGeneration(),
 
 		Logger: logger.NopLogger,
 	}
@@ -134,10 +282,34 @@ func (h *Holder) Open() error {
 	h.closing = make(chan struct{})
 
 	h.setFileLimit()
+	h.cacheAccountant.stats = h.Stats
+	h.cacheRebuilder.stats = h.Stats
+	h.snapshotQueue.stats = h.Stats
 
 	h.Logger.Printf("open holder path: %s", h.Path)
-	if err := os.MkdirAll(h.Path, 0777); err != nil {
-		return errors.Wrap(err, "creating directory")
+	if h.ReadOnly {
+		// A read-only holder's directory must already exist, and the
+		// temporary files of interrupted writes are left alone.
+		h.Logger.Printf("open holder: read-only")
+		if _, err := os.Stat(h.Path); err != nil {
+			return errors.Wrap(err, "checking directory")
+		}
+	} else {
+		if err := os.MkdirAll(h.Path, 0777); err != nil {
+			return errors.Wrap(err, "creating directory")
+		}
+
+		// Clean up the temporary files of writes interrupted by a crash.
+		orphanN, err := h.removeOrphans()
+		if err != nil {
+			return errors.Wrap(err, "removing orphaned files")
+		}
+		h.Logger.Printf("open holder: %d orphaned files", orphanN)
+		h.Stats.Count("orphanedFiles", int64(orphanN), 1.0)
+	}
+
+	if err := h.checkLimits(); err != nil {
+		return errors.Wrap(err, "checking limits")
 	}
 
 	// Open path to read all index directories.
@@ -152,37 +324,114 @@ func (h *Holder) Open() error {
 		return errors.Wrap(err, "reading directory")
 	}
 
+	var indexes []*Index
 	for _, fi := range fis {
 		// Skip files or hidden directories.
 		if !fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
 			continue
 		}
 
-		h.Logger.Printf("opening index: %s", filepath.Base(fi.Name()))
+		name := filepath.Base(fi.Name())
+		h.Logger.Printf("opening index: %s", name)
 
-		index, err := h.newIndex(h.IndexPath(filepath.Base(fi.Name())), filepath.Base(fi.Name()))
-		if errors.Cause(err) == ErrName {
-			h.Logger.Printf("ERROR opening index: %s, err=%s", fi.Name(), err)
-			continue
-		} else if err != nil {
-			return errors.Wrap(err, "opening index")
-		}
-		if err := index.Open(); err != nil {
-			if err == ErrName {
-				h.Logger.Printf("ERROR opening index: %s, err=%s", index.Name(), err)
+		if err := validateName(name); err != nil {
+			if !h.AllowLegacyNames || !isLegacyName(name) {
+				h.Logger.Errorf("opening index: %s, err=%s", name, err)
 				continue
 			}
-			return fmt.Errorf("open index: name=%s, err=%s", index.Name(), err)
+			h.Logger.Printf("WARNING opening index with legacy name: %s, err=%s", name, err)
+		}
+		indexes = append(indexes, h.newIndex(h.IndexPath(name), name))
+	}
+
+	// Open all indexes at once, sharing a limit on the fields being opened.
+	start := time.Now()
+	workers := h.OpenWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	limit := make(chan struct{}, workers)
+	errs := make([]error, len(indexes))
+	var wg sync.WaitGroup
+	for i, index := range indexes {
+		index.openLimit = limit
+		wg.Add(1)
+		go func(i int, index *Index) {
+			defer wg.Done()
+			errs[i] = index.Open()
+		}(i, index)
+	}
+	wg.Wait()
+
+	var errList roaring.ErrorList
+	for i, index := range indexes {
+		index.openLimit = nil
+		if err := errs[i]; errors.Cause(err) == ErrName {
+			h.Logger.Errorf("opening index: %s, err=%s", index.Name(), err)
+			index.Close()
+			continue
+		} else if err != nil {
+			h.Logger.Errorf("opening index: %s, err=%s", index.Name(), err)
+			index.Close()
+			errList.Append(fmt.Errorf("open index: name=%s, err=%s", index.Name(), err))
+			continue
 		}
 		h.mu.Lock()
 		h.indexes[index.Name()] = index
 		h.mu.Unlock()
 	}
-	h.Logger.Printf("open holder: complete")
+	if len(errList) > 0 {
+		// The holder failed to open and won't be closed, so close the
+		// indexes which did open rather than leave their files mapped.
+		var closeErrs closeErrors
+		h.mu.Lock()
+		for name, index := range h.indexes {
+			closeErrs.append(index.Close(), "index=%s", name)
+			delete(h.indexes, name)
+		}
+		h.mu.Unlock()
+		if err := closeErrs.err(); err != nil {
+			h.Logger.Errorf("open holder: closing opened indexes: %s", err)
+			errList.Append(err)
+		}
+		return errList
+	}
+	h.Logger.Printf("open holder: complete in %s", time.Since(start))
 
 	// Periodically flush cache.
-	h.wg.Add(1)
+	h.wg.Add(3)
 	go func() { defer h.wg.Done(); h.monitorCacheFlush() }()
+	go func() { defer h.wg.Done(); h.monitorCacheMemory() }()
+	go func() { defer h.wg.Done(); h.monitorWriteStats() }()
+
+	// Close idle fragments.
+	if h.FragmentIdleTimeout > 0 || h.MaxOpenFragmentsPerView > 0 {
+		h.wg.Add(1)
+		go func() { defer h.wg.Done(); h.monitorIdleFragments() }()
+	}
+
+	// Rebuild caches of imported fragments.
+	for i := 0; i < h.cacheRebuildWorkers; i++ {
+		h.wg.Add(1)
+		go func() { defer h.wg.Done(); h.cacheRebuilder.run(h.closing) }()
+	}
+
+	// Snapshot fragments whose op logs are full.
+	snapshotWorkers := h.SnapshotWorkers
+	if snapshotWorkers <= 0 {
+		snapshotWorkers = defaultSnapshotWorkers
+	}
+	for i := 0; i < snapshotWorkers; i++ {
+		h.wg.Add(1)
+		go func() { defer h.wg.Done(); h.snapshotQueue.run(h.closing) }()
+	}
+
+	// Finish time migrations and field copies interrupted by a previous
+	// close. Read-only holders leave them unfinished.
+	if !h.ReadOnly {
+		h.resumeTimeMigrations()
+		h.resumeFieldCopies()
+	}
 
 	h.Stats.Open()
 
@@ -198,16 +447,35 @@ func (h *Holder) Close() error {
 	close(h.closing)
 	h.wg.Wait()
 
-	for _, index := range h.indexes {
-		if err := index.Close(); err != nil {
-			return errors.Wrap(err, "closing index")
+	// Close every index, in name order, even if some fail so that as much
+	// as possible is flushed.
+	start := time.Now()
+	indexes := h.Indexes()
+	var fieldN, fragmentN int
+	for _, index := range indexes {
+		for _, f := range index.Fields() {
+			fieldN++
+			for _, v := range f.views() {
+				fragmentN += int(v.availableShards().Count())
+			}
 		}
 	}
+	var errs closeErrors
+	for _, index := range indexes {
+		errs.append(index.Close(), "index=%s", index.Name())
+	}
 
 	if h.translateFile != nil {
-		if err := h.translateFile.Close(); err != nil {
-			return err
+		errs.append(h.translateFile.Close(), "translate file")
+	}
+
+	if len(errs) > 0 {
+		h.Logger.Errorf("close holder: %d indexes, %d fields, %d fragments, %d errors in %s", len(indexes), fieldN, fragmentN, len(errs), time.Since(start))
+		for _, err := range errs {
+			h.Logger.Errorf("close holder: %s", err)
 		}
+	} else {
+		h.Logger.Printf("close holder: %d indexes, %d fields, %d fragments in %s", len(indexes), fieldN, fragmentN, time.Since(start))
 	}
 
 	// Reset opened in case Holder needs to be reopened.
@@ -215,7 +483,39 @@ func (h *Holder) Close() error {
 	h.opened.ch = make(chan struct{})
 	h.opened.mu.Unlock()
 
-	return nil
+	return errs.err()
+}
+
+// closeErrors lists everything which failed to flush or close while closing
+// a holder, index, field or view. Each error names what failed.
+type closeErrors []error
+
+func (e closeErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d failed to close: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// append adds err, if not nil, prefixed with the name of what failed. The
+// errors of a nested list already name what failed and are added as is.
+func (e *closeErrors) append(err error, format string, args ...interface{}) {
+	if err == nil {
+		return
+	} else if list, ok := err.(closeErrors); ok {
+		*e = append(*e, list...)
+		return
+	}
+	*e = append(*e, errors.Wrap(err, fmt.Sprintf(format, args...)))
+}
+
+// err returns e, or nil if it is empty.
+func (e closeErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
 }
 
 // HasData returns true if Holder contains at least one index.
@@ -283,20 +583,61 @@ func (h *Holder) Schema() []*IndexInfo {
 	return a
 }
 
-// limitedSchema returns schema information for all indexes and fields.
-func (h *Holder) limitedSchema() []*IndexInfo {
+// SchemaETag returns an entity tag which changes whenever an index or field
+// is created, deleted or renamed.
+func (h *Holder) SchemaETag() string { return h.schemaGen.etag() }
+
+// schemaGeneration counts the changes to a holder's schema. The count
+// starts over when the process restarts, so the start time is included in
+// its entity tag.
+type schemaGeneration struct {
+	start int64
+	n     uint64
+}
+
+func newSchemaGeneration() *schemaGeneration {
+	return &schemaGeneration{start: time.Now().UnixNano()}
+}
+
+// bump records a change to the schema. It does nothing on a nil generation.
+func (g *schemaGeneration) bump() {
+	if g != nil {
+		atomic.AddUint64(&g.n, 1)
+	}
+}
+
+// etag returns the quoted entity tag of the generation.
+func (g *schemaGeneration) etag() string {
+	return fmt.Sprintf(`"%x-%d"`, g.start, atomic.LoadUint64(&g.n))
+}
+
+// limitedSchema returns schema information for all indexes and fields. If
+// views is set then the views of each field are included, and if verbose is
+// set then statistics about each field's cache and writes.
+func (h *Holder) limitedSchema(views, verbose bool) []*IndexInfo {
 	var a []*IndexInfo
 	for _, index := range h.Indexes() {
 		di := &IndexInfo{
-			Name:       index.Name(),
-			Options:    index.Options(),
-			ShardWidth: ShardWidth,
+			Name:        index.Name(),
+			Options:     index.Options(),
+			ShardWidth:  index.ShardWidth(),
+			MaxColumnID: index.MaxColumnID(),
 		}
 		for _, field := range index.Fields() {
 			if strings.HasPrefix(field.name, "_") {
 				continue
 			}
-			fi := &FieldInfo{Name: field.Name(), Options: field.Options()}
+			fi := &FieldInfo{Name: field.Name(), Options: field.Options(), MaxRowID: field.MaxRowID()}
+			if verbose {
+				fi.Cache = field.cacheStats()
+				fi.Writes = field.WriteStats()
+			}
+			if views {
+				for _, view := range field.views() {
+					fi.Views = append(fi.Views, view.info(fi.Options.Type == FieldTypeTime))
+				}
+				sort.Sort(viewInfoSlice(fi.Views))
+			}
 			di.Fields = append(di.Fields, fi)
 		}
 		sort.Sort(fieldInfoSlice(di.Fields))
@@ -306,8 +647,14 @@ func (h *Holder) limitedSchema() []*IndexInfo {
 	return a
 }
 
-// applySchema applies an internal Schema to Holder.
-func (h *Holder) applySchema(schema *Schema) error {
+// applySchema applies an internal Schema to Holder. Indexes and fields which
+// exist with other options are left as they are and returned in a
+// SchemaConflictError once everything else has been created. The options of
+// indexes are only compared if indexOptions is set, as the schema of a node
+// status doesn't hold them.
+func (h *Holder) applySchema(schema *Schema, indexOptions bool) error {
+	conflicts := schemaConflicts(h.CanonicalSchema().Indexes, schema.Indexes, indexOptions)
+
 	// Create indexes that don't exist.
 	for _, index := range schema.Indexes {
 		idx, err := h.CreateIndexIfNotExists(index.Name, index.Options)
@@ -329,6 +676,9 @@ func (h *Holder) applySchema(schema *Schema) error {
 			}
 		}
 	}
+	if len(conflicts) > 0 {
+		return newConflictError(&SchemaConflictError{Conflicts: conflicts})
+	}
 	return nil
 }
 
@@ -357,6 +707,119 @@ func (h *Holder) Indexes() []*Index {
 	return a
 }
 
+// ViewStats holds statistics about a view and each of its fragments on this
+// node.
+type ViewStats struct {
+	Index     string           `json:"index"`
+	Field     string           `json:"field"`
+	View      *ViewInfo        `json:"view"`
+	Fragments []*FragmentStats `json:"fragments"`
+}
+
+// HolderStatsTotals holds the totals of the statistics of every view walked
+// by Holder.walkStats.
+type HolderStatsTotals struct {
+	Indexes       int    `json:"indexes"`
+	Fields        int    `json:"fields"`
+	Views         int    `json:"views"`
+	Fragments     int    `json:"fragments"`
+	OpenFragments int    `json:"openFragments"`
+	BitCount      uint64 `json:"bitCount"`
+	FileBytes     int64  `json:"fileBytes"`
+	Containers    int    `json:"containers"`
+	CacheEntries  int    `json:"cacheEntries"`
+}
+
+// walkStats calls fn with the statistics of each view on this node, in
+// index, field and view order, and returns their totals. If indexName or
+// fieldName is set then only that index or field is walked. Each view is
+// only locked while its fragments are listed, so the walk doesn't stall
+// writes to the rest of the holder.
+func (h *Holder) walkStats(ctx context.Context, indexName, fieldName string, fn func(*ViewStats) error) (*HolderStatsTotals, error) {
+	indexes := h.Indexes()
+	if indexName != "" {
+		index := h.Index(indexName)
+		if index == nil {
+			return nil, newNotFoundError(ErrIndexNotFound)
+		}
+		indexes = []*Index{index}
+	} else if fieldName != "" {
+		return nil, NewBadRequestError(errors.New("field filter requires an index"))
+	}
+
+	totals := &HolderStatsTotals{}
+	for _, index := range indexes {
+		fields := index.Fields()
+		if fieldName != "" {
+			field := index.Field(fieldName)
+			if field == nil {
+				return nil, newNotFoundError(ErrFieldNotFound)
+			}
+			fields = []*Field{field}
+		}
+		totals.Indexes++
+
+		for _, field := range fields {
+			views := field.views()
+			sort.Slice(views, func(i, j int) bool { return views[i].name < views[j].name })
+			totals.Fields++
+
+			for _, view := range views {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+
+				vs := &ViewStats{
+					Index:     index.Name(),
+					Field:     field.Name(),
+					View:      view.info(field.Type() == FieldTypeTime),
+					Fragments: []*FragmentStats{},
+				}
+				for _, fs := range view.fragmentStats() {
+					vs.Fragments = append(vs.Fragments, fs)
+
+					totals.Fragments++
+					if fs.Open {
+						totals.OpenFragments++
+					}
+					totals.BitCount += fs.BitCount
+					totals.FileBytes += fs.FileBytes
+					totals.Containers += fs.Containers
+					totals.CacheEntries += fs.CacheEntries
+				}
+				totals.Views++
+
+				if err := fn(vs); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return totals, nil
+}
+
+// ExpiredView identifies a time view which is older than the retention of
+// its field.
+type ExpiredView struct {
+	Index string `json:"index"`
+	Field string `json:"field"`
+	View  string `json:"view"`
+}
+
+// expiredViews returns all local time views which are older than the
+// retention of their field as of now.
+func (h *Holder) expiredViews(now time.Time) []ExpiredView {
+	var a []ExpiredView
+	for _, index := range h.Indexes() {
+		for _, field := range index.Fields() {
+			for _, name := range field.expiredViews(now) {
+				a = append(a, ExpiredView{Index: index.Name(), Field: field.Name(), View: name})
+			}
+		}
+	}
+	return a
+}
+
 // CreateIndex creates an index.
 // An error is returned if the index already exists.
 func (h *Holder) CreateIndex(name string, opt IndexOptions) (*Index, error) {
@@ -395,13 +858,28 @@ func (h *Holder) createIndex(name string, opt IndexOptions) (*Index, error) {
 	}
 
 	// Otherwise create a new index.
-	index, err := h.newIndex(h.IndexPath(name), name)
-	if err != nil {
-		return nil, errors.Wrap(err, "creating")
+	if h.ReadOnly {
+		return nil, newForbiddenError(ErrReadOnly)
+	} else if err := ValidateIndexName(name); err != nil {
+		return nil, errors.Wrap(err, "validating name")
+	} else if h.MaxIndexes > 0 && len(h.indexes) >= h.MaxIndexes {
+		return nil, NewBadRequestError(errors.Wrapf(ErrTooManyIndexes, "limit %d", h.MaxIndexes))
+	} else if opt.ColumnLabel != "" {
+		if err := validateLabel(opt.ColumnLabel); err != nil {
+			return nil, NewBadRequestError(err)
+		}
 	}
+	if !validShardWidth(opt.ShardWidth) {
+		return nil, NewBadRequestError(ErrInvalidShardWidth)
+	}
+	index := h.newIndex(h.IndexPath(name), name)
 
 	index.keys = opt.Keys
 	index.trackExistence = opt.TrackExistence
+	index.timeQuantum = opt.TimeQuantum
+	index.columnLabel = opt.ColumnLabel
+	index.shardWidth = opt.ShardWidth
+	index.partitionKey = opt.PartitionKey
 
 	if err := index.Open(); err != nil {
 		return nil, errors.Wrap(err, "opening")
@@ -411,21 +889,91 @@ func (h *Holder) createIndex(name string, opt IndexOptions) (*Index, error) {
 
 	// Update options.
 	h.indexes[index.Name()] = index
+	h.schemaGen.bump()
 
 	return index, nil
 }
 
-func (h *Holder) newIndex(path, name string) (*Index, error) {
-	index, err := NewIndex(path, name)
-	if err != nil {
-		return nil, err
-	}
+// newIndex returns a new index using the holder's resources, without
+// validating its name.
+func (h *Holder) newIndex(path, name string) *Index {
+	index := newIndexUnchecked(path, name)
 	index.logger = h.Logger
 	index.Stats = h.Stats.WithTags(fmt.Sprintf("index:%s", index.Name()))
 	index.broadcaster = h.broadcaster
-	index.newAttrStore = h.NewAttrStore
-	index.columnAttrs = h.NewAttrStore(filepath.Join(index.path, ".data"))
-	return index, nil
+	index.cacheAccountant = h.cacheAccountant
+	index.cacheRebuilder = h.cacheRebuilder
+	index.snapshotQueue = h.snapshotQueue
+	index.newAttrStore = h.newAttrStore
+	index.columnAttrs = h.newAttrStore(filepath.Join(index.path, ".data"))
+	index.allowLegacyNames = h.AllowLegacyNames
+	index.maxFields = h.MaxFieldsPerIndex
+	index.maxViews = h.MaxViewsPerField
+	index.durability = h.Durability
+	index.maxOpN = h.MaxOpN
+	index.fragmentCloseTimeout = h.FragmentCloseTimeout
+	index.fragmentOpenConcurrency = h.FragmentOpenConcurrency
+	index.fragmentOpenPolicy = h.FragmentOpenPolicy
+	index.fragmentLayout = h.FragmentLayout
+	index.schemaGen = h.schemaGen
+	index.readOnly = h.ReadOnly
+	return index
+}
+
+// newAttrStore returns a new attribute store which syncs its writes
+// according to the holder's durability, if it supports skipping them.
+// Read-only holders open stores read-only, and don't create them.
+func (h *Holder) newAttrStore(path string) AttrStore {
+	if h.ReadOnly && !fileExists(path) {
+		return nopStore
+	}
+	store := h.NewAttrStore(path)
+	if s, ok := store.(syncer); ok {
+		s.SetSync(h.Durability.syncsAttrs())
+	}
+	if s, ok := store.(readOnlyOpener); ok && h.ReadOnly {
+		s.SetReadOnly(true)
+	}
+	return store
+}
+
+// SchemaLimits returns the configured limits on the number of indexes,
+// fields per index, and views per field, along with the highest current
+// counts.
+func (h *Holder) SchemaLimits() SchemaLimits {
+	limits := SchemaLimits{
+		Indexes:        SchemaLimit{Limit: h.MaxIndexes},
+		FieldsPerIndex: SchemaLimit{Limit: h.MaxFieldsPerIndex},
+		ViewsPerField:  SchemaLimit{Limit: h.MaxViewsPerField},
+	}
+	indexes := h.Indexes()
+	limits.Indexes.Count = len(indexes)
+	for _, index := range indexes {
+		if n := index.fieldCount(); n > limits.FieldsPerIndex.Count {
+			limits.FieldsPerIndex.Count = n
+		}
+		for _, field := range index.Fields() {
+			if n := len(field.views()); n > limits.ViewsPerField.Count {
+				limits.ViewsPerField.Count = n
+			}
+		}
+	}
+	return limits
+}
+
+// SchemaLimits holds limits on the number of indexes, fields, and views.
+// The counts of fields and views are the highest of any index or field.
+type SchemaLimits struct {
+	Indexes        SchemaLimit `json:"indexes"`
+	FieldsPerIndex SchemaLimit `json:"fieldsPerIndex"`
+	ViewsPerField  SchemaLimit `json:"viewsPerField"`
+}
+
+// SchemaLimit is a count and the limit it may not exceed. A zero limit is
+// unlimited.
+type SchemaLimit struct {
+	Count int `json:"count"`
+	Limit int `json:"limit"`
 }
 
 // DeleteIndex removes an index from the holder.
@@ -437,8 +985,14 @@ func (h *Holder) DeleteIndex(name string) error {
 	index := h.index(name)
 	if index == nil {
 		return newNotFoundError(ErrIndexNotFound)
+	} else if h.ReadOnly {
+		return newForbiddenError(ErrReadOnly)
 	}
 
+	// Remove reference first so the index can't be found half closed.
+	// Queries already holding it are failed by the executor once they finish.
+	delete(h.indexes, name)
+
 	// Close index.
 	if err := index.Close(); err != nil {
 		return errors.Wrap(err, "closing")
@@ -448,13 +1002,157 @@ func (h *Holder) DeleteIndex(name string) error {
 	if err := os.RemoveAll(h.IndexPath(name)); err != nil {
 		return errors.Wrap(err, "removing directory")
 	}
+	h.schemaGen.bump()
+	h.Logger.Printf("deleted index: %s", name)
 
-	// Remove reference.
+	return nil
+}
+
+// RenameIndex renames an index. The index is closed, its directory moved,
+// and it is reopened under the new name. If it can't be reopened the
+// directory is moved back and the index reopened under its old name.
+func (h *Holder) RenameIndex(name, newName string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	index, err := h.checkRenameIndex(name, newName)
+	if err != nil {
+		return err
+	}
+
+	// Shards are placed on nodes by the partition key, so keep hashing the
+	// old name to leave them with the nodes that hold them.
+	if index.partitionKey == "" {
+		index.partitionKey = name
+		if err := index.saveMeta(); err != nil {
+			index.partitionKey = ""
+			return errors.Wrap(err, "saving partition key")
+		}
+	}
+
+	// Remove reference first so the index can't be found half closed.
 	delete(h.indexes, name)
+	if err := index.Close(); err != nil {
+		h.reopenIndex(name)
+		return errors.Wrap(err, "closing")
+	}
+
+	if err := os.Rename(h.IndexPath(name), h.IndexPath(newName)); err != nil {
+		h.reopenIndex(name)
+		return errors.Wrap(err, "renaming directory")
+	}
+
+	renamed := h.newIndex(h.IndexPath(newName), newName)
+	if err := renamed.Open(); err != nil {
+		renamed.Close()
+		if rerr := os.Rename(h.IndexPath(newName), h.IndexPath(name)); rerr != nil {
+			h.Logger.Errorf("restoring renamed index: %s, err=%s", name, rerr)
+		} else {
+			h.reopenIndex(name)
+		}
+		return errors.Wrap(err, "opening")
+	}
+	h.indexes[newName] = renamed
+	h.schemaGen.bump()
+	h.Logger.Printf("renamed index: %s to %s", name, newName)
 
 	return nil
 }
 
+// CheckRenameIndex returns an error if the index name can't be renamed to
+// newName.
+func (h *Holder) CheckRenameIndex(name, newName string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, err := h.checkRenameIndex(name, newName)
+	return err
+}
+
+// checkRenameIndex returns the index to be renamed, or an error if it can't
+// be. Names are also used to key column and row translation and the jobs
+// running on fields, so indexes using keys or with running jobs aren't
+// renamed. The caller must hold the holder lock.
+func (h *Holder) checkRenameIndex(name, newName string) (*Index, error) {
+	index := h.index(name)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	} else if h.ReadOnly {
+		return nil, newForbiddenError(ErrReadOnly)
+	} else if err := ValidateIndexName(newName); err != nil {
+		return nil, NewBadRequestError(errors.Wrap(err, "validating name"))
+	} else if h.index(newName) != nil {
+		return nil, newConflictError(ErrIndexExists)
+	} else if index.Keys() {
+		return nil, NewBadRequestError(errors.New("indexes with keys can't be renamed"))
+	}
+	for _, f := range index.Fields() {
+		if f.keys() {
+			return nil, NewBadRequestError(errors.Errorf("indexes with keyed fields can't be renamed: field=%s", f.Name()))
+		} else if m := h.TimeMigration(name, f.Name()); m != nil && !m.Done {
+			return nil, newConflictError(errors.Wrapf(ErrTimeMigrationRunning, "field=%s", f.Name()))
+		} else if h.copyingInto(f) || h.copyingFrom(f) {
+			return nil, newConflictError(errors.Wrapf(ErrFieldCopying, "field=%s", f.Name()))
+		}
+	}
+	return index, nil
+}
+
+// reopenIndex opens the index name again after a failed rename. The caller
+// must hold the holder lock.
+func (h *Holder) reopenIndex(name string) {
+	index := h.newIndex(h.IndexPath(name), name)
+	if err := index.Open(); err != nil {
+		h.Logger.Errorf("reopening index: %s, err=%s", name, err)
+		index.Close()
+		return
+	}
+	h.indexes[name] = index
+}
+
+// RenameField renames a field of an index. The field is closed, its
+// directory moved, and it is reopened under the new name.
+func (h *Holder) RenameField(index, name, newName string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	idx, err := h.checkRenameField(index, name, newName)
+	if err != nil {
+		return err
+	}
+	return idx.renameField(name, newName)
+}
+
+// CheckRenameField returns an error if the field of an index can't be
+// renamed to newName.
+func (h *Holder) CheckRenameField(index, name, newName string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, err := h.checkRenameField(index, name, newName)
+	return err
+}
+
+// checkRenameField returns the index of the field to be renamed, or an
+// error if it can't be. Fields with running jobs, or which are the source of
+// a copy, aren't renamed. The caller must hold the holder lock.
+func (h *Holder) checkRenameField(index, name, newName string) (*Index, error) {
+	idx := h.index(index)
+	if idx == nil {
+		return nil, newNotFoundError(ErrIndexNotFound)
+	}
+
+	idx.mu.RLock()
+	f, err := idx.checkRenameField(name, newName)
+	idx.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	} else if m := h.TimeMigration(index, name); m != nil && !m.Done {
+		return nil, newConflictError(ErrTimeMigrationRunning)
+	} else if h.copyingInto(f) || h.copyingFrom(f) {
+		return nil, newConflictError(ErrFieldCopying)
+	}
+	return idx, nil
+}
+
 // Field returns the field for an index and name.
 func (h *Holder) Field(index, name string) *Field {
 	idx := h.Index(index)
@@ -482,6 +1180,92 @@ func (h *Holder) fragment(index, field, view string, shard uint64) *fragment {
 	return v.Fragment(shard)
 }
 
+// acquireFragment returns the fragment for a view and shard, acquired for
+// reading. See view.acquireFragment.
+func (h *Holder) acquireFragment(index, field, view string, shard uint64) (*fragment, error) {
+	v := h.view(index, field, view)
+	if v == nil {
+		return nil, nil
+	}
+	return v.acquireFragment(shard)
+}
+
+// monitorCacheMemory periodically checks the memory used by fragment caches
+// and shrinks them if the total exceeds CacheMaxMemory.
+func (h *Holder) monitorCacheMemory() {
+	ticker := time.NewTicker(h.cacheMemoryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.closing:
+			return
+		case <-ticker.C:
+			h.cacheAccountant.enforce(h.CacheMaxMemory)
+		}
+	}
+}
+
+// monitorIdleFragments periodically closes the fragments of each view which
+// are idle or beyond the number kept open.
+func (h *Holder) monitorIdleFragments() {
+	ticker := time.NewTicker(h.fragmentIdleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.closing:
+			return
+		case <-ticker.C:
+			h.closeIdleFragments()
+		}
+	}
+}
+
+// closeIdleFragments closes the fragments of each view which are idle or
+// beyond the number kept open, and returns the number closed.
+func (h *Holder) closeIdleFragments() int {
+	var n int
+	for _, index := range h.Indexes() {
+		for _, field := range index.Fields() {
+			for _, view := range field.views() {
+				select {
+				case <-h.closing:
+					return n
+				default:
+				}
+				n += view.closeIdleFragments(h.FragmentIdleTimeout, h.MaxOpenFragmentsPerView)
+			}
+		}
+	}
+	return n
+}
+
+// CacheMemoryUsage returns the approximate memory, in bytes, used by the
+// caches of all open fragments as of the last check.
+func (h *Holder) CacheMemoryUsage() int64 {
+	return h.cacheAccountant.Usage()
+}
+
+// PendingCacheRebuilds returns the number of fragments whose caches are
+// waiting to be rebuilt after an import.
+func (h *Holder) PendingCacheRebuilds() int {
+	return h.cacheRebuilder.Pending()
+}
+
+// PendingSnapshots returns the number of fragments waiting to be snapshotted
+// or being snapshotted once their op logs passed MaxOpN.
+func (h *Holder) PendingSnapshots() int {
+	return h.snapshotQueue.Pending()
+}
+
+// CacheWarmup returns the number of fragments whose caches are being rebuilt
+// from storage in the background, and the number which have been loaded or
+// rebuilt since the holder was opened.
+func (h *Holder) CacheWarmup() (warming, warmed int) {
+	return h.cacheRebuilder.Warmup()
+}
+
 // monitorCacheFlush periodically flushes all fragment caches sequentially.
 // This is run in a goroutine.
 func (h *Holder) monitorCacheFlush() {
@@ -502,7 +1286,7 @@ func (h *Holder) flushCaches() {
 	for _, index := range h.Indexes() {
 		for _, field := range index.Fields() {
 			for _, view := range field.views() {
-				for _, fragment := range view.allFragments() {
+				for _, fragment := range view.loadedFragments() {
 					select {
 					case <-h.closing:
 						return
@@ -510,11 +1294,41 @@ func (h *Holder) flushCaches() {
 					}
 
 					if err := fragment.FlushCache(); err != nil {
-						h.Logger.Printf("ERROR flushing cache: err=%s, path=%s", err, fragment.cachePath())
+						h.Logger.Errorf("flushing cache: err=%s, path=%s", err, fragment.cachePath())
 					}
 				}
 			}
 		}
+
+		if err := index.saveMaxIDs(); err != nil {
+			h.Logger.Errorf("saving max ids: err=%s, index=%s", err, index.Name())
+		}
+	}
+}
+
+// monitorWriteStats periodically persists and reports the write statistics
+// of every field. This is run in a goroutine.
+func (h *Holder) monitorWriteStats() {
+	ticker := time.NewTicker(h.writeStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.closing:
+			return
+		case <-ticker.C:
+			h.saveWriteStats()
+		}
+	}
+}
+
+func (h *Holder) saveWriteStats() {
+	for _, index := range h.Indexes() {
+		for _, field := range index.Fields() {
+			if err := field.saveWriteStats(); err != nil {
+				h.Logger.Errorf("saving write stats: err=%s, index=%s, field=%s", err, index.Name(), field.Name())
+			}
+		}
 	}
 }
 
@@ -528,13 +1342,24 @@ func (h *Holder) recalculateCaches() {
 	}
 }
 
+// rebuildCaches rebuilds the caches of every open fragment from storage in
+// the background. TopN reads counts from storage until each is rebuilt.
+func (h *Holder) rebuildCaches() error {
+	for _, index := range h.Indexes() {
+		if err := index.rebuildCaches(); err != nil {
+			return errors.Wrapf(err, "index %s", index.Name())
+		}
+	}
+	return nil
+}
+
 // setFileLimit attempts to set the open file limit to the FileLimit constant defined above.
 func (h *Holder) setFileLimit() {
 	oldLimit := &syscall.Rlimit{}
 	newLimit := &syscall.Rlimit{}
 
 	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, oldLimit); err != nil {
-		h.Logger.Printf("ERROR checking open file limit: %s", err)
+		h.Logger.Errorf("checking open file limit: %s", err)
 		return
 	}
 	// If the soft limit is lower than the FileLimit constant, we will try to change it.
@@ -558,17 +1383,17 @@ func (h *Holder) setFileLimit() {
 				}
 				// Try setting again with lowered Max (hard limit)
 				if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, newLimit); err != nil {
-					h.Logger.Printf("ERROR setting open file limit: %s", err)
+					h.Logger.Errorf("setting open file limit: %s", err)
 				}
 				// If we weren't trying to change the hard limit, let the user know something is wrong.
 			} else {
-				h.Logger.Printf("ERROR setting open file limit: %s", err)
+				h.Logger.Errorf("setting open file limit: %s", err)
 			}
 		}
 
 		// Check the limit after setting it. OS may not obey Setrlimit call.
 		if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, oldLimit); err != nil {
-			h.Logger.Printf("ERROR checking open file limit: %s", err)
+			h.Logger.Errorf("checking open file limit: %s", err)
 		} else {
 			if oldLimit.Cur < fileLimit {
 				h.Logger.Printf("WARNING: Tried to set open file limit to %d, but it is %d. You may consider running \"sudo ulimit -n %d\" before starting Pilosa to avoid \"too many open files\" error. See https://www.pilosa.com/docs/latest/administration/#open-file-limits for more information.", fileLimit, oldLimit.Cur, fileLimit)
@@ -581,16 +1406,21 @@ func (h *Holder) loadNodeID() (string, error) {
 	idPath := path.Join(h.Path, ".id")
 	nodeID := ""
 	h.Logger.Printf("load NodeID: %s", idPath)
-	if err := os.MkdirAll(h.Path, 0777); err != nil {
-		return "", errors.Wrap(err, "creating directory")
+	if !h.ReadOnly {
+		if err := os.MkdirAll(h.Path, 0777); err != nil {
+			return "", errors.Wrap(err, "creating directory")
+		}
 	}
 
+	// Read-only holders without an ID use a new one which isn't saved.
 	nodeIDBytes, err := ioutil.ReadFile(idPath)
 	if err == nil {
 		nodeID = strings.TrimSpace(string(nodeIDBytes))
+	} else if os.IsNotExist(err) && h.ReadOnly {
+		nodeID = uuid.NewV4().String()
 	} else if os.IsNotExist(err) {
 		nodeID = uuid.NewV4().String()
-		err = ioutil.WriteFile(idPath, []byte(nodeID), 0600)
+		err = writeMetaFile(idPath, []byte(nodeID), 0600)
 		if err != nil {
 			return "", errors.Wrap(err, "writing file")
 		}
@@ -601,8 +1431,12 @@ func (h *Holder) loadNodeID() (string, error) {
 	return nodeID, nil
 }
 
-// Log startup time and version to $DATA_DIR/.startup.log
+// Log startup time and version to $DATA_DIR/.startup.log, unless the holder
+// is read-only.
 func (h *Holder) logStartup() error {
+	if h.ReadOnly {
+		return nil
+	}
 	time, err := time.Now().MarshalText()
 	if err != nil {
 		return errors.Wrap(err, "creating timestamp")
@@ -645,10 +1479,59 @@ type holderSyncer struct {
 	// Stats
 	Stats stats.StatsClient
 
+	// Limits the rate of requests to other nodes. Nil doesn't limit them.
+	Limiter *syncLimiter
+
 	// Signals that the sync should stop.
 	Closing <-chan struct{}
 }
 
+// syncLimiter spaces out the requests of anti-entropy so that no more than a
+// given number are sent per second, however long a pass takes.
+type syncLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newSyncLimiter returns a limiter of n requests per second, or nil if n
+// isn't positive.
+func newSyncLimiter(n int) *syncLimiter {
+	if n <= 0 {
+		return nil
+	}
+	return &syncLimiter{interval: time.Second / time.Duration(n)}
+}
+
+// wait blocks until the next request may be sent. It returns false if
+// closing is closed first.
+func (l *syncLimiter) wait(closing <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	d := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-closing:
+		return false
+	}
+}
+
 // IsClosing returns true if the syncer has been asked to close.
 func (s *holderSyncer) IsClosing() bool {
 	if s.Cluster.abortAntiEntropyQ() {
@@ -667,6 +1550,8 @@ func (s *holderSyncer) SyncHolder() error {
 	s.mu.Lock() // only allow one instance of SyncHolder to be running at a time
 	defer s.mu.Unlock()
 	ti := time.Now()
+	s.syncSchema()
+
 	// Iterate over schema in sorted order.
 	for _, di := range s.Holder.Schema() {
 		// Verify syncer has not closed.
@@ -726,6 +1611,33 @@ func (s *holderSyncer) SyncHolder() error {
 	return nil
 }
 
+// syncSchema compares the local schema with every other host. Divergence is
+// logged and counted but not resolved.
+func (s *holderSyncer) syncSchema() {
+	span, ctx := tracing.StartSpanFromContext(context.Background(), "HolderSyncer.syncSchema")
+	defer span.Finish()
+
+	local := s.Holder.CanonicalSchema()
+	for _, node := range Nodes(s.Cluster.nodes).FilterID(s.Node.ID) {
+		if !s.Limiter.wait(s.Closing) {
+			return
+		}
+		other, err := s.Cluster.InternalClient.CanonicalSchema(ctx, &node.URI)
+		if err != nil {
+			s.Holder.Logger.Errorf("getting schema from node %s: %s", node.ID, err)
+			continue
+		} else if other == nil || other.Checksum == local.Checksum {
+			continue
+		}
+
+		d := DiffSchemas(local.Indexes, other.Indexes)
+		for _, line := range d.Lines() {
+			s.Holder.Logger.Printf("schema differs from node %s: %s", node.ID, line)
+		}
+		s.Stats.CountWithCustomTags("SchemaDivergence", int64(d.Len()), 1.0, []string{node.ID})
+	}
+}
+
 // syncIndex synchronizes index attributes with the rest of the cluster.
 func (s *holderSyncer) syncIndex(index string) error {
 	span, ctx := tracing.StartSpanFromContext(context.Background(), "HolderSyncer.syncIndex")
@@ -747,6 +1659,10 @@ func (s *holderSyncer) syncIndex(index string) error {
 
 	// Sync with every other host.
 	for _, node := range Nodes(s.Cluster.nodes).FilterID(s.Node.ID) {
+		if !s.Limiter.wait(s.Closing) {
+			return nil
+		}
+
 		// Retrieve attributes from differing blocks.
 		// Skip update and recomputation if no attributes have changed.
 		m, err := s.Cluster.InternalClient.ColumnAttrDiff(ctx, &node.URI, index, blks)
@@ -794,6 +1710,10 @@ func (s *holderSyncer) syncField(index, name string) error {
 
 	// Sync with every other host.
 	for _, node := range Nodes(s.Cluster.nodes).FilterID(s.Node.ID) {
+		if !s.Limiter.wait(s.Closing) {
+			return nil
+		}
+
 		// Retrieve attributes from differing blocks.
 		// Skip update and recomputation if no attributes have changed.
 		m, err := s.Cluster.InternalClient.RowAttrDiff(ctx, &node.URI, index, name, blks)
@@ -836,16 +1756,18 @@ func (s *holderSyncer) syncFragment(index, field, view string, shard uint64) err
 	}
 
 	// Ensure fragment exists locally.
-	frag, err := v.CreateFragmentIfNotExists(shard)
+	frag, err := v.acquireFragmentIfNotExists(shard)
 	if err != nil {
 		return errors.Wrap(err, "creating fragment")
 	}
+	defer frag.release()
 
 	// Sync fragments together.
 	fs := fragmentSyncer{
 		Fragment: frag,
 		Node:     s.Node,
 		Cluster:  s.Cluster,
+		Limiter:  s.Limiter,
 		Closing:  s.Closing,
 	}
 	if err := fs.syncFragment(); err != nil {
@@ -888,11 +1810,11 @@ func (c *holderCleaner) CleanHolder() error {
 		// Get the fragments that node is responsible for (based on hash(index, node)).
 		containedShards := c.Cluster.containsShards(index.Name(), index.AvailableShards(), c.Node)
 
-		// Get the fragments registered in memory.
+		// Get the fragments registered in memory, including those closed
+		// while idle, which are deleted without reopening them.
 		for _, field := range index.Fields() {
 			for _, view := range field.views() {
-				for _, fragment := range view.allFragments() {
-					fragShard := fragment.shard
+				for _, fragShard := range view.availableShards().Slice() {
 					// Ignore fragments that should be present.
 					if uint64InSlice(fragShard, containedShards) {
 						continue
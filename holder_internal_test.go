@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pilosa/pilosa/roaring"
 )
@@ -175,6 +176,32 @@ func TestHolder_Optn(t *testing.T) {
 }
 
 // Ensure holder can clean up orphaned fragments.
+// Ensure the holder closes idle fragments in the background, and that they
+// are reopened to be read.
+func TestHolder_CloseIdleFragments(t *testing.T) {
+	h := newHolder()
+	h.FragmentIdleTimeout = time.Millisecond
+	h.fragmentIdleCheckInterval = time.Millisecond
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	h.SetBit("i", "f", 7, 1)
+	v := h.Field("i", "f").view(viewStandard)
+	for deadline := time.Now().Add(5 * time.Second); len(v.loadedFragments()) > 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("fragment not closed")
+		}
+	}
+
+	if id := h.Field("i", "f").MaxRowID(); id != 7 {
+		t.Fatalf("unexpected max row ID: %d", id)
+	} else if got := h.Row("i", "f", 7).Columns(); !reflect.DeepEqual(got, []uint64{1}) {
+		t.Fatalf("unexpected columns: %v", got)
+	}
+}
+
 func TestHolderCleaner_CleanHolder(t *testing.T) {
 	cluster := NewTestCluster(2)
 
@@ -297,3 +324,298 @@ func TestHolderCleaner_Reopen(t *testing.T) {
 	h.Open()
 	h.Close()
 }
+
+// Ensure an index is reopened under its new name when it is renamed, and is
+// left alone when it can't be.
+func TestHolder_RenameIndex(t *testing.T) {
+	h := newHolder()
+	defer os.RemoveAll(h.Path)
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	h.SetBit("i", "f", 1, 1)
+	h.SetBit("i", "f", 1, ShardWidth+1)
+	etag := h.SchemaETag()
+
+	if err := h.RenameIndex("i", "j"); err != nil {
+		t.Fatal(err)
+	} else if h.Index("i") != nil {
+		t.Fatal("expected old index to be gone")
+	} else if _, err := os.Stat(h.IndexPath("i")); !os.IsNotExist(err) {
+		t.Fatalf("expected old directory to be gone: %v", err)
+	} else if cols := h.Row("j", "f", 1).Columns(); !reflect.DeepEqual(cols, []uint64{1, ShardWidth + 1}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if h.SchemaETag() == etag {
+		t.Fatal("expected schema etag to change")
+	}
+
+	// The rename survives reopening.
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := h.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if h.Index("i") != nil || h.Index("j") == nil {
+		t.Fatal("expected only the renamed index after reopening")
+	}
+
+	// Its shards are still placed by the name it was created with, even
+	// when renamed again.
+	if key := h.Index("j").PartitionKey(); key != "i" {
+		t.Fatalf("unexpected partition key: %q", key)
+	} else if err := h.RenameIndex("j", "l"); err != nil {
+		t.Fatal(err)
+	} else if key := h.Index("l").PartitionKey(); key != "i" {
+		t.Fatalf("unexpected partition key after second rename: %q", key)
+	} else if err := h.RenameIndex("l", "j"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.CreateIndex("k", IndexOptions{Keys: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name, newName string
+	}{
+		{"nope", "x"},
+		{"j", "Bad Name"},
+		{"j", "k"},
+		{"k", "x"},
+	} {
+		if err := h.RenameIndex(tt.name, tt.newName); err == nil {
+			t.Fatalf("%s to %s: expected error", tt.name, tt.newName)
+		} else if h.Index(tt.name) == nil && tt.name != "nope" {
+			t.Fatalf("%s to %s: expected index to remain", tt.name, tt.newName)
+		}
+	}
+}
+
+// Ensure a field is reopened under its new name, with its views, when it is
+// renamed, and is left alone when it can't be.
+func TestHolder_RenameField(t *testing.T) {
+	h := newHolder()
+	defer os.RemoveAll(h.Path)
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	h.SetBit("i", "f", 1, 1)
+	h.SetBit("i", "f", 1, ShardWidth+1)
+	idx := h.Index("i")
+	if v, err := idx.CreateField("v", OptFieldTypeInt(0, 100)); err != nil {
+		t.Fatal(err)
+	} else if _, err := v.SetValue(3, 42); err != nil {
+		t.Fatal(err)
+	}
+	etag := h.SchemaETag()
+
+	if err := h.RenameField("i", "f", "g"); err != nil {
+		t.Fatal(err)
+	} else if h.Field("i", "f") != nil {
+		t.Fatal("expected old field to be gone")
+	} else if _, err := os.Stat(idx.fieldPath("f")); !os.IsNotExist(err) {
+		t.Fatalf("expected old directory to be gone: %v", err)
+	} else if cols := h.Row("i", "g", 1).Columns(); !reflect.DeepEqual(cols, []uint64{1, ShardWidth + 1}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if h.SchemaETag() == etag {
+		t.Fatal("expected schema etag to change")
+	}
+
+	// The view of an int field is renamed with it.
+	if err := h.RenameField("i", "v", "w"); err != nil {
+		t.Fatal(err)
+	} else if value, exists, err := h.Field("i", "w").Value(3); err != nil || !exists || value != 42 {
+		t.Fatalf("unexpected value: %d, %v, %v", value, exists, err)
+	}
+
+	// The renames survive reopening.
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := h.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if h.Field("i", "f") != nil || h.Field("i", "g") == nil || h.Field("i", "w") == nil {
+		t.Fatal("expected only the renamed fields after reopening")
+	} else if value, exists, err := h.Field("i", "w").Value(3); err != nil || !exists || value != 42 {
+		t.Fatalf("unexpected value after reopening: %d, %v, %v", value, exists, err)
+	}
+
+	if _, err := h.Index("i").CreateField("k", OptFieldKeys()); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		index, name, newName string
+	}{
+		{"nope", "g", "x"},
+		{"i", "nope", "x"},
+		{"i", "g", "Bad Name"},
+		{"i", "g", "w"},
+		{"i", "k", "x"},
+	} {
+		if err := h.RenameField(tt.index, tt.name, tt.newName); err == nil {
+			t.Fatalf("%s/%s to %s: expected error", tt.index, tt.name, tt.newName)
+		} else if h.Field(tt.index, tt.name) == nil && tt.index != "nope" && tt.name != "nope" {
+			t.Fatalf("%s/%s to %s: expected field to remain", tt.index, tt.name, tt.newName)
+		}
+	}
+}
+
+// Ensure closing a holder closes every fragment and reports each one which
+// failed.
+func TestHolder_Close_Errors(t *testing.T) {
+	h := newHolder()
+	defer os.RemoveAll(h.Path)
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	h.SetBit("i", "f", 1, 1)
+	h.SetBit("i", "f", 1, ShardWidth+1)
+	h.SetBit("i", "f", 1, 2*ShardWidth+1)
+
+	// Close the files of two fragments behind their backs so they can't be
+	// synced on close.
+	for _, shard := range []uint64{0, 2} {
+		if err := h.fragment("i", "f", viewStandard, shard).file.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok := h.fragment("i", "f", viewStandard, 1)
+
+	err := h.Holder.Close()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, exp := range []string{
+		"2 failed to close",
+		"index=i field=f view=standard shard=0",
+		"index=i field=f view=standard shard=2",
+	} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected %q in error: %s", exp, err)
+		}
+	}
+	if strings.Contains(err.Error(), "shard=1") {
+		t.Fatalf("unexpected error for shard 1: %s", err)
+	}
+	if ok.file != nil && ok.file.Fd() != ^uintptr(0) {
+		t.Fatal("expected shard 1 to be closed")
+	}
+}
+
+// Ensure temporary files left by a crash are removed, or moved aside if they
+// are preserved, when a holder is opened.
+func TestHolder_Open_Orphans(t *testing.T) {
+	for _, preserve := range []bool{false, true} {
+		h := newHolder()
+		defer h.Close()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		h.SetBit("i", "f", 1, 1)
+		fragPath := h.fragment("i", "f", viewStandard, 0).path
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		orphans := []string{
+			fragPath + snapshotExt,
+			fragPath + cacheExt + snapshotExt,
+			fragPath + copyExt,
+			filepath.Join(h.Path, "i", "f", ".meta"+metaTmpExt),
+		}
+		for _, path := range orphans {
+			if err := ioutil.WriteFile(path, []byte("x"), 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		h.PreserveOrphans = preserve
+		if n, err := h.removeOrphans(); err != nil {
+			t.Fatal(err)
+		} else if n != len(orphans) {
+			t.Fatalf("preserve=%v: removed %d files, expected %d", preserve, n, len(orphans))
+		}
+		for _, path := range orphans {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatalf("preserve=%v: expected %s to be removed: %v", preserve, path, err)
+			}
+			rel, _ := filepath.Rel(h.Path, path)
+			if _, err := os.Stat(filepath.Join(h.Path, orphanDir, rel)); preserve && err != nil {
+				t.Fatalf("expected %s to be preserved: %v", path, err)
+			} else if !preserve && !os.IsNotExist(err) {
+				t.Fatalf("expected %s not to be preserved: %v", path, err)
+			}
+		}
+
+		// The fragment and meta files are left alone.
+		if _, err := os.Stat(fragPath); err != nil {
+			t.Fatal(err)
+		} else if err := h.Reopen(); err != nil {
+			t.Fatal(err)
+		} else if cols := h.Row("i", "f", 1).Columns(); !reflect.DeepEqual(cols, []uint64{1}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+	}
+}
+
+// Ensure the indexes which opened are closed again, with their files, when
+// others fail to open the holder.
+func TestHolder_Open_ClosesOpenedIndexes(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	h.SetBit("i", "f", 1, 1)
+	h.MustCreateIndexIfNotExists("j", IndexOptions{})
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(filepath.Join(h.Path, "j", ".meta"), []byte("x"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Reopen(); err == nil || !strings.Contains(err.Error(), "name=j") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if idx := h.Index("i"); idx != nil {
+		t.Fatal("expected index i to be closed")
+	}
+
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("open files can't be listed")
+	}
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && strings.HasPrefix(target, h.Path+"/") {
+			t.Fatalf("file left open: %s", target)
+		}
+	}
+}
+
+// Ensure the anti-entropy limiter spaces out requests and stops waiting when
+// the syncer closes.
+func TestSyncLimiter_Wait(t *testing.T) {
+	if l := newSyncLimiter(0); l != nil {
+		t.Fatalf("expected no limiter, got %+v", l)
+	} else if !l.wait(nil) {
+		t.Fatal("expected nil limiter not to wait")
+	}
+
+	l := newSyncLimiter(100)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if !l.wait(nil) {
+			t.Fatal("unexpected close")
+		}
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Fatalf("expected 5 requests to take at least 40ms, took %s", d)
+	}
+
+	closing := make(chan struct{})
+	close(closing)
+	l = newSyncLimiter(1)
+	if !l.wait(closing) {
+		t.Fatal("expected first request not to wait")
+	} else if l.wait(closing) {
+		t.Fatal("expected second request to be closed")
+	}
+}
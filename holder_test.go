@@ -17,15 +17,21 @@ package pilosa_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/boltdb"
+	"github.com/pilosa/pilosa/logger"
+	"github.com/pilosa/pilosa/roaring"
 	"github.com/pilosa/pilosa/test"
+	"github.com/pkg/errors"
 )
 
 func TestHolder_Open(t *testing.T) {
@@ -53,6 +59,53 @@ func TestHolder_Open(t *testing.T) {
 		}
 	})
 
+	t.Run("LegacyNames", func(t *testing.T) {
+		h := test.MustOpenHolder()
+		defer h.Close()
+
+		// Names created before the naming rules were enforced.
+		if idx, err := h.CreateIndex("i", pilosa.IndexOptions{}); err != nil {
+			t.Fatal(err)
+		} else if _, err := idx.CreateField("f", pilosa.OptFieldTypeDefault()); err != nil {
+			t.Fatal(err)
+		} else if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		} else if err := os.Rename(filepath.Join(h.Path, "i", "f"), filepath.Join(h.Path, "i", "from")); err != nil {
+			t.Fatal(err)
+		} else if err := os.Rename(filepath.Join(h.Path, "i"), filepath.Join(h.Path, "Legacy")); err != nil {
+			t.Fatal(err)
+		}
+
+		bufLogger := test.NewBufferLogger()
+		h.Holder.Logger = bufLogger
+		if err := h.Reopen(); err != nil {
+			t.Fatal(err)
+		} else if h.Index("Legacy") != nil {
+			t.Fatal("expected legacy index to be skipped")
+		} else if bufbytes, err := bufLogger.ReadAll(); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Contains(bufbytes, []byte(`ERROR opening index: Legacy, err=invalid name "Legacy": must be lowercase`)) {
+			t.Fatalf("expected log error:\n%s", bufbytes)
+		} else if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		path := h.Path
+		h.Holder = pilosa.NewHolder()
+		h.Holder.Path = path
+		h.Holder.NewAttrStore = boltdb.NewAttrStore
+		h.Holder.AllowLegacyNames = true
+		if err := h.Holder.Open(); err != nil {
+			t.Fatal(err)
+		} else if idx := h.Index("Legacy"); idx == nil {
+			t.Fatal("expected legacy index to be opened")
+		} else if idx.Field("from") == nil {
+			t.Fatal("expected legacy field to be opened")
+		} else if _, err := idx.CreateField("to"); errors.Cause(err) != pilosa.ErrName {
+			t.Fatalf("expected name error creating a field, got: %v", err)
+		}
+	})
+
 	t.Run("ErrIndexPermission", func(t *testing.T) {
 		if os.Geteuid() == 0 {
 			t.Skip("Skipping permissions test since user is root.")
@@ -189,11 +242,175 @@ func TestHolder_Open(t *testing.T) {
 			t.Fatal(err)
 		}
 
+		capture := logger.NewCaptureLogger()
+		h.Holder.Logger = capture
 		if err := h.Reopen(); err == nil || !strings.Contains(err.Error(), "open fragment: shard=0, err=opening storage: unmarshal storage") {
 			t.Fatalf("unexpected error: %s", err)
+		} else if !capture.Contains(logger.ErrorLevel, "opening index: foo, err=") || !capture.Contains(logger.ErrorLevel, "unmarshal storage") {
+			t.Fatalf("expected error log: %+v", capture.Entries())
+		}
+	})
+
+	t.Run("FragmentStorageQuarantine", func(t *testing.T) {
+		h := test.MustOpenHolder()
+		defer h.Close()
+
+		viewPath := filepath.Join(h.Path, "foo", "bar", "views", "standard")
+		if idx, err := h.CreateIndex("foo", pilosa.IndexOptions{}); err != nil {
+			t.Fatal(err)
+		} else if field, err := idx.CreateField("bar", pilosa.OptFieldTypeDefault()); err != nil {
+			t.Fatal(err)
+		} else if _, err := field.SetBit(1, 1, nil); err != nil {
+			t.Fatal(err)
+		} else if _, err := field.SetBit(1, pilosa.ShardWidth+1, nil); err != nil {
+			t.Fatal(err)
+		} else if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Overwrite the header of shard 1.
+		if f, err := os.OpenFile(filepath.Join(viewPath, "fragments", "1"), os.O_WRONLY, 0666); err != nil {
+			t.Fatal(err)
+		} else if _, err := f.WriteAt([]byte("XXXX"), 0); err != nil {
+			t.Fatal(err)
+		} else if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		capture := logger.NewCaptureLogger()
+		path := h.Path
+		h.Holder = pilosa.NewHolder()
+		h.Holder.Path = path
+		h.Holder.Logger = capture
+		h.Holder.NewAttrStore = boltdb.NewAttrStore
+		h.Holder.FragmentOpenPolicy = pilosa.FragmentOpenQuarantine
+		if err := h.Holder.Open(); err != nil {
+			t.Fatal(err)
+		} else if !capture.Contains(logger.ErrorLevel, "QUARANTINE: moved fragment foo/bar/standard/1") {
+			t.Fatalf("expected error log: %+v", capture.Entries())
+		}
+
+		// The data file and its cache are quarantined.
+		if _, err := os.Stat(filepath.Join(viewPath, "fragments", "1")); !os.IsNotExist(err) {
+			t.Fatalf("expected fragment file to be moved: %v", err)
+		} else if names, err := filepath.Glob(filepath.Join(viewPath, "quarantine", "1.*")); err != nil {
+			t.Fatal(err)
+		} else if len(names) != 2 || !strings.HasSuffix(names[1], ".cache") {
+			t.Fatalf("unexpected quarantined files: %v", names)
+		}
+
+		// The other shard is opened, and the quarantined one is created
+		// fresh when written to.
+		if cols := h.Row("foo", "bar", 1).Columns(); !reflect.DeepEqual(cols, []uint64{1}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+		h.SetBit("foo", "bar", 2, pilosa.ShardWidth+2)
+		if cols := h.Row("foo", "bar", 1).Columns(); !reflect.DeepEqual(cols, []uint64{1}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		} else if cols := h.Row("foo", "bar", 2).Columns(); !reflect.DeepEqual(cols, []uint64{pilosa.ShardWidth + 2}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		} else if _, err := os.Stat(filepath.Join(viewPath, "fragments", "1")); err != nil {
+			t.Fatalf("expected fresh fragment file: %v", err)
+		}
+	})
+
+	t.Run("Parallel", func(t *testing.T) {
+		h := test.MustOpenHolder()
+		defer h.Close()
+
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				for shard := uint64(0); shard < 3; shard++ {
+					h.SetBit(fmt.Sprintf("i%d", i), fmt.Sprintf("f%d", j), uint64(i), shard*pilosa.ShardWidth+uint64(j))
+				}
+			}
+		}
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		} else if err := h.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				exp := []uint64{uint64(j), pilosa.ShardWidth + uint64(j), 2*pilosa.ShardWidth + uint64(j)}
+				if cols := h.Row(fmt.Sprintf("i%d", i), fmt.Sprintf("f%d", j), uint64(i)).Columns(); !reflect.DeepEqual(cols, exp) {
+					t.Fatalf("i%d/f%d: unexpected columns: %v", i, j, cols)
+				}
+			}
+		}
+	})
+
+	t.Run("ErrMultipleIndexes", func(t *testing.T) {
+		h := test.MustOpenHolder()
+		defer h.Close()
+
+		for _, name := range []string{"foo", "bar", "baz"} {
+			if idx, err := h.CreateIndex(name, pilosa.IndexOptions{}); err != nil {
+				t.Fatal(err)
+			} else if _, err := idx.CreateField("f", pilosa.OptFieldTypeDefault()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"foo", "baz"} {
+			if err := os.Truncate(filepath.Join(h.Path, name, "f", ".meta"), 2); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Every failing index is reported, not just the first.
+		err := h.Reopen()
+		if errs, ok := err.(roaring.ErrorList); !ok || len(errs) != 2 {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		msgs := err.(roaring.ErrorList)[0].Error() + "\n" + err.(roaring.ErrorList)[1].Error()
+		for _, name := range []string{"foo", "baz"} {
+			if !strings.Contains(msgs, "open index: name="+name+", err=opening fields: open field: name=f") {
+				t.Fatalf("expected error for %s, got: %s", name, msgs)
+			}
+		}
+		// The index which opened is closed again with the holder.
+		if h.Index("bar") != nil {
+			t.Fatal("expected bar to be closed")
 		}
 	})
+}
+
+// BenchmarkHolder_Open measures opening a holder of many fragments, one field
+// at a time and several at once.
+func BenchmarkHolder_Open(b *testing.B) {
+	const indexN, fieldN, shardN = 5, 20, 20
+	h := test.MustOpenHolder()
+	defer os.RemoveAll(h.Path)
+	for i := 0; i < indexN; i++ {
+		for j := 0; j < fieldN; j++ {
+			for shard := uint64(0); shard < shardN; shard++ {
+				h.SetBit(fmt.Sprintf("i%d", i), fmt.Sprintf("f%d", j), 1, shard*pilosa.ShardWidth)
+			}
+		}
+	}
+	if err := h.Holder.Close(); err != nil {
+		b.Fatal(err)
+	}
 
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				hldr := pilosa.NewHolder()
+				hldr.Path = h.Path
+				hldr.NewAttrStore = boltdb.NewAttrStore
+				hldr.OpenWorkers = workers
+				if err := hldr.Open(); err != nil {
+					b.Fatal(err)
+				} else if err := hldr.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
 }
 
 func TestHolder_HasData(t *testing.T) {
@@ -268,6 +485,137 @@ func TestHolder_DeleteIndex(t *testing.T) {
 	} else if _, err := os.Stat(hldr.IndexPath("i1")); err != nil {
 		t.Fatal("expected i1 files to still exist", err)
 	}
+
+	// Ensure i0 can't be found or deleted again.
+	if hldr.Index("i0") != nil {
+		t.Fatal("expected i0 to be removed")
+	} else if err := hldr.DeleteIndex("i0"); err == nil {
+		t.Fatal("expected error deleting i0 again")
+	} else if _, ok := err.(pilosa.NotFoundError); !ok || err.Error() != pilosa.ErrIndexNotFound.Error() {
+		t.Fatalf("unexpected error deleting i0 again: %#v", err)
+	}
+}
+
+// Ensure the schema limits apply to new indexes, fields and views but not to
+// existing ones.
+func TestHolder_SchemaLimits(t *testing.T) {
+	h := test.NewHolder()
+	h.MaxIndexes, h.MaxFieldsPerIndex, h.MaxViewsPerField = 2, 1, 1
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	isLimit := func(err, target error) bool {
+		_, ok := errors.Cause(err).(pilosa.BadRequestError)
+		return ok && strings.Contains(err.Error(), target.Error())
+	}
+
+	h.MustCreateIndexIfNotExists("i0", pilosa.IndexOptions{TrackExistence: true})
+	h.MustCreateIndexIfNotExists("i1", pilosa.IndexOptions{})
+	if _, err := h.CreateIndex("i2", pilosa.IndexOptions{}); !isLimit(err, pilosa.ErrTooManyIndexes) {
+		t.Fatalf("unexpected error creating index: %v", err)
+	} else if _, err := h.CreateIndexIfNotExists("i1", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The existence field isn't counted.
+	idx := h.Index("i0")
+	f, err := idx.CreateField("f", pilosa.OptFieldTypeTime(pilosa.TimeQuantum("Y")))
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := idx.CreateField("g"); !isLimit(err, pilosa.ErrTooManyFields) {
+		t.Fatalf("unexpected error creating field: %v", err)
+	}
+
+	// A timestamp needs a second view.
+	if _, err := f.SetBit(1, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := f.SetBit(1, 2, &ts); !isLimit(err, pilosa.ErrTooManyViews) {
+		t.Fatalf("unexpected error setting bit: %v", err)
+	}
+
+	if limits := h.SchemaLimits(); limits != (pilosa.SchemaLimits{
+		Indexes:        pilosa.SchemaLimit{Count: 2, Limit: 2},
+		FieldsPerIndex: pilosa.SchemaLimit{Count: 1, Limit: 1},
+		ViewsPerField:  pilosa.SchemaLimit{Count: 1, Limit: 1},
+	}) {
+		t.Fatalf("unexpected limits: %+v", limits)
+	}
+
+	// Lowering the limits below the existing counts still opens everything.
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := h.Path
+	h.Holder = pilosa.NewHolder()
+	h.Path = path
+	h.NewAttrStore = boltdb.NewAttrStore
+	h.MaxIndexes, h.MaxFieldsPerIndex, h.MaxViewsPerField = 1, 1, 1
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(h.Indexes()); n != 2 {
+		t.Fatalf("unexpected index count: %d", n)
+	} else if _, err := h.CreateIndex("i2", pilosa.IndexOptions{}); !isLimit(err, pilosa.ErrTooManyIndexes) {
+		t.Fatalf("unexpected error creating index after reopen: %v", err)
+	}
+}
+
+// Ensure queries running while their index is deleted either complete or fail
+// with an index not found error.
+func TestHolder_DeleteIndexConcurrentQueries(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	holder := cmd.Server.Holder()
+
+	for n := 0; n < 10; n++ {
+		hldr := test.Holder{Holder: holder}
+		const shardN = 32
+		for shard := uint64(0); shard < shardN; shard++ {
+			hldr.SetBit("i", "f", 1, shard*pilosa.ShardWidth+uint64(n))
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var unexpected []error
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					resp, err := cmd.API.Query(context.Background(), &pilosa.QueryRequest{
+						Index: "i",
+						Query: "Row(f=1) Count(Row(f=1)) TopN(f) Set(10, f=2)",
+					})
+					if errors.Cause(err) == pilosa.ErrIndexNotFound {
+						return
+					} else if err == nil && resp.Results[1] != uint64(shardN) {
+						err = fmt.Errorf("incomplete count: %v", resp.Results[1])
+					}
+					if err != nil {
+						mu.Lock()
+						unexpected = append(unexpected, err)
+						mu.Unlock()
+						return
+					}
+				}
+			}()
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		if err := holder.DeleteIndex("i"); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+
+		if len(unexpected) > 0 {
+			t.Fatalf("unexpected query errors: %v", unexpected)
+		} else if _, err := os.Stat(holder.IndexPath("i")); !os.IsNotExist(err) {
+			t.Fatalf("expected index directory to be removed: %v", err)
+		}
+	}
 }
 
 // Ensure holder can sync with a remote holder.
@@ -415,3 +763,39 @@ func TestHolderSyncer_TimeQuantum(t *testing.T) {
 		}
 	}
 }
+
+// Ensure the holder syncer logs fields which only exist on another node.
+func TestHolderSyncer_SchemaDivergence(t *testing.T) {
+	c := test.MustNewCluster(t, 2)
+	c[0].Config.AntiEntropy.Interval = 0
+	c[1].Config.AntiEntropy.Interval = 0
+	if err := c.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c[0].API.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatalf("creating index i: %v", err)
+	} else if _, err := c[0].API.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatalf("creating field f: %v", err)
+	}
+
+	// Matching schemas log nothing.
+	capture := logger.NewCaptureLogger()
+	c[0].Server.Holder().Logger = capture
+	if err := c[0].Server.SyncData(); err != nil {
+		t.Fatalf("syncing node 0: %v", err)
+	} else if capture.Contains(logger.InfoLevel, "schema differs") {
+		t.Fatalf("unexpected log: %+v", capture.Entries())
+	}
+
+	// Create a field on the remote node only.
+	if _, err := c[1].Server.Holder().Index("i").CreateField("g"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c[0].Server.SyncData(); err != nil {
+		t.Fatalf("syncing node 0: %v", err)
+	} else if !capture.Contains(logger.InfoLevel, "schema differs from node node1: missing i/g") {
+		t.Fatalf("expected log: %+v", capture.Entries())
+	}
+}
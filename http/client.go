@@ -16,16 +16,20 @@ package http
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/pilosa/pilosa"
 	"github.com/pilosa/pilosa/encoding/proto"
@@ -40,6 +44,12 @@ type InternalClient struct {
 
 	// The client to use for HTTP communication.
 	httpClient *http.Client
+
+	// Whether import bodies are gzipped for nodes which accept them, and
+	// those nodes by host.
+	compressImports bool
+	mu              sync.Mutex
+	gzipHosts       map[string]bool
 }
 
 // NewInternalClient returns a new instance of InternalClient to connect to host.
@@ -65,6 +75,47 @@ func NewInternalClientFromURI(defaultURI *pilosa.URI, remoteClient *http.Client)
 	}
 }
 
+// SetImportCompression sets whether import bodies are sent gzipped to nodes
+// which advertise support for it. Other nodes are sent uncompressed bodies.
+func (c *InternalClient) SetImportCompression(enabled bool) {
+	c.compressImports = enabled
+}
+
+// acceptsGzipImports returns true if the node advertises, in the
+// Accept-Encoding header of its response to an OPTIONS request on the import
+// endpoint, that it accepts gzip-encoded imports. The answer is cached by
+// host, and errors are treated as not accepting them.
+func (c *InternalClient) acceptsGzipImports(ctx context.Context, node *pilosa.Node, index, field string) bool {
+	host := node.URI.HostPort()
+	c.mu.Lock()
+	ok, cached := c.gzipHosts[host]
+	c.mu.Unlock()
+	if cached {
+		return ok
+	}
+
+	u := nodePathToURL(node, fmt.Sprintf("/index/%s/field/%s/import", index, field))
+	req, err := http.NewRequest("OPTIONS", u.String(), nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	if resp, err := c.executeRequest(req.WithContext(ctx)); err == nil {
+		resp.Body.Close()
+		for _, v := range strings.Split(resp.Header.Get("Accept-Encoding"), ",") {
+			ok = ok || strings.TrimSpace(v) == "gzip"
+		}
+	}
+
+	c.mu.Lock()
+	if c.gzipHosts == nil {
+		c.gzipHosts = make(map[string]bool)
+	}
+	c.gzipHosts[host] = ok
+	c.mu.Unlock()
+	return ok
+}
+
 // MaxShardByIndex returns the number of shards on a server by index.
 func (c *InternalClient) MaxShardByIndex(ctx context.Context) (map[string]uint64, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.MaxShardByIndex")
@@ -101,13 +152,14 @@ func (c *InternalClient) maxShardByIndex(ctx context.Context) (map[string]uint64
 	return rsp.Standard, nil
 }
 
-// Schema returns all index and field schema information.
+// Schema returns all index and field schema information, including the
+// views of each field.
 func (c *InternalClient) Schema(ctx context.Context) ([]*pilosa.IndexInfo, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.Schema")
 	defer span.Finish()
 
 	// Execute request against the host.
-	u := c.defaultURI.Path("/schema")
+	u := c.defaultURI.Path("/schema?views=true")
 
 	// Build request.
 	req, err := http.NewRequest("GET", u, nil)
@@ -132,6 +184,98 @@ func (c *InternalClient) Schema(ctx context.Context) ([]*pilosa.IndexInfo, error
 	return rsp.Indexes, nil
 }
 
+// ApplySchema creates the indexes, fields and views of schema which don't
+// exist on the node at uri, as returned by Schema. Those which exist with
+// other options are reported in the error.
+func (c *InternalClient) ApplySchema(ctx context.Context, uri *pilosa.URI, schema []*pilosa.IndexInfo) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ApplySchema")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	buf, err := json.Marshal(getSchemaResponse{Indexes: schema})
+	if err != nil {
+		return errors.Wrap(err, "marshaling")
+	}
+
+	u := uriPathToURL(uri, "/schema/apply")
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(resp.Body.Close(), "closing response body")
+}
+
+// CanonicalSchema returns the canonical schema of the node at uri.
+func (c *InternalClient) CanonicalSchema(ctx context.Context, uri *pilosa.URI) (*pilosa.CanonicalSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.CanonicalSchema")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, "/internal/schema")
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rsp pilosa.CanonicalSchema
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+	return &rsp, nil
+}
+
+// FieldViews returns the views of a field on the node at uri, sorted by name.
+func (c *InternalClient) FieldViews(ctx context.Context, uri *pilosa.URI, index, field string) ([]*pilosa.ViewInfo, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FieldViews")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/views", index, field))
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, pilosa.ErrFieldNotFound
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rsp getViewsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+	return rsp.Views, nil
+}
+
 // CreateIndex creates a new index on the server.
 func (c *InternalClient) CreateIndex(ctx context.Context, index string, opt pilosa.IndexOptions) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.CreateIndex")
@@ -167,6 +311,61 @@ func (c *InternalClient) CreateIndex(ctx context.Context, index string, opt pilo
 	return errors.Wrap(resp.Body.Close(), "closing response body")
 }
 
+// RenameIndex renames an index on every node.
+func (c *InternalClient) RenameIndex(ctx context.Context, index, newName string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.RenameIndex")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&postIndexRenameRequest{Name: newName})
+	if err != nil {
+		return errors.Wrap(err, "encoding request")
+	}
+
+	u := uriPathToURL(c.defaultURI, fmt.Sprintf("/index/%s/rename", index))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(resp.Body.Close(), "closing response body")
+}
+
+// DeleteColumnRange deletes the columns in [min, max] of an index on every
+// node.
+func (c *InternalClient) DeleteColumnRange(ctx context.Context, index string, min, max uint64) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.DeleteColumnRange")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&postIndexDeleteColumnsRequest{Min: &min, Max: &max})
+	if err != nil {
+		return errors.Wrap(err, "encoding request")
+	}
+
+	u := uriPathToURL(c.defaultURI, fmt.Sprintf("/index/%s/delete-columns", index))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(resp.Body.Close(), "closing response body")
+}
+
 // FragmentNodes returns a list of nodes that own a shard.
 func (c *InternalClient) FragmentNodes(ctx context.Context, index string, shard uint64) ([]*pilosa.Node, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FragmentNodes")
@@ -230,6 +429,46 @@ func (c *InternalClient) Nodes(ctx context.Context) ([]*pilosa.Node, error) {
 	return a, nil
 }
 
+// HolderStats returns the statistics of the views, and their fragments, which
+// the node at uri holds of a field, or of every field of the index if field
+// is empty.
+func (c *InternalClient) HolderStats(ctx context.Context, uri *pilosa.URI, index, field string) ([]*pilosa.ViewStats, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.HolderStats")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, "/debug/holder")
+	q := url.Values{"index": {index}}
+	if field != "" {
+		q.Set("field", field)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// A response cut short by an error on the node fails to decode.
+	var rsp struct {
+		Views []*pilosa.ViewStats `json:"views"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return nil, fmt.Errorf("json decode: %s", err)
+	}
+	return rsp.Views, nil
+}
+
 // Query executes query against the index.
 func (c *InternalClient) Query(ctx context.Context, index string, queryRequest *pilosa.QueryRequest) (*pilosa.QueryResponse, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.Query")
@@ -237,6 +476,43 @@ func (c *InternalClient) Query(ctx context.Context, index string, queryRequest *
 	return c.QueryNode(ctx, c.defaultURI, index, queryRequest)
 }
 
+// QueryStream executes a query of a single call returning a row, and returns
+// the body of the response, which streams the columns of the row as
+// newline-delimited JSON: {"columns":[...]} lines in ascending order, then a
+// {"count":n} line, or an {"error":"..."} line if the query failed part way.
+// The caller must close the body.
+func (c *InternalClient) QueryStream(ctx context.Context, index string, queryRequest *pilosa.QueryRequest) (io.ReadCloser, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.QueryStream")
+	defer span.Finish()
+
+	if index == "" {
+		return nil, pilosa.ErrIndexRequired
+	} else if queryRequest.Query == "" {
+		return nil, pilosa.ErrQueryRequired
+	}
+
+	buf, err := c.serializer.Marshal(queryRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling queryRequest")
+	}
+
+	u := c.defaultURI.Path(fmt.Sprintf("/index/%s/query", index))
+	req, err := http.NewRequest("POST", u, bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Accept", "application/x-ndjson")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 // QueryNode executes query against the index, sending the request to the node specified.
 func (c *InternalClient) QueryNode(ctx context.Context, uri *pilosa.URI, index string, queryRequest *pilosa.QueryRequest) (*pilosa.QueryResponse, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "QueryNode")
@@ -265,9 +541,17 @@ func (c *InternalClient) QueryNode(ctx context.Context, uri *pilosa.URI, index s
 	req.Header.Set("Accept", "application/x-protobuf")
 	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
 
-	// Execute request against the host.
+	// Execute request against the host. A node which can't be reached or
+	// fails with a server error is reported as unavailable, so that reads
+	// can be retried against a replica. A query which timed out on the node
+	// isn't retried.
 	resp, err := c.executeRequest(req.WithContext(ctx))
 	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusGatewayTimeout {
+			return nil, pilosa.ErrQueryTimeout
+		} else if (resp == nil && ctx.Err() == nil) || (resp != nil && resp.StatusCode >= http.StatusInternalServerError) {
+			return nil, pilosa.NewNodeUnavailableError(err)
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -288,15 +572,44 @@ func (c *InternalClient) QueryNode(ctx context.Context, uri *pilosa.URI, index s
 	return qresp, nil
 }
 
-// Import bulk imports bits for a single shard to a host.
-func (c *InternalClient) Import(ctx context.Context, index, field string, shard uint64, bits []pilosa.Bit, opts ...pilosa.ImportOption) error {
+// RenameField renames a field of an index on every node.
+func (c *InternalClient) RenameField(ctx context.Context, index, field, newName string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.RenameField")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&postFieldRenameRequest{Name: newName})
+	if err != nil {
+		return errors.Wrap(err, "encoding request")
+	}
+
+	u := uriPathToURL(c.defaultURI, fmt.Sprintf("/index/%s/field/%s/rename", index, field))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(resp.Body.Close(), "closing response body")
+}
+
+// Import bulk imports bits for a single shard to a host. It returns the
+// number of distinct bits imported and how many of them changed, as counted by
+// the first node which owns the shard.
+func (c *InternalClient) Import(ctx context.Context, index, field string, shard uint64, bits []pilosa.Bit, opts ...pilosa.ImportOption) (pilosa.ImportCount, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.Import")
 	defer span.Finish()
 
 	if index == "" {
-		return pilosa.ErrIndexRequired
+		return pilosa.ImportCount{}, pilosa.ErrIndexRequired
 	} else if field == "" {
-		return pilosa.ErrFieldRequired
+		return pilosa.ImportCount{}, pilosa.ErrFieldRequired
 	}
 
 	// Set up import options.
@@ -304,28 +617,122 @@ func (c *InternalClient) Import(ctx context.Context, index, field string, shard
 	for _, opt := range opts {
 		err := opt(options)
 		if err != nil {
-			return errors.Wrap(err, "applying option")
+			return pilosa.ImportCount{}, errors.Wrap(err, "applying option")
 		}
 	}
 
 	buf, err := c.marshalImportPayload(index, field, shard, bits)
 	if err != nil {
-		return fmt.Errorf("Error Creating Payload: %s", err)
+		return pilosa.ImportCount{}, fmt.Errorf("Error Creating Payload: %s", err)
 	}
 
 	// Retrieve a list of nodes that own the shard.
+	nodes, err := c.FragmentNodes(ctx, index, shard)
+	if err != nil {
+		return pilosa.ImportCount{}, fmt.Errorf("shard nodes: %s", err)
+	}
+
+	// Import to each node. Replicas may not hold the same bits, so only the
+	// first node's counts are returned.
+	count := pilosa.ImportCount{Shard: shard}
+	for i, node := range nodes {
+		resp, err := c.importNode(ctx, node, index, field, buf, options)
+		if err != nil {
+			return pilosa.ImportCount{}, fmt.Errorf("import node: host=%s, err=%s", node.URI, err)
+		} else if i > 0 {
+			continue
+		}
+		for _, n := range resp.Counts {
+			count.Bits += n.Bits
+			count.Changed += n.Changed
+		}
+	}
+
+	return count, nil
+}
+
+// ImportRoaringRows imports whole rows of a single shard to every node which
+// owns it. Each row is a serialized roaring bitmap of column offsets within the
+// shard.
+func (c *InternalClient) ImportRoaringRows(ctx context.Context, index, field string, shard uint64, rows []pilosa.ImportRoaringRow, opts ...pilosa.ImportOption) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ImportRoaringRows")
+	defer span.Finish()
+
+	if index == "" {
+		return pilosa.ErrIndexRequired
+	} else if field == "" {
+		return pilosa.ErrFieldRequired
+	}
+
+	options := &pilosa.ImportOptions{}
+	for _, opt := range opts {
+		err := opt(options)
+		if err != nil {
+			return errors.Wrap(err, "applying option")
+		}
+	}
+
+	buf, err := c.serializer.Marshal(&pilosa.ImportRoaringRowsRequest{
+		Index: index,
+		Field: field,
+		Shard: shard,
+		Rows:  rows,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshaling import request")
+	}
+
 	nodes, err := c.FragmentNodes(ctx, index, shard)
 	if err != nil {
 		return fmt.Errorf("shard nodes: %s", err)
 	}
 
-	// Import to each node.
 	for _, node := range nodes {
-		if err := c.importNode(ctx, node, index, field, buf, options); err != nil {
+		if _, err := c.importNodeAs(ctx, node, index, field, contentTypeRoaringRows, buf, options); err != nil {
 			return fmt.Errorf("import node: host=%s, err=%s", node.URI, err)
 		}
 	}
+	return nil
+}
 
+// ImportView imports the bits of a request into the view it names on every
+// node which owns its shard. The request is usually read from a protobuf
+// export.
+func (c *InternalClient) ImportView(ctx context.Context, req *pilosa.ImportRequest, opts ...pilosa.ImportOption) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ImportView")
+	defer span.Finish()
+
+	if req.Index == "" {
+		return pilosa.ErrIndexRequired
+	} else if req.Field == "" {
+		return pilosa.ErrFieldRequired
+	} else if req.View == "" {
+		return pilosa.ErrInvalidView
+	}
+
+	options := &pilosa.ImportOptions{}
+	for _, opt := range opts {
+		err := opt(options)
+		if err != nil {
+			return errors.Wrap(err, "applying option")
+		}
+	}
+
+	buf, err := c.serializer.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshaling import request")
+	}
+
+	nodes, err := c.FragmentNodes(ctx, req.Index, req.Shard)
+	if err != nil {
+		return fmt.Errorf("shard nodes: %s", err)
+	}
+
+	for _, node := range nodes {
+		if _, err := c.importNode(ctx, node, req.Index, req.Field, buf, options); err != nil {
+			return fmt.Errorf("import node: host=%s, err=%s", node.URI, err)
+		}
+	}
 	return nil
 }
 
@@ -338,15 +745,17 @@ func getCoordinatorNode(nodes []*pilosa.Node) *pilosa.Node {
 	return nil
 }
 
-// ImportK bulk imports bits specified by string keys to a host.
-func (c *InternalClient) ImportK(ctx context.Context, index, field string, bits []pilosa.Bit, opts ...pilosa.ImportOption) error {
+// ImportK bulk imports bits specified by string keys to a host. It returns the
+// number of distinct bits imported into each shard and how many of them
+// changed.
+func (c *InternalClient) ImportK(ctx context.Context, index, field string, bits []pilosa.Bit, opts ...pilosa.ImportOption) ([]pilosa.ImportCount, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ImportK")
 	defer span.Finish()
 
 	if index == "" {
-		return pilosa.ErrIndexRequired
+		return nil, pilosa.ErrIndexRequired
 	} else if field == "" {
-		return pilosa.ErrFieldRequired
+		return nil, pilosa.ErrFieldRequired
 	}
 
 	// Set up import options.
@@ -354,32 +763,33 @@ func (c *InternalClient) ImportK(ctx context.Context, index, field string, bits
 	for _, opt := range opts {
 		err := opt(options)
 		if err != nil {
-			return errors.Wrap(err, "applying option")
+			return nil, errors.Wrap(err, "applying option")
 		}
 	}
 
 	buf, err := c.marshalImportPayload(index, field, 0, bits)
 	if err != nil {
-		return fmt.Errorf("Error Creating Payload: %s", err)
+		return nil, fmt.Errorf("Error Creating Payload: %s", err)
 	}
 
 	// Get the coordinator node; all bits are sent to the
 	// primary translate store (i.e. coordinator).
 	nodes, err := c.Nodes(ctx)
 	if err != nil {
-		return fmt.Errorf("getting nodes: %s", err)
+		return nil, fmt.Errorf("getting nodes: %s", err)
 	}
 	coord := getCoordinatorNode(nodes)
 	if coord == nil {
-		return fmt.Errorf("could not find the coordinator node")
+		return nil, fmt.Errorf("could not find the coordinator node")
 	}
 
 	// Import to node.
-	if err := c.importNode(ctx, coord, index, field, buf, options); err != nil {
-		return fmt.Errorf("import node: host=%s, err=%s", coord.URI, err)
+	resp, err := c.importNode(ctx, coord, index, field, buf, options)
+	if err != nil {
+		return nil, fmt.Errorf("import node: host=%s, err=%s", coord.URI, err)
 	}
 
-	return nil
+	return resp.Counts, nil
 }
 
 func (c *InternalClient) EnsureIndex(ctx context.Context, name string, options pilosa.IndexOptions) error {
@@ -436,7 +846,13 @@ func (c *InternalClient) marshalImportPayload(index, field string, shard uint64,
 }
 
 // importNode sends a pre-marshaled import request to a node.
-func (c *InternalClient) importNode(ctx context.Context, node *pilosa.Node, index, field string, buf []byte, opts *pilosa.ImportOptions) error {
+func (c *InternalClient) importNode(ctx context.Context, node *pilosa.Node, index, field string, buf []byte, opts *pilosa.ImportOptions) (*pilosa.ImportResponse, error) {
+	return c.importNodeAs(ctx, node, index, field, "application/x-protobuf", buf, opts)
+}
+
+// importNodeAs posts an import payload of the given content type to a node,
+// and returns its response.
+func (c *InternalClient) importNodeAs(ctx context.Context, node *pilosa.Node, index, field, contentType string, buf []byte, opts *pilosa.ImportOptions) (*pilosa.ImportResponse, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.importNode")
 	defer span.Finish()
 
@@ -453,36 +869,52 @@ func (c *InternalClient) importNode(ctx context.Context, node *pilosa.Node, inde
 	}
 	url := fmt.Sprintf("%s?%s", u.String(), vals.Encode())
 
+	// Compress the body for nodes which accept it.
+	var encoding string
+	if c.compressImports && c.acceptsGzipImports(ctx, node, index, field) {
+		var zbuf bytes.Buffer
+		zw := gzip.NewWriter(&zbuf)
+		if _, err := zw.Write(buf); err != nil {
+			return nil, errors.Wrap(err, "compressing")
+		} else if err := zw.Close(); err != nil {
+			return nil, errors.Wrap(err, "compressing")
+		}
+		buf, encoding = zbuf.Bytes(), "gzip"
+	}
+
 	req, err := http.NewRequest("POST", url, bytes.NewReader(buf))
 	if err != nil {
-		return errors.Wrap(err, "creating request")
+		return nil, errors.Wrap(err, "creating request")
 	}
 	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
-	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Type", contentType)
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
 	req.Header.Set("Accept", "application/x-protobuf")
 	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
 
 	// Execute request against the host.
 	resp, err := c.executeRequest(req.WithContext(ctx))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Read body and unmarshal response.
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "reading")
+		return nil, errors.Wrap(err, "reading")
 	}
 
 	var isresp pilosa.ImportResponse
 	if err := c.serializer.Unmarshal(body, &isresp); err != nil {
-		return fmt.Errorf("unmarshal import response: %s", err)
+		return nil, fmt.Errorf("unmarshal import response: %s", err)
 	} else if s := isresp.Err; s != "" {
-		return errors.New(s)
+		return nil, errors.New(s)
 	}
 
-	return nil
+	return &isresp, nil
 }
 
 // ImportValue bulk imports field values for a single shard to a host.
@@ -518,7 +950,7 @@ func (c *InternalClient) ImportValue(ctx context.Context, index, field string, s
 
 	// Import to each node.
 	for _, node := range nodes {
-		if err := c.importNode(ctx, node, index, field, buf, options); err != nil {
+		if _, err := c.importNode(ctx, node, index, field, buf, options); err != nil {
 			return fmt.Errorf("import node: host=%s, err=%s", node.URI, err)
 		}
 	}
@@ -557,7 +989,7 @@ func (c *InternalClient) ImportValueK(ctx context.Context, index, field string,
 	}
 
 	// Import to node.
-	if err := c.importNode(ctx, coord, index, field, buf, options); err != nil {
+	if _, err := c.importNode(ctx, coord, index, field, buf, options); err != nil {
 		return fmt.Errorf("import node: host=%s, err=%s", coord.URI, err)
 	}
 
@@ -637,7 +1069,7 @@ func (c *InternalClient) ImportRoaring(ctx context.Context, uri *pilosa.URI, ind
 }
 
 // ExportCSV bulk exports data for a single shard from a host to CSV format.
-func (c *InternalClient) ExportCSV(ctx context.Context, index, field string, shard uint64, w io.Writer) error {
+func (c *InternalClient) ExportCSV(ctx context.Context, index, field string, shard uint64, w io.Writer, opts ...pilosa.ExportOption) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ExportCSV")
 	defer span.Finish()
 
@@ -647,6 +1079,11 @@ func (c *InternalClient) ExportCSV(ctx context.Context, index, field string, sha
 		return pilosa.ErrFieldRequired
 	}
 
+	filter, err := exportFilter(opts...)
+	if err != nil {
+		return errors.Wrap(err, "setting up export options")
+	}
+
 	// Retrieve a list of nodes that own the shard.
 	nodes, err := c.FragmentNodes(ctx, index, shard)
 	if err != nil {
@@ -658,7 +1095,7 @@ func (c *InternalClient) ExportCSV(ctx context.Context, index, field string, sha
 	for _, i := range rand.Perm(len(nodes)) {
 		node := nodes[i]
 
-		if err := c.exportNodeCSV(ctx, node, index, field, shard, w); err != nil {
+		if err := c.exportNodeCSV(ctx, node, index, field, shard, filter, w); err != nil {
 			e = fmt.Errorf("export node: host=%s, err=%s", node.URI, err)
 			continue
 		} else {
@@ -670,17 +1107,21 @@ func (c *InternalClient) ExportCSV(ctx context.Context, index, field string, sha
 }
 
 // exportNode copies a CSV export from a node to w.
-func (c *InternalClient) exportNodeCSV(ctx context.Context, node *pilosa.Node, index, field string, shard uint64, w io.Writer) error {
+func (c *InternalClient) exportNodeCSV(ctx context.Context, node *pilosa.Node, index, field string, shard uint64, filter url.Values, w io.Writer) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.exportNodeCSV")
 	defer span.Finish()
 
 	// Create URL.
 	u := nodePathToURL(node, "/export")
-	u.RawQuery = url.Values{
+	q := url.Values{
 		"index": {index},
 		"field": {field},
 		"shard": {strconv.FormatUint(shard, 10)},
-	}.Encode()
+	}
+	for k, v := range filter {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
 
 	// Generate HTTP request.
 	req, err := http.NewRequest("GET", u.String(), nil)
@@ -705,6 +1146,110 @@ func (c *InternalClient) exportNodeCSV(ctx context.Context, node *pilosa.Node, i
 	return nil
 }
 
+// ExportProto bulk exports a shard of a field to w as a stream of
+// length-prefixed ImportRequest messages of at most chunk bits each.
+func (c *InternalClient) ExportProto(ctx context.Context, index, field string, shard uint64, chunk int, w io.Writer, opts ...pilosa.ExportOption) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ExportProto")
+	defer span.Finish()
+
+	if index == "" {
+		return pilosa.ErrIndexRequired
+	} else if field == "" {
+		return pilosa.ErrFieldRequired
+	}
+
+	filter, err := exportFilter(opts...)
+	if err != nil {
+		return errors.Wrap(err, "setting up export options")
+	}
+
+	// Retrieve a list of nodes that own the shard.
+	nodes, err := c.FragmentNodes(ctx, index, shard)
+	if err != nil {
+		return fmt.Errorf("shard nodes: %s", err)
+	}
+
+	// Attempt nodes in random order.
+	var e error
+	for _, i := range rand.Perm(len(nodes)) {
+		node := nodes[i]
+
+		if err := c.exportNodeProto(ctx, node, index, field, shard, chunk, filter, w); err != nil {
+			e = fmt.Errorf("export node: host=%s, err=%s", node.URI, err)
+			continue
+		}
+		return nil
+	}
+
+	return e
+}
+
+// exportNodeProto copies a protobuf export from a node to w.
+func (c *InternalClient) exportNodeProto(ctx context.Context, node *pilosa.Node, index, field string, shard uint64, chunk int, filter url.Values, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.exportNodeProto")
+	defer span.Finish()
+
+	u := nodePathToURL(node, "/export")
+	q := url.Values{
+		"index":  {index},
+		"field":  {field},
+		"shard":  {strconv.FormatUint(shard, 10)},
+		"format": {"proto"},
+		"chunk":  {strconv.Itoa(chunk)},
+	}
+	for k, v := range filter {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Accept", "application/x-protobuf")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return errors.Wrap(err, "copying")
+	}
+
+	return nil
+}
+
+// exportFilter returns the query parameters of an /export request for the
+// row filters in opts.
+func exportFilter(opts ...pilosa.ExportOption) (url.Values, error) {
+	options, err := pilosa.NewExportOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := url.Values{}
+	if options.MinRowID != 0 {
+		filter.Set("minBitmapID", strconv.FormatUint(options.MinRowID, 10))
+	}
+	if options.MaxRowID != math.MaxUint64 {
+		filter.Set("maxBitmapID", strconv.FormatUint(options.MaxRowID, 10))
+	}
+	if options.RowIDs != nil {
+		rowIDs := make([]string, len(options.RowIDs))
+		for i, rowID := range options.RowIDs {
+			rowIDs[i] = strconv.FormatUint(rowID, 10)
+		}
+		filter.Set("bitmapIDs", strings.Join(rowIDs, ","))
+	}
+	if options.View != "" {
+		filter.Set("view", options.View)
+	}
+	return filter, nil
+}
+
 // RetrieveShardFromURI returns a ReadCloser which contains the data of the
 // specified shard from the specified node. Caller *must* close the returned
 // ReadCloser or risk leaking goroutines/tcp connections.
@@ -823,36 +1368,77 @@ func (c *InternalClient) FragmentBlocks(ctx context.Context, uri *pilosa.URI, in
 		"shard": {strconv.FormatUint(shard, 10)},
 	}.Encode()
 
-	// Build request.
+	// Build request.
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	// Execute request.
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		// Return the appropriate error.
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, pilosa.ErrFragmentNotFound
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Decode response object.
+	var rsp getFragmentBlocksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+	return rsp.Blocks, nil
+}
+
+// FragmentChecksums returns the checksum of each fragment of a view on a host.
+func (c *InternalClient) FragmentChecksums(ctx context.Context, uri *pilosa.URI, index, field, view string) ([]pilosa.FragmentChecksum, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FragmentChecksums")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, "/internal/fragment/checksums")
+	u.RawQuery = url.Values{
+		"index": {index},
+		"field": {field},
+		"view":  {view},
+	}.Encode()
+
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating request")
 	}
-
 	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
 	req.Header.Set("Accept", "application/json")
 
-	// Execute request.
 	resp, err := c.executeRequest(req.WithContext(ctx))
 	if err != nil {
-		// Return the appropriate error.
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
-			return nil, pilosa.ErrFragmentNotFound
+			return nil, pilosa.ErrFieldNotFound
 		}
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Decode response object.
-	var rsp getFragmentBlocksResponse
+	var rsp getFragmentChecksumsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
 		return nil, errors.Wrap(err, "decoding")
 	}
-	return rsp.Blocks, nil
+	return rsp.Checksums, nil
 }
 
-// BlockData returns row/column id pairs for a block.
-func (c *InternalClient) BlockData(ctx context.Context, uri *pilosa.URI, index, field, view string, shard uint64, block int) ([]uint64, []uint64, error) {
+// BlockData returns a page of the row/column id pairs for a block, skipping
+// the first offset pairs and returning at most limit pairs. A zero limit
+// returns all pairs from offset. more is true if the block has pairs after
+// the page; nodes which don't paginate always return the whole block.
+func (c *InternalClient) BlockData(ctx context.Context, uri *pilosa.URI, index, field, view string, shard uint64, block, offset, limit int) (rowIDs, columnIDs []uint64, more bool, err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.BlockData")
 	defer span.Finish()
 
@@ -860,20 +1446,22 @@ func (c *InternalClient) BlockData(ctx context.Context, uri *pilosa.URI, index,
 		panic("need to pass a URI to BlockData")
 	}
 	buf, err := c.serializer.Marshal(&pilosa.BlockDataRequest{
-		Index: index,
-		Field: field,
-		View:  view,
-		Shard: shard,
-		Block: uint64(block),
+		Index:  index,
+		Field:  field,
+		View:   view,
+		Shard:  shard,
+		Block:  uint64(block),
+		Offset: uint64(offset),
+		Limit:  uint64(limit),
 	})
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "marshaling")
+		return nil, nil, false, errors.Wrap(err, "marshaling")
 	}
 
 	u := uriPathToURL(uri, "/internal/fragment/block/data")
 	req, err := http.NewRequest("GET", u.String(), bytes.NewReader(buf))
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "creating request")
+		return nil, nil, false, errors.Wrap(err, "creating request")
 	}
 	req.Header.Set("Content-Type", "application/protobuf")
 	req.Header.Set("Content-Length", strconv.Itoa(len(buf)))
@@ -883,20 +1471,20 @@ func (c *InternalClient) BlockData(ctx context.Context, uri *pilosa.URI, index,
 	resp, err := c.executeRequest(req.WithContext(ctx))
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
-			return nil, nil, nil
+			return nil, nil, false, nil
 		}
-		return nil, nil, err
+		return nil, nil, false, err
 	}
 	defer resp.Body.Close()
 
 	// Decode response object.
 	var rsp pilosa.BlockDataResponse
 	if body, err := ioutil.ReadAll(resp.Body); err != nil {
-		return nil, nil, errors.Wrap(err, "reading")
+		return nil, nil, false, errors.Wrap(err, "reading")
 	} else if err := c.serializer.Unmarshal(body, &rsp); err != nil {
-		return nil, nil, errors.Wrap(err, "unmarshalling")
+		return nil, nil, false, errors.Wrap(err, "unmarshalling")
 	}
-	return rsp.RowIDs, rsp.ColumnIDs, nil
+	return rsp.RowIDs, rsp.ColumnIDs, rsp.More, nil
 }
 
 // ColumnAttrDiff returns data from differing blocks on a remote host.
@@ -982,6 +1570,370 @@ func (c *InternalClient) RowAttrDiff(ctx context.Context, uri *pilosa.URI, index
 	return rsp.Attrs, nil
 }
 
+// RowAttrs returns the attributes of the rows of a field with the given IDs
+// on a remote host. The rows without attributes have empty attributes.
+func (c *InternalClient) RowAttrs(ctx context.Context, uri *pilosa.URI, index, field string, ids []uint64) (map[uint64]map[string]interface{}, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.RowAttrs")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/attr/bulk", index, field))
+
+	buf, err := json.Marshal(postRowAttrsBulkRequest{IDs: ids})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling")
+	}
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, pilosa.ErrFieldNotFound
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rsp postRowAttrsBulkResponse
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&rsp); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	} else if err := decodeAttrNumbers(rsp.Attrs); err != nil {
+		return nil, errors.Wrap(err, "decoding numbers")
+	}
+	return rsp.Attrs, nil
+}
+
+// ColumnAttrBlocks returns the checksums of the blocks of the column
+// attributes of an index on a remote host.
+func (c *InternalClient) ColumnAttrBlocks(ctx context.Context, uri *pilosa.URI, index string) ([]pilosa.AttrBlock, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ColumnAttrBlocks")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("/internal/index/%s/attr/blocks", index))
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rsp getIndexAttrBlocksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+	return rsp.Blocks, nil
+}
+
+// ColumnAttrBlockData returns a page of the column attributes in a block of
+// an index on a remote host, starting at column start. A limit of zero uses
+// the server's default.
+func (c *InternalClient) ColumnAttrBlockData(ctx context.Context, uri *pilosa.URI, index string, block, start uint64, limit int) (*pilosa.AttrBlockPage, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ColumnAttrBlockData")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("/internal/index/%s/attr/blocks/%d", index, block))
+	q := url.Values{"start": {strconv.FormatUint(start, 10)}}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var page pilosa.AttrBlockPage
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&page); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	} else if err := decodeAttrNumbers(page.Attrs); err != nil {
+		return nil, errors.Wrap(err, "decoding numbers")
+	}
+	return &page, nil
+}
+
+// ColumnAttrBlock returns all the column attributes in a block of an index
+// on a remote host, reading as many pages as the block needs.
+func (c *InternalClient) ColumnAttrBlock(ctx context.Context, uri *pilosa.URI, index string, block uint64) (map[uint64]map[string]interface{}, error) {
+	attrs := make(map[uint64]map[string]interface{})
+	var start uint64
+	for {
+		page, err := c.ColumnAttrBlockData(ctx, uri, index, block, start, 0)
+		if err != nil {
+			return nil, err
+		}
+		for id, m := range page.Attrs {
+			attrs[id] = m
+		}
+		if page.Next == nil {
+			return attrs, nil
+		}
+		start = *page.Next
+	}
+}
+
+// SetColumnAttrBlockData applies column attributes read from the blocks of
+// another store to an index on a remote host.
+func (c *InternalClient) SetColumnAttrBlockData(ctx context.Context, uri *pilosa.URI, index string, attrs map[uint64]map[string]interface{}) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.SetColumnAttrBlockData")
+	defer span.Finish()
+
+	if uri == nil {
+		uri = c.defaultURI
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("/internal/index/%s/attr/blocks", index))
+
+	buf, err := json.Marshal(postIndexAttrBlockDataRequest{Attrs: attrs})
+	if err != nil {
+		return errors.Wrap(err, "marshaling")
+	}
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// StartTimeMigration starts building the time views of the units in to from
+// the views of unit from on the node at uri.
+func (c *InternalClient) StartTimeMigration(ctx context.Context, uri *pilosa.URI, index, field string, from, to pilosa.TimeQuantum) (*pilosa.TimeMigration, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.StartTimeMigration")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&postTimeMigrationRequest{From: from, To: to})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling")
+	}
+
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/time-migration", index, field))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doTimeMigration(ctx, req)
+}
+
+// TimeMigration returns the progress of the latest time migration of a field
+// on the node at uri.
+func (c *InternalClient) TimeMigration(ctx context.Context, uri *pilosa.URI, index, field string) (*pilosa.TimeMigration, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.TimeMigration")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/time-migration", index, field))
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doTimeMigration(ctx, req)
+}
+
+func (c *InternalClient) doTimeMigration(ctx context.Context, req *http.Request) (*pilosa.TimeMigration, error) {
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var m pilosa.TimeMigration
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, errors.Wrap(err, "json decode")
+	}
+	return &m, nil
+}
+
+// StartFieldCopy creates the destination field of r as a copy of its source
+// on the node at uri and starts copying the node's data into it.
+func (c *InternalClient) StartFieldCopy(ctx context.Context, uri *pilosa.URI, r pilosa.FieldCopyRequest) (*pilosa.FieldCopy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.StartFieldCopy")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&postFieldCopyRequest{
+		ID:          r.ID,
+		SourceIndex: r.SourceIndex,
+		Source:      r.Source,
+		Options:     r.Options,
+		Overwrite:   r.Overwrite,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling")
+	}
+
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/copy", r.Index, r.Field))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doFieldCopy(ctx, req)
+}
+
+// FieldCopy returns the progress of the copy into a field on the node at uri.
+func (c *InternalClient) FieldCopy(ctx context.Context, uri *pilosa.URI, index, field string) (*pilosa.FieldCopy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FieldCopy")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/copy", index, field))
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doFieldCopy(ctx, req)
+}
+
+// FieldCopyByID returns the progress of the copy with the given ID on the
+// node at uri.
+func (c *InternalClient) FieldCopyByID(ctx context.Context, uri *pilosa.URI, id string) (*pilosa.FieldCopy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FieldCopyByID")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, "/field-copy/"+id)
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doFieldCopy(ctx, req)
+}
+
+// FinishFieldCopy marks the destination of a finished copy ready on the node
+// at uri.
+func (c *InternalClient) FinishFieldCopy(ctx context.Context, uri *pilosa.URI, index, field string) (*pilosa.FieldCopy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FinishFieldCopy")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("/index/%s/field/%s/copy/ready", index, field))
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doFieldCopy(ctx, req)
+}
+
+// RemoveNodeWithHandoff starts, or resumes, the removal of node id with a
+// handoff on the coordinator at uri.
+func (c *InternalClient) RemoveNodeWithHandoff(ctx context.Context, uri *pilosa.URI, id string) (*pilosa.NodeRemoval, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.RemoveNodeWithHandoff")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&removeNodeRequest{ID: id})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling")
+	}
+
+	u := uriPathToURL(uri, "/cluster/remove-node")
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doNodeRemoval(ctx, req)
+}
+
+// NodeRemoval returns the progress of the current or last removal of a node
+// with a handoff on the coordinator at uri.
+func (c *InternalClient) NodeRemoval(ctx context.Context, uri *pilosa.URI) (*pilosa.NodeRemoval, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.NodeRemoval")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, "/cluster/remove-node")
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+pilosa.Version)
+
+	return c.doNodeRemoval(ctx, req)
+}
+
+func (c *InternalClient) doNodeRemoval(ctx context.Context, req *http.Request) (*pilosa.NodeRemoval, error) {
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r pilosa.NodeRemoval
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, errors.Wrap(err, "json decode")
+	}
+	return &r, nil
+}
+
+func (c *InternalClient) doFieldCopy(ctx context.Context, req *http.Request) (*pilosa.FieldCopy, error) {
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var fc pilosa.FieldCopy
+	if err := json.NewDecoder(resp.Body).Decode(&fc); err != nil {
+		return nil, errors.Wrap(err, "json decode")
+	}
+	return &fc, nil
+}
+
 // SendMessage posts a message synchronously.
 func (c *InternalClient) SendMessage(ctx context.Context, uri *pilosa.URI, msg []byte) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.SendMessage")
@@ -1023,15 +1975,11 @@ func (c *InternalClient) executeRequest(req *http.Request) (*http.Response, erro
 		if err != nil {
 			return resp, errors.Wrapf(err, "bad status '%s' and err reading body", resp.Status)
 		}
-		var msg string
-		// try to decode a JSON response
-		var sr successResponse
-		if err = json.Unmarshal(buf, &sr); err == nil {
-			msg = sr.Error.Error()
-		} else {
-			msg = string(buf)
+		var serializer pilosa.Serializer
+		if req.Header.Get("Accept") == "application/x-protobuf" {
+			serializer = c.serializer
 		}
-		return resp, errors.Errorf("server error %s: '%s'", resp.Status, msg)
+		return resp, errorFromResponse(resp.Status, buf, serializer)
 	}
 	return resp, nil
 }
@@ -1129,11 +2077,12 @@ func (p Bits) Timestamps() []int64 {
 	return other
 }
 
-// GroupByShard returns a map of bits by shard.
-func (p Bits) GroupByShard() map[uint64][]pilosa.Bit {
+// GroupByShard returns a map of bits by shard, for an index with the given
+// shard width.
+func (p Bits) GroupByShard(shardWidth uint64) map[uint64][]pilosa.Bit {
 	m := make(map[uint64][]pilosa.Bit)
 	for _, bit := range p {
-		shard := bit.ColumnID / pilosa.ShardWidth
+		shard := bit.ColumnID / shardWidth
 		m[shard] = append(m[shard], bit)
 	}
 
@@ -1198,11 +2147,12 @@ func (p FieldValues) Values() []int64 {
 	return other
 }
 
-// GroupByShard returns a map of field values by shard.
-func (p FieldValues) GroupByShard() map[uint64][]pilosa.FieldValue {
+// GroupByShard returns a map of field values by shard, for an index with the
+// given shard width.
+func (p FieldValues) GroupByShard(shardWidth uint64) map[uint64][]pilosa.FieldValue {
 	m := make(map[uint64][]pilosa.FieldValue)
 	for _, val := range p {
-		shard := val.ColumnID / pilosa.ShardWidth
+		shard := val.ColumnID / shardWidth
 		m[shard] = append(m[shard], val)
 	}
 
@@ -1214,22 +2164,19 @@ func (p FieldValues) GroupByShard() map[uint64][]pilosa.FieldValue {
 	return m
 }
 
-// BitsByPos is a slice of bits sorted row then column.
+// BitsByPos is a slice of bits sorted row then column, which is the order of
+// their positions within a shard of any width.
 type BitsByPos []pilosa.Bit
 
 func (p BitsByPos) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
 func (p BitsByPos) Len() int      { return len(p) }
 func (p BitsByPos) Less(i, j int) bool {
-	p0, p1 := pos(p[i].RowID, p[i].ColumnID), pos(p[j].RowID, p[j].ColumnID)
-	if p0 == p1 {
-		return p[i].Timestamp < p[j].Timestamp
+	if p[i].RowID != p[j].RowID {
+		return p[i].RowID < p[j].RowID
+	} else if p[i].ColumnID != p[j].ColumnID {
+		return p[i].ColumnID < p[j].ColumnID
 	}
-	return p0 < p1
-}
-
-// pos returns the row position of a row/column pair.
-func pos(rowID, columnID uint64) uint64 {
-	return (rowID * pilosa.ShardWidth) + (columnID % pilosa.ShardWidth)
+	return p[i].Timestamp < p[j].Timestamp
 }
 
 func uriPathToURL(uri *pilosa.URI, path string) url.URL {
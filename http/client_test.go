@@ -20,8 +20,10 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	gohttp "net/http"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +33,7 @@ import (
 	"github.com/pilosa/pilosa/pql"
 	"github.com/pilosa/pilosa/server"
 	"github.com/pilosa/pilosa/test"
+	"github.com/pkg/errors"
 )
 
 // Test distributed TopN Row count across 3 nodes.
@@ -347,12 +350,26 @@ func TestClient_Import(t *testing.T) {
 
 	// Send import request.
 	c := MustNewClient(host, http.GetHTTPClient(nil))
-	if err := c.Import(context.Background(), "i", "f", 0, []pilosa.Bit{
+	if count, err := c.Import(context.Background(), "i", "f", 0, []pilosa.Bit{
 		{RowID: 0, ColumnID: 1},
 		{RowID: 0, ColumnID: 5},
 		{RowID: 200, ColumnID: 6},
+		{RowID: 0, ColumnID: 5},
 	}); err != nil {
 		t.Fatal(err)
+	} else if count != (pilosa.ImportCount{Shard: 0, Bits: 3, Changed: 3}) {
+		t.Fatalf("unexpected import count: %+v", count)
+	}
+
+	// Importing the same bits again changes nothing.
+	if count, err := c.Import(context.Background(), "i", "f", 0, []pilosa.Bit{
+		{RowID: 0, ColumnID: 1},
+		{RowID: 0, ColumnID: 5},
+		{RowID: 200, ColumnID: 6},
+	}); err != nil {
+		t.Fatal(err)
+	} else if count != (pilosa.ImportCount{Shard: 0, Bits: 3, Changed: 0}) {
+		t.Fatalf("unexpected import count: %+v", count)
 	}
 
 	// Verify data.
@@ -364,11 +381,14 @@ func TestClient_Import(t *testing.T) {
 	}
 
 	// Clear some data.
-	if err := c.Import(context.Background(), "i", "f", 0, []pilosa.Bit{
+	if count, err := c.Import(context.Background(), "i", "f", 0, []pilosa.Bit{
 		{RowID: 0, ColumnID: 5},
 		{RowID: 200, ColumnID: 6},
+		{RowID: 200, ColumnID: 7},
 	}, pilosa.OptImportOptionsClear(true)); err != nil {
 		t.Fatal(err)
+	} else if count != (pilosa.ImportCount{Shard: 0, Bits: 3, Changed: 2}) {
+		t.Fatalf("unexpected import count: %+v", count)
 	}
 
 	// Verify data.
@@ -380,6 +400,51 @@ func TestClient_Import(t *testing.T) {
 	}
 }
 
+// Ensure client gzips imports only for servers which accept them.
+func TestClient_ImportCompressed(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	hldr := test.Holder{Holder: cmd.Server.Holder()}
+	hldr.SetBit("i", "f", 1, 0)
+
+	for _, accepts := range []bool{true, false} {
+		rt := &encodingRecorder{accepts: accepts}
+		c := MustNewClient(cmd.URL(), &gohttp.Client{Transport: rt})
+		c.SetImportCompression(true)
+		if _, err := c.Import(context.Background(), "i", "f", 0, []pilosa.Bit{
+			{RowID: 1, ColumnID: 3},
+			{RowID: 1, ColumnID: 4},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if exp := map[bool]string{true: "gzip", false: ""}[accepts]; rt.encoding != exp {
+			t.Fatalf("accepts=%v: expected encoding %q, got %q", accepts, exp, rt.encoding)
+		}
+	}
+
+	if a := hldr.Row("i", "f", 1).Columns(); !reflect.DeepEqual(a, []uint64{0, 3, 4}) {
+		t.Fatalf("unexpected columns: %+v", a)
+	}
+}
+
+// encodingRecorder records the encoding of import requests, and reports
+// servers not handling OPTIONS on the import endpoint when accepts is false.
+type encodingRecorder struct {
+	accepts  bool
+	encoding string
+}
+
+func (rt *encodingRecorder) RoundTrip(req *gohttp.Request) (*gohttp.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/import") {
+		return gohttp.DefaultTransport.RoundTrip(req)
+	} else if req.Method == "OPTIONS" && !rt.accepts {
+		return &gohttp.Response{StatusCode: gohttp.StatusMethodNotAllowed, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	if req.Method == "POST" {
+		rt.encoding = req.Header.Get("Content-Encoding")
+	}
+	return gohttp.DefaultTransport.RoundTrip(req)
+}
+
 // Ensure client can bulk import data.
 func TestClient_ImportRoaring(t *testing.T) {
 	cluster := test.MustNewCluster(t, 2)
@@ -519,7 +584,7 @@ func TestClient_ImportKeys(t *testing.T) {
 		c := MustNewClient(host, http.GetHTTPClient(nil))
 
 		t.Run("Import keyed,keyed", func(t *testing.T) {
-			if err := c.Import(context.Background(), "keyed", "keyedf", 0, []pilosa.Bit{
+			if _, err := c.Import(context.Background(), "keyed", "keyedf", 0, []pilosa.Bit{
 				{RowKey: "green", ColumnKey: "eve"},
 				{RowKey: "green", ColumnKey: "alice"},
 				{RowKey: "green", ColumnKey: "bob"},
@@ -546,7 +611,7 @@ func TestClient_ImportKeys(t *testing.T) {
 		})
 
 		t.Run("Import keyed,unkeyedf", func(t *testing.T) {
-			if err := c.Import(context.Background(), "keyed", "unkeyedf", 0, []pilosa.Bit{
+			if _, err := c.Import(context.Background(), "keyed", "unkeyedf", 0, []pilosa.Bit{
 				{RowID: 1, ColumnKey: "eve"},
 				{RowID: 1, ColumnKey: "alice"},
 				{RowID: 1, ColumnKey: "bob"},
@@ -573,7 +638,7 @@ func TestClient_ImportKeys(t *testing.T) {
 		})
 
 		t.Run("Import unkeyed,keyed", func(t *testing.T) {
-			if err := c.Import(context.Background(), "unkeyed", "keyedf", 0, []pilosa.Bit{
+			if _, err := c.Import(context.Background(), "unkeyed", "keyedf", 0, []pilosa.Bit{
 				{RowKey: "green", ColumnID: 1},
 				{RowKey: "green", ColumnID: 2},
 				{RowKey: "green", ColumnID: 3},
@@ -617,7 +682,7 @@ func TestClient_ImportKeys(t *testing.T) {
 
 		// Import to node0.
 		t.Run("Import node0", func(t *testing.T) {
-			if err := c0.ImportK(context.Background(), "keyed", "keyedf0", []pilosa.Bit{
+			if _, err := c0.ImportK(context.Background(), "keyed", "keyedf0", []pilosa.Bit{
 				{RowKey: "green", ColumnKey: "eve"},
 				{RowKey: "green", ColumnKey: "alice"},
 				{RowKey: "green", ColumnKey: "bob"},
@@ -645,15 +710,18 @@ func TestClient_ImportKeys(t *testing.T) {
 
 		// Import to node1 (ensure import is routed to coordinator for translation).
 		t.Run("Import node1", func(t *testing.T) {
-			if err := c1.ImportK(context.Background(), "keyed", "keyedf1", []pilosa.Bit{
+			if counts, err := c1.ImportK(context.Background(), "keyed", "keyedf1", []pilosa.Bit{
 				{RowKey: "green", ColumnKey: "eve"},
 				{RowKey: "green", ColumnKey: "alice"},
 				{RowKey: "green", ColumnKey: "bob"},
 				{RowKey: "blue", ColumnKey: "eve"},
 				{RowKey: "blue", ColumnKey: "alice"},
 				{RowKey: "purple", ColumnKey: "eve"},
+				{RowKey: "purple", ColumnKey: "eve"},
 			}); err != nil {
 				t.Fatal(err)
+			} else if !reflect.DeepEqual(counts, []pilosa.ImportCount{{Shard: 0, Bits: 6, Changed: 6}}) {
+				t.Fatalf("unexpected import counts: %+v", counts)
 			}
 
 			// Wait for translation replication.
@@ -883,7 +951,7 @@ func TestClient_ImportExistence(t *testing.T) {
 
 		// Send import request.
 		c := MustNewClient(host, http.GetHTTPClient(nil))
-		if err := c.Import(context.Background(), idxName, fldName, 0, []pilosa.Bit{
+		if _, err := c.Import(context.Background(), idxName, fldName, 0, []pilosa.Bit{
 			{RowID: 0, ColumnID: 1},
 			{RowID: 0, ColumnID: 5},
 			{RowID: 200, ColumnID: 6},
@@ -972,6 +1040,195 @@ func TestClient_FragmentBlocks(t *testing.T) {
 	}
 }
 
+// Ensure column attributes can be copied between nodes block by block.
+func TestClient_ColumnAttrBlocks(t *testing.T) {
+	src := test.MustRunCluster(t, 1)[0]
+	defer src.Close()
+	dst := test.MustRunCluster(t, 1)[0]
+	defer dst.Close()
+	src.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	dst.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+
+	store := src.Server.Holder().Index("i").ColumnAttrStore()
+	for id := uint64(0); id < 250; id += 10 {
+		if err := store.SetAttrs(id, map[string]interface{}{"n": int64(id), "f": 0.5, "s": "x", "b": true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := dst.Server.Holder().Index("i").ColumnAttrStore().SetAttrs(30, map[string]interface{}{"n": int64(-1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := MustNewClient(src.URL(), http.GetHTTPClient(nil))
+	srcURI, dstURI := &src.API.Node().URI, &dst.API.Node().URI
+
+	// Pages of a block are read in order.
+	if page, err := c.ColumnAttrBlockData(ctx, srcURI, "i", 0, 0, 4); err != nil {
+		t.Fatal(err)
+	} else if len(page.Attrs) != 4 || page.Next == nil || *page.Next != 40 {
+		t.Fatalf("unexpected page: %+v", page)
+	} else if page, err := c.ColumnAttrBlockData(ctx, srcURI, "i", 0, *page.Next, 4); err != nil {
+		t.Fatal(err)
+	} else if len(page.Attrs) != 4 || page.Next == nil || *page.Next != 80 {
+		t.Fatalf("unexpected page: %+v", page)
+	} else if _, err := c.ColumnAttrBlockData(ctx, srcURI, "i", 0, 0, pilosa.MaxAttrBlockPageLimit+1); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+
+	// Pull each differing block from the source and apply it.
+	srcBlocks, err := c.ColumnAttrBlocks(ctx, srcURI, "i")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(srcBlocks) != 3 {
+		t.Fatalf("unexpected blocks: %+v", srcBlocks)
+	}
+	dstBlocks, err := c.ColumnAttrBlocks(ctx, dstURI, "i")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksums := make(map[uint64][]byte)
+	for _, blk := range dstBlocks {
+		checksums[blk.ID] = blk.Checksum
+	}
+	for _, blk := range srcBlocks {
+		if bytes.Equal(checksums[blk.ID], blk.Checksum) {
+			continue
+		}
+		attrs, err := c.ColumnAttrBlock(ctx, srcURI, "i", blk.ID)
+		if err != nil {
+			t.Fatal(err)
+		} else if err := c.SetColumnAttrBlockData(ctx, dstURI, "i", attrs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if dstBlocks, err := c.ColumnAttrBlocks(ctx, dstURI, "i"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(dstBlocks, srcBlocks) {
+		t.Fatalf("blocks differ after sync:\nsrc=%s\ndst=%s", spew.Sdump(srcBlocks), spew.Sdump(dstBlocks))
+	}
+	if m, err := dst.Server.Holder().Index("i").ColumnAttrStore().Attrs(30); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[string]interface{}{"n": int64(30), "f": 0.5, "s": "x", "b": true}) {
+		t.Fatalf("unexpected attrs: %#v", m)
+	}
+}
+
+// Ensure the attributes of many rows can be read at once, keeping the types
+// of their values.
+func TestClient_RowAttrs(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	defer cmd.Close()
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	f := cmd.MustCreateField(t, "i", "f")
+	if err := f.RowAttrStore().SetBulkAttrs(map[uint64]map[string]interface{}{
+		1:   {"n": int64(1), "f": 0.5},
+		250: {"s": "x", "b": true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := MustNewClient(cmd.URL(), http.GetHTTPClient(nil))
+	if m, err := c.RowAttrs(ctx, nil, "i", "f", []uint64{250, 2, 1}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(m, map[uint64]map[string]interface{}{
+		1:   {"n": int64(1), "f": 0.5},
+		2:   {},
+		250: {"s": "x", "b": true},
+	}) {
+		t.Fatalf("unexpected attrs: %#v", m)
+	}
+
+	if _, err := c.RowAttrs(ctx, nil, "i", "nope", []uint64{1}); err != pilosa.ErrFieldNotFound {
+		t.Fatalf("expected %v, got %v", pilosa.ErrFieldNotFound, err)
+	} else if _, err := c.RowAttrs(ctx, nil, "i", "f", make([]uint64, pilosa.MaxBulkRowAttrIDs+1)); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+}
+
+// Ensure the codes of errors returned by the server are translated back into
+// their errors.
+func TestClient_ErrorCodes(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	defer cmd.Close()
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+
+	ctx := context.Background()
+	c := MustNewClient(cmd.URL(), http.GetHTTPClient(nil))
+	if _, err := c.Query(ctx, "i", &pilosa.QueryRequest{Query: "Row(f=1)"}); errors.Cause(err) != pilosa.ErrFieldNotFound {
+		t.Fatalf("expected %v, got %v", pilosa.ErrFieldNotFound, err)
+	} else if !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected status in error: %v", err)
+	}
+	if _, err := c.Query(ctx, "nope", &pilosa.QueryRequest{Query: "Row(f=1)"}); errors.Cause(err) != pilosa.ErrIndexNotFound {
+		t.Fatalf("expected %v, got %v", pilosa.ErrIndexNotFound, err)
+	}
+	if _, err := c.Query(ctx, "i", &pilosa.QueryRequest{Query: "Row("}); errors.Cause(err) != pilosa.ErrQueryParse {
+		t.Fatalf("expected %v, got %v", pilosa.ErrQueryParse, err)
+	}
+	if err := c.DeleteColumnRange(ctx, "nope", 0, 1); errors.Cause(err) != pilosa.ErrIndexNotFound {
+		t.Fatalf("expected %v, got %v", pilosa.ErrIndexNotFound, err)
+	}
+}
+
+// Ensure a schema read from one node can be applied to another.
+func TestClient_ApplySchema(t *testing.T) {
+	src := test.MustRunCluster(t, 1)[0]
+	defer src.Close()
+	src.MustCreateIndex(t, "i", pilosa.IndexOptions{Keys: true})
+	src.MustCreateField(t, "i", "f", pilosa.OptFieldKeys())
+	src.MustCreateField(t, "i", "t", pilosa.OptFieldTypeTime(pilosa.TimeQuantum("YMD")))
+	if _, err := src.Query("i", "", `Set("a", t=1, 2018-01-02T03:04)`); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := MustNewClient(src.URL(), http.GetHTTPClient(nil))
+	schema, err := c.Schema(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(schema) != 1 || len(schema[0].Fields) != 2 || len(schema[0].Fields[1].Views) != 4 {
+		t.Fatalf("unexpected schema: %s", spew.Sdump(schema))
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		dst := test.MustRunCluster(t, 1)[0]
+		defer dst.Close()
+		dstURI := &dst.API.Node().URI
+
+		// Applying twice is the same as applying once.
+		for i := 0; i < 2; i++ {
+			if err := c.ApplySchema(ctx, dstURI, schema); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if diff := dst.Server.Holder().DiffSchema(src.API.CanonicalSchema(ctx).Indexes); diff.Len() != 0 {
+			t.Fatalf("unexpected differences: %v", diff.Lines())
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		dst := test.MustRunCluster(t, 1)[0]
+		defer dst.Close()
+		dst.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+		dst.MustCreateField(t, "i", "t", pilosa.OptFieldTypeTime(pilosa.TimeQuantum("YM")))
+
+		err := c.ApplySchema(ctx, &dst.API.Node().URI, schema)
+		if err == nil || !strings.Contains(err.Error(), "409") || !strings.Contains(err.Error(), "i: local=") || !strings.Contains(err.Error(), "i/t: local=") {
+			t.Fatalf("expected conflicts, got %v", err)
+		}
+
+		// Everything else is created.
+		if f := dst.Server.Holder().Field("i", "f"); f == nil || !f.Options().Keys {
+			t.Fatalf("unexpected field: %+v", f)
+		} else if q := dst.Server.Holder().Field("i", "t").TimeQuantum(); q != "YM" {
+			t.Fatalf("unexpected time quantum: %s", q)
+		}
+	})
+}
+
 // Client represents a test wrapper for pilosa.Client.
 type Client struct {
 	*http.InternalClient
@@ -995,3 +1252,105 @@ func makeImportRoaringRequest(clear bool, viewData string) *pilosa.ImportRoaring
 		},
 	}
 }
+
+// Ensure the client can list the views of a field, sorted by name.
+func TestClient_FieldViews(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	defer cmd.Close()
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("YM"))
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: `Set(1, f=1, 2018-03-04T00:00)`})
+
+	ctx := context.Background()
+	c := MustNewClient(cmd.URL(), http.GetHTTPClient(nil))
+	views, err := c.FieldViews(ctx, nil, "i", "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, v := range views {
+		names = append(names, v.Name)
+	}
+	if exp := []string{"standard", "standard_2018", "standard_201803"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("expected views %v, got %v", exp, names)
+	}
+
+	if _, err := c.FieldViews(ctx, &cmd.API.Node().URI, "i", "g"); err != pilosa.ErrFieldNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure the columns of a row can be read as a stream.
+func TestClient_QueryStream(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1)[0]
+	defer cmd.Close()
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f")
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf(`Set(1, f=1) Set(%d, f=1)`, pilosa.ShardWidth+2)})
+
+	ctx := context.Background()
+	c := MustNewClient(cmd.URL(), http.GetHTTPClient(nil))
+	body, err := c.QueryStream(ctx, "i", &pilosa.QueryRequest{Query: "Row(f=1)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := fmt.Sprintf("{\"columns\":[1]}\n{\"columns\":[%d]}\n{\"count\":2}\n", pilosa.ShardWidth+2); string(buf) != exp {
+		t.Fatalf("unexpected body: %s", buf)
+	}
+
+	if _, err := c.QueryStream(ctx, "i", &pilosa.QueryRequest{Query: "Row(g=1)"}); errors.Cause(err) != pilosa.ErrFieldNotFound {
+		t.Fatalf("expected %v, got %v", pilosa.ErrFieldNotFound, err)
+	}
+}
+
+// Ensure nodes holding the same bits report the same fragment checksums,
+// whatever order the bits were written in.
+func TestClient_FragmentChecksums(t *testing.T) {
+	c0 := test.MustRunCluster(t, 1)[0]
+	defer c0.Close()
+	c1 := test.MustRunCluster(t, 1)[0]
+	defer c1.Close()
+
+	cols := []uint64{1, 2, pilosa.ShardWidth + 3, 3*pilosa.ShardWidth + 4}
+	for i, m := range []*test.Command{c0, c1} {
+		m.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+		f := m.MustCreateField(t, "i", "f")
+		for j := range cols {
+			col := cols[j]
+			if i == 1 {
+				col = cols[len(cols)-1-j]
+			}
+			if _, err := f.SetBit(col%7, col, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	c := MustNewClient(c0.URL(), http.GetHTTPClient(nil))
+	sums0, err := c.FragmentChecksums(ctx, &c0.API.Node().URI, "i", "f", "standard")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sums0) != 3 || sums0[0].Shard != 0 || sums0[1].Shard != 1 || sums0[2].Shard != 3 {
+		t.Fatalf("unexpected checksums: %+v", sums0)
+	}
+	sums1, err := c.FragmentChecksums(ctx, &c1.API.Node().URI, "i", "f", "standard")
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(sums0, sums1) {
+		t.Fatalf("checksums differ: %+v != %+v", sums0, sums1)
+	}
+
+	// A missing view has no checksums, and a missing field is an error.
+	if sums, err := c.FragmentChecksums(ctx, nil, "i", "f", "standard_2018"); err != nil {
+		t.Fatal(err)
+	} else if len(sums) != 0 {
+		t.Fatalf("unexpected checksums: %+v", sums)
+	} else if _, err := c.FragmentChecksums(ctx, nil, "i", "g", "standard"); err != pilosa.ErrFieldNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
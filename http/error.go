@@ -14,13 +14,81 @@
 
 package http
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pkg/errors"
+)
+
 // Error defines a standard application error.
 type Error struct {
 	// Human-readable message.
 	Message string `json:"message"`
+
+	// Code identifying the error, as returned by pilosa.ErrorCode.
+	Code string `json:"code,omitempty"`
 }
 
 // Error returns the string representation of the error message.
 func (e *Error) Error() string {
 	return e.Message
 }
+
+// errorCodeStatuses are the HTTP statuses of errors with codes.
+var errorCodeStatuses = map[string]int{
+	"index-not-found":    http.StatusNotFound,
+	"index-exists":       http.StatusConflict,
+	"field-not-found":    http.StatusNotFound,
+	"field-exists":       http.StatusConflict,
+	"fragment-not-found": http.StatusNotFound,
+	"query-parse":        http.StatusBadRequest,
+	"query-timeout":      http.StatusGatewayTimeout,
+	"read-only":          http.StatusForbidden,
+	"too-many-writes":    http.StatusRequestEntityTooLarge,
+}
+
+// errorStatus returns the HTTP status of err: that of its code if it has
+// one, or else that of the type wrapping it, or else def.
+func errorStatus(err error, def int) int {
+	if status, ok := errorCodeStatuses[pilosa.ErrorCode(err)]; ok {
+		return status
+	}
+	switch errors.Cause(err).(type) {
+	case pilosa.BadRequestError:
+		return http.StatusBadRequest
+	case pilosa.ConflictError:
+		return http.StatusConflict
+	case pilosa.NotFoundError:
+		return http.StatusNotFound
+	case pilosa.ForbiddenError:
+		return http.StatusForbidden
+	}
+	return def
+}
+
+// errorFromResponse returns the error of an unsuccessful response with the
+// given status and body. Bodies which aren't JSON are decoded as protobuf
+// query responses if serializer is set. The cause of an error with a code is
+// the pilosa error identified by it.
+func errorFromResponse(status string, body []byte, serializer pilosa.Serializer) error {
+	var msg, code string
+	var sr successResponse
+	var qr struct {
+		Err  string `json:"error"`
+		Code string `json:"code"`
+	}
+	var qresp pilosa.QueryResponse
+	if err := json.Unmarshal(body, &sr); err == nil && sr.Error != nil {
+		msg, code = sr.Error.Message, sr.Error.Code
+	} else if err := json.Unmarshal(body, &qr); err == nil && qr.Err != "" {
+		msg, code = qr.Err, qr.Code
+	} else if serializer != nil && serializer.Unmarshal(body, &qresp) == nil && qresp.Err != nil {
+		msg, code = qresp.Err.Error(), pilosa.ErrorCode(qresp.Err)
+	} else {
+		msg = string(body)
+	}
+	return pilosa.ErrorWithCode(code, fmt.Sprintf("server error %s: '%s'", status, msg))
+}
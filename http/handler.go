@@ -15,6 +15,7 @@
 package http
 
 import (
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -22,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net"
 	"net/http"
 	_ "net/http/pprof" // Imported for its side-effect of registering pprof endpoints with the server.
@@ -55,6 +57,9 @@ type Handler struct {
 
 	closeTimeout time.Duration
 
+	// Limit on the decompressed size of a gzip-encoded import body.
+	maxDecompressedImportSize int64
+
 	server *http.Server
 }
 
@@ -118,11 +123,28 @@ func OptHandlerCloseTimeout(d time.Duration) handlerOption {
 	}
 }
 
+// OptHandlerMaxDecompressedImportSize limits the size in bytes of a
+// gzip-encoded import body once decompressed. Zero means no limit.
+func OptHandlerMaxDecompressedImportSize(n int64) handlerOption {
+	return func(h *Handler) error {
+		if n < 0 {
+			return errors.New("max decompressed import size must not be negative")
+		}
+		h.maxDecompressedImportSize = n
+		return nil
+	}
+}
+
+// DefaultMaxDecompressedImportSize is the default limit on the decompressed
+// size of a gzip-encoded import body.
+const DefaultMaxDecompressedImportSize = 1 << 30
+
 // NewHandler returns a new instance of Handler with a default logger.
 func NewHandler(opts ...handlerOption) (*Handler, error) {
 	handler := &Handler{
-		logger:       logger.NopLogger,
-		closeTimeout: time.Second * 30,
+		logger:                    logger.NopLogger,
+		closeTimeout:              time.Second * 30,
+		maxDecompressedImportSize: DefaultMaxDecompressedImportSize,
 	}
 	handler.Handler = newRouter(handler)
 	handler.populateValidators()
@@ -150,7 +172,7 @@ func NewHandler(opts ...handlerOption) (*Handler, error) {
 func (h *Handler) Serve() error {
 	err := h.server.Serve(h.ln)
 	if err != nil && err.Error() != "http: Server closed" {
-		h.logger.Printf("HTTP handler terminated with error: %s\n", err)
+		h.logger.Errorf("HTTP handler terminated with error: %s\n", err)
 		return errors.Wrap(err, "serve http")
 	}
 	return nil
@@ -171,32 +193,62 @@ func (h *Handler) Close() error {
 func (h *Handler) populateValidators() {
 	h.validators = map[string]*queryValidationSpec{}
 	h.validators["Home"] = queryValidationSpecRequired()
+	h.validators["GetClusterRemoveNode"] = queryValidationSpecRequired()
+	h.validators["PostClusterRemoveNode"] = queryValidationSpecRequired()
 	h.validators["PostClusterResizeAbort"] = queryValidationSpecRequired()
 	h.validators["PostClusterResizeRemoveNode"] = queryValidationSpecRequired()
 	h.validators["PostClusterResizeSetCoordinator"] = queryValidationSpecRequired()
-	h.validators["GetExport"] = queryValidationSpecRequired("index", "field", "shard")
+	h.validators["GetDebugHolder"] = queryValidationSpecRequired().Optional("index", "field")
+	h.validators["GetExport"] = queryValidationSpecRequired("index", "field", "shard").Optional("format", "chunk", "minBitmapID", "maxBitmapID", "bitmapIDs", "view")
 	h.validators["GetIndexes"] = queryValidationSpecRequired()
 	h.validators["GetIndex"] = queryValidationSpecRequired()
 	h.validators["PostIndex"] = queryValidationSpecRequired()
+	h.validators["PatchIndex"] = queryValidationSpecRequired()
 	h.validators["DeleteIndex"] = queryValidationSpecRequired()
+	h.validators["PostIndexRename"] = queryValidationSpecRequired()
+	h.validators["PostIndexDeleteColumns"] = queryValidationSpecRequired()
+	h.validators["GetIndexMaxIDs"] = queryValidationSpecRequired()
 	h.validators["PostField"] = queryValidationSpecRequired()
 	h.validators["DeleteField"] = queryValidationSpecRequired()
-	h.validators["PostImport"] = queryValidationSpecRequired().Optional("clear", "ignoreKeyCheck")
+	h.validators["PatchField"] = queryValidationSpecRequired()
+	h.validators["PostFieldRename"] = queryValidationSpecRequired()
+	h.validators["GetTimeMigration"] = queryValidationSpecRequired()
+	h.validators["GetViews"] = queryValidationSpecRequired().Optional("from", "to")
+	h.validators["DeleteView"] = queryValidationSpecRequired()
+	h.validators["PostTimeMigration"] = queryValidationSpecRequired()
+	h.validators["GetFieldCopy"] = queryValidationSpecRequired()
+	h.validators["PostFieldCopy"] = queryValidationSpecRequired()
+	h.validators["PostFieldCopyReady"] = queryValidationSpecRequired()
+	h.validators["GetFieldCopyByID"] = queryValidationSpecRequired()
+	h.validators["GetFieldCache"] = queryValidationSpecRequired().Optional("shard")
+	h.validators["DeleteFieldCache"] = queryValidationSpecRequired().Optional("shard")
+	h.validators["OptionsImport"] = queryValidationSpecRequired()
+	h.validators["PostImport"] = queryValidationSpecRequired().Optional("clear", "ignoreKeyCheck", "bitmapField", "profileField", "timeField")
 	h.validators["PostImportRoaring"] = queryValidationSpecRequired().Optional("remote", "clear")
-	h.validators["PostQuery"] = queryValidationSpecRequired().Optional("shards", "columnAttrs", "excludeRowAttrs", "excludeColumns")
+	h.validators["PostQuery"] = queryValidationSpecRequired().Optional("shards", "columnAttrs", "excludeRowAttrs", "excludeColumns", "labels", "stream", "profile")
 	h.validators["GetInfo"] = queryValidationSpecRequired()
-	h.validators["RecalculateCaches"] = queryValidationSpecRequired()
-	h.validators["GetSchema"] = queryValidationSpecRequired()
+	h.validators["RecalculateCaches"] = queryValidationSpecRequired().Optional("rebuild")
+	h.validators["GetExpiredViews"] = queryValidationSpecRequired()
+	h.validators["GetSchema"] = queryValidationSpecRequired().Optional("verbose", "views")
+	h.validators["PostSchemaApply"] = queryValidationSpecRequired()
 	h.validators["GetStatus"] = queryValidationSpecRequired()
 	h.validators["GetVersion"] = queryValidationSpecRequired()
 	h.validators["PostClusterMessage"] = queryValidationSpecRequired()
 	h.validators["GetFragmentBlockData"] = queryValidationSpecRequired()
 	h.validators["GetFragmentBlocks"] = queryValidationSpecRequired("index", "field", "view", "shard")
+	h.validators["GetFragmentChecksums"] = queryValidationSpecRequired("index", "field", "view")
 	h.validators["GetFragmentData"] = queryValidationSpecRequired("index", "field", "view", "shard")
+	h.validators["DeleteFragmentData"] = queryValidationSpecRequired("index", "field", "view", "shard")
+	h.validators["PostFragmentCompact"] = queryValidationSpecRequired("index", "field", "view").Optional("shard")
 	h.validators["GetFragmentNodes"] = queryValidationSpecRequired("shard", "index")
+	h.validators["GetIndexAttrBlocks"] = queryValidationSpecRequired()
+	h.validators["PostIndexAttrBlockData"] = queryValidationSpecRequired()
+	h.validators["GetIndexAttrBlockData"] = queryValidationSpecRequired().Optional("start", "limit")
 	h.validators["PostIndexAttrDiff"] = queryValidationSpecRequired()
+	h.validators["PostColumnAttrSearch"] = queryValidationSpecRequired()
 	h.validators["PostFieldAttrDiff"] = queryValidationSpecRequired()
 	h.validators["GetNodes"] = queryValidationSpecRequired()
+	h.validators["GetInternalSchema"] = queryValidationSpecRequired()
 	h.validators["GetShardMax"] = queryValidationSpecRequired()
 	h.validators["GetTranslateData"] = queryValidationSpecRequired("offset")
 	h.validators["PostTranslateKeys"] = queryValidationSpecRequired()
@@ -236,25 +288,49 @@ func (h *Handler) extractTracing(next http.Handler) http.Handler {
 func newRouter(handler *Handler) *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc("/", handler.handleHome).Methods("GET").Name("Home")
+	router.HandleFunc("/cluster/remove-node", handler.handleGetClusterRemoveNode).Methods("GET").Name("GetClusterRemoveNode")
+	router.HandleFunc("/cluster/remove-node", handler.handlePostClusterRemoveNode).Methods("POST").Name("PostClusterRemoveNode")
 	router.HandleFunc("/cluster/resize/abort", handler.handlePostClusterResizeAbort).Methods("POST").Name("PostClusterResizeAbort")
 	router.HandleFunc("/cluster/resize/remove-node", handler.handlePostClusterResizeRemoveNode).Methods("POST").Name("PostClusterResizeRemoveNode")
 	router.HandleFunc("/cluster/resize/set-coordinator", handler.handlePostClusterResizeSetCoordinator).Methods("POST").Name("PostClusterResizeSetCoordinator")
+	router.HandleFunc("/debug/holder", handler.handleGetDebugHolder).Methods("GET").Name("GetDebugHolder")
 	router.PathPrefix("/debug/pprof/").Handler(http.DefaultServeMux).Methods("GET")
 	router.Handle("/debug/vars", expvar.Handler()).Methods("GET")
 	router.HandleFunc("/export", handler.handleGetExport).Methods("GET").Name("GetExport")
+	router.HandleFunc("/field-copy/{id}", handler.handleGetFieldCopyByID).Methods("GET").Name("GetFieldCopyByID")
 	router.HandleFunc("/index", handler.handleGetIndexes).Methods("GET").Name("GetIndexes")
 	router.HandleFunc("/index/{index}", handler.handleGetIndex).Methods("GET").Name("GetIndex")
 	router.HandleFunc("/index/{index}", handler.handlePostIndex).Methods("POST").Name("PostIndex")
 	router.HandleFunc("/index/{index}", handler.handleDeleteIndex).Methods("DELETE").Name("DeleteIndex")
+	router.HandleFunc("/index/{index}", handler.handlePatchIndex).Methods("PATCH").Name("PatchIndex")
+	router.HandleFunc("/index/{index}/rename", handler.handlePostIndexRename).Methods("POST").Name("PostIndexRename")
+	router.HandleFunc("/index/{index}/delete-columns", handler.handlePostIndexDeleteColumns).Methods("POST").Name("PostIndexDeleteColumns")
 	//router.HandleFunc("/index/{index}/field", handler.handleGetFields).Methods("GET") // Not implemented.
 	router.HandleFunc("/index/{index}/field/{field}", handler.handlePostField).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field/{field}", handler.handleDeleteField).Methods("DELETE").Name("DeleteField")
+	router.HandleFunc("/index/{index}/field/{field}", handler.handlePatchField).Methods("PATCH").Name("PatchField")
+	router.HandleFunc("/index/{index}/field/{field}/attr/bulk", handler.handlePostRowAttrsBulk).Methods("POST").Name("PostRowAttrsBulk")
+	router.HandleFunc("/index/{index}/field/{field}/cache", handler.handleGetFieldCache).Methods("GET").Name("GetFieldCache")
+	router.HandleFunc("/index/{index}/field/{field}/cache", handler.handleDeleteFieldCache).Methods("DELETE").Name("DeleteFieldCache")
 	router.HandleFunc("/index/{index}/field/{field}/import", handler.handlePostImport).Methods("POST").Name("PostImport")
+	router.HandleFunc("/index/{index}/field/{field}/import", handler.handleOptionsImport).Methods("OPTIONS").Name("OptionsImport")
 	router.HandleFunc("/index/{index}/field/{field}/import-roaring/{shard}", handler.handlePostImportRoaring).Methods("POST").Name("PostImportRoaring")
+	router.HandleFunc("/index/{index}/field/{field}/rename", handler.handlePostFieldRename).Methods("POST").Name("PostFieldRename")
+	router.HandleFunc("/index/{index}/field/{field}/time-migration", handler.handleGetTimeMigration).Methods("GET").Name("GetTimeMigration")
+	router.HandleFunc("/index/{index}/field/{field}/time-migration", handler.handlePostTimeMigration).Methods("POST").Name("PostTimeMigration")
+	router.HandleFunc("/index/{index}/field/{field}/copy", handler.handleGetFieldCopy).Methods("GET").Name("GetFieldCopy")
+	router.HandleFunc("/index/{index}/field/{field}/copy", handler.handlePostFieldCopy).Methods("POST").Name("PostFieldCopy")
+	router.HandleFunc("/index/{index}/field/{field}/copy/ready", handler.handlePostFieldCopyReady).Methods("POST").Name("PostFieldCopyReady")
+	router.HandleFunc("/index/{index}/field/{field}/views", handler.handleGetViews).Methods("GET").Name("GetViews")
+	router.HandleFunc("/index/{index}/field/{field}/view/{view}", handler.handleDeleteView).Methods("DELETE").Name("DeleteView")
+	router.HandleFunc("/index/{index}/column-attrs/search", handler.handlePostColumnAttrSearch).Methods("POST").Name("PostColumnAttrSearch")
+	router.HandleFunc("/index/{index}/ids/max", handler.handleGetIndexMaxIDs).Methods("GET").Name("GetIndexMaxIDs")
 	router.HandleFunc("/index/{index}/query", handler.handlePostQuery).Methods("POST").Name("PostQuery")
 	router.HandleFunc("/info", handler.handleGetInfo).Methods("GET").Name("GetInfo")
 	router.HandleFunc("/recalculate-caches", handler.handleRecalculateCaches).Methods("POST").Name("RecalculateCaches")
+	router.HandleFunc("/retention/expired", handler.handleGetExpiredViews).Methods("GET").Name("GetExpiredViews")
 	router.HandleFunc("/schema", handler.handleGetSchema).Methods("GET").Name("GetSchema")
+	router.HandleFunc("/schema/apply", handler.handlePostSchemaApply).Methods("POST").Name("PostSchemaApply")
 	router.HandleFunc("/status", handler.handleGetStatus).Methods("GET").Name("GetStatus")
 	router.HandleFunc("/version", handler.handleGetVersion).Methods("GET").Name("GetVersion")
 
@@ -263,12 +339,19 @@ func newRouter(handler *Handler) *mux.Router {
 	router.HandleFunc("/internal/cluster/message", handler.handlePostClusterMessage).Methods("POST").Name("PostClusterMessage")
 	router.HandleFunc("/internal/fragment/block/data", handler.handleGetFragmentBlockData).Methods("GET").Name("GetFragmentBlockData")
 	router.HandleFunc("/internal/fragment/blocks", handler.handleGetFragmentBlocks).Methods("GET").Name("GetFragmentBlocks")
+	router.HandleFunc("/internal/fragment/compact", handler.handlePostFragmentCompact).Methods("POST").Name("PostFragmentCompact")
+	router.HandleFunc("/internal/fragment/checksums", handler.handleGetFragmentChecksums).Methods("GET").Name("GetFragmentChecksums")
 	router.HandleFunc("/internal/fragment/data", handler.handleGetFragmentData).Methods("GET").Name("GetFragmentData")
+	router.HandleFunc("/internal/fragment/data", handler.handleDeleteFragmentData).Methods("DELETE").Name("DeleteFragmentData")
 	router.HandleFunc("/internal/fragment/nodes", handler.handleGetFragmentNodes).Methods("GET").Name("GetFragmentNodes")
+	router.HandleFunc("/internal/index/{index}/attr/blocks", handler.handleGetIndexAttrBlocks).Methods("GET").Name("GetIndexAttrBlocks")
+	router.HandleFunc("/internal/index/{index}/attr/blocks", handler.handlePostIndexAttrBlockData).Methods("POST").Name("PostIndexAttrBlockData")
+	router.HandleFunc("/internal/index/{index}/attr/blocks/{block}", handler.handleGetIndexAttrBlockData).Methods("GET").Name("GetIndexAttrBlockData")
 	router.HandleFunc("/internal/index/{index}/attr/diff", handler.handlePostIndexAttrDiff).Methods("POST").Name("PostIndexAttrDiff")
 	router.HandleFunc("/internal/index/{index}/field/{field}/attr/diff", handler.handlePostFieldAttrDiff).Methods("POST").Name("PostFieldAttrDiff")
 	router.HandleFunc("/internal/index/{index}/field/{field}/remote-available-shards/{shardID}", handler.handleDeleteRemoteAvailableShard).Methods("DELETE")
 	router.HandleFunc("/internal/nodes", handler.handleGetNodes).Methods("GET").Name("GetNodes")
+	router.HandleFunc("/internal/schema", handler.handleGetInternalSchema).Methods("GET").Name("GetInternalSchema")
 	router.HandleFunc("/internal/shards/max", handler.handleGetShardsMax).Methods("GET").Name("GetShardsMax") // TODO: deprecate, but it's being used by the client
 	router.HandleFunc("/internal/translate/data", handler.handleGetTranslateData).Methods("GET").Name("GetTranslateData")
 	router.HandleFunc("/internal/translate/keys", handler.handlePostTranslateKeys).Methods("POST").Name("PostTranslateKeys")
@@ -285,7 +368,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			stack := debug.Stack()
 			msg := "PANIC: %s\n%s"
-			h.logger.Printf(msg, err, stack)
+			h.logger.Errorf(msg, err, stack)
 			fmt.Fprintf(w, msg, err, stack)
 		}
 	}()
@@ -332,24 +415,10 @@ func (r *successResponse) check(err error) (statusCode int) {
 		return 0
 	}
 
-	cause := errors.Cause(err)
-
-	// Determine HTTP status code based on the error type.
-	switch cause.(type) {
-	case pilosa.BadRequestError:
-		statusCode = http.StatusBadRequest
-	case pilosa.ConflictError:
-		statusCode = http.StatusConflict
-	case pilosa.NotFoundError:
-		statusCode = http.StatusNotFound
-	default:
-		statusCode = http.StatusInternalServerError
-	}
-
 	r.Success = false
-	r.Error = &Error{Message: cause.Error()}
+	r.Error = &Error{Message: errors.Cause(err).Error(), Code: pilosa.ErrorCode(err)}
 
-	return statusCode
+	return errorStatus(err, http.StatusInternalServerError)
 }
 
 // write sends a response to the http.ResponseWriter based on the success
@@ -400,9 +469,115 @@ func (h *Handler) handleGetSchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schema := h.api.Schema(r.Context())
+	etag := h.api.SchemaETag()
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var schema []*pilosa.IndexInfo
+	if verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose")); verbose {
+		schema = h.api.SchemaVerbose(r.Context())
+	} else if views, _ := strconv.ParseBool(r.URL.Query().Get("views")); views {
+		schema = h.api.SchemaViews(r.Context())
+	} else {
+		schema = h.api.Schema(r.Context())
+	}
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{"indexes": schema}); err != nil {
-		h.logger.Printf("write schema response error: %s", err)
+		h.logger.Errorf("write schema response error: %s", err)
+	}
+}
+
+// handlePostSchemaApply handles POST /schema/apply requests. It creates the
+// indexes, fields and views of a schema in the form GET /schema returns
+// which don't exist on this node.
+func (h *Handler) handlePostSchemaApply(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	resp := successResponse{}
+
+	var req getSchemaResponse
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.Wrap(err, "decoding schema")))
+		return
+	}
+
+	err := h.api.ApplySchema(r.Context(), req.Indexes)
+	resp.write(w, err)
+}
+
+// handleGetExpiredViews handles GET /retention/expired requests. It lists
+// the views which the next retention pass would delete without deleting them.
+func (h *Handler) handleGetExpiredViews(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	views, err := h.api.ExpiredViews(r.Context())
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"views": views}); err != nil {
+		h.logger.Errorf("write expired views response error: %s", err)
+	}
+}
+
+// handleGetDebugHolder handles GET /debug/holder requests. It streams the
+// statistics of each view and fragment on this node, followed by their
+// totals, as they are walked.
+func (h *Handler) handleGetDebugHolder(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	q := r.URL.Query()
+
+	enc := json.NewEncoder(w)
+	n := 0
+	totals, err := h.api.HolderStats(r.Context(), q.Get("index"), q.Get("field"), func(vs *pilosa.ViewStats) error {
+		prefix := ","
+		if n == 0 {
+			w.Header().Set("Content-Type", "application/json")
+			prefix = `{"views":[`
+		}
+		n++
+		if _, err := io.WriteString(w, prefix); err != nil {
+			return err
+		}
+		return enc.Encode(vs)
+	})
+	if err != nil {
+		// Once streaming has begun the status can't be changed, so the
+		// client sees a truncated document.
+		if n > 0 {
+			h.logger.Errorf("write holder stats response error: %s", err)
+			return
+		}
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+
+	if n == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := io.WriteString(w, `{"views":[`); err != nil {
+			h.logger.Errorf("write holder stats response error: %s", err)
+			return
+		}
+	}
+	if _, err := io.WriteString(w, `],"totals":`); err != nil {
+		h.logger.Errorf("write holder stats response error: %s", err)
+	} else if err := enc.Encode(totals); err != nil {
+		h.logger.Errorf("write holder stats response error: %s", err)
+	} else if _, err := io.WriteString(w, "}\n"); err != nil {
+		h.logger.Errorf("write holder stats response error: %s", err)
 	}
 }
 
@@ -413,12 +588,14 @@ func (h *Handler) handleGetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	status := getStatusResponse{
-		State:   h.api.State(),
-		Nodes:   h.api.Hosts(r.Context()),
-		LocalID: h.api.Node().ID,
+		State:    h.api.State(),
+		Nodes:    h.api.Hosts(r.Context()),
+		LocalID:  h.api.Node().ID,
+		Limits:   h.api.SchemaLimits(),
+		ReadOnly: h.api.ReadOnly(),
 	}
 	if err := json.NewEncoder(w).Encode(status); err != nil {
-		h.logger.Printf("write status response error: %s", err)
+		h.logger.Errorf("write status response error: %s", err)
 	}
 }
 
@@ -429,7 +606,7 @@ func (h *Handler) handleGetInfo(w http.ResponseWriter, r *http.Request) {
 	}
 	info := h.api.Info()
 	if err := json.NewEncoder(w).Encode(info); err != nil {
-		h.logger.Printf("write info response error: %s", err)
+		h.logger.Errorf("write info response error: %s", err)
 	}
 }
 
@@ -438,9 +615,11 @@ type getSchemaResponse struct {
 }
 
 type getStatusResponse struct {
-	State   string         `json:"state"`
-	Nodes   []*pilosa.Node `json:"nodes"`
-	LocalID string         `json:"localID"`
+	State    string              `json:"state"`
+	Nodes    []*pilosa.Node      `json:"nodes"`
+	LocalID  string              `json:"localID"`
+	Limits   pilosa.SchemaLimits `json:"limits"`
+	ReadOnly bool                `json:"readOnly,omitempty"`
 }
 
 // handlePostQuery handles /query requests.
@@ -455,14 +634,14 @@ func (h *Handler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	// TODO: Remove
 	req.Index = mux.Vars(r)["index"]
 
+	if streamQueryRequested(r) {
+		h.writeQueryStream(w, r, req)
+		return
+	}
+
 	resp, err := h.api.Query(r.Context(), req)
 	if err != nil {
-		switch errors.Cause(resp.Err) {
-		case pilosa.ErrTooManyWrites:
-			w.WriteHeader(http.StatusRequestEntityTooLarge)
-		default:
-			w.WriteHeader(http.StatusBadRequest)
-		}
+		w.WriteHeader(errorStatus(err, http.StatusBadRequest))
 		h.writeQueryResponse(w, r, &pilosa.QueryResponse{Err: err})
 		return
 	}
@@ -471,18 +650,79 @@ func (h *Handler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	// resp.Err could ever be set in API.Query, so this code block is probably
 	// doing nothing right now.
 	if resp.Err != nil {
-		switch errors.Cause(resp.Err) {
-		case pilosa.ErrTooManyWrites:
-			w.WriteHeader(http.StatusRequestEntityTooLarge)
-		default:
-			w.WriteHeader(http.StatusBadRequest)
-		}
+		w.WriteHeader(errorStatus(resp.Err, http.StatusBadRequest))
 	}
 
 	// Write response back to client.
 	if err := h.writeQueryResponse(w, r, &resp); err != nil {
-		h.logger.Printf("write query response error: %s", err)
+		h.logger.Errorf("write query response error: %s", err)
+	}
+}
+
+// streamQueryRequested returns true if the columns of a query's row should be
+// streamed, which is asked for with "Accept: application/x-ndjson" or
+// stream=true.
+func streamQueryRequested(r *http.Request) bool {
+	if r.URL.Query().Get("stream") == "true" {
+		return true
+	}
+	for _, v := range r.Header["Accept"] {
+		if v == "application/x-ndjson" {
+			return true
+		}
 	}
+	return false
+}
+
+// writeQueryStream executes a query of a single call returning a row, and
+// writes the columns of the row as newline-delimited JSON as they are found:
+// a {"columns":[...]} line for each chunk of columns, in ascending order,
+// then a {"count":n} line. An error once columns have been written ends the
+// stream with an {"error":"..."} line instead of the count.
+func (h *Handler) writeQueryStream(w http.ResponseWriter, r *http.Request, req *pilosa.QueryRequest) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+
+	var buf []byte
+	var written bool
+	n, err := h.api.QueryStream(r.Context(), req, func(columns []uint64) error {
+		buf = append(buf[:0], `{"columns":[`...)
+		for i, col := range columns {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = strconv.AppendUint(buf, col, 10)
+		}
+		buf = append(buf, "]}\n"...)
+
+		written = true
+		if _, err := w.Write(buf); err != nil {
+			return errors.Wrap(err, "writing")
+		} else if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	var line interface{} = queryStreamCount{Count: n}
+	if err != nil {
+		if !written {
+			w.WriteHeader(errorStatus(err, http.StatusBadRequest))
+		}
+		line = queryStreamError{Err: err.Error(), Code: pilosa.ErrorCode(err)}
+	}
+	if err := json.NewEncoder(w).Encode(line); err != nil {
+		h.logger.Errorf("write query stream error: %s", err)
+	}
+}
+
+type queryStreamCount struct {
+	Count uint64 `json:"count"`
+}
+
+type queryStreamError struct {
+	Err  string `json:"error"`
+	Code string `json:"code,omitempty"`
 }
 
 // handleGetShardsMax handles GET /internal/shards/max requests.
@@ -494,7 +734,7 @@ func (h *Handler) handleGetShardsMax(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewEncoder(w).Encode(getShardsMaxResponse{
 		Standard: h.api.MaxShards(r.Context()),
 	}); err != nil {
-		h.logger.Printf("write shards-max response error: %s", err)
+		h.logger.Errorf("write shards-max response error: %s", err)
 	}
 }
 
@@ -517,7 +757,7 @@ func (h *Handler) handleGetIndex(w http.ResponseWriter, r *http.Request) {
 	for _, idx := range h.api.Schema(r.Context()) {
 		if idx.Name == indexName {
 			if err := json.NewEncoder(w).Encode(idx); err != nil {
-				h.logger.Printf("write response error: %s", err)
+				h.logger.Errorf("write response error: %s", err)
 			}
 			return
 		}
@@ -525,6 +765,25 @@ func (h *Handler) handleGetIndex(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, fmt.Sprintf("Index %s Not Found", indexName), http.StatusNotFound)
 }
 
+// handleGetIndexMaxIDs handles GET /index/<indexname>/ids/max requests.
+func (h *Handler) handleGetIndexMaxIDs(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	ids, err := h.api.MaxIDs(r.Context(), mux.Vars(r)["index"])
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(ids); err != nil {
+		h.logger.Errorf("write response error: %s", err)
+	}
+}
+
 type postIndexRequest struct {
 	Options pilosa.IndexOptions `json:"options"`
 }
@@ -627,6 +886,11 @@ func (h *Handler) handlePostIndex(w http.ResponseWriter, r *http.Request) {
 
 	resp := successResponse{}
 
+	if err := pilosa.ValidateIndexName(indexName); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+
 	// Decode request.
 	req := postIndexRequest{
 		Options: pilosa.IndexOptions{
@@ -644,6 +908,104 @@ func (h *Handler) handlePostIndex(w http.ResponseWriter, r *http.Request) {
 	resp.write(w, err)
 }
 
+// handlePatchIndex handles PATCH /index/{index} requests.
+func (h *Handler) handlePatchIndex(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	resp := successResponse{}
+
+	// Decode request. Only the default time quantum may be changed.
+	var req patchIndexRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	} else if req.Options.ShardWidth != nil {
+		resp.write(w, pilosa.NewBadRequestError(pilosa.ErrShardWidthImmutable))
+		return
+	} else if req.Options.TimeQuantum == nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("timeQuantum is required")))
+		return
+	}
+
+	err := h.api.SetIndexTimeQuantum(r.Context(), indexName, *req.Options.TimeQuantum)
+	resp.write(w, err)
+}
+
+type patchIndexRequest struct {
+	Options struct {
+		TimeQuantum *pilosa.TimeQuantum `json:"timeQuantum"`
+		ShardWidth  *uint64             `json:"shardWidth"`
+	} `json:"options"`
+}
+
+// handlePostIndexRename handles POST /index/{index}/rename requests.
+func (h *Handler) handlePostIndexRename(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	resp := successResponse{}
+
+	// Decode request.
+	var req postIndexRenameRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	} else if req.Name == "" {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("name is required")))
+		return
+	}
+
+	err := h.api.RenameIndex(r.Context(), indexName, req.Name)
+	resp.write(w, err)
+}
+
+type postIndexRenameRequest struct {
+	Name string `json:"name"`
+}
+
+// handlePostIndexDeleteColumns handles POST /index/{index}/delete-columns
+// requests.
+func (h *Handler) handlePostIndexDeleteColumns(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	resp := successResponse{}
+
+	// Decode request.
+	var req postIndexDeleteColumnsRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	} else if req.Min == nil || req.Max == nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("min and max are required")))
+		return
+	}
+
+	err := h.api.DeleteColumnRange(r.Context(), indexName, *req.Min, *req.Max)
+	resp.write(w, err)
+}
+
+type postIndexDeleteColumnsRequest struct {
+	Min *uint64 `json:"min"`
+	Max *uint64 `json:"max"`
+}
+
 // handlePostIndexAttrDiff handles POST /internal/index/attr/diff requests.
 func (h *Handler) handlePostIndexAttrDiff(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -673,7 +1035,7 @@ func (h *Handler) handlePostIndexAttrDiff(w http.ResponseWriter, r *http.Request
 	if err := json.NewEncoder(w).Encode(postIndexAttrDiffResponse{
 		Attrs: attrs,
 	}); err != nil {
-		h.logger.Printf("response encoding error: %s", err)
+		h.logger.Errorf("response encoding error: %s", err)
 	}
 }
 
@@ -685,6 +1047,123 @@ type postIndexAttrDiffResponse struct {
 	Attrs map[uint64]map[string]interface{} `json:"attrs"`
 }
 
+// handleGetIndexAttrBlocks handles GET /internal/index/{index}/attr/blocks
+// requests. It returns the checksums of the blocks of the column attributes.
+func (h *Handler) handleGetIndexAttrBlocks(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	blocks, err := h.api.IndexAttrBlocks(r.Context(), indexName)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getIndexAttrBlocksResponse{Blocks: blocks}); err != nil {
+		h.logger.Errorf("response encoding error: %s", err)
+	}
+}
+
+type getIndexAttrBlocksResponse struct {
+	Blocks []pilosa.AttrBlock `json:"blocks"`
+}
+
+// handleGetIndexAttrBlockData handles GET
+// /internal/index/{index}/attr/blocks/{block} requests. It returns a page of
+// the column attributes in the block.
+func (h *Handler) handleGetIndexAttrBlockData(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	block, err := strconv.ParseUint(mux.Vars(r)["block"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid block", http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	var start uint64
+	if s := q.Get("start"); s != "" {
+		if start, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "invalid start", http.StatusBadRequest)
+			return
+		}
+	}
+	var limit int
+	if s := q.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.api.IndexAttrBlockData(r.Context(), indexName, block, start, limit)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		h.logger.Errorf("response encoding error: %s", err)
+	}
+}
+
+// handlePostIndexAttrBlockData handles POST /internal/index/{index}/attr/blocks
+// requests. It applies column attributes read from another node's blocks.
+func (h *Handler) handlePostIndexAttrBlockData(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	var req postIndexAttrBlockDataRequest
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err := decodeAttrNumbers(req.Attrs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.api.SetIndexAttrBlockData(r.Context(), indexName, req.Attrs)
+	resp := successResponse{}
+	resp.write(w, err)
+}
+
+type postIndexAttrBlockDataRequest struct {
+	Attrs map[uint64]map[string]interface{} `json:"attrs"`
+}
+
+// decodeAttrNumbers replaces the numbers in attributes decoded as json.Number
+// with integers if they're whole and floats otherwise, so integer attributes
+// keep their type.
+func decodeAttrNumbers(attrs map[uint64]map[string]interface{}) error {
+	for _, m := range attrs {
+		for k, v := range m {
+			n, ok := v.(json.Number)
+			if !ok {
+				continue
+			}
+			if i, err := n.Int64(); err == nil {
+				m[k] = i
+			} else if f, err := n.Float64(); err == nil {
+				m[k] = f
+			} else {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // handlePostField handles POST /field request.
 func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -696,6 +1175,11 @@ func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 
 	resp := successResponse{}
 
+	if err := pilosa.ValidateFieldName(fieldName); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+
 	// Decode request.
 	var req postFieldRequest
 	dec := json.NewDecoder(r.Body)
@@ -720,7 +1204,15 @@ func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 	case pilosa.FieldTypeInt:
 		fos = append(fos, pilosa.OptFieldTypeInt(*req.Options.Min, *req.Options.Max))
 	case pilosa.FieldTypeTime:
-		fos = append(fos, pilosa.OptFieldTypeTime(*req.Options.TimeQuantum, req.Options.NoStandardView))
+		// A field without a time quantum inherits the index's.
+		var q pilosa.TimeQuantum
+		if req.Options.TimeQuantum != nil {
+			q = *req.Options.TimeQuantum
+		}
+		fos = append(fos, pilosa.OptFieldTypeTime(q, req.Options.NoStandardView))
+		if req.Options.Retention != nil {
+			fos = append(fos, pilosa.OptFieldRetention(*req.Options.Retention))
+		}
 	case pilosa.FieldTypeMutex:
 		fos = append(fos, pilosa.OptFieldTypeMutex(*req.Options.CacheType, *req.Options.CacheSize))
 	case pilosa.FieldTypeBool:
@@ -731,6 +1223,12 @@ func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 			fos = append(fos, pilosa.OptFieldKeys())
 		}
 	}
+	if req.Options.RowLabel != nil {
+		fos = append(fos, pilosa.OptFieldRowLabel(*req.Options.RowLabel))
+	}
+	if req.Options.AttrCommit != nil {
+		fos = append(fos, pilosa.OptFieldAttrCommit(*req.Options.AttrCommit))
+	}
 
 	_, err = h.api.CreateField(r.Context(), indexName, fieldName, fos...)
 	resp.write(w, err)
@@ -743,14 +1241,17 @@ type postFieldRequest struct {
 // fieldOptions tracks pilosa.FieldOptions. It is made up of pointers to values,
 // and used for input validation.
 type fieldOptions struct {
-	Type           string              `json:"type,omitempty"`
-	CacheType      *string             `json:"cacheType,omitempty"`
-	CacheSize      *uint32             `json:"cacheSize,omitempty"`
-	Min            *int64              `json:"min,omitempty"`
-	Max            *int64              `json:"max,omitempty"`
-	TimeQuantum    *pilosa.TimeQuantum `json:"timeQuantum,omitempty"`
-	Keys           *bool               `json:"keys,omitempty"`
-	NoStandardView bool                `json:"noStandardView,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	CacheType      *string               `json:"cacheType,omitempty"`
+	CacheSize      *uint32               `json:"cacheSize,omitempty"`
+	Min            *int64                `json:"min,omitempty"`
+	Max            *int64                `json:"max,omitempty"`
+	TimeQuantum    *pilosa.TimeQuantum   `json:"timeQuantum,omitempty"`
+	Retention      *pilosa.TimeRetention `json:"retention,omitempty"`
+	Keys           *bool                 `json:"keys,omitempty"`
+	NoStandardView bool                  `json:"noStandardView,omitempty"`
+	RowLabel       *string               `json:"rowLabel,omitempty"`
+	AttrCommit     *pilosa.AttrCommit    `json:"attrCommit,omitempty"`
 }
 
 func (o *fieldOptions) validate() error {
@@ -800,8 +1301,6 @@ func (o *fieldOptions) validate() error {
 			return pilosa.NewBadRequestError(errors.New("min does not apply to field type time"))
 		} else if o.Max != nil {
 			return pilosa.NewBadRequestError(errors.New("max does not apply to field type time"))
-		} else if o.TimeQuantum == nil {
-			return pilosa.NewBadRequestError(errors.New("timeQuantum is required for field type time"))
 		}
 	case pilosa.FieldTypeMutex:
 		if o.CacheType == nil {
@@ -834,6 +1333,11 @@ func (o *fieldOptions) validate() error {
 	default:
 		return errors.Errorf("invalid field type: %s", o.Type)
 	}
+	if o.Retention != nil && o.Type != pilosa.FieldTypeTime {
+		return pilosa.NewBadRequestError(errors.Errorf("retention does not apply to field type %s", o.Type))
+	} else if o.RowLabel != nil && (o.Type == pilosa.FieldTypeInt || o.Type == pilosa.FieldTypeBool) {
+		return pilosa.NewBadRequestError(errors.Errorf("rowLabel does not apply to field type %s", o.Type))
+	}
 	return nil
 }
 
@@ -852,20 +1356,466 @@ func (h *Handler) handleDeleteField(w http.ResponseWriter, r *http.Request) {
 	resp.write(w, err)
 }
 
-// handleDeleteRemoteAvailableShard handles DELETE /field/{field}/available-shards/{shardID} request.
-func (h *Handler) handleDeleteRemoteAvailableShard(w http.ResponseWriter, r *http.Request) {
+// handlePatchField handles PATCH /field request.
+func (h *Handler) handlePatchField(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
 		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
 		return
 	}
-
 	indexName := mux.Vars(r)["index"]
 	fieldName := mux.Vars(r)["field"]
-	shardID, _ := strconv.ParseUint(mux.Vars(r)["shardID"], 10, 64)
 
 	resp := successResponse{}
-	err := h.api.DeleteAvailableShard(r.Context(), indexName, fieldName, shardID)
-	resp.write(w, err)
+
+	// Decode request. Only the time quantum and cache size may be changed.
+	var req patchFieldRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	} else if req.Options.TimeQuantum == nil && req.Options.CacheSize == nil {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("timeQuantum or cacheSize is required")))
+		return
+	}
+
+	var err error
+	if req.Options.TimeQuantum != nil {
+		err = h.api.SetFieldTimeQuantum(r.Context(), indexName, fieldName, *req.Options.TimeQuantum)
+	}
+	if err == nil && req.Options.CacheSize != nil {
+		err = h.api.SetFieldCacheSize(r.Context(), indexName, fieldName, *req.Options.CacheSize)
+	}
+	resp.write(w, err)
+}
+
+type patchFieldRequest struct {
+	Options struct {
+		TimeQuantum *pilosa.TimeQuantum `json:"timeQuantum"`
+		CacheSize   *uint32             `json:"cacheSize"`
+	} `json:"options"`
+}
+
+// handlePostFieldRename handles POST /index/{index}/field/{field}/rename
+// requests.
+func (h *Handler) handlePostFieldRename(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	resp := successResponse{}
+
+	// Decode request.
+	var req postFieldRenameRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	} else if req.Name == "" {
+		resp.write(w, pilosa.NewBadRequestError(errors.New("name is required")))
+		return
+	}
+
+	err := h.api.RenameField(r.Context(), indexName, fieldName, req.Name)
+	resp.write(w, err)
+}
+
+type postFieldRenameRequest struct {
+	Name string `json:"name"`
+}
+
+// handlePostTimeMigration handles POST /index/{index}/field/{field}/time-migration
+// requests. It starts building the larger time views of the field on this node
+// and returns the planned views.
+func (h *Handler) handlePostTimeMigration(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	var req postTimeMigrationRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp := successResponse{}
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+
+	m, err := h.api.StartTimeMigration(r.Context(), indexName, fieldName, req.From, req.To)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(m); err != nil {
+		h.logger.Errorf("write time migration response error: %s", err)
+	}
+}
+
+type postTimeMigrationRequest struct {
+	From pilosa.TimeQuantum `json:"from"`
+	To   pilosa.TimeQuantum `json:"to"`
+}
+
+// handlePostColumnAttrSearch handles POST /index/{index}/column-attrs/search
+// requests. It returns the columns whose value of an attribute matches.
+func (h *Handler) handlePostColumnAttrSearch(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+
+	var req pilosa.ColumnAttrSearch
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		resp := successResponse{}
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+
+	result, err := h.api.SearchColumnAttrs(r.Context(), indexName, &req)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Errorf("write column attr search response error: %s", err)
+	}
+}
+
+// handlePostRowAttrsBulk handles POST /index/{index}/field/{field}/attr/bulk
+// requests. It returns the attributes of the rows with the requested IDs.
+func (h *Handler) handlePostRowAttrsBulk(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	var req postRowAttrsBulkRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		resp := successResponse{}
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+
+	attrs, err := h.api.RowAttrs(r.Context(), indexName, fieldName, req.IDs)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(postRowAttrsBulkResponse{Attrs: attrs}); err != nil {
+		h.logger.Errorf("write row attrs response error: %s", err)
+	}
+}
+
+type postRowAttrsBulkRequest struct {
+	IDs []uint64 `json:"ids"`
+}
+
+type postRowAttrsBulkResponse struct {
+	Attrs map[uint64]map[string]interface{} `json:"attrs"`
+}
+
+// handleGetTimeMigration handles GET /index/{index}/field/{field}/time-migration
+// requests. It returns the progress of the field's latest migration on this node.
+func (h *Handler) handleGetTimeMigration(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	m, err := h.api.TimeMigration(r.Context(), indexName, fieldName)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(m); err != nil {
+		h.logger.Errorf("write time migration response error: %s", err)
+	}
+}
+
+// handlePostFieldCopy handles POST /index/{index}/field/{field}/copy requests.
+// It creates the field as a copy of the source field, which may be of another
+// index, and starts copying the source's data on this node.
+func (h *Handler) handlePostFieldCopy(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	var req postFieldCopyRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		resp := successResponse{}
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	} else if req.Source == "" {
+		resp := successResponse{}
+		resp.write(w, pilosa.NewBadRequestError(errors.New("source field required")))
+		return
+	}
+
+	c, err := h.api.CopyField(r.Context(), pilosa.FieldCopyRequest{
+		ID:          req.ID,
+		Index:       indexName,
+		Field:       fieldName,
+		SourceIndex: req.SourceIndex,
+		Source:      req.Source,
+		Options:     req.Options,
+		Overwrite:   req.Overwrite,
+	})
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(c); err != nil {
+		h.logger.Errorf("write field copy response error: %s", err)
+	}
+}
+
+type postFieldCopyRequest struct {
+	ID          string                  `json:"id,omitempty"`
+	SourceIndex string                  `json:"sourceIndex,omitempty"`
+	Source      string                  `json:"source"`
+	Options     pilosa.FieldCopyOptions `json:"options"`
+	Overwrite   bool                    `json:"overwrite,omitempty"`
+}
+
+// handleGetFieldCopy handles GET /index/{index}/field/{field}/copy requests. It
+// returns the progress of the copy into the field on this node.
+func (h *Handler) handleGetFieldCopy(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	c, err := h.api.FieldCopy(r.Context(), indexName, fieldName)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(c); err != nil {
+		h.logger.Errorf("write field copy response error: %s", err)
+	}
+}
+
+// handleGetFieldCopyByID handles GET /field-copy/{id} requests. It returns the
+// progress of the copy with the ID on this node.
+func (h *Handler) handleGetFieldCopyByID(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	c, err := h.api.FieldCopyByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(c); err != nil {
+		h.logger.Errorf("write field copy response error: %s", err)
+	}
+}
+
+// handlePostFieldCopyReady handles POST /index/{index}/field/{field}/copy/ready
+// requests. It marks the field ready on this node once the copy into it has
+// finished.
+func (h *Handler) handlePostFieldCopyReady(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	c, err := h.api.FinishFieldCopy(r.Context(), indexName, fieldName)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(c); err != nil {
+		h.logger.Errorf("write field copy response error: %s", err)
+	}
+}
+
+// handleGetViews handles GET /index/{index}/field/{field}/views requests. The
+// optional from and to arguments list only the time views overlapping a range.
+func (h *Handler) handleGetViews(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	resp := successResponse{}
+	var from, to time.Time
+	for _, arg := range []struct {
+		name string
+		t    *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		v := r.URL.Query().Get(arg.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(pilosa.TimeFormat, v)
+		if err != nil {
+			resp.write(w, pilosa.NewBadRequestError(errors.Wrapf(err, "parsing %s", arg.name)))
+			return
+		}
+		*arg.t = t
+	}
+
+	views, err := h.api.ViewInfos(r.Context(), indexName, fieldName, from, to)
+	if err != nil {
+		resp.write(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getViewsResponse{Views: views}); err != nil {
+		h.logger.Errorf("write views response error: %s", err)
+	}
+}
+
+type getViewsResponse struct {
+	Views []*pilosa.ViewInfo `json:"views"`
+}
+
+// handleDeleteView handles DELETE /index/{index}/field/{field}/view/{view}
+// requests.
+func (h *Handler) handleDeleteView(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+	viewName := mux.Vars(r)["view"]
+
+	resp := successResponse{}
+	if err := pilosa.ValidateViewName(viewName); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+	err := h.api.DeleteView(r.Context(), indexName, fieldName, viewName)
+	resp.write(w, err)
+}
+
+// handleGetFieldCache handles GET /index/{index}/field/{field}/cache requests.
+func (h *Handler) handleGetFieldCache(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	shards, err := parseCacheShards(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	caches, err := h.api.FieldCache(r.Context(), indexName, fieldName, shards)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(fieldCacheResponse{Fragments: caches}); err != nil {
+		h.logger.Errorf("write response error: %s", err)
+	}
+}
+
+// handleDeleteFieldCache handles DELETE /index/{index}/field/{field}/cache requests.
+func (h *Handler) handleDeleteFieldCache(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+
+	shards, err := parseCacheShards(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	caches, err := h.api.InvalidateFieldCache(r.Context(), indexName, fieldName, shards)
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(fieldCacheResponse{Fragments: caches}); err != nil {
+		h.logger.Errorf("write response error: %s", err)
+	}
+}
+
+// parseCacheShards returns the optional shard parameter of a field cache
+// request. A nil slice refers to every local shard.
+func parseCacheShards(r *http.Request) ([]uint64, error) {
+	s := r.URL.Query().Get("shard")
+	if s == "" {
+		return nil, nil
+	}
+	shard, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid shard argument")
+	}
+	return []uint64{shard}, nil
+}
+
+type fieldCacheResponse struct {
+	Fragments []pilosa.FragmentCache `json:"fragments"`
+}
+
+// handleDeleteRemoteAvailableShard handles DELETE /field/{field}/available-shards/{shardID} request.
+func (h *Handler) handleDeleteRemoteAvailableShard(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+	fieldName := mux.Vars(r)["field"]
+	shardID, _ := strconv.ParseUint(mux.Vars(r)["shardID"], 10, 64)
+
+	resp := successResponse{}
+	err := h.api.DeleteAvailableShard(r.Context(), indexName, fieldName, shardID)
+	resp.write(w, err)
 }
 
 // handlePostFieldAttrDiff handles POST /internal/field/attr/diff requests.
@@ -899,7 +1849,7 @@ func (h *Handler) handlePostFieldAttrDiff(w http.ResponseWriter, r *http.Request
 	if err := json.NewEncoder(w).Encode(postFieldAttrDiffResponse{
 		Attrs: attrs,
 	}); err != nil {
-		h.logger.Printf("response encoding error: %s", err)
+		h.logger.Errorf("response encoding error: %s", err)
 	}
 }
 
@@ -960,6 +1910,8 @@ func (h *Handler) readURLQueryRequest(r *http.Request) (*pilosa.QueryRequest, er
 		ColumnAttrs:     q.Get("columnAttrs") == "true",
 		ExcludeRowAttrs: q.Get("excludeRowAttrs") == "true",
 		ExcludeColumns:  q.Get("excludeColumns") == "true",
+		Labels:          q.Get("labels") == "true",
+		Profile:         q.Get("profile") == "true",
 	}, nil
 }
 
@@ -986,8 +1938,89 @@ func (h *Handler) writeJSONQueryResponse(w io.Writer, resp *pilosa.QueryResponse
 	return json.NewEncoder(w).Encode(resp)
 }
 
+// handleOptionsImport handles OPTIONS /import requests, advertising the
+// content codings accepted for import bodies as described by RFC 7694.
+func (h *Handler) handleOptionsImport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Allow", "OPTIONS, POST")
+	w.Header().Set("Accept-Encoding", "gzip")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errImportTooLarge is returned when reading a compressed import body which
+// decompresses to more than the handler's limit.
+var errImportTooLarge = errors.New("decompressed import body is too large")
+
+// decodeImportBody replaces the body of an import request sent with
+// "Content-Encoding: gzip" with one which decompresses it as it is read.
+func (h *Handler) decodeImportBody(r *http.Request) (int, error) {
+	switch r.Header.Get("Content-Encoding") {
+	case "", "identity":
+		return 0, nil
+	case "gzip":
+	default:
+		return http.StatusUnsupportedMediaType, errors.New("unsupported content encoding")
+	}
+
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "reading gzip header")
+	}
+	r.Body = &gzipBody{gz: gz, body: r.Body, remaining: h.maxDecompressedImportSize, limited: h.maxDecompressedImportSize > 0}
+	r.Header.Del("Content-Encoding")
+	return 0, nil
+}
+
+// gzipBody decompresses a request body, failing with errImportTooLarge once
+// more than the limit has been decompressed.
+type gzipBody struct {
+	gz        *gzip.Reader
+	body      io.ReadCloser
+	remaining int64
+	limited   bool
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.limited && int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.gz.Read(p)
+	if b.limited {
+		if b.remaining -= int64(n); b.remaining < 0 {
+			return 0, errImportTooLarge
+		}
+	}
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	b.gz.Close()
+	return b.body.Close()
+}
+
+// readBodyStatus returns the status code of a failure to read a request body.
+func readBodyStatus(err error) int {
+	if errors.Cause(err) == errImportTooLarge {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
 // handlePostImport handles /import requests.
 func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
+	if code, err := h.decodeImportBody(r); err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	switch r.Header.Get("Content-Type") {
+	case contentTypeRoaringRows:
+		h.handlePostImportRoaringRows(w, r)
+		return
+	case contentTypeJSONLines:
+		h.handlePostImportJSONLines(w, r)
+		return
+	}
+
 	// Verify that request is only communicating over protobufs.
 	if r.Header.Get("Content-Type") != "application/x-protobuf" {
 		http.Error(w, "Unsupported media type", http.StatusUnsupportedMediaType)
@@ -999,90 +2032,240 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 	indexName := mux.Vars(r)["index"]
 	fieldName := mux.Vars(r)["field"]
 
-	// If the clear flag is true, treat the import as clear bits.
-	q := r.URL.Query()
-	doClear := q.Get("clear") == "true"
-	doIgnoreKeyCheck := q.Get("ignoreKeyCheck") == "true"
+	// If the clear flag is true, treat the import as clear bits.
+	q := r.URL.Query()
+	doClear := q.Get("clear") == "true"
+	doIgnoreKeyCheck := q.Get("ignoreKeyCheck") == "true"
+
+	opts := []pilosa.ImportOption{
+		pilosa.OptImportOptionsClear(doClear),
+		pilosa.OptImportOptionsIgnoreKeyCheck(doIgnoreKeyCheck),
+	}
+
+	// Get index and field type to determine how to handle the
+	// import data.
+	field, err := h.api.Field(r.Context(), indexName, fieldName)
+	if err != nil {
+		switch errors.Cause(err) {
+		case pilosa.ErrIndexNotFound:
+			fallthrough
+		case pilosa.ErrFieldNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Read entire body.
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), readBodyStatus(err))
+		return
+	}
+
+	resp := &pilosa.ImportResponse{}
+
+	// Unmarshal request based on field type.
+	if field.Type() == pilosa.FieldTypeInt {
+		// Field type: Int
+		// Marshal into request object.
+		req := &pilosa.ImportValueRequest{}
+		if err := h.api.Serializer.Unmarshal(body, req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.api.ImportValue(r.Context(), req, opts...); err != nil {
+			switch cause := errors.Cause(err); cause.(type) {
+			case pilosa.ForbiddenError:
+				http.Error(w, err.Error(), http.StatusForbidden)
+			default:
+				if cause == pilosa.ErrClusterDoesNotOwnShard {
+					http.Error(w, err.Error(), http.StatusPreconditionFailed)
+				} else {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+				}
+			}
+			return
+		}
+	} else {
+		// Field type: Set, Time
+		// Marshal into request object.
+		req := &pilosa.ImportRequest{}
+		if err := h.api.Serializer.Unmarshal(body, req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if resp.Counts, err = h.api.Import(r.Context(), req, opts...); err != nil {
+			switch cause := errors.Cause(err); cause.(type) {
+			case pilosa.BadRequestError:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case pilosa.ForbiddenError:
+				http.Error(w, err.Error(), http.StatusForbidden)
+			default:
+				if cause == pilosa.ErrClusterDoesNotOwnShard {
+					http.Error(w, err.Error(), http.StatusPreconditionFailed)
+				} else {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+				}
+			}
+			return
+		}
+
+		if resp.Views, err = h.api.ImportViewCounts(r.Context(), req); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	resp.PendingCacheRebuilds = h.api.PendingCacheRebuilds()
+
+	// Marshal response object.
+	buf, e := h.api.Serializer.Marshal(resp)
+	if e != nil {
+		http.Error(w, fmt.Sprintf("marshal import response"), http.StatusInternalServerError)
+		return
+	}
+
+	// Write response.
+	w.Write(buf)
+}
+
+// contentTypeRoaringRows is the content type of an import request made of
+// whole rows, each a serialized roaring bitmap of column offsets.
+const contentTypeRoaringRows = "application/x-pilosa-roaring-rows"
+
+// handlePostImportRoaringRows handles /import requests whose body is a
+// protobuf encoded ImportRoaringRowsRequest.
+func (h *Handler) handlePostImportRoaringRows(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/x-protobuf" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), readBodyStatus(err))
+		return
+	}
+
+	req := &pilosa.ImportRoaringRowsRequest{}
+	if err := h.api.Serializer.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Index, req.Field = mux.Vars(r)["index"], mux.Vars(r)["field"]
+
+	opts := []pilosa.ImportOption{
+		pilosa.OptImportOptionsClear(r.URL.Query().Get("clear") == "true"),
+	}
+	n, err := h.api.ImportRoaringRows(r.Context(), req, opts...)
+	if err != nil {
+		switch errors.Cause(err).(type) {
+		case pilosa.BadRequestError:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case pilosa.NotFoundError:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case pilosa.ForbiddenError:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			if errors.Cause(err) == pilosa.ErrClusterDoesNotOwnShard {
+				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			} else {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	resp := &pilosa.ImportResponse{
+		PendingCacheRebuilds: h.api.PendingCacheRebuilds(),
+		Views:                map[string]uint64{"standard": n},
+	}
+	buf, err := h.api.Serializer.Marshal(resp)
+	if err != nil {
+		http.Error(w, "marshal import response", http.StatusInternalServerError)
+		return
+	}
+	w.Write(buf)
+}
+
+// contentTypeJSONLines is the content type of an import request made of JSON
+// objects, one per line.
+const contentTypeJSONLines = "application/x-ndjson"
 
-	opts := []pilosa.ImportOption{
-		pilosa.OptImportOptionsClear(doClear),
-		pilosa.OptImportOptionsIgnoreKeyCheck(doIgnoreKeyCheck),
+// jsonLinesImportBufferSize is the number of bits read from a JSON-lines
+// import before they are imported.
+const jsonLinesImportBufferSize = 100000
+
+// handlePostImportJSONLines handles /import requests whose body is JSON
+// lines. The body is streamed, so lines before an invalid one are imported.
+func (h *Handler) handlePostImportJSONLines(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
 	}
+	indexName, fieldName := mux.Vars(r)["index"], mux.Vars(r)["field"]
+	q := r.URL.Query()
 
-	// Get index and field type to determine how to handle the
-	// import data.
-	field, err := h.api.Field(r.Context(), indexName, fieldName)
+	resp := successResponse{}
+	index, err := h.api.Index(r.Context(), indexName)
 	if err != nil {
-		switch errors.Cause(err) {
-		case pilosa.ErrIndexNotFound:
-			fallthrough
-		case pilosa.ErrFieldNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		resp.write(w, err)
 		return
 	}
-
-	// Read entire body.
-	body, err := ioutil.ReadAll(r.Body)
+	field, err := h.api.Field(r.Context(), indexName, fieldName)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		resp.write(w, err)
 		return
 	}
 
-	// Unmarshal request based on field type.
-	if field.Type() == pilosa.FieldTypeInt {
-		// Field type: Int
-		// Marshal into request object.
-		req := &pilosa.ImportValueRequest{}
-		if err := h.api.Serializer.Unmarshal(body, req); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	jr := NewJSONLinesReader(r.Body, JSONLinesFormat{
+		RowField:    q.Get("bitmapField"),
+		ColumnField: q.Get("profileField"),
+		TimeField:   q.Get("timeField"),
+		RowKeys:     field.Options().Keys,
+		ColumnKeys:  index.Keys(),
+	})
+	opts := []pilosa.ImportOption{pilosa.OptImportOptionsClear(q.Get("clear") == "true")}
 
-		if err := h.api.ImportValue(r.Context(), req, opts...); err != nil {
-			switch errors.Cause(err) {
-			case pilosa.ErrClusterDoesNotOwnShard:
-				http.Error(w, err.Error(), http.StatusPreconditionFailed)
-			default:
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+	bits := make([]pilosa.Bit, 0, jsonLinesImportBufferSize)
+	for {
+		bit, err := jr.Read()
+		if err == io.EOF {
+			break
+		} else if err == errImportTooLarge {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
 			return
-		}
-	} else {
-		// Field type: Set, Time
-		// Marshal into request object.
-		req := &pilosa.ImportRequest{}
-		if err := h.api.Serializer.Unmarshal(body, req); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if err != nil {
+			resp.write(w, pilosa.NewBadRequestError(err))
 			return
 		}
 
-		if err := h.api.Import(r.Context(), req, opts...); err != nil {
-			switch errors.Cause(err) {
-			case pilosa.ErrClusterDoesNotOwnShard:
-				http.Error(w, err.Error(), http.StatusPreconditionFailed)
-			default:
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+		if bits = append(bits, bit); len(bits) == jsonLinesImportBufferSize {
+			if err := h.api.ImportBits(r.Context(), indexName, fieldName, bits, opts...); err != nil {
+				resp.write(w, err)
+				return
 			}
-			return
+			bits = bits[:0]
 		}
 	}
-
-	// Marshal response object.
-	buf, e := h.api.Serializer.Marshal(&pilosa.ImportResponse{Err: ""})
-	if e != nil {
-		http.Error(w, fmt.Sprintf("marshal import response"), http.StatusInternalServerError)
-		return
-	}
-
-	// Write response.
-	w.Write(buf)
+	resp.write(w, h.api.ImportBits(r.Context(), indexName, fieldName, bits, opts...))
 }
 
 // handleGetExport handles /export requests.
 func (h *Handler) handleGetExport(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("format") {
+	case "proto":
+		h.handleGetExportProto(w, r)
+		return
+	case "", "csv":
+	default:
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
 	switch r.Header.Get("Accept") {
 	case "text/csv":
 		h.handleGetExportCSV(w, r)
@@ -1102,7 +2285,13 @@ func (h *Handler) handleGetExportCSV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.api.ExportCSV(r.Context(), index, field, shard, w); err != nil {
+	opts, err := exportOptions(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.api.ExportCSV(r.Context(), index, field, shard, w, opts...); err != nil {
 		switch errors.Cause(err) {
 		case pilosa.ErrFragmentNotFound:
 			break
@@ -1115,6 +2304,98 @@ func (h *Handler) handleGetExportCSV(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// defaultExportChunkSize is the number of bits in each message of a protobuf
+// export unless the chunk argument is given.
+const defaultExportChunkSize = 65536
+
+// handleGetExportProto handles /export requests for the protobuf format.
+func (h *Handler) handleGetExportProto(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	index, field := q.Get("index"), q.Get("field")
+
+	shard, err := strconv.ParseUint(q.Get("shard"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid shard", http.StatusBadRequest)
+		return
+	}
+
+	chunk := defaultExportChunkSize
+	if s := q.Get("chunk"); s != "" {
+		if chunk, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "invalid chunk", http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts, err := exportOptions(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	if err = h.api.ExportProto(r.Context(), index, field, shard, chunk, w, opts...); err != nil {
+		switch cause := errors.Cause(err); cause.(type) {
+		case pilosa.BadRequestError:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case pilosa.NotFoundError:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			if cause == pilosa.ErrClusterDoesNotOwnShard {
+				http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			} else {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+}
+
+// exportOptions returns the filters of an /export request: an inclusive
+// range of rows given by minBitmapID and maxBitmapID, a comma-separated list
+// of row IDs given by bitmapIDs, and the view given by view.
+func exportOptions(q url.Values) ([]pilosa.ExportOption, error) {
+	min, max := uint64(0), uint64(math.MaxUint64)
+	var err error
+	if s := q.Get("minBitmapID"); s != "" {
+		if min, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return nil, errors.New("invalid minBitmapID")
+		}
+	}
+	if s := q.Get("maxBitmapID"); s != "" {
+		if max, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return nil, errors.New("invalid maxBitmapID")
+		}
+	}
+	opts := []pilosa.ExportOption{pilosa.OptExportOptionsRowRange(min, max)}
+
+	if _, ok := q["bitmapIDs"]; ok {
+		rowIDs := make([]uint64, 0)
+		if s := q.Get("bitmapIDs"); s != "" {
+			for _, v := range strings.Split(s, ",") {
+				rowID, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid bitmapIDs: %q", v)
+				}
+				rowIDs = append(rowIDs, rowID)
+			}
+		}
+		opts = append(opts, pilosa.OptExportOptionsRowIDs(rowIDs))
+	}
+	if view := q.Get("view"); view != "" {
+		if err := pilosa.ValidateViewName(view); err != nil {
+			return nil, err
+		}
+		opts = append(opts, pilosa.OptExportOptionsView(view))
+	}
+
+	// Report invalid combinations before anything is written.
+	if _, err := pilosa.NewExportOptions(opts...); err != nil {
+		return nil, errors.Cause(err)
+	}
+	return opts, nil
+}
+
 // handleGetFragmentNodes handles /internal/fragment/nodes requests.
 func (h *Handler) handleGetFragmentNodes(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -1140,7 +2421,7 @@ func (h *Handler) handleGetFragmentNodes(w http.ResponseWriter, r *http.Request)
 
 	// Write to response.
 	if err := json.NewEncoder(w).Encode(nodes); err != nil {
-		h.logger.Printf("json write error: %s", err)
+		h.logger.Errorf("json write error: %s", err)
 	}
 }
 
@@ -1156,7 +2437,19 @@ func (h *Handler) handleGetNodes(w http.ResponseWriter, r *http.Request) {
 
 	// Write to response.
 	if err := json.NewEncoder(w).Encode(nodes); err != nil {
-		h.logger.Printf("json write error: %s", err)
+		h.logger.Errorf("json write error: %s", err)
+	}
+}
+
+// handleGetInternalSchema handles GET /internal/schema requests.
+func (h *Handler) handleGetInternalSchema(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(h.api.CanonicalSchema(r.Context())); err != nil {
+		h.logger.Errorf("write schema response error: %s", err)
 	}
 }
 
@@ -1192,6 +2485,9 @@ func (h *Handler) handleGetFragmentBlocks(w http.ResponseWriter, r *http.Request
 	if err != nil {
 		http.Error(w, "shard required", http.StatusBadRequest)
 		return
+	} else if err := pilosa.ValidateViewName(q.Get("view")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	blocks, err := h.api.FragmentBlocks(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"), shard)
@@ -1208,7 +2504,7 @@ func (h *Handler) handleGetFragmentBlocks(w http.ResponseWriter, r *http.Request
 	if err := json.NewEncoder(w).Encode(getFragmentBlocksResponse{
 		Blocks: blocks,
 	}); err != nil {
-		h.logger.Printf("block response encoding error: %s", err)
+		h.logger.Errorf("block response encoding error: %s", err)
 	}
 }
 
@@ -1216,6 +2512,39 @@ type getFragmentBlocksResponse struct {
 	Blocks []pilosa.FragmentBlock `json:"blocks"`
 }
 
+// handleGetFragmentChecksums handles GET /internal/fragment/checksums requests.
+func (h *Handler) handleGetFragmentChecksums(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	q := r.URL.Query()
+	if err := pilosa.ValidateViewName(q.Get("view")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	checksums, err := h.api.FragmentChecksums(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"))
+	if err != nil {
+		if errors.Cause(err) == pilosa.ErrFieldNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(getFragmentChecksumsResponse{
+		Checksums: checksums,
+	}); err != nil {
+		h.logger.Errorf("checksums response encoding error: %s", err)
+	}
+}
+
+type getFragmentChecksumsResponse struct {
+	Checksums []pilosa.FragmentChecksum `json:"checksums"`
+}
+
 // handleGetFragmentData handles GET /internal/fragment/data requests.
 func (h *Handler) handleGetFragmentData(w http.ResponseWriter, r *http.Request) {
 	// Read shard parameter.
@@ -1224,6 +2553,9 @@ func (h *Handler) handleGetFragmentData(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		http.Error(w, "shard required", http.StatusBadRequest)
 		return
+	} else if err := pilosa.ValidateViewName(q.Get("view")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 	// Retrieve fragment data from holder.
 	f, err := h.api.FragmentData(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"), shard)
@@ -1233,8 +2565,71 @@ func (h *Handler) handleGetFragmentData(w http.ResponseWriter, r *http.Request)
 	}
 	// Stream fragment to response body.
 	if _, err := f.WriteTo(w); err != nil {
-		h.logger.Printf("error streaming fragment data: %s", err)
+		h.logger.Errorf("error streaming fragment data: %s", err)
+	}
+}
+
+// handleDeleteFragmentData handles DELETE /internal/fragment/data requests.
+func (h *Handler) handleDeleteFragmentData(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	q := r.URL.Query()
+	shard, err := strconv.ParseUint(q.Get("shard"), 10, 64)
+	if err != nil {
+		http.Error(w, "shard required", http.StatusBadRequest)
+		return
+	}
+
+	resp := successResponse{}
+	if err := pilosa.ValidateViewName(q.Get("view")); err != nil {
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+	err = h.api.DeleteFragment(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"), shard)
+	resp.write(w, err)
+}
+
+// handlePostFragmentCompact handles POST /internal/fragment/compact requests.
+// Without a shard, every fragment of the view is compacted.
+func (h *Handler) handlePostFragmentCompact(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	q := r.URL.Query()
+	if err := pilosa.ValidateViewName(q.Get("view")); err != nil {
+		resp := successResponse{}
+		resp.write(w, pilosa.NewBadRequestError(err))
+		return
+	}
+	var reclaimed int64
+	var err error
+	if s := q.Get("shard"); s == "" {
+		reclaimed, err = h.api.CompactView(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"))
+	} else if shard, perr := strconv.ParseUint(s, 10, 64); perr != nil {
+		http.Error(w, "invalid shard", http.StatusBadRequest)
+		return
+	} else {
+		reclaimed, err = h.api.CompactFragment(r.Context(), q.Get("index"), q.Get("field"), q.Get("view"), shard)
+	}
+
+	if err != nil {
+		resp := successResponse{}
+		resp.write(w, err)
+		return
 	}
+	if err := json.NewEncoder(w).Encode(postFragmentCompactResponse{Success: true, Reclaimed: reclaimed}); err != nil {
+		h.logger.Errorf("write compact response error: %s", err)
+	}
+}
+
+type postFragmentCompactResponse struct {
+	Success   bool  `json:"success"`
+	Reclaimed int64 `json:"reclaimed"`
 }
 
 // handleGetVersion handles /version requests.
@@ -1249,7 +2644,7 @@ func (h *Handler) handleGetVersion(w http.ResponseWriter, r *http.Request) {
 		Version: h.api.Version(),
 	})
 	if err != nil {
-		h.logger.Printf("write version response error: %s", err)
+		h.logger.Errorf("write version response error: %s", err)
 	}
 }
 
@@ -1306,7 +2701,7 @@ func (h *Handler) handlePostClusterResizeSetCoordinator(w http.ResponseWriter, r
 		Old: oldNode,
 		New: newNode,
 	}); err != nil {
-		h.logger.Printf("response encoding error: %s", err)
+		h.logger.Errorf("response encoding error: %s", err)
 	}
 }
 
@@ -1347,7 +2742,7 @@ func (h *Handler) handlePostClusterResizeRemoveNode(w http.ResponseWriter, r *ht
 	if err := json.NewEncoder(w).Encode(removeNodeResponse{
 		Remove: removeNode,
 	}); err != nil {
-		h.logger.Printf("response encoding error: %s", err)
+		h.logger.Errorf("response encoding error: %s", err)
 	}
 }
 
@@ -1355,6 +2750,61 @@ type removeNodeRequest struct {
 	ID string `json:"id"`
 }
 
+// handlePostClusterRemoveNode handles POST /cluster/remove-node request.
+func (h *Handler) handlePostClusterRemoveNode(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+	// Decode request.
+	var req removeNodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	removal, err := h.api.RemoveNodeWithHandoff(req.ID)
+	if err != nil {
+		if errors.Cause(err) == pilosa.ErrNodeIDNotExists {
+			http.Error(w, "removing node: "+err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "removing node: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Encode response.
+	if err := json.NewEncoder(w).Encode(removal); err != nil {
+		h.logger.Errorf("response encoding error: %s", err)
+	}
+}
+
+// handleGetClusterRemoveNode handles GET /cluster/remove-node request.
+func (h *Handler) handleGetClusterRemoveNode(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	removal, err := h.api.NodeRemoval()
+	if err != nil {
+		switch errors.Cause(err) {
+		case pilosa.ErrNodeNotCoordinator:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case pilosa.ErrNodeRemovalNotStarted:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Encode response.
+	if err := json.NewEncoder(w).Encode(removal); err != nil {
+		h.logger.Errorf("response encoding error: %s", err)
+	}
+}
+
 type removeNodeResponse struct {
 	Remove *pilosa.Node `json:"remove"`
 }
@@ -1383,7 +2833,7 @@ func (h *Handler) handlePostClusterResizeAbort(w http.ResponseWriter, r *http.Re
 	if err := json.NewEncoder(w).Encode(clusterResizeAbortResponse{
 		Info: msg,
 	}); err != nil {
-		h.logger.Printf("response encoding error: %s", err)
+		h.logger.Errorf("response encoding error: %s", err)
 	}
 }
 
@@ -1392,6 +2842,16 @@ type clusterResizeAbortResponse struct {
 }
 
 func (h *Handler) handleRecalculateCaches(w http.ResponseWriter, r *http.Request) {
+	// Rebuilding caches from storage only starts the rebuilds.
+	if r.URL.Query().Get("rebuild") == "true" {
+		if err := h.api.RebuildCaches(r.Context()); err != nil {
+			http.Error(w, "rebuilding caches: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
 	err := h.api.RecalculateCaches(r.Context())
 	if err != nil {
 		http.Error(w, "recalculating caches: "+err.Error(), http.StatusInternalServerError)
@@ -1419,7 +2879,7 @@ func (h *Handler) handlePostClusterMessage(w http.ResponseWriter, r *http.Reques
 	}
 
 	if err := json.NewEncoder(w).Encode(defaultClusterMessageResponse{}); err != nil {
-		h.logger.Printf("response encoding error: %s", err)
+		h.logger.Errorf("response encoding error: %s", err)
 	}
 }
 
@@ -1465,13 +2925,13 @@ func (h *Handler) handleGetTranslateData(w http.ResponseWriter, r *http.Request)
 			useBufferSize *= 2
 			// Prevent the buffer from growing without bound.
 			if useBufferSize > translateStoreBufferSizeMax {
-				h.logger.Printf("http: translate store buffer exceeded max size: %s", err)
+				h.logger.Errorf("http: translate store buffer exceeded max size: %s", err)
 				return
 			}
 			buf = make([]byte, useBufferSize)
 			continue
 		} else if err != nil {
-			h.logger.Printf("http: translate store read error: %s", err)
+			h.logger.Errorf("http: translate store read error: %s", err)
 			return
 		} else if n == 0 {
 			// Reset the default buffer size.
@@ -1482,7 +2942,7 @@ func (h *Handler) handleGetTranslateData(w http.ResponseWriter, r *http.Request)
 
 		// Write to response & flush.
 		if _, err := w.Write(buf[:n]); err != nil {
-			h.logger.Printf("http: translate store response write error: %s", err)
+			h.logger.Errorf("http: translate store response write error: %s", err)
 			return
 		} else if w, ok := w.(http.Flusher); ok {
 			w.Flush()
@@ -1550,6 +3010,11 @@ func GetHTTPClient(t *tls.Config) *http.Client {
 
 // handlPostRoaringImport
 func (h *Handler) handlePostImportRoaring(w http.ResponseWriter, r *http.Request) {
+	if code, err := h.decodeImportBody(r); err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+
 	// Verify that request is only communicating over protobufs.
 	if r.Header.Get("Content-Type") != "application/x-protobuf" {
 		http.Error(w, "Unsupported media type", http.StatusUnsupportedMediaType)
@@ -1572,7 +3037,7 @@ func (h *Handler) handlePostImportRoaring(w http.ResponseWriter, r *http.Request
 	// Read entire body.
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), readBodyStatus(err))
 		return
 	}
 
@@ -1592,10 +3057,13 @@ func (h *Handler) handlePostImportRoaring(w http.ResponseWriter, r *http.Request
 	resp := &pilosa.ImportResponse{}
 	// TODO give meaningful stats for import
 	err = h.api.ImportRoaring(r.Context(), indexName, fieldName, shard, remote, req)
+	resp.PendingCacheRebuilds = h.api.PendingCacheRebuilds()
 	if err != nil {
 		resp.Err = err.Error()
 		if _, ok := err.(pilosa.BadRequestError); ok {
 			w.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := errors.Cause(err).(pilosa.ForbiddenError); ok {
+			w.WriteHeader(http.StatusForbidden)
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -1610,7 +3078,7 @@ func (h *Handler) handlePostImportRoaring(w http.ResponseWriter, r *http.Request
 	// Write response.
 	_, err = w.Write(buf)
 	if err != nil {
-		h.logger.Printf("writing import-roaring response: %v", err)
+		h.logger.Errorf("writing import-roaring response: %v", err)
 	}
 }
 
@@ -1632,6 +3100,6 @@ func (h *Handler) handlePostTranslateKeys(w http.ResponseWriter, r *http.Request
 	// Write response.
 	_, err = w.Write(buf)
 	if err != nil {
-		h.logger.Printf("writing translate keys response: %v", err)
+		h.logger.Errorf("writing translate keys response: %v", err)
 	}
 }
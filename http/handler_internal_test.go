@@ -145,7 +145,9 @@ func TestFieldOptionValidation(t *testing.T) {
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "timeQuantum": "YMD"}}`, err: "timeQuantum does not apply to field type int"},
 
 		// FieldType: Time
-		{json: `{"options": {"type": "time"}}`, err: "timeQuantum is required for field type time"},
+		{json: `{"options": {"type": "time"}}`, expected: postFieldRequest{Options: fieldOptions{
+			Type: pilosa.FieldTypeTime,
+		}}},
 		{json: `{"options": {"type": "time", "timeQuantum": "YMD"}}`, expected: postFieldRequest{Options: fieldOptions{
 			Type:        pilosa.FieldTypeTime,
 			TimeQuantum: &timeQuantum,
@@ -0,0 +1,157 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pilosa/pilosa"
+)
+
+// Default names of the properties of a JSON-lines import record.
+const (
+	DefaultJSONLinesRowField    = "bitmapID"
+	DefaultJSONLinesColumnField = "profileID"
+	DefaultJSONLinesTimeField   = "timestamp"
+)
+
+// JSONLinesFormat describes the records of a JSON-lines import.
+type JSONLinesFormat struct {
+	// Names of the properties holding the row, column and optional time of
+	// each bit.
+	RowField    string
+	ColumnField string
+	TimeField   string
+
+	// Whether rows and columns are given as string keys instead of IDs.
+	RowKeys    bool
+	ColumnKeys bool
+}
+
+// JSONLinesReader reads bits from a stream of JSON objects, one per line.
+// Properties other than those of the format are ignored.
+type JSONLinesReader struct {
+	r      *bufio.Reader
+	format JSONLinesFormat
+	line   int
+	record map[string]json.RawMessage
+}
+
+// NewJSONLinesReader returns a reader of bits from r. Empty field names in
+// format are replaced by the defaults.
+func NewJSONLinesReader(r io.Reader, format JSONLinesFormat) *JSONLinesReader {
+	if format.RowField == "" {
+		format.RowField = DefaultJSONLinesRowField
+	}
+	if format.ColumnField == "" {
+		format.ColumnField = DefaultJSONLinesColumnField
+	}
+	if format.TimeField == "" {
+		format.TimeField = DefaultJSONLinesTimeField
+	}
+	return &JSONLinesReader{r: bufio.NewReader(r), format: format}
+}
+
+// Read returns the next bit, or io.EOF when the stream is exhausted. Blank
+// lines are skipped, and errors include the number of the offending line.
+func (r *JSONLinesReader) Read() (pilosa.Bit, error) {
+	for {
+		buf, err := r.r.ReadBytes('\n')
+		if err == io.EOF && len(buf) == 0 {
+			return pilosa.Bit{}, io.EOF
+		} else if err != nil && err != io.EOF {
+			return pilosa.Bit{}, err
+		}
+		r.line++
+
+		if buf = bytes.TrimSpace(buf); len(buf) == 0 {
+			continue
+		}
+		return r.parse(buf)
+	}
+}
+
+// Line returns the number of the last line read.
+func (r *JSONLinesReader) Line() int { return r.line }
+
+// Property returns the named property of the last record read, if it is
+// present and not null.
+func (r *JSONLinesReader) Property(name string) (json.RawMessage, bool) {
+	v, ok := r.record[name]
+	if !ok || string(v) == "null" {
+		return nil, false
+	}
+	return v, true
+}
+
+// parse decodes a single record.
+func (r *JSONLinesReader) parse(buf []byte) (pilosa.Bit, error) {
+	var bit pilosa.Bit
+
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &record); err != nil {
+		return bit, fmt.Errorf("invalid JSON on line %d: %s", r.line, err)
+	}
+	r.record = record
+
+	var err error
+	if bit.RowID, bit.RowKey, err = r.parseID(record, r.format.RowField, r.format.RowKeys); err != nil {
+		return bit, err
+	}
+	if bit.ColumnID, bit.ColumnKey, err = r.parseID(record, r.format.ColumnField, r.format.ColumnKeys); err != nil {
+		return bit, err
+	}
+
+	if v, ok := record[r.format.TimeField]; ok && string(v) != "null" {
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return bit, fmt.Errorf("invalid %s on line %d: %s", r.format.TimeField, r.line, v)
+		}
+		t, err := time.Parse(pilosa.TimeFormat, s)
+		if err != nil {
+			return bit, fmt.Errorf("invalid %s on line %d: %q", r.format.TimeField, r.line, s)
+		}
+		bit.Timestamp = t.UnixNano()
+	}
+	return bit, nil
+}
+
+// parseID returns the required ID or key in the named property of record.
+func (r *JSONLinesReader) parseID(record map[string]json.RawMessage, name string, keys bool) (uint64, string, error) {
+	v, ok := record[name]
+	if !ok || string(v) == "null" {
+		return 0, "", fmt.Errorf("missing %s on line %d", name, r.line)
+	}
+
+	if keys {
+		var key string
+		if err := json.Unmarshal(v, &key); err != nil || key == "" {
+			return 0, "", fmt.Errorf("invalid %s on line %d: expected a string key, got %s", name, r.line, v)
+		}
+		return 0, key, nil
+	}
+
+	id, err := strconv.ParseUint(string(v), 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid %s on line %d: expected an unsigned integer, got %s", name, r.line, v)
+	}
+	return id, "", nil
+}
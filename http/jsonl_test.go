@@ -0,0 +1,102 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/http"
+)
+
+func TestJSONLinesReader(t *testing.T) {
+	readAll := func(r *http.JSONLinesReader) ([]pilosa.Bit, error) {
+		var bits []pilosa.Bit
+		for {
+			bit, err := r.Read()
+			if err == io.EOF {
+				return bits, nil
+			} else if err != nil {
+				return bits, err
+			}
+			bits = append(bits, bit)
+		}
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		r := http.NewJSONLinesReader(strings.NewReader(
+			`{"bitmapID": 1, "profileID": 2, "extra": {"a": [1]}}`+"\n\n"+
+				`{"profileID": 4, "bitmapID": 3, "timestamp": "2018-01-02T03:04"}`), http.JSONLinesFormat{})
+		bits, err := readAll(r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(bits, []pilosa.Bit{
+			{RowID: 1, ColumnID: 2},
+			{RowID: 3, ColumnID: 4, Timestamp: time.Date(2018, 1, 2, 3, 4, 0, 0, time.UTC).UnixNano()},
+		}) {
+			t.Fatalf("unexpected bits: %+v", bits)
+		} else if r.Line() != 3 {
+			t.Fatalf("unexpected line: %d", r.Line())
+		} else if _, ok := r.Property("extra"); ok {
+			t.Fatal("expected no extra property on the last record")
+		}
+	})
+
+	t.Run("Mapping", func(t *testing.T) {
+		r := http.NewJSONLinesReader(strings.NewReader(`{"tag": "a", "user": "u1", "at": "2018-01-02T03:04"}`), http.JSONLinesFormat{
+			RowField:    "tag",
+			ColumnField: "user",
+			TimeField:   "at",
+			RowKeys:     true,
+			ColumnKeys:  true,
+		})
+		bits, err := readAll(r)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(bits, []pilosa.Bit{
+			{RowKey: "a", ColumnKey: "u1", Timestamp: time.Date(2018, 1, 2, 3, 4, 0, 0, time.UTC).UnixNano()},
+		}) {
+			t.Fatalf("unexpected bits: %+v", bits)
+		} else if v, ok := r.Property("user"); !ok || string(v) != `"u1"` {
+			t.Fatalf("unexpected user property: %s", v)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			data string
+			err  string
+			n    int
+		}{
+			{data: `{"bitmapID": 1, "profileID": 2}` + "\n" + `{"bitmapID": 1}`, err: "missing profileID on line 2", n: 1},
+			{data: "\n" + `{"profileID": 2, "bitmapID": null}`, err: "missing bitmapID on line 2"},
+			{data: `{"bitmapID": -1, "profileID": 2}`, err: "invalid bitmapID on line 1: expected an unsigned integer, got -1"},
+			{data: `{"bitmapID": "a", "profileID": 2}`, err: "invalid bitmapID on line 1"},
+			{data: `{"bitmapID": 1, "profileID": 2, "timestamp": "2018"}`, err: `invalid timestamp on line 1: "2018"`},
+			{data: `{"bitmapID": 1,`, err: "invalid JSON on line 1"},
+		} {
+			bits, err := readAll(http.NewJSONLinesReader(strings.NewReader(tt.data), http.JSONLinesFormat{}))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Fatalf("%s: expected error %q, got %v", tt.data, tt.err, err)
+			} else if len(bits) != tt.n {
+				t.Fatalf("%s: expected %d bits before the error, got %+v", tt.data, tt.n, bits)
+			}
+		}
+	})
+}
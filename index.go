@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -38,21 +39,82 @@ type Index struct {
 	name string
 	keys bool // use string keys
 
+	// Number of column IDs in each shard. Zero is ShardWidth.
+	shardWidth uint64
+
+	// Name hashed to place shards on nodes. Empty is the index's name.
+	partitionKey string
+
 	// Existence tracking.
 	trackExistence bool
 	existenceFld   *Field
 
+	// Time quantum of time fields created without one.
+	timeQuantum TimeQuantum
+
+	// Name queries may use for the column argument.
+	columnLabel string
+
 	// Fields by name.
 	fields map[string]*Field
 
 	newAttrStore func(string) AttrStore
 
+	// Limits the number of fields opened concurrently, shared by all
+	// indexes opened by a holder. Fields are opened one at a time if nil.
+	openLimit chan struct{}
+
+	// Opens fields whose names break the naming rules, as long as they are
+	// usable as directory names, instead of skipping them.
+	allowLegacyNames bool
+
+	// Limits on the number of fields which can be created in the index,
+	// and views in each field. Zero is unlimited.
+	maxFields int
+	maxViews  int
+
+	// Determines which writes of the index's fragments are synced.
+	durability Durability
+	maxOpN     int
+
+	// How long closing a fragment waits for its readers.
+	fragmentCloseTimeout time.Duration
+
+	// Number of fragments of a view opened at once.
+	fragmentOpenConcurrency int
+
+	// What opening a view does with fragments which fail to open.
+	fragmentOpenPolicy FragmentOpenPolicy
+
+	// Layout of the fragments of new views.
+	fragmentLayout FragmentLayout
+
+	// Opens the index's files without modifying them.
+	readOnly bool
+
 	// Column attribute storage and cache.
 	columnAttrs AttrStore
 
 	broadcaster broadcaster
 	Stats       stats.StatsClient
 
+	// Accounts for cache memory across the holder.
+	cacheAccountant *cacheAccountant
+
+	// Rebuilds caches after imports.
+	cacheRebuilder *cacheRebuilder
+
+	// Snapshots fragments whose op logs are full.
+	snapshotQueue *snapshotQueue
+
+	// Bumped when a field is created or deleted.
+	schemaGen *schemaGeneration
+
+	// Highest column ID ever set in the index, and the value last written
+	// to the meta file.
+	maxColumnID      maxID
+	savedMaxColumnID uint64
+
 	logger logger.Logger
 }
 
@@ -63,6 +125,11 @@ func NewIndex(path, name string) (*Index, error) {
 		return nil, errors.Wrap(err, "validating name")
 	}
 
+	return newIndexUnchecked(path, name), nil
+}
+
+// newIndexUnchecked returns a new instance of Index (without name validation).
+func newIndexUnchecked(path, name string) *Index {
 	return &Index{
 		path:   path,
 		name:   name,
@@ -75,7 +142,7 @@ func NewIndex(path, name string) (*Index, error) {
 		Stats:          stats.NopStatsClient,
 		logger:         logger.NopLogger,
 		trackExistence: true,
-	}, nil
+	}
 }
 
 // Name returns name of the index.
@@ -87,6 +154,41 @@ func (i *Index) Path() string { return i.path }
 // Keys returns true if the index uses string keys.
 func (i *Index) Keys() bool { return i.keys }
 
+// ShardWidth returns the number of column IDs in each shard of the index.
+func (i *Index) ShardWidth() uint64 {
+	if i.shardWidth == 0 {
+		return ShardWidth
+	}
+	return i.shardWidth
+}
+
+// PartitionKey returns the name hashed to place the shards of the index on
+// nodes. It's the name the index was created with, so renaming an index
+// doesn't move its shards.
+func (i *Index) PartitionKey() string {
+	if i.partitionKey == "" {
+		return i.name
+	}
+	return i.partitionKey
+}
+
+// IndexShardWidth returns the number of column IDs in each shard of the
+// index stored at path, from its meta file.
+func IndexShardWidth(path string) (uint64, error) {
+	var pb internal.IndexMeta
+	buf, err := ioutil.ReadFile(filepath.Join(path, ".meta"))
+	if os.IsNotExist(err) {
+		return ShardWidth, nil
+	} else if err != nil {
+		return 0, errors.Wrap(err, "reading")
+	} else if err := proto.Unmarshal(buf, &pb); err != nil {
+		return 0, errors.Wrap(err, "unmarshalling")
+	} else if pb.ShardWidth == 0 {
+		return ShardWidth, nil
+	}
+	return pb.ShardWidth, nil
+}
+
 // ColumnAttrStore returns the storage for column attributes.
 func (i *Index) ColumnAttrStore() AttrStore { return i.columnAttrs }
 
@@ -101,14 +203,44 @@ func (i *Index) options() IndexOptions {
 	return IndexOptions{
 		Keys:           i.keys,
 		TrackExistence: i.trackExistence,
+		TimeQuantum:    i.timeQuantum,
+		ColumnLabel:    i.columnLabel,
+		ShardWidth:     i.shardWidth,
+		PartitionKey:   i.partitionKey,
 	}
 }
 
+// ColumnLabel returns the name queries may use for the column argument.
+func (i *Index) ColumnLabel() string { return i.columnLabel }
+
+// TimeQuantum returns the default time quantum of the index's time fields.
+func (i *Index) TimeQuantum() TimeQuantum {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.timeQuantum
+}
+
+// setTimeQuantum sets the default time quantum of the index's time fields.
+// Fields which already exist keep their own quantum.
+func (i *Index) setTimeQuantum(q TimeQuantum) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !q.Valid() {
+		return ErrInvalidTimeQuantum
+	}
+	i.timeQuantum = q
+
+	return i.saveMeta()
+}
+
 // Open opens and initializes the index.
 func (i *Index) Open() error {
-	// Ensure the path exists.
-	if err := os.MkdirAll(i.path, 0777); err != nil {
-		return errors.Wrap(err, "creating directory")
+	// Ensure the path exists, unless the index is read-only.
+	if !i.readOnly {
+		if err := os.MkdirAll(i.path, 0777); err != nil {
+			return errors.Wrap(err, "creating directory")
+		}
 	}
 
 	// Read meta file.
@@ -126,6 +258,10 @@ func (i *Index) Open() error {
 		}
 	}
 
+	// Columns set before the max column ID was last persisted are already
+	// covered by the meta file, but data may be more recent.
+	i.openMaxColumnID()
+
 	if err := i.columnAttrs.Open(); err != nil {
 		return errors.Wrap(err, "opening attrstore")
 	}
@@ -146,25 +282,91 @@ func (i *Index) openFields() error {
 		return errors.Wrap(err, "reading directory")
 	}
 
+	// Create every field before opening any, skipping invalid names.
+	var fields []*Field
 	for _, fi := range fis {
 		if !fi.IsDir() {
 			continue
 		}
 
-		fld, err := i.newField(i.fieldPath(filepath.Base(fi.Name())), filepath.Base(fi.Name()))
+		name := filepath.Base(fi.Name())
+		if err := ValidateFieldName(name); err != nil && name != existenceFieldName {
+			if !i.allowLegacyNames || !isLegacyName(name) {
+				i.logger.Errorf("opening field: index=%s, field=%s, err=%s", i.name, name, err)
+				continue
+			}
+			i.logger.Printf("WARNING opening field with legacy name: index=%s, field=%s, err=%s", i.name, name, err)
+		}
+
+		fld, err := i.newField(i.fieldPath(name), name)
 		if err != nil {
 			return ErrName
 		}
-		if err := fld.Open(); err != nil {
-			return fmt.Errorf("open field: name=%s, err=%s", fld.Name(), err)
+		fields = append(fields, fld)
+	}
+
+	// Open fields concurrently, up to the capacity of the limit. Each field
+	// opens its views and their fragments in turn.
+	limit := i.openLimit
+	if limit == nil {
+		limit = make(chan struct{}, 1)
+	}
+	errs := make([]error, len(fields))
+	var wg sync.WaitGroup
+	for j, fld := range fields {
+		limit <- struct{}{}
+		wg.Add(1)
+		go func(j int, fld *Field) {
+			defer func() { <-limit; wg.Done() }()
+			if err := fld.Open(); err != nil {
+				errs[j] = fmt.Errorf("open field: name=%s, err=%s", fld.Name(), err)
+			}
+		}(j, fld)
+	}
+	wg.Wait()
+
+	var errList roaring.ErrorList
+	for j, fld := range fields {
+		if errs[j] != nil {
+			errList.Append(errs[j])
+			continue
 		}
 		i.fields[fld.Name()] = fld
 	}
+	if len(errList) > 0 {
+		return errList
+	}
 	return nil
 }
 
+// openMaxColumnID raises the max column ID to cover the data in the highest
+// shard of every view.
+func (i *Index) openMaxColumnID() {
+	for _, f := range i.fields {
+		for _, v := range f.views() {
+			var last *fragment
+			for _, frag := range v.allFragments() {
+				if last == nil || frag.shard > last.shard {
+					last = frag
+				}
+			}
+			if last == nil {
+				continue
+			}
+			if columnID, ok := last.maxColumn(); ok {
+				i.maxColumnID.observe(columnID)
+			}
+		}
+	}
+}
+
 // openExistenceField gets or creates the existence field and associates it to the index.
+// Read-only indexes only use the existence field if it was already created.
 func (i *Index) openExistenceField() error {
+	if i.readOnly {
+		i.existenceFld = i.fields[existenceFieldName]
+		return nil
+	}
 	f, err := i.createFieldIfNotExists(existenceFieldName, FieldOptions{CacheType: CacheTypeNone, CacheSize: 0})
 	if err != nil {
 		return errors.Wrap(err, "creating existence field")
@@ -192,46 +394,96 @@ func (i *Index) loadMeta() error {
 	// Copy metadata fields.
 	i.keys = pb.Keys
 	i.trackExistence = pb.TrackExistence
+	i.timeQuantum = TimeQuantum(pb.TimeQuantum)
+	i.columnLabel = pb.ColumnLabel
+	i.shardWidth = pb.ShardWidth
+	i.partitionKey = pb.PartitionKey
+	i.maxColumnID.observe(pb.MaxColumnID)
+	i.savedMaxColumnID = pb.MaxColumnID
 
 	return nil
 }
 
 // saveMeta writes meta data for the index.
 func (i *Index) saveMeta() error {
+	if i.readOnly {
+		return newForbiddenError(ErrReadOnly)
+	}
+
 	// Marshal metadata.
+	maxColumnID := i.maxColumnID.value()
 	buf, err := proto.Marshal(&internal.IndexMeta{
 		Keys:           i.keys,
 		TrackExistence: i.trackExistence,
+		MaxColumnID:    maxColumnID,
+		TimeQuantum:    string(i.timeQuantum),
+		ColumnLabel:    i.columnLabel,
+		ShardWidth:     i.shardWidth,
+		PartitionKey:   i.partitionKey,
 	})
 	if err != nil {
 		return errors.Wrap(err, "marshalling")
 	}
 
 	// Write to meta file.
-	if err := ioutil.WriteFile(filepath.Join(i.path, ".meta"), buf, 0666); err != nil {
+	if err := writeMetaFile(filepath.Join(i.path, ".meta"), buf, 0666); err != nil {
 		return errors.Wrap(err, "writing")
 	}
+	i.savedMaxColumnID = maxColumnID
+
+	return nil
+}
+
+// MaxColumnID returns the highest column ID ever set in the index, including
+// columns that have since been cleared.
+func (i *Index) MaxColumnID() uint64 { return i.maxColumnID.value() }
 
+// saveMaxIDs writes the meta files of the index and its fields if their max
+// IDs have increased since they were last written.
+func (i *Index) saveMaxIDs() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := i.unprotectedSaveMaxColumnID(); err != nil {
+		return errors.Wrap(err, "saving max column id")
+	}
+	for _, f := range i.fields {
+		if err := f.saveMaxRowID(); err != nil {
+			return errors.Wrapf(err, "saving max row id: field=%s", f.Name())
+		}
+	}
 	return nil
 }
 
+func (i *Index) unprotectedSaveMaxColumnID() error {
+	if i.readOnly || i.maxColumnID.value() == i.savedMaxColumnID {
+		return nil
+	}
+	return i.saveMeta()
+}
+
 // Close closes the index and its fields.
 func (i *Index) Close() error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	// Close the attribute store.
-	i.columnAttrs.Close()
+	var errs closeErrors
+	errs.append(i.unprotectedSaveMaxColumnID(), "index=%s: saving max column id", i.name)
 
-	// Close all fields.
-	for _, f := range i.fields {
-		if err := f.Close(); err != nil {
-			return errors.Wrap(err, "closing field")
-		}
+	// Close all fields, in name order, then the attribute store.
+	names := make([]string, 0, len(i.fields))
+	for name := range i.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs.append(i.fields[name].Close(), "index=%s field=%s", i.name, name)
 	}
 	i.fields = make(map[string]*Field)
 
-	return nil
+	errs.append(i.columnAttrs.Close(), "index=%s: closing column attr store", i.name)
+
+	return errs.err()
 }
 
 // AvailableShards returns a bitmap of all shards with data in the index.
@@ -278,6 +530,22 @@ func (i *Index) Fields() []*Field {
 	return a
 }
 
+// fieldCount returns the number of fields in the index, other than the
+// existence field.
+func (i *Index) fieldCount() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.unprotectedFieldCount()
+}
+
+func (i *Index) unprotectedFieldCount() int {
+	n := len(i.fields)
+	if i.fields[existenceFieldName] != nil {
+		n--
+	}
+	return n
+}
+
 // existenceField returns the internal field used to track column existence.
 func (i *Index) existenceField() *Field {
 	i.mu.RLock()
@@ -293,9 +561,40 @@ func (i *Index) recalculateCaches() {
 	}
 }
 
+// rebuildCaches rebuilds the caches of every field in the index from storage
+// in the background.
+func (i *Index) rebuildCaches() error {
+	for _, field := range i.Fields() {
+		if err := field.rebuildCaches(); err != nil {
+			return errors.Wrapf(err, "field %s", field.Name())
+		}
+	}
+	return nil
+}
+
+// DeleteColumnRange deletes the columns in [min, max] from every field of the
+// index on this node, along with their attributes.
+func (i *Index) DeleteColumnRange(min, max uint64) error {
+	if i.readOnly {
+		return newForbiddenError(ErrReadOnly)
+	} else if min > max {
+		return NewBadRequestError(errors.Errorf("invalid column range: %d > %d", min, max))
+	}
+
+	for _, field := range i.Fields() {
+		if err := field.clearColumnRange(min, max); err != nil {
+			return errors.Wrapf(err, "clearing field %s", field.Name())
+		}
+	}
+	if err := i.columnAttrs.DeleteRange(min, max); err != nil {
+		return errors.Wrap(err, "deleting column attrs")
+	}
+	return nil
+}
+
 // CreateField creates a field.
 func (i *Index) CreateField(name string, opts ...FieldOption) (*Field, error) {
-	err := validateName(name)
+	err := ValidateFieldName(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "validating name")
 	}
@@ -322,7 +621,7 @@ func (i *Index) CreateField(name string, opts ...FieldOption) (*Field, error) {
 
 // CreateFieldIfNotExists creates a field with the given options if it doesn't exist.
 func (i *Index) CreateFieldIfNotExists(name string, opts ...FieldOption) (*Field, error) {
-	err := validateName(name)
+	err := ValidateFieldName(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "validating name")
 	}
@@ -362,8 +661,20 @@ func (i *Index) createFieldIfNotExists(name string, opt FieldOptions) (*Field, e
 func (i *Index) createField(name string, opt FieldOptions) (*Field, error) {
 	if name == "" {
 		return nil, errors.New("field name required")
+	} else if i.readOnly {
+		return nil, newForbiddenError(ErrReadOnly)
 	} else if opt.CacheType != "" && !isValidCacheType(opt.CacheType) {
 		return nil, ErrInvalidCacheType
+	} else if name != existenceFieldName && i.maxFields > 0 && i.unprotectedFieldCount() >= i.maxFields {
+		return nil, NewBadRequestError(errors.Wrapf(ErrTooManyFields, "limit %d", i.maxFields))
+	} else if err := i.validateFieldLabels(name, opt); err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	// Time fields created without a quantum inherit the index's.
+	if opt.Type == FieldTypeTime && opt.TimeQuantum == "" && i.timeQuantum != "" {
+		opt.TimeQuantum = i.timeQuantum
+		opt.TimeQuantumInherited = true
 	}
 
 	// Initialize field.
@@ -390,10 +701,63 @@ func (i *Index) createField(name string, opt FieldOptions) (*Field, error) {
 
 	// Add to index's field lookup.
 	i.fields[name] = f
+	i.schemaGen.bump()
 
 	return f, nil
 }
 
+// validateFieldLabels returns an error if a new field's name or row label
+// would be confused with the labels or names already used in the index.
+func (i *Index) validateFieldLabels(name string, opt FieldOptions) error {
+	if name == i.columnLabel {
+		return labelError{name, "is the column label of the index"}
+	}
+	for _, f := range i.fields {
+		if f.options.RowLabel == name {
+			return labelError{name, fmt.Sprintf("is the row label of field %s", f.name)}
+		}
+	}
+
+	if opt.RowLabel == "" {
+		return nil
+	}
+	switch opt.Type {
+	case FieldTypeInt, FieldTypeBool:
+		return errors.Errorf("rowLabel does not apply to field type %s", opt.Type)
+	}
+	if opt.RowLabel == i.columnLabel {
+		return labelError{opt.RowLabel, "is the column label of the index"}
+	} else if opt.RowLabel == name || i.fields[opt.RowLabel] != nil {
+		return labelError{opt.RowLabel, "is the name of a field"}
+	}
+	return nil
+}
+
+// fieldByRowLabel returns the field whose rows are labelled label. If more
+// than one field uses the label, the one named by fieldName is returned.
+func (i *Index) fieldByRowLabel(label, fieldName string) (*Field, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if f := i.fields[fieldName]; f != nil && f.options.RowLabel == label {
+		return f, nil
+	}
+	var found *Field
+	for _, f := range i.fields {
+		if f.options.RowLabel != label {
+			continue
+		} else if found != nil {
+			a, b := found.name, f.name
+			if a > b {
+				a, b = b, a
+			}
+			return nil, errors.Errorf("row label %s is used by fields %s and %s, specify field=", label, a, b)
+		}
+		found = f
+	}
+	return found, nil
+}
+
 func (i *Index) newField(path, name string) (*Field, error) {
 	f, err := newField(path, i.name, name, OptFieldTypeDefault())
 	if err != nil {
@@ -402,6 +766,19 @@ func (i *Index) newField(path, name string) (*Field, error) {
 	f.logger = i.logger
 	f.Stats = i.Stats.WithTags(fmt.Sprintf("field:%s", name))
 	f.broadcaster = i.broadcaster
+	f.cacheAccountant = i.cacheAccountant
+	f.cacheRebuilder = i.cacheRebuilder
+	f.snapshotQueue = i.snapshotQueue
+	f.maxColumnID = &i.maxColumnID
+	f.shardWidth = i.ShardWidth()
+	f.maxViews = i.maxViews
+	f.durability = i.durability
+	f.maxOpN = i.maxOpN
+	f.fragmentCloseTimeout = i.fragmentCloseTimeout
+	f.fragmentOpenConcurrency = i.fragmentOpenConcurrency
+	f.fragmentOpenPolicy = i.fragmentOpenPolicy
+	f.readOnly = i.readOnly
+	f.fragmentLayout = i.fragmentLayout
 	f.rowAttrStore = i.newAttrStore(filepath.Join(f.path, ".data"))
 	return f, nil
 }
@@ -415,17 +792,25 @@ func (i *Index) DeleteField(name string) error {
 	f := i.field(name)
 	if f == nil {
 		return newNotFoundError(ErrFieldNotFound)
+	} else if i.readOnly {
+		return newForbiddenError(ErrReadOnly)
 	}
 
-	// Close field.
+	// Remove reference first so the field can't be found half closed.
+	delete(i.fields, name)
+
+	// Close field, including its row attribute store.
 	if err := f.Close(); err != nil {
 		return errors.Wrap(err, "closing")
 	}
 
-	// Delete field directory.
+	// Delete field directory, which holds its fragments, caches and
+	// attributes.
 	if err := os.RemoveAll(i.fieldPath(name)); err != nil {
 		return errors.Wrap(err, "removing directory")
 	}
+	i.schemaGen.bump()
+	i.logger.Printf("deleted field: index=%s, field=%s", i.name, name)
 
 	// If the field being deleted is the existence field,
 	// turn off existence tracking on the index.
@@ -439,12 +824,108 @@ func (i *Index) DeleteField(name string) error {
 		}
 	}
 
-	// Remove reference.
+	return nil
+}
+
+// renameField renames a field. The field is closed, its directory and the
+// view named for it moved, and it is reopened under the new name. If it
+// can't be reopened everything is moved back and the field reopened under
+// its old name.
+func (i *Index) renameField(name, newName string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	f, err := i.checkRenameField(name, newName)
+	if err != nil {
+		return err
+	}
+
+	// Remove reference first so the field can't be found half closed.
 	delete(i.fields, name)
+	if err := f.Close(); err != nil {
+		i.reopenField(name)
+		return errors.Wrap(err, "closing")
+	}
+
+	if err := i.moveField(name, newName); err != nil {
+		i.reopenField(name)
+		return err
+	}
+
+	renamed, err := i.newField(i.fieldPath(newName), newName)
+	if err == nil {
+		err = renamed.Open()
+	}
+	if err != nil {
+		if rerr := i.moveField(newName, name); rerr != nil {
+			i.logger.Errorf("restoring renamed field: index=%s, field=%s, err=%s", i.name, name, rerr)
+		} else {
+			i.reopenField(name)
+		}
+		return errors.Wrap(err, "opening")
+	}
+	i.fields[newName] = renamed
+	i.schemaGen.bump()
+	i.logger.Printf("renamed field: index=%s, field=%s to %s", i.name, name, newName)
 
 	return nil
 }
 
+// checkRenameField returns the field to be renamed, or an error if it can't
+// be. Keyed fields aren't renamed since their names also key their row
+// translation. The caller must hold the index lock.
+func (i *Index) checkRenameField(name, newName string) (*Field, error) {
+	f := i.field(name)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound)
+	} else if i.readOnly {
+		return nil, newForbiddenError(ErrReadOnly)
+	} else if name == existenceFieldName {
+		return nil, NewBadRequestError(errors.New("the existence field can't be renamed"))
+	} else if err := ValidateFieldName(newName); err != nil {
+		return nil, NewBadRequestError(errors.Wrap(err, "validating name"))
+	} else if i.field(newName) != nil {
+		return nil, newConflictError(ErrFieldExists)
+	} else if err := i.validateFieldLabels(newName, f.Options()); err != nil {
+		return nil, NewBadRequestError(err)
+	} else if f.keys() {
+		return nil, NewBadRequestError(errors.New("fields with keys can't be renamed"))
+	}
+	return f, nil
+}
+
+// moveField moves the directory of a closed field, and the view of an int
+// field, which is named for the field, to those of newName.
+func (i *Index) moveField(name, newName string) error {
+	if err := os.Rename(i.fieldPath(name), i.fieldPath(newName)); err != nil {
+		return errors.Wrap(err, "renaming directory")
+	}
+
+	viewsPath := filepath.Join(i.fieldPath(newName), "views")
+	err := os.Rename(filepath.Join(viewsPath, viewBSIGroupPrefix+name), filepath.Join(viewsPath, viewBSIGroupPrefix+newName))
+	if err != nil && !os.IsNotExist(err) {
+		if rerr := os.Rename(i.fieldPath(newName), i.fieldPath(name)); rerr != nil {
+			i.logger.Errorf("restoring renamed field directory: index=%s, field=%s, err=%s", i.name, name, rerr)
+		}
+		return errors.Wrap(err, "renaming view directory")
+	}
+	return nil
+}
+
+// reopenField opens the field name again after a failed rename. The caller
+// must hold the index lock.
+func (i *Index) reopenField(name string) {
+	f, err := i.newField(i.fieldPath(name), name)
+	if err == nil {
+		err = f.Open()
+	}
+	if err != nil {
+		i.logger.Errorf("reopening field: index=%s, field=%s, err=%s", i.name, name, err)
+		return
+	}
+	i.fields[name] = f
+}
+
 type indexSlice []*Index
 
 func (p indexSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -453,10 +934,11 @@ func (p indexSlice) Less(i, j int) bool { return p[i].Name() < p[j].Name() }
 
 // IndexInfo represents schema information for an index.
 type IndexInfo struct {
-	Name       string       `json:"name"`
-	Options    IndexOptions `json:"options"`
-	Fields     []*FieldInfo `json:"fields"`
-	ShardWidth uint64       `json:"shardWidth"`
+	Name        string       `json:"name"`
+	Options     IndexOptions `json:"options"`
+	Fields      []*FieldInfo `json:"fields"`
+	ShardWidth  uint64       `json:"shardWidth"`
+	MaxColumnID uint64       `json:"maxColumnID,omitempty"`
 }
 
 type indexInfoSlice []*IndexInfo
@@ -467,8 +949,45 @@ func (p indexInfoSlice) Less(i, j int) bool { return p[i].Name < p[j].Name }
 
 // IndexOptions represents options to set when initializing an index.
 type IndexOptions struct {
-	Keys           bool `json:"keys"`
-	TrackExistence bool `json:"trackExistence"`
+	Keys           bool        `json:"keys"`
+	TrackExistence bool        `json:"trackExistence"`
+	TimeQuantum    TimeQuantum `json:"timeQuantum,omitempty"`
+	ColumnLabel    string      `json:"columnLabel,omitempty"`
+	ShardWidth     uint64      `json:"shardWidth,omitempty"`
+	PartitionKey   string      `json:"partitionKey,omitempty"`
+}
+
+// validShardWidth returns true if w is zero, for the default shard width, or
+// a power of 2 between MinShardWidth and MaxShardWidth.
+func validShardWidth(w uint64) bool {
+	return w == 0 || (w >= MinShardWidth && w <= MaxShardWidth && w&(w-1) == 0)
+}
+
+// maxID is the highest of a set of IDs. It is safe for concurrent use and a
+// nil maxID ignores the IDs it observes.
+type maxID struct {
+	v uint64
+}
+
+// observe raises m to id if id is higher.
+func (m *maxID) observe(id uint64) {
+	if m == nil {
+		return
+	}
+	for {
+		v := atomic.LoadUint64(&m.v)
+		if id <= v || atomic.CompareAndSwapUint64(&m.v, v, id) {
+			return
+		}
+	}
+}
+
+// value returns the highest ID observed.
+func (m *maxID) value() uint64 {
+	if m == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&m.v)
 }
 
 // hasTime returns true if a contains a non-nil time.
@@ -16,6 +16,7 @@ package pilosa_test
 
 import (
 	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 
@@ -211,6 +212,223 @@ func TestIndex_DeleteField(t *testing.T) {
 	}
 }
 
+// Ensure a field recreated after deletion is empty.
+func TestIndex_DeleteFieldRecreate(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	hldr.SetBit("i", "f", 1, 2)
+	hldr.SetBit("i", "f", 1, ShardWidth+3)
+	index := hldr.Index("i")
+	if err := index.Field("f").RowAttrStore().SetAttrs(1, map[string]interface{}{"x": int64(1)}); err != nil {
+		t.Fatal(err)
+	}
+	path := index.Field("f").Path()
+
+	if err := index.DeleteField("f"); err != nil {
+		t.Fatal(err)
+	} else if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected field directory to be removed: %v", err)
+	}
+
+	f, err := index.CreateField("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := f.AvailableShards().Count(); n != 0 {
+		t.Fatalf("unexpected shards: %d", n)
+	} else if _, err := f.SetBit(2, 5, nil); err != nil {
+		t.Fatal(err)
+	} else if cols := hldr.Row("i", "f", 1).Columns(); len(cols) != 0 {
+		t.Fatalf("unexpected columns: %v", cols)
+	} else if attrs, err := f.RowAttrStore().Attrs(1); err != nil {
+		t.Fatal(err)
+	} else if len(attrs) != 0 {
+		t.Fatalf("unexpected attrs: %v", attrs)
+	}
+}
+
+// Ensure the max column and row IDs only increase, even across restarts.
+func TestIndex_MaxIDs(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	hldr.SetBit("i", "f", 3, 10)
+	index := hldr.Index("i")
+	if _, err := index.CreateField("n", pilosa.OptFieldTypeInt(0, 100)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.Field("f").Import([]uint64{7, 5}, []uint64{ShardWidth + 20, 2*ShardWidth + 4}, nil); err != nil {
+		t.Fatal(err)
+	} else if _, err := index.Field("n").SetValue(ShardWidth+30, 50); err != nil {
+		t.Fatal(err)
+	}
+	if id := index.MaxColumnID(); id != 2*ShardWidth+4 {
+		t.Fatalf("unexpected max column id: %d", id)
+	} else if id := index.Field("f").MaxRowID(); id != 7 {
+		t.Fatalf("unexpected max row id: %d", id)
+	} else if id := index.Field("n").MaxRowID(); id != 0 {
+		t.Fatalf("unexpected int field max row id: %d", id)
+	}
+
+	// Clear the highest row and column before reopening.
+	hldr.ClearBit("i", "f", 7, ShardWidth+20)
+	hldr.ClearBit("i", "f", 5, 2*ShardWidth+4)
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	index = hldr.Index("i")
+	if id := index.MaxColumnID(); id != 2*ShardWidth+4 {
+		t.Fatalf("unexpected max column id after reopen: %d", id)
+	} else if id := index.Field("f").MaxRowID(); id != 7 {
+		t.Fatalf("unexpected max row id after reopen: %d", id)
+	}
+
+	// Higher IDs still raise the maximums.
+	hldr.SetBit("i", "f", 9, 3*ShardWidth+1)
+	if id := index.MaxColumnID(); id != 3*ShardWidth+1 {
+		t.Fatalf("unexpected max column id after set: %d", id)
+	} else if id := index.Field("f").MaxRowID(); id != 9 {
+		t.Fatalf("unexpected max row id after set: %d", id)
+	}
+}
+
+// Ensure time fields created without a quantum inherit the index's.
+func TestIndex_TimeQuantum(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	index := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{TimeQuantum: "YMD"})
+	if _, err := index.CreateField("inherited", pilosa.OptFieldTypeTime("")); err != nil {
+		t.Fatal(err)
+	} else if _, err := index.CreateField("own", pilosa.OptFieldTypeTime("YM")); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(index *pilosa.Index) {
+		t.Helper()
+		if q := index.TimeQuantum(); q != "YMD" {
+			t.Fatalf("unexpected index time quantum: %s", q)
+		} else if o := index.Field("inherited").Options(); o.TimeQuantum != "YMD" || !o.TimeQuantumInherited {
+			t.Fatalf("unexpected inherited field options: %+v", o)
+		} else if o := index.Field("own").Options(); o.TimeQuantum != "YM" || o.TimeQuantumInherited {
+			t.Fatalf("unexpected field options: %+v", o)
+		}
+	}
+	check(index.Index)
+
+	// The default and whether it was inherited survive a reopen.
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check(hldr.Index("i"))
+}
+
+// Ensure an index's shard width splits its columns into shards and survives a
+// reopen.
+func TestIndex_ShardWidth(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	for _, w := range []uint64{1000, 1 << 15, 3 << 16, 1 << 33} {
+		if _, err := hldr.CreateIndex("x", pilosa.IndexOptions{ShardWidth: w}); !isBadRequestError(err) {
+			t.Fatalf("shard width %d: unexpected error: %v", w, err)
+		}
+	}
+
+	index := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{ShardWidth: 1 << 16})
+	f, err := index.CreateField("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, col := range []uint64{1, 70000, 1000000} {
+		if _, err := f.SetBit(1, col, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.ClearBit(1, 70000); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(index *pilosa.Index) {
+		t.Helper()
+		if w := index.ShardWidth(); w != 1<<16 {
+			t.Fatalf("unexpected shard width: %d", w)
+		} else if w := index.Options().ShardWidth; w != 1<<16 {
+			t.Fatalf("unexpected shard width option: %d", w)
+		} else if shards := index.AvailableShards().Slice(); !reflect.DeepEqual(shards, []uint64{0, 1, 15}) {
+			t.Fatalf("unexpected shards: %v", shards)
+		}
+		row, err := index.Field("f").Row(1)
+		if err != nil {
+			t.Fatal(err)
+		} else if cols := row.Columns(); !reflect.DeepEqual(cols, []uint64{1, 1000000}) {
+			t.Fatalf("unexpected columns: %v", cols)
+		}
+	}
+	check(index.Index)
+
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check(hldr.Index("i"))
+
+	// Indexes created without a shard width use the default.
+	if w := hldr.MustCreateIndexIfNotExists("j", pilosa.IndexOptions{}).ShardWidth(); w != ShardWidth {
+		t.Fatalf("unexpected default shard width: %d", w)
+	}
+}
+
+// Ensure row and column labels can't be confused with argument or field names.
+func TestIndex_Labels(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+
+	for _, label := range []string{"n", "field", "from", "_col", "1user"} {
+		if _, err := hldr.CreateIndex("x", pilosa.IndexOptions{ColumnLabel: label}); err == nil {
+			t.Fatalf("column label %q: expected error", label)
+		}
+	}
+
+	index := hldr.MustCreateIndexIfNotExists("i", pilosa.IndexOptions{ColumnLabel: "user"})
+	if _, err := index.CreateField("traffic", pilosa.OptFieldTypeDefault(), pilosa.OptFieldRowLabel("site")); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		opts []pilosa.FieldOption
+	}{
+		{name: "user"},
+		{name: "site"},
+		{name: "f", opts: []pilosa.FieldOption{pilosa.OptFieldRowLabel("limit")}},
+		{name: "f", opts: []pilosa.FieldOption{pilosa.OptFieldRowLabel("user")}},
+		{name: "f", opts: []pilosa.FieldOption{pilosa.OptFieldRowLabel("traffic")}},
+		{name: "f", opts: []pilosa.FieldOption{pilosa.OptFieldRowLabel("f")}},
+		{name: "f", opts: []pilosa.FieldOption{pilosa.OptFieldTypeInt(0, 10), pilosa.OptFieldRowLabel("value")}},
+	} {
+		if _, err := index.CreateField(tt.name, tt.opts...); err == nil {
+			t.Fatalf("field %s: expected error", tt.name)
+		}
+	}
+
+	// Labels survive a reopen.
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := hldr.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if label := hldr.Index("i").ColumnLabel(); label != "user" {
+		t.Fatalf("unexpected column label: %q", label)
+	} else if label := hldr.Field("i", "traffic").RowLabel(); label != "site" {
+		t.Fatalf("unexpected row label: %q", label)
+	}
+}
+
 // Ensure index can validate its name.
 func TestIndex_InvalidName(t *testing.T) {
 	path, err := ioutil.TempDir("", "pilosa-index-")
@@ -226,6 +444,11 @@ func TestIndex_InvalidName(t *testing.T) {
 	}
 }
 
+func isBadRequestError(err error) bool {
+	_, ok := errors.Cause(err).(pilosa.BadRequestError)
+	return ok
+}
+
 func isNotFoundError(err error) bool {
 	root := errors.Cause(err)
 	_, ok := root.(pilosa.NotFoundError)
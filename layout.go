@@ -0,0 +1,251 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// FragmentLayout determines where the files of a view's fragments are kept
+// under its fragments directory.
+//
+//	flat      fragments/<shard>
+//	sharded   fragments/<shard % 256>/<shard>
+//
+// The subdirectories of the sharded layout are named with three digits, 000
+// to 255, so they never clash with the data file of a shard in the flat
+// layout. The sharded layout keeps fewer files in each directory for views
+// with very many shards. Each view records its layout when it is created.
+type FragmentLayout string
+
+// Fragment layouts.
+const (
+	FragmentLayoutFlat    FragmentLayout = "flat"
+	FragmentLayoutSharded FragmentLayout = "sharded"
+)
+
+// fragmentDirN is the number of subdirectories of the sharded layout.
+const fragmentDirN = 256
+
+// ParseFragmentLayout returns the named fragment layout. An empty name is the
+// flat layout.
+func ParseFragmentLayout(s string) (FragmentLayout, error) {
+	switch l := FragmentLayout(s); l {
+	case "":
+		return FragmentLayoutFlat, nil
+	case FragmentLayoutFlat, FragmentLayoutSharded:
+		return l, nil
+	default:
+		return "", errors.Errorf("invalid fragment layout %q, must be flat or sharded", s)
+	}
+}
+
+// fragmentPath returns the path of a fragment's data file under the view at
+// viewPath.
+func (l FragmentLayout) fragmentPath(viewPath string, shard uint64) string {
+	name := strconv.FormatUint(shard, 10)
+	if l == FragmentLayoutSharded {
+		return filepath.Join(viewPath, "fragments", fmt.Sprintf("%03d", shard%fragmentDirN), name)
+	}
+	return filepath.Join(viewPath, "fragments", name)
+}
+
+// viewMeta is the metadata of a view, kept in the .meta file of its
+// directory.
+type viewMeta struct {
+	FragmentLayout FragmentLayout `json:"fragmentLayout"`
+}
+
+// readViewLayout returns the layout recorded for the view at viewPath. Views
+// created before layouts were recorded have none.
+func readViewLayout(viewPath string) (FragmentLayout, bool, error) {
+	buf, err := ioutil.ReadFile(filepath.Join(viewPath, ".meta"))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, errors.Wrap(err, "reading")
+	}
+
+	var meta viewMeta
+	if err := json.Unmarshal(buf, &meta); err != nil {
+		return "", false, errors.Wrap(err, "unmarshaling")
+	}
+	l, err := ParseFragmentLayout(string(meta.FragmentLayout))
+	if err != nil {
+		return "", false, err
+	}
+	return l, true, nil
+}
+
+// writeViewLayout records the layout of the view at viewPath.
+func writeViewLayout(viewPath string, l FragmentLayout) error {
+	buf, err := json.Marshal(viewMeta{FragmentLayout: l})
+	if err != nil {
+		return errors.Wrap(err, "marshaling")
+	}
+	return writeMetaFile(filepath.Join(viewPath, ".meta"), buf, 0666)
+}
+
+// fragmentFiles returns the paths of the data files of the fragments under
+// the view at viewPath, in either layout, by shard.
+func fragmentFiles(viewPath string) (map[uint64]string, error) {
+	dir := filepath.Join(viewPath, "fragments")
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	files := make(map[uint64]string)
+	add := func(dir, name string) error {
+		shard, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			return nil
+		} else if path, ok := files[shard]; ok {
+			return errors.Errorf("shard %d is in both %s and %s", shard, path, dir)
+		}
+		files[shard] = filepath.Join(dir, name)
+		return nil
+	}
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			if err := add(dir, fi.Name()); err != nil {
+				return nil, err
+			}
+			continue
+		} else if _, err := strconv.ParseUint(fi.Name(), 10, 64); err != nil || len(fi.Name()) != 3 {
+			continue
+		}
+
+		sub := filepath.Join(dir, fi.Name())
+		subFis, err := ioutil.ReadDir(sub)
+		if err != nil {
+			return nil, err
+		}
+		for _, subFi := range subFis {
+			if subFi.IsDir() {
+				continue
+			} else if err := add(sub, subFi.Name()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// detectFragmentLayout returns the layout of the fragments on disk under the
+// view at viewPath. A view without fragments has no layout.
+func detectFragmentLayout(viewPath string) (FragmentLayout, bool, error) {
+	files, err := fragmentFiles(viewPath)
+	if err != nil {
+		return "", false, err
+	}
+	for shard, path := range files {
+		if path == FragmentLayoutSharded.fragmentPath(viewPath, shard) {
+			return FragmentLayoutSharded, true, nil
+		}
+		return FragmentLayoutFlat, true, nil
+	}
+	return "", false, nil
+}
+
+// migrateViewLayout records l as the layout of the view at viewPath and moves
+// any fragments which aren't already in it, returning the number moved. The
+// layout is recorded before anything is moved and each file is renamed into
+// place, so a migration interrupted at any point is finished by running it
+// again. The view must not be open.
+func migrateViewLayout(viewPath string, l FragmentLayout) (int, error) {
+	if cur, ok, err := readViewLayout(viewPath); err != nil {
+		return 0, errors.Wrap(err, "reading layout")
+	} else if !ok || cur != l {
+		if err := writeViewLayout(viewPath, l); err != nil {
+			return 0, errors.Wrap(err, "writing layout")
+		}
+	}
+
+	files, err := fragmentFiles(viewPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "listing fragments")
+	}
+	var n int
+	for shard, path := range files {
+		dst := l.fragmentPath(viewPath, shard)
+		if path == dst {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			return n, errors.Wrap(err, "creating directory")
+		}
+
+		// Move the cache first, so the data file is only moved once its
+		// cache is in place.
+		if err := os.Rename(path+cacheExt, dst+cacheExt); err != nil && !os.IsNotExist(err) {
+			return n, errors.Wrap(err, "moving cache")
+		} else if err := os.Rename(path, dst); err != nil {
+			return n, errors.Wrap(err, "moving fragment")
+		}
+		n++
+	}
+
+	// Remove the subdirectories emptied by moving to the flat layout. Those
+	// which aren't empty are left alone.
+	if l == FragmentLayoutFlat && n > 0 {
+		for i := 0; i < fragmentDirN; i++ {
+			_ = os.Remove(filepath.Join(viewPath, "fragments", fmt.Sprintf("%03d", i)))
+		}
+	}
+	return n, nil
+}
+
+// MigrateFragmentLayout moves the fragments of every view under the data
+// directory at path to layout l, returning the number of views and fragments
+// migrated. It can be run again to finish an interrupted migration. Pilosa
+// must not be running on the data directory.
+func MigrateFragmentLayout(path string, l FragmentLayout) (views, fragments int, err error) {
+	indexes, err := readDirs(path)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "reading indexes")
+	}
+	for _, index := range indexes {
+		fields, err := readDirs(filepath.Join(path, index))
+		if err != nil {
+			return views, fragments, errors.Wrap(err, "reading fields")
+		}
+		for _, field := range fields {
+			viewsPath := filepath.Join(path, index, field, "views")
+			names, err := readDirs(viewsPath)
+			if err != nil {
+				return views, fragments, errors.Wrap(err, "reading views")
+			}
+			for _, name := range names {
+				n, err := migrateViewLayout(filepath.Join(viewsPath, name), l)
+				if err != nil {
+					return views, fragments, errors.Wrapf(err, "migrating index=%s field=%s view=%s", index, field, name)
+				}
+				views++
+				fragments += n
+			}
+		}
+	}
+	return views, fragments, nil
+}
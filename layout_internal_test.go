@@ -0,0 +1,135 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Ensure a view created with the sharded layout keeps it when reopened with
+// another configured layout.
+func TestView_FragmentLayout(t *testing.T) {
+	v := mustOpenView("i", "f", "v")
+	defer os.RemoveAll(v.path)
+	v.close()
+	v.fragmentLayout = FragmentLayoutSharded
+	if err := os.RemoveAll(v.path); err != nil {
+		t.Fatal(err)
+	} else if err := v.open(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, shard := range []uint64{1, 257} {
+		frag, err := v.CreateFragmentIfNotExists(shard)
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err := frag.setBit(1, shard*ShardWidth); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := v.close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"1", "257"} {
+		if _, err := os.Stat(filepath.Join(v.path, "fragments", "001", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v = newView(v.path, "i", "f", "v", FieldOptions{CacheType: DefaultCacheType, CacheSize: DefaultCacheSize})
+	if err := v.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer v.close()
+	if v.fragmentLayout != FragmentLayoutSharded {
+		t.Fatalf("unexpected layout: %s", v.fragmentLayout)
+	} else if frag := v.Fragment(257); frag == nil {
+		t.Fatal("expected fragment")
+	} else if cols := frag.row(1).Columns(); !reflect.DeepEqual(cols, []uint64{257 * ShardWidth}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+}
+
+// Ensure fragments are moved between layouts, and an interrupted migration
+// is finished when the view is opened.
+func TestMigrateFragmentLayout(t *testing.T) {
+	h := newHolder()
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	shards := []uint64{0, 5, 300}
+	for _, shard := range shards {
+		h.SetBit("i", "f", 1, shard*ShardWidth)
+	}
+	viewPath := h.Field("i", "f").viewPath(viewStandard)
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Views which predate recorded layouts are detected as flat.
+	if l, ok, err := detectFragmentLayout(viewPath); err != nil || !ok || l != FragmentLayoutFlat {
+		t.Fatalf("unexpected layout: %s, %v, %v", l, ok, err)
+	}
+
+	for _, tt := range []struct {
+		layout FragmentLayout
+		moved  int
+	}{
+		{FragmentLayoutSharded, 3},
+		{FragmentLayoutSharded, 0},
+		{FragmentLayoutFlat, 3},
+		{FragmentLayoutSharded, 3},
+	} {
+		if views, moved, err := MigrateFragmentLayout(h.Path, tt.layout); err != nil {
+			t.Fatal(err)
+		} else if views != 1 || moved != tt.moved {
+			t.Fatalf("%s: migrated %d views and %d fragments, expected 1 and %d", tt.layout, views, moved, tt.moved)
+		}
+		for _, shard := range shards {
+			if _, err := os.Stat(tt.layout.fragmentPath(viewPath, shard)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Interrupt a migration back to flat after the first move.
+	src, dst := FragmentLayoutSharded.fragmentPath(viewPath, 5), FragmentLayoutFlat.fragmentPath(viewPath, 5)
+	if err := writeViewLayout(viewPath, FragmentLayoutFlat); err != nil {
+		t.Fatal(err)
+	} else if err := os.Rename(src+cacheExt, dst+cacheExt); err != nil {
+		t.Fatal(err)
+	} else if err := os.Rename(src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	for _, shard := range shards {
+		if _, err := os.Stat(FragmentLayoutFlat.fragmentPath(viewPath, shard)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Dir(src)); !os.IsNotExist(err) {
+		t.Fatalf("expected emptied directory to be removed: %v", err)
+	}
+	if cols := h.Row("i", "f", 1).Columns(); !reflect.DeepEqual(cols, []uint64{0, 5 * ShardWidth, 300 * ShardWidth}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// fdHeadroom is the number of file descriptors reserved for listeners,
+	// client and cluster connections, the translate store and imports, on
+	// top of those used by fragments and attribute stores.
+	fdHeadroom = 1024
+
+	// mmapHeadroom is the number of memory mappings reserved for the Go
+	// runtime and shared libraries.
+	mmapHeadroom = 1024
+)
+
+// resourceEstimate is the number of file descriptors and memory mappings the
+// data under a holder's path is expected to use once opened.
+type resourceEstimate struct {
+	fragments  int
+	attrStores int
+}
+
+// fds returns the number of file descriptors expected to be used.
+func (e resourceEstimate) fds() uint64 {
+	return uint64(e.fragments+e.attrStores) + fdHeadroom
+}
+
+// mmaps returns the number of memory mappings expected to be used.
+func (e resourceEstimate) mmaps() uint64 {
+	return uint64(e.fragments+e.attrStores) + mmapHeadroom
+}
+
+// estimateResources counts the fragments and attribute stores under the
+// holder's path without opening them.
+func (h *Holder) estimateResources() (resourceEstimate, error) {
+	var e resourceEstimate
+	indexes, err := readDirs(h.Path)
+	if err != nil {
+		return e, err
+	}
+	for _, index := range indexes {
+		e.attrStores++
+		indexPath := filepath.Join(h.Path, index)
+		fields, err := readDirs(indexPath)
+		if err != nil {
+			return e, err
+		}
+		for _, field := range fields {
+			e.attrStores++
+			viewsPath := filepath.Join(indexPath, field, "views")
+			views, err := readDirs(viewsPath)
+			if err != nil {
+				return e, err
+			}
+			for _, view := range views {
+				files, err := fragmentFiles(filepath.Join(viewsPath, view))
+				if err != nil {
+					return e, err
+				}
+				e.fragments += len(files)
+			}
+		}
+	}
+	return e, nil
+}
+
+// readDirs returns the names of the directories, other than hidden ones,
+// under path. A missing path has none.
+func readDirs(path string) ([]string, error) {
+	fis, err := ioutil.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, fi := range fis {
+		if fi.IsDir() && !strings.HasPrefix(fi.Name(), ".") {
+			names = append(names, fi.Name())
+		}
+	}
+	return names, nil
+}
+
+// checkLimits compares the resources expected to be used by the holder's
+// data against the open file limit, which it raises to the hard limit if
+// needed, and the maximum number of memory mappings. Shortfalls are returned
+// as an error if StrictLimits is set, otherwise they are logged.
+func (h *Holder) checkLimits() error {
+	e, err := h.estimateResources()
+	if err != nil {
+		return errors.Wrap(err, "estimating resources")
+	}
+
+	var problems []string
+	fdLimit, err := raiseFileLimit(e.fds())
+	if err != nil {
+		h.Logger.Errorf("checking open file limit: %s", err)
+	} else if msg := checkLimit("fds", e.fds(), fdLimit); msg != "" {
+		problems = append(problems, msg)
+	}
+	if mmapLimit, ok := maxMapCount(); ok {
+		if msg := checkLimit("memory mappings", e.mmaps(), mmapLimit); msg != "" {
+			problems = append(problems, msg+" (vm.max_map_count)")
+		}
+	}
+	h.Logger.Printf("open holder: %d fragments, %d attribute stores, need ~%d fds, limit is %d", e.fragments, e.attrStores, e.fds(), fdLimit)
+
+	if len(problems) == 0 {
+		return nil
+	} else if h.StrictLimits {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	for _, msg := range problems {
+		h.Logger.Printf("WARNING: %s; Pilosa may fail with \"too many open files\" or out of memory errors. See https://www.pilosa.com/docs/latest/administration/#open-file-limits for more information.", msg)
+	}
+	return nil
+}
+
+// checkLimit returns a message describing the shortfall if need exceeds
+// limit, or an empty string.
+func checkLimit(name string, need, limit uint64) string {
+	if need <= limit {
+		return ""
+	}
+	return fmt.Sprintf("need ~%d %s, limit is %d", need, name, limit)
+}
+
+// raiseFileLimit raises the soft open file limit as far as the hard limit if
+// it is less than need, and returns the resulting soft limit.
+func raiseFileLimit(need uint64) (uint64, error) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, err
+	} else if uint64(limit.Cur) >= need || limit.Cur >= limit.Max {
+		return uint64(limit.Cur), nil
+	}
+
+	limit.Cur = limit.Max
+	if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, err
+	} else if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, err
+	}
+	return uint64(limit.Cur), nil
+}
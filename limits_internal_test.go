@@ -0,0 +1,52 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"testing"
+)
+
+// Ensure the resources needed by a holder's data are estimated from its
+// directories.
+func TestHolder_EstimateResources(t *testing.T) {
+	h := newHolder()
+	h.StrictLimits = true
+	defer h.Close()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	h.SetBit("i", "f", 1, 1)
+	h.SetBit("i", "f", 1, ShardWidth+1)
+	h.SetBit("i", "g", 1, 1)
+	h.SetBit("j", "f", 1, 1)
+
+	if e, err := h.estimateResources(); err != nil {
+		t.Fatal(err)
+	} else if e.fragments != 4 || e.attrStores != 5 {
+		t.Fatalf("unexpected estimate: %+v", e)
+	} else if e.fds() != 9+fdHeadroom || e.mmaps() != 9+mmapHeadroom {
+		t.Fatalf("unexpected fds=%d, mmaps=%d", e.fds(), e.mmaps())
+	}
+}
+
+// Ensure shortfalls are described with the need and the limit.
+func TestCheckLimit(t *testing.T) {
+	if msg := checkLimit("fds", 1024, 1024); msg != "" {
+		t.Fatalf("unexpected message: %s", msg)
+	} else if msg := checkLimit("fds", 41200, 1024); msg != "need ~41200 fds, limit is 1024" {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
@@ -15,17 +15,66 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
 )
 
-// Ensure nopLogger implements interface.
-var _ Logger = &nopLogger{}
+// Ensure loggers implement interface.
+var (
+	_ Logger = &nopLogger{}
+	_ Logger = &levelLogger{}
+	_ Logger = &contextLogger{}
+	_ Logger = &CaptureLogger{}
+)
 
 // Logger represents an interface for a shared logger.
 type Logger interface {
 	Printf(format string, v ...interface{})
 	Debugf(format string, v ...interface{})
+	Errorf(format string, v ...interface{})
+}
+
+// Level is the least severe level of the entries a logger writes.
+type Level int
+
+// Log levels, from the most to the least verbose.
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	ErrorLevel
+)
+
+// ParseLevel returns the named level.
+func ParseLevel(s string) (Level, error) {
+	switch s {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	default:
+		return 0, errors.Errorf("invalid log level %q, must be debug, info or error", s)
+	}
+}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case ErrorLevel:
+		return "error"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
 }
 
 // NopLogger represents a Logger that doesn't do anything.
@@ -39,46 +88,145 @@ func (n *nopLogger) Printf(format string, v ...interface{}) {}
 // Debugf is a no-op implementation of the Logger Debugf method.
 func (n *nopLogger) Debugf(format string, v ...interface{}) {}
 
-// standardLogger is a basic implementation of Logger based on log.Logger.
-type standardLogger struct {
+// Errorf is a no-op implementation of the Logger Errorf method.
+func (n *nopLogger) Errorf(format string, v ...interface{}) {}
+
+// levelLogger is an implementation of Logger based on log.Logger which
+// drops entries less severe than its level. Printf writes entries at the
+// info level, and Errorf prefixes its entries with "ERROR ".
+type levelLogger struct {
 	logger *log.Logger
+	level  Level
 }
 
-func NewStandardLogger(w io.Writer) *standardLogger {
-	return &standardLogger{
+// NewLevelLogger returns a logger which writes the entries of level and
+// above to w.
+func NewLevelLogger(w io.Writer, level Level) *levelLogger {
+	return &levelLogger{
 		logger: log.New(w, "", log.LstdFlags),
+		level:  level,
 	}
 }
 
-func (s *standardLogger) Printf(format string, v ...interface{}) {
-	s.logger.Printf(format, v...)
+// NewStandardLogger returns a logger which writes all but debug entries to w.
+func NewStandardLogger(w io.Writer) *levelLogger {
+	return NewLevelLogger(w, InfoLevel)
 }
 
-func (s *standardLogger) Debugf(format string, v ...interface{}) {}
+// NewVerboseLogger returns a logger which writes all entries to w.
+func NewVerboseLogger(w io.Writer) *levelLogger {
+	return NewLevelLogger(w, DebugLevel)
+}
 
-func (s *standardLogger) Logger() *log.Logger {
-	return s.logger
+func (l *levelLogger) Printf(format string, v ...interface{}) {
+	if l.level <= InfoLevel {
+		l.logger.Printf(format, v...)
+	}
 }
 
-// verboseLogger is an implementation of Logger which includes debug messages.
-type verboseLogger struct {
-	logger *log.Logger
+func (l *levelLogger) Debugf(format string, v ...interface{}) {
+	if l.level <= DebugLevel {
+		l.logger.Printf(format, v...)
+	}
 }
 
-func NewVerboseLogger(w io.Writer) *verboseLogger {
-	return &verboseLogger{
-		logger: log.New(w, "", log.LstdFlags),
+func (l *levelLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Printf("ERROR "+format, v...)
+}
+
+func (l *levelLogger) Logger() *log.Logger {
+	return l.logger
+}
+
+// contextLogger prefixes the entries of a logger with key-value pairs.
+type contextLogger struct {
+	logger Logger
+	prefix string
+}
+
+// With returns a logger which writes the entries of l prefixed with each
+// key and value, e.g. "index=i field=f: message".
+func With(l Logger, keyvals ...interface{}) Logger {
+	if len(keyvals) == 0 {
+		return l
 	}
+	a := make([]string, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			a = append(a, fmt.Sprintf("%v=%v", keyvals[i], keyvals[i+1]))
+		} else {
+			a = append(a, fmt.Sprint(keyvals[i]))
+		}
+	}
+	prefix := strings.Replace(strings.Join(a, " "), "%", "%%", -1) + ": "
+
+	if cl, ok := l.(*contextLogger); ok {
+		return &contextLogger{logger: cl.logger, prefix: cl.prefix + prefix}
+	}
+	return &contextLogger{logger: l, prefix: prefix}
+}
+
+func (l *contextLogger) Printf(format string, v ...interface{}) {
+	l.logger.Printf(l.prefix+format, v...)
+}
+
+func (l *contextLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Debugf(l.prefix+format, v...)
+}
+
+func (l *contextLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Errorf(l.prefix+format, v...)
+}
+
+// Entry is a log entry recorded by a CaptureLogger.
+type Entry struct {
+	Level   Level
+	Message string
 }
 
-func (vb *verboseLogger) Printf(format string, v ...interface{}) {
-	vb.logger.Printf(format, v...)
+// CaptureLogger records its entries so that tests can make assertions about
+// them. It is safe for concurrent use.
+type CaptureLogger struct {
+	mu      sync.Mutex
+	entries []Entry
 }
 
-func (vb *verboseLogger) Debugf(format string, v ...interface{}) {
-	vb.logger.Printf(format, v...)
+// NewCaptureLogger returns a new instance of CaptureLogger.
+func NewCaptureLogger() *CaptureLogger {
+	return &CaptureLogger{}
 }
 
-func (vb *verboseLogger) Logger() *log.Logger {
-	return vb.logger
+func (l *CaptureLogger) Printf(format string, v ...interface{}) {
+	l.record(InfoLevel, format, v)
+}
+
+func (l *CaptureLogger) Debugf(format string, v ...interface{}) {
+	l.record(DebugLevel, format, v)
+}
+
+func (l *CaptureLogger) Errorf(format string, v ...interface{}) {
+	l.record(ErrorLevel, format, v)
+}
+
+func (l *CaptureLogger) record(level Level, format string, v []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, Entry{Level: level, Message: fmt.Sprintf(format, v...)})
+}
+
+// Entries returns the entries recorded so far.
+func (l *CaptureLogger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Contains returns true if an entry of level contains substr.
+func (l *CaptureLogger) Contains(level Level, substr string) bool {
+	for _, e := range l.Entries() {
+		if e.Level == level && strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
 }
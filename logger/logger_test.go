@@ -0,0 +1,80 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pilosa/pilosa/logger"
+)
+
+func TestLevelLogger(t *testing.T) {
+	for _, tt := range []struct {
+		level logger.Level
+		exp   []string
+	}{
+		{logger.DebugLevel, []string{"debug 1", "info 2", "ERROR error 3"}},
+		{logger.InfoLevel, []string{"info 2", "ERROR error 3"}},
+		{logger.ErrorLevel, []string{"ERROR error 3"}},
+	} {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logger.NewLevelLogger(&buf, tt.level)
+			l.Debugf("debug %d", 1)
+			l.Printf("info %d", 2)
+			l.Errorf("error %d", 3)
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				// Strip the date and time.
+				got = append(got, strings.SplitN(line, " ", 3)[2])
+			}
+			if !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("unexpected entries: %q", got)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []logger.Level{logger.DebugLevel, logger.InfoLevel, logger.ErrorLevel} {
+		if l, err := logger.ParseLevel(level.String()); err != nil {
+			t.Fatal(err)
+		} else if l != level {
+			t.Fatalf("unexpected level: %s", l)
+		}
+	}
+	if _, err := logger.ParseLevel("warn"); err == nil || !strings.Contains(err.Error(), `invalid log level "warn"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWith(t *testing.T) {
+	capture := logger.NewCaptureLogger()
+	l := logger.With(logger.With(capture, "index", "i", "field", "f%"), "shard", 3)
+	l.Printf("opened %d rows", 2)
+	l.Errorf("closing: %s", "boom")
+
+	exp := []logger.Entry{
+		{Level: logger.InfoLevel, Message: "index=i field=f%: shard=3: opened 2 rows"},
+		{Level: logger.ErrorLevel, Message: "index=i field=f%: shard=3: closing: boom"},
+	}
+	if got := capture.Entries(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+}
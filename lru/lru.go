@@ -66,7 +66,7 @@ func (c *Cache) Add(key Key, value interface{}) {
 	ele := c.ll.PushFront(&entry{key, value})
 	c.cache[key] = ele
 	if c.maxEntries != 0 && c.ll.Len() > c.maxEntries {
-		c.removeOldest()
+		c.RemoveOldest()
 	}
 }
 
@@ -92,8 +92,8 @@ func (c *Cache) remove(key Key) { // nolint: staticcheck
 	}
 }
 
-// removeOldest removes the oldest item from the cache.
-func (c *Cache) removeOldest() {
+// RemoveOldest removes the oldest item from the cache.
+func (c *Cache) RemoveOldest() {
 	if c.cache == nil {
 		return
 	}
@@ -112,6 +112,25 @@ func (c *Cache) removeElement(e *list.Element) {
 	}
 }
 
+// SetMaxEntries changes the maximum number of entries, removing the oldest
+// entries beyond it. Zero means no limit.
+func (c *Cache) SetMaxEntries(maxEntries int) {
+	c.maxEntries = maxEntries
+	if maxEntries == 0 || c.Len() <= maxEntries {
+		return
+	}
+	for c.Len() > maxEntries {
+		c.RemoveOldest()
+	}
+
+	// Maps don't shrink as keys are deleted, so copy the remaining entries.
+	cache := make(map[interface{}]*list.Element, len(c.cache))
+	for k, e := range c.cache {
+		cache[k] = e
+	}
+	c.cache = cache
+}
+
 // Len returns the number of items in the cache.
 func (c *Cache) Len() int {
 	if c.cache == nil {
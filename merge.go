@@ -0,0 +1,151 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pilosa/pilosa/roaring"
+	"github.com/pkg/errors"
+)
+
+// FragmentFilePath returns the path of the data file of a fragment under the
+// data directory at path, in the layout of its view. The file may not exist.
+func FragmentFilePath(path, index, field, view string, shard uint64) (string, error) {
+	viewPath := filepath.Join(path, index, field, "views", view)
+	files, err := fragmentFiles(viewPath)
+	if err != nil {
+		return "", errors.Wrap(err, "listing fragments")
+	} else if p, ok := files[shard]; ok {
+		return p, nil
+	}
+
+	l, ok, err := readViewLayout(viewPath)
+	if err != nil {
+		return "", errors.Wrap(err, "reading view layout")
+	} else if !ok {
+		l = FragmentLayoutFlat
+	}
+	return l.fragmentPath(viewPath, shard), nil
+}
+
+// MergeFragmentFiles writes the union of the bits of the fragment data files
+// at pathA and pathB to a new data file at outPath, along with a cache file of
+// the given type and size for its rows. Either input may be absent, in which
+// case the bits of the other are copied. The inputs are only read, and must
+// be of the same shard, which is the name of a fragment's data file. It
+// returns the number of bits in the merged fragment. Pilosa must not be
+// running on the data directories of the inputs or of the output.
+func MergeFragmentFiles(pathA, pathB, outPath, cacheType string, cacheSize uint32) (uint64, error) {
+	shard, err := mergeShard(pathA, pathB, outPath)
+	if err != nil {
+		return 0, err
+	}
+
+	// Refuse to write over an existing fragment, including either input.
+	if _, err := os.Stat(outPath); err == nil {
+		return 0, errors.Errorf("output file already exists: %s", outPath)
+	} else if !os.IsNotExist(err) {
+		return 0, errors.Wrap(err, "statting output file")
+	}
+
+	a, okA, err := readFragmentFile(pathA)
+	if err != nil {
+		return 0, errors.Wrapf(err, "reading %s", pathA)
+	}
+	b, okB, err := readFragmentFile(pathB)
+	if err != nil {
+		return 0, errors.Wrapf(err, "reading %s", pathB)
+	} else if !okA && !okB {
+		return 0, errors.New("neither fragment file exists")
+	}
+	merged := a.Union(b)
+
+	// Write the merged storage to a temporary file and move it into place, so
+	// that a failed merge never leaves a partial fragment behind.
+	var buf bytes.Buffer
+	if _, err := merged.WriteTo(&buf); err != nil {
+		return 0, errors.Wrap(err, "encoding merged fragment")
+	} else if err := os.MkdirAll(filepath.Dir(outPath), 0777); err != nil {
+		return 0, errors.Wrap(err, "creating output directory")
+	}
+	tmpPath := outPath + snapshotExt
+	if err := writeFileSync(tmpPath, buf.Bytes()); err != nil {
+		os.Remove(tmpPath)
+		return 0, errors.Wrap(err, "writing merged fragment")
+	} else if err := os.Rename(tmpPath, outPath); err != nil {
+		os.Remove(tmpPath)
+		return 0, errors.Wrap(err, "renaming merged fragment")
+	}
+
+	// Rebuild the cache from the merged storage, which is persisted when the
+	// fragment is closed.
+	if err := os.Remove(outPath + cacheExt); err != nil && !os.IsNotExist(err) {
+		return 0, errors.Wrap(err, "removing stale cache file")
+	}
+	frag := newFragment(outPath, "", "", "", shard)
+	frag.CacheType = cacheType
+	frag.CacheSize = cacheSize
+	if err := frag.Open(); err != nil {
+		return 0, errors.Wrap(err, "opening merged fragment")
+	} else if err := frag.resetCache(); err != nil {
+		frag.Close()
+		return 0, errors.Wrap(err, "rebuilding cache")
+	}
+	frag.cacheRebuild.Wait()
+	if err := frag.Close(); err != nil {
+		return 0, errors.Wrap(err, "closing merged fragment")
+	}
+	return merged.Count(), nil
+}
+
+// mergeShard returns the shard of the fragment files to merge, refusing
+// fragments of different shards and outputs which are named for another.
+func mergeShard(pathA, pathB, outPath string) (uint64, error) {
+	var shard uint64
+	for i, path := range []string{pathA, pathB, outPath} {
+		n, err := strconv.ParseUint(filepath.Base(path), 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("fragment file must be named for its shard: %s", path)
+		} else if i > 0 && n != shard {
+			return 0, errors.Errorf("cannot merge fragments of different shards: %s and %s", pathA, path)
+		}
+		shard = n
+	}
+	return shard, nil
+}
+
+// readFragmentFile returns the storage of the fragment data file at path. It
+// returns false, and an empty bitmap, if the file doesn't exist.
+func readFragmentFile(path string) (*roaring.Bitmap, bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return roaring.NewBitmap(), false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	bm := roaring.NewBitmap()
+	if len(data) == 0 {
+		return bm, true, nil
+	} else if err := bm.UnmarshalBinary(data); err != nil {
+		return nil, false, errors.Wrap(err, "unmarshaling")
+	}
+	return bm, true, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// orphanDir is the directory, under the holder's path, which orphaned
+// temporary files are moved to when they are preserved.
+const orphanDir = ".orphans"
+
+// tempFileExts lists the extensions of the temporary files written under the
+// holder's path. They are only ever left behind by a crash, and are removed
+// when the holder is opened. Anything which writes a temporary file under the
+// holder's path must use one of these extensions.
+var tempFileExts = []struct {
+	ext    string
+	writer string
+}{
+	{snapshotExt, "fragment snapshots and caches"},
+	{copyExt, "fragment copies"},
+	{metaTmpExt, "meta, shard, node ID, topology and migration files"},
+}
+
+// isTempFile returns true if name has the extension of a temporary file.
+func isTempFile(name string) bool {
+	for _, t := range tempFileExts {
+		if strings.HasSuffix(name, t.ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeOrphans removes the temporary files left under the holder's path by
+// a crash, or moves them under orphanDir if PreserveOrphans is set. It must be
+// called before anything is opened. It returns the number of files found.
+func (h *Holder) removeOrphans() (int, error) {
+	var n int
+	err := filepath.Walk(h.Path, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if fi.IsDir() {
+			if path == filepath.Join(h.Path, orphanDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		} else if !isTempFile(fi.Name()) {
+			return nil
+		}
+		n++
+
+		if !h.PreserveOrphans {
+			h.Logger.Printf("removing orphaned file: %s", path)
+			return errors.Wrap(os.Remove(path), "removing")
+		}
+
+		rel, err := filepath.Rel(h.Path, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(h.Path, orphanDir, rel)
+		h.Logger.Printf("moving orphaned file: %s to %s", path, dst)
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			return errors.Wrap(err, "creating directory")
+		}
+		return errors.Wrap(os.Rename(path, dst), "moving")
+	})
+	return n, err
+}
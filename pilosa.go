@@ -17,7 +17,11 @@ package pilosa
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
+
+	"github.com/pilosa/pilosa/pql"
 )
 
 // System errors.
@@ -28,11 +32,23 @@ var (
 	ErrIndexExists   = errors.New("index already exists")
 	ErrIndexNotFound = errors.New("index not found")
 
+	// ErrInvalidShardWidth is returned for a shard width which isn't a
+	// power of 2 between MinShardWidth and MaxShardWidth.
+	ErrInvalidShardWidth = errors.New("invalid shard width, must be a power of 2 from 65536 to 4294967296")
+
+	// ErrShardWidthImmutable is returned when changing the shard width of
+	// an existing index.
+	ErrShardWidthImmutable = errors.New("the shard width of an existing index cannot be changed")
+
 	// ErrFieldRequired is returned when no field is specified.
 	ErrFieldRequired = errors.New("field required")
 	ErrFieldExists   = errors.New("field already exists")
 	ErrFieldNotFound = errors.New("field not found")
 
+	ErrTooManyIndexes = errors.New("too many indexes")
+	ErrTooManyFields  = errors.New("too many fields in index")
+	ErrTooManyViews   = errors.New("too many views in field")
+
 	ErrBSIGroupNotFound         = errors.New("bsigroup not found")
 	ErrBSIGroupExists           = errors.New("bsigroup already exists")
 	ErrBSIGroupNameRequired     = errors.New("bsigroup name required")
@@ -47,16 +63,39 @@ var (
 	ErrInvalidView      = errors.New("invalid view")
 	ErrInvalidCacheType = errors.New("invalid cache type")
 
-	ErrName  = errors.New("invalid index or field name, must match [a-z0-9_-]")
-	ErrLabel = errors.New("invalid row or column label, must match [A-Za-z0-9_-]")
+	ErrTimeMigrationRunning  = errors.New("time migration already running")
+	ErrTimeMigrationNotFound = errors.New("time migration not found")
+
+	ErrFieldCopying      = errors.New("field is being copied")
+	ErrFieldCopyNotFound = errors.New("field copy not found")
+
+	// ErrReadOnly is returned for writes to a server started read-only.
+	ErrReadOnly = errors.New("server is read-only")
+
+	ErrName  = errors.New("invalid index or field name, must match [a-z][a-z0-9_-]{0,63}")
+	ErrLabel = errors.New("invalid row or column label, must match [A-Za-z][A-Za-z0-9_-]{0,63}")
+
+	// ErrInvalidViewName is returned for view names which Pilosa doesn't
+	// give views.
+	ErrInvalidViewName = errors.New("invalid view name, must be standard, standard_<time> or bsig_<field>")
 
 	// ErrFragmentNotFound is returned when a fragment does not exist.
 	ErrFragmentNotFound = errors.New("fragment not found")
+	ErrFragmentClosing  = errors.New("fragment is closing")
 	ErrQueryRequired    = errors.New("query required")
+	ErrQueryParse       = errors.New("query parse error")
 	ErrQueryCancelled   = errors.New("query cancelled")
 	ErrQueryTimeout     = errors.New("query timeout")
 	ErrTooManyWrites    = errors.New("too many write commands")
 
+	// ErrTooManyConstRowColumns is returned when a ConstRow() call lists more
+	// columns than the executor allows.
+	ErrTooManyConstRowColumns = errors.New("too many ConstRow columns")
+
+	// ErrQueryNotStreamable is returned when streaming a query which isn't a
+	// single call returning a row.
+	ErrQueryNotStreamable = errors.New("only a single call returning a row can be streamed")
+
 	// TODO(2.0) poorly named - used when a *node* doesn't own a shard. Probably
 	// we won't need this error at all by 2.0 though.
 	ErrClusterDoesNotOwnShard = errors.New("node does not own shard")
@@ -65,11 +104,94 @@ var (
 	ErrNodeNotCoordinator = errors.New("node is not the coordinator")
 	ErrResizeNotRunning   = errors.New("no resize job currently running")
 
+	ErrNodeRemovalNotStarted = errors.New("no node removal has been started")
+
 	ErrNotImplemented            = errors.New("not implemented")
 	ErrFieldsArgumentRequired    = errors.New("fields argument required")
 	ErrExpectedFieldListArgument = errors.New("expected field list argument")
 )
 
+// errorCodes are the codes which identify errors in API responses, so that
+// clients can tell them apart without matching messages.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrIndexNotFound, "index-not-found"},
+	{ErrIndexExists, "index-exists"},
+	{ErrFieldNotFound, "field-not-found"},
+	{ErrFieldExists, "field-exists"},
+	{ErrFragmentNotFound, "fragment-not-found"},
+	{ErrQueryParse, "query-parse"},
+	{ErrQueryTimeout, "query-timeout"},
+	{ErrQueryCancelled, "query-cancelled"},
+	{ErrReadOnly, "read-only"},
+	{ErrTooManyWrites, "too-many-writes"},
+}
+
+// ErrorCode returns the code identifying the cause of err, looking through
+// the types which wrap errors to set their HTTP status, or "" if it has none.
+// Query parse errors have the code of ErrQueryParse.
+func ErrorCode(err error) string {
+	for err != nil {
+		if c, ok := err.(interface{ Cause() error }); ok {
+			err = c.Cause()
+			continue
+		}
+		switch e := err.(type) {
+		case BadRequestError:
+			err = e.error
+		case ConflictError:
+			err = e.error
+		case NotFoundError:
+			err = e.error
+		case ForbiddenError:
+			err = e.error
+		case NodeUnavailableError:
+			err = e.error
+		case *pql.ParseError:
+			err = ErrQueryParse
+		default:
+			for _, ec := range errorCodes {
+				if e == ec.err {
+					return ec.code
+				}
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// ErrorForCode returns the error identified by code, or nil if there is none.
+func ErrorForCode(code string) error {
+	for _, ec := range errorCodes {
+		if ec.code == code {
+			return ec.err
+		}
+	}
+	return nil
+}
+
+// ErrorWithCode returns an error with the message msg, whose cause is the
+// error identified by code. It is how errors received from other nodes keep
+// their codes.
+func ErrorWithCode(code, msg string) error {
+	if err := ErrorForCode(code); err != nil {
+		return codedError{msg: msg, cause: err}
+	}
+	return errors.New(msg)
+}
+
+// codedError is an error with its own message and the cause of its code.
+type codedError struct {
+	msg   string
+	cause error
+}
+
+func (e codedError) Error() string { return e.msg }
+func (e codedError) Cause() error  { return e.cause }
+
 // apiMethodNotAllowedError wraps an error value indicating that a particular
 // API method is not allowed in the current cluster state.
 type apiMethodNotAllowedError struct {
@@ -116,9 +238,67 @@ func newNotFoundError(err error) NotFoundError {
 	return NotFoundError{err}
 }
 
+// ForbiddenError wraps an error value to signify that a request isn't
+// allowed by the configuration of the server, such that in an HTTP scenario,
+// http.StatusForbidden would be returned.
+type ForbiddenError struct {
+	error
+}
+
+// newForbiddenError returns err wrapped in a ForbiddenError.
+func newForbiddenError(err error) ForbiddenError {
+	return ForbiddenError{err}
+}
+
+// NodeUnavailableError wraps an error value to signify that a node couldn't
+// serve a request, because it couldn't be reached or answered with a server
+// error, so that the request may succeed against a replica.
+type NodeUnavailableError struct {
+	error
+}
+
+// NewNodeUnavailableError returns err wrapped in a NodeUnavailableError.
+func NewNodeUnavailableError(err error) NodeUnavailableError {
+	return NodeUnavailableError{err}
+}
+
 // Regular expression to validate index and field names.
 var nameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
 
+// viewNameRegexp matches the names of the standard view, of its time views,
+// whose suffix is a year, a quarter, or a year followed by up to a month,
+// day, hour and minute, and of the views of BSI groups.
+var viewNameRegexp = regexp.MustCompile(`^(standard(_[0-9]{4}(Q[1-4]|([0-9]{2}){0,4}))?|bsig_[a-z][a-z0-9_-]{0,63})$`)
+
+// labelRegexp matches valid row and column labels, which PQL accepts as
+// argument names.
+var labelRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,63}$`)
+
+// labelReservedArgs are the names of call arguments which can't be used as
+// row or column labels, as well as those which pql reserves.
+var labelReservedArgs = map[string]struct{}{
+	"attrName":          {},
+	"attrValues":        {},
+	"column":            {},
+	"columnAttrs":       {},
+	"exact":             {},
+	"excludeColumns":    {},
+	"excludeRowAttrs":   {},
+	"field":             {},
+	"filter":            {},
+	"ids":               {},
+	"limit":             {},
+	"n":                 {},
+	"offset":            {},
+	"previous":          {},
+	"shards":            {},
+	"tanimotoThreshold": {},
+	"threshold":         {},
+}
+
+// maxNameLength is the maximum length of index and field names.
+const maxNameLength = 64
+
 // ColumnAttrSet represents a set of attributes for a vertical column in an index.
 // Can have a set of attributes attached to it.
 type ColumnAttrSet struct {
@@ -149,14 +329,110 @@ func (cas ColumnAttrSet) MarshalJSON() ([]byte, error) {
 // TimeFormat is the go-style time format used to parse string dates.
 const TimeFormat = "2006-01-02T15:04"
 
-// validateName ensures that the name is a valid format.
+// timeFormatSeconds is TimeFormat with seconds, accepted for query time ranges.
+const timeFormatSeconds = "2006-01-02T15:04:05"
+
+// validateName ensures that the name is a valid format. Names start with a
+// lowercase letter, which is followed by at most 63 lowercase letters, digits,
+// underscores and hyphens. They are case sensitive, so uppercase letters are
+// rejected rather than folded.
 func validateName(name string) error {
-	if !nameRegexp.Match([]byte(name)) {
-		return ErrName
+	if nameRegexp.MatchString(name) {
+		return nil
+	}
+
+	switch {
+	case name == "":
+		return nameError{name, "must not be empty"}
+	case len(name) > maxNameLength:
+		return nameError{name, fmt.Sprintf("must be at most %d characters", maxNameLength)}
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			return nameError{name, "must be lowercase"}
+		case i == 0 && (r < 'a' || r > 'z'):
+			return nameError{name, "must start with a letter"}
+		case !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' && r != '-':
+			return nameError{name, fmt.Sprintf("must not contain %q", r)}
+		}
+	}
+	return ErrName
+}
+
+// ValidateIndexName returns an error describing why name can't be used for
+// an index, or nil if it can.
+func ValidateIndexName(name string) error {
+	return validateName(name)
+}
+
+// ValidateViewName returns ErrInvalidViewName if name can't be used for a
+// view. View names are directory names under their field, so only the names
+// Pilosa gives views are allowed, which have no separators or dots.
+func ValidateViewName(name string) error {
+	if viewNameRegexp.MatchString(name) {
+		return nil
+	}
+	return ErrInvalidViewName
+}
+
+// ValidateFieldName returns an error describing why name can't be used for
+// a field, or nil if it can. Field names are also keys of PQL call
+// arguments, so ones which PQL reserves are rejected.
+func ValidateFieldName(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	} else if pql.IsReservedArg(name) {
+		return nameError{name, "is reserved"}
+	}
+	return nil
+}
+
+// validateLabel returns an error describing why label can't be used as a
+// row or column label, or nil if it can.
+func validateLabel(label string) error {
+	if !labelRegexp.MatchString(label) {
+		return labelError{label, "must match [A-Za-z][A-Za-z0-9_-]{0,63}"}
+	} else if _, ok := labelReservedArgs[label]; ok || pql.IsReservedArg(label) {
+		return labelError{label, "is a reserved argument name"}
 	}
 	return nil
 }
 
+// isLegacyName returns true if name was loaded from disk and, although it
+// breaks the naming rules, is still usable as a directory name.
+func isLegacyName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\\x00")
+}
+
+// nameError describes the rule an index or field name breaks. Its cause is
+// ErrName.
+type nameError struct {
+	name   string
+	reason string
+}
+
+func (e nameError) Error() string {
+	return fmt.Sprintf("invalid name %q: %s", e.name, e.reason)
+}
+
+// Cause returns ErrName.
+func (e nameError) Cause() error { return ErrName }
+
+// labelError describes why a row or column label can't be used. Its cause
+// is ErrLabel.
+type labelError struct {
+	label  string
+	reason string
+}
+
+func (e labelError) Error() string {
+	return fmt.Sprintf("invalid label %q: %s", e.label, e.reason)
+}
+
+// Cause returns ErrLabel.
+func (e labelError) Cause() error { return ErrLabel }
+
 // stringSlicesAreEqual determines if two string slices are equal.
 func stringSlicesAreEqual(a, b []string) bool {
 
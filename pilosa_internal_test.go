@@ -15,7 +15,10 @@
 package pilosa
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestValidateName(t *testing.T) {
@@ -42,6 +45,78 @@ func TestValidateNameInvalid(t *testing.T) {
 	}
 }
 
+func TestValidateName_Reasons(t *testing.T) {
+	for name, exp := range map[string]string{
+		"":                      `invalid name "": must not be empty`,
+		"Foo":                   `invalid name "Foo": must be lowercase`,
+		"_exists":               `invalid name "_exists": must start with a letter`,
+		"1a":                    `invalid name "1a": must start with a letter`,
+		"a.b":                   `invalid name "a.b": must not contain '.'`,
+		"yüce":                  `invalid name "yüce": must not contain 'ü'`,
+		strings.Repeat("a", 65): `invalid name "` + strings.Repeat("a", 65) + `": must be at most 64 characters`,
+	} {
+		if err := validateName(name); err == nil || err.Error() != exp {
+			t.Errorf("%q: expected %q, got %v", name, exp, err)
+		} else if errors.Cause(err) != ErrName {
+			t.Errorf("%q: unexpected cause: %v", name, errors.Cause(err))
+		}
+	}
+}
+
+func TestValidateFieldName(t *testing.T) {
+	for _, name := range []string{"from", "to", "precise", "view"} {
+		if err := ValidateFieldName(name); err == nil || err.Error() != `invalid name "`+name+`": is reserved` {
+			t.Errorf("%q: unexpected error: %v", name, err)
+		} else if err := ValidateIndexName(name); err != nil {
+			t.Errorf("%q: unexpected index name error: %v", name, err)
+		}
+	}
+	if err := ValidateFieldName("fromage"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateViewName(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		valid bool
+	}{
+		{name: "standard", valid: true},
+		{name: "standard_2019", valid: true},
+		{name: "standard_2019Q4", valid: true},
+		{name: "standard_201912", valid: true},
+		{name: "standard_20191231", valid: true},
+		{name: "standard_2019123123", valid: true},
+		{name: "standard_201912312359", valid: true},
+		{name: "bsig_f", valid: true},
+		{name: "bsig_a-b_c1", valid: true},
+		{name: ""},
+		{name: "v"},
+		{name: "standard_"},
+		{name: "standard_201"},
+		{name: "standard_20191"},
+		{name: "standard_2019Q5"},
+		{name: "standard_20191231235900"},
+		{name: "standard_v"},
+		{name: "bsig_"},
+		{name: "bsig_F"},
+		{name: ".."},
+		{name: "../standard"},
+		{name: "standard/.."},
+		{name: "standard\\.."},
+		{name: "standard.2019"},
+		{name: "standard_2019\n"},
+		{name: "bsig_f\x00"},
+		{name: "/etc"},
+	} {
+		if err := ValidateViewName(tt.name); tt.valid && err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.name, err)
+		} else if !tt.valid && err != ErrInvalidViewName {
+			t.Errorf("%q: expected invalid view name, got %v", tt.name, err)
+		}
+	}
+}
+
 // memAttrStore represents an in-memory implementation of the AttrStore interface.
 type memAttrStore struct {
 	store map[uint64]map[string]interface{}
@@ -51,6 +126,15 @@ func (s *memAttrStore) Path() string                                          {
 func (s *memAttrStore) Open() error                                           { return nil }
 func (s *memAttrStore) Close() error                                          { return nil }
 func (s *memAttrStore) Attrs(id uint64) (m map[string]interface{}, err error) { return s.store[id], nil }
+func (s *memAttrStore) BlockAttrs(ids []uint64) (map[uint64]map[string]interface{}, error) {
+	m := make(map[uint64]map[string]interface{})
+	for _, id := range ids {
+		if attrs := s.store[id]; len(attrs) > 0 {
+			m[id] = attrs
+		}
+	}
+	return m, nil
+}
 func (s *memAttrStore) SetAttrs(id uint64, m map[string]interface{}) error {
 	s.store[id] = m
 	return nil
@@ -61,5 +145,16 @@ func (s *memAttrStore) SetBulkAttrs(m map[uint64]map[string]interface{}) error {
 	}
 	return nil
 }
+func (s *memAttrStore) DeleteRange(min, max uint64) error {
+	for id := range s.store {
+		if id >= min && id <= max {
+			delete(s.store, id)
+		}
+	}
+	return nil
+}
 func (s *memAttrStore) Blocks() ([]AttrBlock, error)                                  { return nil, nil }
 func (s *memAttrStore) BlockData(i uint64) (map[uint64]map[string]interface{}, error) { return nil, nil }
+func (s *memAttrStore) Find(attr string, match func(value interface{}) bool, limit int) (map[uint64]map[string]interface{}, error) {
+	return nil, nil
+}
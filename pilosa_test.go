@@ -19,7 +19,9 @@ import (
 	"testing"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/pql"
 	_ "github.com/pilosa/pilosa/test"
+	"github.com/pkg/errors"
 )
 
 func TestAddressWithDefaults(t *testing.T) {
@@ -55,3 +57,33 @@ func TestAddressWithDefaults(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorCode(t *testing.T) {
+	_, perr := pql.ParseString("Row(")
+	for _, test := range []struct {
+		err  error
+		code string
+	}{
+		{err: pilosa.ErrFieldNotFound, code: "field-not-found"},
+		{err: errors.Wrap(pilosa.ErrIndexNotFound, "executing"), code: "index-not-found"},
+		{err: pilosa.NewNodeUnavailableError(errors.Wrap(pilosa.ErrFragmentNotFound, "x")), code: "fragment-not-found"},
+		{err: errors.Wrap(perr, "parsing"), code: "query-parse"},
+		{err: errors.New("other"), code: ""},
+		{err: nil, code: ""},
+	} {
+		if code := pilosa.ErrorCode(test.err); code != test.code {
+			t.Errorf("%v: expected code %q, got %q", test.err, test.code, code)
+		}
+	}
+
+	// Errors received with a code keep their message and cause.
+	if err := pilosa.ErrorWithCode("field-exists", "server error: field already exists"); err.Error() != "server error: field already exists" {
+		t.Fatalf("unexpected message: %s", err)
+	} else if errors.Cause(err) != pilosa.ErrFieldExists {
+		t.Fatalf("unexpected cause: %v", errors.Cause(err))
+	} else if pilosa.ErrorCode(err) != "field-exists" {
+		t.Fatalf("unexpected code: %s", pilosa.ErrorCode(err))
+	} else if err := pilosa.ErrorWithCode("nope", "x"); errors.Cause(err) != err {
+		t.Fatalf("unexpected cause for unknown code: %v", errors.Cause(err))
+	}
+}
@@ -267,7 +267,7 @@ func IsReservedArg(name string) bool {
 		return true
 	}
 	switch name {
-	case "from", "to":
+	case "from", "to", "precise", "view":
 		return true
 	default:
 		return false
@@ -510,11 +510,25 @@ func formatValue(v interface{}) string {
 		return fmt.Sprintf("\"%s\"", v.Format(timeFormat))
 	case *Condition:
 		return v.String()
+	case nil:
+		return "null"
+	case float64:
+		return formatFloat(v)
 	default:
 		return fmt.Sprintf("%v", v)
 	}
 }
 
+// formatFloat formats v so that it's parsed as the same float, rather than as
+// an integer or with an exponent the parser doesn't accept.
+func formatFloat(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
 // CopyArgs returns a copy of m.
 func CopyArgs(m map[string]interface{}) map[string]interface{} {
 	other := make(map[string]interface{}, len(m))
@@ -527,12 +541,7 @@ func CopyArgs(m map[string]interface{}) map[string]interface{} {
 func joinInterfaceSlice(a []interface{}) string {
 	other := make([]string, len(a))
 	for i := range a {
-		switch v := a[i].(type) {
-		case string:
-			other[i] = fmt.Sprintf("%q", v)
-		default:
-			other[i] = fmt.Sprintf("%v", v)
-		}
+		other[i] = formatValue(a[i])
 	}
 	return "[" + strings.Join(other, ",") + "]"
 }
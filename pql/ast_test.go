@@ -41,6 +41,46 @@ func TestCall_String(t *testing.T) {
 			t.Fatalf("unexpected string: %s", s)
 		}
 	})
+	t.Run("Attr Values", func(t *testing.T) {
+		c := &pql.Call{
+			Name: "SetRowAttrs",
+			Args: map[string]interface{}{
+				"_field": "f",
+				"_row":   uint64(10),
+				"a":      "",
+				"b":      nil,
+				"c":      true,
+				"d":      int64(-3),
+				"e":      float64(2),
+				"g":      0.30000000000000004,
+				"h":      1e-9,
+				"i":      []interface{}{"x", nil, 1.5},
+			},
+		}
+		s := c.String()
+		if exp := `SetRowAttrs(_field="f", _row=10, a="", b=null, c=true, d=-3, e=2.0, g=0.30000000000000004, h=0.000000001, i=["x",null,1.5])`; s != exp {
+			t.Fatalf("unexpected string: %s", s)
+		}
+
+		// The values are parsed back unchanged.
+		q, err := pql.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		} else if args := q.Calls[0].Args; !reflect.DeepEqual(args, map[string]interface{}{
+			"_field": "f",
+			"_row":   int64(10),
+			"a":      "",
+			"b":      nil,
+			"c":      true,
+			"d":      int64(-3),
+			"e":      float64(2),
+			"g":      0.30000000000000004,
+			"h":      1e-9,
+			"i":      []interface{}{"x", nil, 1.5},
+		}) {
+			t.Fatalf("unexpected args: %#v", args)
+		}
+	})
 }
 
 // Ensure condition can handle values for BETWEEN operator.
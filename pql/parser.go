@@ -56,9 +56,29 @@ func (p *parser) Parse() (*Query, error) {
 	}
 	p.Init()
 	err = p.PQL.Parse()
-	if err != nil {
+	if pe, ok := err.(*parseError); ok {
+		return nil, errors.Wrap(pe.parseError(), "parsing")
+	} else if err != nil {
 		return nil, errors.Wrap(err, "parsing")
 	}
 	p.Execute()
 	return &p.Query, nil
 }
+
+// ParseError is returned for a query which can't be parsed, with the position
+// of the text the parser stopped at. Lines and symbols are numbered from 1.
+type ParseError struct {
+	Message string
+	Line    int
+	Symbol  int
+}
+
+// Error returns the message of the error.
+func (e *ParseError) Error() string { return e.Message }
+
+// parseError returns the ParseError of an error of the generated parser.
+func (e *parseError) parseError() *ParseError {
+	begin := int(e.max.begin)
+	pos := translatePositions(e.p.buffer, []int{begin})[begin]
+	return &ParseError{Message: e.Error(), Line: pos.line, Symbol: pos.symbol}
+}
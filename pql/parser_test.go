@@ -20,6 +20,7 @@ import (
 
 	"github.com/pilosa/pilosa/pql"
 	_ "github.com/pilosa/pilosa/test"
+	"github.com/pkg/errors"
 )
 
 // Ensure the parser can parse PQL.
@@ -171,6 +172,21 @@ func TestParser_Parse(t *testing.T) {
 		}
 	})
 
+	// Parse an empty list argument.
+	t.Run("EmptyListArgument", func(t *testing.T) {
+		q, err := pql.ParseString(`ConstRow(columns=[ ])`)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(q.Calls[0],
+			&pql.Call{
+				Name: "ConstRow",
+				Args: map[string]interface{}{"columns": []interface{}{}},
+			},
+		) {
+			t.Fatalf("unexpected call: %#v", q.Calls[0])
+		}
+	})
+
 	// Parse with condition arguments.
 	t.Run("WithCondition", func(t *testing.T) {
 		q, err := pql.ParseString(`MyCall(key=foo, x == 12.25, y >= 100, z >< [4,8], m != null)`)
@@ -192,4 +208,14 @@ func TestParser_Parse(t *testing.T) {
 		}
 	})
 
+	// Parse errors report where the parser stopped.
+	t.Run("ParseError", func(t *testing.T) {
+		_, err := pql.ParseString("Row(f=1)\nRow(f=")
+		if pe, ok := errors.Cause(err).(*pql.ParseError); !ok {
+			t.Fatalf("expected parse error, got %#v", err)
+		} else if pe.Line != 2 || pe.Symbol != 5 {
+			t.Fatalf("unexpected position: line %d symbol %d", pe.Line, pe.Symbol)
+		}
+	})
+
 }
@@ -1543,7 +1543,7 @@ func (p *PQL) Init() {
 		},
 		/* 9 condfield <- <(<fieldExpr> sp Action29)> */
 		nil,
-		/* 10 value <- <(item / (lbrack Action30 list rbrack Action31))> */
+		/* 10 value <- <(item / (lbrack Action30 list? rbrack Action31))> */
 		func() bool {
 			position114, tokenIndex114 := position, tokenIndex
 			{
@@ -1570,9 +1570,16 @@ func (p *PQL) Init() {
 					{
 						add(ruleAction30, position)
 					}
-					if !_rules[rulelist]() {
-						goto l114
+					{
+						position119, tokenIndex119 := position, tokenIndex
+						if !_rules[rulelist]() {
+							goto l119
+						}
+						goto l121
+					l119:
+						position, tokenIndex = position119, tokenIndex119
 					}
+				l121:
 					{
 						position120 := position
 						if !_rules[rulesp]() {
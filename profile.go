@@ -0,0 +1,287 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pilosa/pilosa/pql"
+)
+
+// QueryProfile records where the time of a query went. It is returned with
+// the results of a query requested with QueryRequest.Profile. Durations are
+// encoded in JSON as nanoseconds.
+type QueryProfile struct {
+	// Time spent parsing the query.
+	Parse time.Duration `json:"parse"`
+
+	// Profile of each call of the query, in order.
+	Calls []*CallProfile `json:"calls"`
+}
+
+// CallProfile records the time spent executing a call on a node. Its
+// children mirror the children of the call.
+type CallProfile struct {
+	Name string `json:"name"`
+
+	// Time spent executing the call. For a call nested in another, it is
+	// the total of the call's shard times.
+	Duration time.Duration `json:"duration"`
+
+	// Time spent reducing the results of the shards and nodes.
+	Reduce time.Duration `json:"reduce,omitempty"`
+
+	// Time spent executing the call on each shard held by this node,
+	// including the time of its children.
+	Shards []*ShardProfile `json:"shards,omitempty"`
+
+	// The other nodes the call was sent to.
+	Nodes []*NodeProfile `json:"nodes,omitempty"`
+
+	Children []*CallProfile `json:"children,omitempty"`
+
+	// Set for a TopN call which counted every row in storage instead of
+	// ranking rows by the cache.
+	Exact bool `json:"exact,omitempty"`
+}
+
+// ShardProfile is the time spent executing a call on a shard.
+type ShardProfile struct {
+	Shard    uint64        `json:"shard"`
+	Duration time.Duration `json:"duration"`
+}
+
+// NodeProfile records a call sent to another node: the time until the node
+// answered, and the node's own profile of the call.
+type NodeProfile struct {
+	ID       string        `json:"id"`
+	Shards   []uint64      `json:"shards"`
+	Duration time.Duration `json:"duration"`
+	Call     *CallProfile  `json:"call,omitempty"`
+
+	// Error of the node, whose shards were retried on other nodes.
+	Err string `json:"error,omitempty"`
+}
+
+// queryProfiler records the profile of a query as it executes. Its methods
+// do nothing on a nil profiler, which is what executors get from the context
+// of a query which isn't profiled, so that they don't read the clock.
+type queryProfiler struct {
+	mu      sync.Mutex
+	profile *QueryProfile
+	calls   map[*pql.Call]*CallProfile
+
+	// Time of each call on each shard, until the profile is finished.
+	shards map[*CallProfile]map[uint64]time.Duration
+
+	// Calls whose shards are timed by mapReduce, rather than by the call
+	// they are nested in.
+	mapped map[*CallProfile]bool
+}
+
+// newQueryProfiler returns a profiler with a profile mirroring calls.
+func newQueryProfiler(calls []*pql.Call) *queryProfiler {
+	p := &queryProfiler{
+		profile: &QueryProfile{},
+		calls:   make(map[*pql.Call]*CallProfile),
+		shards:  make(map[*CallProfile]map[uint64]time.Duration),
+		mapped:  make(map[*CallProfile]bool),
+	}
+	for _, c := range calls {
+		p.profile.Calls = append(p.profile.Calls, p.newCallProfile(c))
+	}
+	return p
+}
+
+func (p *queryProfiler) newCallProfile(c *pql.Call) *CallProfile {
+	cp := &CallProfile{Name: c.Name}
+	for _, child := range c.Children {
+		cp.Children = append(cp.Children, p.newCallProfile(child))
+	}
+	p.calls[c] = cp
+	return cp
+}
+
+type queryProfilerKey struct{}
+
+// withQueryProfiler returns a context carrying the profiler.
+func withQueryProfiler(ctx context.Context, p *queryProfiler) context.Context {
+	return context.WithValue(ctx, queryProfilerKey{}, p)
+}
+
+// queryProfilerFrom returns the profiler of the context, or nil if the query
+// isn't profiled.
+func queryProfilerFrom(ctx context.Context) *queryProfiler {
+	p, _ := ctx.Value(queryProfilerKey{}).(*queryProfiler)
+	return p
+}
+
+// alias records the time of clone, a clone of c made while executing it,
+// and of its children, in the profile of c.
+func (p *queryProfiler) alias(clone, c *pql.Call) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.aliasCall(clone, c)
+}
+
+func (p *queryProfiler) aliasCall(clone, c *pql.Call) {
+	cp := p.calls[c]
+	if cp == nil {
+		return
+	}
+	p.calls[clone] = cp
+	for i := range clone.Children {
+		if i < len(c.Children) {
+			p.aliasCall(clone.Children[i], c.Children[i])
+		}
+	}
+}
+
+// start returns the time a step starts, to be passed to the method recording
+// it.
+func (p *queryProfiler) start() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// call records the time spent executing a call since start.
+func (p *queryProfiler) call(c *pql.Call, start time.Time) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cp := p.calls[c]; cp != nil {
+		cp.Duration += d
+	}
+}
+
+// exact marks a call as executed in exact mode.
+func (p *queryProfiler) exact(c *pql.Call) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cp := p.calls[c]; cp != nil {
+		cp.Exact = true
+	}
+}
+
+// mapping marks the shards of a call as timed by mapReduce.
+func (p *queryProfiler) mapping(c *pql.Call) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cp := p.calls[c]; cp != nil {
+		p.mapped[cp] = true
+	}
+}
+
+// shard records the time spent executing a call on a shard since start.
+// mapped is set by mapReduce, and the time of a call it maps is only
+// recorded by it, so that it isn't counted twice.
+func (p *queryProfiler) shard(c *pql.Call, shard uint64, start time.Time, mapped bool) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cp := p.calls[c]
+	if cp == nil || p.mapped[cp] != mapped {
+		return
+	}
+	m := p.shards[cp]
+	if m == nil {
+		m = make(map[uint64]time.Duration)
+		p.shards[cp] = m
+	}
+	m[shard] += d
+}
+
+// reduce records the time spent reducing results of a call since start.
+func (p *queryProfiler) reduce(c *pql.Call, start time.Time) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cp := p.calls[c]; cp != nil {
+		cp.Reduce += d
+	}
+}
+
+// node records a call sent to another node since start, and the node's
+// profile of it.
+func (p *queryProfiler) node(c *pql.Call, node *Node, shards []uint64, start time.Time, profile *QueryProfile, err error) {
+	if p == nil {
+		return
+	}
+	np := &NodeProfile{ID: node.ID, Shards: shards, Duration: time.Since(start)}
+	if profile != nil && len(profile.Calls) > 0 {
+		np.Call = profile.Calls[0]
+	}
+	if err != nil {
+		np.Err = err.Error()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cp := p.calls[c]; cp != nil {
+		cp.Nodes = append(cp.Nodes, np)
+	}
+}
+
+// finish returns the profile once the query has executed.
+func (p *queryProfiler) finish() *QueryProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for cp, m := range p.shards {
+		cp.Shards = make([]*ShardProfile, 0, len(m))
+		for shard, d := range m {
+			cp.Shards = append(cp.Shards, &ShardProfile{Shard: shard, Duration: d})
+		}
+		sort.Slice(cp.Shards, func(i, j int) bool { return cp.Shards[i].Shard < cp.Shards[j].Shard })
+	}
+	for _, cp := range p.profile.Calls {
+		sumChildShards(cp)
+	}
+	return p.profile
+}
+
+// sumChildShards sets the duration of each call nested in cp, which isn't
+// timed as a whole, to the total of its shard times.
+func sumChildShards(cp *CallProfile) {
+	for _, child := range cp.Children {
+		if child.Duration == 0 {
+			for _, sp := range child.Shards {
+				child.Duration += sp.Duration
+			}
+		}
+		sumChildShards(child)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FragmentOpenPolicy determines what opening a view does when one of its
+// fragments fails to open.
+//
+//	strict       the view fails to open, and so does the holder
+//	quarantine   the fragment's files are moved under the view's quarantine
+//	             directory, and the rest of the view is opened
+//
+// A quarantined shard is empty on the node, until it is written to again or
+// repaired by anti-entropy from its other replicas.
+type FragmentOpenPolicy string
+
+// Fragment open policies.
+const (
+	FragmentOpenStrict     FragmentOpenPolicy = "strict"
+	FragmentOpenQuarantine FragmentOpenPolicy = "quarantine"
+)
+
+// quarantineDir is the directory, under a view's path, which the files of
+// fragments which fail to open are moved to.
+const quarantineDir = "quarantine"
+
+// ParseFragmentOpenPolicy returns the named fragment open policy. An empty
+// name is the strict policy.
+func ParseFragmentOpenPolicy(s string) (FragmentOpenPolicy, error) {
+	switch p := FragmentOpenPolicy(s); p {
+	case "":
+		return FragmentOpenStrict, nil
+	case FragmentOpenStrict, FragmentOpenQuarantine:
+		return p, nil
+	default:
+		return "", errors.Errorf("invalid fragment open policy %q, must be strict or quarantine", s)
+	}
+}
+
+// quarantineFragment moves the data and cache files of the fragment of shard
+// at path, which failed to open with openErr, under the view's quarantine
+// directory. Each is named after the shard and the time it was quarantined,
+// so that a shard quarantined again doesn't replace the earlier files.
+// Read-only views leave the files where they are.
+func (v *view) quarantineFragment(shard uint64, path string, openErr error) error {
+	v.stats.Count("fragment.quarantine", 1, 1.0)
+	if v.readOnly {
+		v.logger.Errorf("QUARANTINE: skipping fragment %s/%s/%s/%d which failed to open, left at %s: %s", v.index, v.field, v.name, shard, path, openErr)
+		return nil
+	}
+
+	dir := filepath.Join(v.path, quarantineDir)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return errors.Wrap(err, "creating quarantine directory")
+	}
+	dst := filepath.Join(dir, fmt.Sprintf("%d.%s", shard, time.Now().UTC().Format("20060102T150405.000000000")))
+	if err := os.Rename(path, dst); err != nil {
+		return errors.Wrap(err, "moving data file")
+	} else if err := os.Rename(path+cacheExt, dst+cacheExt); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "moving cache file")
+	}
+	v.logger.Errorf("QUARANTINE: moved fragment %s/%s/%s/%d which failed to open from %s to %s, the shard is empty until it is repaired: %s", v.index, v.field, v.name, shard, path, dst, openErr)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"os"
+
+	"github.com/pilosa/pilosa/pql"
+)
+
+// readOnlyWriter is the op log of a read-only fragment. Every write to it
+// fails, so a write which gets past the checks above the fragment still
+// isn't logged.
+type readOnlyWriter struct{}
+
+func (readOnlyWriter) Write(p []byte) (int, error) {
+	return 0, newForbiddenError(ErrReadOnly)
+}
+
+// readOnlyOpener is implemented by attribute stores which can be opened
+// read-only. It is called before the store is opened.
+type readOnlyOpener interface {
+	SetReadOnly(readOnly bool)
+}
+
+// writesData returns true if any of calls modifies data.
+func writesData(calls []*pql.Call) bool {
+	for _, call := range calls {
+		switch call.Name {
+		case "Set", "Clear", "ClearRow", "Store", "SetRowAttrs", "SetColumnAttrs":
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists returns true if there is a file at path.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pilosa/pilosa/pql"
+	"github.com/pkg/errors"
+)
+
+// Ensure a read-only holder reads existing data and rejects writes.
+func TestHolder_ReadOnly(t *testing.T) {
+	h := newHolder()
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	h.SetBit("i", "f", 1, 10)
+	if err := h.Holder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := h.Path
+	h.Holder = NewHolder()
+	h.Path = path
+	h.ReadOnly = true
+	if err := h.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cols := h.Row("i", "f", 1).Columns(); !reflect.DeepEqual(cols, []uint64{10}) {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+	if _, err := h.fragment("i", "f", viewStandard, 0).setBit(1, 11); !isForbidden(err) {
+		t.Fatalf("set bit: expected forbidden error, got %v", err)
+	} else if _, err := h.CreateIndex("j", IndexOptions{}); !isForbidden(err) {
+		t.Fatalf("create index: expected forbidden error, got %v", err)
+	} else if _, err := h.Index("i").CreateField("g"); !isForbidden(err) {
+		t.Fatalf("create field: expected forbidden error, got %v", err)
+	} else if err := h.DeleteIndex("i"); !isForbidden(err) {
+		t.Fatalf("delete index: expected forbidden error, got %v", err)
+	}
+}
+
+// Ensure writing calls are told apart from reads.
+func TestWritesData(t *testing.T) {
+	for query, exp := range map[string]bool{
+		`Row(f=1)`:                      false,
+		`Count(Row(f=1)) TopN(f)`:       false,
+		`Row(f=1) Set(1, f=1)`:          true,
+		`Clear(1, f=1)`:                 true,
+		`SetColumnAttrs(1, x=1)`:        true,
+		`Store(Row(f=1), g=1)`:          true,
+		`Options(Row(f=1), shards=[0])`: false,
+	} {
+		q, err := pql.ParseString(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := writesData(q.Calls); got != exp {
+			t.Fatalf("%s: got %v, expected %v", query, got, exp)
+		}
+	}
+}
+
+func isForbidden(err error) bool {
+	_, ok := errors.Cause(err).(ForbiddenError)
+	return ok
+}
@@ -331,7 +331,7 @@ func (b *Bitmap) Any() bool {
 	// TODO (jaffee) I'm not sure if it's possible/legal to have an empty
 	// container, so this loop may be totally unnecessary. In theory, any empty
 	// container should be removed from the bitmap though.
-	for b := iter.Next(); b; iter.Next() {
+	for iter.Next() {
 		_, c := iter.Value()
 		if c.n > 0 {
 			return true
@@ -1108,8 +1108,7 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 		// Unmarshal the op and apply it.
 		var opr op
 		if err := opr.UnmarshalBinary(buf); err != nil {
-			// FIXME(benbjohnson): return error with position so file can be trimmed.
-			return err
+			return &OpLogError{Offset: len(data) - len(buf), Tail: opTail(buf), Err: err}
 		}
 
 		opr.apply(b)
@@ -1124,6 +1123,22 @@ func (b *Bitmap) unmarshalPilosaRoaring(data []byte) error {
 	return nil
 }
 
+// OpLogError is returned when an op in the ops log can't be read, such as an
+// op whose write was cut short by a crash. The bitmap holds the containers
+// and the ops before Offset. Only an op at the tail of the log can have
+// been cut short, so the log can be trimmed at Offset when Tail is set;
+// otherwise the log is corrupt and trimming it would drop the ops after it.
+type OpLogError struct {
+	Offset int
+	Tail   bool
+	Err    error
+}
+
+// Error returns the error message of the op which can't be read.
+func (e *OpLogError) Error() string {
+	return fmt.Sprintf("reading op at offset %d: %s", e.Offset, e.Err)
+}
+
 // writeOp writes op to the OpWriter, if available.
 func (b *Bitmap) writeOp(op *op) error {
 	if b.OpWriter == nil {
@@ -1238,8 +1253,13 @@ func (itr *Iterator) Seek(seek uint64) {
 	}
 	itr.key, itr.c = itr.citer.Value()
 
-	// Move to the correct value index inside the container.
+	// Move to the correct value index inside the container. If the
+	// container of seek doesn't exist then the next one starts at its first
+	// value.
 	lb := lowbits(seek)
+	if itr.key > highbits(seek) {
+		lb = 0
+	}
 	if itr.c.isArray() {
 		// Find index in the container.
 		itr.j = search32(itr.c.array, lb)
@@ -1270,10 +1290,18 @@ func (itr *Iterator) Seek(seek uint64) {
 		if contains {
 			itr.j = j
 			itr.k = int32(lb) - int32(itr.c.runs[j].start) - 1
-		} else {
+		} else if j < int32(len(itr.c.runs)) {
 			// Set iterator to next value in the Bitmap.
 			itr.j = j
 			itr.k = -1
+		} else {
+			// If it's after the last run then move to the next container.
+			if !itr.citer.Next() {
+				itr.c = nil
+				return
+			}
+			itr.key, itr.c = itr.citer.Value()
+			itr.j = -1
 		}
 
 		return
@@ -2217,11 +2245,26 @@ func (c *Container) check() error {
 		if int32(len(c.array)) != c.n {
 			a.Append(fmt.Errorf("array count mismatch: count=%d, n=%d", len(c.array), c.n))
 		}
+		for i := 1; i < len(c.array); i++ {
+			if c.array[i-1] >= c.array[i] {
+				a.Append(fmt.Errorf("array unsorted: array[%d]=%d, array[%d]=%d", i-1, c.array[i-1], i, c.array[i]))
+				break
+			}
+		}
 	} else if c.isRun() {
 		n := c.runCountRange(0, maxContainerVal+1)
 		if n != c.n {
 			a.Append(fmt.Errorf("run count mismatch: count=%d, n=%d", n, c.n))
 		}
+		for i, r := range c.runs {
+			if r.start > r.last {
+				a.Append(fmt.Errorf("run reversed: runs[%d]=%d-%d", i, r.start, r.last))
+				break
+			} else if i > 0 && c.runs[i-1].last >= r.start {
+				a.Append(fmt.Errorf("runs unsorted: runs[%d]=%d-%d, runs[%d]=%d-%d", i-1, c.runs[i-1].start, c.runs[i-1].last, i, r.start, r.last))
+				break
+			}
+		}
 	} else if c.isBitmap() {
 		if n := c.bitmapCountRange(0, maxContainerVal+1); n != c.n {
 			a.Append(fmt.Errorf("bitmap count mismatch: count=%d, n=%d", n, c.n))
@@ -3613,11 +3656,11 @@ func (op *op) apply(b *Bitmap) (changed bool) {
 		return b.remove(op.value)
 	case opTypeAddBatch:
 		for _, v := range op.values {
-			changed = changed || b.DirectAdd(v)
+			changed = b.DirectAdd(v) || changed
 		}
 	case opTypeRemoveBatch:
 		for _, v := range op.values {
-			changed = changed || b.remove(v)
+			changed = b.remove(v) || changed
 		}
 	default:
 		panic(fmt.Sprintf("invalid op type: %d", op.typ))
@@ -3689,6 +3732,24 @@ func (op *op) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// opTail returns true if the op at the start of data, which can't be read,
+// is the last in the log: data is shorter than the op's header says it is,
+// or the op ends exactly at the end of data.
+func opTail(data []byte) bool {
+	if len(data) < minOpSize {
+		return true
+	}
+	size := uint64(minOpSize)
+	if typ := opType(data[0]); typ != opTypeAdd && typ != opTypeRemove {
+		n := binary.LittleEndian.Uint64(data[1:9])
+		if n > uint64(len(data))/8 {
+			return true
+		}
+		size += n * 8
+	}
+	return size >= uint64(len(data))
+}
+
 // size returns the encoded size of the op, in bytes.
 func (op *op) size() int {
 	if op.typ == opTypeAdd || op.typ == opTypeRemove {
@@ -3440,6 +3440,21 @@ func TestShiftRun(t *testing.T) {
 	}
 }
 
+// Ensure applying a batch op adds or removes every one of its values.
+func TestOpApplyBatch(t *testing.T) {
+	b := NewBitmap(2)
+	if !(&op{typ: opTypeAddBatch, values: []uint64{1, 2, 3, 70000}}).apply(b) {
+		t.Fatal("expected change")
+	} else if got := b.Slice(); !reflect.DeepEqual(got, []uint64{1, 2, 3, 70000}) {
+		t.Fatalf("unexpected values after add: %v", got)
+	}
+	if !(&op{typ: opTypeRemoveBatch, values: []uint64{1, 3, 70000}}).apply(b) {
+		t.Fatal("expected change")
+	} else if got := b.Slice(); !reflect.DeepEqual(got, []uint64{2}) {
+		t.Fatalf("unexpected values after remove: %v", got)
+	}
+}
+
 func TestOpLogWriteUnmarshal(t *testing.T) {
 	tests := []*op{
 		{
@@ -3653,3 +3668,24 @@ func TestDirectAddNVsAdd(t *testing.T) {
 	}
 
 }
+
+// Ensure the check of a container reports unsorted values.
+func TestContainerCheck_Unsorted(t *testing.T) {
+	for _, tt := range []struct {
+		c   *Container
+		err string
+	}{
+		{c: &Container{containerType: containerArray, n: 3, array: []uint16{1, 5, 10}}},
+		{c: &Container{containerType: containerArray, n: 3, array: []uint16{1, 10, 5}}, err: "array unsorted"},
+		{c: &Container{containerType: containerArray, n: 2, array: []uint16{5, 5}}, err: "array unsorted"},
+		{c: &Container{containerType: containerRun, n: 6, runs: []interval16{{start: 1, last: 2}, {start: 5, last: 8}}}},
+		{c: &Container{containerType: containerRun, n: 7, runs: []interval16{{start: 5, last: 8}, {start: 1, last: 3}}}, err: "runs unsorted"},
+		{c: &Container{containerType: containerRun, n: 6, runs: []interval16{{start: 1, last: 4}, {start: 4, last: 5}}}, err: "runs unsorted"},
+	} {
+		if err := tt.c.check(); tt.err == "" && err != nil {
+			t.Fatalf("%v: unexpected error: %v", tt.c, err)
+		} else if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Fatalf("%v: expected %q, got %v", tt.c, tt.err, err)
+		}
+	}
+}
@@ -241,6 +241,21 @@ func TestBitmap_Remove_Empty(t *testing.T) {
 	roaring.NewFileBitmap().Remove(1000)
 }
 
+// Ensure Any skips empty containers left behind by removals.
+func TestBitmap_Any_EmptyContainer(t *testing.T) {
+	bm := roaring.NewBitmap(1, 1<<16)
+	if _, err := bm.Remove(1); err != nil {
+		t.Fatal(err)
+	} else if !bm.Any() {
+		t.Fatal("expected true")
+	}
+	if _, err := bm.Remove(1 << 16); err != nil {
+		t.Fatal(err)
+	} else if bm.Any() {
+		t.Fatal("expected false")
+	}
+}
+
 // Ensure a bitmap can return a slice of values.
 func TestBitmap_Slice(t *testing.T) {
 	if a := roaring.NewFileBitmap(1, 2, 3).Slice(); !reflect.DeepEqual(a, []uint64{1, 2, 3}) {
@@ -285,6 +300,29 @@ func TestBitmap_ForEachRange(t *testing.T) {
 	}
 }
 
+// Ensure a range can start after the last run of a container, or in a
+// container which doesn't exist.
+func TestBitmap_ForEachRange_Seek(t *testing.T) {
+	bm := roaring.NewFileBitmap(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 65541, 196609)
+	bm.Optimize()
+
+	for _, tt := range []struct {
+		start uint64
+		exp   []uint64
+	}{
+		{start: 20, exp: []uint64{65541, 196609}},
+		{start: 131082, exp: []uint64{196609}},
+	} {
+		var a []uint64
+		bm.ForEachRange(tt.start, 1<<20, func(v uint64) {
+			a = append(a, v)
+		})
+		if !reflect.DeepEqual(a, tt.exp) {
+			t.Fatalf("%d: unexpected values: %+v", tt.start, a)
+		}
+	}
+}
+
 // Ensure bitmap can return the highest value.
 func TestBitmap_Max(t *testing.T) {
 	bm := roaring.NewFileBitmap()
@@ -291,6 +291,28 @@ func (r *Row) Columns() []uint64 {
 	return a
 }
 
+// forEachColumns calls fn with the columns of r in ascending order, as many
+// at a time as buf can hold. Each call reuses buf.
+func (r *Row) forEachColumns(buf []uint64, fn func(columns []uint64) error) error {
+	buf = buf[:0]
+	for i := range r.segments {
+		itr := r.segments[i].data.Iterator()
+		for v, eof := itr.Next(); !eof; v, eof = itr.Next() {
+			if len(buf) == cap(buf) {
+				if err := fn(buf); err != nil {
+					return err
+				}
+				buf = buf[:0]
+			}
+			buf = append(buf, v)
+		}
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	return fn(buf)
+}
+
 // rowSegment holds a subset of a row.
 // This could point to a mmapped roaring bitmap or an in-memory bitmap. The
 // width of the segment will always match the shard width.
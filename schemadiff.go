@@ -0,0 +1,278 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cespare/xxhash"
+)
+
+// CanonicalSchema is the schema of a node in a form which can be compared
+// with other nodes: every index and field, including internal fields, with
+// their options and the names of their views, in sorted order. Nodes with
+// the same schema have the same checksum.
+type CanonicalSchema struct {
+	Indexes  []*IndexInfo `json:"indexes"`
+	Checksum string       `json:"checksum"`
+}
+
+// CanonicalSchema returns the canonical schema of the holder.
+func (h *Holder) CanonicalSchema() *CanonicalSchema {
+	var a []*IndexInfo
+	for _, index := range h.Indexes() {
+		di := &IndexInfo{Name: index.Name(), Options: index.Options()}
+		for _, field := range index.Fields() {
+			fi := &FieldInfo{Name: field.Name(), Options: field.Options()}
+			for _, view := range field.views() {
+				fi.Views = append(fi.Views, &ViewInfo{Name: view.name})
+			}
+			sort.Sort(viewInfoSlice(fi.Views))
+			di.Fields = append(di.Fields, fi)
+		}
+		sort.Sort(fieldInfoSlice(di.Fields))
+		a = append(a, di)
+	}
+	sort.Sort(indexInfoSlice(a))
+	return &CanonicalSchema{Indexes: a, Checksum: schemaChecksum(a)}
+}
+
+// schemaChecksum returns the checksum of a sorted schema.
+func schemaChecksum(indexes []*IndexInfo) string {
+	buf, err := json.Marshal(indexes)
+	if err != nil {
+		panic(err) // the schema only holds types which marshal
+	}
+	return fmt.Sprintf("%016x", xxhash.Sum64(buf))
+}
+
+// DiffSchema returns the difference between the schema of the holder and
+// other, the canonical schema of another node.
+func (h *Holder) DiffSchema(other []*IndexInfo) *SchemaDiff {
+	return DiffSchemas(h.CanonicalSchema().Indexes, other)
+}
+
+// SchemaPath identifies an index, a field of an index or a view of a field.
+type SchemaPath struct {
+	Index string `json:"index"`
+	Field string `json:"field,omitempty"`
+	View  string `json:"view,omitempty"`
+}
+
+// String returns the path with its parts separated by slashes.
+func (p SchemaPath) String() string {
+	switch {
+	case p.Field == "":
+		return p.Index
+	case p.View == "":
+		return p.Index + "/" + p.Field
+	default:
+		return p.Index + "/" + p.Field + "/" + p.View
+	}
+}
+
+// SchemaMismatch is an index or field whose options differ between two
+// schemas. The options are IndexOptions or FieldOptions.
+type SchemaMismatch struct {
+	SchemaPath
+	Local interface{} `json:"local"`
+	Other interface{} `json:"other"`
+}
+
+// SchemaDiff is the difference between a local schema and another. The
+// fields of a missing or extra index, and the views of a missing or extra
+// field, aren't listed separately.
+type SchemaDiff struct {
+	// Missing lists what only the other schema has.
+	Missing []SchemaPath `json:"missing,omitempty"`
+
+	// Extra lists what only the local schema has.
+	Extra []SchemaPath `json:"extra,omitempty"`
+
+	// Mismatched lists the indexes and fields in both schemas whose
+	// options differ.
+	Mismatched []SchemaMismatch `json:"mismatched,omitempty"`
+}
+
+// Len returns the number of differences.
+func (d *SchemaDiff) Len() int {
+	return len(d.Missing) + len(d.Extra) + len(d.Mismatched)
+}
+
+// Lines returns a description of each difference.
+func (d *SchemaDiff) Lines() []string {
+	var a []string
+	for _, p := range d.Missing {
+		a = append(a, fmt.Sprintf("missing %s", p))
+	}
+	for _, p := range d.Extra {
+		a = append(a, fmt.Sprintf("extra %s", p))
+	}
+	for _, m := range d.Mismatched {
+		a = append(a, fmt.Sprintf("options differ %s: local=%+v other=%+v", m.SchemaPath, m.Local, m.Other))
+	}
+	return a
+}
+
+// DiffSchemas returns the difference between the local and other schemas.
+func DiffSchemas(local, other []*IndexInfo) *SchemaDiff {
+	d := &SchemaDiff{}
+
+	otherIndexes := make(map[string]*IndexInfo, len(other))
+	for _, oi := range other {
+		otherIndexes[oi.Name] = oi
+	}
+	for _, li := range local {
+		oi, ok := otherIndexes[li.Name]
+		if !ok {
+			d.Extra = append(d.Extra, SchemaPath{Index: li.Name})
+			continue
+		}
+		delete(otherIndexes, li.Name)
+
+		if li.Options != oi.Options {
+			d.Mismatched = append(d.Mismatched, SchemaMismatch{SchemaPath: SchemaPath{Index: li.Name}, Local: li.Options, Other: oi.Options})
+		}
+		d.diffFields(li, oi)
+	}
+	for _, oi := range other {
+		if _, ok := otherIndexes[oi.Name]; ok {
+			d.Missing = append(d.Missing, SchemaPath{Index: oi.Name})
+		}
+	}
+
+	sortSchemaPaths(d.Missing)
+	sortSchemaPaths(d.Extra)
+	sort.Slice(d.Mismatched, func(i, j int) bool {
+		return d.Mismatched[i].SchemaPath.String() < d.Mismatched[j].SchemaPath.String()
+	})
+	return d
+}
+
+// diffFields adds the differences between the fields of an index in the
+// local and other schemas.
+func (d *SchemaDiff) diffFields(li, oi *IndexInfo) {
+	otherFields := make(map[string]*FieldInfo, len(oi.Fields))
+	for _, of := range oi.Fields {
+		otherFields[of.Name] = of
+	}
+	for _, lf := range li.Fields {
+		path := SchemaPath{Index: li.Name, Field: lf.Name}
+		of, ok := otherFields[lf.Name]
+		if !ok {
+			d.Extra = append(d.Extra, path)
+			continue
+		}
+		delete(otherFields, lf.Name)
+
+		if lf.Options != of.Options {
+			d.Mismatched = append(d.Mismatched, SchemaMismatch{SchemaPath: path, Local: lf.Options, Other: of.Options})
+		}
+
+		otherViews := make(map[string]struct{}, len(of.Views))
+		for _, ov := range of.Views {
+			otherViews[ov.Name] = struct{}{}
+		}
+		for _, lv := range lf.Views {
+			if _, ok := otherViews[lv.Name]; !ok {
+				d.Extra = append(d.Extra, SchemaPath{Index: li.Name, Field: lf.Name, View: lv.Name})
+			}
+			delete(otherViews, lv.Name)
+		}
+		for name := range otherViews {
+			d.Missing = append(d.Missing, SchemaPath{Index: li.Name, Field: lf.Name, View: name})
+		}
+	}
+	for name := range otherFields {
+		d.Missing = append(d.Missing, SchemaPath{Index: li.Name, Field: name})
+	}
+}
+
+// SchemaConflictError lists the indexes and fields of a schema being applied
+// which exist with other options.
+type SchemaConflictError struct {
+	Conflicts []SchemaMismatch
+}
+
+// Error returns each conflicting path with its local and other options.
+func (e *SchemaConflictError) Error() string {
+	a := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		a[i] = fmt.Sprintf("%s: local=%s other=%s", c.SchemaPath, optionsJSON(c.Local), optionsJSON(c.Other))
+	}
+	return "schema conflicts: " + strings.Join(a, "; ")
+}
+
+// schemaConflicts returns the fields, and the indexes if indexOptions is set
+// or their shard widths differ, in both the local and other schemas whose
+// options differ. Options are
+// compared by their JSON encoding, which only holds the options of each field
+// type, so a schema read from GET /schema doesn't conflict with the schema it
+// was read from.
+func schemaConflicts(local, other []*IndexInfo, indexOptions bool) []SchemaMismatch {
+	localIndexes := make(map[string]*IndexInfo, len(local))
+	for _, li := range local {
+		localIndexes[li.Name] = li
+	}
+
+	var a []SchemaMismatch
+	for _, oi := range other {
+		li := localIndexes[oi.Name]
+		if li == nil {
+			continue
+		}
+		// An index's shard width and partition key determine the layout of
+		// its data, so a different width or key always conflicts.
+		layout := li.Options.ShardWidth != oi.Options.ShardWidth || li.Options.PartitionKey != oi.Options.PartitionKey
+		if (indexOptions || layout) && optionsJSON(li.Options) != optionsJSON(oi.Options) {
+			a = append(a, SchemaMismatch{SchemaPath: SchemaPath{Index: oi.Name}, Local: li.Options, Other: oi.Options})
+		}
+
+		localFields := make(map[string]*FieldInfo, len(li.Fields))
+		for _, lf := range li.Fields {
+			localFields[lf.Name] = lf
+		}
+		for _, of := range oi.Fields {
+			lf := localFields[of.Name]
+			if lf == nil {
+				continue
+			}
+			if optionsJSON(&lf.Options) != optionsJSON(&of.Options) {
+				a = append(a, SchemaMismatch{SchemaPath: SchemaPath{Index: oi.Name, Field: of.Name}, Local: lf.Options, Other: of.Options})
+			}
+		}
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].SchemaPath.String() < a[j].SchemaPath.String() })
+	return a
+}
+
+// optionsJSON returns the JSON encoding of IndexOptions or FieldOptions.
+func optionsJSON(v interface{}) string {
+	if o, ok := v.(FieldOptions); ok {
+		v = &o // FieldOptions only marshals the options of its type by pointer
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		panic(err) // options only hold types which marshal
+	}
+	return string(buf)
+}
+
+func sortSchemaPaths(a []SchemaPath) {
+	sort.Slice(a, func(i, j int) bool { return a[i].String() < a[j].String() })
+}
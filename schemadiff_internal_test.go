@@ -0,0 +1,114 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Ensure holders with the same schema have the same checksum and holders
+// with different schemas are diffed.
+func TestHolder_DiffSchema(t *testing.T) {
+	h0, h1 := newHolder(), newHolder()
+	for _, h := range []*tHolder{h0, h1} {
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		h.SetBit("i", "f", 1, 1)
+		if _, err := h.MustCreateIndexIfNotExists("j", IndexOptions{}).CreateField("v", OptFieldTypeInt(0, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s0, s1 := h0.CanonicalSchema(), h1.CanonicalSchema()
+	if s0.Checksum != s1.Checksum {
+		t.Fatalf("checksums differ: %s, %s", s0.Checksum, s1.Checksum)
+	} else if d := h0.DiffSchema(s1.Indexes); d.Len() != 0 {
+		t.Fatalf("unexpected differences: %v", d.Lines())
+	}
+
+	// Diverge: a field only on h1, a view only on h0, an index only on h1,
+	// and a field whose options differ.
+	h1.MustCreateFieldIfNotExists("i", "g")
+	if _, err := h0.Field("i", "f").createViewIfNotExists("standard_2019"); err != nil {
+		t.Fatal(err)
+	}
+	h1.MustCreateIndexIfNotExists("k", IndexOptions{})
+	if err := h1.DeleteIndex("j"); err != nil {
+		t.Fatal(err)
+	} else if _, err := h1.MustCreateIndexIfNotExists("j", IndexOptions{}).CreateField("v", OptFieldTypeInt(0, 20)); err != nil {
+		t.Fatal(err)
+	}
+
+	s1 = h1.CanonicalSchema()
+	if s0.Checksum == h0.CanonicalSchema().Checksum {
+		t.Fatal("expected checksum to change")
+	}
+	d := h0.DiffSchema(s1.Indexes)
+	if exp := []SchemaPath{{Index: "i", Field: "g"}, {Index: "k"}}; !reflect.DeepEqual(d.Missing, exp) {
+		t.Fatalf("unexpected missing: %v", d.Missing)
+	} else if exp := []SchemaPath{{Index: "i", Field: "f", View: "standard_2019"}}; !reflect.DeepEqual(d.Extra, exp) {
+		t.Fatalf("unexpected extra: %v", d.Extra)
+	} else if len(d.Mismatched) != 1 || d.Mismatched[0].SchemaPath != (SchemaPath{Index: "j", Field: "v"}) {
+		t.Fatalf("unexpected mismatched: %v", d.Mismatched)
+	} else if d.Mismatched[0].Local.(FieldOptions).Max != 10 || d.Mismatched[0].Other.(FieldOptions).Max != 20 {
+		t.Fatalf("unexpected options: %v", d.Mismatched[0])
+	} else if d.Len() != 4 {
+		t.Fatalf("unexpected length: %d", d.Len())
+	}
+
+	// The diff from the other side swaps missing and extra.
+	r := h1.DiffSchema(h0.CanonicalSchema().Indexes)
+	if !reflect.DeepEqual(r.Missing, d.Extra) || !reflect.DeepEqual(r.Extra, d.Missing) {
+		t.Fatalf("unexpected reverse diff: %v", r.Lines())
+	}
+}
+
+// Ensure only the options of a field's type conflict, and index options only
+// when they are compared or their shard widths differ.
+func TestSchemaConflicts(t *testing.T) {
+	local := []*IndexInfo{{
+		Name:    "i",
+		Options: IndexOptions{Keys: true},
+		Fields: []*FieldInfo{
+			{Name: "v", Options: FieldOptions{Type: FieldTypeInt, Min: 0, Max: 10, CacheType: CacheTypeRanked}},
+			{Name: "t", Options: FieldOptions{Type: FieldTypeTime, TimeQuantum: "YMD"}},
+		},
+	}}
+	other := []*IndexInfo{{
+		Name: "i",
+		Fields: []*FieldInfo{
+			{Name: "v", Options: FieldOptions{Type: FieldTypeInt, Min: 0, Max: 10}},
+			{Name: "t", Options: FieldOptions{Type: FieldTypeTime, TimeQuantum: "YM"}},
+			{Name: "new", Options: FieldOptions{Type: FieldTypeSet}},
+		},
+	}, {Name: "new"}}
+
+	if a := schemaConflicts(local, other, false); len(a) != 1 || a[0].SchemaPath != (SchemaPath{Index: "i", Field: "t"}) {
+		t.Fatalf("unexpected conflicts: %+v", a)
+	} else if a := schemaConflicts(local, other, true); len(a) != 2 || a[0].SchemaPath != (SchemaPath{Index: "i"}) {
+		t.Fatalf("unexpected conflicts: %+v", a)
+	} else if msg := (&SchemaConflictError{Conflicts: a}).Error(); msg != `schema conflicts: i: local={"keys":true,"trackExistence":false} other={"keys":false,"trackExistence":false}; i/t: local={"type":"time","timeQuantum":"YMD","timeQuantumInherited":false,"keys":false,"noStandardView":false} other={"type":"time","timeQuantum":"YM","timeQuantumInherited":false,"keys":false,"noStandardView":false}` {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+
+	// A different shard width conflicts even when index options aren't
+	// compared.
+	other[0].Options = IndexOptions{Keys: true, ShardWidth: 1 << 16}
+	if a := schemaConflicts(local, other, false); len(a) != 2 || a[0].SchemaPath != (SchemaPath{Index: "i"}) {
+		t.Fatalf("unexpected conflicts: %+v", a)
+	}
+}
@@ -65,9 +65,14 @@ type Server struct { // nolint: maligned
 	nodeID              string
 	uri                 URI
 	antiEntropyInterval time.Duration
+	antiEntropyRate     int
+	retentionInterval   time.Duration
 	metricInterval      time.Duration
 	diagnosticInterval  time.Duration
 	maxWritesPerRequest int
+	maxQueryTime        time.Duration
+	maxConstRowColumns  int
+	disableFailover     bool
 	isCoordinator       bool
 	syncer              holderSyncer
 
@@ -118,6 +123,26 @@ func OptServerAntiEntropyInterval(interval time.Duration) ServerOption {
 	}
 }
 
+// OptServerAntiEntropyRate is a functional option on Server used to set the
+// maximum number of requests per second anti-entropy sends to other nodes.
+// Zero doesn't limit them.
+func OptServerAntiEntropyRate(n int) ServerOption {
+	return func(s *Server) error {
+		s.antiEntropyRate = n
+		return nil
+	}
+}
+
+// OptServerRetentionInterval is a functional option on Server
+// used to set the interval at which expired time views are deleted.
+// Zero disables the deletion of expired views.
+func OptServerRetentionInterval(interval time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.retentionInterval = interval
+		return nil
+	}
+}
+
 func OptServerLongQueryTime(dur time.Duration) ServerOption {
 	return func(s *Server) error {
 		s.cluster.longQueryTime = dur
@@ -132,6 +157,226 @@ func OptServerMaxWritesPerRequest(n int) ServerOption {
 	}
 }
 
+// OptServerMaxQueryTime is a functional option on Server used to set the
+// maximum time a query runs for when its context has no deadline.
+func OptServerMaxQueryTime(d time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.maxQueryTime = d
+		return nil
+	}
+}
+
+// OptServerMaxConstRowColumns is a functional option on Server used to set
+// the maximum number of columns a ConstRow() call may list.
+func OptServerMaxConstRowColumns(n int) ServerOption {
+	return func(s *Server) error {
+		s.maxConstRowColumns = n
+		return nil
+	}
+}
+
+// OptServerDisableFailover is a functional option on Server used to fail
+// reads whose shards are on an unavailable node, instead of retrying the
+// shards against replicas.
+func OptServerDisableFailover(disable bool) ServerOption {
+	return func(s *Server) error {
+		s.disableFailover = disable
+		return nil
+	}
+}
+
+// OptServerCacheMaxMemory is a functional option on Server used to set the
+// approximate memory budget, in bytes, for fragment caches.
+func OptServerCacheMaxMemory(n int64) ServerOption {
+	return func(s *Server) error {
+		s.holder.CacheMaxMemory = n
+		return nil
+	}
+}
+
+// OptServerAllowLegacyNames is a functional option on Server used to open
+// indexes and fields whose names break the naming rules, instead of skipping
+// them.
+func OptServerAllowLegacyNames(allow bool) ServerOption {
+	return func(s *Server) error {
+		s.holder.AllowLegacyNames = allow
+		return nil
+	}
+}
+
+// OptServerOpenWorkers is a functional option on Server used to set the
+// number of fields opened concurrently at startup. Zero keeps the default of
+// one per CPU.
+func OptServerOpenWorkers(n int) ServerOption {
+	return func(s *Server) error {
+		if n > 0 {
+			s.holder.OpenWorkers = n
+		}
+		return nil
+	}
+}
+
+// OptServerSchemaLimits is a functional option on Server used to limit the
+// number of indexes, fields per index, and views per field which can be
+// created. Zero is unlimited.
+func OptServerSchemaLimits(indexes, fieldsPerIndex, viewsPerField int) ServerOption {
+	return func(s *Server) error {
+		s.holder.MaxIndexes = indexes
+		s.holder.MaxFieldsPerIndex = fieldsPerIndex
+		s.holder.MaxViewsPerField = viewsPerField
+		return nil
+	}
+}
+
+// OptServerPreserveOrphans is a functional option on Server used to move the
+// temporary files a crash left behind aside at startup, instead of removing
+// them.
+func OptServerPreserveOrphans(preserve bool) ServerOption {
+	return func(s *Server) error {
+		s.holder.PreserveOrphans = preserve
+		return nil
+	}
+}
+
+// OptServerStrictLimits is a functional option on Server used to fail at
+// startup if the open file or memory mapping limits are too low for the data,
+// instead of logging a warning.
+func OptServerStrictLimits(strict bool) ServerOption {
+	return func(s *Server) error {
+		s.holder.StrictLimits = strict
+		return nil
+	}
+}
+
+// OptServerFragmentLayout is a functional option on Server used to set the
+// layout of the fragments of new views. An empty layout is flat.
+func OptServerFragmentLayout(layout string) ServerOption {
+	return func(s *Server) error {
+		l, err := ParseFragmentLayout(layout)
+		if err != nil {
+			return err
+		}
+		s.holder.FragmentLayout = l
+		return nil
+	}
+}
+
+// OptServerDurability is a functional option on Server used to set the
+// durability policy, which determines which writes are synced to disk. An
+// empty policy is the default.
+func OptServerDurability(durability string) ServerOption {
+	return func(s *Server) error {
+		d, err := ParseDurability(durability)
+		if err != nil {
+			return err
+		}
+		s.holder.Durability = d
+		return nil
+	}
+}
+
+// OptServerMaxOpN is a functional option on Server used to set the number of
+// ops appended to the op log of a fragment before the fragment is
+// snapshotted. Zero uses the default.
+func OptServerMaxOpN(n int) ServerOption {
+	return func(s *Server) error {
+		if n < 0 {
+			return errors.Errorf("invalid max op n %d, must not be negative", n)
+		}
+		s.holder.MaxOpN = n
+		return nil
+	}
+}
+
+// OptServerFragmentCloseTimeout is a functional option on Server used to set
+// how long closing a fragment waits for the queries and exports reading it to
+// finish before closing it under them. Zero uses the default.
+func OptServerFragmentCloseTimeout(d time.Duration) ServerOption {
+	return func(s *Server) error {
+		if d < 0 {
+			return errors.Errorf("invalid fragment close timeout %s, must not be negative", d)
+		}
+		s.holder.FragmentCloseTimeout = d
+		return nil
+	}
+}
+
+// OptServerFragmentOpenConcurrency is a functional option on Server used to
+// set how many fragments of a view are opened at once when the server starts.
+// Zero uses GOMAXPROCS.
+func OptServerFragmentOpenConcurrency(n int) ServerOption {
+	return func(s *Server) error {
+		if n < 0 {
+			return errors.Errorf("invalid fragment open concurrency %d, must not be negative", n)
+		}
+		s.holder.FragmentOpenConcurrency = n
+		return nil
+	}
+}
+
+// OptServerFragmentOpenPolicy is a functional option on Server used to set
+// what opening a view does with a fragment which fails to open: fail, or
+// quarantine the fragment and open the rest. An empty policy is strict.
+func OptServerFragmentOpenPolicy(policy string) ServerOption {
+	return func(s *Server) error {
+		p, err := ParseFragmentOpenPolicy(policy)
+		if err != nil {
+			return err
+		}
+		s.holder.FragmentOpenPolicy = p
+		return nil
+	}
+}
+
+// OptServerFragmentIdleTimeout is a functional option on Server used to set
+// how long a fragment may go without being accessed before it is closed. It
+// is reopened when next accessed. Zero keeps fragments open.
+func OptServerFragmentIdleTimeout(d time.Duration) ServerOption {
+	return func(s *Server) error {
+		if d < 0 {
+			return errors.Errorf("invalid fragment idle timeout %s, must not be negative", d)
+		}
+		s.holder.FragmentIdleTimeout = d
+		return nil
+	}
+}
+
+// OptServerMaxOpenFragmentsPerView is a functional option on Server used to
+// set how many fragments of a view are kept open before the least recently
+// accessed are closed. Zero is unlimited.
+func OptServerMaxOpenFragmentsPerView(n int) ServerOption {
+	return func(s *Server) error {
+		if n < 0 {
+			return errors.Errorf("invalid max open fragments per view %d, must not be negative", n)
+		}
+		s.holder.MaxOpenFragmentsPerView = n
+		return nil
+	}
+}
+
+// OptServerSnapshotWorkers is a functional option on Server used to set how
+// many fragments are snapshotted at once in the background once their op logs
+// are full. Zero uses the default.
+func OptServerSnapshotWorkers(n int) ServerOption {
+	return func(s *Server) error {
+		if n < 0 {
+			return errors.Errorf("invalid snapshot workers %d, must not be negative", n)
+		}
+		s.holder.SnapshotWorkers = n
+		return nil
+	}
+}
+
+// OptServerReadOnly is a functional option on Server used to open the data
+// directory without modifying it. Writes fail, and anti-entropy and
+// retention are disabled.
+func OptServerReadOnly(readOnly bool) ServerOption {
+	return func(s *Server) error {
+		s.holder.ReadOnly = readOnly
+		return nil
+	}
+}
+
 func OptServerMetricInterval(dur time.Duration) ServerOption {
 	return func(s *Server) error {
 		s.metricInterval = dur
@@ -257,6 +502,8 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		gcNotifier: NopGCNotifier,
 
 		antiEntropyInterval: time.Minute * 10,
+		antiEntropyRate:     100,
+		retentionInterval:   time.Hour,
 		metricInterval:      0,
 		diagnosticInterval:  0,
 
@@ -274,6 +521,7 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		}
 	}
 	s.holder.translateFile.logger = s.logger
+	s.holder.translateFile.readOnly = s.holder.ReadOnly
 
 	path, err := expandDirName(s.dataDir)
 	if err != nil {
@@ -318,9 +566,13 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 	s.executor.Cluster = s.cluster
 	s.executor.TranslateStore = s.holder.translateFile
 	s.executor.MaxWritesPerRequest = s.maxWritesPerRequest
+	s.executor.MaxQueryTime = s.maxQueryTime
+	s.executor.MaxConstRowColumns = s.maxConstRowColumns
+	s.executor.DisableFailover = s.disableFailover
 	s.cluster.broadcaster = s
 	s.cluster.maxWritesPerRequest = s.maxWritesPerRequest
 	s.holder.broadcaster = s
+	s.holder.shardFetcher = s.cluster
 
 	err = s.cluster.setup()
 	if err != nil {
@@ -370,10 +622,12 @@ func (s *Server) Open() error {
 	s.syncer.Cluster = s.cluster
 	s.syncer.Closing = s.closing
 	s.syncer.Stats = s.holder.Stats.WithTags("HolderSyncer")
+	s.syncer.Limiter = newSyncLimiter(s.antiEntropyRate)
 
 	// Start background monitoring.
-	s.wg.Add(3)
+	s.wg.Add(4)
 	go func() { defer s.wg.Done(); s.monitorAntiEntropy() }()
+	go func() { defer s.wg.Done(); s.monitorRetention() }()
 	go func() { defer s.wg.Done(); s.monitorRuntime() }()
 	go func() { defer s.wg.Done(); s.monitorDiagnostics() }()
 
@@ -430,6 +684,9 @@ func (s *Server) SyncData() error {
 func (s *Server) monitorAntiEntropy() {
 	if s.antiEntropyInterval == 0 || s.cluster.ReplicaN <= 1 {
 		return // anti entropy disabled
+	} else if s.holder.ReadOnly {
+		s.logger.Printf("holder sync monitor disabled: read-only")
+		return
 	}
 	s.cluster.initializeAntiEntropy()
 
@@ -481,6 +738,71 @@ func (s *Server) monitorAntiEntropy() {
 	}
 }
 
+// monitorRetention periodically deletes time views which are older than the
+// retention of their field.
+func (s *Server) monitorRetention() {
+	if s.retentionInterval == 0 {
+		return // retention disabled
+	} else if s.holder.ReadOnly {
+		s.logger.Printf("retention monitor disabled: read-only")
+		return
+	}
+
+	ticker := time.NewTicker(s.retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+
+		if s.cluster.State() == ClusterStateResizing {
+			continue
+		}
+		if err := s.deleteExpiredViews(time.Now()); err != nil {
+			s.logger.Printf("deleting expired views: err=%s", err)
+		}
+	}
+}
+
+// deleteExpiredViews deletes local time views which are expired as of now
+// and broadcasts the deletions to the rest of the cluster, so that views
+// held only by other nodes are removed too. A view which fails to be deleted
+// or broadcast is logged and skipped, so that it doesn't hold up the others,
+// and the errors are returned together.
+func (s *Server) deleteExpiredViews(now time.Time) error {
+	var errList roaring.ErrorList
+	for _, ev := range s.holder.expiredViews(now) {
+		f := s.holder.Field(ev.Index, ev.Field)
+		if f == nil {
+			continue
+		}
+
+		s.logger.Printf("deleting expired view: index=%s, field=%s, view=%s", ev.Index, ev.Field, ev.View)
+		if err := f.deleteExpiredView(ev.View); err != nil {
+			s.logger.Printf("deleting expired view: index=%s, field=%s, view=%s, err=%s", ev.Index, ev.Field, ev.View, err)
+			errList.Append(errors.Wrapf(err, "deleting view: %s", ev.View))
+			continue
+		}
+		s.holder.Stats.CountWithCustomTags("expiredView", 1, 1.0, []string{fmt.Sprintf("index:%s", ev.Index)})
+
+		if err := s.SendSync(&DeleteViewMessage{
+			Index: ev.Index,
+			Field: ev.Field,
+			View:  ev.View,
+		}); err != nil {
+			s.logger.Printf("sending DeleteView message: index=%s, field=%s, view=%s, err=%s", ev.Index, ev.Field, ev.View, err)
+			errList.Append(errors.Wrapf(err, "sending DeleteView message: %s", ev.View))
+		}
+	}
+	if len(errList) > 0 {
+		return errList
+	}
+	return nil
+}
+
 // receiveMessage represents an implementation of BroadcastHandler.
 func (s *Server) receiveMessage(m Message) error {
 	switch obj := m.(type) {
@@ -517,6 +839,44 @@ func (s *Server) receiveMessage(m Message) error {
 		if err := idx.DeleteField(obj.Field); err != nil {
 			return err
 		}
+	case *SetFieldTimeQuantumMessage:
+		f := s.holder.Field(obj.Index, obj.Field)
+		if f == nil {
+			return fmt.Errorf("local field not found: %s", obj.Field)
+		}
+		if err := f.setTimeQuantum(obj.TimeQuantum); err != nil {
+			return err
+		}
+	case *SetFieldCacheSizeMessage:
+		f := s.holder.Field(obj.Index, obj.Field)
+		if f == nil {
+			return fmt.Errorf("local field not found: %s", obj.Field)
+		}
+		if err := f.SetCacheSize(obj.CacheSize); err != nil {
+			return err
+		}
+	case *SetIndexTimeQuantumMessage:
+		idx := s.holder.Index(obj.Index)
+		if idx == nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		if err := idx.setTimeQuantum(obj.TimeQuantum); err != nil {
+			return err
+		}
+	case *RenameIndexMessage:
+		if obj.Check {
+			return s.holder.CheckRenameIndex(obj.Index, obj.NewName)
+		}
+		if err := s.holder.RenameIndex(obj.Index, obj.NewName); err != nil {
+			return err
+		}
+	case *RenameFieldMessage:
+		if obj.Check {
+			return s.holder.CheckRenameField(obj.Index, obj.Field, obj.NewName)
+		}
+		if err := s.holder.RenameField(obj.Index, obj.Field, obj.NewName); err != nil {
+			return err
+		}
 	case *DeleteAvailableShardMessage:
 		f := s.holder.Field(obj.Index, obj.Field)
 		if err := f.RemoveAvailableShard(obj.ShardID); err != nil {
@@ -536,8 +896,17 @@ func (s *Server) receiveMessage(m Message) error {
 		if f == nil {
 			return fmt.Errorf("local field not found: %s", obj.Field)
 		}
-		err := f.deleteView(obj.View)
-		if err != nil {
+		// Views do not exist on all nodes due to shard distribution.
+		err := f.DeleteView(obj.View)
+		if err != nil && err != ErrInvalidView {
+			return err
+		}
+	case *DeleteColumnRangeMessage:
+		idx := s.holder.Index(obj.Index)
+		if idx == nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		if err := idx.DeleteColumnRange(obj.Min, obj.Max); err != nil {
 			return err
 		}
 	case *ClusterStatus:
@@ -555,6 +924,14 @@ func (s *Server) receiveMessage(m Message) error {
 		if err != nil {
 			return err
 		}
+	case *NodeHandoffMessage:
+		if err := s.cluster.followHandoff(obj); err != nil {
+			return err
+		}
+	case *NodeHandoffProgress:
+		if err := s.cluster.markNodeHandoff(obj); err != nil {
+			return err
+		}
 	case *SetCoordinatorMessage:
 		s.cluster.setCoordinator(obj.New)
 	case *UpdateCoordinatorMessage:
@@ -565,6 +942,9 @@ func (s *Server) receiveMessage(m Message) error {
 			return err
 		}
 	case *RecalculateCaches:
+		if obj.Rebuild {
+			return s.holder.rebuildCaches()
+		}
 		s.holder.recalculateCaches()
 	case *NodeEvent:
 		err := s.cluster.ReceiveEvent(obj)
@@ -646,9 +1026,13 @@ func (s *Server) mergeRemoteStatus(ns *NodeStatus) error {
 		return nil
 	}
 
-	// Sync schema.
-	if err := s.holder.applySchema(ns.Schema); err != nil {
-		return errors.Wrap(err, "applying schema")
+	// Sync schema. Conflicts don't keep the shards of the rest of the
+	// schema from being synced.
+	if err := s.holder.applySchema(ns.Schema, false); err != nil {
+		if _, ok := errors.Cause(err).(ConflictError); !ok {
+			return errors.Wrap(err, "applying schema")
+		}
+		s.logger.Errorf("applying schema of node %s: %s", ns.Node.ID, err)
 	}
 
 	// Sync available shards.
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/server"
 	"github.com/pilosa/pilosa/test"
 	"golang.org/x/sync/errgroup"
 )
@@ -452,6 +453,88 @@ func TestClusterResize_RemoveNode(t *testing.T) {
 	})
 }
 
+// Ensure a node is removed once its shards are handed off, without data
+// loss.
+func TestCluster_RemoveNodeWithHandoff(t *testing.T) {
+	clus := test.MustRunCluster(t, 3)
+	defer clus.Close()
+	m0, m1 := clus[0], clus[1]
+
+	t.Run("ErrorRemoveInvalidNode", func(t *testing.T) {
+		resp := test.MustDo("POST", m0.URL()+"/cluster/remove-node", `{"id": "invalid-node-id"}`)
+		expBody := "removing node: finding node to remove: node with provided ID does not exist"
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected StatusCode %d but got %d", http.StatusNotFound, resp.StatusCode)
+		} else if strings.TrimSpace(resp.Body) != expBody {
+			t.Fatalf("expected Body '%s' but got '%s'", expBody, strings.TrimSpace(resp.Body))
+		}
+	})
+
+	t.Run("ErrorNotStarted", func(t *testing.T) {
+		if resp := test.MustDo("GET", m0.URL()+"/cluster/remove-node", ""); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected StatusCode %d but got %d", http.StatusNotFound, resp.StatusCode)
+		} else if resp := test.MustDo("GET", m1.URL()+"/cluster/remove-node", ""); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected StatusCode %d but got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("RemoveNode", func(t *testing.T) {
+		client0 := m0.Client()
+		if err := client0.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+			t.Fatal(err)
+		} else if err := client0.CreateField(context.Background(), "i", "f"); err != nil {
+			t.Fatal(err)
+		}
+		setColumns := ""
+		for i := 0; i < 20; i++ {
+			setColumns += fmt.Sprintf("Set(%d, f=1) ", i*pilosa.ShardWidth)
+		}
+		if _, err := m0.Query("i", "", setColumns); err != nil {
+			t.Fatal(err)
+		}
+
+		nodeID := m1.API.Node().ID
+		resp := test.MustDo("POST", m0.URL()+"/cluster/remove-node", fmt.Sprintf(`{"id": "%s"}`, nodeID))
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: %d: %s", resp.StatusCode, resp.Body)
+		}
+
+		// Wait for the other nodes to pull the node's shards.
+		var r pilosa.NodeRemoval
+		for i := 0; !r.Done; i++ {
+			if i == 500 {
+				t.Fatalf("removal didn't complete: %+v", r)
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			resp := test.MustDo("GET", m0.URL()+"/cluster/remove-node", "")
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status: %d: %s", resp.StatusCode, resp.Body)
+			} else if err := json.Unmarshal([]byte(resp.Body), &r); err != nil {
+				t.Fatal(err)
+			} else if r.Error != "" {
+				t.Fatalf("unexpected error: %s", r.Error)
+			}
+		}
+		if r.ID != nodeID || r.Transferred != r.Shards || len(r.Nodes) != 2 {
+			t.Fatalf("unexpected removal: %+v", r)
+		}
+
+		if n := len(m0.API.Hosts(context.Background())); n != 2 {
+			t.Fatalf("expected 2 nodes, got %d", n)
+		} else if state := m0.API.State(); state != pilosa.ClusterStateNormal {
+			t.Fatalf("unexpected state: %s", state)
+		}
+		for _, m := range []*test.Command{m0, clus[2]} {
+			if res, err := m.Query("i", "", "Count(Row(f=1))"); err != nil {
+				t.Fatal(err)
+			} else if res != `{"results":[20]}`+"\n" {
+				t.Fatalf("unexpected result: %s", res)
+			}
+		}
+	})
+}
+
 // checkClusterState polls a given cluster for its state until it
 // receives a matching state. It polls up to n times before returning.
 func checkClusterState(m *test.Command, state string, n int) bool {
@@ -463,3 +546,343 @@ func checkClusterState(m *test.Command, state string, n int) bool {
 	}
 	return false
 }
+
+// Ensure an index is renamed on every node, or none of them.
+func TestCluster_RenameIndex(t *testing.T) {
+	clus := test.MustRunCluster(t, 3)
+	defer clus.Close()
+
+	client0 := clus[0].Client()
+	if err := client0.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client0.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An index created on one node only makes that node reject the rename.
+	if _, err := clus[2].Server.Holder().CreateIndex("x", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client0.RenameIndex(context.Background(), "i", "x"); err == nil {
+		t.Fatal("expected error")
+	}
+	for n, m := range clus {
+		if m.Server.Holder().Index("i") == nil {
+			t.Fatalf("node%d: expected index to remain", n)
+		}
+	}
+
+	if err := client0.RenameIndex(context.Background(), "i", "j"); err != nil {
+		t.Fatal(err)
+	}
+	for n, m := range clus {
+		if h := m.Server.Holder(); h.Index("i") != nil || h.Index("j") == nil || h.Field("j", "f") == nil {
+			t.Fatalf("node%d: expected index to be renamed", n)
+		}
+	}
+	if _, err := clus[1].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Row(f=1)"}); err == nil || !strings.Contains(err.Error(), "index not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a renamed index's shards stay with the nodes which hold them.
+func TestCluster_RenameIndex_Shards(t *testing.T) {
+	clus := test.MustRunCluster(t, 3)
+	defer clus.Close()
+
+	clus.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	var bits [][2]uint64
+	for shard := uint64(0); shard < 12; shard++ {
+		bits = append(bits, [2]uint64{1, shard * pilosa.ShardWidth})
+	}
+	bits = append(bits, [2]uint64{1, 1})
+	clus.ImportBits(t, "i", "f", bits)
+
+	// Wait for every node to know of every shard.
+	for _, m := range clus {
+		for i := 0; ; i++ {
+			idx, err := m.API.Index(context.Background(), "i")
+			if err != nil {
+				t.Fatal(err)
+			} else if n := idx.AvailableShards().Count(); n == 12 {
+				break
+			} else if i == 500 {
+				t.Fatalf("unexpected available shards: %d", n)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	owners := make(map[uint64]string)
+	for shard := uint64(0); shard < 12; shard++ {
+		nodes, err := clus[0].API.ShardNodes(context.Background(), "i", shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		owners[shard] = nodes[0].ID
+	}
+
+	if err := clus[0].API.RenameIndex(context.Background(), "i", "j"); err != nil {
+		t.Fatal(err)
+	}
+
+	for n, m := range clus {
+		for shard := uint64(0); shard < 12; shard++ {
+			nodes, err := m.API.ShardNodes(context.Background(), "j", shard)
+			if err != nil {
+				t.Fatal(err)
+			} else if nodes[0].ID != owners[shard] {
+				t.Fatalf("node%d: shard %d moved from %s to %s", n, shard, owners[shard], nodes[0].ID)
+			}
+		}
+
+		resp, err := m.API.Query(context.Background(), &pilosa.QueryRequest{Index: "j", Query: "Count(Row(f=1))"})
+		if err != nil {
+			t.Fatal(err)
+		} else if resp.Results[0] != uint64(13) {
+			t.Fatalf("node%d: unexpected count: %v", n, resp.Results[0])
+		}
+	}
+}
+
+func TestCluster_RenameField(t *testing.T) {
+	clus := test.MustRunCluster(t, 3)
+	defer clus.Close()
+
+	client0 := clus[0].Client()
+	if err := client0.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client0.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	} else if _, err := clus[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: fmt.Sprintf("Set(1, f=1) Set(%d, f=1) Set(%d, f=1)", pilosa.ShardWidth+1, 2*pilosa.ShardWidth+1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A field created on one node only makes that node reject the rename.
+	if _, err := clus[2].Server.Holder().Index("i").CreateField("x"); err != nil {
+		t.Fatal(err)
+	} else if err := client0.RenameField(context.Background(), "i", "f", "x"); err == nil {
+		t.Fatal("expected error")
+	}
+	for n, m := range clus {
+		if m.Server.Holder().Field("i", "f") == nil {
+			t.Fatalf("node%d: expected field to remain", n)
+		}
+	}
+
+	if err := client0.RenameField(context.Background(), "i", "f", "g"); err != nil {
+		t.Fatal(err)
+	}
+	for n, m := range clus {
+		if h := m.Server.Holder(); h.Field("i", "f") != nil || h.Field("i", "g") == nil {
+			t.Fatalf("node%d: expected field to be renamed", n)
+		}
+	}
+	if resp, err := clus[1].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Count(Row(g=1))"}); err != nil {
+		t.Fatal(err)
+	} else if resp.Results[0] != uint64(3) {
+		t.Fatalf("unexpected count: %v", resp.Results[0])
+	}
+	if _, err := clus[1].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Row(f=1)"}); err == nil || !strings.Contains(err.Error(), "field not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a column range is deleted from every replica.
+func TestCluster_DeleteColumnRange(t *testing.T) {
+	clus := test.MustRunCluster(t, 3, []server.CommandOption{server.OptCommandServerOptions(pilosa.OptServerReplicaN(2))})
+	defer clus.Close()
+
+	client0 := clus[0].Client()
+	if err := client0.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client0.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Set a bit at either end of each of six shards.
+	var query string
+	for shard := uint64(0); shard < 6; shard++ {
+		query += fmt.Sprintf("Set(%d, f=1) Set(%d, f=1) ", shard*pilosa.ShardWidth, (shard+1)*pilosa.ShardWidth-1)
+	}
+	if _, err := clus[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: query}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Delete the end of shard 1 through the start of shard 4.
+	if err := client0.DeleteColumnRange(context.Background(), "i", pilosa.ShardWidth+1, 4*pilosa.ShardWidth); err != nil {
+		t.Fatal(err)
+	}
+
+	for n, m := range clus {
+		resp, err := m.API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"})
+		if err != nil {
+			t.Fatal(err)
+		} else if resp.Results[0] != uint64(6) {
+			t.Fatalf("node%d: unexpected count: %v", n, resp.Results[0])
+		}
+
+		// Each replica of a shard only keeps the bits outside the range.
+		var exp uint64
+		for shard, n := range []uint64{2, 1, 0, 0, 1, 2} {
+			nodes, err := m.API.ShardNodes(context.Background(), "i", uint64(shard))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, node := range nodes {
+				if node.ID == m.API.Node().ID {
+					exp += n
+				}
+			}
+		}
+		var bits uint64
+		if _, err := m.API.HolderStats(context.Background(), "i", "f", func(vs *pilosa.ViewStats) error {
+			bits += vs.View.BitCount
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		} else if bits != exp {
+			t.Fatalf("node%d: unexpected local bits: %d != %d", n, bits, exp)
+		}
+	}
+}
+
+// Ensure that a shard imported on one node is queried from every other node
+// as soon as the import returns.
+func TestCluster_ImportNewShard(t *testing.T) {
+	clus := test.MustRunCluster(t, 3)
+	defer clus.Close()
+
+	client0 := clus[0].Client()
+	if err := client0.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client0.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	} else if _, err := clus[0].Query("i", "", `Set(1, f=1)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Import shard 7 on the node which owns it.
+	nodes, err := clus[0].API.ShardNodes(context.Background(), "i", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var owner int
+	for n, m := range clus {
+		if m.API.Node().ID == nodes[0].ID {
+			owner = n
+		}
+	}
+	if _, err := clus[owner].Client().Import(context.Background(), "i", "f", 7, []pilosa.Bit{
+		{RowID: 1, ColumnID: 7*pilosa.ShardWidth + 1},
+		{RowID: 1, ColumnID: 7*pilosa.ShardWidth + 2},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for n, m := range clus {
+		if n == owner {
+			continue
+		}
+		resp, err := m.API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Count(Row(f=1))"})
+		if err != nil {
+			t.Fatal(err)
+		} else if resp.Results[0] != uint64(3) {
+			t.Fatalf("node%d: unexpected count: %v", n, resp.Results[0])
+		} else if shards := m.API.MaxShards(context.Background()); shards["i"] != 7 {
+			t.Fatalf("node%d: unexpected max shard: %d", n, shards["i"])
+		}
+	}
+}
+
+// Ensure a field copied into another index is placed on the owners of its
+// shards in that index.
+func TestCluster_FieldCopyBetweenIndexes(t *testing.T) {
+	clus := test.MustRunCluster(t, 3)
+	defer clus.Close()
+
+	clus.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	var bits [][2]uint64
+	for shard := uint64(0); shard < 12; shard++ {
+		bits = append(bits, [2]uint64{1, shard * pilosa.ShardWidth}, [2]uint64{2, shard*pilosa.ShardWidth + 1})
+	}
+	bits = append(bits, [2]uint64{1, 1})
+	clus.ImportBits(t, "i", "f", bits)
+
+	// Wait for every node to know of every shard.
+	for _, m := range clus {
+		for i := 0; ; i++ {
+			idx, err := m.API.Index(context.Background(), "i")
+			if err != nil {
+				t.Fatal(err)
+			} else if n := idx.AvailableShards().Count(); n == 12 {
+				break
+			} else if i == 500 {
+				t.Fatalf("unexpected available shards: %d", n)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// Each node copies the shards it owns in the destination.
+	req := pilosa.FieldCopyRequest{ID: "copy", Index: "j", Field: "g", SourceIndex: "i", Source: "f"}
+	client := clus[0].Client()
+	for _, m := range clus {
+		if _, err := client.StartFieldCopy(context.Background(), &m.API.Node().URI, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, m := range clus {
+		for i := 0; ; i++ {
+			c, err := client.FieldCopyByID(context.Background(), &m.API.Node().URI, req.ID)
+			if err != nil {
+				t.Fatal(err)
+			} else if c.Error != "" {
+				t.Fatal(c.Error)
+			} else if c.Done {
+				break
+			} else if i == 500 {
+				t.Fatalf("copy didn't finish: %+v", c)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	for _, m := range clus {
+		if _, err := client.FinishFieldCopy(context.Background(), &m.API.Node().URI, "j", "g"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for n, m := range clus {
+		for row, exp := range map[int]uint64{1: 13, 2: 12} {
+			resp, err := m.API.Query(context.Background(), &pilosa.QueryRequest{Index: "j", Query: fmt.Sprintf("Count(Row(g=%d))", row)})
+			if err != nil {
+				t.Fatal(err)
+			} else if resp.Results[0] != exp {
+				t.Fatalf("node%d: unexpected count of row %d: %v", n, row, resp.Results[0])
+			}
+		}
+
+		// Only the owners of a shard in the destination hold it.
+		var exp uint64
+		for shard := uint64(0); shard < 12; shard++ {
+			nodes, err := m.API.ShardNodes(context.Background(), "j", shard)
+			if err != nil {
+				t.Fatal(err)
+			} else if nodes[0].ID == m.API.Node().ID {
+				exp += 2
+				if shard == 0 {
+					exp++
+				}
+			}
+		}
+		var bits uint64
+		if _, err := m.API.HolderStats(context.Background(), "j", "g", func(vs *pilosa.ViewStats) error {
+			bits += vs.View.BitCount
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		} else if bits != exp {
+			t.Fatalf("node%d: unexpected local bits: %d != %d", n, bits, exp)
+		}
+	}
+}
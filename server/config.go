@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/pilosa/pilosa/gossip"
+	"github.com/pilosa/pilosa/http"
+	"github.com/pilosa/pilosa/logger"
 	"github.com/pilosa/pilosa/toml"
 	"github.com/pkg/errors"
 	"github.com/uber/jaeger-client-go"
@@ -58,16 +60,102 @@ type Config struct {
 	// SetRowAttrs & SetColumnAttrs.
 	MaxWritesPerRequest int `toml:"max-writes-per-request"`
 
+	// MaxQueryTime is how long a query runs for before it times out, unless
+	// its client set a deadline. Zero is unlimited.
+	MaxQueryTime toml.Duration `toml:"max-query-time"`
+
+	// MaxConstRowColumns is the most column IDs that a ConstRow() call may
+	// list. Zero is unlimited.
+	MaxConstRowColumns int `toml:"max-const-row-columns"`
+
+	// CacheMaxMemory is the approximate number of bytes which the row count
+	// caches of all fragments may use. When exceeded, the caches least
+	// recently used by TopN are shrunk. Zero disables the limit.
+	CacheMaxMemory int64 `toml:"cache-max-memory"`
+
+	// OpenWorkers is the number of fields opened concurrently at startup.
+	// Zero uses one per CPU.
+	OpenWorkers int `toml:"open-workers"`
+
+	// AllowLegacyNames opens indexes and fields with names created before
+	// the naming rules were enforced, instead of skipping them.
+	AllowLegacyNames bool `toml:"allow-legacy-names"`
+
+	// Limits on the number of indexes, fields per index, and views per field
+	// which can be created. Zero is unlimited.
+	MaxIndexes        int `toml:"max-indexes"`
+	MaxFieldsPerIndex int `toml:"max-fields-per-index"`
+	MaxViewsPerField  int `toml:"max-views-per-field"`
+
+	// Durability determines which writes are synced to disk: relaxed,
+	// default or strict.
+	Durability string `toml:"durability"`
+
+	// MaxOpN is the number of ops appended to the op log of a fragment
+	// before the fragment is snapshotted.
+	MaxOpN int `toml:"max-op-n"`
+
+	// FragmentCloseTimeout is how long closing a fragment waits for the
+	// queries and exports reading it to finish.
+	FragmentCloseTimeout toml.Duration `toml:"fragment-close-timeout"`
+
+	// FragmentOpenConcurrency is the number of fragments of a view opened at
+	// once at startup. Zero uses GOMAXPROCS.
+	FragmentOpenConcurrency int `toml:"fragment-open-concurrency"`
+
+	// FragmentOpenPolicy determines what happens at startup to a fragment
+	// which fails to open: strict fails startup, quarantine moves its files
+	// aside and opens the rest.
+	FragmentOpenPolicy string `toml:"fragment-open-policy"`
+
+	// FragmentIdleTimeout is how long a fragment may go without being
+	// accessed before it is closed, until it is next accessed. Zero keeps
+	// fragments open.
+	FragmentIdleTimeout toml.Duration `toml:"fragment-idle-timeout"`
+
+	// MaxOpenFragmentsPerView is the number of fragments of a view kept
+	// open before the least recently accessed are closed. Zero is
+	// unlimited.
+	MaxOpenFragmentsPerView int `toml:"max-open-fragments-per-view"`
+
+	// SnapshotWorkers is the number of fragments snapshotted at once in the
+	// background once their op logs pass MaxOpN.
+	SnapshotWorkers int `toml:"snapshot-workers"`
+
+	// PreserveOrphans moves the temporary files a crash left behind aside
+	// at startup, instead of removing them.
+	PreserveOrphans bool `toml:"preserve-orphans"`
+
+	// StrictLimits fails startup if the open file or memory mapping limits
+	// are too low for the data, instead of logging a warning.
+	StrictLimits bool `toml:"strict-limits"`
+
+	// FragmentLayout is the layout of the fragments of new views: flat or
+	// sharded.
+	FragmentLayout string `toml:"fragment-layout"`
+
+	// ReadOnly opens the data directory without modifying it, and rejects
+	// writes.
+	ReadOnly bool `toml:"read-only"`
+
 	// LogPath configures where Pilosa will write logs.
 	LogPath string `toml:"log-path"`
 
 	// Verbose toggles verbose logging which can be useful for debugging.
 	Verbose bool `toml:"verbose"`
 
+	// LogLevel is the least severe level logged: debug, info or error. It
+	// overrides Verbose when set.
+	LogLevel string `toml:"log-level"`
+
 	// HTTP Handler options
 	Handler struct {
 		// CORS Allowed Origins
 		AllowedOrigins []string `toml:"allowed-origins"`
+
+		// Limit on the size of a gzip-encoded import body once
+		// decompressed, in bytes. Zero means no limit.
+		MaxDecompressedImportSize int64 `toml:"max-decompressed-import-size"`
 	} `toml:"handler"`
 
 	// TLS
@@ -80,6 +168,10 @@ type Config struct {
 		ReplicaN      int           `toml:"replicas"`
 		Hosts         []string      `toml:"hosts"`
 		LongQueryTime toml.Duration `toml:"long-query-time"`
+
+		// DisableFailover fails reads whose shards are on an unavailable
+		// node, instead of retrying the shards against replicas.
+		DisableFailover bool `toml:"disable-failover"`
 	} `toml:"cluster"`
 
 	// Gossip config is based around memberlist.Config.
@@ -93,10 +185,21 @@ type Config struct {
 
 	AntiEntropy struct {
 		Interval toml.Duration `toml:"interval"`
+
+		// RequestsPerSecond limits the requests anti-entropy sends to
+		// other nodes. Zero doesn't limit them.
+		RequestsPerSecond int `toml:"requests-per-second"`
 	} `toml:"anti-entropy"`
 
+	// Retention controls the deletion of time views older than the
+	// retention configured on their field.
+	Retention struct {
+		Interval toml.Duration `toml:"interval"`
+	} `toml:"retention"`
+
 	Metric struct {
-		// Service can be statsd, expvar, or none.
+		// Service is the name of a registered stats backend: statsd,
+		// expvar, or none, unless a build registers others.
 		Service string `toml:"service"`
 		// Host tells the statsd client where to write.
 		Host         string        `toml:"host"`
@@ -123,9 +226,17 @@ func NewConfig() *Config {
 		DataDir:             "~/.pilosa",
 		Bind:                ":10101",
 		MaxWritesPerRequest: 5000,
+		MaxConstRowColumns:  100000,
+		MaxOpN:              10000,
 		TLS:                 TLSConfig{},
 	}
 
+	c.FragmentCloseTimeout = toml.Duration(10 * time.Second)
+	c.SnapshotWorkers = 2
+
+	// Handler config.
+	c.Handler.MaxDecompressedImportSize = http.DefaultMaxDecompressedImportSize
+
 	// Cluster config.
 	c.Cluster.Disabled = false
 	c.Cluster.ReplicaN = 1
@@ -145,6 +256,10 @@ func NewConfig() *Config {
 
 	// AntiEntropy config.
 	c.AntiEntropy.Interval = toml.Duration(10 * time.Minute)
+	c.AntiEntropy.RequestsPerSecond = 100
+
+	// Retention config.
+	c.Retention.Interval = toml.Duration(time.Hour)
 
 	// Metric config.
 	c.Metric.Service = "none"
@@ -158,6 +273,16 @@ func NewConfig() *Config {
 	return c
 }
 
+// logLevel returns the least severe level to log.
+func (cfg *Config) logLevel() (logger.Level, error) {
+	if cfg.LogLevel != "" {
+		return logger.ParseLevel(cfg.LogLevel)
+	} else if cfg.Verbose {
+		return logger.DebugLevel, nil
+	}
+	return logger.InfoLevel, nil
+}
+
 // validateAddrs controls the address fields in the Config object
 // and fills in any blanks.
 // The addresses fields must be guaranteed by the caller to either be
@@ -15,8 +15,11 @@
 package server_test
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -24,7 +27,10 @@ import (
 	"io/ioutil"
 	gohttp "net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -91,6 +97,22 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Schema verbose", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/schema?verbose=true", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+
+		// The times of the last writes vary, so only their presence is checked.
+		body := regexp.MustCompile(`"lastWrite":"[^"]+"`).ReplaceAllString(w.Body.String(), `"lastWrite":"-"`)
+		target := `{"indexes":[{"name":"i0","options":{"keys":false,"trackExistence":false},"fields":[{"name":"f0","options":{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":false},"cache":{"type":"ranked","size":50000,"rows":0,"threshold":0,"scans":0}},{"name":"f1","options":{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":false},"views":[{"name":"standard","bitCount":1,"rowCount":1,"maxShard":0,"fragmentCount":1,"diskBytes":21}],"cache":{"type":"ranked","size":50000,"rows":1,"threshold":1,"scans":0},"writes":{"bitsSet":1,"bitsCleared":0,"lastWrite":"-"}}],"shardWidth":1048576},{"name":"i1","options":{"keys":false,"trackExistence":false},"fields":[{"name":"f0","options":{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":false},"views":[{"name":"standard","bitCount":1,"rowCount":1,"maxShard":0,"fragmentCount":1,"diskBytes":21}],"cache":{"type":"ranked","size":50000,"rows":1,"threshold":1,"scans":0},"writes":{"bitsSet":1,"bitsCleared":0,"lastWrite":"-"}}],"shardWidth":1048576}]}
+`
+		if body != target {
+			t.Fatalf("%s != %s", target, body)
+		}
+	})
+
 	t.Run("ImportRoaring", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		roaringData, _ := hex.DecodeString("3B3001000100000900010000000100010009000100")
@@ -160,6 +182,23 @@ func TestHandler_Endpoints(t *testing.T) {
 		if len(ret["nodes"].([]interface{})) != 1 {
 			t.Fatalf("wrong length nodes list: %#v", ret)
 		}
+		limits := ret["limits"].(map[string]interface{})
+		if n := limits["indexes"].(map[string]interface{})["count"].(float64); int(n) != len(holder.Indexes()) {
+			t.Fatalf("wrong index count from /status: %#v", limits)
+		}
+	})
+
+	t.Run("Schema limits", func(t *testing.T) {
+		holder.MaxIndexes = len(holder.Indexes())
+		defer func() { holder.MaxIndexes = 0 }()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/over", strings.NewReader("")))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if body := w.Body.String(); !strings.Contains(body, pilosa.ErrTooManyIndexes.Error()) {
+			t.Fatalf("unexpected body: %s", body)
+		}
 	})
 
 	t.Run("Abort no resize job", func(t *testing.T) {
@@ -192,6 +231,34 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Max IDs", func(t *testing.T) {
+		idx := hldr.MustCreateIndexIfNotExists("ids", pilosa.IndexOptions{})
+		defer func() {
+			if err := holder.DeleteIndex("ids"); err != nil {
+				t.Fatal(err)
+			}
+		}()
+		hldr.SetBit("ids", "f", 4, pilosa.ShardWidth+7)
+		hldr.ClearBit("ids", "f", 4, pilosa.ShardWidth+7)
+		if _, err := idx.CreateField("n", pilosa.OptFieldTypeInt(0, 10)); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ids/ids/max", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if body := w.Body.String(); body != `{"maxColumnID":1048583,"maxRowIDs":{"f":4}}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/missing/ids/max", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+	})
+
 	t.Run("Shards args", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=0,1", strings.NewReader("Count(Row(f0=30))")))
@@ -227,6 +294,27 @@ func TestHandler_Endpoints(t *testing.T) {
 
 	})
 
+	t.Run("Query profile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=0,1&profile=true", strings.NewReader("Count(Row(f0=30))")))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Results []uint64             `json:"results"`
+			Profile *pilosa.QueryProfile `json:"profile"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(resp.Results, []uint64{2}) {
+			t.Fatalf("unexpected results: %v", resp.Results)
+		} else if p := resp.Profile; p == nil || len(p.Calls) != 1 || p.Calls[0].Name != "Count" || len(p.Calls[0].Children) != 1 || p.Calls[0].Children[0].Name != "Row" {
+			t.Fatalf("unexpected profile: %s", w.Body.String())
+		} else if shards := p.Calls[0].Children[0].Shards; len(shards) != 2 || shards[0].Shard != 0 || shards[1].Shard != 1 {
+			t.Fatalf("unexpected shards: %s", w.Body.String())
+		}
+	})
+
 	t.Run("Query args error", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?shards=a,b", strings.NewReader("Count(Row(f0=30))")))
@@ -274,6 +362,38 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Row stream", func(t *testing.T) {
+		exp := `{"columns":[1048577,1048578]}` + "\n" + `{"columns":[3145732]}` + "\n" + `{"count":3}` + "\n"
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?stream=true", strings.NewReader("Row(f0=30)")))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Fatalf("unexpected content type: %s", ct)
+		} else if body := w.Body.String(); body != exp {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		w = httptest.NewRecorder()
+		r := test.MustNewHTTPRequest("POST", "/index/i0/query", strings.NewReader("Row(f0=30)"))
+		r.Header.Set("Accept", "application/x-ndjson")
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if body := w.Body.String(); body != exp {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query?stream=true", strings.NewReader("Count(Row(f0=30))")))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if body := w.Body.String(); !strings.Contains(body, `{"error":"executing: Count(): only a single call returning a row can be streamed"`) {
+			t.Fatalf("unexpected body: %s", body)
+		}
+	})
+
 	f0 := i0.Field("f0")
 	if err := i0.ColumnAttrStore().SetAttrs((1*pilosa.ShardWidth)+1, map[string]interface{}{"x": "y"}); err != nil {
 		t.Fatal(err)
@@ -396,9 +516,9 @@ func TestHandler_Endpoints(t *testing.T) {
 	t.Run("Query err JSON", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i0/query", strings.NewReader(`Row(row=30)`)))
-		if w.Code != gohttp.StatusBadRequest {
+		if w.Code != gohttp.StatusNotFound {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if body := w.Body.String(); body != `{"error":"executing: map reduce: field not found"}`+"\n" {
+		} else if body := w.Body.String(); body != `{"error":"executing: map reduce: field not found","code":"field-not-found"}`+"\n" {
 			t.Fatalf("unexpected body: %q", body)
 		}
 	})
@@ -408,7 +528,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		r := test.MustNewHTTPRequest("POST", "/index/i0/query", strings.NewReader(`Row(row=30)`))
 		r.Header.Set("Accept", "application/x-protobuf")
 		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusBadRequest {
+		if w.Code != gohttp.StatusNotFound {
 			t.Fatalf("unexpected status code: %d", w.Code)
 		}
 
@@ -433,7 +553,7 @@ func TestHandler_Endpoints(t *testing.T) {
 		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idx0/query?shards=0,1", strings.NewReader("bad_fn(")))
 		if w.Code != gohttp.StatusBadRequest {
 			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if body := w.Body.String(); body != `{"error":"parsing: parsing: \nparse error near IDENT (line 1 symbol 1 - line 1 symbol 4):\n\"bad\"\n"}`+"\n" {
+		} else if body := w.Body.String(); body != `{"error":"parsing: parsing: \nparse error near IDENT (line 1 symbol 1 - line 1 symbol 4):\n\"bad\"\n","code":"query-parse","position":{"line":1,"symbol":1}}`+"\n" {
 			t.Fatalf("unexpected body: %s", body)
 		}
 	})
@@ -606,201 +726,1581 @@ func TestHandler_Endpoints(t *testing.T) {
 		}
 	})
 
-	t.Run("CORS", func(t *testing.T) {
-		req := test.MustNewHTTPRequest("OPTIONS", "/index/foo/query", nil)
-		req.Header.Add("Origin", "http://test/")
-		req.Header.Add("Access-Control-Request-Method", "POST")
-
+	t.Run("Rebuild Caches", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		h.ServeHTTP(w, req)
-		result := w.Result()
-
-		// This handler does not support CORS, return Method Not Allowed (405)
-		if result.StatusCode != 405 {
-			t.Fatalf("CORS preflight status should be 405, but is %v", result.StatusCode)
-		}
-
-		clus := test.MustRunCluster(t, 1, []server.CommandOption{test.OptAllowedOrigins([]string{"http://test/"})})
-		w = httptest.NewRecorder()
-		h := clus[0].Handler.(*http.Handler).Handler
-		h.ServeHTTP(w, req)
-		result = w.Result()
-
-		if result.StatusCode != 200 {
-			t.Fatalf("CORS preflight status should be 200, but is %v", result.StatusCode)
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/recalculate-caches?rebuild=true", nil))
+		if w.Code != gohttp.StatusAccepted {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
 		}
+	})
 
-		if result.Header["Access-Control-Allow-Origin"][0] != "http://test/" {
-			t.Fatal("CORS header not present")
+	t.Run("Field Cache", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("ic", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("f", pilosa.OptFieldTypeDefault()); err != nil {
+			t.Fatal(err)
 		}
-	})
+		hldr.MustSetBits("ic", "f", 1, 1, 2, 3)
+		hldr.MustSetBits("ic", "f", 2, 1)
 
-	t.Run("index handlers", func(t *testing.T) {
-		// create index
 		w := httptest.NewRecorder()
-		r := test.MustNewHTTPRequest("POST", "/index/idx1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, test.MustNewHTTPRequest("DELETE", "/index/ic/field/f/cache", nil))
 		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"fragments":[{"shard":0,"size":2}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
 		}
 
-		// create index again
 		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/index/idx1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusConflict {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":false,"error":{"message":"index already exists"}}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ic/field/f/cache?shard=0", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"fragments":[{"shard":0,"size":2,"pairs":[{"id":1,"count":3},{"id":2,"count":1}]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
 		}
 
-		// create field
 		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/index/idx1/field/fld1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ic/field/f/cache?shard=5", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
 		}
 
-		// create field again
 		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/index/idx1/field/fld1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusConflict {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":false,"error":{"message":"field already exists"}}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ic/field/nope/cache", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
 		}
+	})
 
-		// delete field
-		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("DELETE", "/index/idx1/field/fld1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
+	t.Run("Field time quantum", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("itq", pilosa.IndexOptions{})
+		f, err := i.CreateFieldIfNotExists("t", pilosa.OptFieldTypeTime("YMD"))
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err := i.CreateFieldIfNotExists("s", pilosa.OptFieldTypeDefault()); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", "/index/itq/field/t", strings.NewReader(`{"options":{"timeQuantum":"YMDH"}}`)))
 		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if q := f.TimeQuantum(); q != "YMDH" {
+			t.Fatalf("unexpected time quantum: %s", q)
 		}
 
-		// delete field again
+		// Subsequent writes use the new quantum.
 		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("DELETE", "/index/idx1/field/fld1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusNotFound {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":false,"error":{"message":"field not found"}}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/itq/query", strings.NewReader(`Set(1, t=1, 2018-01-02T03:04)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
 		}
 
-		// delete index
+		// An hour range is covered by the hour view written above.
 		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("DELETE", "/index/idx1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/itq/query", strings.NewReader(`Range(t=1, from=2018-01-02T03:00, to=2018-01-02T04:00)`)))
 		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[{"attrs":{},"columns":[1]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
 		}
 
-		// delete index again
-		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("DELETE", "/index/idx1", strings.NewReader(""))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusNotFound {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":false,"error":{"message":"index not found"}}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/itq/field/t", body: `{"options":{"timeQuantum":"HD"}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itq/field/t", body: `{"options":{}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itq/field/t", body: `{"options":{"cacheSize":10}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itq/field/s", body: `{"options":{"timeQuantum":"YMD"}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itq/field/nope", body: `{"options":{"timeQuantum":"YMD"}}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+		if q := f.TimeQuantum(); q != "YMDH" {
+			t.Fatalf("unexpected time quantum: %s", q)
 		}
 	})
 
-	t.Run("translate keys", func(t *testing.T) {
-		// create index
+	t.Run("Delete fragment data", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("idf", pilosa.IndexOptions{})
+		hldr.MustSetBits("idf", "f", 1, 1, 2*pilosa.ShardWidth+1)
+
 		w := httptest.NewRecorder()
-		r := test.MustNewHTTPRequest("POST", "/index/i1-tr", strings.NewReader(`{"options":{"keys":true}}`))
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, test.MustNewHTTPRequest("DELETE", "/internal/fragment/data?index=idf&field=f&view=standard&shard=2", nil))
 		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if columns := hldr.Row("idf", "f", 1).Columns(); !reflect.DeepEqual(columns, []uint64{1}) {
+			t.Fatalf("unexpected columns: %v", columns)
+		}
+
+		// No other view or node holds the shard, so it's no longer available.
+		if n := cmd.API.MaxShards(context.Background())["idf"]; n != 0 {
+			t.Fatalf("unexpected max shard: %d", n)
+		}
+
+		for _, tt := range []struct {
+			url  string
+			code int
+		}{
+			{url: "/internal/fragment/data?index=idf&field=f&view=standard&shard=2", code: gohttp.StatusNotFound},
+			{url: "/internal/fragment/data?index=idf&field=f&view=standard_2019&shard=0", code: gohttp.StatusNotFound},
+			{url: "/internal/fragment/data?index=idf&field=nope&view=standard&shard=0", code: gohttp.StatusNotFound},
+			{url: "/internal/fragment/data?index=idf&field=f&view=standard&shard=x", code: gohttp.StatusBadRequest},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("DELETE", tt.url, nil))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.url, w.Code, w.Body.String())
+			}
 		}
 
-		// create field
-		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/index/i1-tr/field/f1", strings.NewReader(`{"options":{"keys":true}}`))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		} else if w.Body.String() != `{"success":true}`+"\n" {
-			t.Fatalf("unexpected body: %q", w.Body.String())
+		// Writing to the shard again starts a new, empty fragment.
+		hldr.MustSetBits("idf", "f", 2, 2*pilosa.ShardWidth+5)
+		if columns := hldr.Row("idf", "f", 1).Columns(); !reflect.DeepEqual(columns, []uint64{1}) {
+			t.Fatalf("unexpected columns: %v", columns)
+		} else if columns := hldr.Row("idf", "f", 2).Columns(); !reflect.DeepEqual(columns, []uint64{2*pilosa.ShardWidth + 5}) {
+			t.Fatalf("unexpected columns: %v", columns)
+		} else if n := cmd.API.MaxShards(context.Background())["idf"]; n != 2 {
+			t.Fatalf("unexpected max shard: %d", n)
 		}
+	})
 
-		// set some bits
-		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/index/i1-tr/query", strings.NewReader(`Set("col1", f1="row1")`))
-		h.ServeHTTP(w, r)
-		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
+	t.Run("Compact fragment", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("icf", pilosa.IndexOptions{})
+		columnIDs := make([]uint64, 2000)
+		for i := range columnIDs {
+			columnIDs[i] = uint64(i) * 30
 		}
+		hldr.MustSetBits("icf", "f", 1, columnIDs...)
+		for _, columnID := range columnIDs {
+			hldr.ClearBit("icf", "f", 1, columnID)
+		}
+		hldr.MustSetBits("icf", "f", 2, 3)
 
-		// Generate request body for translate column keys request
-		reqBody, err := cmd.API.Serializer.Marshal(&pilosa.TranslateKeysRequest{
-			Index: "i1-tr",
-			Keys:  []string{"col1", "col2", "col3"},
-		})
+		path := filepath.Join(holder.Path, "icf", "f", "views", "standard", "fragments", "0")
+		before, err := os.Stat(path)
 		if err != nil {
 			t.Fatal(err)
 		}
-		// Generate protobuf request.
-		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/internal/translate/keys", bytes.NewReader(reqBody))
-		r.Header.Set("Content-Type", "application/x-protobuf")
-		r.Header.Set("Accept", "application/x-protobuf")
-		h.ServeHTTP(w, r)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/internal/fragment/compact?index=icf&field=f&view=standard&shard=0", nil))
 		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
 		}
-		target := []uint64{1, 2, 3}
-		resp := pilosa.TranslateKeysResponse{}
-		err = cmd.API.Serializer.Unmarshal(w.Body.Bytes(), &resp)
-		if err != nil {
-			t.Fatal(err)
+		var resp struct {
+			Reclaimed int64 `json:"reclaimed"`
 		}
-		if !reflect.DeepEqual(target, resp.IDs) {
-			t.Fatalf("%v != %v", target, resp.IDs)
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
 		}
-
-		// Generate request body for translate row keys request
-		reqBody, err = cmd.API.Serializer.Marshal(&pilosa.TranslateKeysRequest{
-			Index: "i1-tr",
-			Field: "f1",
-			Keys:  []string{"row1", "row2"},
-		})
+		after, err := os.Stat(path)
 		if err != nil {
 			t.Fatal(err)
+		} else if after.Size() >= before.Size() {
+			t.Fatalf("file didn't shrink: before=%d, after=%d", before.Size(), after.Size())
+		} else if resp.Reclaimed != before.Size()-after.Size() {
+			t.Fatalf("unexpected reclaimed bytes: %d, want %d", resp.Reclaimed, before.Size()-after.Size())
+		} else if columns := hldr.Row("icf", "f", 2).Columns(); !reflect.DeepEqual(columns, []uint64{3}) {
+			t.Fatalf("unexpected columns: %v", columns)
 		}
-		// Generate protobuf request.
+
+		// Without a shard every fragment of the view is compacted, and
+		// there's nothing left to reclaim.
 		w = httptest.NewRecorder()
-		r = test.MustNewHTTPRequest("POST", "/internal/translate/keys", bytes.NewReader(reqBody))
-		r.Header.Set("Content-Type", "application/x-protobuf")
-		r.Header.Set("Accept", "application/x-protobuf")
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/internal/fragment/compact?index=icf&field=f&view=standard", nil))
 		if w.Code != gohttp.StatusOK {
-			t.Fatalf("unexpected status code: %d", w.Code)
-		}
-		target = []uint64{1, 2}
-		resp = pilosa.TranslateKeysResponse{}
-		err = cmd.API.Serializer.Unmarshal(w.Body.Bytes(), &resp)
-		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"success":true,"reclaimed":0}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
 		}
-		if !reflect.DeepEqual(target, resp.IDs) {
-			t.Fatalf("%v != %v", target, resp.IDs)
+
+		for _, tt := range []struct {
+			url  string
+			code int
+		}{
+			{url: "/internal/fragment/compact?index=icf&field=f&view=standard&shard=1", code: gohttp.StatusNotFound},
+			{url: "/internal/fragment/compact?index=icf&field=f&view=standard_2019", code: gohttp.StatusNotFound},
+			{url: "/internal/fragment/compact?index=icf&field=nope&view=standard", code: gohttp.StatusNotFound},
+			{url: "/internal/fragment/compact?index=icf&field=f&view=standard&shard=x", code: gohttp.StatusBadRequest},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.url, nil))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.url, w.Code, w.Body.String())
+			}
 		}
 	})
+
+	t.Run("Column attr search", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("ica", pilosa.IndexOptions{})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ica/query", strings.NewReader(
+			`SetColumnAttrs(1, name="Alice") SetColumnAttrs(2, name="bob") SetColumnAttrs(3, name="alex", age=30) SetColumnAttrs(4, name=5)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		for _, tt := range []struct {
+			body string
+			exp  string
+		}{
+			{body: `{"attr":"name","like":"AL"}`, exp: `{"columnAttrs":[{"id":1,"attrs":{"name":"Alice"}},{"id":3,"attrs":{"age":30,"name":"alex"}}],"truncated":false}`},
+			{body: `{"attr":"name","like":"al","limit":1}`, exp: `{"columnAttrs":[{"id":1,"attrs":{"name":"Alice"}}],"truncated":true}`},
+			{body: `{"attr":"name","equals":"bob"}`, exp: `{"columnAttrs":[{"id":2,"attrs":{"name":"bob"}}],"truncated":false}`},
+			{body: `{"attr":"name","equals":"Bob"}`, exp: `{"columnAttrs":[],"truncated":false}`},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ica/column-attrs/search", strings.NewReader(tt.body)))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.body, w.Code, w.Body.String())
+			} else if body := w.Body.String(); body != tt.exp+"\n" {
+				t.Fatalf("%s: unexpected body: %s", tt.body, body)
+			}
+		}
+
+		for _, tt := range []struct {
+			url  string
+			body string
+			code int
+		}{
+			{url: "/index/ica/column-attrs/search", body: `{"like":"al"}`, code: gohttp.StatusBadRequest},
+			{url: "/index/ica/column-attrs/search", body: `{"attr":"name"}`, code: gohttp.StatusBadRequest},
+			{url: "/index/ica/column-attrs/search", body: `{"attr":"name","like":"a","equals":"b"}`, code: gohttp.StatusBadRequest},
+			{url: "/index/ica/column-attrs/search", body: `{"attr":"name","like":"a","limit":1001}`, code: gohttp.StatusBadRequest},
+			{url: "/index/ica/column-attrs/search", body: `{"attr":"name","regex":"a"}`, code: gohttp.StatusBadRequest},
+			{url: "/index/nope/column-attrs/search", body: `{"attr":"name","like":"a"}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.url, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Row attrs bulk", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("irab", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("f"); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/irab/query", strings.NewReader(
+			`SetRowAttrs(f, 1, name="a") SetRowAttrs(f, 150, name="b", n=2)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/irab/field/f/attr/bulk", strings.NewReader(`{"ids":[150,2,1]}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"attrs":{"1":{"name":"a"},"150":{"n":2,"name":"b"},"2":{}}}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		tooMany := make([]uint64, pilosa.MaxBulkRowAttrIDs+1)
+		buf, err := json.Marshal(map[string]interface{}{"ids": tooMany})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range []struct {
+			url  string
+			body string
+			code int
+		}{
+			{url: "/index/irab/field/f/attr/bulk", body: string(buf), code: gohttp.StatusBadRequest},
+			{url: "/index/irab/field/f/attr/bulk", body: `{"ids":["a"]}`, code: gohttp.StatusBadRequest},
+			{url: "/index/irab/field/nope/attr/bulk", body: `{"ids":[1]}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.url, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.url, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Delete columns", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("idc", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("f"); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idc/query", strings.NewReader(fmt.Sprintf(
+			`Set(1, f=1) Set(5, f=1) Set(10, f=1) Set(%d, f=1) SetColumnAttrs(1, x=1) SetColumnAttrs(5, x=2)`, pilosa.ShardWidth+1))))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idc/delete-columns", strings.NewReader(fmt.Sprintf(`{"min":5,"max":%d}`, pilosa.ShardWidth))))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idc/query?columnAttrs=true", strings.NewReader(`Row(f=1)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body, exp := w.Body.String(), fmt.Sprintf(`{"results":[{"attrs":{},"columns":[1,%d]}],"columnAttrs":[{"id":1,"attrs":{"x":1}}]}`+"\n", pilosa.ShardWidth+1); body != exp {
+			t.Fatalf("unexpected body: %s", body)
+		}
+		if attrs, err := i.ColumnAttrStore().Attrs(5); err != nil {
+			t.Fatal(err)
+		} else if len(attrs) != 0 {
+			t.Fatalf("unexpected attrs: %v", attrs)
+		}
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/idc/delete-columns", body: `{}`, code: gohttp.StatusBadRequest},
+			{path: "/index/idc/delete-columns", body: `{"min":5,"max":1}`, code: gohttp.StatusBadRequest},
+			{path: "/index/nope/delete-columns", body: `{"min":1,"max":5}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Debug holder", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("idh", pilosa.IndexOptions{})
+		for _, name := range []string{"f", "g"} {
+			if _, err := i.CreateFieldIfNotExists(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idh/query", strings.NewReader(
+			fmt.Sprintf(`Set(1, f=1) Set(%d, f=1) Set(1, g=3)`, pilosa.ShardWidth+1))))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		var resp struct {
+			Views  []*pilosa.ViewStats      `json:"views"`
+			Totals pilosa.HolderStatsTotals `json:"totals"`
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/debug/holder?index=idh&field=f", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decoding %s: %v", w.Body.String(), err)
+		}
+		if len(resp.Views) != 1 {
+			t.Fatalf("unexpected views: %s", w.Body.String())
+		} else if vs := resp.Views[0]; vs.Index != "idh" || vs.Field != "f" || vs.View.Name != "standard" || vs.View.BitCount != 2 || vs.View.RowCount != 2 {
+			t.Fatalf("unexpected view: %s", w.Body.String())
+		} else if len(vs.Fragments) != 2 || vs.Fragments[0].Shard != 0 || vs.Fragments[1].Shard != 1 {
+			t.Fatalf("unexpected fragments: %s", w.Body.String())
+		} else if !vs.Fragments[0].Open || vs.Fragments[0].Containers != 1 || vs.Fragments[0].CacheEntries != 1 || vs.Fragments[0].BitCount != 1 || vs.Fragments[0].RowCount != 1 {
+			t.Fatalf("unexpected fragment: %s", w.Body.String())
+		}
+		if exp := (pilosa.HolderStatsTotals{
+			Indexes:       1,
+			Fields:        1,
+			Views:         1,
+			Fragments:     2,
+			OpenFragments: 2,
+			BitCount:      2,
+			FileBytes:     resp.Views[0].Fragments[0].FileBytes + resp.Views[0].Fragments[1].FileBytes,
+			Containers:    2,
+			CacheEntries:  2,
+		}); resp.Totals != exp {
+			t.Fatalf("unexpected totals: %+v", resp.Totals)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/debug/holder?index=idh", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decoding %s: %v", w.Body.String(), err)
+		} else if len(resp.Views) != 2 || resp.Views[1].Field != "g" || resp.Totals.Fields != 2 || resp.Totals.Fragments != 3 {
+			t.Fatalf("unexpected response: %s", w.Body.String())
+		}
+
+		for _, tt := range []struct {
+			url  string
+			code int
+		}{
+			{url: "/debug/holder?index=nope", code: gohttp.StatusNotFound},
+			{url: "/debug/holder?index=idh&field=nope", code: gohttp.StatusNotFound},
+			{url: "/debug/holder?field=f", code: gohttp.StatusBadRequest},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", tt.url, nil))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.url, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Field cache size", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("ics", pilosa.IndexOptions{})
+		f, err := i.CreateFieldIfNotExists("r", pilosa.OptFieldTypeSet(pilosa.CacheTypeRanked, 100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hldr.MustSetBits("ics", "r", 1, 1)
+		hldr.MustSetBits("ics", "r", 2, 1, 2)
+		hldr.MustSetBits("ics", "r", 3, 1, 2, 3)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", "/index/ics/field/r", strings.NewReader(`{"options":{"cacheSize":2}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if n := f.CacheSize(); n != 2 {
+			t.Fatalf("unexpected cache size: %d", n)
+		}
+
+		// The rows beyond the new size are evicted.
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/recalculate-caches", nil))
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ics/query", strings.NewReader(`TopN(r)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[[{"id":3,"count":3},{"id":2,"count":2}]]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/ics/field/r", body: `{"options":{"cacheSize":0}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/ics/field/r", body: `{"options":{"cacheSize":-1}}`, code: gohttp.StatusBadRequest},
+			{path: "/index/ics/field/nope", body: `{"options":{"cacheSize":10}}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+		if n := f.CacheSize(); n != 2 {
+			t.Fatalf("unexpected cache size: %d", n)
+		}
+	})
+
+	t.Run("Labels", func(t *testing.T) {
+		for _, req := range []struct{ path, body string }{
+			{"/index/ilabel", `{"options":{"columnLabel":"user"}}`},
+			{"/index/ilabel/field/traffic", `{"options":{"rowLabel":"site"}}`},
+			{"/index/ilabel/query", `Set(user=10, site=3) SetColumnAttrs(user=10, x=1)`},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", req.path, strings.NewReader(req.body)))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", req.path, w.Code, w.Body.String())
+			}
+		}
+
+		// Responses only use the labels when asked to.
+		for _, tt := range []struct {
+			query string
+			exp   string
+		}{
+			{query: "columnAttrs=true", exp: `{"results":[[{"id":3,"count":1}],{"attrs":{},"columns":[10]}],"columnAttrs":[{"id":10,"attrs":{"x":1}}]}` + "\n"},
+			{query: "columnAttrs=true&labels=true", exp: `{"results":[[{"count":1,"site":3}],{"attrs":{},"columns":[10]}],"columnAttrs":[{"attrs":{"x":1},"user":10}]}` + "\n"},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ilabel/query?"+tt.query, strings.NewReader(`TopN(traffic) Row(site=3)`)))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+			} else if body := w.Body.String(); body != tt.exp {
+				t.Fatalf("%s: unexpected body: %s", tt.query, body)
+			}
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ilabel", nil))
+		if body := w.Body.String(); !strings.Contains(body, `"columnLabel":"user"`) || !strings.Contains(body, `"rowLabel":"site"`) {
+			t.Fatalf("unexpected schema: %s", body)
+		}
+
+		for _, req := range []struct{ path, body string }{
+			{"/index/ilabel2", `{"options":{"columnLabel":"limit"}}`},
+			{"/index/ilabel/field/user", `{}`},
+			{"/index/ilabel/field/n", `{"options":{"type":"int","min":0,"max":10,"rowLabel":"value"}}`},
+			{"/index/ilabel/field/visits", `{"options":{"rowLabel":"traffic"}}`},
+			{"/index/ilabel/field/visits", `{"options":{"rowLabel":"bad label"}}`},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", req.path, strings.NewReader(req.body)))
+			if w.Code != gohttp.StatusBadRequest {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", req.path, req.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Index time quantum", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idtq", strings.NewReader(`{"options":{"timeQuantum":"YMD"}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idtq/field/a", strings.NewReader(`{"options":{"type":"time"}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		// Changing the default only affects fields created afterwards.
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("PATCH", "/index/idtq", strings.NewReader(`{"options":{"timeQuantum":"YMDH"}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		for _, tt := range []struct {
+			field string
+			body  string
+		}{
+			{field: "b", body: `{"options":{"type":"time"}}`},
+			{field: "c", body: `{"options":{"type":"time","timeQuantum":"YM"}}`},
+		} {
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/idtq/field/"+tt.field, strings.NewReader(tt.body)))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+			}
+		}
+
+		// The schema shows each field's quantum and whether it was inherited.
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/idtq", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		var info pilosa.IndexInfo
+		if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+			t.Fatal(err)
+		} else if info.Options.TimeQuantum != "YMDH" {
+			t.Fatalf("unexpected index time quantum: %s", info.Options.TimeQuantum)
+		}
+		exp := map[string]pilosa.FieldOptions{
+			"a": {TimeQuantum: "YMD", TimeQuantumInherited: true},
+			"b": {TimeQuantum: "YMDH", TimeQuantumInherited: true},
+			"c": {TimeQuantum: "YM"},
+		}
+		for _, f := range info.Fields {
+			if e, ok := exp[f.Name]; !ok {
+				continue
+			} else if f.Options.TimeQuantum != e.TimeQuantum || f.Options.TimeQuantumInherited != e.TimeQuantumInherited {
+				t.Fatalf("field %s: unexpected options: %+v", f.Name, f.Options)
+			}
+			delete(exp, f.Name)
+		}
+		if len(exp) > 0 {
+			t.Fatalf("missing fields: %v", exp)
+		}
+
+		for _, tt := range []struct {
+			method string
+			path   string
+			body   string
+			code   int
+		}{
+			{method: "PATCH", path: "/index/idtq", body: `{"options":{"timeQuantum":"HD"}}`, code: gohttp.StatusBadRequest},
+			{method: "PATCH", path: "/index/idtq", body: `{"options":{}}`, code: gohttp.StatusBadRequest},
+			{method: "PATCH", path: "/index/idtq", body: `{"options":{"keys":true}}`, code: gohttp.StatusBadRequest},
+			{method: "PATCH", path: "/index/nope", body: `{"options":{"timeQuantum":"YMD"}}`, code: gohttp.StatusNotFound},
+			{method: "POST", path: "/index/idtq2", body: `{"options":{"timeQuantum":"HD"}}`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/itq/field/nq", body: `{"options":{"type":"time"}}`, code: gohttp.StatusBadRequest},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s %s: unexpected status code: %d, body: %s", tt.method, tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Index shard width", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/isw", strings.NewReader(`{"options":{"shardWidth":131072}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/isw", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		var info pilosa.IndexInfo
+		if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+			t.Fatal(err)
+		} else if info.Options.ShardWidth != 1<<17 || info.ShardWidth != 1<<17 {
+			t.Fatalf("unexpected shard width: %+v", info)
+		}
+
+		for _, tt := range []struct {
+			method string
+			path   string
+			body   string
+			code   int
+		}{
+			{method: "PATCH", path: "/index/isw", body: `{"options":{"shardWidth":262144}}`, code: gohttp.StatusBadRequest},
+			{method: "POST", path: "/index/isw", body: `{"options":{"shardWidth":262144}}`, code: gohttp.StatusConflict},
+			{method: "POST", path: "/index/isw2", body: `{"options":{"shardWidth":100000}}`, code: gohttp.StatusBadRequest},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s %s: unexpected status code: %d, body: %s", tt.method, tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+		if idx := holder.Index("isw"); idx.ShardWidth() != 1<<17 {
+			t.Fatalf("unexpected shard width after change: %d", idx.ShardWidth())
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("iret", pilosa.IndexOptions{})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iret/field/t", strings.NewReader(`{"options":{"type":"time","timeQuantum":"YMDH","retention":{"hour":"48h"}}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iret/field/s", strings.NewReader(`{"options":{"type":"set","retention":{"hour":"48h"}}}`)))
+		if w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		f := hldr.Field("iret", "t")
+		if f == nil {
+			t.Fatal("expected field")
+		}
+		opts := f.Options()
+		if buf, err := json.Marshal(&opts); err != nil {
+			t.Fatal(err)
+		} else if string(buf) != `{"type":"time","timeQuantum":"YMDH","timeQuantumInherited":false,"keys":false,"noStandardView":false,"retention":{"hour":"48h0m0s"}}` {
+			t.Fatalf("unexpected options: %s", buf)
+		}
+
+		// Only the hour view is listed and nothing is deleted.
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iret/query", strings.NewReader(`Set(1, t=1, 2018-01-02T03:04)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		for i := 0; i < 2; i++ {
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/retention/expired", nil))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+			} else if body := w.Body.String(); body != `{"views":[{"index":"iret","field":"t","view":"standard_2018010203"}]}`+"\n" {
+				t.Fatalf("unexpected body: %s", body)
+			}
+		}
+	})
+
+	t.Run("AttrCommit", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("iac", pilosa.IndexOptions{})
+
+		for body, code := range map[string]int{
+			`{"options":{"attrCommit":{"mode":"batched","interval":"5ms","maxOps":100}}}`: gohttp.StatusOK,
+			`{"options":{"attrCommit":{"mode":"sometimes"}}}`:                             gohttp.StatusBadRequest,
+			`{"options":{"attrCommit":{"mode":"always","maxOps":100}}}`:                   gohttp.StatusBadRequest,
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iac/field/f", strings.NewReader(body)))
+			if w.Code != code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", body, w.Code, w.Body.String())
+			}
+		}
+
+		opts := hldr.Field("iac", "f").Options()
+		if buf, err := json.Marshal(&opts); err != nil {
+			t.Fatal(err)
+		} else if string(buf) != `{"type":"set","cacheType":"ranked","cacheSize":50000,"keys":false,"attrCommit":{"mode":"batched","interval":"5ms","maxOps":100}}` {
+			t.Fatalf("unexpected options: %s", buf)
+		}
+
+		// Batched writes are read back at once.
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iac/query", strings.NewReader(`Set(1, f=10) SetRowAttrs(f, 10, x=1)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iac/query", strings.NewReader(`Row(f=10)`)))
+		if body := w.Body.String(); body != `{"results":[{"attrs":{"x":1},"columns":[1]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+	})
+
+	t.Run("Time migration", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("itm", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("t", pilosa.OptFieldTypeTime("YMD")); err != nil {
+			t.Fatal(err)
+		} else if _, err := i.CreateFieldIfNotExists("s", pilosa.OptFieldTypeDefault()); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/itm/field/t/time-migration", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/itm/field/t/time-migration", strings.NewReader(`{"from":"D","to":"M"}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		var m pilosa.TimeMigration
+		for n := 0; !m.Done; n++ {
+			if n == 100 {
+				t.Fatal("time migration didn't finish")
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/itm/field/t/time-migration", nil))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+			} else if err := json.Unmarshal(w.Body.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if m.Index != "itm" || m.Field != "t" || m.From != "D" || m.To != "M" || m.Error != "" {
+			t.Fatalf("unexpected migration: %+v", m)
+		}
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/itm/field/t/time-migration", body: `{"from":"M","to":"D"}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itm/field/t/time-migration", body: `{"from":"D","to":"M","x":1}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itm/field/s/time-migration", body: `{"from":"D"}`, code: gohttp.StatusBadRequest},
+			{path: "/index/itm/field/nope/time-migration", body: `{"from":"D"}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Field copy", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("ifc", pilosa.IndexOptions{})
+		query := func(q string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ifc/query", strings.NewReader(q)))
+			return w
+		}
+		if _, err := hldr.Index("ifc").CreateField("s"); err != nil {
+			t.Fatal(err)
+		} else if w := query(`Set(1, s=10) Set(2, s=10) SetRowAttrs(s, 10, x=1)`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ifc/field/c/copy", strings.NewReader(`{"source":"s","options":{"cacheType":"lru"}}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		var c pilosa.FieldCopy
+		for n := 0; !c.Done; n++ {
+			if n == 100 {
+				t.Fatal("field copy didn't finish")
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/ifc/field/c/copy", nil))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+			} else if err := json.Unmarshal(w.Body.Bytes(), &c); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if c.Index != "ifc" || c.Field != "c" || c.Source != "s" || c.Copied != 1 || c.Ready || c.Error != "" {
+			t.Fatalf("unexpected copy: %+v", c)
+		}
+
+		// The destination can't be used until it is ready.
+		if w := query(`Row(c=10)`); w.Code == gohttp.StatusOK || !strings.Contains(w.Body.String(), "field is being copied") {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ifc/field/c/copy/ready", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		if w := query(`Row(c=10)`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[{"attrs":{"x":1},"columns":[1,2]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/ifc/field/d/copy", body: `{}`, code: gohttp.StatusBadRequest},
+			{path: "/index/ifc/field/d/copy", body: `{"source":"s","x":1}`, code: gohttp.StatusBadRequest},
+			{path: "/index/ifc/field/d/copy", body: `{"source":"nope"}`, code: gohttp.StatusNotFound},
+			{path: "/index/ifc/field/c/copy", body: `{"source":"s"}`, code: gohttp.StatusConflict},
+			{path: "/index/ifc/field/s/copy/ready", code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Field copy to another index", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("ifcs", pilosa.IndexOptions{})
+		query := func(index, q string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/"+index+"/query", strings.NewReader(q)))
+			return w
+		}
+		if _, err := hldr.Index("ifcs").CreateField("s"); err != nil {
+			t.Fatal(err)
+		} else if w := query("ifcs", `Set(1, s=10) Set(2, s=10) SetRowAttrs(s, 10, x=1)`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		copyField := func(body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ifcd/field/c/copy", strings.NewReader(body)))
+			return w
+		}
+		waitCopy := func(id string) pilosa.FieldCopy {
+			var c pilosa.FieldCopy
+			for n := 0; !c.Done; n++ {
+				if n == 100 {
+					t.Fatal("field copy didn't finish")
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/field-copy/"+id, nil))
+				if w.Code != gohttp.StatusOK {
+					t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+				} else if err := json.Unmarshal(w.Body.Bytes(), &c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return c
+		}
+
+		// The destination index is created by the copy.
+		if w := copyField(`{"id":"job1","sourceIndex":"ifcs","source":"s"}`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		c := waitCopy("job1")
+		if c.ID != "job1" || c.Index != "ifcd" || c.Field != "c" || c.SourceIndex != "ifcs" || c.Source != "s" || c.Copied != 1 || c.Error != "" {
+			t.Fatalf("unexpected copy: %+v", c)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ifcd/field/c/copy/ready", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		if w := query("ifcd", `Row(c=10)`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[{"attrs":{"x":1},"columns":[1,2]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		// Copying again replaces the destination only when asked to.
+		if w := query("ifcs", `Set(3, s=10)`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if w := copyField(`{"sourceIndex":"ifcs","source":"s"}`); w.Code != gohttp.StatusConflict {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if w := copyField(`{"id":"job2","sourceIndex":"ifcs","source":"s","overwrite":true}`); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if c := waitCopy("job2"); c.Error != "" {
+			t.Fatalf("unexpected copy: %+v", c)
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ifcd/field/c/copy/ready", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if w := query("ifcd", `Row(c=10)`); !strings.Contains(w.Body.String(), `"columns":[1,2,3]`) {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+
+		// The first copy's destination was replaced.
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/field-copy/job1", nil))
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Invalid view names", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("iview", pilosa.IndexOptions{})
+		if _, err := hldr.Index("iview").CreateField("s"); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range []struct {
+			method, path string
+		}{
+			{method: "GET", path: "/internal/fragment/data?index=iview&field=s&view=..%2F..&shard=0"},
+			{method: "DELETE", path: "/internal/fragment/data?index=iview&field=s&view=..%2F..&shard=0"},
+			{method: "GET", path: "/internal/fragment/blocks?index=iview&field=s&view=..%2F..&shard=0"},
+			{method: "GET", path: "/internal/fragment/checksums?index=iview&field=s&view=..%2F.."},
+			{method: "POST", path: "/internal/fragment/compact?index=iview&field=s&view=..%2F.."},
+			{method: "GET", path: "/export?index=iview&field=s&shard=0&format=proto&view=..%2F.."},
+			{method: "DELETE", path: "/index/iview/field/s/view/standard.2019"},
+			{method: "DELETE", path: "/index/iview/field/s/view/standard%00"},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest(tt.method, tt.path, nil))
+			if w.Code != gohttp.StatusBadRequest {
+				t.Errorf("%s %s: unexpected status code: %d, body: %s", tt.method, tt.path, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Index rename", func(t *testing.T) {
+		hldr.MustCreateIndexIfNotExists("iren", pilosa.IndexOptions{})
+		hldr.MustCreateIndexIfNotExists("irenk", pilosa.IndexOptions{Keys: true})
+		if _, err := hldr.Index("iren").CreateField("s"); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iren/query", strings.NewReader(`Set(1, s=10)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/schema", nil))
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("expected schema etag")
+		}
+		w = httptest.NewRecorder()
+		req := test.MustNewHTTPRequest("GET", "/schema", nil)
+		req.Header.Set("If-None-Match", etag)
+		h.ServeHTTP(w, req)
+		if w.Code != gohttp.StatusNotModified {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iren/rename", strings.NewReader(`{"name":"iren2"}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iren/query", strings.NewReader(`Row(s=10)`)))
+		if w.Code != gohttp.StatusNotFound || !strings.Contains(w.Body.String(), `"code":"index-not-found"`) {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iren2/query", strings.NewReader(`Row(s=10)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[{"attrs":{},"columns":[1]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/schema", nil))
+		if w.Header().Get("ETag") == etag {
+			t.Fatal("expected schema etag to change")
+		}
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/iren2/rename", body: `{}`, code: gohttp.StatusBadRequest},
+			{path: "/index/iren2/rename", body: `{"name":"Bad Name"}`, code: gohttp.StatusBadRequest},
+			{path: "/index/iren2/rename", body: `{"name":"irenk"}`, code: gohttp.StatusConflict},
+			{path: "/index/irenk/rename", body: `{"name":"irenk2"}`, code: gohttp.StatusBadRequest},
+			{path: "/index/iren/rename", body: `{"name":"iren3"}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Field rename", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("fren", pilosa.IndexOptions{})
+		if _, err := i.CreateField("s"); err != nil {
+			t.Fatal(err)
+		} else if _, err := i.CreateField("t"); err != nil {
+			t.Fatal(err)
+		} else if _, err := i.CreateField("k", pilosa.OptFieldKeys()); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/fren/query", strings.NewReader(`Set(1, s=10) SetRowAttrs(s, 10, x="y")`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/fren/field/s/rename", strings.NewReader(`{"name":"s2"}`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/fren/query", strings.NewReader(`Row(s=10)`)))
+		if w.Code != gohttp.StatusNotFound || !strings.Contains(w.Body.String(), `"code":"field-not-found"`) {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/fren/query", strings.NewReader(`Row(s2=10)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		} else if body := w.Body.String(); body != `{"results":[{"attrs":{"x":"y"},"columns":[1]}]}`+"\n" {
+			t.Fatalf("unexpected body: %s", body)
+		}
+
+		for _, tt := range []struct {
+			path string
+			body string
+			code int
+		}{
+			{path: "/index/fren/field/s2/rename", body: `{}`, code: gohttp.StatusBadRequest},
+			{path: "/index/fren/field/s2/rename", body: `{"name":"Bad Name"}`, code: gohttp.StatusBadRequest},
+			{path: "/index/fren/field/s2/rename", body: `{"name":"t"}`, code: gohttp.StatusConflict},
+			{path: "/index/fren/field/k/rename", body: `{"name":"k2"}`, code: gohttp.StatusBadRequest},
+			{path: "/index/fren/field/s/rename", body: `{"name":"s3"}`, code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Fatalf("%s %s: unexpected status code: %d, body: %s", tt.path, tt.body, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Import JSON lines", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("ijsonl", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("s"); err != nil {
+			t.Fatal(err)
+		}
+
+		post := func(query, body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req := test.MustNewHTTPRequest("POST", "/index/ijsonl/field/s/import"+query, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			req.Header.Set("Accept", "application/json")
+			h.ServeHTTP(w, req)
+			return w
+		}
+
+		if w := post("?profileField=user", fmt.Sprintf(`{"bitmapID": 3, "user": 1}
+{"bitmapID": 3, "user": %d, "name": "x"}`, pilosa.ShardWidth+2)); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/ijsonl/query", strings.NewReader(`Row(s=3)`)))
+		if body := w.Body.String(); !strings.Contains(body, fmt.Sprintf(`"columns":[1,%d]`, pilosa.ShardWidth+2)) {
+			t.Fatalf("unexpected query response: %s", body)
+		}
+
+		if w := post("", `{"bitmapID": 3, "profileID": 4}`+"\n"+`{"profileID": 5}`); w.Code != gohttp.StatusBadRequest || !strings.Contains(w.Body.String(), "missing bitmapID on line 2") {
+			t.Fatalf("unexpected response: %d, body: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Import gzip", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("igzip", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("s"); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("OPTIONS", "/index/igzip/field/s/import", nil))
+		if w.Code != gohttp.StatusNoContent || w.Header().Get("Accept-Encoding") != "gzip" {
+			t.Fatalf("unexpected response: %d, headers: %v", w.Code, w.Header())
+		}
+
+		post := func(h gohttp.Handler, encoding string, body []byte) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req := test.MustNewHTTPRequest("POST", "/index/igzip/field/s/import", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			req.Header.Set("Content-Encoding", encoding)
+			req.Header.Set("Accept", "application/json")
+			h.ServeHTTP(w, req)
+			return w
+		}
+		gzipped := func(s string) []byte {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			if _, err := zw.Write([]byte(s)); err != nil {
+				t.Fatal(err)
+			} else if err := zw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			return buf.Bytes()
+		}
+
+		body := gzipped(`{"bitmapID": 2, "profileID": 7}` + "\n" + `{"bitmapID": 2, "profileID": 9}`)
+		if w := post(h, "gzip", body); w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/igzip/query", strings.NewReader(`Row(s=2)`)))
+		if body := w.Body.String(); !strings.Contains(body, `"columns":[7,9]`) {
+			t.Fatalf("unexpected query response: %s", body)
+		}
+
+		if w := post(h, "gzip", []byte("not gzip")); w.Code != gohttp.StatusBadRequest {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+		if w := post(h, "br", body); w.Code != gohttp.StatusUnsupportedMediaType {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		// A body which decompresses past the limit is rejected.
+		clus := test.MustRunCluster(t, 1, []server.CommandOption{func(m *server.Command) error {
+			m.Config.Handler.MaxDecompressedImportSize = 64
+			return nil
+		}})
+		defer clus.Close()
+		hldr := test.Holder{Holder: clus[0].Server.Holder()}
+		if _, err := hldr.MustCreateIndexIfNotExists("igzip", pilosa.IndexOptions{}).CreateFieldIfNotExists("s"); err != nil {
+			t.Fatal(err)
+		}
+		large := gzipped(strings.Repeat(`{"bitmapID": 2, "profileID": 7}`+"\n", 100))
+		if w := post(clus[0].Handler.(*http.Handler).Handler, "gzip", large); w.Code != gohttp.StatusRequestEntityTooLarge {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Export proto", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("iexport", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("s"); err != nil {
+			t.Fatal(err)
+		} else if _, err := i.CreateFieldIfNotExists("n", pilosa.OptFieldTypeInt(0, 10)); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iexport/query", strings.NewReader(`Set(1, s=2) Set(3, s=2) Set(3, s=5)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/export?index=iexport&field=s&shard=0&format=proto&chunk=2", nil))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		var reqs []pilosa.ImportRequest
+		r := bufio.NewReader(w.Body)
+		for {
+			n, err := binary.ReadUvarint(r)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				t.Fatal(err)
+			}
+			var req pilosa.ImportRequest
+			if err := (proto.Serializer{}).Unmarshal(buf, &req); err != nil {
+				t.Fatal(err)
+			}
+			reqs = append(reqs, req)
+		}
+		if !reflect.DeepEqual(reqs, []pilosa.ImportRequest{
+			{Index: "iexport", Field: "s", View: "standard", RowIDs: []uint64{2, 2}, ColumnIDs: []uint64{1, 3}},
+			{Index: "iexport", Field: "s", View: "standard", RowIDs: []uint64{5}, ColumnIDs: []uint64{3}},
+		}) {
+			t.Fatalf("unexpected messages: %+v", reqs)
+		}
+
+		for _, tt := range []struct {
+			path string
+			code int
+		}{
+			{path: "/export?index=iexport&field=s&shard=0&format=xml", code: gohttp.StatusBadRequest},
+			{path: "/export?index=iexport&field=s&shard=0&format=proto&chunk=0", code: gohttp.StatusBadRequest},
+			{path: "/export?index=iexport&field=n&shard=0&format=proto", code: gohttp.StatusBadRequest},
+			{path: "/export?index=iexport&field=nope&shard=0&format=proto", code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", tt.path, nil))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.path, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Export filtered", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("iexportfilter", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("s"); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iexportfilter/query", strings.NewReader(`Set(1, s=1) Set(2, s=2) Set(3, s=3) Set(4, s=4)`)))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		for _, tt := range []struct {
+			query string
+			code  int
+			body  string
+		}{
+			{query: "&minBitmapID=2&maxBitmapID=3", code: gohttp.StatusOK, body: "2,2\n3,3\n"},
+			{query: "&bitmapIDs=4,1,9", code: gohttp.StatusOK, body: "1,1\n4,4\n"},
+			{query: "&bitmapIDs=", code: gohttp.StatusOK, body: ""},
+			{query: "&minBitmapID=2&bitmapIDs=1,3", code: gohttp.StatusOK, body: "3,3\n"},
+			{query: "&minBitmapID=3&maxBitmapID=2", code: gohttp.StatusBadRequest},
+			{query: "&maxBitmapID=x", code: gohttp.StatusBadRequest},
+			{query: "&bitmapIDs=1,,2", code: gohttp.StatusBadRequest},
+		} {
+			req := test.MustNewHTTPRequest("GET", "/export?index=iexportfilter&field=s&shard=0"+tt.query, nil)
+			req.Header.Set("Accept", "text/csv")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.query, w.Code, w.Body.String())
+			} else if tt.code == gohttp.StatusOK && w.Body.String() != tt.body {
+				t.Fatalf("%s: expected %q, got %q", tt.query, tt.body, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Views", func(t *testing.T) {
+		i := hldr.MustCreateIndexIfNotExists("iviews", pilosa.IndexOptions{})
+		if _, err := i.CreateFieldIfNotExists("t", pilosa.OptFieldTypeTime("YMD")); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/iviews/query", strings.NewReader(fmt.Sprintf(
+			`Set(1, t=1, 2018-01-02T00:00) Set(2, t=1, 2018-01-02T00:00) Set(%d, t=1, 2018-03-05T00:00)`, pilosa.ShardWidth+1))))
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+		}
+
+		views := func(query string) []*pilosa.ViewInfo {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/index/iviews/field/t/views"+query, nil))
+			if w.Code != gohttp.StatusOK {
+				t.Fatalf("unexpected status code: %d, body: %s", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Views []*pilosa.ViewInfo `json:"views"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			return resp.Views
+		}
+
+		all := views("")
+		var names []string
+		for _, v := range all {
+			names = append(names, v.Name)
+		}
+		if !reflect.DeepEqual(names, []string{"standard", "standard_2018", "standard_201801", "standard_20180102", "standard_201803", "standard_20180305"}) {
+			t.Fatalf("unexpected views: %v", names)
+		} else if v := all[0]; v.Granularity != "" || v.Start != nil || v.BitCount != 3 || v.MaxShard != 1 {
+			t.Fatalf("unexpected standard view: %+v", v)
+		} else if v := all[2]; v.Granularity != "month" || !v.Start.Equal(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)) || !v.End.Equal(time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)) || v.BitCount != 2 || v.MaxShard != 0 {
+			t.Fatalf("unexpected month view: %+v", v)
+		}
+
+		// Only time views overlapping the range are listed.
+		names = nil
+		for _, v := range views("?from=2018-02-01T00:00&to=2018-03-05T00:00") {
+			names = append(names, v.Name)
+		}
+		if !reflect.DeepEqual(names, []string{"standard_2018", "standard_201803"}) {
+			t.Fatalf("unexpected views: %v", names)
+		}
+
+		for _, tt := range []struct {
+			path string
+			code int
+		}{
+			{path: "/index/iviews/field/t/views?from=2018", code: gohttp.StatusBadRequest},
+			{path: "/index/iviews/field/t/views?from=2018-02-01T00:00&to=2018-01-01T00:00", code: gohttp.StatusBadRequest},
+			{path: "/index/iviews/field/t/views?since=2018-02-01T00:00", code: gohttp.StatusBadRequest},
+			{path: "/index/iviews/field/nope/views", code: gohttp.StatusNotFound},
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("GET", tt.path, nil))
+			if w.Code != tt.code {
+				t.Fatalf("%s: unexpected status code: %d, body: %s", tt.path, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("CORS", func(t *testing.T) {
+		req := test.MustNewHTTPRequest("OPTIONS", "/index/foo/query", nil)
+		req.Header.Add("Origin", "http://test/")
+		req.Header.Add("Access-Control-Request-Method", "POST")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		result := w.Result()
+
+		// This handler does not support CORS, return Method Not Allowed (405)
+		if result.StatusCode != 405 {
+			t.Fatalf("CORS preflight status should be 405, but is %v", result.StatusCode)
+		}
+
+		clus := test.MustRunCluster(t, 1, []server.CommandOption{test.OptAllowedOrigins([]string{"http://test/"})})
+		w = httptest.NewRecorder()
+		h := clus[0].Handler.(*http.Handler).Handler
+		h.ServeHTTP(w, req)
+		result = w.Result()
+
+		if result.StatusCode != 200 {
+			t.Fatalf("CORS preflight status should be 200, but is %v", result.StatusCode)
+		}
+
+		if result.Header["Access-Control-Allow-Origin"][0] != "http://test/" {
+			t.Fatal("CORS header not present")
+		}
+	})
+
+	t.Run("index handlers", func(t *testing.T) {
+		// create index
+		w := httptest.NewRecorder()
+		r := test.MustNewHTTPRequest("POST", "/index/idx1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":true}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// create index again
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/index/idx1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusConflict {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":false,"error":{"message":"index already exists","code":"index-exists"}}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// create field
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/index/idx1/field/fld1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":true}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// create field again
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/index/idx1/field/fld1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusConflict {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":false,"error":{"message":"field already exists","code":"field-exists"}}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// create index and field with invalid names
+		for path, msg := range map[string]string{
+			"/index/Idx2":            `invalid name \"Idx2\": must be lowercase`,
+			"/index/idx1/field/from": `invalid name \"from\": is reserved`,
+			"/index/idx1/field/f.g":  `invalid name \"f.g\": must not contain '.'`,
+		} {
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, test.MustNewHTTPRequest("POST", path, strings.NewReader("")))
+			if w.Code != gohttp.StatusBadRequest {
+				t.Fatalf("%s: unexpected status code: %d", path, w.Code)
+			} else if w.Body.String() != `{"success":false,"error":{"message":"`+msg+`"}}`+"\n" {
+				t.Fatalf("%s: unexpected body: %q", path, w.Body.String())
+			}
+		}
+
+		// delete field
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("DELETE", "/index/idx1/field/fld1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":true}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// delete field again
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("DELETE", "/index/idx1/field/fld1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":false,"error":{"message":"field not found","code":"field-not-found"}}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// delete index
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("DELETE", "/index/idx1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":true}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// delete index again
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("DELETE", "/index/idx1", strings.NewReader(""))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusNotFound {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":false,"error":{"message":"index not found","code":"index-not-found"}}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+	})
+
+	t.Run("translate keys", func(t *testing.T) {
+		// create index
+		w := httptest.NewRecorder()
+		r := test.MustNewHTTPRequest("POST", "/index/i1-tr", strings.NewReader(`{"options":{"keys":true}}`))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":true}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// create field
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/index/i1-tr/field/f1", strings.NewReader(`{"options":{"keys":true}}`))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		} else if w.Body.String() != `{"success":true}`+"\n" {
+			t.Fatalf("unexpected body: %q", w.Body.String())
+		}
+
+		// set some bits
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/index/i1-tr/query", strings.NewReader(`Set("col1", f1="row1")`))
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+
+		// Generate request body for translate column keys request
+		reqBody, err := cmd.API.Serializer.Marshal(&pilosa.TranslateKeysRequest{
+			Index: "i1-tr",
+			Keys:  []string{"col1", "col2", "col3"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Generate protobuf request.
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/internal/translate/keys", bytes.NewReader(reqBody))
+		r.Header.Set("Content-Type", "application/x-protobuf")
+		r.Header.Set("Accept", "application/x-protobuf")
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+		target := []uint64{1, 2, 3}
+		resp := pilosa.TranslateKeysResponse{}
+		err = cmd.API.Serializer.Unmarshal(w.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(target, resp.IDs) {
+			t.Fatalf("%v != %v", target, resp.IDs)
+		}
+
+		// Generate request body for translate row keys request
+		reqBody, err = cmd.API.Serializer.Marshal(&pilosa.TranslateKeysRequest{
+			Index: "i1-tr",
+			Field: "f1",
+			Keys:  []string{"row1", "row2"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Generate protobuf request.
+		w = httptest.NewRecorder()
+		r = test.MustNewHTTPRequest("POST", "/internal/translate/keys", bytes.NewReader(reqBody))
+		r.Header.Set("Content-Type", "application/x-protobuf")
+		r.Header.Set("Accept", "application/x-protobuf")
+		h.ServeHTTP(w, r)
+		if w.Code != gohttp.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+		target = []uint64{1, 2}
+		resp = pilosa.TranslateKeysResponse{}
+		err = cmd.API.Serializer.Unmarshal(w.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(target, resp.IDs) {
+			t.Fatalf("%v != %v", target, resp.IDs)
+		}
+	})
+}
+
+// Ensure a query which runs past the maximum query time fails with a
+// timeout status.
+func TestHandler_QueryTimeout(t *testing.T) {
+	cmd := test.MustRunCluster(t, 1, []server.CommandOption{
+		server.OptCommandServerOptions(pilosa.OptServerMaxQueryTime(time.Nanosecond)),
+	})[0]
+	defer cmd.Close()
+	h := cmd.Handler.(*http.Handler).Handler
+	cmd.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "i", "f")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, test.MustNewHTTPRequest("POST", "/index/i/query", strings.NewReader("Count(Row(f=1))")))
+	if w.Code != gohttp.StatusGatewayTimeout {
+		t.Fatalf("unexpected status code: %d", w.Code)
+	} else if body := w.Body.String(); !strings.Contains(body, pilosa.ErrQueryTimeout.Error()) {
+		t.Fatalf("unexpected body: %s", body)
+	}
 }
 
 func TestClusterTranslator(t *testing.T) {
@@ -44,7 +44,7 @@ import (
 	"github.com/pilosa/pilosa/http"
 	"github.com/pilosa/pilosa/logger"
 	"github.com/pilosa/pilosa/stats"
-	"github.com/pilosa/pilosa/statsd"
+	_ "github.com/pilosa/pilosa/statsd" // Registers the statsd stats client.
 	"github.com/pkg/errors"
 )
 
@@ -227,7 +227,7 @@ func (m *Command) SetupServer() error {
 		diagnosticsInterval = defaultDiagnosticsInterval
 	}
 
-	statsClient, err := newStatsClient(m.Config.Metric.Service, m.Config.Metric.Host)
+	statsClient, err := stats.NewStatsClient(m.Config.Metric.Service, stats.Config{Host: m.Config.Metric.Host})
 	if err != nil {
 		return errors.Wrap(err, "new stats client")
 	}
@@ -269,10 +269,31 @@ func (m *Command) SetupServer() error {
 
 	serverOptions := []pilosa.ServerOption{
 		pilosa.OptServerAntiEntropyInterval(time.Duration(m.Config.AntiEntropy.Interval)),
+		pilosa.OptServerAntiEntropyRate(m.Config.AntiEntropy.RequestsPerSecond),
+		pilosa.OptServerRetentionInterval(time.Duration(m.Config.Retention.Interval)),
 		pilosa.OptServerLongQueryTime(time.Duration(m.Config.Cluster.LongQueryTime)),
+		pilosa.OptServerDisableFailover(m.Config.Cluster.DisableFailover),
 		pilosa.OptServerDataDir(m.Config.DataDir),
 		pilosa.OptServerReplicaN(m.Config.Cluster.ReplicaN),
 		pilosa.OptServerMaxWritesPerRequest(m.Config.MaxWritesPerRequest),
+		pilosa.OptServerMaxQueryTime(time.Duration(m.Config.MaxQueryTime)),
+		pilosa.OptServerMaxConstRowColumns(m.Config.MaxConstRowColumns),
+		pilosa.OptServerCacheMaxMemory(m.Config.CacheMaxMemory),
+		pilosa.OptServerOpenWorkers(m.Config.OpenWorkers),
+		pilosa.OptServerAllowLegacyNames(m.Config.AllowLegacyNames),
+		pilosa.OptServerSchemaLimits(m.Config.MaxIndexes, m.Config.MaxFieldsPerIndex, m.Config.MaxViewsPerField),
+		pilosa.OptServerDurability(m.Config.Durability),
+		pilosa.OptServerMaxOpN(m.Config.MaxOpN),
+		pilosa.OptServerFragmentCloseTimeout(time.Duration(m.Config.FragmentCloseTimeout)),
+		pilosa.OptServerFragmentOpenConcurrency(m.Config.FragmentOpenConcurrency),
+		pilosa.OptServerFragmentOpenPolicy(m.Config.FragmentOpenPolicy),
+		pilosa.OptServerFragmentIdleTimeout(time.Duration(m.Config.FragmentIdleTimeout)),
+		pilosa.OptServerMaxOpenFragmentsPerView(m.Config.MaxOpenFragmentsPerView),
+		pilosa.OptServerSnapshotWorkers(m.Config.SnapshotWorkers),
+		pilosa.OptServerPreserveOrphans(m.Config.PreserveOrphans),
+		pilosa.OptServerStrictLimits(m.Config.StrictLimits),
+		pilosa.OptServerFragmentLayout(m.Config.FragmentLayout),
+		pilosa.OptServerReadOnly(m.Config.ReadOnly),
 		pilosa.OptServerMetricInterval(time.Duration(m.Config.Metric.PollInterval)),
 		pilosa.OptServerDiagnosticsInterval(diagnosticsInterval),
 
@@ -316,6 +337,7 @@ func (m *Command) SetupServer() error {
 		http.OptHandlerLogger(m.logger),
 		http.OptHandlerListener(m.ln),
 		http.OptHandlerCloseTimeout(m.closeTimeout),
+		http.OptHandlerMaxDecompressedImportSize(m.Config.Handler.MaxDecompressedImportSize),
 	)
 	return errors.Wrap(err, "new handler")
 }
@@ -380,20 +402,6 @@ func (m *Command) Close() error {
 	return errors.Wrap(err, "closing everything")
 }
 
-// newStatsClient creates a stats client from the config
-func newStatsClient(name string, host string) (stats.StatsClient, error) {
-	switch name {
-	case "expvar":
-		return stats.NewExpvarStatsClient(), nil
-	case "statsd":
-		return statsd.NewStatsClient(host)
-	case "nop", "none":
-		return stats.NopStatsClient, nil
-	default:
-		return nil, errors.Errorf("'%v' not a valid stats client, choose from [expvar, statsd, none].", name)
-	}
-}
-
 // getListener gets a net.Listener based on the config.
 func getListener(uri pilosa.URI, tlsconf *tls.Config) (ln net.Listener, err error) {
 	// If bind URI has the https scheme, enable TLS
@@ -22,6 +22,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	gohttp "net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
@@ -300,6 +303,184 @@ func TestMain_GroupBy(t *testing.T) {
 	}
 }
 
+// Ensure a read-only program answers read queries, rejects writes and leaves
+// its data directory untouched.
+func TestMain_ReadOnly(t *testing.T) {
+	m := test.MustRunCommand()
+	defer os.RemoveAll(m.Config.DataDir)
+
+	client := m.Client()
+	if err := client.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	} else if err := client.CreateFieldWithOptions(context.Background(), "i", "k", pilosa.FieldOptions{Keys: true}); err != nil {
+		t.Fatal(err)
+	} else if err := client.CreateFieldWithOptions(context.Background(), "i", "v", pilosa.FieldOptions{Type: pilosa.FieldTypeInt, Min: 0, Max: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Query("i", "", `
+		Set(1, f=1) Set(2, f=1) Set(3000000, f=2)
+		Set(1, k="a") Set(3000000, k="b")
+		Set(1, v=10) Set(2, v=20)
+		SetRowAttrs(f, 1, x=100)
+		SetColumnAttrs(1, y="z")
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close the program without removing its data and restart it read-only.
+	if err := m.Command.Close(); err != nil {
+		t.Fatal(err)
+	}
+	before := dataDirFiles(t, m.Config.DataDir)
+
+	ro := test.NewCommandNode(true)
+	os.RemoveAll(ro.Config.DataDir)
+	ro.Config.DataDir = m.Config.DataDir
+	ro.Config.Cluster.Disabled = true
+	ro.Config.Metric.Diagnostics = false
+	ro.Config.ReadOnly = true
+	if err := ro.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, exp := range map[string]string{
+		`Row(f=1)`:                            `{"results":[{"attrs":{"x":100},"columns":[1,2]}]}`,
+		`Count(Union(Row(f=1), Row(f=2)))`:    `{"results":[3]}`,
+		`Row(k="b")`:                          `{"results":[{"attrs":{},"columns":[3000000]}]}`,
+		`Sum(field=v)`:                        `{"results":[{"value":30,"count":2}]}`,
+		`Row(v > 15)`:                         `{"results":[{"attrs":{},"columns":[2]}]}`,
+		`TopN(f, n=1)`:                        `{"results":[[{"id":1,"count":2}]]}`,
+		`Rows(f)`:                             `{"results":[{"rows":[1,2]}]}`,
+		`Options(Row(f=1), columnAttrs=true)`: `{"results":[{"attrs":{"x":100},"columns":[1,2]}],"columnAttrs":[{"id":1,"attrs":{"y":"z"}}]}`,
+	} {
+		if res, err := ro.Query("i", "", query); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		} else if res != exp+"\n" {
+			t.Fatalf("%s: unexpected result: %s", query, res)
+		}
+	}
+
+	for _, query := range []string{`Set(5, f=1)`, `Clear(1, f=1)`, `ClearRow(f=1)`, `SetRowAttrs(f, 1, x=1)`, `Row(f=1) Set(5, f=1)`} {
+		if resp := test.MustDo("POST", ro.URL()+"/index/i/query", query); resp.StatusCode != gohttp.StatusForbidden {
+			t.Fatalf("%s: unexpected status: %d, body=%s", query, resp.StatusCode, resp.Body)
+		}
+	}
+	if resp := test.MustDo("POST", ro.URL()+"/index/j", ""); resp.StatusCode != gohttp.StatusForbidden {
+		t.Fatalf("create index: unexpected status: %d, body=%s", resp.StatusCode, resp.Body)
+	} else if resp := test.MustDo("DELETE", ro.URL()+"/index/i/field/f", ""); resp.StatusCode != gohttp.StatusForbidden {
+		t.Fatalf("delete field: unexpected status: %d, body=%s", resp.StatusCode, resp.Body)
+	}
+	if _, err := ro.Client().Import(context.Background(), "i", "f", 0, []pilosa.Bit{{RowID: 1, ColumnID: 5}}); err == nil {
+		t.Fatal("expected import error")
+	}
+
+	var status struct {
+		ReadOnly bool `json:"readOnly"`
+	}
+	if resp := test.MustDo("GET", ro.URL()+"/status", ""); resp.StatusCode != gohttp.StatusOK {
+		t.Fatalf("unexpected status: %d, body=%s", resp.StatusCode, resp.Body)
+	} else if err := json.Unmarshal([]byte(resp.Body), &status); err != nil {
+		t.Fatal(err)
+	} else if !status.ReadOnly {
+		t.Fatalf("expected read-only status: %s", resp.Body)
+	}
+
+	if err := ro.Command.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if after := dataDirFiles(t, m.Config.DataDir); !reflect.DeepEqual(before, after) {
+		t.Fatalf("data directory changed:\nbefore: %v\nafter:  %v", before, after)
+	}
+}
+
+// Ensure a read-only program can open the data directory of a program which
+// still has it open for writing, without modifying it.
+func TestMain_ReadOnly_Live(t *testing.T) {
+	m := test.MustRunCommand()
+	defer m.Close()
+
+	client := m.Client()
+	if err := client.CreateIndex(context.Background(), "i", pilosa.IndexOptions{}); err != nil {
+		t.Fatal(err)
+	} else if err := client.CreateField(context.Background(), "i", "f"); err != nil {
+		t.Fatal(err)
+	} else if err := client.CreateFieldWithOptions(context.Background(), "i", "k", pilosa.FieldOptions{Keys: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Query("i", "", `
+		Set(1, f=1) Set(2, f=1) Set(3000000, f=2)
+		Set(1, k="a")
+		SetRowAttrs(f, 1, x=1.5)
+		SetColumnAttrs(1, y="z")
+	`); err != nil {
+		t.Fatal(err)
+	}
+	before := dataDirFiles(t, m.Config.DataDir)
+
+	ro := test.NewCommandNode(true)
+	os.RemoveAll(ro.Config.DataDir)
+	ro.Config.DataDir = m.Config.DataDir
+	ro.Config.Cluster.Disabled = true
+	ro.Config.Metric.Diagnostics = false
+	ro.Config.ReadOnly = true
+	if err := ro.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, exp := range map[string]string{
+		`Row(f=1)`:                            `{"results":[{"attrs":{"x":1.5},"columns":[1,2]}]}`,
+		`Row(k="a")`:                          `{"results":[{"attrs":{},"columns":[1]}]}`,
+		`TopN(f, n=1)`:                        `{"results":[[{"id":1,"count":2}]]}`,
+		`Options(Row(f=1), columnAttrs=true)`: `{"results":[{"attrs":{"x":1.5},"columns":[1,2]}],"columnAttrs":[{"id":1,"attrs":{"y":"z"}}]}`,
+	} {
+		if res, err := ro.Query("i", "", query); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		} else if res != exp+"\n" {
+			t.Fatalf("%s: unexpected result: %s", query, res)
+		}
+	}
+	var buf bytes.Buffer
+	if err := ro.Client().ExportCSV(context.Background(), "i", "f", 0, &buf); err != nil {
+		t.Fatal(err)
+	} else if buf.String() != "1,1\n1,2\n" {
+		t.Fatalf("unexpected export: %q", buf.String())
+	}
+	if resp := test.MustDo("POST", ro.URL()+"/index/i/query", `Set(5, f=1)`); resp.StatusCode != gohttp.StatusForbidden {
+		t.Fatalf("unexpected status: %d, body=%s", resp.StatusCode, resp.Body)
+	}
+
+	if err := ro.Command.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if after := dataDirFiles(t, m.Config.DataDir); !reflect.DeepEqual(before, after) {
+		t.Fatalf("data directory changed:\nbefore: %v\nafter:  %v", before, after)
+	}
+
+	// The writer is unaffected.
+	if res, err := m.Query("i", "", `Set(5, f=1) Count(Row(f=1))`); err != nil {
+		t.Fatal(err)
+	} else if res != `{"results":[true,3]}`+"\n" {
+		t.Fatalf("unexpected result: %s", res)
+	}
+}
+
+// dataDirFiles returns the size and modification time of each file under dir.
+func dataDirFiles(t *testing.T, dir string) map[string]string {
+	files := make(map[string]string)
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		files[path] = fmt.Sprintf("%d %s", info.Size(), info.ModTime().Format(time.RFC3339Nano))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
 // Ensure the host can be parsed.
 func TestConfig_Parse_Host(t *testing.T) {
 	if c, err := ParseConfig(`bind = "local"`); err != nil {
@@ -318,6 +499,17 @@ func TestConfig_Parse_DataDir(t *testing.T) {
 	}
 }
 
+// Ensure a program with an unknown stats backend fails to start, listing the
+// registered backends.
+func TestMain_UnknownStatsClient(t *testing.T) {
+	m := test.NewCommandNode(false)
+	defer os.RemoveAll(m.Config.DataDir)
+	m.Config.Metric.Service = "nope"
+	if err := m.Start(); err == nil || !strings.Contains(err.Error(), "'nope' not a valid stats client, choose from [expvar, none, nop, statsd]") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestMain_RecalculateHashes(t *testing.T) {
 	const clusterSize = 5
 	cluster := test.MustRunCluster(t, clusterSize)
@@ -630,6 +822,141 @@ func TestRemoveNodeAfterItDies(t *testing.T) {
 	}
 }
 
+// Ensure reads of the shards of an unavailable node are retried against
+// replicas, unless failover is disabled, and that writes never are.
+func TestClusterQueryFailover(t *testing.T) {
+	cluster := test.MustNewCluster(t, 3)
+	for _, c := range cluster {
+		c.Config.Cluster.ReplicaN = 2
+	}
+	cluster[1].Config.Cluster.DisableFailover = true
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer cluster.Close()
+
+	var wait = true
+	for wait {
+		wait = false
+		for _, node := range cluster {
+			if node.API.State() != pilosa.ClusterStateNormal {
+				wait = true
+			}
+		}
+		time.Sleep(time.Millisecond * 1)
+	}
+
+	cluster.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	var bits [][2]uint64
+	for shard := uint64(0); shard < 12; shard++ {
+		bits = append(bits, [2]uint64{1, shard * pilosa.ShardWidth})
+	}
+	cluster.ImportBits(t, "i", "f", bits)
+
+	// Stop serving HTTP on the third node, which stays in the cluster as
+	// though it's restarting, so other nodes' connections are refused.
+	if err := cluster[2].Handler.Close(); err != nil {
+		t.Fatalf("closing third node's handler: %v", err)
+	}
+
+	query := func(node int, q string) ([]interface{}, error) {
+		resp, err := cluster[node].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: q})
+		return resp.Results, err
+	}
+
+	// The shards of the third node are read from their replicas.
+	if results, err := query(0, "Count(Row(f=1))"); err != nil {
+		t.Fatalf("querying with failover: %v", err)
+	} else if n := results[0].(uint64); n != 12 {
+		t.Fatalf("unexpected count: %d", n)
+	}
+
+	// Without failover the read fails.
+	if _, err := query(1, "Count(Row(f=1))"); err == nil {
+		t.Fatal("expected error querying without failover")
+	}
+
+	// A write isn't applied by a replica in place of the unavailable node.
+	if _, err := query(0, "ClearRow(f=1)"); err == nil {
+		t.Fatal("expected error clearing a row on an unavailable node")
+	}
+
+	// Errors from a node which served the query aren't retried.
+	if _, err := query(0, "Count(Row(nope=1))"); err == nil || !strings.Contains(err.Error(), "field not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure indexes with different shard widths can be written and queried on
+// the same cluster.
+func TestClusterShardWidths(t *testing.T) {
+	cluster := test.MustNewCluster(t, 3)
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("starting cluster: %v", err)
+	}
+	defer cluster.Close()
+
+	cols := []uint64{0, 70000, 1500000, 5000000, 9000000}
+	for _, w := range []uint64{1 << 16, 0, 1 << 22} {
+		index := fmt.Sprintf("i%d", w)
+		cluster.CreateField(t, index, pilosa.IndexOptions{TrackExistence: true, ShardWidth: w}, "f")
+
+		query := func(node int, q string) []interface{} {
+			t.Helper()
+			resp, err := cluster[node].API.Query(context.Background(), &pilosa.QueryRequest{Index: index, Query: q})
+			if err != nil {
+				t.Fatalf("index %s: querying %q: %v", index, q, err)
+			}
+			return resp.Results
+		}
+		columns := func(node int, q string) []uint64 {
+			t.Helper()
+			return query(node, q)[0].(*pilosa.Row).Columns()
+		}
+
+		// Set row 1 by queries on one node, and row 2 by an import.
+		for _, col := range cols {
+			query(1, fmt.Sprintf("Set(%d, f=1)", col))
+		}
+		query(1, "Set(12000000, f=1) Clear(12000000, f=1)")
+		var bits [][2]uint64
+		for _, col := range append(cols, 12000000) {
+			bits = append(bits, [2]uint64{2, col})
+		}
+		cluster.ImportBits(t, index, "f", bits)
+
+		if a := columns(0, "Row(f=1)"); !reflect.DeepEqual(a, cols) {
+			t.Fatalf("index %s: unexpected columns: %v", index, a)
+		} else if n := query(2, "Count(Row(f=2))")[0].(uint64); n != 6 {
+			t.Fatalf("index %s: unexpected count: %d", index, n)
+		} else if a := columns(0, "Not(Row(f=1))"); !reflect.DeepEqual(a, []uint64{12000000}) {
+			t.Fatalf("index %s: unexpected Not columns: %v", index, a)
+		}
+
+		query(0, "Store(Intersect(Row(f=1), Row(f=2)), f=3)")
+		if a := columns(2, "Row(f=3)"); !reflect.DeepEqual(a, cols) {
+			t.Fatalf("index %s: unexpected stored columns: %v", index, a)
+		} else if ids := query(1, "Rows(f, column=9000000)")[0].(pilosa.RowIdentifiers).Rows; !reflect.DeepEqual(ids, []uint64{1, 2, 3}) {
+			t.Fatalf("index %s: unexpected rows: %v", index, ids)
+		}
+	}
+
+	// Each node uses the width of each index.
+	for i, node := range cluster {
+		for _, w := range []uint64{1 << 16, 0, 1 << 22} {
+			idx, err := node.API.Index(context.Background(), fmt.Sprintf("i%d", w))
+			if err != nil {
+				t.Fatalf("node %d: getting index: %v", i, err)
+			} else if w == 0 {
+				w = pilosa.ShardWidth
+			}
+			if idx.ShardWidth() != w {
+				t.Fatalf("node %d: unexpected shard width of %s: %d", i, idx.Name(), idx.ShardWidth())
+			}
+		}
+	}
+}
+
 // Ensure program imports timestamps as UTC.
 func TestMain_ImportTimestamp(t *testing.T) {
 	m := test.MustRunCommand()
@@ -658,7 +985,7 @@ func TestMain_ImportTimestamp(t *testing.T) {
 	}
 
 	// Import data.
-	if err := m.API.Import(context.Background(), &data); err != nil {
+	if _, err := m.API.Import(context.Background(), &data); err != nil {
 		t.Fatal(err)
 	}
 
@@ -683,6 +1010,86 @@ func TestMain_ImportTimestamp(t *testing.T) {
 	}
 }
 
+// Ensure a view can be deleted on every node while it is queried, and isn't
+// reopened after a restart.
+func TestMain_DeleteView(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c[0].MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	c[0].MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("Y"))
+
+	var q string
+	for shard := uint64(0); shard < 4; shard++ {
+		q += fmt.Sprintf("Set(%d, f=1, 2017-01-02T00:00) Set(%d, f=1, 2018-01-02T00:00) ", shard*pilosa.ShardWidth, shard*pilosa.ShardWidth+1)
+	}
+	c.Query(t, "i", q)
+
+	var g errgroup.Group
+	done := make(chan struct{})
+	g.Go(func() error {
+		for {
+			select {
+			case <-done:
+				return nil
+			default:
+			}
+			if _, err := c[0].API.Query(context.Background(), &pilosa.QueryRequest{Index: "i", Query: "Row(f=1, from=2017-01-01T00:00, to=2019-01-01T00:00)"}); err != nil {
+				return err
+			}
+		}
+	})
+	resp := test.MustDo("DELETE", c[0].URL()+"/index/i/field/f/view/standard_2017", "")
+	close(done)
+	if resp.StatusCode != gohttp.StatusOK {
+		t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, resp.Body)
+	} else if err := g.Wait(); err != nil {
+		t.Fatalf("querying while deleting view: %v", err)
+	}
+
+	hasView := func(m *test.Command) bool {
+		views, err := m.API.ViewInfos(context.Background(), "i", "f", time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range views {
+			if v.Name == "standard_2017" {
+				return true
+			}
+		}
+		return false
+	}
+	for i, m := range c {
+		if hasView(m) {
+			t.Fatalf("node %d: view not deleted", i)
+		} else if _, err := os.Stat(filepath.Join(m.Config.DataDir, "i", "f", "views", "standard_2017")); !os.IsNotExist(err) {
+			t.Fatalf("node %d: unexpected view directory: %v", i, err)
+		}
+	}
+	res := c.Query(t, "i", "Row(f=1, from=2017-01-01T00:00, to=2019-01-01T00:00)")
+	exp := []uint64{1, pilosa.ShardWidth + 1, 2*pilosa.ShardWidth + 1, 3*pilosa.ShardWidth + 1}
+	if columns := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(columns, exp) {
+		t.Fatalf("unexpected columns: %v", columns)
+	}
+
+	if resp := test.MustDo("DELETE", c[0].URL()+"/index/i/field/g/view/standard", ""); resp.StatusCode != gohttp.StatusNotFound {
+		t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, resp.Body)
+	}
+
+	// The deleted view stays deleted after a restart.
+	m := test.MustRunCommand()
+	defer m.Close()
+	m.MustCreateIndex(t, "i", pilosa.IndexOptions{})
+	m.MustCreateField(t, "i", "f", pilosa.OptFieldTypeTime("Y"))
+	m.MustQuery(t, &pilosa.QueryRequest{Index: "i", Query: "Set(1, f=1, 2017-01-02T00:00)"})
+	if err := m.API.DeleteView(context.Background(), "i", "f", "standard_2017"); err != nil {
+		t.Fatal(err)
+	} else if err := m.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if hasView(m) {
+		t.Fatal("view reopened")
+	}
+}
+
 func TestMain_ImportTimestampNoStandardView(t *testing.T) {
 	m := test.MustRunCommand()
 	defer m.Close()
@@ -710,7 +1117,7 @@ func TestMain_ImportTimestampNoStandardView(t *testing.T) {
 	}
 
 	// Import data.
-	if err := m.API.Import(context.Background(), &data); err != nil {
+	if _, err := m.API.Import(context.Background(), &data); err != nil {
 		t.Fatal(err)
 	}
 
@@ -24,10 +24,10 @@ func (m *Command) setupLogger() error {
 		}
 	}
 
-	if m.Config.Verbose {
-		m.logger = logger.NewVerboseLogger(m.logOutput)
-	} else {
-		m.logger = logger.NewStandardLogger(m.logOutput)
+	level, err := m.Config.logLevel()
+	if err != nil {
+		return errors.Wrap(err, "parsing log level")
 	}
+	m.logger = logger.NewLevelLogger(m.logOutput, level)
 	return nil
 }
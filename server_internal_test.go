@@ -16,9 +16,15 @@ package pilosa
 
 import (
 	"io/ioutil"
+	"os"
+	"reflect"
 	"runtime"
+	"sort"
 	"testing"
 	"time"
+
+	"github.com/pilosa/pilosa/roaring"
+	"github.com/pkg/errors"
 )
 
 // Ensure the file handle count is working
@@ -60,3 +66,131 @@ func TestMonitorAntiEntropyZero(t *testing.T) {
 		t.Fatalf("monitorAntiEntropy should have returned immediately with duration 0")
 	}
 }
+
+// Ensure expired time views are deleted by the retention pass.
+func TestServer_DeleteExpiredViews(t *testing.T) {
+	td, err := ioutil.TempDir(*TempDir, "")
+	if err != nil {
+		t.Fatalf("getting temp dir: %v", err)
+	}
+	defer os.RemoveAll(td)
+
+	s, err := NewServer(OptServerDataDir(td), OptServerSerializer(nopSerializer{}))
+	if err != nil {
+		t.Fatalf("making new server: %v", err)
+	} else if err := s.holder.Open(); err != nil {
+		t.Fatalf("opening holder: %v", err)
+	}
+	defer s.holder.Close()
+
+	idx, err := s.holder.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f", func(fo *FieldOptions) error {
+		if err := OptFieldTypeTime(TimeQuantum("YMD"))(fo); err != nil {
+			return err
+		}
+		return OptFieldRetention(TimeRetention{Day: 24 * time.Hour, Month: 24 * time.Hour})(fo)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := f.SetBit(1, 1, &ts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the day view has expired by the start of February.
+	if err := s.deleteExpiredViews(time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, v := range f.views() {
+		names = append(names, v.name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"standard", "standard_2018", "standard_201801"}) {
+		t.Fatalf("unexpected views: %v", names)
+	}
+}
+
+// Ensure a view which fails to be deleted, or whose deletion fails to be
+// broadcast, doesn't stop the other expired views from being deleted.
+func TestServer_DeleteExpiredViews_Failures(t *testing.T) {
+	td, err := ioutil.TempDir(*TempDir, "")
+	if err != nil {
+		t.Fatalf("getting temp dir: %v", err)
+	}
+	defer os.RemoveAll(td)
+
+	ser := failingViewSerializer{view: "standard_20180102"}
+	s, err := NewServer(OptServerDataDir(td), OptServerSerializer(ser))
+	if err != nil {
+		t.Fatalf("making new server: %v", err)
+	} else if err := s.holder.Open(); err != nil {
+		t.Fatalf("opening holder: %v", err)
+	}
+	defer s.holder.Close()
+
+	idx, err := s.holder.CreateIndex("i", IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := idx.CreateField("f", func(fo *FieldOptions) error {
+		if err := OptFieldTypeTime(TimeQuantum("D"))(fo); err != nil {
+			return err
+		}
+		return OptFieldRetention(TimeRetention{Day: 24 * time.Hour})(fo)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day := 1; day <= 3; day++ {
+		ts := time.Date(2018, time.January, day, 0, 0, 0, 0, time.UTC)
+		if _, err := f.SetBit(1, 1, &ts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first day's view can't be removed from disk, as its path is
+	// invalid, and the second's deletion can't be broadcast.
+	v := f.view("standard_20180101")
+	path := v.path
+	v.path += "\x00"
+	defer func() { v.path = path }()
+
+	err = s.deleteExpiredViews(time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC))
+	if errs, ok := err.(roaring.ErrorList); !ok || len(errs) != 2 {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	var names []string
+	for _, v := range f.views() {
+		names = append(names, v.name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"standard", "standard_20180101"}) {
+		t.Fatalf("unexpected views: %v", names)
+	}
+}
+
+// failingViewSerializer is a Serializer which fails to encode the deletion of
+// one view, and encodes every other message as empty.
+type failingViewSerializer struct {
+	view string
+}
+
+func (s failingViewSerializer) Marshal(m Message) ([]byte, error) {
+	if m, ok := m.(*DeleteViewMessage); ok && m.View == s.view {
+		return nil, errors.New("marshal failed")
+	}
+	return nil, nil
+}
+
+func (failingViewSerializer) Unmarshal([]byte, Message) error { return nil }
+
+// nopSerializer is a Serializer which encodes every message as empty.
+type nopSerializer struct{}
+
+func (nopSerializer) Marshal(Message) ([]byte, error) { return nil, nil }
+func (nopSerializer) Unmarshal([]byte, Message) error { return nil }
@@ -0,0 +1,178 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"sync"
+
+	"github.com/pilosa/pilosa/stats"
+)
+
+// snapshotQueue snapshots fragments whose op logs have passed their
+// threshold in the background, so that a burst of writes across many shards
+// doesn't rewrite all of their fragments at once. Each fragment is queued at
+// most once until its snapshot starts, and the number of concurrent
+// snapshots is bounded by the number of workers running. The writes in the
+// op log of a queued fragment are already durable.
+type snapshotQueue struct {
+	mu      sync.Mutex
+	pending map[*fragment]struct{}
+	queue   []*fragment
+	running int
+
+	// Number of snapshots taken by workers.
+	snapshots int
+
+	// Signals idle workers that the queue is non-empty.
+	notify chan struct{}
+
+	stats stats.StatsClient
+}
+
+// newSnapshotQueue returns a new instance of snapshotQueue.
+func newSnapshotQueue() *snapshotQueue {
+	return &snapshotQueue{
+		pending: make(map[*fragment]struct{}),
+		notify:  make(chan struct{}, 1),
+		stats:   stats.NopStatsClient,
+	}
+}
+
+// enqueue schedules a snapshot of the fragment unless one is already
+// pending. It returns false if there is no queue, in which case the caller
+// must snapshot the fragment itself.
+func (q *snapshotQueue) enqueue(f *fragment) bool {
+	if q == nil {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pending[f]; ok {
+		return true
+	}
+	q.pending[f] = struct{}{}
+	q.queue = append(q.queue, f)
+	q.gaugeDepth()
+	q.signal()
+	return true
+}
+
+// done removes the fragment from the pending set. It is called whenever the
+// fragment is snapshotted or closed, so that a snapshot which jumped the
+// queue isn't taken again by a worker.
+func (q *snapshotQueue) done(f *fragment) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pending[f]; ok {
+		delete(q.pending, f)
+		q.gaugeDepth()
+	}
+}
+
+// queued returns true if a snapshot of the fragment is pending.
+func (q *snapshotQueue) queued(f *fragment) bool {
+	if q == nil {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, ok := q.pending[f]
+	return ok
+}
+
+// Pending returns the number of fragments waiting to be snapshotted or being
+// snapshotted.
+func (q *snapshotQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) + q.running
+}
+
+// Snapshots returns the number of snapshots taken by workers.
+func (q *snapshotQueue) Snapshots() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.snapshots
+}
+
+// gaugeDepth reports the number of fragments waiting. q.mu must be held.
+func (q *snapshotQueue) gaugeDepth() {
+	q.stats.Gauge("snapshot.queue.depth", float64(len(q.pending)), 1.0)
+}
+
+// signal wakes a worker without blocking. q.mu must be held.
+func (q *snapshotQueue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the next fragment to snapshot off the queue. Fragments which
+// were snapshotted or closed since being queued are skipped.
+func (q *snapshotQueue) next() *fragment {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.queue) > 0 {
+		f := q.queue[0]
+		q.queue[0] = nil
+		q.queue = q.queue[1:]
+		if _, ok := q.pending[f]; !ok {
+			continue
+		}
+		delete(q.pending, f)
+		q.running++
+		q.gaugeDepth()
+
+		// Let another worker pick up the rest of the queue.
+		if len(q.queue) > 0 {
+			q.signal()
+		}
+		return f
+	}
+	return nil
+}
+
+// run snapshots queued fragments until closing is closed.
+func (q *snapshotQueue) run(closing <-chan struct{}) {
+	for {
+		select {
+		case <-closing:
+			return
+		default:
+		}
+
+		if f := q.next(); f != nil {
+			ok := f.snapshotQueued()
+
+			q.mu.Lock()
+			q.running--
+			if ok {
+				q.snapshots++
+			}
+			q.mu.Unlock()
+			continue
+		}
+
+		select {
+		case <-closing:
+			return
+		case <-q.notify:
+		}
+	}
+}
@@ -17,11 +17,13 @@ package stats
 import (
 	"expvar"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/pilosa/pilosa/logger"
+	"github.com/pkg/errors"
 )
 
 // Expvar global expvar map.
@@ -80,9 +82,69 @@ func (c *nopStatsClient) SetLogger(logger logger.Logger)
 func (c *nopStatsClient) Open()                                                                     {}
 func (c *nopStatsClient) Close() error                                                              { return nil }
 
-// expvarStatsClient writes stats out to expvars.
+// Config holds the configuration of the metric service from which a
+// registered backend creates its client.
+type Config struct {
+	// Address of the service receiving the metrics, such as a StatsD agent.
+	Host string
+}
+
+// NewStatsClientFunc creates a client of a stats backend.
+type NewStatsClientFunc func(c Config) (StatsClient, error)
+
+var registry = struct {
+	mu sync.Mutex
+	m  map[string]NewStatsClientFunc
+}{m: make(map[string]NewStatsClientFunc)}
+
+func init() {
+	RegisterStatsClient("expvar", func(Config) (StatsClient, error) { return NewExpvarStatsClient(), nil })
+	RegisterStatsClient("none", func(Config) (StatsClient, error) { return NopStatsClient, nil })
+	RegisterStatsClient("nop", func(Config) (StatsClient, error) { return NopStatsClient, nil })
+}
+
+// RegisterStatsClient makes a stats backend available by name, usually from
+// the init function of the package implementing it. It panics if fn is nil or
+// if a backend is already registered with the name.
+func RegisterStatsClient(name string, fn NewStatsClientFunc) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if fn == nil {
+		panic("stats: RegisterStatsClient function is nil")
+	} else if _, ok := registry.m[name]; ok {
+		panic("stats: RegisterStatsClient called twice for " + name)
+	}
+	registry.m[name] = fn
+}
+
+// StatsClientNames returns the sorted names of the registered backends.
+func StatsClientNames() []string {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	names := make([]string, 0, len(registry.m))
+	for name := range registry.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewStatsClient returns a client of the backend registered with the name.
+func NewStatsClient(name string, c Config) (StatsClient, error) {
+	registry.mu.Lock()
+	fn := registry.m[name]
+	registry.mu.Unlock()
+	if fn == nil {
+		return nil, errors.Errorf("'%v' not a valid stats client, choose from [%s].", name, strings.Join(StatsClientNames(), ", "))
+	}
+	return fn(c)
+}
+
+// expvarStatsClient writes stats out to expvars. The tags of the client are
+// flattened into the name of each metric as name{tag,...}, and every client
+// derived from it with WithTags writes to the same map.
 type expvarStatsClient struct {
-	mu   sync.Mutex
+	mu   *sync.Mutex
 	m    *expvar.Map
 	tags []string
 }
@@ -91,42 +153,55 @@ type expvarStatsClient struct {
 // This client points at the root of the expvar index map.
 func NewExpvarStatsClient() *expvarStatsClient {
 	return &expvarStatsClient{
-		m: Expvar,
+		mu: &sync.Mutex{},
+		m:  Expvar,
 	}
 }
 
 // Tags returns a sorted list of tags on the client.
 func (c *expvarStatsClient) Tags() []string {
-	return nil
+	return c.tags
 }
 
 // WithTags returns a new client with additional tags appended.
 func (c *expvarStatsClient) WithTags(tags ...string) StatsClient {
-	m := &expvar.Map{}
-	m.Init()
-	c.m.Set(strings.Join(tags, ","), m)
-
 	return &expvarStatsClient{
-		m:    m,
+		mu:   c.mu,
+		m:    c.m,
 		tags: unionStringSlice(c.tags, tags),
 	}
 }
 
+// key returns the name of the metric with the client's tags.
+func (c *expvarStatsClient) key(name string) string {
+	return expvarKey(name, c.tags)
+}
+
+// expvarKey returns the name of a metric with its tags flattened into it.
+func expvarKey(name string, tags []string) string {
+	if len(tags) == 0 {
+		return name
+	}
+	return name + "{" + strings.Join(tags, ",") + "}"
+}
+
 // Count tracks the number of times something occurs.
 func (c *expvarStatsClient) Count(name string, value int64, rate float64) {
-	c.m.Add(name, value)
+	c.m.Add(c.key(name), value)
 }
 
 // CountWithCustomTags Tracks the number of times something occurs per second with custom tags
 func (c *expvarStatsClient) CountWithCustomTags(name string, value int64, rate float64, tags []string) {
-	c.m.Add(name, value)
+	// Copy the tags, which unionStringSlice sorts in place.
+	tags = unionStringSlice(c.tags, append([]string(nil), tags...))
+	c.m.Add(expvarKey(name, tags), value)
 }
 
 // Gauge sets the value of a metric.
 func (c *expvarStatsClient) Gauge(name string, value float64, rate float64) {
 	var f expvar.Float
 	f.Set(value)
-	c.m.Set(name, &f)
+	c.m.Set(c.key(name), &f)
 }
 
 // Histogram tracks statistical distribution of a metric.
@@ -139,17 +214,24 @@ func (c *expvarStatsClient) Histogram(name string, value float64, rate float64)
 func (c *expvarStatsClient) Set(name string, value string, rate float64) {
 	var s expvar.String
 	s.Set(value)
-	c.m.Set(name, &s)
+	c.m.Set(c.key(name), &s)
 }
 
 // Timing tracks timing information for a metric.
 func (c *expvarStatsClient) Timing(name string, value time.Duration, rate float64) {
+	key := c.key(name)
 	c.mu.Lock()
-	d, _ := c.m.Get(name).(time.Duration)
-	c.m.Set(name, d+value)
+	d, _ := c.m.Get(key).(expvarDuration)
+	c.m.Set(key, d+expvarDuration(value))
 	c.mu.Unlock()
 }
 
+// expvarDuration is the total of a timing, written to expvars as a JSON
+// string.
+type expvarDuration time.Duration
+
+func (d expvarDuration) String() string { return strconv.Quote(time.Duration(d).String()) }
+
 // SetLogger has no logger.
 func (c *expvarStatsClient) SetLogger(logger logger.Logger) {
 }
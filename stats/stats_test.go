@@ -16,14 +16,20 @@ package stats_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	gohttp "net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/pilosa/pilosa"
+	"github.com/pilosa/pilosa/boltdb"
 	"github.com/pilosa/pilosa/http"
 	"github.com/pilosa/pilosa/logger"
+	"github.com/pilosa/pilosa/server"
 	"github.com/pilosa/pilosa/stats"
 	"github.com/pilosa/pilosa/test"
 )
@@ -45,39 +51,39 @@ func TestMultiStatClient_Expvar(t *testing.T) {
 	hldr.SetBit("d", "f", 0, pilosa.ShardWidth+2)
 	hldr.ClearBit("d", "f", 0, 1)
 
-	if stats.Expvar.String() != `{"index:d": {"field:f": {"view:standard": {"shard:0": {"clearBit": 1, "rows": 0, "setBit": 2}, "shard:1": {"rows": 0, "setBit": 2}}}}}` {
+	if stats.Expvar.String() != `{"clearBit{field:f,index:d,shard:0,view:standard}": 1, "rows{field:f,index:d,shard:0,view:standard}": 0, "rows{field:f,index:d,shard:1,view:standard}": 0, "setBit{field:f,index:d,shard:0,view:standard}": 2, "setBit{field:f,index:d,shard:1,view:standard}": 2, "view.fragments.open{field:f,index:d,view:standard}": 2}` {
 		t.Fatalf("unexpected expvar : %s", stats.Expvar.String())
 	}
 
 	hldr.Stats.CountWithCustomTags("cc", 1, 1.0, []string{"foo:bar"})
-	if stats.Expvar.String() != `{"cc": 1, "index:d": {"field:f": {"view:standard": {"shard:0": {"clearBit": 1, "rows": 0, "setBit": 2}, "shard:1": {"rows": 0, "setBit": 2}}}}}` {
+	if stats.Expvar.String() != `{"cc{foo:bar}": 1, "clearBit{field:f,index:d,shard:0,view:standard}": 1, "rows{field:f,index:d,shard:0,view:standard}": 0, "rows{field:f,index:d,shard:1,view:standard}": 0, "setBit{field:f,index:d,shard:0,view:standard}": 2, "setBit{field:f,index:d,shard:1,view:standard}": 2, "view.fragments.open{field:f,index:d,view:standard}": 2}` {
 		t.Fatalf("unexpected expvar : %s", stats.Expvar.String())
 	}
 
 	// Gauge creates a unique key, subsequent Gauge calls will overwrite
 	hldr.Stats.Gauge("g", 5, 1.0)
 	hldr.Stats.Gauge("g", 8, 1.0)
-	if stats.Expvar.String() != `{"cc": 1, "g": 8, "index:d": {"field:f": {"view:standard": {"shard:0": {"clearBit": 1, "rows": 0, "setBit": 2}, "shard:1": {"rows": 0, "setBit": 2}}}}}` {
+	if stats.Expvar.String() != `{"cc{foo:bar}": 1, "clearBit{field:f,index:d,shard:0,view:standard}": 1, "g": 8, "rows{field:f,index:d,shard:0,view:standard}": 0, "rows{field:f,index:d,shard:1,view:standard}": 0, "setBit{field:f,index:d,shard:0,view:standard}": 2, "setBit{field:f,index:d,shard:1,view:standard}": 2, "view.fragments.open{field:f,index:d,view:standard}": 2}` {
 		t.Fatalf("unexpected expvar : %s", stats.Expvar.String())
 	}
 
 	// Set creates a unique key, subsequent sets will overwrite
 	hldr.Stats.Set("s", "4", 1.0)
 	hldr.Stats.Set("s", "7", 1.0)
-	if stats.Expvar.String() != `{"cc": 1, "g": 8, "index:d": {"field:f": {"view:standard": {"shard:0": {"clearBit": 1, "rows": 0, "setBit": 2}, "shard:1": {"rows": 0, "setBit": 2}}}}, "s": "7"}` {
+	if stats.Expvar.String() != `{"cc{foo:bar}": 1, "clearBit{field:f,index:d,shard:0,view:standard}": 1, "g": 8, "rows{field:f,index:d,shard:0,view:standard}": 0, "rows{field:f,index:d,shard:1,view:standard}": 0, "s": "7", "setBit{field:f,index:d,shard:0,view:standard}": 2, "setBit{field:f,index:d,shard:1,view:standard}": 2, "view.fragments.open{field:f,index:d,view:standard}": 2}` {
 		t.Fatalf("unexpected expvar : %s", stats.Expvar.String())
 	}
 
 	// Record timing duration and a uniquely Set key/value
 	dur, _ := time.ParseDuration("123us")
 	hldr.Stats.Timing("tt", dur, 1.0)
-	if stats.Expvar.String() != `{"cc": 1, "g": 8, "index:d": {"field:f": {"view:standard": {"shard:0": {"clearBit": 1, "rows": 0, "setBit": 2}, "shard:1": {"rows": 0, "setBit": 2}}}}, "s": "7", "tt": 123µs}` {
+	if stats.Expvar.String() != `{"cc{foo:bar}": 1, "clearBit{field:f,index:d,shard:0,view:standard}": 1, "g": 8, "rows{field:f,index:d,shard:0,view:standard}": 0, "rows{field:f,index:d,shard:1,view:standard}": 0, "s": "7", "setBit{field:f,index:d,shard:0,view:standard}": 2, "setBit{field:f,index:d,shard:1,view:standard}": 2, "tt": "123µs", "view.fragments.open{field:f,index:d,view:standard}": 2}` {
 		t.Fatalf("unexpected expvar : %s", stats.Expvar.String())
 	}
 
 	// Expvar histogram is implemented as a gauge
 	hldr.Stats.Histogram("hh", 3, 1.0)
-	if stats.Expvar.String() != `{"cc": 1, "g": 8, "hh": 3, "index:d": {"field:f": {"view:standard": {"shard:0": {"clearBit": 1, "rows": 0, "setBit": 2}, "shard:1": {"rows": 0, "setBit": 2}}}}, "s": "7", "tt": 123µs}` {
+	if stats.Expvar.String() != `{"cc{foo:bar}": 1, "clearBit{field:f,index:d,shard:0,view:standard}": 1, "g": 8, "hh": 3, "rows{field:f,index:d,shard:0,view:standard}": 0, "rows{field:f,index:d,shard:1,view:standard}": 0, "s": "7", "setBit{field:f,index:d,shard:0,view:standard}": 2, "setBit{field:f,index:d,shard:1,view:standard}": 2, "tt": "123µs", "view.fragments.open{field:f,index:d,view:standard}": 2}` {
 		t.Fatalf("unexpected expvar : %s", stats.Expvar.String())
 	}
 
@@ -87,6 +93,141 @@ func TestMultiStatClient_Expvar(t *testing.T) {
 	}
 }
 
+// Ensure clients derived with WithTags write to the same metrics, whatever
+// the order in which their tags were added.
+func TestExpvarStatsClient_WithTags(t *testing.T) {
+	c := stats.NewExpvarStatsClient()
+	a := c.WithTags("index:w").WithTags("field:x")
+	b := c.WithTags("field:x", "index:w")
+	if tags := b.Tags(); !reflect.DeepEqual(tags, []string{"field:x", "index:w"}) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	a.Count("n", 1, 1.0)
+	b.Count("n", 2, 1.0)
+	b.CountWithCustomTags("n", 4, 1.0, []string{"shard:0"})
+	a.Timing("d", time.Millisecond, 1.0)
+	b.Timing("d", 2*time.Millisecond, 1.0)
+	for key, exp := range map[string]string{
+		"n{field:x,index:w}":         "3",
+		"n{field:x,index:w,shard:0}": "4",
+		"d{field:x,index:w}":         `"3ms"`,
+	} {
+		if v := stats.Expvar.Get(key); v == nil || v.String() != exp {
+			t.Fatalf("unexpected %s: %v", key, v)
+		}
+	}
+}
+
+// Ensure backends are created by name, and unknown names list the
+// registered backends.
+func TestNewStatsClient(t *testing.T) {
+	var host string
+	stats.RegisterStatsClient("test", func(c stats.Config) (stats.StatsClient, error) {
+		host = c.Host
+		return stats.NopStatsClient, nil
+	})
+	if c, err := stats.NewStatsClient("test", stats.Config{Host: "localhost:1"}); err != nil {
+		t.Fatal(err)
+	} else if c != stats.NopStatsClient || host != "localhost:1" {
+		t.Fatalf("unexpected client: %v, host: %s", c, host)
+	}
+
+	if _, err := stats.NewStatsClient("nope", stats.Config{}); err == nil || !strings.Contains(err.Error(), "choose from [expvar, none, nop, statsd, test]") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected panic")
+			}
+		}()
+		stats.RegisterStatsClient("test", func(stats.Config) (stats.StatsClient, error) { return nil, nil })
+	}()
+}
+
+// Ensure a server using the expvar backend serves its counters at
+// /debug/vars.
+func TestExpvarStatsClient_Handler(t *testing.T) {
+	c := test.MustRunCluster(t, 1, []server.CommandOption{func(c *server.Command) error {
+		c.Config.Metric.Service = "expvar"
+		return nil
+	}})
+	defer c.Close()
+	cmd := c[0]
+	h := cmd.Handler.(*http.Handler).Handler
+
+	cmd.MustCreateIndex(t, "ev", pilosa.IndexOptions{})
+	cmd.MustCreateField(t, "ev", "f")
+	cmd.MustQuery(t, &pilosa.QueryRequest{Index: "ev", Query: fmt.Sprintf("Set(0, f=1) Set(1, f=1) Set(%d, f=1) Set(1, f=2) Clear(0, f=1)", pilosa.ShardWidth)})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, test.MustNewHTTPRequest("GET", "/debug/vars", nil))
+	if w.Code != gohttp.StatusOK {
+		t.Fatalf("unexpected status code: %d", w.Code)
+	}
+	var vars struct {
+		Index map[string]interface{} `json:"index"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &vars); err != nil {
+		t.Fatal(err)
+	}
+	tags := "NodeID:" + cmd.API.Node().ID + ",field:f,index:ev"
+	for key, exp := range map[string]float64{
+		"setBit{" + tags + ",shard:0,view:standard}":   3,
+		"setBit{" + tags + ",shard:1,view:standard}":   1,
+		"clearBit{" + tags + ",shard:0,view:standard}": 1,
+	} {
+		if v, ok := vars.Index[key]; !ok || v != exp {
+			t.Fatalf("unexpected %s: %v", key, v)
+		}
+	}
+}
+
+func TestStatsTiming_Fragments(t *testing.T) {
+	hldr := test.MustOpenHolder()
+	defer hldr.Close()
+	hldr.SetBit("d", "f", 0, 0)
+	hldr.SetBit("d", "f", 0, pilosa.ShardWidth)
+
+	// Reopen the holder with the fragments on disk.
+	if err := hldr.Holder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	timings := make(map[string]int)
+	var open float64
+	path := hldr.Path
+	hldr.Holder = pilosa.NewHolder()
+	hldr.Holder.Path = path
+	hldr.Holder.NewAttrStore = boltdb.NewAttrStore
+	hldr.Holder.Stats = &MockStats{
+		mockGauge: func(name string, value float64, rate float64) {
+			if name == "view.fragments.open" {
+				open = value
+			}
+		},
+		mockTiming: func(name string, value time.Duration, rate float64) {
+			timings[name]++
+		},
+	}
+	if err := hldr.Holder.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if timings["fragment.open.duration"] != 2 {
+		t.Fatalf("unexpected fragment.open.duration timings: %d", timings["fragment.open.duration"])
+	} else if open != 2 {
+		t.Fatalf("unexpected view.fragments.open: %v", open)
+	}
+
+	// Clearing a row snapshots its fragments.
+	if _, err := hldr.Index("d").Field("f").ClearRow(0); err != nil {
+		t.Fatal(err)
+	} else if timings["fragment.snapshot.duration"] != 2 {
+		t.Fatalf("unexpected fragment.snapshot.duration timings: %d", timings["fragment.snapshot.duration"])
+	}
+}
+
 func TestStatsCount_TopN(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -298,6 +439,8 @@ func TestStatsCount_APICalls(t *testing.T) {
 type MockStats struct {
 	mockCount         func(name string, value int64, rate float64)
 	mockCountWithTags func(name string, value int64, rate float64, tags []string)
+	mockGauge         func(name string, value float64, rate float64)
+	mockTiming        func(name string, value time.Duration, rate float64)
 }
 
 func (s *MockStats) Count(name string, value int64, rate float64) {
@@ -312,12 +455,22 @@ func (s *MockStats) CountWithCustomTags(name string, value int64, rate float64,
 	}
 }
 
-func (c *MockStats) Tags() []string                                        { return nil }
-func (c *MockStats) WithTags(tags ...string) stats.StatsClient             { return c }
-func (c *MockStats) Gauge(name string, value float64, rate float64)        {}
-func (c *MockStats) Histogram(name string, value float64, rate float64)    {}
-func (c *MockStats) Set(name string, value string, rate float64)           {}
-func (c *MockStats) Timing(name string, value time.Duration, rate float64) {}
-func (c *MockStats) SetLogger(logger logger.Logger)                        {}
-func (c *MockStats) Open()                                                 {}
-func (c *MockStats) Close() error                                          { return nil }
+func (s *MockStats) Gauge(name string, value float64, rate float64) {
+	if s.mockGauge != nil {
+		s.mockGauge(name, value, rate)
+	}
+}
+
+func (s *MockStats) Timing(name string, value time.Duration, rate float64) {
+	if s.mockTiming != nil {
+		s.mockTiming(name, value, rate)
+	}
+}
+
+func (c *MockStats) Tags() []string                                     { return nil }
+func (c *MockStats) WithTags(tags ...string) stats.StatsClient          { return c }
+func (c *MockStats) Histogram(name string, value float64, rate float64) {}
+func (c *MockStats) Set(name string, value string, rate float64)        {}
+func (c *MockStats) SetLogger(logger logger.Logger)                     {}
+func (c *MockStats) Open()                                              {}
+func (c *MockStats) Close() error                                       { return nil }
@@ -37,6 +37,12 @@ const (
 // Ensure client implements interface.
 var _ stats.StatsClient = &statsClient{}
 
+func init() {
+	stats.RegisterStatsClient("statsd", func(c stats.Config) (stats.StatsClient, error) {
+		return NewStatsClient(c.Host)
+	})
+}
+
 // statsClient represents a StatsD implementation of pilosa.statsClient.
 type statsClient struct {
 	client *statsd.Client
@@ -43,6 +43,11 @@ func (b *bufferLogger) Printf(format string, v ...interface{}) {
 
 func (b *bufferLogger) Debugf(format string, v ...interface{}) {}
 
+// Errorf writes an entry prefixed the same way as the server's logger.
+func (b *bufferLogger) Errorf(format string, v ...interface{}) {
+	b.Printf("ERROR "+format, v...)
+}
+
 func (b *bufferLogger) ReadAll() ([]byte, error) {
 	return ioutil.ReadAll(b.buf)
 }
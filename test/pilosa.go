@@ -253,9 +253,13 @@ func (c Cluster) Query(t testing.TB, index, query string) pilosa.QueryResponse {
 }
 
 func (c Cluster) ImportBits(t testing.TB, index, field string, rowcols [][2]uint64) {
+	idx, err := c[0].API.Index(context.Background(), index)
+	if err != nil {
+		t.Fatalf("getting index: %v", err)
+	}
 	byShard := make(map[uint64][][2]uint64)
 	for _, rowcol := range rowcols {
-		shard := rowcol[1] / pilosa.ShardWidth
+		shard := rowcol[1] / idx.ShardWidth()
 		byShard[shard] = append(byShard[shard], rowcol)
 	}
 
@@ -276,7 +280,7 @@ func (c Cluster) ImportBits(t testing.TB, index, field string, rowcols [][2]uint
 				if com.API.Node().ID != node.ID {
 					continue
 				}
-				err := com.API.Import(context.Background(), &pilosa.ImportRequest{
+				_, err := com.API.Import(context.Background(), &pilosa.ImportRequest{
 					Index:     index,
 					Field:     field,
 					Shard:     shard,
@@ -15,6 +15,7 @@
 package pilosa
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
@@ -31,6 +32,9 @@ type TimeQuantum string
 // HasYear returns true if the quantum contains a 'Y' unit.
 func (q TimeQuantum) HasYear() bool { return strings.ContainsRune(string(q), 'Y') }
 
+// HasQuarter returns true if the quantum contains a 'Q' unit.
+func (q TimeQuantum) HasQuarter() bool { return strings.ContainsRune(string(q), 'Q') }
+
 // HasMonth returns true if the quantum contains a 'M' unit.
 func (q TimeQuantum) HasMonth() bool { return strings.ContainsRune(string(q), 'M') }
 
@@ -40,18 +44,32 @@ func (q TimeQuantum) HasDay() bool { return strings.ContainsRune(string(q), 'D')
 // HasHour returns true if the quantum contains a 'H' unit.
 func (q TimeQuantum) HasHour() bool { return strings.ContainsRune(string(q), 'H') }
 
-// Valid returns true if q is a valid time quantum value.
+// HasMinute returns true if the quantum contains a 'T' unit.
+func (q TimeQuantum) HasMinute() bool { return strings.ContainsRune(string(q), 'T') }
+
+// Valid returns true if q is a valid time quantum value. A valid quantum
+// contains each unit at most once, from largest to smallest.
 func (q TimeQuantum) Valid() bool {
-	switch q {
-	case "Y", "YM", "YMD", "YMDH",
-		"M", "MD", "MDH",
-		"D", "DH",
-		"H",
-		"":
-		return true
-	default:
-		return false
+	last := -1
+	for _, c := range q {
+		i := timeUnitIndex(c)
+		if i <= last {
+			return false
+		}
+		last = i
 	}
+	return true
+}
+
+// units returns the units of the quantum from largest to smallest.
+func (q TimeQuantum) units() []*timeUnit {
+	var a []*timeUnit
+	for _, u := range timeUnits {
+		if strings.ContainsRune(string(q), u.char) {
+			a = append(a, u)
+		}
+	}
+	return a
 }
 
 // The following methods are required to implement pflag Value interface.
@@ -71,20 +89,153 @@ func (q TimeQuantum) Type() string {
 	return "TimeQuantum"
 }
 
+// timeUnit is a granularity of time views, identified in a time quantum by
+// its character.
+type timeUnit struct {
+	char rune
+	name string
+
+	// format returns the time part of the name of the view containing t.
+	format func(t time.Time) string
+
+	// parse returns the start of the unit from the time part of a view name.
+	parse func(s string) (time.Time, error)
+
+	// truncate returns the start of the unit containing t.
+	truncate func(t time.Time) time.Time
+
+	// next returns the start of the following unit, given the start of a unit.
+	next func(t time.Time) time.Time
+}
+
+// timeUnits lists the supported units from largest to smallest. Each unit
+// must evenly divide the units before it, and the time parts of view names
+// must be distinct between units.
+var timeUnits = []*timeUnit{
+	{
+		char:     'Y',
+		name:     "year",
+		format:   layoutFormat("2006"),
+		parse:    layoutParse("2006"),
+		truncate: func(t time.Time) time.Time { return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location()) },
+		next:     func(t time.Time) time.Time { return t.AddDate(1, 0, 0) },
+	},
+	{
+		char: 'Q',
+		name: "quarter",
+		format: func(t time.Time) string {
+			return fmt.Sprintf("%04dQ%d", t.Year(), (t.Month()-1)/3+1)
+		},
+		parse: func(s string) (time.Time, error) {
+			if len(s) != 6 || s[4] != 'Q' || s[5] < '1' || s[5] > '4' {
+				return time.Time{}, errors.New("invalid quarter")
+			}
+			t, err := time.Parse("2006", s[:4])
+			if err != nil {
+				return time.Time{}, err
+			}
+			return t.AddDate(0, 3*int(s[5]-'1'), 0), nil
+		},
+		truncate: func(t time.Time) time.Time {
+			return time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, t.Location())
+		},
+		next: func(t time.Time) time.Time { return t.AddDate(0, 3, 0) },
+	},
+	{
+		char:     'M',
+		name:     "month",
+		format:   layoutFormat("200601"),
+		parse:    layoutParse("200601"),
+		truncate: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()) },
+		next:     func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+	},
+	{
+		char:     'D',
+		name:     "day",
+		format:   layoutFormat("20060102"),
+		parse:    layoutParse("20060102"),
+		truncate: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()) },
+		next:     func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+	},
+	{
+		char:   'H',
+		name:   "hour",
+		format: layoutFormat("2006010215"),
+		parse:  layoutParse("2006010215"),
+		truncate: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+		},
+		next: func(t time.Time) time.Time { return t.Add(time.Hour) },
+	},
+	{
+		char:   'T',
+		name:   "minute",
+		format: layoutFormat("200601021504"),
+		parse:  layoutParse("200601021504"),
+		truncate: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+		},
+		next: func(t time.Time) time.Time { return t.Add(time.Minute) },
+	},
+}
+
+func layoutFormat(layout string) func(time.Time) string {
+	return func(t time.Time) string { return t.Format(layout) }
+}
+
+func layoutParse(layout string) func(string) (time.Time, error) {
+	return func(s string) (time.Time, error) { return time.Parse(layout, s) }
+}
+
+// timeUnitByName returns the unit with a name, such as "day", or nil.
+func timeUnitByName(name string) *timeUnit {
+	for _, u := range timeUnits {
+		if u.name == name {
+			return u
+		}
+	}
+	return nil
+}
+
+// timeUnitIndex returns the position of a unit in timeUnits, or -1.
+func timeUnitIndex(c rune) int {
+	for i, u := range timeUnits {
+		if u.char == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// viewTimeUnit returns the unit and start time of a time view name. Only
+// names which are formatted exactly by a single unit are accepted.
+func viewTimeUnit(v string) (*timeUnit, time.Time, error) {
+	timePart := viewTimePart(v)
+
+	var unit *timeUnit
+	var start time.Time
+	for _, u := range timeUnits {
+		t, err := u.parse(timePart)
+		if err != nil || u.format(t) != timePart {
+			continue
+		} else if unit != nil {
+			return nil, time.Time{}, fmt.Errorf("ambiguous time format on view: %s", v)
+		}
+		unit, start = u, t
+	}
+	if unit == nil {
+		return nil, time.Time{}, fmt.Errorf("invalid time format on view: %s", v)
+	}
+	return unit, start, nil
+}
+
 // viewByTimeUnit returns the view name for time with a given quantum unit.
 func viewByTimeUnit(name string, t time.Time, unit rune) string {
-	switch unit {
-	case 'Y':
-		return fmt.Sprintf("%s_%s", name, t.Format("2006"))
-	case 'M':
-		return fmt.Sprintf("%s_%s", name, t.Format("200601"))
-	case 'D':
-		return fmt.Sprintf("%s_%s", name, t.Format("20060102"))
-	case 'H':
-		return fmt.Sprintf("%s_%s", name, t.Format("2006010215"))
-	default:
+	i := timeUnitIndex(unit)
+	if i < 0 {
 		return ""
 	}
+	return fmt.Sprintf("%s_%s", name, timeUnits[i].format(t))
 }
 
 // viewsByTime returns a list of views for a given timestamp.
@@ -100,130 +251,66 @@ func viewsByTime(name string, t time.Time, q TimeQuantum) []string { // nolint:
 	return a
 }
 
-// viewsByTimeRange returns a list of views to traverse to query a time range.
+// viewsByTimeRange returns the smallest set of views to traverse to query a
+// time range. Each view is the largest unit of the quantum which starts on a
+// unit boundary and fits within the range, so the views cover the range
+// exactly once. Bounds which don't fall on a boundary of the smallest unit are
+// truncated to the start of the view containing them. Views are named in UTC,
+// like the views written by imports and queries, so bounds are converted to
+// UTC before any calendar arithmetic.
 func viewsByTimeRange(name string, start, end time.Time, q TimeQuantum) []string { // nolint: unparam
-	t := start
+	units := q.units()
+	if len(units) == 0 || !start.Before(end) {
+		return nil
+	}
+	start, end = start.UTC(), end.UTC()
+	smallest := units[len(units)-1]
 
-	// Save flags for performance.
-	hasYear := q.HasYear()
-	hasMonth := q.HasMonth()
-	hasDay := q.HasDay()
-	hasHour := q.HasHour()
+	t, end := smallest.truncate(start), smallest.truncate(end)
 
 	var results []string
-
-	// Walk up from smallest units to largest units.
-	if hasHour || hasDay || hasMonth {
-		for t.Before(end) {
-			if hasHour {
-				if !nextDayGTE(t, end) {
-					break
-				} else if t.Hour() != 0 {
-					results = append(results, viewByTimeUnit(name, t, 'H'))
-					t = t.Add(time.Hour)
-					continue
-				}
-
-			}
-
-			if hasDay {
-				if !nextMonthGTE(t, end) {
-					break
-				} else if t.Day() != 1 {
-					results = append(results, viewByTimeUnit(name, t, 'D'))
-					t = t.AddDate(0, 0, 1)
-					continue
-				}
-			}
-
-			if hasMonth {
-				if !nextYearGTE(t, end) {
-					break
-				} else if t.Month() != 1 {
-					results = append(results, viewByTimeUnit(name, t, 'M'))
-					t = addMonth(t)
-					continue
-				}
-			}
-
-			// If a unit exists but isn't set and there are no larger units
-			// available then we need to exit the loop because we are no longer
-			// making progress.
-			break
-		}
-	}
-
-	// Walk back down from largest units to smallest units.
 	for t.Before(end) {
-		if hasYear && nextYearGTE(t, end) {
-			results = append(results, viewByTimeUnit(name, t, 'Y'))
-			t = t.AddDate(1, 0, 0)
-		} else if hasMonth && nextMonthGTE(t, end) {
-			results = append(results, viewByTimeUnit(name, t, 'M'))
-			t = addMonth(t)
-		} else if hasDay && nextDayGTE(t, end) {
-			results = append(results, viewByTimeUnit(name, t, 'D'))
-			t = t.AddDate(0, 0, 1)
-		} else if hasHour {
-			results = append(results, viewByTimeUnit(name, t, 'H'))
-			t = t.Add(time.Hour)
-		} else {
-			break
+		unit := smallest
+		for _, u := range units {
+			if u.truncate(t).Equal(t) && !u.next(t).After(end) {
+				unit = u
+				break
+			}
 		}
+		results = append(results, fmt.Sprintf("%s_%s", name, unit.format(t)))
+		t = unit.next(t)
 	}
 
 	return results
 }
 
-// addMonth adds a month similar to time.AddDate(0, 1, 0), but
-// in certain edge cases it doesn't normalize for days late in the month.
-// In the "YM" case where t.Day is greater than 28, there are
-// edge cases where using time.AddDate() to add a month will result
-// in two "months" being added (Jan 31 + 1mo = March 2).
-func addMonth(t time.Time) time.Time {
-	if t.Day() > 28 {
-		t = time.Date(t.Year(), t.Month(), 1, t.Hour(), 0, 0, 0, t.Location())
-	}
-	t = t.AddDate(0, 1, 0)
-	return t
-}
-
-func nextYearGTE(t time.Time, end time.Time) bool {
-	next := t.AddDate(1, 0, 0)
-	if next.Year() == end.Year() {
-		return true
+// roundTime returns the boundary of the smallest unit of q at or before t,
+// or at or after t if up is set.
+func roundTime(t time.Time, q TimeQuantum, up bool) time.Time {
+	units := q.units()
+	if len(units) == 0 {
+		return t
 	}
-	return end.After(next)
-}
+	smallest := units[len(units)-1]
 
-func nextMonthGTE(t time.Time, end time.Time) bool {
-	next := t.AddDate(0, 1, 0)
-	y1, m1, _ := next.Date()
-	y2, m2, _ := end.Date()
-	if (y1 == y2) && (m1 == m2) {
-		return true
+	start := smallest.truncate(t)
+	if up && !start.Equal(t) {
+		return smallest.next(start)
 	}
-	return end.After(next)
+	return start
 }
 
-func nextDayGTE(t time.Time, end time.Time) bool {
-	next := t.AddDate(0, 0, 1)
-	y1, m1, d1 := next.Date()
-	y2, m2, d2 := end.Date()
-	if (y1 == y2) && (m1 == m2) && (d1 == d2) {
-		return true
-	}
-	return end.After(next)
-}
-
-// parseTime parses a string or int64 into a time.Time value.
+// parseTime parses a string or int64 into a time.Time value. Strings may
+// include seconds.
 func parseTime(t interface{}) (time.Time, error) {
 	var err error
 	var calcTime time.Time
 	switch v := t.(type) {
 	case string:
 		if calcTime, err = time.Parse(TimeFormat, v); err != nil {
-			return time.Time{}, errors.New("cannot parse string time")
+			if calcTime, err = time.Parse(timeFormatSeconds, v); err != nil {
+				return time.Time{}, errors.New("cannot parse string time")
+			}
 		}
 	case int64:
 		calcTime = time.Unix(v, 0).UTC()
@@ -241,30 +328,28 @@ func minMaxViews(views []string, q TimeQuantum) (min string, max string) {
 	// Sort the list of views.
 	sort.Strings(views)
 
-	// Determine the least significant quantum and set that as the
-	// number of string characters to compare against.
-	var chars int
-	if q.HasYear() {
-		chars = 4
-	} else if q.HasMonth() {
-		chars = 6
-	} else if q.HasDay() {
-		chars = 8
-	} else if q.HasHour() {
-		chars = 10
+	// Determine the least significant quantum and only compare views
+	// of that unit.
+	units := q.units()
+	if len(units) == 0 {
+		return "", ""
+	}
+	isUnit := func(v string) bool {
+		u, _, err := viewTimeUnit(v)
+		return err == nil && u == units[0]
 	}
 
-	// min: get the first view with the matching number of time chars.
+	// min: get the first view with the matching unit.
 	for _, v := range views {
-		if len(viewTimePart(v)) == chars {
+		if isUnit(v) {
 			min = v
 			break
 		}
 	}
 
-	// max: get the first view (from the end) with the matching number of time chars.
+	// max: get the first view (from the end) with the matching unit.
 	for i := len(views) - 1; i >= 0; i-- {
-		if len(viewTimePart(views[i])) == chars {
+		if isUnit(views[i]) {
 			max = views[i]
 			break
 		}
@@ -281,49 +366,14 @@ func timeOfView(v string, adj bool) (time.Time, error) {
 		return time.Time{}, nil
 	}
 
-	layout := "2006010203"
-	timePart := viewTimePart(v)
-
-	switch len(timePart) {
-	case 4: // year
-		t, err := time.Parse(layout[:4], timePart)
-		if err != nil {
-			return time.Time{}, err
-		}
-		if adj {
-			t = t.AddDate(1, 0, 0)
-		}
-		return t, nil
-	case 6: // month
-		t, err := time.Parse(layout[:6], timePart)
-		if err != nil {
-			return time.Time{}, err
-		}
-		if adj {
-			t = addMonth(t)
-		}
-		return t, nil
-	case 8: // day
-		t, err := time.Parse(layout[:8], timePart)
-		if err != nil {
-			return time.Time{}, err
-		}
-		if adj {
-			t = t.AddDate(0, 0, 1)
-		}
-		return t, nil
-	case 10: // hour
-		t, err := time.Parse(layout[:10], timePart)
-		if err != nil {
-			return time.Time{}, err
-		}
-		if adj {
-			t = t.Add(time.Hour)
-		}
-		return t, nil
+	unit, t, err := viewTimeUnit(v)
+	if err != nil {
+		return time.Time{}, err
 	}
-
-	return time.Time{}, fmt.Errorf("invalid time format on view: %s", v)
+	if adj {
+		t = unit.next(t)
+	}
+	return t, nil
 }
 
 // viewTimePart returns the time portion of a string view name.
@@ -332,3 +382,102 @@ func viewTimePart(v string) string {
 	parts := strings.Split(v, "_")
 	return parts[len(parts)-1]
 }
+
+// TimeRetention is the period for which a time field keeps the views of each
+// granularity. A view is expired once its whole period ends before the
+// retention for its granularity. A zero duration keeps views forever.
+type TimeRetention struct {
+	Year    time.Duration
+	Quarter time.Duration
+	Month   time.Duration
+	Day     time.Duration
+	Hour    time.Duration
+	Minute  time.Duration
+}
+
+// timeRetentionJSON is the JSON representation of TimeRetention.
+type timeRetentionJSON struct {
+	Year    string `json:"year,omitempty"`
+	Quarter string `json:"quarter,omitempty"`
+	Month   string `json:"month,omitempty"`
+	Day     string `json:"day,omitempty"`
+	Hour    string `json:"hour,omitempty"`
+	Minute  string `json:"minute,omitempty"`
+}
+
+// IsZero returns true if no views are ever expired.
+func (r TimeRetention) IsZero() bool { return r == TimeRetention{} }
+
+// MarshalJSON encodes each duration as a string, e.g. "2160h0m0s".
+func (r TimeRetention) MarshalJSON() ([]byte, error) {
+	var o timeRetentionJSON
+	for _, d := range []struct {
+		v time.Duration
+		s *string
+	}{{r.Year, &o.Year}, {r.Quarter, &o.Quarter}, {r.Month, &o.Month}, {r.Day, &o.Day}, {r.Hour, &o.Hour}, {r.Minute, &o.Minute}} {
+		if d.v != 0 {
+			*d.s = d.v.String()
+		}
+	}
+	return json.Marshal(o)
+}
+
+// UnmarshalJSON decodes durations such as "2160h" for each granularity.
+func (r *TimeRetention) UnmarshalJSON(data []byte) error {
+	var o timeRetentionJSON
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	var other TimeRetention
+	for _, d := range []struct {
+		s string
+		v *time.Duration
+	}{{o.Year, &other.Year}, {o.Quarter, &other.Quarter}, {o.Month, &other.Month}, {o.Day, &other.Day}, {o.Hour, &other.Hour}, {o.Minute, &other.Minute}} {
+		if d.s == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.s)
+		if err != nil {
+			return fmt.Errorf("invalid retention: %s", err)
+		} else if v < 0 {
+			return fmt.Errorf("invalid retention: negative duration %q", d.s)
+		}
+		*d.v = v
+	}
+	*r = other
+	return nil
+}
+
+// expired returns true if the entire period of a time view ends more than
+// the retention for its granularity before now. Views which are not time
+// views are never expired.
+func (r TimeRetention) expired(v string, now time.Time) bool {
+	if !strings.HasPrefix(v, viewStandard+"_") {
+		return false
+	}
+
+	unit, start, err := viewTimeUnit(v)
+	if err != nil {
+		return false
+	}
+
+	var retention time.Duration
+	switch unit.char {
+	case 'Y':
+		retention = r.Year
+	case 'Q':
+		retention = r.Quarter
+	case 'M':
+		retention = r.Month
+	case 'D':
+		retention = r.Day
+	case 'H':
+		retention = r.Hour
+	case 'T':
+		retention = r.Minute
+	}
+	if retention <= 0 {
+		return false
+	}
+	return !unit.next(start).After(now.Add(-retention))
+}
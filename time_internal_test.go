@@ -15,6 +15,8 @@
 package pilosa
 
 import (
+	"encoding/json"
+	"math/rand"
 	"reflect"
 	"strings"
 	"testing"
@@ -36,6 +38,19 @@ func TestParseTimeQuantum(t *testing.T) {
 			t.Fatalf("unexpected error: %s", err)
 		}
 	})
+
+	t.Run("Units", func(t *testing.T) {
+		for _, s := range []string{"YQMDHT", "YQ", "Q", "QM", "HT", "T", "YD"} {
+			if _, err := parseTimeQuantum(s); err != nil {
+				t.Fatalf("%s: unexpected error: %s", s, err)
+			}
+		}
+		for _, s := range []string{"QY", "TH", "YY", "MQ", "S"} {
+			if _, err := parseTimeQuantum(s); err != ErrInvalidTimeQuantum {
+				t.Fatalf("%s: expected invalid time quantum, got: %v", s, err)
+			}
+		}
+	})
 }
 
 // Ensure generated view name can be returned for a given time unit.
@@ -62,6 +77,19 @@ func TestViewByTimeUnit(t *testing.T) {
 			t.Fatalf("unexpected name: %s", s)
 		}
 	})
+	t.Run("Q", func(t *testing.T) {
+		if s := viewByTimeUnit("F", ts, 'Q'); s != "F_2000Q1" {
+			t.Fatalf("unexpected name: %s", s)
+		}
+		if s := viewByTimeUnit("F", ts.AddDate(0, 11, 0), 'Q'); s != "F_2000Q4" {
+			t.Fatalf("unexpected name: %s", s)
+		}
+	})
+	t.Run("T", func(t *testing.T) {
+		if s := viewByTimeUnit("F", ts, 'T'); s != "F_200001020304" {
+			t.Fatalf("unexpected name: %s", s)
+		}
+	})
 }
 
 // Ensure all applicable field names can be generated when mutating a time bit.
@@ -81,6 +109,13 @@ func TestViewsByTime(t *testing.T) {
 			t.Fatalf("unexpected names: %+v", a)
 		}
 	})
+
+	t.Run("YQMDHT", func(t *testing.T) {
+		a := viewsByTime("F", ts, mustParseTimeQuantum("YQMDHT"))
+		if !reflect.DeepEqual(a, []string{"F_2000", "F_2000Q1", "F_200001", "F_20000102", "F_2000010203", "F_200001020304"}) {
+			t.Fatalf("unexpected names: %+v", a)
+		}
+	})
 }
 
 // Ensure sets of fields can be returned for a given time range.
@@ -165,6 +200,196 @@ func TestViewsByTimeRange(t *testing.T) {
 	})
 }
 
+// Ensure the views for a time range cover the same time as the union of one
+// view of the smallest unit per step, exactly once, using the fewest views.
+func TestViewsByTimeRange_Cover(t *testing.T) {
+	base := time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rand := rand.New(rand.NewSource(0))
+	for _, tt := range []struct {
+		quanta []TimeQuantum
+		step   time.Duration
+		span   time.Duration
+	}{
+		{
+			quanta: []TimeQuantum{"Y", "YM", "YMD", "YMDH", "M", "MD", "MDH", "D", "DH", "H", "YD", "YH", "YDH", "YMH", "MH", "Q", "YQ", "YQM", "YQMDH", "QD"},
+			step:   time.Hour,
+			span:   800 * 24 * time.Hour,
+		},
+		{
+			quanta: []TimeQuantum{"T", "HT", "DHT", "YQMDHT", "YT", "MT"},
+			step:   time.Minute,
+			span:   4 * 24 * time.Hour,
+		},
+	} {
+		for _, q := range tt.quanta {
+			units := []rune(string(q))
+			smallest := units[len(units)-1]
+
+			for i := 0; i < 25; i++ {
+				start := base.Add(time.Duration(rand.Intn(int(3*365*24*time.Hour/tt.step))) * tt.step)
+				var end time.Time
+				switch rand.Intn(3) {
+				case 0:
+					end = start.Add(time.Duration(rand.Intn(int(tt.span/tt.step/100))) * tt.step)
+				case 1:
+					end = start.Add(time.Duration(rand.Intn(int(tt.span/tt.step/8))) * tt.step)
+				default:
+					end = start.Add(time.Duration(rand.Intn(int(tt.span/tt.step))) * tt.step)
+				}
+
+				// Naive plan: one view of the smallest unit for each step in the
+				// range, excluding the view containing the end of the range.
+				var naive []string
+				last := viewByTimeUnit("F", end, smallest)
+				for tm := start; tm.Before(end); tm = tm.Add(tt.step) {
+					if v := viewByTimeUnit("F", tm, smallest); v == last {
+						break
+					} else if len(naive) == 0 || naive[len(naive)-1] != v {
+						naive = append(naive, v)
+					}
+				}
+
+				views := viewsByTimeRange("F", start, end, q)
+				if len(naive) == 0 {
+					if len(views) != 0 {
+						t.Fatalf("%s [%s, %s): unexpected views: %v", q, start, end, views)
+					}
+					continue
+				}
+
+				// Every step covered by the naive plan must be covered by exactly
+				// one view, and no view may cover anything else.
+				lo, hi := mustTimeOfView(t, naive[0], false), mustTimeOfView(t, naive[len(naive)-1], true)
+				covered := make([]int, hi.Sub(lo)/tt.step)
+				for _, v := range views {
+					vstart, vend := mustTimeOfView(t, v, false), mustTimeOfView(t, v, true)
+					if vstart.Before(lo) || vend.After(hi) {
+						t.Fatalf("%s [%s, %s): view %s outside of range: %v", q, start, end, v, views)
+					}
+					for i := vstart.Sub(lo) / tt.step; i < vend.Sub(lo)/tt.step; i++ {
+						covered[i]++
+					}
+				}
+				for i, n := range covered {
+					if n != 1 {
+						t.Fatalf("%s [%s, %s): %s covered %d times: %v", q, start, end, lo.Add(time.Duration(i)*tt.step), n, views)
+					}
+				}
+
+				// No view may be replaced by a larger view which also fits in the
+				// range covered by the naive plan.
+				for _, v := range views {
+					vstart := mustTimeOfView(t, v, false)
+					for _, unit := range units {
+						larger := viewByTimeUnit("F", vstart, unit)
+						if larger == v {
+							break
+						} else if !mustTimeOfView(t, larger, false).Before(lo) && !mustTimeOfView(t, larger, true).After(hi) {
+							t.Fatalf("%s [%s, %s): view %s could be replaced by %s: %v", q, start, end, v, larger, views)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Ensure ranges across year, month and day boundaries, including leap days,
+// are covered by the expected views and match a per-hour union of the range.
+func TestViewsByTimeRange_Calendar(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		q          TimeQuantum
+		start, end string
+		views      []string
+		n          int
+
+		// Location to pass the bounds in, the views are always in UTC.
+		zone *time.Location
+	}{
+		{
+			name: "NewYearDays", q: "YMD", start: "1999-12-30 00:00", end: "2000-01-03 00:00",
+			views: []string{"F_19991230", "F_19991231", "F_20000101", "F_20000102"},
+		},
+		{
+			name: "LeapDay", q: "YMD", start: "2020-02-27 00:00", end: "2020-03-02 00:00",
+			views: []string{"F_20200227", "F_20200228", "F_20200229", "F_20200301"},
+		},
+		{
+			name: "LeapFebruary", q: "YMD", start: "2020-02-01 00:00", end: "2020-03-01 00:00",
+			views: []string{"F_202002"},
+		},
+		{
+			name: "LeapFebruaryDays", q: "D", start: "2020-02-01 00:00", end: "2020-03-01 00:00",
+			n: 29,
+		},
+		{
+			name: "NonLeapFebruaryDays", q: "D", start: "2019-02-01 00:00", end: "2019-03-01 00:00",
+			n: 28,
+		},
+		{
+			name: "NewYearsEveHours", q: "YMDH", start: "2018-12-31 22:00", end: "2019-01-01 02:00",
+			views: []string{"F_2018123122", "F_2018123123", "F_2019010100", "F_2019010101"},
+		},
+		{
+			name: "MultiYearMonths", q: "YM", start: "2017-11-01 00:00", end: "2020-03-01 00:00",
+			views: []string{"F_201711", "F_201712", "F_2018", "F_2019", "F_202001", "F_202002"},
+		},
+		{
+			name: "NewYearsEveHoursOffset", q: "YMDH", start: "2018-12-31 22:00", end: "2019-01-01 02:00", zone: time.FixedZone("", 5*3600),
+			views: []string{"F_2018123122", "F_2018123123", "F_2019010100", "F_2019010101"},
+		},
+		{
+			name: "MultiYearMonthsOnly", q: "M", start: "2017-11-01 00:00", end: "2020-03-01 00:00",
+			n: 28,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := mustParseTime(tt.start), mustParseTime(tt.end)
+			from, to := start, end
+			if tt.zone != nil {
+				from, to = start.In(tt.zone), end.In(tt.zone)
+			}
+			views := viewsByTimeRange("F", from, to, tt.q)
+			if tt.views != nil && !reflect.DeepEqual(views, tt.views) {
+				t.Fatalf("unexpected views: %v", views)
+			} else if tt.n != 0 && len(views) != tt.n {
+				t.Fatalf("unexpected number of views: %d: %v", len(views), views)
+			}
+
+			// Oracle: every hour of the range is covered by exactly one view,
+			// and no hour outside of it is covered.
+			covered := make(map[time.Time]int)
+			for _, v := range views {
+				vend := mustTimeOfView(t, v, true)
+				for h := mustTimeOfView(t, v, false); h.Before(vend); h = h.Add(time.Hour) {
+					covered[h]++
+				}
+			}
+			n := 0
+			for h := start; h.Before(end); h = h.Add(time.Hour) {
+				if covered[h] != 1 {
+					t.Fatalf("%s covered %d times: %v", h, covered[h], views)
+				}
+				n++
+			}
+			if len(covered) != n {
+				t.Fatalf("views cover %d hours outside of the range: %v", len(covered)-n, views)
+			}
+		})
+	}
+}
+
+// mustTimeOfView returns the time of a view or fails the test.
+func mustTimeOfView(tb testing.TB, v string, adj bool) time.Time {
+	tm, err := timeOfView(v, adj)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return tm
+}
+
 func TestMinMaxViews(t *testing.T) {
 	t.Run("Combos", func(t *testing.T) {
 		tests := []struct {
@@ -203,6 +428,12 @@ func TestMinMaxViews(t *testing.T) {
 				"std_20190201",
 				"std_20190201",
 			},
+			{
+				[]string{"std_2019Q2", "std_201904", "std_201812", "std_2019Q1"},
+				mustParseTimeQuantum("QM"),
+				"std_2019Q1",
+				"std_2019Q2",
+			},
 			{
 				[]string{"foo", "bar"},
 				mustParseTimeQuantum("D"),
@@ -252,6 +483,12 @@ func TestTimeOfView(t *testing.T) {
 				time.Date(2019, 2, 3, 9, 0, 0, 0, time.UTC),
 				"",
 			},
+			{
+				"std_2019020317",
+				time.Date(2019, 2, 3, 17, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 3, 18, 0, 0, 0, time.UTC),
+				"",
+			},
 			{
 				"foo",
 				time.Time{},
@@ -260,9 +497,45 @@ func TestTimeOfView(t *testing.T) {
 			},
 			{
 				"std_201902030801",
+				time.Date(2019, 2, 3, 8, 1, 0, 0, time.UTC),
+				time.Date(2019, 2, 3, 8, 2, 0, 0, time.UTC),
+				"",
+			},
+			{
+				"std_2019Q4",
+				time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				"",
+			},
+			{
+				"std_20190203080",
+				time.Time{},
+				time.Time{},
+				"invalid time format on view: std_20190203080",
+			},
+			{
+				"std_2019Q5",
+				time.Time{},
+				time.Time{},
+				"invalid time format on view: std_2019Q5",
+			},
+			{
+				"std_201913",
 				time.Time{},
 				time.Time{},
-				"invalid time format on view: std_201902030801",
+				"invalid time format on view: std_201913",
+			},
+			{
+				"std_2019q1",
+				time.Time{},
+				time.Time{},
+				"invalid time format on view: std_2019q1",
+			},
+			{
+				"std_+019",
+				time.Time{},
+				time.Time{},
+				"invalid time format on view: std_+019",
 			},
 		}
 		for i, test := range tests {
@@ -319,3 +592,62 @@ func parseTimeQuantum(v string) (TimeQuantum, error) {
 	}
 	return q, nil
 }
+
+// Ensure a view is expired only once its whole period is older than the
+// retention for its granularity.
+func TestTimeRetention_Expired(t *testing.T) {
+	r := TimeRetention{Quarter: 365 * 24 * time.Hour, Day: 48 * time.Hour, Hour: 2 * time.Hour, Minute: 10 * time.Minute}
+	now := time.Date(2018, time.March, 10, 12, 30, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		view    string
+		expired bool
+	}{
+		{"standard_2018031009", true},
+		{"standard_2018031010", false},
+		{"standard_2018031012", false},
+		{"standard_20180307", true},
+		{"standard_20180308", false},
+		{"standard_201801", false},
+		{"standard_2010", false},
+		{"standard_2016Q4", true},
+		{"standard_2017Q1", false},
+		{"standard_201803101219", true},
+		{"standard_201803101220", false},
+		{"standard", false},
+		{"bsig_f", false},
+		{"standard_x", false},
+	} {
+		if v := r.expired(tt.view, now); v != tt.expired {
+			t.Errorf("%s: expected expired=%v", tt.view, tt.expired)
+		}
+	}
+}
+
+// Ensure retention durations are encoded as strings.
+func TestTimeRetention_JSON(t *testing.T) {
+	var r TimeRetention
+	if err := json.Unmarshal([]byte(`{"hour":"2160h","day":"8760h"}`), &r); err != nil {
+		t.Fatal(err)
+	} else if r != (TimeRetention{Day: 8760 * time.Hour, Hour: 2160 * time.Hour}) {
+		t.Fatalf("unexpected retention: %+v", r)
+	}
+
+	if buf, err := json.Marshal(r); err != nil {
+		t.Fatal(err)
+	} else if string(buf) != `{"day":"8760h0m0s","hour":"2160h0m0s"}` {
+		t.Fatalf("unexpected json: %s", buf)
+	}
+
+	if err := json.Unmarshal([]byte(`{"quarter":"8760h","minute":"1h"}`), &r); err != nil {
+		t.Fatal(err)
+	} else if r != (TimeRetention{Quarter: 8760 * time.Hour, Minute: time.Hour}) {
+		t.Fatalf("unexpected retention: %+v", r)
+	}
+
+	for _, s := range []string{`{"hour":"1x"}`, `{"day":"-1h"}`} {
+		if err := json.Unmarshal([]byte(s), &r); err == nil {
+			t.Fatalf("expected error: %s", s)
+		}
+	}
+}
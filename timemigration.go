@@ -0,0 +1,352 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// timeMigrationFile is the name of the file in a field's directory which
+// holds the state of an unfinished time migration.
+const timeMigrationFile = ".timemigration"
+
+// TimeMigration is a job which builds the time views of larger units of a
+// field by unioning the existing views of a smaller unit, e.g. month views
+// from day views. Unioning is idempotent, so an interrupted migration is
+// resumed by rebuilding the views which weren't finished.
+type TimeMigration struct {
+	Index string      `json:"index"`
+	Field string      `json:"field"`
+	From  TimeQuantum `json:"from"`
+	To    TimeQuantum `json:"to"`
+
+	// Views lists the progress of each view being built.
+	Views []*TimeMigrationView `json:"views"`
+
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+}
+
+// TimeMigrationView is the progress of building a single view.
+type TimeMigrationView struct {
+	View    string   `json:"view"`
+	Sources []string `json:"sources"`
+
+	// The number of local shards to build and the number built so far.
+	Shards int `json:"shards"`
+	Built  int `json:"built"`
+}
+
+// copy returns a deep copy of m.
+func (m *TimeMigration) copy() *TimeMigration {
+	other := *m
+	other.Views = make([]*TimeMigrationView, len(m.Views))
+	for i, v := range m.Views {
+		view := *v
+		other.Views[i] = &view
+	}
+	return &other
+}
+
+// timeMigrations tracks the time migrations of a holder.
+type timeMigrations struct {
+	mu sync.Mutex
+
+	// Migrations by index and field name.
+	m map[string]*TimeMigration
+}
+
+func newTimeMigrations() *timeMigrations {
+	return &timeMigrations{m: make(map[string]*TimeMigration)}
+}
+
+// newTimeMigration plans a migration of f which builds views of the units
+// in to from the views of unit from. An empty to builds every unit of the
+// field's quantum which is larger than from.
+func newTimeMigration(f *Field, from, to TimeQuantum) (*TimeMigration, error) {
+	q := f.TimeQuantum()
+	if len(from) != 1 || timeUnitIndex(rune(from[0])) < 0 {
+		return nil, errors.Errorf("invalid source unit: %q", from)
+	} else if !to.Valid() {
+		return nil, ErrInvalidTimeQuantum
+	}
+	source := rune(from[0])
+
+	if to == "" {
+		for _, u := range q.units() {
+			if timeUnitIndex(u.char) < timeUnitIndex(source) {
+				to += TimeQuantum(u.char)
+			}
+		}
+		if to == "" {
+			return nil, errors.Errorf("field quantum %q has no units larger than %q", q, from)
+		}
+	}
+	for _, c := range to {
+		if timeUnitIndex(c) >= timeUnitIndex(source) {
+			return nil, errors.Errorf("cannot build %q views from %q views, only larger units can be built from smaller ones", string(c), from)
+		} else if !strings.ContainsRune(string(q), c) {
+			return nil, errors.Errorf("unit %q is not in the field quantum %q", string(c), q)
+		}
+	}
+
+	// Group the source views by the views they are unioned into.
+	m := &TimeMigration{Index: f.Index(), Field: f.Name(), From: from, To: to}
+	targets := make(map[string]*TimeMigrationView)
+	for _, v := range f.views() {
+		unit, start, err := viewTimeUnit(v.name)
+		if err != nil || unit.char != source || v.name != viewByTimeUnit(viewStandard, start, source) {
+			continue
+		}
+		for _, c := range to {
+			name := viewByTimeUnit(viewStandard, start, c)
+			if targets[name] == nil {
+				targets[name] = &TimeMigrationView{View: name}
+				m.Views = append(m.Views, targets[name])
+			}
+			targets[name].Sources = append(targets[name].Sources, v.name)
+		}
+	}
+	sort.Slice(m.Views, func(i, j int) bool { return m.Views[i].View < m.Views[j].View })
+	for _, v := range m.Views {
+		sort.Strings(v.Sources)
+		v.Shards = len(timeMigrationShards(f, v))
+	}
+	return m, nil
+}
+
+// timeMigrationShards returns the local shards of the source views of v.
+func timeMigrationShards(f *Field, v *TimeMigrationView) []uint64 {
+	set := make(map[uint64]struct{})
+	for _, name := range v.Sources {
+		view := f.view(name)
+		if view == nil {
+			continue
+		}
+		for _, shard := range view.availableShards().Slice() {
+			set[shard] = struct{}{}
+		}
+	}
+	shards := make([]uint64, 0, len(set))
+	for shard := range set {
+		shards = append(shards, shard)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+	return shards
+}
+
+// StartTimeMigration plans and starts a time view migration of a field.
+func (h *Holder) StartTimeMigration(index, field string, from, to TimeQuantum) (*TimeMigration, error) {
+	f := h.Field(index, field)
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+
+	h.timeMigrations.mu.Lock()
+	defer h.timeMigrations.mu.Unlock()
+
+	key := index + "/" + field
+	if m := h.timeMigrations.m[key]; m != nil && !m.Done {
+		return nil, ErrTimeMigrationRunning
+	}
+
+	m, err := newTimeMigration(f, from, to)
+	if err != nil {
+		return nil, err
+	} else if err := saveTimeMigration(f, m); err != nil {
+		return nil, errors.Wrap(err, "saving time migration")
+	}
+	h.timeMigrations.m[key] = m
+	h.runTimeMigration(f, m)
+	return m.copy(), nil
+}
+
+// TimeMigration returns the state of the latest time migration of a field,
+// or nil if the field hasn't been migrated since the holder was opened.
+func (h *Holder) TimeMigration(index, field string) *TimeMigration {
+	h.timeMigrations.mu.Lock()
+	defer h.timeMigrations.mu.Unlock()
+	if m := h.timeMigrations.m[index+"/"+field]; m != nil {
+		return m.copy()
+	}
+	return nil
+}
+
+// resumeTimeMigrations restarts the unfinished migrations of every field.
+func (h *Holder) resumeTimeMigrations() {
+	for _, index := range h.Indexes() {
+		for _, f := range index.Fields() {
+			m, err := loadTimeMigration(f)
+			if err != nil {
+				h.Logger.Printf("loading time migration: index=%s, field=%s, err=%s", f.Index(), f.Name(), err)
+				continue
+			} else if m == nil {
+				continue
+			}
+
+			h.Logger.Printf("resuming time migration: index=%s, field=%s", f.Index(), f.Name())
+			h.timeMigrations.mu.Lock()
+			h.timeMigrations.m[f.Index()+"/"+f.Name()] = m
+			h.runTimeMigration(f, m)
+			h.timeMigrations.mu.Unlock()
+		}
+	}
+}
+
+// runTimeMigration builds the unfinished views of m in the background.
+// Progress is saved after each view so the migration can be resumed if the
+// holder is closed first. The caller must hold the timeMigrations lock.
+func (h *Holder) runTimeMigration(f *Field, m *TimeMigration) {
+	// Views which weren't finished are rebuilt from the start.
+	for _, v := range m.Views {
+		if v.Built < v.Shards {
+			v.Built = 0
+		}
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		err := h.migrateTimeViews(f, m)
+		if err == errTimeMigrationInterrupted {
+			return
+		}
+
+		h.timeMigrations.mu.Lock()
+		defer h.timeMigrations.mu.Unlock()
+		m.Done = true
+		if err != nil {
+			h.Logger.Printf("time migration error: index=%s, field=%s, err=%s", m.Index, m.Field, err)
+			m.Error = err.Error()
+		}
+		if err := os.Remove(filepath.Join(f.Path(), timeMigrationFile)); err != nil && !os.IsNotExist(err) {
+			h.Logger.Printf("removing time migration: index=%s, field=%s, err=%s", m.Index, m.Field, err)
+		}
+	}()
+}
+
+// errTimeMigrationInterrupted is returned when the holder closes during a
+// time migration.
+var errTimeMigrationInterrupted = errors.New("time migration interrupted")
+
+// migrateTimeViews unions the source views of each unfinished view of m.
+func (h *Holder) migrateTimeViews(f *Field, m *TimeMigration) error {
+	h.timeMigrations.mu.Lock()
+	views := m.copy().Views
+	h.timeMigrations.mu.Unlock()
+
+	for i, v := range views {
+		if v.Built == v.Shards {
+			continue
+		}
+
+		for _, shard := range timeMigrationShards(f, v) {
+			select {
+			case <-h.closing:
+				return errTimeMigrationInterrupted
+			default:
+			}
+
+			if h.Field(m.Index, m.Field) != f {
+				return ErrFieldNotFound
+			} else if err := migrateTimeFragment(f, v, shard); err != nil {
+				return errors.Wrapf(err, "building view %s, shard %d", v.View, shard)
+			}
+
+			h.timeMigrations.mu.Lock()
+			m.Views[i].Built++
+			h.timeMigrations.mu.Unlock()
+		}
+
+		h.timeMigrations.mu.Lock()
+		m.Views[i].Built = m.Views[i].Shards
+		err := saveTimeMigration(f, m)
+		h.timeMigrations.mu.Unlock()
+		if err != nil {
+			return errors.Wrap(err, "saving time migration")
+		}
+		h.Stats.Count("timeMigrationView", 1, 1.0)
+	}
+	return nil
+}
+
+// migrateTimeFragment unions one shard of the source views of v into v.
+func migrateTimeFragment(f *Field, v *TimeMigrationView, shard uint64) error {
+	target, err := f.createViewIfNotExists(v.View)
+	if err != nil {
+		return errors.Wrap(err, "creating view")
+	}
+	frag, err := target.acquireFragmentIfNotExists(shard)
+	if err != nil {
+		return errors.Wrap(err, "creating fragment")
+	}
+	defer frag.release()
+
+	for _, name := range v.Sources {
+		source := f.view(name)
+		if source == nil {
+			continue
+		}
+		sourceFrag, err := source.acquireFragment(shard)
+		if err != nil {
+			return errors.Wrapf(err, "reading view %s", name)
+		} else if sourceFrag == nil {
+			continue
+		}
+
+		data, err := sourceFrag.marshalStorage()
+		sourceFrag.release()
+		if err != nil {
+			return errors.Wrapf(err, "reading view %s", name)
+		} else if err := frag.importRoaring(data, false); err != nil {
+			return errors.Wrapf(err, "importing view %s", name)
+		}
+	}
+	return nil
+}
+
+// loadTimeMigration reads the unfinished time migration of f, if any.
+func loadTimeMigration(f *Field) (*TimeMigration, error) {
+	buf, err := ioutil.ReadFile(filepath.Join(f.Path(), timeMigrationFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var m TimeMigration
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling")
+	}
+	return &m, nil
+}
+
+// saveTimeMigration writes the state of m to the directory of f.
+func saveTimeMigration(f *Field, m *TimeMigration) error {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "marshaling")
+	}
+	return writeMetaFile(filepath.Join(f.Path(), timeMigrationFile), buf, 0666)
+}
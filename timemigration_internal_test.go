@@ -0,0 +1,191 @@
+// Copyright 2017 Pilosa Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilosa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHolder_TimeMigration(t *testing.T) {
+	// newField returns a field with day views in two months and two shards,
+	// whose quantum has since been changed to YMD.
+	newField := func(t *testing.T, h *tHolder) *Field {
+		idx := h.MustCreateIndexIfNotExists("i", IndexOptions{})
+		f, err := idx.CreateFieldIfNotExists("f", OptFieldTypeTime("D"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, bit := range []struct {
+			col uint64
+			ts  string
+		}{
+			{1, "2018-01-01T00:00"},
+			{2, "2018-01-31T00:00"},
+			{ShardWidth + 3, "2018-01-15T00:00"},
+			{4, "2018-02-03T00:00"},
+		} {
+			ts, err := time.Parse(TimeFormat, bit.ts)
+			if err != nil {
+				t.Fatal(err)
+			} else if _, err := f.SetBit(10, bit.col, &ts); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.setTimeQuantum("YMD"); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	// columns returns the columns of row 10 of a view of the field.
+	columns := func(t *testing.T, h *tHolder, view string) []uint64 {
+		v := h.Field("i", "f").view(view)
+		if v == nil {
+			t.Fatalf("view %s not found", view)
+		}
+		return v.row(10).Columns()
+	}
+
+	t.Run("Build", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		newField(t, h)
+
+		m, err := h.StartTimeMigration("i", "f", "D", "")
+		if err != nil {
+			t.Fatal(err)
+		} else if m.To != "YM" {
+			t.Fatalf("unexpected units: %s", m.To)
+		}
+		var views []string
+		for _, v := range m.Views {
+			views = append(views, v.View)
+		}
+		if !reflect.DeepEqual(views, []string{"standard_2018", "standard_201801", "standard_201802"}) {
+			t.Fatalf("unexpected views: %v", views)
+		} else if m.Views[1].Shards != 2 || !reflect.DeepEqual(m.Views[1].Sources, []string{"standard_20180101", "standard_20180115", "standard_20180131"}) {
+			t.Fatalf("unexpected view: %+v", m.Views[1])
+		}
+
+		m = mustWaitTimeMigration(t, h, "i", "f")
+		if m.Error != "" {
+			t.Fatal(m.Error)
+		}
+		for _, v := range m.Views {
+			if v.Built != v.Shards {
+				t.Fatalf("view not built: %+v", v)
+			}
+		}
+		if cols := columns(t, h, "standard_2018"); !reflect.DeepEqual(cols, []uint64{1, 2, 4, ShardWidth + 3}) {
+			t.Fatalf("unexpected year columns: %v", cols)
+		} else if cols := columns(t, h, "standard_201801"); !reflect.DeepEqual(cols, []uint64{1, 2, ShardWidth + 3}) {
+			t.Fatalf("unexpected month columns: %v", cols)
+		} else if cols := columns(t, h, "standard_201802"); !reflect.DeepEqual(cols, []uint64{4}) {
+			t.Fatalf("unexpected month columns: %v", cols)
+		}
+		if _, err := os.Stat(filepath.Join(h.Field("i", "f").Path(), timeMigrationFile)); !os.IsNotExist(err) {
+			t.Fatalf("expected state file to be removed: %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		newField(t, h)
+
+		for _, tt := range []struct {
+			from, to TimeQuantum
+			err      string
+		}{
+			{"M", "D", `cannot build "D" views from "M" views`},
+			{"D", "D", `cannot build "D" views from "D" views`},
+			{"D", "H", `cannot build "H" views from "D" views`},
+			{"D", "Q", `unit "Q" is not in the field quantum`},
+			{"Y", "", `has no units larger than`},
+			{"YM", "", `invalid source unit`},
+			{"D", "MY", ErrInvalidTimeQuantum.Error()},
+		} {
+			if _, err := h.StartTimeMigration("i", "f", tt.from, tt.to); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("from %q to %q: expected %q, got %v", tt.from, tt.to, tt.err, err)
+			}
+		}
+		if _, err := h.StartTimeMigration("i", "nope", "D", ""); err != ErrFieldNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		} else if m := h.TimeMigration("i", "f"); m != nil {
+			t.Fatalf("unexpected migration: %+v", m)
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		h := newHolder()
+		if err := h.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		f := newField(t, h)
+
+		// Save a migration with one view finished, as if the holder was
+		// closed while it was running.
+		m, err := newTimeMigration(f, "D", "M")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Views[0].Built = m.Views[0].Shards
+		m.Views[1].Built = 0
+		if err := saveTimeMigration(f, m); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := h.Holder.Close(); err != nil {
+			t.Fatal(err)
+		} else if err := h.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+
+		m = mustWaitTimeMigration(t, h, "i", "f")
+		if m.Error != "" {
+			t.Fatal(m.Error)
+		}
+		if cols := columns(t, h, "standard_201802"); !reflect.DeepEqual(cols, []uint64{4}) {
+			t.Fatalf("unexpected month columns: %v", cols)
+		} else if v := h.Field("i", "f").view("standard_201801"); v != nil {
+			t.Fatalf("expected finished view to be skipped")
+		}
+	})
+}
+
+// mustWaitTimeMigration waits for the migration of a field to finish.
+func mustWaitTimeMigration(tb testing.TB, h *tHolder, index, field string) *TimeMigration {
+	tb.Helper()
+	for i := 0; i < 1000; i++ {
+		if m := h.TimeMigration(index, field); m != nil && m.Done {
+			return m
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("time migration of %s/%s didn't finish", index, field)
+	return nil
+}
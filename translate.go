@@ -80,6 +80,10 @@ type TranslateFile struct {
 
 	// Delay after attempting to connect to a primary that the store will retry.
 	replicationRetryInterval time.Duration
+
+	// If set, the data file is only read, if it exists, and nothing is
+	// replicated from a primary.
+	readOnly bool
 }
 
 // TranslateFileOption is a functional option type for pilosa.TranslateFile
@@ -138,6 +142,10 @@ func NewTranslateFile(opts ...TranslateFileOption) *TranslateFile {
 }
 
 func (s *TranslateFile) Open() (err error) {
+	if s.readOnly {
+		return s.openReadOnly()
+	}
+
 	// Open writer & buffered writer.
 	if err := os.MkdirAll(filepath.Dir(s.Path), 0777); err != nil {
 		return errors.Wrapf(err, "mkdir %s", filepath.Dir(s.Path))
@@ -163,6 +171,25 @@ func (s *TranslateFile) Open() (err error) {
 	return nil
 }
 
+// openReadOnly opens the data file, if it exists, without creating or
+// writing to it.
+func (s *TranslateFile) openReadOnly() (err error) {
+	if s.file, err = os.Open(s.Path); err == nil {
+		if s.data, err = syscall.Mmap(int(s.file.Fd()), 0, s.mapSize, syscall.PROT_READ, syscall.MAP_SHARED); err != nil {
+			return errors.Wrapf(err, "creating Mmap (size: %d)", s.mapSize)
+		} else if err := s.replayEntries(); err != nil {
+			return errors.Wrap(err, "replaying log entries")
+		}
+	} else if !os.IsNotExist(err) {
+		return errors.Wrapf(err, "open file %s", s.Path)
+	}
+
+	s.wg.Add(1)
+	go func() { defer s.wg.Done(); s.monitorPrimaryStoreEvents() }()
+
+	return nil
+}
+
 // primaryStoreEvent is used to set/change the primary translate store.
 // It contains a TranslateStore along with an associated string ID which
 // is used to determine whether the primary needs to be changed from the
@@ -209,7 +236,7 @@ func (s *TranslateFile) handlePrimaryStoreEvent(ev primaryStoreEvent) error {
 	}
 
 	// Start translate store replication. Stream from primary, if available.
-	if s.PrimaryTranslateStore != nil {
+	if s.PrimaryTranslateStore != nil && !s.readOnly {
 		s.replicationClosing = make(chan struct{})
 		s.repWG.Add(1)
 		go func() { defer s.repWG.Done(); s.monitorReplication() }()
@@ -250,9 +277,10 @@ func (s *TranslateFile) size() int64 {
 	return n
 }
 
-// isReadOnly returns true if this store is being replicated from a primary store.
+// isReadOnly returns true if this store is being replicated from a primary
+// store, or was opened read-only.
 func (s *TranslateFile) isReadOnly() bool {
-	return s.PrimaryTranslateStore != nil
+	return s.readOnly || s.PrimaryTranslateStore != nil
 }
 
 // WriteNotify returns a channel that is closed when a new entry is written.
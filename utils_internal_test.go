@@ -372,7 +372,7 @@ func (t *ClusterCluster) FollowResizeInstruction(instr *ResizeInstruction) error
 		destCluster := t.clusterByID(instrNode.ID)
 
 		// Sync the schema received in the resize instruction.
-		if err := destCluster.holder.applySchema(instr.NodeStatus.Schema); err != nil {
+		if err := destCluster.holder.applySchema(instr.NodeStatus.Schema, false); err != nil {
 			return err
 		}
 
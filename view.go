@@ -15,10 +15,12 @@
 package pilosa
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +30,7 @@ import (
 	"github.com/pilosa/pilosa/roaring"
 	"github.com/pilosa/pilosa/stats"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 // View layout modes.
@@ -49,13 +52,46 @@ type view struct {
 	cacheType string
 	cacheSize uint32
 
-	// Fragments by shard.
+	// Number of column IDs in each fragment, set by the index.
+	shardWidth uint64
+
+	// Open fragments by shard.
 	fragments map[uint64]*fragment
 
-	broadcaster  broadcaster
-	stats        stats.StatsClient
-	rowAttrStore AttrStore
-	logger       logger.Logger
+	// Set once the view starts closing, after which no fragments are
+	// created in it.
+	closing bool
+
+	// Fragments on disk which were closed while idle, by shard. They are
+	// reopened when next accessed.
+	closed map[uint64]*closedFragment
+
+	broadcaster     broadcaster
+	stats           stats.StatsClient
+	rowAttrStore    AttrStore
+	logger          logger.Logger
+	cacheAccountant *cacheAccountant
+	cacheRebuilder  *cacheRebuilder
+	snapshotQueue   *snapshotQueue
+	maxColumnID     *maxID
+	writeStats      *writeStats
+	durability      Durability
+	maxOpN          int
+	readOnly        bool
+
+	// How long closing a fragment waits for its readers.
+	fragmentCloseTimeout time.Duration
+
+	// Number of fragments opened at once when the view is opened. Zero uses
+	// GOMAXPROCS.
+	fragmentOpenConcurrency int
+
+	// What opening the view does with fragments which fail to open.
+	fragmentOpenPolicy FragmentOpenPolicy
+
+	// Layout of the fragments on disk. Before the view is opened, the
+	// layout used if it is new.
+	fragmentLayout FragmentLayout
 }
 
 // newView returns a new instance of View.
@@ -70,7 +106,13 @@ func newView(path, index, field, name string, fieldOptions FieldOptions) *view {
 		cacheType: fieldOptions.CacheType,
 		cacheSize: fieldOptions.CacheSize,
 
-		fragments: make(map[uint64]*fragment),
+		shardWidth: ShardWidth,
+
+		fragments:      make(map[uint64]*fragment),
+		closed:         make(map[uint64]*closedFragment),
+		fragmentLayout: FragmentLayoutFlat,
+
+		fragmentOpenPolicy: FragmentOpenStrict,
 
 		broadcaster: NopBroadcaster,
 		stats:       stats.NopStatsClient,
@@ -80,6 +122,8 @@ func newView(path, index, field, name string, fieldOptions FieldOptions) *view {
 
 // open opens and initializes the view.
 func (v *view) open() error {
+	// A view may be opened again after it was closed.
+	v.closing = false
 
 	// Never keep a cache for field views.
 	if strings.HasPrefix(v.name, viewBSIGroupPrefix) {
@@ -87,11 +131,17 @@ func (v *view) open() error {
 	}
 
 	if err := func() error {
-		// Ensure the view's path exists.
-		if err := os.MkdirAll(v.path, 0777); err != nil {
-			return errors.Wrap(err, "creating view directory")
-		} else if err := os.MkdirAll(filepath.Join(v.path, "fragments"), 0777); err != nil {
-			return errors.Wrap(err, "creating fragments directory")
+		// Ensure the view's path exists, unless it is read-only.
+		if !v.readOnly {
+			if err := os.MkdirAll(v.path, 0777); err != nil {
+				return errors.Wrap(err, "creating view directory")
+			} else if err := os.MkdirAll(filepath.Join(v.path, "fragments"), 0777); err != nil {
+				return errors.Wrap(err, "creating fragments directory")
+			}
+		}
+
+		if err := v.openLayout(); err != nil {
+			return errors.Wrap(err, "opening layout")
 		}
 
 		if err := v.openFragments(); err != nil {
@@ -107,60 +157,139 @@ func (v *view) open() error {
 	return nil
 }
 
-// openFragments opens and initializes the fragments inside the view.
-func (v *view) openFragments() error {
-	file, err := os.Open(filepath.Join(v.path, "fragments"))
-	if os.IsNotExist(err) {
+// openLayout determines the layout of the view's fragments: the one recorded
+// for the view, else the one found on disk, else the configured one if the
+// view is new. An interrupted migration to the recorded layout is finished.
+func (v *view) openLayout() error {
+	l, ok, err := readViewLayout(v.path)
+	if err != nil {
+		return errors.Wrap(err, "reading layout")
+	} else if !ok {
+		if l, ok, err = detectFragmentLayout(v.path); err != nil {
+			return errors.Wrap(err, "detecting layout")
+		} else if !ok {
+			l = v.fragmentLayout
+		}
+	}
+
+	// Read-only views leave their fragments where they are, even if a
+	// migration was interrupted, and open each one where it is found.
+	if v.readOnly {
+		v.fragmentLayout = l
 		return nil
-	} else if err != nil {
-		return errors.Wrap(err, "opening fragments directory")
 	}
-	defer file.Close()
 
-	fis, err := file.Readdir(0)
+	n, err := migrateViewLayout(v.path, l)
+	if err != nil {
+		return errors.Wrap(err, "migrating layout")
+	} else if n > 0 {
+		v.logger.Printf("moved %d fragments of view %s/%s/%s to the %s layout", n, v.index, v.field, v.name, l)
+	}
+	v.fragmentLayout = l
+	return nil
+}
+
+// openFragments opens and initializes the fragments inside the view.
+func (v *view) openFragments() error {
+	files, err := fragmentFiles(v.path)
 	if err != nil {
 		return errors.Wrap(err, "reading fragments directory")
 	}
 
-	for _, fi := range fis {
-		if fi.IsDir() {
-			continue
-		}
+	shards := make([]uint64, 0, len(files))
+	for shard := range files {
+		shards = append(shards, shard)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
 
-		// Parse filename into integer.
-		shard, err := strconv.ParseUint(filepath.Base(fi.Name()), 10, 64)
-		if err != nil {
-			continue
-		}
+	concurrency := v.fragmentOpenConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
 
-		frag := v.newFragment(v.fragmentPath(shard), shard)
-		if err := frag.Open(); err != nil {
-			return fmt.Errorf("open fragment: shard=%d, err=%s", frag.shard, err)
+	// Open the fragments, in shard order, with a pool of workers. Each is
+	// added to the view once it's open, so that closing the view after one
+	// fails closes those already opened. No more are started after a failure.
+	eg, ctx := errgroup.WithContext(context.Background())
+	ch := make(chan uint64)
+	for i := 0; i < concurrency; i++ {
+		eg.Go(func() error {
+			for shard := range ch {
+				frag := v.newFragment(files[shard], shard)
+				start := time.Now()
+				if err := frag.Open(); err != nil {
+					if v.fragmentOpenPolicy != FragmentOpenQuarantine {
+						return fmt.Errorf("open fragment: shard=%d, err=%s", frag.shard, err)
+					} else if qerr := v.quarantineFragment(shard, files[shard], err); qerr != nil {
+						return fmt.Errorf("quarantine fragment: shard=%d, err=%s, open err=%s", frag.shard, qerr, err)
+					}
+					continue
+				}
+				frag.stats.Timing("fragment.open.duration", time.Since(start), 1.0)
+				frag.RowAttrStore = v.rowAttrStore
+
+				v.mu.Lock()
+				v.fragments[frag.shard] = frag
+				v.mu.Unlock()
+			}
+			return nil
+		})
+	}
+	func() {
+		defer close(ch)
+		for _, shard := range shards {
+			select {
+			case ch <- shard:
+			case <-ctx.Done():
+				return
+			}
 		}
-		frag.RowAttrStore = v.rowAttrStore
-		v.fragments[frag.shard] = frag
+	}()
+	if err := eg.Wait(); err != nil {
+		return err
 	}
+	v.stats.Gauge("view.fragments.open", float64(len(v.fragments)), 1.0)
 
 	return nil
 }
 
-// close closes the view and its fragments.
+// close closes the view and its fragments. The view is only locked while
+// its fragments are taken from it, so that callers looking fragments up
+// aren't held up while readers drain.
 func (v *view) close() error {
 	v.mu.Lock()
-	defer v.mu.Unlock()
-
-	// Close all fragments.
+	frags := make([]*fragment, 0, len(v.fragments))
 	for _, frag := range v.fragments {
-		if err := frag.Close(); err != nil {
-			return errors.Wrap(err, "closing fragment")
-		}
+		frags = append(frags, frag)
 	}
 	v.fragments = make(map[uint64]*fragment)
+	v.closed = make(map[uint64]*closedFragment)
+	v.closing = true
+	v.mu.Unlock()
+
+	// Refuse new readers of every fragment before waiting for any, so that
+	// they all wait for their readers until the same deadline, rather than
+	// one after another.
+	for _, frag := range frags {
+		frag.refuseReaders()
+	}
+	timeout := v.fragmentCloseTimeout
+	if timeout <= 0 {
+		timeout = defaultFragmentCloseTimeout
+	}
+	deadline := time.Now().Add(timeout)
 
-	return nil
+	// Close all fragments, in shard order, even if some fail.
+	sort.Slice(frags, func(i, j int) bool { return frags[i].shard < frags[j].shard })
+	var errs closeErrors
+	for _, frag := range frags {
+		errs.append(frag.closeWithin(time.Until(deadline)), "index=%s field=%s view=%s shard=%d", v.index, v.field, v.name, frag.shard)
+	}
+	return errs.err()
 }
 
-// availableShards returns a bitmap of shards which contain data.
+// availableShards returns a bitmap of shards which contain data, including
+// those whose fragments were closed while idle.
 func (v *view) availableShards() *roaring.Bitmap {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -169,57 +298,405 @@ func (v *view) availableShards() *roaring.Bitmap {
 	for shard := range v.fragments {
 		b.Add(shard) // ignore error, no writer attached
 	}
+	for shard := range v.closed {
+		b.Add(shard)
+	}
 	return b
 }
 
 // fragmentPath returns the path to a fragment in the view.
 func (v *view) fragmentPath(shard uint64) string {
-	return filepath.Join(v.path, "fragments", strconv.FormatUint(shard, 10))
+	return v.fragmentLayout.fragmentPath(v.path, shard)
 }
 
-// Fragment returns a fragment in the view by shard.
+// Fragment returns a fragment in the view by shard, reopening it if it was
+// closed while idle. It returns nil if the fragment can't be reopened.
 func (v *view) Fragment(shard uint64) *fragment {
+	v.mu.RLock()
+	frag, closed := v.fragments[shard], v.closed[shard] != nil
+	v.mu.RUnlock()
+	if frag != nil {
+		frag.touch()
+		return frag
+	} else if !closed {
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	frag, err := v.reopenFragment(shard)
+	if err != nil {
+		v.logger.Errorf("reopening fragment: index=%s field=%s view=%s shard=%d err=%s", v.index, v.field, v.name, shard, err)
+		return nil
+	}
+	return frag
+}
+
+// acquireFragment returns the fragment of shard like Fragment, acquired so
+// that it isn't closed while the caller reads it, or nil if the view has
+// none. A fragment which was closed while idle as it was acquired is
+// reopened. It returns ErrFragmentClosing if the view is closing. Each
+// fragment returned must be released.
+func (v *view) acquireFragment(shard uint64) (*fragment, error) {
+	for {
+		frag := v.Fragment(shard)
+		if frag == nil {
+			return nil, nil
+		} else if err := frag.acquire(); err == nil {
+			return frag, nil
+		} else if !v.closedIdle(shard, frag) {
+			return nil, ErrFragmentClosing
+		}
+	}
+}
+
+// acquireFragmentIfNotExists returns the fragment of shard like
+// CreateFragmentIfNotExists, acquired so that it isn't closed while the
+// caller writes it. A fragment which was closed while idle as it was
+// acquired is reopened, so that writes aren't made to its closed storage.
+// It returns ErrFragmentClosing if the view is closing. The fragment must be
+// released.
+func (v *view) acquireFragmentIfNotExists(shard uint64) (*fragment, error) {
+	for {
+		frag, err := v.CreateFragmentIfNotExists(shard)
+		if err != nil {
+			return nil, err
+		} else if err := frag.acquire(); err == nil {
+			return frag, nil
+		} else if !v.closedIdle(shard, frag) {
+			return nil, ErrFragmentClosing
+		}
+	}
+}
+
+// closedIdle returns true if frag, which refused to be acquired, was closed
+// by the view while idle and can be reopened, rather than closed with the
+// view or deleted. The view closes idle fragments while holding v.mu.
+func (v *view) closedIdle(shard uint64, frag *fragment) bool {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
-	return v.fragments[shard]
+	current, closed := v.fragments[shard], v.closed[shard] != nil
+	return current != frag && (current != nil || closed)
 }
 
-// allFragments returns a list of all fragments in the view.
+// allFragments returns a list of all fragments in the view, reopening those
+// which were closed while idle.
 func (v *view) allFragments() []*fragment {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	for shard := range v.closed {
+		if _, err := v.reopenFragment(shard); err != nil {
+			v.logger.Errorf("reopening fragment: index=%s field=%s view=%s shard=%d err=%s", v.index, v.field, v.name, shard, err)
+		}
+	}
+
 	other := make([]*fragment, 0, len(v.fragments))
 	for _, fragment := range v.fragments {
+		fragment.touch()
 		other = append(other, fragment)
 	}
 	return other
 }
 
-// recalculateCaches recalculates the cache on every fragment in the view.
+// loadedFragments returns a list of the open fragments in the view, without
+// reopening those which were closed while idle.
+func (v *view) loadedFragments() []*fragment {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	other := make([]*fragment, 0, len(v.fragments))
+	for _, fragment := range v.fragments {
+		other = append(other, fragment)
+	}
+	return other
+}
+
+// closedFragment is what a view keeps of a fragment it closed while idle.
+type closedFragment struct {
+	path     string
+	bitCount uint64
+	rowCount uint64
+	maxRowID uint64
+}
+
+// reopenFragment opens a fragment which was closed while idle, unless it
+// has already been reopened, and returns it. It returns nil if the fragment
+// doesn't exist. v.mu must be held for writing.
+func (v *view) reopenFragment(shard uint64) (*fragment, error) {
+	if frag := v.fragments[shard]; frag != nil {
+		frag.touch()
+		return frag, nil
+	}
+	cf := v.closed[shard]
+	if cf == nil {
+		return nil, nil
+	}
+
+	start := time.Now()
+	frag := v.newFragment(cf.path, shard)
+	if err := frag.Open(); err != nil {
+		return nil, errors.Wrap(err, "opening fragment")
+	}
+	frag.RowAttrStore = v.rowAttrStore
+
+	delete(v.closed, shard)
+	v.fragments[shard] = frag
+	v.stats.Timing("fragment.reopen.duration", time.Since(start), 1.0)
+	v.stats.Gauge("view.fragments.open", float64(len(v.fragments)), 1.0)
+	return frag, nil
+}
+
+// closeIdleFragments closes the fragments of the view which haven't been
+// accessed for idle, and then those least recently accessed while more
+// than maxOpen are open. Zero disables either limit. Fragments held by a
+// reader are left open. It returns the number of fragments closed.
+func (v *view) closeIdleFragments(idle time.Duration, maxOpen int) int {
+	if idle <= 0 && maxOpen <= 0 {
+		return 0
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	type candidate struct {
+		frag     *fragment
+		accessed time.Time
+	}
+	frags := make([]candidate, 0, len(v.fragments))
+	for _, frag := range v.fragments {
+		frags = append(frags, candidate{frag: frag, accessed: frag.lastAccessed()})
+	}
+	sort.Slice(frags, func(i, j int) bool { return frags[i].accessed.Before(frags[j].accessed) })
+
+	now := time.Now()
+	var n int
+	for _, c := range frags {
+		reason := "idle"
+		if idle <= 0 || now.Sub(c.accessed) < idle {
+			if maxOpen <= 0 || len(v.fragments) <= maxOpen {
+				break
+			}
+			reason = "max"
+		}
+
+		// Reading the fragment before it's closed is what lets the view
+		// report on it without reopening it.
+		cf := &closedFragment{path: c.frag.path, bitCount: c.frag.BitCount(), rowCount: c.frag.RowCount(), maxRowID: c.frag.maxRow()}
+		if ok, err := c.frag.closeUnlessRead(); !ok {
+			continue
+		} else if err != nil {
+			v.logger.Errorf("closing idle fragment: index=%s field=%s view=%s shard=%d err=%s", v.index, v.field, v.name, c.frag.shard, err)
+		}
+		delete(v.fragments, c.frag.shard)
+		v.closed[c.frag.shard] = cf
+		v.stats.CountWithCustomTags("fragment.evict", 1, 1.0, []string{"reason:" + reason})
+		n++
+	}
+	if n > 0 {
+		v.stats.Gauge("view.fragments.open", float64(len(v.fragments)), 1.0)
+	}
+	return n
+}
+
+// fragmentStats returns the current statistics of each fragment in the
+// view, in shard order, without reopening those closed while idle.
+func (v *view) fragmentStats() []*FragmentStats {
+	v.mu.RLock()
+	frags := make([]*fragment, 0, len(v.fragments))
+	for _, frag := range v.fragments {
+		frags = append(frags, frag)
+	}
+	a := make([]*FragmentStats, 0, len(v.fragments)+len(v.closed))
+	for shard, cf := range v.closed {
+		a = append(a, &FragmentStats{Shard: shard, BitCount: cf.bitCount, RowCount: cf.rowCount, FileBytes: fileSize(cf.path)})
+	}
+	v.mu.RUnlock()
+
+	for _, frag := range frags {
+		a = append(a, frag.statistics())
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Shard < a[j].Shard })
+	return a
+}
+
+// BitCount returns the number of bits set in the view on this node, without
+// reopening fragments closed while idle.
+func (v *view) BitCount() uint64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var n uint64
+	for _, frag := range v.fragments {
+		n += frag.BitCount()
+	}
+	for _, cf := range v.closed {
+		n += cf.bitCount
+	}
+	return n
+}
+
+// RowCount returns the number of rows with any bits set in each fragment of
+// the view on this node, summed. A row set in several shards is counted in
+// each, as fragments don't know the rows of the others.
+func (v *view) RowCount() uint64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var n uint64
+	for _, frag := range v.fragments {
+		n += frag.RowCount()
+	}
+	for _, cf := range v.closed {
+		n += cf.rowCount
+	}
+	return n
+}
+
+// maxRowID returns the highest row ID of any fragment in the view, without
+// reopening those closed while idle.
+func (v *view) maxRowID() uint64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var max uint64
+	for _, frag := range v.fragments {
+		if rowID := frag.maxRow(); rowID > max {
+			max = rowID
+		}
+	}
+	for _, cf := range v.closed {
+		if cf.maxRowID > max {
+			max = cf.maxRowID
+		}
+	}
+	return max
+}
+
+// fragmentChecksums returns the checksum of each fragment in the view, in
+// shard order.
+func (v *view) fragmentChecksums() []FragmentChecksum {
+	frags := v.allFragments()
+	a := make([]FragmentChecksum, 0, len(frags))
+	for _, frag := range frags {
+		a = append(a, FragmentChecksum{Shard: frag.shard, Checksum: frag.Checksum()})
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Shard < a[j].Shard })
+	return a
+}
+
+// compact compacts each fragment in the view in turn, in shard order, and
+// returns the number of bytes reclaimed.
+func (v *view) compact() (int64, error) {
+	if v.readOnly {
+		return 0, newForbiddenError(ErrReadOnly)
+	}
+	frags := v.allFragments()
+	sort.Slice(frags, func(i, j int) bool { return frags[i].shard < frags[j].shard })
+
+	var reclaimed int64
+	for _, f := range frags {
+		frag, err := v.acquireFragment(f.shard)
+		if err == ErrFragmentClosing {
+			continue // deleted while compacting the others
+		} else if err != nil {
+			return reclaimed, err
+		} else if frag == nil {
+			continue
+		}
+		n, err := frag.Compact()
+		frag.release()
+		if err == ErrFragmentClosing {
+			continue
+		} else if err != nil {
+			return reclaimed, errors.Wrapf(err, "compacting shard %d", frag.shard)
+		}
+		reclaimed += n
+	}
+	return reclaimed, nil
+}
+
+// clearColumnRange clears the columns in [min, max] from every fragment of
+// the view whose shard overlaps the range, and returns the number of bits
+// cleared.
+func (v *view) clearColumnRange(min, max uint64) (uint64, error) {
+	if v.readOnly {
+		return 0, newForbiddenError(ErrReadOnly)
+	}
+
+	var n uint64
+	for _, frag := range v.allFragments() {
+		if frag.shard < min/v.shardWidth || frag.shard > max/v.shardWidth {
+			continue
+		}
+		cleared, err := frag.clearColumnRange(min, max)
+		if err == ErrFragmentClosing {
+			continue // deleted while clearing the others
+		} else if err != nil {
+			return n, errors.Wrapf(err, "clearing shard %d", frag.shard)
+		}
+		n += cleared
+	}
+	return n, nil
+}
+
+// recalculateCaches recalculates the cache on every open fragment in the
+// view. Closed fragments recalculate theirs when they are reopened.
 func (v *view) recalculateCaches() {
-	for _, fragment := range v.allFragments() {
+	for _, fragment := range v.loadedFragments() {
 		fragment.RecalculateCache()
 	}
 }
 
+// rebuildCaches rebuilds the cache of every fragment in the view from storage
+// in the background.
+func (v *view) rebuildCaches() error {
+	for _, fragment := range v.allFragments() {
+		if err := fragment.rebuildCacheInBackground(); err != nil {
+			return errors.Wrapf(err, "rebuilding cache of shard %d", fragment.shard)
+		}
+	}
+	return nil
+}
+
+// setCacheSize changes the cache size of the view and of each of its
+// fragments.
+func (v *view) setCacheSize(n uint32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cacheSize = n
+	for _, frag := range v.fragments {
+		frag.setCacheSize(n)
+	}
+}
+
 // CreateFragmentIfNotExists returns a fragment in the view by shard.
 func (v *view) CreateFragmentIfNotExists(shard uint64) (*fragment, error) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	// Find fragment in cache first.
 	if frag := v.fragments[shard]; frag != nil {
+		frag.touch()
 		return frag, nil
+	} else if v.closed[shard] != nil {
+		return v.reopenFragment(shard)
+	} else if v.closing {
+		return nil, ErrFragmentClosing
+	} else if v.readOnly {
+		return nil, newForbiddenError(ErrReadOnly)
 	}
 
 	// Initialize and open fragment.
 	frag := v.newFragment(v.fragmentPath(shard), shard)
-	if err := frag.Open(); err != nil {
+	if err := os.MkdirAll(filepath.Dir(frag.path), 0777); err != nil {
+		return nil, errors.Wrap(err, "creating fragment directory")
+	} else if err := frag.Open(); err != nil {
 		return nil, errors.Wrap(err, "opening fragment")
 	}
 	frag.RowAttrStore = v.rowAttrStore
 
 	v.fragments[shard] = frag
+	v.stats.Gauge("view.fragments.open", float64(len(v.fragments)), 1.0)
 	broadcastChan := make(chan struct{})
 
 	go func() {
@@ -231,7 +708,7 @@ func (v *view) CreateFragmentIfNotExists(shard uint64) (*fragment, error) {
 		// Broadcast a message that a new max shard was just created.
 		err := v.broadcaster.SendSync(msg)
 		if err != nil {
-			v.logger.Printf("broadcasting create shard: %v", err)
+			v.logger.Errorf("broadcasting create shard: %v", err)
 		}
 		close(broadcastChan)
 	}()
@@ -251,8 +728,22 @@ func (v *view) newFragment(path string, shard uint64) *fragment {
 	frag := newFragment(path, v.index, v.field, v.name, shard)
 	frag.CacheType = v.cacheType
 	frag.CacheSize = v.cacheSize
-	frag.Logger = v.logger
+	frag.shardWidth = v.shardWidth
+	frag.Logger = logger.With(v.logger, "index", v.index, "field", v.field, "view", v.name, "shard", shard)
 	frag.stats = v.stats.WithTags(fmt.Sprintf("shard:%d", shard))
+	frag.cacheAccountant = v.cacheAccountant
+	frag.cacheRebuilder = v.cacheRebuilder
+	frag.snapshotQueue = v.snapshotQueue
+	frag.maxColumnID = v.maxColumnID
+	frag.writeStats = v.writeStats
+	frag.durability = v.durability
+	if v.maxOpN > 0 {
+		frag.MaxOpN = v.maxOpN
+	}
+	if v.fragmentCloseTimeout > 0 {
+		frag.CloseTimeout = v.fragmentCloseTimeout
+	}
+	frag.readOnly = v.readOnly
 	if v.fieldType == FieldTypeMutex {
 		frag.mutexVector = newRowsVector(frag)
 	} else if v.fieldType == FieldTypeBool {
@@ -263,29 +754,42 @@ func (v *view) newFragment(path string, shard uint64) *fragment {
 
 // deleteFragment removes the fragment from the view.
 func (v *view) deleteFragment(shard uint64) error {
-	fragment := v.Fragment(shard)
-	if fragment == nil {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	fragment, cf := v.fragments[shard], v.closed[shard]
+	if fragment == nil && cf == nil {
 		return ErrFragmentNotFound
+	} else if v.readOnly {
+		return newForbiddenError(ErrReadOnly)
 	}
 
 	v.logger.Printf("delete fragment: (%s/%s/%s) %d", v.index, v.field, v.name, shard)
 
-	// Close data files before deletion.
-	if err := fragment.Close(); err != nil {
+	// Close data files before deletion. A fragment closed while idle has
+	// none open.
+	var path string
+	if fragment == nil {
+		path = cf.path
+	} else if err := fragment.Close(); err != nil {
 		return errors.Wrap(err, "closing fragment")
+	} else {
+		path = fragment.path
 	}
 
 	// Delete fragment file.
-	if err := os.Remove(fragment.path); err != nil {
+	if err := os.Remove(path); err != nil {
 		return errors.Wrap(err, "deleting fragment file")
 	}
 
 	// Delete fragment cache file.
-	if err := os.Remove(fragment.cachePath()); err != nil {
+	if err := os.Remove(path + cacheExt); err != nil {
 		v.logger.Printf("no cache file to delete for shard %d", shard)
 	}
 
 	delete(v.fragments, shard)
+	delete(v.closed, shard)
+	v.stats.Gauge("view.fragments.open", float64(len(v.fragments)), 1.0)
 
 	return nil
 }
@@ -306,109 +810,163 @@ func (v *view) row(rowID uint64) *Row {
 
 // setBit sets a bit within the view.
 func (v *view) setBit(rowID, columnID uint64) (changed bool, err error) {
-	shard := columnID / ShardWidth
-	frag, err := v.CreateFragmentIfNotExists(shard)
+	shard := columnID / v.shardWidth
+	frag, err := v.acquireFragmentIfNotExists(shard)
 	if err != nil {
 		return changed, err
 	}
+	defer frag.release()
 	return frag.setBit(rowID, columnID)
 }
 
 // clearBit clears a bit within the view.
 func (v *view) clearBit(rowID, columnID uint64) (changed bool, err error) {
-	shard := columnID / ShardWidth
-	frag := v.Fragment(shard)
-	if frag == nil {
-		return false, nil
+	shard := columnID / v.shardWidth
+	frag, err := v.acquireFragment(shard)
+	if err != nil || frag == nil {
+		return false, err
 	}
+	defer frag.release()
 	return frag.clearBit(rowID, columnID)
 }
 
+// clearRow clears a row in every fragment of the view. The view isn't locked
+// while the fragments are cleared, so writes to other shards can proceed.
+func (v *view) clearRow(rowID uint64) (changed bool, err error) {
+	for _, f := range v.allFragments() {
+		frag, err := v.acquireFragment(f.shard)
+		if err == ErrFragmentClosing {
+			continue // deleted while clearing the others
+		} else if err != nil {
+			return changed, err
+		} else if frag == nil {
+			continue
+		}
+		cleared, err := frag.clearRow(rowID)
+		frag.release()
+		if err != nil {
+			return changed, errors.Wrapf(err, "clearing row on shard %d", frag.shard)
+		}
+		changed = changed || cleared
+	}
+	return changed, nil
+}
+
 // value uses a column of bits to read a multi-bit value.
 func (v *view) value(columnID uint64, bitDepth uint) (value uint64, exists bool, err error) {
-	shard := columnID / ShardWidth
-	frag, err := v.CreateFragmentIfNotExists(shard)
+	shard := columnID / v.shardWidth
+	frag, err := v.acquireFragmentIfNotExists(shard)
 	if err != nil {
 		return value, exists, err
 	}
+	defer frag.release()
 	return frag.value(columnID, bitDepth)
 }
 
 // setValue uses a column of bits to set a multi-bit value.
 func (v *view) setValue(columnID uint64, bitDepth uint, value uint64) (changed bool, err error) {
-	shard := columnID / ShardWidth
-	frag, err := v.CreateFragmentIfNotExists(shard)
+	shard := columnID / v.shardWidth
+	frag, err := v.acquireFragmentIfNotExists(shard)
 	if err != nil {
 		return changed, err
 	}
+	defer frag.release()
 	return frag.setValue(columnID, bitDepth, value)
 }
 
+// forEachFragment calls fn with each fragment of the view in turn, acquired
+// so that it isn't closed while fn reads it. Fragments deleted while the
+// others are read are skipped.
+func (v *view) forEachFragment(fn func(frag *fragment) error) error {
+	for _, f := range v.allFragments() {
+		frag, err := v.acquireFragment(f.shard)
+		if err == ErrFragmentClosing {
+			continue
+		} else if err != nil {
+			return err
+		} else if frag == nil {
+			continue
+		}
+		err = fn(frag)
+		frag.release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // sum returns the sum & count of a field.
 func (v *view) sum(filter *Row, bitDepth uint) (sum, count uint64, err error) {
-	for _, f := range v.allFragments() {
+	err = v.forEachFragment(func(f *fragment) error {
 		fsum, fcount, err := f.sum(filter, bitDepth)
 		if err != nil {
-			return sum, count, err
+			return err
 		}
 		sum += fsum
 		count += fcount
-	}
-	return sum, count, nil
+		return nil
+	})
+	return sum, count, err
 }
 
 // min returns the min and count of a field.
 func (v *view) min(filter *Row, bitDepth uint) (min, count uint64, err error) {
 	var minHasValue bool
-	for _, f := range v.allFragments() {
+	err = v.forEachFragment(func(f *fragment) error {
 		fmin, fcount, err := f.min(filter, bitDepth)
 		if err != nil {
-			return min, count, err
+			return err
 		}
 		// Don't consider a min based on zero columns.
 		if fcount == 0 {
-			continue
+			return nil
 		}
 
 		if !minHasValue {
 			min = fmin
 			minHasValue = true
 			count += fcount
-			continue
+			return nil
 		}
 
 		if fmin < min {
 			min = fmin
 			count += fcount
 		}
-	}
-	return min, count, nil
+		return nil
+	})
+	return min, count, err
 }
 
 // max returns the max and count of a field.
 func (v *view) max(filter *Row, bitDepth uint) (max, count uint64, err error) {
-	for _, f := range v.allFragments() {
+	err = v.forEachFragment(func(f *fragment) error {
 		fmax, fcount, err := f.max(filter, bitDepth)
 		if err != nil {
-			return max, count, err
+			return err
 		}
 		if fcount > 0 && fmax > max {
 			max = fmax
 			count += fcount
 		}
-	}
-	return max, count, nil
+		return nil
+	})
+	return max, count, err
 }
 
 // rangeOp returns rows with a field value encoding matching the predicate.
 func (v *view) rangeOp(op pql.Token, bitDepth uint, predicate uint64) (*Row, error) {
 	r := NewRow()
-	for _, frag := range v.allFragments() {
+	if err := v.forEachFragment(func(frag *fragment) error {
 		other, err := frag.rangeOp(op, bitDepth, predicate)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		r = r.Union(other)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 	return r, nil
 }
@@ -416,6 +974,92 @@ func (v *view) rangeOp(op pql.Token, bitDepth uint, predicate uint64) (*Row, err
 // ViewInfo represents schema information for a view.
 type ViewInfo struct {
 	Name string `json:"name"`
+
+	// The unit and period of a time view. Granularity is "unknown", and the
+	// period unset, for a time view whose name can't be parsed.
+	Granularity string     `json:"granularity,omitempty"`
+	Start       *time.Time `json:"start,omitempty"`
+	End         *time.Time `json:"end,omitempty"`
+
+	// The number of bits set, the number of rows with any bits set in each
+	// fragment, summed, and the largest shard of the view on this node.
+	BitCount uint64 `json:"bitCount"`
+	RowCount uint64 `json:"rowCount"`
+	MaxShard uint64 `json:"maxShard"`
+
+	// The number of fragments of the view on this node, and the size of
+	// their data and cache files.
+	FragmentCount int   `json:"fragmentCount"`
+	DiskBytes     int64 `json:"diskBytes"`
+}
+
+// viewGranularityUnknown is the granularity of time views with invalid names.
+const viewGranularityUnknown = "unknown"
+
+// info returns the schema information of v. Every view of a time field other
+// than the standard view is a time view. Fragments closed while idle are
+// counted as they were when closed, without reopening them.
+func (v *view) info(timeField bool) *ViewInfo {
+	info := &ViewInfo{Name: v.name}
+
+	v.mu.RLock()
+	frags := make([]*fragment, 0, len(v.fragments))
+	for _, frag := range v.fragments {
+		frags = append(frags, frag)
+	}
+	for shard, cf := range v.closed {
+		info.BitCount += cf.bitCount
+		info.RowCount += cf.rowCount
+		if shard > info.MaxShard {
+			info.MaxShard = shard
+		}
+		info.FragmentCount++
+		info.DiskBytes += fileSize(cf.path) + fileSize(cf.path+cacheExt)
+	}
+	v.mu.RUnlock()
+
+	for _, frag := range frags {
+		info.BitCount += frag.BitCount()
+		info.RowCount += frag.RowCount()
+		if frag.shard > info.MaxShard {
+			info.MaxShard = frag.shard
+		}
+		info.FragmentCount++
+		info.DiskBytes += fileSize(frag.path) + fileSize(frag.cachePath())
+	}
+
+	if timeField && v.name != viewStandard {
+		unit, start, err := viewTimeUnit(v.name)
+		if err != nil || !strings.HasPrefix(v.name, viewStandard+"_") {
+			info.Granularity = viewGranularityUnknown
+			return info
+		}
+		end := unit.next(start)
+		info.Granularity, info.Start, info.End = unit.name, &start, &end
+	}
+	return info
+}
+
+// fileSize returns the size of the file at path, or zero if it can't be
+// read, such as a cache which hasn't been flushed yet.
+func fileSize(path string) int64 {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+// overlaps returns true if the period of a time view overlaps [from, to).
+// Zero bounds are unbounded. Time views with an unknown period always
+// overlap, since they can't be ruled out.
+func (info *ViewInfo) overlaps(from, to time.Time) bool {
+	if info.Granularity == viewGranularityUnknown {
+		return true
+	} else if info.Start == nil {
+		return false
+	}
+	return (to.IsZero() || info.Start.Before(to)) && (from.IsZero() || info.End.After(from))
 }
 
 type viewInfoSlice []*ViewInfo
@@ -15,10 +15,18 @@
 package pilosa
 
 import (
+	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/pilosa/pilosa/stats"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -57,6 +65,10 @@ func TestView_DeleteFragment(t *testing.T) {
 		t.Fatal(err)
 	} else if fragment == nil {
 		t.Fatal("expected fragment")
+	} else if _, err := fragment.setBit(1, shard*ShardWidth+1); err != nil {
+		t.Fatal(err)
+	} else if err := fragment.FlushCache(); err != nil {
+		t.Fatal(err)
 	}
 
 	err = v.deleteFragment(shard)
@@ -66,6 +78,12 @@ func TestView_DeleteFragment(t *testing.T) {
 
 	if v.Fragment(shard) != nil {
 		t.Fatal("fragment still exists in view")
+	} else if _, err := os.Stat(fragment.path); !os.IsNotExist(err) {
+		t.Fatalf("expected fragment file to be deleted: %v", err)
+	} else if _, err := os.Stat(fragment.cachePath()); !os.IsNotExist(err) {
+		t.Fatalf("expected cache file to be deleted: %v", err)
+	} else if err := v.deleteFragment(shard); err != ErrFragmentNotFound {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
 	// Recreate fragment with same shard, verify that the old fragment was not reused.
@@ -74,6 +92,27 @@ func TestView_DeleteFragment(t *testing.T) {
 		t.Fatal(err)
 	} else if fragment == fragment2 {
 		t.Fatal("failed to create new fragment")
+	} else if n := fragment2.row(1).Count(); n != 0 {
+		t.Fatalf("expected new fragment to be empty, row has %d columns", n)
+	}
+}
+
+// Ensure fragments are created with the view's op log threshold.
+func TestView_MaxOpN(t *testing.T) {
+	v := mustOpenView("i", "f", "v")
+	defer v.close()
+
+	if f, err := v.CreateFragmentIfNotExists(0); err != nil {
+		t.Fatal(err)
+	} else if f.MaxOpN != defaultFragmentMaxOpN {
+		t.Fatalf("unexpected default max op n: %d", f.MaxOpN)
+	}
+
+	v.maxOpN = 3
+	if f, err := v.CreateFragmentIfNotExists(1); err != nil {
+		t.Fatal(err)
+	} else if f.MaxOpN != 3 {
+		t.Fatalf("unexpected max op n: %d", f.MaxOpN)
 	}
 }
 
@@ -115,3 +154,465 @@ func (d delayBroadcaster) SendSync(Message) error {
 	time.Sleep(d.delay)
 	return nil
 }
+
+// Ensure a row can be cleared in every fragment of a view while other shards
+// are written.
+func TestView_ClearRow(t *testing.T) {
+	v := mustOpenView("i", "f", "v")
+	defer v.close()
+
+	// Clearing a view without fragments changes nothing.
+	if changed, err := v.clearRow(1); err != nil {
+		t.Fatal(err)
+	} else if changed {
+		t.Fatal("expected no change")
+	}
+
+	for _, col := range []uint64{1, ShardWidth + 2, 3*ShardWidth + 3} {
+		if _, err := v.setBit(1, col); err != nil {
+			t.Fatal(err)
+		} else if _, err := v.setBit(2, col); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		for col := uint64(0); col < 100; col++ {
+			if _, err := v.setBit(2, 5*ShardWidth+col); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if changed, err := v.clearRow(1); err != nil {
+		t.Fatal(err)
+	} else if !changed {
+		t.Fatal("expected change")
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := v.row(1).Count(); n != 0 {
+		t.Fatalf("unexpected count of cleared row: %d", n)
+	} else if n := v.row(2).Count(); n != 103 {
+		t.Fatalf("unexpected count of other row: %d", n)
+	} else if n := v.Fragment(1).cache.Get(1); n != 0 {
+		t.Fatalf("unexpected cached count of cleared row: %d", n)
+	}
+
+	if changed, err := v.clearRow(1); err != nil {
+		t.Fatal(err)
+	} else if changed {
+		t.Fatal("expected no change")
+	}
+}
+
+// Ensure view info reports the period of time views, and time views whose
+// names can't be parsed as having an unknown period.
+func TestView_Info(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		timeField   bool
+		granularity string
+		start       time.Time
+	}{
+		{name: viewStandard, timeField: true},
+		{name: "bsig_f", timeField: false},
+		{name: "standard_2018Q2", timeField: true, granularity: "quarter", start: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "standard_2018010203", timeField: true, granularity: "hour", start: time.Date(2018, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{name: "standard_201813", timeField: true, granularity: viewGranularityUnknown},
+		{name: "other_2018", timeField: true, granularity: viewGranularityUnknown},
+	} {
+		v := mustOpenView("i", "f", tt.name)
+		if _, err := v.setBit(1, ShardWidth+1); err != nil {
+			t.Fatal(err)
+		}
+
+		info := v.info(tt.timeField)
+		if info.Name != tt.name || info.Granularity != tt.granularity || info.BitCount != 1 || info.RowCount != 1 || info.MaxShard != 1 {
+			t.Fatalf("%s: unexpected info: %+v", tt.name, info)
+		} else if tt.start.IsZero() && (info.Start != nil || info.End != nil) {
+			t.Fatalf("%s: unexpected period: %v - %v", tt.name, info.Start, info.End)
+		} else if !tt.start.IsZero() && (!info.Start.Equal(tt.start) || !info.End.After(tt.start)) {
+			t.Fatalf("%s: unexpected period: %v - %v", tt.name, info.Start, info.End)
+		}
+
+		// Views with unknown periods are never filtered out.
+		if overlaps := info.overlaps(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}); overlaps != (tt.granularity == viewGranularityUnknown) {
+			t.Fatalf("%s: unexpected overlap: %v", tt.name, overlaps)
+		}
+		v.close()
+	}
+}
+
+// Ensure view info reports the fragments of the view and the size of their
+// files, counting files missing on disk as empty.
+func TestView_InfoDiskUsage(t *testing.T) {
+	v := mustOpenView("i", "f", "v")
+	defer v.close()
+
+	for _, col := range []uint64{1, 2*ShardWidth + 1} {
+		if _, err := v.setBit(1, col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info := v.info(false)
+	if info.FragmentCount != 2 || info.MaxShard != 2 {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	frag := v.Fragment(2)
+	size := fileSize(frag.path) + fileSize(frag.cachePath())
+	if size == 0 || info.DiskBytes < size {
+		t.Fatalf("unexpected disk bytes: %d, fragment has %d", info.DiskBytes, size)
+	}
+
+	if err := os.Remove(frag.path); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(frag.cachePath())
+	if after := v.info(false); after.FragmentCount != 2 || after.DiskBytes != info.DiskBytes-size {
+		t.Fatalf("unexpected info after removing fragment file: %+v", after)
+	}
+}
+
+// mustCreateViewFragments returns the path of a closed view holding n
+// fragments, each with one bit in row 1.
+func mustCreateViewFragments(tb testing.TB, n int) string {
+	v := mustOpenView("i", "f", viewStandard)
+	for shard := uint64(0); shard < uint64(n); shard++ {
+		if frag, err := v.CreateFragmentIfNotExists(shard); err != nil {
+			tb.Fatal(err)
+		} else if _, err := frag.setBit(1, shard*ShardWidth+shard); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := v.close(); err != nil {
+		tb.Fatal(err)
+	}
+	return v.path
+}
+
+// openFiles returns the files under path which the process has open or
+// mapped. It skips the test where they can't be listed.
+func openFiles(tb testing.TB, path string) []string {
+	var files []string
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		tb.Skipf("reading open files: %v", err)
+	}
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && strings.HasPrefix(target, path) {
+			files = append(files, target)
+		}
+	}
+	maps, err := ioutil.ReadFile("/proc/self/maps")
+	if err != nil {
+		tb.Skipf("reading mapped files: %v", err)
+	}
+	for _, line := range strings.Split(string(maps), "\n") {
+		if i := strings.Index(line, path); i >= 0 {
+			files = append(files, line[i:])
+		}
+	}
+	return files
+}
+
+// Ensure a view opens its fragments with several workers.
+func TestView_OpenFragments(t *testing.T) {
+	path := mustCreateViewFragments(t, 50)
+	defer os.RemoveAll(path)
+
+	v := newView(path, "i", "f", viewStandard, FieldOptions{CacheType: DefaultCacheType, CacheSize: DefaultCacheSize})
+	v.fragmentOpenConcurrency = 4
+	if err := v.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer v.close()
+
+	if n := len(v.allFragments()); n != 50 {
+		t.Fatalf("unexpected number of fragments: %d", n)
+	}
+	for shard := uint64(0); shard < 50; shard++ {
+		if cols := v.Fragment(shard).row(1).Columns(); len(cols) != 1 || cols[0] != shard*ShardWidth+shard {
+			t.Fatalf("unexpected columns of shard %d: %v", shard, cols)
+		}
+	}
+}
+
+// Ensure a view fails to open if one of its fragments does, closing the
+// fragments already opened.
+func TestView_OpenFragmentsError(t *testing.T) {
+	path := mustCreateViewFragments(t, 50)
+	defer os.RemoveAll(path)
+	if err := ioutil.WriteFile(mustFragmentPath(t, path, 7), []byte("not a fragment"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	v := newView(path, "i", "f", viewStandard, FieldOptions{CacheType: DefaultCacheType, CacheSize: DefaultCacheSize})
+	v.fragmentOpenConcurrency = 4
+	if err := v.open(); err == nil || !strings.Contains(err.Error(), "shard=7") {
+		t.Fatalf("expected error opening shard 7, got %v", err)
+	} else if n := len(v.allFragments()); n != 0 {
+		t.Fatalf("expected no fragments, got %d", n)
+	} else if files := openFiles(t, path); len(files) > 0 {
+		t.Fatalf("expected fragments to be closed, but files are open: %v", files)
+	}
+}
+
+// mustFragmentPath returns the path of the data file of a shard in the view
+// at path.
+func mustFragmentPath(tb testing.TB, path string, shard uint64) string {
+	files, err := fragmentFiles(path)
+	if err != nil {
+		tb.Fatal(err)
+	} else if _, ok := files[shard]; !ok {
+		tb.Fatalf("no fragment file for shard %d", shard)
+	}
+	return files[shard]
+}
+
+// Ensure idle fragments are closed, still reported, and reopened once when
+// accessed by several callers at once.
+func TestView_CloseIdleFragments(t *testing.T) {
+	v := mustOpenView("i", "f", "v")
+	defer v.close()
+	st := &eventStatsClient{StatsClient: stats.NopStatsClient, events: make(map[string]int)}
+	v.stats = st
+
+	// Shard 0 is the least recently accessed.
+	for shard := uint64(0); shard < 3; shard++ {
+		if _, err := v.setBit(shard+1, shard*ShardWidth); err != nil {
+			t.Fatal(err)
+		}
+		atomic.StoreInt64(&v.Fragment(shard).accessed, time.Now().Add(-time.Duration(3-shard)*time.Second).UnixNano())
+	}
+
+	if n := v.closeIdleFragments(0, 1); n != 2 {
+		t.Fatalf("unexpected closed: %d", n)
+	} else if frags := v.loadedFragments(); len(frags) != 1 || frags[0].shard != 2 {
+		t.Fatalf("unexpected open fragments: %v", frags)
+	} else if got := v.availableShards().Slice(); !reflect.DeepEqual(got, []uint64{0, 1, 2}) {
+		t.Fatalf("unexpected shards: %v", got)
+	} else if info := v.info(false); info.FragmentCount != 3 || info.BitCount != 3 || info.RowCount != 3 || info.MaxShard != 2 {
+		t.Fatalf("unexpected info: %+v", info)
+	} else if v.BitCount() != 3 || v.RowCount() != 3 {
+		t.Fatalf("unexpected counts: %d bits, %d rows", v.BitCount(), v.RowCount())
+	} else if id := v.maxRowID(); id != 3 {
+		t.Fatalf("unexpected max row ID: %d", id)
+	} else if st.events["fragment.evict{reason:max}"] != 2 {
+		t.Fatalf("unexpected stats: %v", st.events)
+	}
+	for i, fs := range v.fragmentStats() {
+		if fs.Shard != uint64(i) || fs.Open != (i == 2) || fs.BitCount != 1 || fs.RowCount != 1 || fs.FileBytes == 0 {
+			t.Fatalf("unexpected stats of shard %d: %+v", i, fs)
+		}
+	}
+
+	// Callers racing to a closed fragment reopen it once.
+	var eg errgroup.Group
+	frags := make([]*fragment, 8)
+	for i := range frags {
+		i := i
+		eg.Go(func() error { frags[i] = v.Fragment(0); return nil })
+	}
+	eg.Wait()
+	for _, frag := range frags {
+		if frag == nil || frag != frags[0] {
+			t.Fatalf("unexpected fragments: %v", frags)
+		}
+	}
+	if n := frags[0].row(1).Count(); n != 1 {
+		t.Fatalf("unexpected count: %d", n)
+	} else if st.events["fragment.reopen.duration"] != 1 {
+		t.Fatalf("unexpected stats: %v", st.events)
+	}
+
+	// Writes reopen closed fragments.
+	if changed, err := v.setBit(5, ShardWidth+1); err != nil || !changed {
+		t.Fatalf("unexpected set: changed=%v, err=%v", changed, err)
+	} else if got := v.Fragment(1).row(5).Columns(); !reflect.DeepEqual(got, []uint64{ShardWidth + 1}) {
+		t.Fatalf("unexpected columns: %v", got)
+	}
+
+	// Fragments held by a reader stay open.
+	frag := v.Fragment(0)
+	if err := frag.acquire(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if n := v.closeIdleFragments(time.Nanosecond, 0); n != 2 {
+		t.Fatalf("unexpected closed: %d", n)
+	} else if frags := v.loadedFragments(); len(frags) != 1 || frags[0] != frag {
+		t.Fatalf("unexpected open fragments: %v", frags)
+	} else if st.events["fragment.evict{reason:idle}"] != 2 {
+		t.Fatalf("unexpected stats: %v", st.events)
+	}
+	frag.release()
+
+	// Closed fragments are deleted without being reopened.
+	path := v.fragmentPath(1)
+	if err := v.deleteFragment(1); err != nil {
+		t.Fatal(err)
+	} else if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected fragment file to be deleted: %v", err)
+	} else if v.Fragment(1) != nil {
+		t.Fatal("fragment still exists in view")
+	} else if got := v.availableShards().Slice(); !reflect.DeepEqual(got, []uint64{0, 2}) {
+		t.Fatalf("unexpected shards: %v", got)
+	}
+}
+
+// Ensure fragments acquired for reading are never closed under the read,
+// and are reopened when they were closed while idle.
+func TestView_AcquireFragment(t *testing.T) {
+	v := mustOpenView("i", "f", "v")
+	defer v.close()
+	for shard := uint64(0); shard < 4; shard++ {
+		if _, err := v.setBit(1, shard*ShardWidth); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan struct{})
+	var eg errgroup.Group
+	eg.Go(func() error {
+		for {
+			select {
+			case <-done:
+				return nil
+			default:
+				v.closeIdleFragments(time.Nanosecond, 0)
+			}
+		}
+	})
+	for i := 0; i < 4; i++ {
+		shard := uint64(i)
+		eg.Go(func() error {
+			for j := 0; j < 200; j++ {
+				frag, err := v.acquireFragment(shard)
+				if err != nil {
+					return err
+				} else if frag == nil {
+					return fmt.Errorf("fragment of shard %d not found", shard)
+				}
+				n := frag.row(1).Count()
+				frag.release()
+				if n != 1 {
+					return fmt.Errorf("unexpected count of shard %d: %d", shard, n)
+				}
+			}
+			return nil
+		})
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(done)
+	if err := eg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fragments can't be acquired once the view is closed.
+	frag := v.Fragment(0)
+	if err := v.close(); err != nil {
+		t.Fatal(err)
+	} else if err := frag.acquire(); err != ErrFragmentClosing {
+		t.Fatalf("unexpected error: %v", err)
+	} else if frag, err := v.acquireFragment(0); frag != nil || err != nil {
+		t.Fatalf("unexpected fragment: %v, %v", frag, err)
+	}
+}
+
+// Ensure the fragments of a view wait for their readers until one shared
+// deadline when the view is closed, and that the view isn't locked while they
+// wait.
+func TestView_Close_SharedReaderDeadline(t *testing.T) {
+	v := mustOpenView("i", "f", viewStandard)
+	defer os.RemoveAll(v.path)
+	v.fragmentCloseTimeout = 100 * time.Millisecond
+
+	const shardN = 5
+	var frags []*fragment
+	for shard := uint64(0); shard < shardN; shard++ {
+		frag, err := v.CreateFragmentIfNotExists(shard)
+		if err != nil {
+			t.Fatal(err)
+		} else if err := frag.acquire(); err != nil {
+			t.Fatal(err)
+		}
+		frags = append(frags, frag)
+	}
+	defer func() {
+		for _, frag := range frags {
+			frag.release()
+		}
+	}()
+
+	start := time.Now()
+	closed := make(chan error)
+	go func() { closed <- v.close() }()
+
+	// Lookups return while the fragments wait for their readers.
+	time.Sleep(20 * time.Millisecond)
+	looked := make(chan *fragment)
+	go func() { looked <- v.Fragment(0) }()
+	select {
+	case frag := <-looked:
+		if frag != nil {
+			t.Fatal("expected no fragment in a closing view")
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("fragment lookup blocked by close")
+	}
+
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	} else if d := time.Since(start); d >= shardN*v.fragmentCloseTimeout/2 {
+		t.Fatalf("close waited %s for %d fragments", d, shardN)
+	}
+}
+
+// eventStatsClient counts the stats reported of each name.
+type eventStatsClient struct {
+	stats.StatsClient
+	mu     sync.Mutex
+	events map[string]int
+}
+
+func (c *eventStatsClient) record(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events[name]++
+}
+
+func (c *eventStatsClient) Count(name string, value int64, rate float64) { c.record(name) }
+
+func (c *eventStatsClient) CountWithCustomTags(name string, value int64, rate float64, tags []string) {
+	c.record(name + "{" + strings.Join(tags, ",") + "}")
+}
+
+func (c *eventStatsClient) Timing(name string, value time.Duration, rate float64) { c.record(name) }
+
+// BenchmarkView_OpenFragments measures opening a view of 1000 small fragments
+// with one worker and with several.
+func BenchmarkView_OpenFragments(b *testing.B) {
+	path := mustCreateViewFragments(b, 1000)
+	defer os.RemoveAll(path)
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				v := newView(path, "i", "f", viewStandard, FieldOptions{CacheType: DefaultCacheType, CacheSize: DefaultCacheSize})
+				v.fragmentOpenConcurrency = concurrency
+				if err := v.open(); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				if err := v.close(); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}